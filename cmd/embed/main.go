@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/joho/godotenv"
+
+	"maukemana-backend/internal/config"
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/embedding"
+	"maukemana-backend/internal/repositories"
+)
+
+// Backfill of semantic search embeddings for approved POIs. Only POIs whose
+// text or embedding model changed are sent to the provider, so reruns are cheap.
+// Incremental updates are applied by the server from the event outbox.
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		log.Fatal("DATABASE_URL environment variable is required")
+	}
+
+	embedder, err := embedding.NewFromConfig(config.GetEmbeddingSettings())
+	if err != nil {
+		log.Fatalf("Invalid embedding configuration: %v", err)
+	}
+	if embedder == nil {
+		log.Fatal("EMBEDDING_PROVIDER is not set; nothing to embed")
+	}
+
+	db, err := database.New(databaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	syncer := embedding.NewSyncer(embedder, repositories.NewPOIRepository(db), repositories.NewEmbeddingRepository(db))
+	total, err := syncer.Backfill(ctx)
+	if err != nil {
+		log.Fatalf("Embedding backfill failed after %d POIs: %v", total, err)
+	}
+
+	log.Printf("✓ Embedded %d POIs", total)
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 	"github.com/pressly/goose/v3"
+
+	"maukemana-backend/migrations"
 )
 
 func main() {
@@ -22,10 +24,12 @@ func main() {
 		log.Fatal("DATABASE_URL environment variable is required")
 	}
 
-	// Parse command line arguments
+	// Parse command line arguments, e.g. "up-to 20260305090000"
 	command := "up"
+	var args []string
 	if len(os.Args) > 1 {
 		command = os.Args[1]
+		args = os.Args[2:]
 	}
 
 	fmt.Printf("Running goose %s...\n", command)
@@ -43,11 +47,17 @@ func main() {
 	}
 	fmt.Println("✓ Connected to PostgreSQL")
 
-	// Set migration directory
-	migrationsDir := "migrations"
+	// Run against the migrations embedded in the binary; new files can only be
+	// created in the directory on disk
+	migrationsDir := "."
+	goose.SetBaseFS(migrations.FS)
+	if command == "create" || command == "fix" {
+		goose.SetBaseFS(nil)
+		migrationsDir = "migrations"
+	}
 
 	// Run goose command
-	if err := goose.Run(command, db, migrationsDir); err != nil {
+	if err := goose.Run(command, db, migrationsDir, args...); err != nil {
 		log.Fatalf("Goose %s failed: %v", command, err)
 	}
 
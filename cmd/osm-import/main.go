@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/joho/godotenv"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/osm"
+	"maukemana-backend/internal/repositories"
+)
+
+// Seeds cafes and coworking spaces from OpenStreetMap. Places are inserted as
+// unverified drafts with OSM attribution for moderators to complete and
+// approve; reruns skip records already imported and places that already exist.
+func main() {
+	bboxFlag := flag.String("bbox", "", "bounding box to import: south,west,north,east")
+	overpassURL := flag.String("overpass", osm.DefaultOverpassURL, "Overpass API endpoint")
+	radius := flag.Float64("dedupe-radius", 75, "meters within which a similarly named POI counts as a duplicate")
+	dryRun := flag.Bool("dry-run", false, "fetch and map places without writing to the database")
+	flag.Parse()
+
+	bbox, err := osm.ParseBBox(*bboxFlag)
+	if err != nil {
+		log.Fatalf("Invalid -bbox: %v", err)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	elements, err := osm.NewClient(*overpassURL).FetchPlaces(ctx, bbox)
+	if err != nil {
+		log.Fatalf("Failed to fetch places from Overpass: %v", err)
+	}
+
+	var places []repositories.ImportedPOI
+	for _, e := range elements {
+		if poi, ok := osm.ToImportedPOI(e); ok {
+			places = append(places, poi)
+		}
+	}
+	log.Printf("Fetched %d OSM elements, %d named places", len(elements), len(places))
+
+	if *dryRun {
+		for _, p := range places {
+			log.Printf("  %s %q (%s) %.6f,%.6f", p.SourceID, p.Name, p.CategoryKey, p.Latitude, p.Longitude)
+		}
+		return
+	}
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		log.Fatal("DATABASE_URL environment variable is required")
+	}
+	db, err := database.New(databaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	poiRepo := repositories.NewPOIRepository(db)
+	counts := map[string]int{}
+	for _, p := range places {
+		outcome, poiID, err := poiRepo.ImportPOI(ctx, p, *radius)
+		if err != nil {
+			log.Fatalf("Import stopped at %s %q: %v", p.SourceID, p.Name, err)
+		}
+		counts[outcome]++
+		if outcome == repositories.ImportDuplicate {
+			log.Printf("  skipped %s %q: duplicate of %s", p.SourceID, p.Name, poiID)
+		}
+	}
+
+	log.Printf("✓ Imported %d new drafts (%d already imported, %d duplicates)",
+		counts[repositories.ImportCreated], counts[repositories.ImportExists], counts[repositories.ImportDuplicate])
+}
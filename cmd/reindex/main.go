@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/joho/godotenv"
+
+	"maukemana-backend/internal/config"
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/search"
+)
+
+// Full reindex of approved POIs into the configured search index.
+// Incremental updates are applied by the server from the event outbox.
+func main() {
+	clear := flag.Bool("clear", false, "remove every document before reindexing")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		log.Fatal("DATABASE_URL environment variable is required")
+	}
+
+	index, err := search.NewFromConfig(config.GetSearchSettings())
+	if err != nil {
+		log.Fatalf("Invalid search configuration: %v", err)
+	}
+	if index == nil {
+		log.Fatal("SEARCH_PROVIDER is not set; nothing to reindex")
+	}
+
+	db, err := database.New(databaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	total, err := search.Reindex(ctx, index, repositories.NewPOIRepository(db), *clear)
+	if err != nil {
+		log.Fatalf("Reindex failed after %d documents: %v", total, err)
+	}
+
+	log.Printf("✓ Reindexed %d POIs", total)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"maukemana-backend/internal/config"
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/imaging"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/storage"
+)
+
+// Regenerates the derivatives of every ready image asset with the current
+// rendition ladder, or with -outdated only of those rendered with an older
+// version of their ladder. Progress is stored in image_reprocess_runs, so an
+// interrupted run resumes where it stopped when the command is run again.
+func main() {
+	category := flag.String("category", "", "only reprocess assets of this category")
+	outdated := flag.Bool("outdated", false, "only reprocess assets rendered with an older ladder version")
+	batchSize := flag.Int("batch", 50, "assets queued per batch")
+	interval := flag.Duration("interval", 5*time.Second, "pause between batches")
+	workers := flag.Int("workers", config.GetImagingSettings().Workers, "processing workers")
+	restart := flag.Bool("restart", false, "cancel the running run and start a new one")
+	flag.Parse()
+
+	if *batchSize < 1 || *interval < 0 || *interval > time.Minute {
+		log.Fatal("-batch must be positive and -interval between 0 and 1m")
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		log.Fatal("DATABASE_URL environment variable is required")
+	}
+	db, err := database.New(databaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	stores, err := storage.NewRegistry(config.GetStorageSettings())
+	if err != nil {
+		log.Fatalf("Object storage not configured: %v", err)
+	}
+	images := stores.For(storage.CategoryImages)
+
+	repo := repositories.NewImagingRepository(db)
+	var opts []imaging.ServiceOption
+	if moderator, policy, err := imaging.NewModeratorFromEnv(); err != nil {
+		log.Printf("Warning: image moderation not configured: %v", err)
+	} else if moderator != nil {
+		opts = append(opts, imaging.WithModerator(moderator, policy))
+	}
+	ladders := imaging.NewLadders(repo)
+	if err := ladders.Load(ctx); err != nil {
+		log.Fatalf("Failed to load rendition ladders: %v", err)
+	}
+	opts = append(opts, imaging.WithLadders(ladders, 0))
+	svc := imaging.NewService(images, repo, min(max(*workers, 1), imaging.MaxWorkers), opts...)
+
+	run, err := repo.GetActiveReprocessRun(ctx)
+	if err != nil {
+		log.Fatalf("Failed to load reprocess run: %v", err)
+	}
+	if run != nil && *restart {
+		if err := repo.FinishReprocessRun(ctx, run.RunID, imaging.ReprocessCancelled, ""); err != nil {
+			log.Fatalf("Failed to cancel reprocess run: %v", err)
+		}
+		log.Printf("Cancelled reprocess run %s", run.RunID)
+		run = nil
+	}
+	if run != nil {
+		log.Printf("Resuming reprocess run %s (%d/%d queued)", run.RunID, run.Queued, run.Total)
+	} else {
+		var cat *string
+		if *category != "" {
+			cat = category
+		}
+		run, err = repo.StartReprocessRun(ctx, cat, *outdated, *batchSize, int(interval.Milliseconds()), nil)
+		if err != nil {
+			log.Fatalf("Failed to start reprocess run: %v", err)
+		}
+		log.Printf("Started reprocess run %s over %d ready assets", run.RunID, run.Total)
+	}
+
+	runErr := imaging.NewReprocessor(repo, svc).Run(ctx, run)
+	switch {
+	case errors.Is(runErr, imaging.ErrReprocessLeased):
+		log.Print("The run is being driven by another process (e.g. the API); try again once its lease expires")
+	case runErr != nil && !errors.Is(runErr, context.Canceled):
+		log.Printf("Reprocess run stopped: %v", runErr)
+	}
+
+	// Let queued jobs finish; on interrupt they stay pending for the next start
+	if runErr == nil {
+		log.Print("All assets queued, waiting for processing to finish...")
+		if err := svc.WaitIdle(ctx); err != nil {
+			log.Printf("Stopped before processing finished: %v", err)
+		}
+	}
+	drainCtx, cancel := context.WithTimeout(context.Background(), config.GetImagingSettings().DrainTimeout)
+	defer cancel()
+	if err := svc.Stop(drainCtx); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	if runErr != nil {
+		os.Exit(1)
+	}
+	log.Printf("✓ Reprocess run %s finished", run.RunID)
+}
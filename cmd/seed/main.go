@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/joho/godotenv"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/seed"
+)
+
+// Populates a local database with categories, vocabularies, a few users and
+// approved POIs around Jakarta with placeholder photos and reviews. The same
+// -seed always generates the same POIs, so reruns only add the missing ones.
+func main() {
+	count := flag.Int("count", 200, "number of POIs to seed")
+	randSeed := flag.Int64("seed", 42, "random seed for generated POIs")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		log.Fatal("DATABASE_URL environment variable is required")
+	}
+	db, err := database.New(databaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	summary, err := seed.New(db).Run(ctx, seed.Options{Count: *count, Seed: *randSeed})
+	if err != nil {
+		log.Fatalf("Seeding failed: %v", err)
+	}
+
+	log.Printf("✓ Seeded %d users, %d new POIs (%d already seeded), %d photos, %d reviews",
+		summary.Users, summary.POIsCreated, summary.POIsExisting, summary.Photos, summary.Reviews)
+}
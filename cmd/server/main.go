@@ -12,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 
+	"maukemana-backend/internal/config"
 	"maukemana-backend/internal/database"
 	"maukemana-backend/internal/logger"
 	"maukemana-backend/internal/observability"
@@ -48,22 +49,51 @@ func main() {
 		log.Println("✓ OpenTelemetry initialized")
 	}
 
+	shutdownMetrics, err := observability.InitMetrics(context.Background(), "maukemana-api")
+	if err != nil {
+		log.Printf("Warning: Failed to initialize metrics: %v", err)
+	} else {
+		defer func() {
+			if err := shutdownMetrics(context.Background()); err != nil {
+				log.Printf("Error shutting down metrics: %v", err)
+			}
+		}()
+	}
+
 	// Set Gin mode
 	if env == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	// Initialize database
-	db, err := database.New(databaseURL)
+	db, err := database.New(databaseURL, database.WithPool(config.GetDatabasePoolSettings()))
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 	defer db.Close()
+	db.LogQueries(config.GetQueryLogSettings().SlowThreshold)
 
 	log.Println("✓ Connected to PostgreSQL")
 
+	// Heavy reads go to the replica when one is configured
+	if replicaURL := getEnv("DATABASE_REPLICA_URL", ""); replicaURL != "" {
+		if err := db.ConnectReplica(replicaURL, config.GetBreakerSettings()); err != nil {
+			log.Printf("Warning: read replica not configured, reading from primary: %v", err)
+		} else {
+			log.Println("✓ Connected to read replica")
+		}
+	}
+
+	if config.GetMigrationSettings().AutoMigrate {
+		results, err := db.Migrate(context.Background())
+		if err != nil {
+			log.Fatal("Failed to apply migrations:", err)
+		}
+		log.Printf("✓ Applied %d pending migrations", len(results))
+	}
+
 	// Setup router with all handlers
-	r := router.Setup(db)
+	r, stopWorkers := router.Setup(db)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -96,6 +126,13 @@ func main() {
 		log.Fatal("Server forced to shutdown:", err)
 	}
 
+	// Let image processing jobs finish; unfinished ones resume on the next start
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), config.GetImagingSettings().DrainTimeout)
+	defer cancelDrain()
+	if err := stopWorkers(drainCtx); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
 	log.Println("✅ Server exited")
 }
 
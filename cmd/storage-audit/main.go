@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"maukemana-backend/internal/config"
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/imaging"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/storage"
+)
+
+// Compares image_assets and image_derivatives with the objects stored in R2
+// and writes a JSON report of missing objects and orphan keys. With -requeue,
+// ready assets missing derivatives are reprocessed from their original; with
+// -delete-orphans, objects no asset refers to are deleted.
+func main() {
+	out := flag.String("out", "", "write the JSON report to this file instead of stdout")
+	requeue := flag.Bool("requeue", false, "reprocess assets with missing derivatives")
+	deleteOrphans := flag.Bool("delete-orphans", false, "delete objects no asset or derivative refers to")
+	minAge := flag.Duration("min-age", 24*time.Hour, "only treat objects older than this as orphans")
+	workers := flag.Int("workers", config.GetImagingSettings().Workers, "processing workers for -requeue")
+	flag.Parse()
+
+	if *minAge < 0 {
+		log.Fatal("-min-age must not be negative")
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		log.Fatal("DATABASE_URL environment variable is required")
+	}
+	db, err := database.New(databaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	stores, err := storage.NewRegistry(config.GetStorageSettings())
+	if err != nil {
+		log.Fatalf("Object storage not configured: %v", err)
+	}
+	images := stores.For(storage.CategoryImages)
+
+	repo := repositories.NewImagingRepository(db)
+	opts := imaging.AuditOptions{DeleteOrphans: *deleteOrphans, OrphanMinAge: *minAge}
+	if *requeue {
+		ladders := imaging.NewLadders(repo)
+		if err := ladders.Load(ctx); err != nil {
+			log.Fatalf("Failed to load rendition ladders: %v", err)
+		}
+		opts.Requeue = imaging.NewService(images, repo, min(max(*workers, 1), imaging.MaxWorkers), imaging.WithLadders(ladders, 0))
+	}
+
+	report, auditErr := imaging.Audit(ctx, repo, images, opts)
+
+	// Let requeued jobs finish; on interrupt they stay pending for the server to resume
+	if opts.Requeue != nil {
+		if auditErr == nil && len(report.Requeued) > 0 {
+			log.Printf("Requeued %d assets, waiting for processing to finish...", len(report.Requeued))
+			if err := opts.Requeue.WaitIdle(ctx); err != nil {
+				log.Printf("Stopped before processing finished: %v", err)
+			}
+		}
+		drainCtx, cancel := context.WithTimeout(context.Background(), config.GetImagingSettings().DrainTimeout)
+		defer cancel()
+		if err := opts.Requeue.Stop(drainCtx); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	if auditErr != nil {
+		log.Fatalf("Storage audit failed: %v", auditErr)
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("Failed to create report: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+
+	log.Printf("✓ Scanned %d objects and %d assets: %d missing, %d orphans, %d requeued, %d deleted, %d errors",
+		report.ObjectsScanned, report.AssetsChecked, len(report.Missing), len(report.Orphans),
+		len(report.Requeued), len(report.Deleted), len(report.Errors))
+}
@@ -0,0 +1,215 @@
+// Package alerts notifies users when newly approved POIs match their saved searches.
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// searchBatchSize is how many saved searches are evaluated per query
+	searchBatchSize = 200
+	// maxLookback bounds how far back approvals are considered, e.g. after downtime
+	maxLookback = 7 * 24 * time.Hour
+	// commitGrace leaves recent approvals for the next run, so ones whose
+	// transaction commits after the scan are not skipped
+	commitGrace = time.Minute
+	// namesInBody is how many matching POI names a notification lists
+	namesInBody = 3
+)
+
+// Store is the saved search persistence the worker needs
+type Store interface {
+	ListAlertSearches(ctx context.Context, before time.Time, limit int) ([]models.SavedSearch, error)
+	MarkChecked(ctx context.Context, searchID uuid.UUID, until time.Time, alerted bool) error
+}
+
+// POISource finds newly approved POIs and matches them against search filters
+type POISource interface {
+	ListApprovedBetween(ctx context.Context, from, until time.Time) ([]repositories.ApprovedPOI, error)
+	Search(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]repositories.POI, error)
+}
+
+// Notifier creates in-app notifications
+type Notifier interface {
+	Create(ctx context.Context, userID uuid.UUID, notificationType, title string, body *string, data interface{}) error
+}
+
+// Worker periodically evaluates POIs approved since each saved search was
+// last checked and notifies its owner about the ones matching its filters
+type Worker struct {
+	store    Store
+	pois     POISource
+	notifier Notifier
+	interval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWorker creates a worker evaluating saved searches every interval
+func NewWorker(store Store, pois POISource, notifier Notifier, interval time.Duration) *Worker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Worker{store: store, pois: pois, notifier: notifier, interval: interval, ctx: ctx, cancel: cancel}
+}
+
+// Start begins evaluating in the background
+func (w *Worker) Start() {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-ticker.C:
+				w.run()
+			}
+		}
+	}()
+}
+
+// Stop waits for the current run to finish and stops the worker
+func (w *Worker) Stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+// run evaluates every saved search with alerts enabled, a batch at a time
+func (w *Worker) run() {
+	until := time.Now().Add(-commitGrace)
+	for w.ctx.Err() == nil {
+		searches, err := w.store.ListAlertSearches(w.ctx, until, searchBatchSize)
+		if err != nil {
+			slog.Error("list alert searches failed", "error", err)
+			return
+		}
+		if len(searches) == 0 {
+			return
+		}
+
+		// Searches are ordered by checked_until, so the first one reaches back furthest
+		from := later(searches[0].CheckedUntil, until.Add(-maxLookback))
+		approved, err := w.pois.ListApprovedBetween(w.ctx, from, until)
+		if err != nil {
+			slog.Error("list approved pois failed", "error", err)
+			return
+		}
+
+		failed := false
+		for _, s := range searches {
+			alerted, err := w.evaluate(s, approved, until)
+			if err != nil {
+				// Left unchecked, the search is evaluated again next run
+				slog.Error("evaluate saved search failed", "search_id", s.SearchID, "error", err)
+				failed = true
+				continue
+			}
+			if err := w.store.MarkChecked(w.ctx, s.SearchID, until, alerted); err != nil {
+				slog.Error("mark saved search checked failed", "search_id", s.SearchID, "error", err)
+				return
+			}
+		}
+		if failed || len(searches) < searchBatchSize {
+			return
+		}
+	}
+}
+
+// evaluate notifies the owner of a saved search about the approved POIs it
+// has not been checked against that match its filters
+func (w *Worker) evaluate(s models.SavedSearch, approved []repositories.ApprovedPOI, until time.Time) (bool, error) {
+	from := later(s.CheckedUntil, until.Add(-maxLookback))
+	var ids []uuid.UUID
+	for _, p := range approved {
+		if p.ApprovedAt.After(from) {
+			ids = append(ids, p.PoiID)
+		}
+	}
+	if len(ids) == 0 {
+		return false, nil
+	}
+
+	filters, err := searchFilters(s.Query)
+	if err != nil {
+		return false, err
+	}
+	filters["match_ids"] = ids
+	filters["fields"] = []string{"name"}
+	matches, err := w.pois.Search(w.ctx, filters, len(ids), 0)
+	if err != nil {
+		return false, err
+	}
+	if len(matches) == 0 {
+		return false, nil
+	}
+
+	title := fmt.Sprintf("%d new places match \"%s\"", len(matches), s.Name)
+	if len(matches) == 1 {
+		title = fmt.Sprintf("A new place matches \"%s\"", s.Name)
+	}
+	names := make([]string, 0, namesInBody)
+	matchIDs := make([]uuid.UUID, len(matches))
+	for i, p := range matches {
+		if i < namesInBody {
+			names = append(names, p.Name)
+		}
+		matchIDs[i] = p.PoiID
+	}
+	body := strings.Join(names, ", ")
+	if len(matches) > namesInBody {
+		body += fmt.Sprintf(" and %d more", len(matches)-namesInBody)
+	}
+
+	err = w.notifier.Create(w.ctx, s.UserID, models.NotificationSavedSearchMatch, title, &body, map[string]interface{}{
+		"saved_search_id": s.SearchID,
+		"poi_ids":         matchIDs,
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// searchFilters turns stored GET /api/v1/pois parameters into approved-only Search filters
+func searchFilters(query json.RawMessage) (map[string]interface{}, error) {
+	var params map[string]string
+	if err := json.Unmarshal(query, &params); err != nil {
+		return nil, fmt.Errorf("decode saved search query: %w", err)
+	}
+	values := url.Values{}
+	for k, v := range params {
+		values.Set(k, v)
+	}
+	filters, err := repositories.ParseSearchFilters(values)
+	if err != nil {
+		return nil, err
+	}
+	filters["status"] = "approved"
+	if q := strings.TrimSpace(values.Get("q")); q != "" {
+		filters["q"] = q
+	}
+	return filters, nil
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,213 @@
+// Package analytics records anonymized, sampled product usage for admin reports.
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"maukemana-backend/internal/config"
+	"maukemana-backend/internal/models"
+)
+
+const (
+	searchQueueSize     = 1024
+	searchBatchSize     = 200
+	searchFlushInterval = 5 * time.Second
+	purgeInterval       = 24 * time.Hour
+	maxQueryLength      = 100 // Runes kept of a free-text query
+)
+
+// ignoredSearchFilters are not choices made by the searcher, or have their own column
+var ignoredSearchFilters = map[string]bool{
+	"status": true, "q": true, "match_ids": true, "fields": true, "locale": true,
+	"sort_by": true, "area": true, "lat": true, "lng": true,
+}
+
+// SearchStore persists search events
+type SearchStore interface {
+	InsertSearchEvents(ctx context.Context, events []models.SearchEvent) error
+	PurgeSearchEvents(ctx context.Context, before time.Time) (int64, error)
+}
+
+// SearchRecorder samples public searches and writes them in batches in the
+// background. Recording never blocks a request: when the queue is full the
+// event is dropped.
+type SearchRecorder struct {
+	store      SearchStore
+	sampleRate float64
+	retention  time.Duration
+
+	queue  chan models.SearchEvent
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewSearchRecorder creates a recorder; it returns nil when sampling is disabled
+func NewSearchRecorder(store SearchStore, cfg config.SearchAnalyticsSettings) *SearchRecorder {
+	if cfg.SampleRate <= 0 {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SearchRecorder{
+		store:      store,
+		sampleRate: cfg.SampleRate,
+		retention:  cfg.Retention,
+		queue:      make(chan models.SearchEvent, searchQueueSize),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// RecordSearch samples a search with its applied filters and result count
+func (r *SearchRecorder) RecordSearch(query string, filters map[string]interface{}, resultCount int) {
+	if rand.Float64() >= r.sampleRate {
+		return
+	}
+	event, err := newSearchEvent(query, filters, resultCount, r.sampleRate)
+	if err != nil {
+		slog.Debug("skipping search event", "error", err)
+		return
+	}
+	select {
+	case r.queue <- event:
+	default:
+		slog.Debug("search event queue full, dropping event")
+	}
+}
+
+// Start begins writing queued events and purging expired ones
+func (r *SearchRecorder) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		flush := time.NewTicker(searchFlushInterval)
+		defer flush.Stop()
+		purge := time.NewTicker(purgeInterval)
+		defer purge.Stop()
+
+		batch := make([]models.SearchEvent, 0, searchBatchSize)
+		for {
+			select {
+			case <-r.ctx.Done():
+				// Drain what is already queued before stopping
+				for len(r.queue) > 0 && len(batch) < cap(batch) {
+					batch = append(batch, <-r.queue)
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				r.write(ctx, batch)
+				cancel()
+				return
+			case event := <-r.queue:
+				batch = append(batch, event)
+				if len(batch) >= searchBatchSize {
+					r.write(r.ctx, batch)
+					batch = batch[:0]
+				}
+			case <-flush.C:
+				r.write(r.ctx, batch)
+				batch = batch[:0]
+			case <-purge.C:
+				r.purge()
+			}
+		}
+	}()
+}
+
+// Stop writes the queued events and stops the recorder
+func (r *SearchRecorder) Stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+func (r *SearchRecorder) write(ctx context.Context, batch []models.SearchEvent) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := r.store.InsertSearchEvents(ctx, batch); err != nil {
+		slog.Error("write search events failed", "count", len(batch), "error", err)
+	}
+}
+
+func (r *SearchRecorder) purge() {
+	if r.retention <= 0 {
+		return
+	}
+	n, err := r.store.PurgeSearchEvents(r.ctx, time.Now().Add(-r.retention))
+	if err != nil {
+		slog.Error("purge search events failed", "error", err)
+	} else if n > 0 {
+		slog.Info("purged expired search events", "count", n)
+	}
+}
+
+// newSearchEvent anonymizes a search: the query is normalized (and dropped if
+// it may hold contact details) and coordinates are rounded to about 1 km
+func newSearchEvent(query string, filters map[string]interface{}, resultCount int, sampleRate float64) (models.SearchEvent, error) {
+	applied := make(map[string]interface{}, len(filters))
+	for k, v := range filters {
+		if !ignoredSearchFilters[k] {
+			applied[k] = v
+		}
+	}
+	encoded, err := json.Marshal(applied)
+	if err != nil {
+		return models.SearchEvent{}, err
+	}
+
+	event := models.SearchEvent{
+		Query:       normalizeQuery(query),
+		Filters:     encoded,
+		ResultCount: resultCount,
+		SampleRate:  sampleRate,
+	}
+	if v, ok := filters["sort_by"].(string); ok {
+		event.SortBy = &v
+	}
+	if v, ok := filters["area"].(string); ok {
+		event.Area = &v
+	}
+	lat, hasLat := filters["lat"].(float64)
+	lng, hasLng := filters["lng"].(float64)
+	if hasLat && hasLng {
+		lat, lng = math.Round(lat*100)/100, math.Round(lng*100)/100
+		event.Lat, event.Lng = &lat, &lng
+	}
+	return event, nil
+}
+
+// normalizeQuery lowercases and collapses whitespace. Queries that look like
+// they contain an email address or phone number are not kept.
+func normalizeQuery(q string) *string {
+	q = strings.ToLower(strings.Join(strings.Fields(q), " "))
+	if q == "" || strings.Contains(q, "@") || longestDigitRun(q) >= 6 {
+		return nil
+	}
+	if runes := []rune(q); len(runes) > maxQueryLength {
+		q = string(runes[:maxQueryLength])
+	}
+	return &q
+}
+
+func longestDigitRun(s string) int {
+	longest, run := 0, 0
+	for _, r := range s {
+		switch {
+		case unicode.IsDigit(r):
+			run++
+			longest = max(longest, run)
+		case r == ' ' || r == '-' || r == '.':
+			// Separators inside phone numbers
+		default:
+			run = 0
+		}
+	}
+	return longest
+}
@@ -0,0 +1,168 @@
+package analytics
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"sync"
+	"time"
+
+	"maukemana-backend/internal/config"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	viewQueueSize     = 4096
+	viewBatchSize     = 500
+	viewFlushInterval = 10 * time.Second
+)
+
+// ViewStore persists POI views
+type ViewStore interface {
+	InsertPOIViews(ctx context.Context, views []models.POIView) error
+	PurgePOIViews(ctx context.Context, before time.Time) (int64, error)
+}
+
+// ViewRecorder counts POI detail views at most once per viewer and POI per
+// hour and writes them in batches in the background. Viewers are identified
+// by an HMAC that rotates every hour; the identity itself is never stored.
+type ViewRecorder struct {
+	store     ViewStore
+	secret    []byte
+	retention time.Duration
+
+	mu       sync.Mutex
+	seenHour time.Time
+	seen     map[models.POIView]struct{}
+
+	queue  chan models.POIView
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewViewRecorder creates a recorder; it returns nil when view recording is disabled
+func NewViewRecorder(store ViewStore, cfg config.ViewAnalyticsSettings) *ViewRecorder {
+	if !cfg.Enabled {
+		return nil
+	}
+	secret := []byte(cfg.Secret)
+	if len(secret) == 0 {
+		// Hashes only need to be stable within the process for deduplication
+		secret = make([]byte, 32)
+		_, _ = rand.Read(secret)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ViewRecorder{
+		store:     store,
+		secret:    secret,
+		retention: cfg.Retention,
+		seen:      make(map[models.POIView]struct{}),
+		queue:     make(chan models.POIView, viewQueueSize),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// RecordView counts a view of a POI by a viewer identity (a user ID or a
+// client fingerprint). Repeated views within the same hour are ignored.
+func (r *ViewRecorder) RecordView(poiID uuid.UUID, viewer string) {
+	hour := time.Now().UTC().Truncate(time.Hour)
+	view := models.POIView{PoiID: poiID, ViewedHour: hour, ViewerHash: r.viewerHash(viewer, hour)}
+
+	r.mu.Lock()
+	if !hour.Equal(r.seenHour) {
+		r.seenHour = hour
+		clear(r.seen)
+	}
+	_, dup := r.seen[view]
+	if !dup {
+		r.seen[view] = struct{}{}
+	}
+	r.mu.Unlock()
+	if dup {
+		return
+	}
+
+	select {
+	case r.queue <- view:
+	default:
+		slog.Debug("view queue full, dropping view")
+	}
+}
+
+// viewerHash keys the viewer identity by hour so views cannot be linked across hours
+func (r *ViewRecorder) viewerHash(viewer string, hour time.Time) string {
+	mac := hmac.New(sha256.New, r.secret)
+	mac.Write([]byte(hour.Format(time.RFC3339)))
+	mac.Write([]byte{0})
+	mac.Write([]byte(viewer))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// Start begins writing queued views and purging expired ones
+func (r *ViewRecorder) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		flush := time.NewTicker(viewFlushInterval)
+		defer flush.Stop()
+		purge := time.NewTicker(purgeInterval)
+		defer purge.Stop()
+
+		batch := make([]models.POIView, 0, viewBatchSize)
+		for {
+			select {
+			case <-r.ctx.Done():
+				// Drain what is already queued before stopping
+				for len(r.queue) > 0 && len(batch) < cap(batch) {
+					batch = append(batch, <-r.queue)
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				r.write(ctx, batch)
+				cancel()
+				return
+			case view := <-r.queue:
+				batch = append(batch, view)
+				if len(batch) >= viewBatchSize {
+					r.write(r.ctx, batch)
+					batch = batch[:0]
+				}
+			case <-flush.C:
+				r.write(r.ctx, batch)
+				batch = batch[:0]
+			case <-purge.C:
+				r.purge()
+			}
+		}
+	}()
+}
+
+// Stop writes the queued views and stops the recorder
+func (r *ViewRecorder) Stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+func (r *ViewRecorder) write(ctx context.Context, batch []models.POIView) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := r.store.InsertPOIViews(ctx, batch); err != nil {
+		slog.Error("write poi views failed", "count", len(batch), "error", err)
+	}
+}
+
+func (r *ViewRecorder) purge() {
+	n, err := r.store.PurgePOIViews(r.ctx, time.Now().Add(-r.retention))
+	if err != nil {
+		slog.Error("purge poi views failed", "error", err)
+	} else if n > 0 {
+		slog.Info("purged expired poi views", "count", n)
+	}
+}
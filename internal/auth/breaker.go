@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"maukemana-backend/internal/breaker"
+	"maukemana-backend/internal/config"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+)
+
+// clerkBreaker guards calls to the Clerk API. While it is open, tokens are
+// still verified against the cached signing keys.
+var clerkBreaker = newClerkBreaker()
+
+func newClerkBreaker() *breaker.Breaker {
+	cfg := config.GetBreakerSettings()
+	return breaker.New("clerk", breaker.Settings{
+		MaxFailures: cfg.MaxFailures,
+		OpenFor:     cfg.OpenFor,
+		IsFailure:   isClerkFailure,
+	})
+}
+
+// isClerkFailure counts network errors and server errors; a rejected request
+// (such as an unknown user) says nothing about Clerk's health
+func isClerkFailure(err error) bool {
+	var apiErr *clerk.APIErrorResponse
+	if errors.As(err, &apiErr) && apiErr.HTTPStatusCode < http.StatusInternalServerError {
+		return false
+	}
+	return !errors.Is(err, context.Canceled)
+}
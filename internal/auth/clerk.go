@@ -5,12 +5,16 @@ import (
 	"os"
 	"time"
 
+	"maukemana-backend/internal/breaker"
+	"maukemana-backend/internal/config"
+
 	"github.com/clerk/clerk-sdk-go/v2"
 	"github.com/clerk/clerk-sdk-go/v2/jwt"
 	"github.com/clerk/clerk-sdk-go/v2/user"
 )
 
-// InitClerk initializes the Clerk SDK
+// InitClerk initializes the Clerk SDK and starts refreshing the signing keys
+// used to verify session tokens
 func InitClerk() {
 	secretKey := os.Getenv("CLERK_SECRET_KEY")
 	if secretKey == "" {
@@ -18,12 +22,25 @@ func InitClerk() {
 		panic("CLERK_SECRET_KEY not set")
 	}
 	clerk.SetKey(secretKey)
+	signingKeys.startRefresh(config.GetAuthCacheSettings().JWKSRefresh)
 }
 
-// VerifyToken verifies the session token and returns the claims
+// VerifyToken verifies the session token and returns the claims. Signing keys
+// come from the in-memory key cache, so verification does not call Clerk.
 func VerifyToken(token string) (*clerk.SessionClaims, error) {
-	claims, err := jwt.Verify(context.Background(), &jwt.VerifyParams{
+	ctx := context.Background()
+	unverified, err := jwt.Decode(ctx, &jwt.DecodeParams{Token: token})
+	if err != nil {
+		return nil, err
+	}
+	key, err := signingKeys.get(ctx, unverified.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := jwt.Verify(ctx, &jwt.VerifyParams{
 		Token:  token,
+		JWK:    key,
 		Leeway: 30 * time.Second,
 	})
 	if err != nil {
@@ -34,5 +51,27 @@ func VerifyToken(token string) (*clerk.SessionClaims, error) {
 
 // GetUser retrieves a user from Clerk by ID
 func GetUser(userID string) (*clerk.User, error) {
-	return user.Get(context.Background(), userID)
+	ctx := context.Background()
+	return breaker.Call(ctx, clerkBreaker, func() (*clerk.User, error) {
+		return user.Get(ctx, userID)
+	})
+}
+
+// DeleteUser removes a user from Clerk, ending their sessions
+func DeleteUser(ctx context.Context, userID string) error {
+	return clerkBreaker.Do(ctx, func() error {
+		_, err := user.Delete(ctx, userID)
+		return err
+	})
+}
+
+// VerifiedEmails returns the email addresses of a Clerk user that passed verification
+func VerifiedEmails(u *clerk.User) []string {
+	var emails []string
+	for _, e := range u.EmailAddresses {
+		if e.Verification != nil && e.Verification.Status == "verified" {
+			emails = append(emails, e.EmailAddress)
+		}
+	}
+	return emails
 }
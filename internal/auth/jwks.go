@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"maukemana-backend/internal/breaker"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/clerk/clerk-sdk-go/v2/jwks"
+	"golang.org/x/sync/singleflight"
+)
+
+// minKeyRefetch limits how often an unknown key ID can force a JWKS fetch,
+// so forged tokens cannot turn every request into a call to Clerk
+const minKeyRefetch = 30 * time.Second
+
+var errUnknownSigningKey = errors.New("unknown token signing key")
+
+// keyCache holds Clerk's JSON Web Key Set in memory. It is refreshed in the
+// background and on demand when a token is signed with a key it has not seen,
+// which is how key rotation shows up.
+type keyCache struct {
+	mu        sync.RWMutex
+	keys      map[string]*clerk.JSONWebKey
+	fetchedAt time.Time
+
+	fetches singleflight.Group
+}
+
+var signingKeys = &keyCache{keys: make(map[string]*clerk.JSONWebKey)}
+
+// get returns the key for kid, fetching the key set if it is not cached
+func (k *keyCache) get(ctx context.Context, kid string) (*clerk.JSONWebKey, error) {
+	if kid == "" {
+		return nil, fmt.Errorf("missing jwt kid header claim")
+	}
+
+	k.mu.RLock()
+	key, ok := k.keys[kid]
+	stale := time.Since(k.fetchedAt) >= minKeyRefetch
+	k.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+	if !stale {
+		return nil, errUnknownSigningKey
+	}
+
+	if err := k.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	k.mu.RLock()
+	key, ok = k.keys[kid]
+	k.mu.RUnlock()
+	if !ok {
+		return nil, errUnknownSigningKey
+	}
+	return key, nil
+}
+
+// refresh replaces the cached key set. Concurrent callers share one fetch.
+func (k *keyCache) refresh(ctx context.Context) error {
+	_, err, _ := k.fetches.Do("jwks", func() (interface{}, error) {
+		set, err := breaker.Call(ctx, clerkBreaker, func() (*clerk.JSONWebKeySet, error) {
+			return jwks.Get(ctx, &jwks.GetParams{})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("fetch jwks: %w", err)
+		}
+
+		keys := make(map[string]*clerk.JSONWebKey, len(set.Keys))
+		for _, key := range set.Keys {
+			if key != nil {
+				keys[key.KeyID] = key
+			}
+		}
+
+		k.mu.Lock()
+		k.keys = keys
+		k.fetchedAt = time.Now()
+		k.mu.Unlock()
+		return nil, nil
+	})
+	return err
+}
+
+// startRefresh loads the key set and keeps it fresh every interval. A failed
+// refresh keeps serving the previous keys.
+func (k *keyCache) startRefresh(interval time.Duration) {
+	if err := k.refresh(context.Background()); err != nil {
+		slog.Warn("initial JWKS fetch failed; keys will be fetched on first use", "error", err)
+	}
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			if err := k.refresh(ctx); err != nil {
+				slog.Warn("JWKS refresh failed", "error", err)
+			}
+			cancel()
+		}
+	}()
+}
@@ -0,0 +1,235 @@
+// Package breaker implements circuit breakers around external dependencies
+// (R2, Clerk, geocoding), so a slow or failing service is skipped for a while
+// instead of adding its latency to every request.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const instrumentationName = "maukemana-backend/internal/breaker"
+
+// ErrOpen is returned without calling the dependency while the breaker is open
+var ErrOpen = errors.New("circuit breaker open")
+
+// State is the position of a breaker
+type State int
+
+const (
+	// Closed lets calls through and counts consecutive failures
+	Closed State = iota
+	// HalfOpen lets a single trial call through after the open period
+	HalfOpen
+	// Open rejects calls until the open period has passed
+	Open
+)
+
+func (s State) String() string {
+	switch s {
+	case HalfOpen:
+		return "half_open"
+	case Open:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+// Settings tunes a breaker
+type Settings struct {
+	// MaxFailures is how many consecutive failures open the breaker
+	MaxFailures int
+	// OpenFor is how long calls are rejected before a trial call is allowed
+	OpenFor time.Duration
+	// IsFailure decides which errors count against the dependency. Nil counts
+	// every error except the caller's own cancellation.
+	IsFailure func(error) bool
+}
+
+// Breaker tracks the health of one dependency
+type Breaker struct {
+	name     string
+	settings Settings
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trialOut bool // A half-open trial call is in flight
+}
+
+// New creates a closed breaker and registers it for the state metrics
+func New(name string, settings Settings) *Breaker {
+	if settings.MaxFailures <= 0 {
+		settings.MaxFailures = 5
+	}
+	if settings.OpenFor <= 0 {
+		settings.OpenFor = 30 * time.Second
+	}
+	if settings.IsFailure == nil {
+		settings.IsFailure = defaultIsFailure
+	}
+	b := &Breaker{name: name, settings: settings}
+	registry.add(b)
+	return b
+}
+
+func defaultIsFailure(err error) bool {
+	return !errors.Is(err, context.Canceled)
+}
+
+// Name identifies the dependency in logs and metrics
+func (b *Breaker) Name() string { return b.name }
+
+// State returns the current position, moving an expired open breaker to half-open
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.currentLocked(time.Now())
+}
+
+// Do calls fn unless the breaker is open, and records its outcome
+func (b *Breaker) Do(ctx context.Context, fn func() error) error {
+	if !b.allow() {
+		metrics.recordRejection(ctx, b.name)
+		return ErrOpen
+	}
+	err := fn()
+	b.record(err)
+	return err
+}
+
+// Call runs fn through a breaker and returns its result
+func Call[T any](ctx context.Context, b *Breaker, fn func() (T, error)) (T, error) {
+	var result T
+	err := b.Do(ctx, func() error {
+		var err error
+		result, err = fn()
+		return err
+	})
+	return result, err
+}
+
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.currentLocked(time.Now()) {
+	case Open:
+		return false
+	case HalfOpen:
+		if b.trialOut {
+			return false
+		}
+		b.trialOut = true
+	}
+	return true
+}
+
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	failed := err != nil && b.settings.IsFailure(err)
+	prev := b.state
+	switch {
+	case !failed:
+		b.state, b.failures = Closed, 0
+	case prev == HalfOpen:
+		b.state, b.openedAt = Open, time.Now()
+	default:
+		b.failures++
+		if b.failures >= b.settings.MaxFailures {
+			b.state, b.openedAt = Open, time.Now()
+		}
+	}
+	if prev == HalfOpen {
+		b.trialOut = false
+	}
+	if b.state != prev {
+		if b.state == Open {
+			slog.Warn("circuit breaker opened", "dependency", b.name, "error", err, "open_for", b.settings.OpenFor)
+		} else {
+			slog.Info("circuit breaker closed", "dependency", b.name)
+		}
+	}
+}
+
+// currentLocked moves an open breaker whose period has passed to half-open.
+// Callers hold mu.
+func (b *Breaker) currentLocked(now time.Time) State {
+	if b.state == Open && now.Sub(b.openedAt) >= b.settings.OpenFor {
+		b.state, b.trialOut = HalfOpen, false
+	}
+	return b.state
+}
+
+// breakers lists every breaker for the state gauge
+type breakers struct {
+	mu    sync.Mutex
+	items []*Breaker
+}
+
+var registry = &breakers{}
+
+func (r *breakers) add(b *Breaker) {
+	r.mu.Lock()
+	r.items = append(r.items, b)
+	r.mu.Unlock()
+}
+
+func (r *breakers) all() []*Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*Breaker(nil), r.items...)
+}
+
+// breakerMetrics holds the OTel instruments for circuit breakers. Instruments
+// are created from the global MeterProvider, so they are no-ops until
+// observability.InitOTel installs a real one.
+type breakerMetrics struct {
+	rejections metric.Int64Counter
+}
+
+var metrics = newBreakerMetrics()
+
+func newBreakerMetrics() *breakerMetrics {
+	meter := otel.Meter(instrumentationName)
+	m := &breakerMetrics{}
+	var err error
+
+	if m.rejections, err = meter.Int64Counter("breaker.rejections",
+		metric.WithDescription("Calls rejected because the dependency's circuit breaker was open"),
+		metric.WithUnit("{call}")); err != nil {
+		slog.Warn("failed to create breaker metric", "name", "breaker.rejections", "error", err)
+	}
+
+	state, err := meter.Int64ObservableGauge("breaker.state",
+		metric.WithDescription("Circuit breaker state per dependency: 0 closed, 1 half-open, 2 open"))
+	if err != nil {
+		slog.Warn("failed to create breaker metric", "name", "breaker.state", "error", err)
+		return m
+	}
+	if _, err := meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for _, b := range registry.all() {
+			o.ObserveInt64(state, int64(b.State()), metric.WithAttributes(attribute.String("dependency", b.name)))
+		}
+		return nil
+	}, state); err != nil {
+		slog.Warn("failed to register breaker state callback", "error", err)
+	}
+	return m
+}
+
+func (m *breakerMetrics) recordRejection(ctx context.Context, name string) {
+	if m.rejections != nil {
+		m.rejections.Add(ctx, 1, metric.WithAttributes(attribute.String("dependency", name)))
+	}
+}
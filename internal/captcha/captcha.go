@@ -0,0 +1,106 @@
+// Package captcha verifies Cloudflare Turnstile and Google reCAPTCHA tokens server-side.
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"maukemana-backend/internal/config"
+)
+
+const (
+	turnstileURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+	recaptchaURL = "https://www.google.com/recaptcha/api/siteverify"
+)
+
+// ErrRejected is returned when the provider does not accept a token
+var ErrRejected = errors.New("captcha token rejected")
+
+// Verifier checks tokens against the provider's siteverify endpoint. Both
+// providers share the same request format and response fields.
+type Verifier struct {
+	httpClient *http.Client
+	provider   string
+	verifyURL  string
+	secret     string
+	minScore   float64
+}
+
+// NewFromConfig builds the configured verifier. It returns nil when no provider is set.
+func NewFromConfig(cfg config.CaptchaSettings) (*Verifier, error) {
+	v := &Verifier{
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		provider:   cfg.Provider,
+		secret:     cfg.Secret,
+		minScore:   cfg.MinScore,
+	}
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case "turnstile":
+		v.verifyURL = turnstileURL
+	case "recaptcha":
+		v.verifyURL = recaptchaURL
+	default:
+		return nil, fmt.Errorf("unknown captcha provider %q", cfg.Provider)
+	}
+	if cfg.Secret == "" {
+		return nil, errors.New("CAPTCHA_SECRET is required for bot protection")
+	}
+	return v, nil
+}
+
+// Name returns the provider name
+func (v *Verifier) Name() string { return v.provider }
+
+// siteverifyResponse is the part of the provider response that is checked.
+// Score is only sent by reCAPTCHA v3.
+type siteverifyResponse struct {
+	Success    bool     `json:"success"`
+	Score      *float64 `json:"score"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+// Verify checks a token solved by the client at remoteIP. It returns
+// ErrRejected for invalid, expired or low-score tokens, and other errors when
+// the provider could not be asked.
+func (v *Verifier) Verify(ctx context.Context, token, remoteIP string) error {
+	form := url.Values{"secret": {v.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("build %s request: %w", v.provider, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request: %w", v.provider, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s returned %d: %s", v.provider, resp.StatusCode, body)
+	}
+
+	var result siteverifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("decode %s response: %w", v.provider, err)
+	}
+	if !result.Success {
+		return fmt.Errorf("%w: %s", ErrRejected, strings.Join(result.ErrorCodes, ", "))
+	}
+	if result.Score != nil && *result.Score < v.minScore {
+		return fmt.Errorf("%w: score %.2f below %.2f", ErrRejected, *result.Score, v.minScore)
+	}
+	return nil
+}
@@ -3,7 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -37,3 +39,561 @@ func GetAllowedOrigins() []string {
 	}
 	return origins
 }
+
+// RankingWeights tunes the sort_by=recommended scoring expression.
+// Each component is normalised to 0..1 before weighting.
+type RankingWeights struct {
+	Rating   float64 // Bayesian-smoothed rating
+	Recency  float64 // Exponential decay on POI age
+	Distance float64 // Proximity, only applied when coordinates are supplied
+	Verified float64 // Bonus for verified POIs
+
+	PriorMean       float64 // Rating assumed for POIs with few reviews
+	PriorWeight     float64 // Number of "virtual" reviews at PriorMean
+	RecencyHalfLife float64 // Days until the recency component halves
+	DistanceScaleKm float64 // Distance at which the proximity component halves
+}
+
+// GetRankingWeights returns ranking weights from RANKING_* environment variables,
+// falling back to defaults for unset or invalid values.
+func GetRankingWeights() RankingWeights {
+	return RankingWeights{
+		Rating:          getEnvFloat("RANKING_WEIGHT_RATING", 0.5),
+		Recency:         getEnvFloat("RANKING_WEIGHT_RECENCY", 0.2),
+		Distance:        getEnvFloat("RANKING_WEIGHT_DISTANCE", 0.2),
+		Verified:        getEnvFloat("RANKING_WEIGHT_VERIFIED", 0.1),
+		PriorMean:       getEnvFloat("RANKING_PRIOR_MEAN", 3.5),
+		PriorWeight:     getEnvFloat("RANKING_PRIOR_WEIGHT", 5),
+		RecencyHalfLife: getEnvFloat("RANKING_RECENCY_HALF_LIFE_DAYS", 30),
+		DistanceScaleKm: getEnvFloat("RANKING_DISTANCE_SCALE_KM", 2),
+	}
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	v, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil || v < 0 {
+		return defaultValue
+	}
+	return v
+}
+
+// TimeoutSettings bounds how long a request may run
+type TimeoutSettings struct {
+	Default time.Duration // REQUEST_TIMEOUT_SECONDS, default 10
+	Long    time.Duration // REQUEST_LONG_TIMEOUT_SECONDS, for bulk and export endpoints, default 60
+}
+
+// GetTimeoutSettings returns request timeout settings from the environment
+func GetTimeoutSettings() TimeoutSettings {
+	return TimeoutSettings{
+		Default: time.Duration(getEnvFloat("REQUEST_TIMEOUT_SECONDS", 10) * float64(time.Second)),
+		Long:    time.Duration(getEnvFloat("REQUEST_LONG_TIMEOUT_SECONDS", 60) * float64(time.Second)),
+	}
+}
+
+// CompressionSettings configures gzip compression of responses
+type CompressionSettings struct {
+	Enabled bool // COMPRESSION_ENABLED, default true
+	Level   int  // COMPRESSION_LEVEL, gzip level 1-9, default 5
+	MinSize int  // COMPRESSION_MIN_BYTES, smaller responses are sent uncompressed, default 1024
+}
+
+// GetCompressionSettings returns response compression settings from the environment
+func GetCompressionSettings() CompressionSettings {
+	return CompressionSettings{
+		Enabled: getEnvBool("COMPRESSION_ENABLED", true),
+		Level:   min(max(int(getEnvFloat("COMPRESSION_LEVEL", 5)), 1), 9),
+		MinSize: int(getEnvFloat("COMPRESSION_MIN_BYTES", 1024)),
+	}
+}
+
+// GetMaxBodyBytes returns the default request body limit (MAX_BODY_KB, default 1024)
+func GetMaxBodyBytes() int64 {
+	return int64(getEnvFloat("MAX_BODY_KB", 1024)) << 10
+}
+
+// GetDefaultLocale returns the locale POI content is authored in (DEFAULT_LOCALE, default "id")
+func GetDefaultLocale() string {
+	if v := strings.TrimSpace(os.Getenv("DEFAULT_LOCALE")); v != "" {
+		return v
+	}
+	return "id"
+}
+
+// GetSupportedLocales returns the locales the API can serve (SUPPORTED_LOCALES, default "id,en").
+// The default locale is always included and listed first.
+func GetSupportedLocales() []string {
+	def := GetDefaultLocale()
+	locales := []string{def}
+
+	raw := os.Getenv("SUPPORTED_LOCALES")
+	if raw == "" {
+		raw = "id,en"
+	}
+	for _, p := range strings.Split(raw, ",") {
+		if l := strings.TrimSpace(p); l != "" && l != def {
+			locales = append(locales, l)
+		}
+	}
+	return locales
+}
+
+// GraphQLSettings controls the optional /graphql endpoint
+type GraphQLSettings struct {
+	Enabled       bool // GRAPHQL_ENABLED, default false
+	Introspection bool // GRAPHQL_INTROSPECTION, default false
+}
+
+// GetGraphQLSettings returns GraphQL endpoint settings from the environment
+func GetGraphQLSettings() GraphQLSettings {
+	return GraphQLSettings{
+		Enabled:       getEnvBool("GRAPHQL_ENABLED", false),
+		Introspection: getEnvBool("GRAPHQL_INTROSPECTION", false),
+	}
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	v, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return v
+}
+
+// SearchSettings configures the external full-text search index
+type SearchSettings struct {
+	Provider string // SEARCH_PROVIDER: "meilisearch", "opensearch" or "" (database search only)
+	URL      string // SEARCH_URL
+	APIKey   string // SEARCH_API_KEY (Meilisearch)
+	Username string // SEARCH_USERNAME (OpenSearch basic auth)
+	Password string // SEARCH_PASSWORD (OpenSearch basic auth)
+	Index    string // SEARCH_INDEX, default "pois"
+}
+
+// GetSearchSettings returns search index settings from the environment
+func GetSearchSettings() SearchSettings {
+	index := strings.TrimSpace(os.Getenv("SEARCH_INDEX"))
+	if index == "" {
+		index = "pois"
+	}
+	return SearchSettings{
+		Provider: strings.ToLower(strings.TrimSpace(os.Getenv("SEARCH_PROVIDER"))),
+		URL:      strings.TrimRight(strings.TrimSpace(os.Getenv("SEARCH_URL")), "/"),
+		APIKey:   os.Getenv("SEARCH_API_KEY"),
+		Username: os.Getenv("SEARCH_USERNAME"),
+		Password: os.Getenv("SEARCH_PASSWORD"),
+		Index:    index,
+	}
+}
+
+// EmbeddingSettings configures the text embedding provider used by semantic search
+type EmbeddingSettings struct {
+	Provider string // EMBEDDING_PROVIDER: "openai" or "" (semantic search disabled)
+	Model    string // EMBEDDING_MODEL, default "text-embedding-3-small"
+	APIKey   string // EMBEDDING_API_KEY, falling back to OPENAI_API_KEY
+	URL      string // EMBEDDING_URL, for OpenAI-compatible endpoints
+}
+
+// GetEmbeddingSettings returns embedding provider settings from the environment
+func GetEmbeddingSettings() EmbeddingSettings {
+	model := strings.TrimSpace(os.Getenv("EMBEDDING_MODEL"))
+	if model == "" {
+		model = "text-embedding-3-small"
+	}
+	apiKey := os.Getenv("EMBEDDING_API_KEY")
+	if apiKey == "" {
+		apiKey = os.Getenv("OPENAI_API_KEY")
+	}
+	return EmbeddingSettings{
+		Provider: strings.ToLower(strings.TrimSpace(os.Getenv("EMBEDDING_PROVIDER"))),
+		Model:    model,
+		APIKey:   apiKey,
+		URL:      strings.TrimRight(strings.TrimSpace(os.Getenv("EMBEDDING_URL")), "/"),
+	}
+}
+
+// RoutingSettings configures the distance-matrix provider used for travel times
+type RoutingSettings struct {
+	Provider  string        // ROUTING_PROVIDER: "osrm", "mapbox", "google" or "" (straight-line distance only)
+	URL       string        // ROUTING_URL, OSRM server base URL
+	APIKey    string        // ROUTING_API_KEY (Mapbox access token or Google API key)
+	CacheTTL  time.Duration // ROUTING_CACHE_TTL_MINUTES, default 60
+	CacheSize int           // ROUTING_CACHE_SIZE, max cached origin/destination pairs, default 100000
+}
+
+// GetRoutingSettings returns routing provider settings from the environment
+func GetRoutingSettings() RoutingSettings {
+	return RoutingSettings{
+		Provider:  strings.ToLower(strings.TrimSpace(os.Getenv("ROUTING_PROVIDER"))),
+		URL:       strings.TrimRight(strings.TrimSpace(os.Getenv("ROUTING_URL")), "/"),
+		APIKey:    os.Getenv("ROUTING_API_KEY"),
+		CacheTTL:  time.Duration(getEnvFloat("ROUTING_CACHE_TTL_MINUTES", 60) * float64(time.Minute)),
+		CacheSize: int(getEnvFloat("ROUTING_CACHE_SIZE", 100000)),
+	}
+}
+
+// GeoIPSettings configures the IP geolocation used when searches sort by
+// distance without coordinates
+type GeoIPSettings struct {
+	Provider   string        // GEOIP_PROVIDER: "maxmind", "ipinfo" or "" (disabled)
+	URL        string        // GEOIP_URL, MaxMind web service base URL, default "https://geolite.info" (GeoLite2)
+	AccountID  string        // GEOIP_ACCOUNT_ID, MaxMind account ID
+	LicenseKey string        // GEOIP_LICENSE_KEY (MaxMind license key or ipinfo token)
+	CacheTTL   time.Duration // GEOIP_CACHE_TTL_HOURS, default 24
+	CacheSize  int           // GEOIP_CACHE_SIZE, max cached addresses, default 50000
+}
+
+// GetGeoIPSettings returns IP geolocation settings from the environment
+func GetGeoIPSettings() GeoIPSettings {
+	baseURL := strings.TrimRight(strings.TrimSpace(os.Getenv("GEOIP_URL")), "/")
+	if baseURL == "" {
+		baseURL = "https://geolite.info"
+	}
+	return GeoIPSettings{
+		Provider:   strings.ToLower(strings.TrimSpace(os.Getenv("GEOIP_PROVIDER"))),
+		URL:        baseURL,
+		AccountID:  strings.TrimSpace(os.Getenv("GEOIP_ACCOUNT_ID")),
+		LicenseKey: os.Getenv("GEOIP_LICENSE_KEY"),
+		CacheTTL:   time.Duration(getEnvFloat("GEOIP_CACHE_TTL_HOURS", 24) * float64(time.Hour)),
+		CacheSize:  int(getEnvFloat("GEOIP_CACHE_SIZE", 50000)),
+	}
+}
+
+// BreakerSettings tunes the circuit breakers around R2, Clerk and geocoding
+type BreakerSettings struct {
+	MaxFailures int           // BREAKER_MAX_FAILURES, consecutive failures that open a breaker, default 5
+	OpenFor     time.Duration // BREAKER_OPEN_SECONDS, how long an open breaker rejects calls, default 30
+}
+
+// GetBreakerSettings returns circuit breaker settings from the environment
+func GetBreakerSettings() BreakerSettings {
+	return BreakerSettings{
+		MaxFailures: max(int(getEnvFloat("BREAKER_MAX_FAILURES", 5)), 1),
+		OpenFor:     time.Duration(getEnvFloat("BREAKER_OPEN_SECONDS", 30) * float64(time.Second)),
+	}
+}
+
+// AuthCacheSettings controls the caches that keep token verification off the network
+type AuthCacheSettings struct {
+	UserTTL       time.Duration // AUTH_USER_CACHE_TTL_SECONDS, how long a signed-in user is served from memory, default 60
+	UserCacheSize int           // AUTH_USER_CACHE_SIZE, max cached users, default 10000
+	JWKSRefresh   time.Duration // AUTH_JWKS_REFRESH_MINUTES, background refresh interval for signing keys, default 60
+}
+
+// GetAuthCacheSettings returns auth cache settings from the environment
+func GetAuthCacheSettings() AuthCacheSettings {
+	return AuthCacheSettings{
+		UserTTL:       time.Duration(getEnvFloat("AUTH_USER_CACHE_TTL_SECONDS", 60) * float64(time.Second)),
+		UserCacheSize: int(getEnvFloat("AUTH_USER_CACHE_SIZE", 10000)),
+		JWKSRefresh:   time.Duration(getEnvFloat("AUTH_JWKS_REFRESH_MINUTES", 60) * float64(time.Minute)),
+	}
+}
+
+// ValidationSettings configures the automated checks run before approving a POI
+type ValidationSettings struct {
+	DuplicateRadius float64 // VALIDATION_DUPLICATE_RADIUS_METERS, default 150
+}
+
+// GetValidationSettings returns pre-approval validation settings from the environment
+func GetValidationSettings() ValidationSettings {
+	return ValidationSettings{
+		DuplicateRadius: getEnvFloat("VALIDATION_DUPLICATE_RADIUS_METERS", 150),
+	}
+}
+
+// ReviewSettings configures the moderation queue
+type ReviewSettings struct {
+	AssignmentTimeout time.Duration // REVIEW_ASSIGNMENT_TIMEOUT_MINUTES, how long a reviewer keeps a POI assigned, default 60
+}
+
+// GetReviewSettings returns moderation queue settings from the environment
+func GetReviewSettings() ReviewSettings {
+	return ReviewSettings{
+		AssignmentTimeout: time.Duration(max(getEnvFloat("REVIEW_ASSIGNMENT_TIMEOUT_MINUTES", 60), 1) * float64(time.Minute)),
+	}
+}
+
+// SpamSettings configures the heuristics run before comments and reviews are stored
+type SpamSettings struct {
+	MaxPerWindow    int           // SPAM_MAX_SUBMISSIONS, comments and reviews a user may post per window, default 10
+	Window          time.Duration // SPAM_WINDOW_MINUTES, velocity window, default 10
+	DuplicateWindow time.Duration // SPAM_DUPLICATE_WINDOW_HOURS, how long identical content is rejected, default 168
+	MaxLinks        int           // SPAM_MAX_LINKS, links allowed in one submission, default 2
+}
+
+// GetSpamSettings returns anti-spam settings from the environment
+func GetSpamSettings() SpamSettings {
+	return SpamSettings{
+		MaxPerWindow:    int(getEnvFloat("SPAM_MAX_SUBMISSIONS", 10)),
+		Window:          time.Duration(getEnvFloat("SPAM_WINDOW_MINUTES", 10) * float64(time.Minute)),
+		DuplicateWindow: time.Duration(getEnvFloat("SPAM_DUPLICATE_WINDOW_HOURS", 168) * float64(time.Hour)),
+		MaxLinks:        int(getEnvFloat("SPAM_MAX_LINKS", 2)),
+	}
+}
+
+// DatabasePoolSettings sizes the API server's connection pool
+type DatabasePoolSettings struct {
+	MaxOpenConns     int           // DB_MAX_OPEN_CONNS, default 25
+	MaxIdleConns     int           // DB_MAX_IDLE_CONNS, default 5
+	ConnMaxLifetime  time.Duration // DB_CONN_MAX_LIFETIME_MINUTES, default 5
+	ConnMaxIdleTime  time.Duration // DB_CONN_MAX_IDLE_TIME_MINUTES, idle connections are closed after this, 0 keeps them, default 0
+	StatementTimeout time.Duration // DB_STATEMENT_TIMEOUT_SECONDS, set on every connection, 0 disables, default 30
+}
+
+// GetDatabasePoolSettings returns connection pool settings from the environment
+func GetDatabasePoolSettings() DatabasePoolSettings {
+	return DatabasePoolSettings{
+		MaxOpenConns:     max(int(getEnvFloat("DB_MAX_OPEN_CONNS", 25)), 1),
+		MaxIdleConns:     int(getEnvFloat("DB_MAX_IDLE_CONNS", 5)),
+		ConnMaxLifetime:  time.Duration(getEnvFloat("DB_CONN_MAX_LIFETIME_MINUTES", 5) * float64(time.Minute)),
+		ConnMaxIdleTime:  time.Duration(getEnvFloat("DB_CONN_MAX_IDLE_TIME_MINUTES", 0) * float64(time.Minute)),
+		StatementTimeout: time.Duration(getEnvFloat("DB_STATEMENT_TIMEOUT_SECONDS", 30) * float64(time.Second)),
+	}
+}
+
+// QueryLogSettings configures the logging of repository queries
+type QueryLogSettings struct {
+	SlowThreshold time.Duration // DB_SLOW_QUERY_MS, queries at least this slow are logged as warnings, 0 disables, default 500
+}
+
+// GetQueryLogSettings returns query logging settings from the environment
+func GetQueryLogSettings() QueryLogSettings {
+	return QueryLogSettings{
+		SlowThreshold: time.Duration(getEnvFloat("DB_SLOW_QUERY_MS", 500) * float64(time.Millisecond)),
+	}
+}
+
+// MigrationSettings controls migrations applied by the server itself
+type MigrationSettings struct {
+	AutoMigrate bool // AUTO_MIGRATE, apply pending migrations at startup, default false
+}
+
+// GetMigrationSettings returns migration settings from the environment
+func GetMigrationSettings() MigrationSettings {
+	return MigrationSettings{
+		AutoMigrate: getEnvBool("AUTO_MIGRATE", false),
+	}
+}
+
+// ImagingSettings configures the image processing workers
+type ImagingSettings struct {
+	Workers        int           // IMAGING_WORKERS, initial size of the worker pool, default 4
+	DrainTimeout   time.Duration // IMAGING_DRAIN_TIMEOUT_SECONDS, time in-flight jobs get to finish on shutdown, default 60
+	OriginalURLTTL time.Duration // IMAGING_ORIGINAL_URL_TTL_SECONDS, lifetime of signed original URLs, default 300
+
+	// Caching of /img asset lookups; 0 disables
+	LookupTTL         time.Duration // IMAGING_LOOKUP_TTL_SECONDS, for servable assets, default 60
+	LookupNegativeTTL time.Duration // IMAGING_LOOKUP_NEGATIVE_TTL_SECONDS, for missing or unready assets, default 10
+
+	LadderRefresh time.Duration // IMAGING_LADDER_REFRESH_SECONDS, how often rendition ladder changes are picked up, 0 disables, default 60
+}
+
+// GetImagingSettings returns imaging worker settings from the environment
+func GetImagingSettings() ImagingSettings {
+	return ImagingSettings{
+		Workers:        int(getEnvFloat("IMAGING_WORKERS", 4)),
+		DrainTimeout:   time.Duration(getEnvFloat("IMAGING_DRAIN_TIMEOUT_SECONDS", 60) * float64(time.Second)),
+		OriginalURLTTL: time.Duration(getEnvFloat("IMAGING_ORIGINAL_URL_TTL_SECONDS", 300) * float64(time.Second)),
+
+		LookupTTL:         time.Duration(getEnvFloat("IMAGING_LOOKUP_TTL_SECONDS", 60) * float64(time.Second)),
+		LookupNegativeTTL: time.Duration(getEnvFloat("IMAGING_LOOKUP_NEGATIVE_TTL_SECONDS", 10) * float64(time.Second)),
+
+		LadderRefresh: time.Duration(getEnvFloat("IMAGING_LADDER_REFRESH_SECONDS", 60) * float64(time.Second)),
+	}
+}
+
+// MailSettings configures outgoing email
+type MailSettings struct {
+	Provider string // MAIL_PROVIDER: "smtp", "resend", "ses", "log" or "" (email disabled)
+	From     string // MAIL_FROM, e.g. "Maukemana <no-reply@maukemana.id>"
+
+	SMTPHost     string // SMTP_HOST
+	SMTPPort     int    // SMTP_PORT, default 587
+	SMTPUsername string // SMTP_USERNAME
+	SMTPPassword string // SMTP_PASSWORD
+
+	ResendAPIKey string // RESEND_API_KEY
+	SESRegion    string // MAIL_SES_REGION, falling back to AWS_REGION
+
+	AppURL            string        // APP_URL, web app base URL used in links, default "http://localhost:3000"
+	APIURL            string        // API_PUBLIC_URL, this API's public base URL used for unsubscribe links
+	UnsubscribeSecret string        // MAIL_UNSUBSCRIBE_SECRET, signs unsubscribe links, falling back to JWT_SECRET
+	DigestInterval    time.Duration // MAIL_DIGEST_INTERVAL_HOURS, time between saved-POI digests, 0 disables, default 168
+}
+
+// GetMailSettings returns email settings from the environment
+func GetMailSettings() MailSettings {
+	appURL := strings.TrimRight(strings.TrimSpace(os.Getenv("APP_URL")), "/")
+	if appURL == "" {
+		appURL = "http://localhost:3000"
+	}
+	secret := os.Getenv("MAIL_UNSUBSCRIBE_SECRET")
+	if secret == "" {
+		secret = os.Getenv("JWT_SECRET")
+	}
+	region := os.Getenv("MAIL_SES_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	return MailSettings{
+		Provider:          strings.ToLower(strings.TrimSpace(os.Getenv("MAIL_PROVIDER"))),
+		From:              strings.TrimSpace(os.Getenv("MAIL_FROM")),
+		SMTPHost:          strings.TrimSpace(os.Getenv("SMTP_HOST")),
+		SMTPPort:          int(getEnvFloat("SMTP_PORT", 587)),
+		SMTPUsername:      os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:      os.Getenv("SMTP_PASSWORD"),
+		ResendAPIKey:      os.Getenv("RESEND_API_KEY"),
+		SESRegion:         region,
+		AppURL:            appURL,
+		APIURL:            strings.TrimRight(strings.TrimSpace(os.Getenv("API_PUBLIC_URL")), "/"),
+		UnsubscribeSecret: secret,
+		DigestInterval:    time.Duration(getEnvFloat("MAIL_DIGEST_INTERVAL_HOURS", 168) * float64(time.Hour)),
+	}
+}
+
+// SearchAnalyticsSettings configures the sampling of public searches for analytics
+type SearchAnalyticsSettings struct {
+	SampleRate float64       // SEARCH_ANALYTICS_SAMPLE_RATE, share of first-page searches recorded (0-1), 0 disables, default 0.1
+	Retention  time.Duration // SEARCH_ANALYTICS_RETENTION_DAYS, how long events are kept, default 90
+}
+
+// GetSearchAnalyticsSettings returns search analytics settings from the environment
+func GetSearchAnalyticsSettings() SearchAnalyticsSettings {
+	return SearchAnalyticsSettings{
+		SampleRate: min(getEnvFloat("SEARCH_ANALYTICS_SAMPLE_RATE", 0.1), 1),
+		Retention:  time.Duration(getEnvFloat("SEARCH_ANALYTICS_RETENTION_DAYS", 90) * float64(24*time.Hour)),
+	}
+}
+
+// ViewAnalyticsSettings configures the recording of POI detail views
+type ViewAnalyticsSettings struct {
+	Enabled   bool          // POI_VIEWS_ENABLED, default true
+	Secret    string        // ANALYTICS_SECRET, keys the hourly viewer hashes, falling back to JWT_SECRET
+	Retention time.Duration // POI_VIEWS_RETENTION_DAYS, how long views are kept, default 30, at least the 7 day trending window
+}
+
+// GetViewAnalyticsSettings returns POI view analytics settings from the environment
+func GetViewAnalyticsSettings() ViewAnalyticsSettings {
+	secret := os.Getenv("ANALYTICS_SECRET")
+	if secret == "" {
+		secret = os.Getenv("JWT_SECRET")
+	}
+	return ViewAnalyticsSettings{
+		Enabled:   getEnvBool("POI_VIEWS_ENABLED", true),
+		Secret:    secret,
+		Retention: time.Duration(max(getEnvFloat("POI_VIEWS_RETENTION_DAYS", 30), 7) * float64(24*time.Hour)),
+	}
+}
+
+// CaptchaSettings configures bot protection on high-abuse endpoints
+type CaptchaSettings struct {
+	Provider string   // CAPTCHA_PROVIDER: "turnstile", "recaptcha" or "" (disabled)
+	Secret   string   // CAPTCHA_SECRET, the provider's server-side secret key
+	MinScore float64  // CAPTCHA_MIN_SCORE, lowest accepted reCAPTCHA v3 score (0-1), default 0.5
+	Routes   []string // CAPTCHA_ROUTES, comma-separated "METHOD /route/template" entries that require a token
+}
+
+// defaultCaptchaRoutes are the endpoints protected when CAPTCHA_ROUTES is unset
+var defaultCaptchaRoutes = []string{
+	"POST /api/v1/pois",
+	"POST /api/v1/pois/:id/submit",
+	"POST /api/v1/pois/:id/comments",
+}
+
+// GetCaptchaSettings returns bot protection settings from the environment
+func GetCaptchaSettings() CaptchaSettings {
+	routes := defaultCaptchaRoutes
+	if raw := os.Getenv("CAPTCHA_ROUTES"); raw != "" {
+		routes = nil
+		for _, p := range strings.Split(raw, ",") {
+			if r := strings.Join(strings.Fields(p), " "); r != "" {
+				routes = append(routes, r)
+			}
+		}
+	}
+	return CaptchaSettings{
+		Provider: strings.ToLower(strings.TrimSpace(os.Getenv("CAPTCHA_PROVIDER"))),
+		Secret:   os.Getenv("CAPTCHA_SECRET"),
+		MinScore: min(getEnvFloat("CAPTCHA_MIN_SCORE", 0.5), 1),
+		Routes:   routes,
+	}
+}
+
+// StorageProfile configures one object storage bucket on Cloudflare R2 or
+// another S3-compatible service. Variables are prefixed with
+// STORAGE_<NAME>_, e.g. STORAGE_DOCUMENTS_BUCKET for the "documents" profile.
+type StorageProfile struct {
+	Name            string
+	Endpoint        string // ENDPOINT, falling back to the R2 endpoint of R2_ACCOUNT_ID
+	Region          string // REGION, default "auto"
+	AccessKeyID     string // ACCESS_KEY_ID
+	SecretAccessKey string // SECRET_ACCESS_KEY
+	Bucket          string // BUCKET
+	PublicURL       string // PUBLIC_URL, base URL public objects are served from
+	PathStyle       bool   // PATH_STYLE, address buckets by path (MinIO and similar)
+}
+
+// StorageSettings configures object storage profiles and which one serves
+// each storage category
+type StorageSettings struct {
+	// STORAGE_PROFILES, comma-separated profile names. Without it the R2_*
+	// variables make up a single "default" profile.
+	Profiles []StorageProfile
+	// STORAGE_CATEGORY_<CATEGORY>, the profile serving a category such as
+	// "images" or "documents"; other categories use the first profile
+	Categories map[string]string
+}
+
+// GetStorageSettings returns object storage settings from the environment
+func GetStorageSettings() StorageSettings {
+	settings := StorageSettings{Categories: make(map[string]string)}
+
+	names := strings.Split(os.Getenv("STORAGE_PROFILES"), ",")
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "STORAGE_" + strings.ToUpper(name) + "_"
+		settings.Profiles = append(settings.Profiles, storageProfile(name,
+			os.Getenv(prefix+"ENDPOINT"), os.Getenv(prefix+"R2_ACCOUNT_ID"), os.Getenv(prefix+"REGION"),
+			os.Getenv(prefix+"ACCESS_KEY_ID"), os.Getenv(prefix+"SECRET_ACCESS_KEY"),
+			os.Getenv(prefix+"BUCKET"), os.Getenv(prefix+"PUBLIC_URL"), getEnvBool(prefix+"PATH_STYLE", false)))
+	}
+	if len(settings.Profiles) == 0 && os.Getenv("R2_BUCKET_NAME") != "" {
+		settings.Profiles = append(settings.Profiles, storageProfile("default",
+			"", os.Getenv("R2_ACCOUNT_ID"), "",
+			os.Getenv("R2_ACCESS_KEY_ID"), os.Getenv("R2_SECRET_ACCESS_KEY"),
+			os.Getenv("R2_BUCKET_NAME"), os.Getenv("R2_PUBLIC_URL"), false))
+	}
+
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if category, ok := strings.CutPrefix(key, "STORAGE_CATEGORY_"); ok && strings.TrimSpace(value) != "" {
+			settings.Categories[strings.ToLower(category)] = strings.ToLower(strings.TrimSpace(value))
+		}
+	}
+	return settings
+}
+
+// storageProfile builds a profile, deriving the R2 endpoint from an account ID
+func storageProfile(name, endpoint, r2AccountID, region, accessKeyID, secretAccessKey, bucket, publicURL string, pathStyle bool) StorageProfile {
+	endpoint = strings.TrimRight(strings.TrimSpace(endpoint), "/")
+	if endpoint == "" && r2AccountID != "" {
+		endpoint = "https://" + strings.TrimSpace(r2AccountID) + ".r2.cloudflarestorage.com"
+	}
+	region = strings.TrimSpace(region)
+	if region == "" {
+		region = "auto"
+	}
+	return StorageProfile{
+		Name:            name,
+		Endpoint:        endpoint,
+		Region:          region,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		Bucket:          strings.TrimSpace(bucket),
+		PublicURL:       strings.TrimRight(strings.TrimSpace(publicURL), "/"),
+		PathStyle:       pathStyle,
+	}
+}
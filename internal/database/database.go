@@ -2,34 +2,76 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
+	"maukemana-backend/internal/config"
+
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	"github.com/uptrace/opentelemetry-go-extra/otelsql"
 	"github.com/uptrace/opentelemetry-go-extra/otelsqlx"
+	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 // DB represents the PostgreSQL database connection
 type DB struct {
 	*sqlx.DB
+
+	pool     config.DatabasePoolSettings
+	queryLog *queryLog // Set by LogQueries
+	replica  *replica  // Set by ConnectReplica
+}
+
+// Option configures a connection made by New
+type Option func(*config.DatabasePoolSettings)
+
+// WithPool sizes the connection pool and sets the per-connection statement
+// timeout. Without it the pool keeps 25 open and 5 idle connections for up to
+// 5 minutes and statements are not bounded, which suits the batch commands.
+func WithPool(pool config.DatabasePoolSettings) Option {
+	return func(p *config.DatabasePoolSettings) { *p = pool }
 }
 
 // New creates a new PostgreSQL database connection
-func New(databaseURL string) (*DB, error) {
+func New(databaseURL string, opts ...Option) (*DB, error) {
+	pool := config.DatabasePoolSettings{MaxOpenConns: 25, MaxIdleConns: 5, ConnMaxLifetime: 5 * time.Minute}
+	for _, opt := range opts {
+		opt(&pool)
+	}
+
+	db, err := connect(databaseURL, pool)
+	if err != nil {
+		return nil, err
+	}
+	return &DB{DB: db, pool: pool}, nil
+}
+
+// connect opens a pool sized by pool and checks that the server answers.
+// attrs are added to the pool's traces and metrics.
+func connect(databaseURL string, pool config.DatabasePoolSettings, attrs ...attribute.KeyValue) (*sqlx.DB, error) {
+	attrs = append(attrs, semconv.DBSystemPostgreSQL)
+	if pool.StatementTimeout > 0 {
+		databaseURL = withRuntimeParam(databaseURL, "statement_timeout", strconv.FormatInt(pool.StatementTimeout.Milliseconds(), 10))
+	}
+
 	db, err := otelsqlx.Connect("postgres", databaseURL,
-		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithAttributes(attrs...),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
 	// Configure connection pool
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
 
 	// Ping the database to verify connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -39,7 +81,48 @@ func New(databaseURL string) (*DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &DB{DB: db}, nil
+	// Export connection pool stats (open, idle, in-use, wait count/duration)
+	otelsql.ReportDBStatsMetrics(db.DB, otelsql.WithAttributes(attrs...))
+
+	return db, nil
+}
+
+// withRuntimeParam adds a server run-time parameter, which lib/pq sends at
+// connection startup, to a URL or key=value connection string unless it is
+// already set there
+func withRuntimeParam(databaseURL, name, value string) string {
+	if u, err := url.Parse(databaseURL); err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql") {
+		q := u.Query()
+		if q.Get(name) == "" {
+			q.Set(name, value)
+			u.RawQuery = q.Encode()
+		}
+		return u.String()
+	}
+	if strings.Contains(databaseURL, name+"=") {
+		return databaseURL
+	}
+	return strings.TrimSpace(databaseURL + " " + name + "=" + value)
+}
+
+// PoolStats reports the connection pool usage next to its configured limits
+type PoolStats struct {
+	MaxOpenConns      int     `json:"max_open_connections"`
+	MaxIdleConns      int     `json:"max_idle_connections"`
+	ConnMaxLifetime   string  `json:"conn_max_lifetime"`
+	ConnMaxIdleTime   string  `json:"conn_max_idle_time"`
+	StatementTimeout  string  `json:"statement_timeout"`
+	Open              int     `json:"open_connections"`
+	InUse             int     `json:"in_use"`
+	Idle              int     `json:"idle"`
+	WaitCount         int64   `json:"wait_count"`
+	WaitSeconds       float64 `json:"wait_seconds"`
+	MaxIdleClosed     int64   `json:"max_idle_closed"`
+	MaxIdleTimeClosed int64   `json:"max_idle_time_closed"`
+	MaxLifetimeClosed int64   `json:"max_lifetime_closed"`
+
+	// Replica is the read replica's pool, when one is connected
+	Replica *PoolStats `json:"replica,omitempty"`
 }
 
 // Health checks the database connection health
@@ -47,13 +130,36 @@ func (db *DB) Health(ctx context.Context) error {
 	return db.PingContext(ctx)
 }
 
-// BeginTx starts a new transaction
-func (db *DB) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
-	return db.BeginTxx(ctx, nil)
-}
-
 // RefreshMaterializedView refreshes the POI materialized view
 func (db *DB) RefreshMaterializedView(ctx context.Context) error {
 	_, err := db.ExecContext(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY mv_pois_with_hero")
 	return err
 }
+
+// PoolStats returns the current connection pool statistics
+func (db *DB) PoolStats() PoolStats {
+	stats := db.poolStats(db.Stats())
+	if db.replica != nil {
+		replica := db.poolStats(db.replica.db.Stats())
+		stats.Replica = &replica
+	}
+	return stats
+}
+
+func (db *DB) poolStats(st sql.DBStats) PoolStats {
+	return PoolStats{
+		MaxOpenConns:      st.MaxOpenConnections,
+		MaxIdleConns:      db.pool.MaxIdleConns,
+		ConnMaxLifetime:   db.pool.ConnMaxLifetime.String(),
+		ConnMaxIdleTime:   db.pool.ConnMaxIdleTime.String(),
+		StatementTimeout:  db.pool.StatementTimeout.String(),
+		Open:              st.OpenConnections,
+		InUse:             st.InUse,
+		Idle:              st.Idle,
+		WaitCount:         st.WaitCount,
+		WaitSeconds:       st.WaitDuration.Seconds(),
+		MaxIdleClosed:     st.MaxIdleClosed,
+		MaxIdleTimeClosed: st.MaxIdleTimeClosed,
+		MaxLifetimeClosed: st.MaxLifetimeClosed,
+	}
+}
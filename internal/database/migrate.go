@@ -0,0 +1,31 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+
+	"maukemana-backend/migrations"
+)
+
+// Migrate applies the pending embedded migrations. A Postgres advisory lock is
+// held while migrating so instances starting together apply them only once.
+func (db *DB) Migrate(ctx context.Context) ([]*goose.MigrationResult, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("create migration lock: %w", err)
+	}
+	provider, err := goose.NewProvider(goose.DialectPostgres, db.DB.DB, migrations.FS,
+		goose.WithSessionLocker(locker),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create migration provider: %w", err)
+	}
+	results, err := provider.Up(ctx)
+	if err != nil {
+		return results, fmt.Errorf("apply migrations: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"maukemana-backend/internal/logger"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LogQueries logs every statement run through Conn at debug level, and those
+// taking at least slowThreshold as warnings (0 disables the warnings)
+func (db *DB) LogQueries(slowThreshold time.Duration) {
+	db.queryLog = &queryLog{slow: slowThreshold}
+}
+
+type queryLog struct {
+	slow time.Duration
+}
+
+// loggedQuerier records the statement name, duration and row count of each query
+type loggedQuerier struct {
+	Querier
+	log *queryLog
+}
+
+func (q *loggedQuerier) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	start := time.Now()
+	res, err := q.Querier.ExecContext(ctx, query, args...)
+	rows := int64(-1)
+	if err == nil {
+		if n, nerr := res.RowsAffected(); nerr == nil {
+			rows = n
+		}
+	}
+	q.log.record(ctx, start, query, rows, err)
+	return res, err
+}
+
+func (q *loggedQuerier) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	start := time.Now()
+	err := q.Querier.GetContext(ctx, dest, query, args...)
+	rows := int64(0)
+	if err == nil {
+		rows = 1
+	}
+	q.log.record(ctx, start, query, rows, err)
+	return err
+}
+
+func (q *loggedQuerier) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	start := time.Now()
+	err := q.Querier.SelectContext(ctx, dest, query, args...)
+	rows := int64(-1)
+	if v := reflect.ValueOf(dest); err == nil && v.Kind() == reflect.Pointer && v.Elem().Kind() == reflect.Slice {
+		rows = int64(v.Elem().Len())
+	}
+	q.log.record(ctx, start, query, rows, err)
+	return err
+}
+
+// Rows are streamed by the callers of the query methods below, so only the time
+// to the first row is measured and no row count is logged
+
+func (q *loggedQuerier) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := q.Querier.QueryContext(ctx, query, args...)
+	q.log.record(ctx, start, query, -1, err)
+	return rows, err
+}
+
+func (q *loggedQuerier) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	start := time.Now()
+	rows, err := q.Querier.QueryxContext(ctx, query, args...)
+	q.log.record(ctx, start, query, -1, err)
+	return rows, err
+}
+
+func (q *loggedQuerier) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	start := time.Now()
+	row := q.Querier.QueryRowxContext(ctx, query, args...)
+	q.log.record(ctx, start, query, -1, row.Err())
+	return row
+}
+
+func (q *loggedQuerier) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	start := time.Now()
+	row := q.Querier.QueryRowContext(ctx, query, args...)
+	q.log.record(ctx, start, query, -1, row.Err())
+	return row
+}
+
+// record logs a finished query. rows is -1 when the row count is unknown.
+func (l *queryLog) record(ctx context.Context, start time.Time, query string, rows int64, err error) {
+	elapsed := time.Since(start)
+	slow := l.slow > 0 && elapsed >= l.slow
+	if !slow && !slog.Default().Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	attrs := []any{
+		slog.String("statement", statementName()),
+		slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+	}
+	if rows >= 0 {
+		attrs = append(attrs, slog.Int64("rows", rows))
+	}
+	if id := logger.RequestID(ctx); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		attrs = append(attrs, slog.String("trace_id", sc.TraceID().String()))
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	if slow {
+		attrs = append(attrs, slog.String("query", strings.Join(strings.Fields(query), " ")))
+		slog.WarnContext(ctx, "slow query", attrs...)
+		return
+	}
+	slog.DebugContext(ctx, "query", attrs...)
+}
+
+// statementName names a query after the function that issued it, e.g.
+// repositories.(*POIRepository).SearchPOIs, skipping this package and sqlx
+func statementName() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		fn := frame.Function
+		if !strings.HasPrefix(fn, "maukemana-backend/internal/database.") && !strings.HasPrefix(fn, "github.com/jmoiron/sqlx.") {
+			if i := strings.LastIndex(fn, "/"); i >= 0 {
+				fn = fn[i+1:]
+			}
+			return fn
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"maukemana-backend/internal/breaker"
+	"maukemana-backend/internal/config"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// replica is a read-only copy of the database. Its breaker opens after
+// repeated connection failures, sending reads to the primary until a trial
+// query succeeds again.
+type replica struct {
+	db      *sqlx.DB
+	breaker *breaker.Breaker
+}
+
+// ConnectReplica opens a pool to a streaming replica, sized like the primary
+// pool, for the reads made through ReadConn
+func (db *DB) ConnectReplica(replicaURL string, cfg config.BreakerSettings) error {
+	conn, err := connect(replicaURL, db.pool, attribute.String("db.role", "replica"))
+	if err != nil {
+		return fmt.Errorf("connect replica: %w", err)
+	}
+	db.replica = &replica{
+		db: conn,
+		breaker: breaker.New("db_replica", breaker.Settings{
+			MaxFailures: cfg.MaxFailures,
+			OpenFor:     cfg.OpenFor,
+			IsFailure:   isUnavailable,
+		}),
+	}
+	return nil
+}
+
+// Close closes the primary pool and the replica pool, if any
+func (db *DB) Close() error {
+	if db.replica != nil {
+		db.replica.db.Close()
+	}
+	return db.DB.Close()
+}
+
+type primaryKey struct{}
+
+// ReadFromPrimary marks ctx so that ReadConn uses the primary. Reads that must
+// see a write made moments before, which the replica may not have replayed
+// yet, run with it.
+func ReadFromPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// ReadConn returns a connection for read-only queries: the replica when one is
+// connected, falling back to the primary when it cannot be reached. Inside a
+// transaction, or on a context marked with ReadFromPrimary, it is the same as Conn.
+func (db *DB) ReadConn(ctx context.Context) Querier {
+	if db.replica == nil || txFromContext(ctx) != nil || ctx.Value(primaryKey{}) != nil {
+		return db.Conn(ctx)
+	}
+	var q Querier = &replicaQuerier{Querier: db.replica.db, primary: db.DB, breaker: db.replica.breaker}
+	if db.queryLog != nil {
+		return &loggedQuerier{Querier: q, log: db.queryLog}
+	}
+	return q
+}
+
+// isUnavailable reports whether err means the server could not run the query
+// at all, as opposed to the query itself failing. A replica refusing a write
+// (read_only_sql_transaction) is included so the statement is retried on the primary.
+func isUnavailable(err error) bool {
+	if errors.Is(err, breaker.ErrOpen) || errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		code := string(pqErr.Code)
+		// 08: connection exception, 53: insufficient resources, 57P: operator
+		// intervention (shutdown, cannot connect now), 25006: read-only transaction
+		return strings.HasPrefix(code, "08") || strings.HasPrefix(code, "53") ||
+			strings.HasPrefix(code, "57P") || code == "25006"
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// replicaQuerier runs reads on the replica and repeats them on the primary
+// when the replica is unavailable. Writes always go to the primary.
+type replicaQuerier struct {
+	Querier // The replica pool
+	primary Querier
+	breaker *breaker.Breaker
+}
+
+// try runs fn against the replica, then against the primary if the replica
+// could not answer
+func (q *replicaQuerier) try(ctx context.Context, fn func(Querier) error) error {
+	err := q.breaker.Do(ctx, func() error { return fn(q.Querier) })
+	if err == nil || !isUnavailable(err) || ctx.Err() != nil {
+		return err
+	}
+	return fn(q.primary)
+}
+
+func (q *replicaQuerier) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return q.primary.ExecContext(ctx, query, args...)
+}
+
+func (q *replicaQuerier) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return q.try(ctx, func(db Querier) error { return db.GetContext(ctx, dest, query, args...) })
+}
+
+func (q *replicaQuerier) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return q.try(ctx, func(db Querier) error { return db.SelectContext(ctx, dest, query, args...) })
+}
+
+func (q *replicaQuerier) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := q.try(ctx, func(db Querier) error {
+		var err error
+		rows, err = db.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+func (q *replicaQuerier) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	var rows *sqlx.Rows
+	err := q.try(ctx, func(db Querier) error {
+		var err error
+		rows, err = db.QueryxContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// A single-row query runs when it is issued, so a connection failure is
+// already visible in the row's Err
+
+func (q *replicaQuerier) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	var row *sqlx.Row
+	q.try(ctx, func(db Querier) error {
+		row = db.QueryRowxContext(ctx, query, args...)
+		return row.Err()
+	})
+	if row == nil {
+		row = q.primary.QueryRowxContext(ctx, query, args...)
+	}
+	return row
+}
+
+func (q *replicaQuerier) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	var row *sql.Row
+	q.try(ctx, func(db Querier) error {
+		row = db.QueryRowContext(ctx, query, args...)
+		return row.Err()
+	})
+	if row == nil {
+		row = q.primary.QueryRowContext(ctx, query, args...)
+	}
+	return row
+}
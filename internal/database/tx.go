@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Querier is the query surface shared by *sqlx.DB and *sqlx.Tx.
+// Repositories run their statements through Conn so they automatically
+// participate in a transaction started with WithTx.
+type Querier interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+// Tx wraps a transaction. A Tx obtained while another transaction is already
+// active on the context is nested: Commit and Rollback are left to the outer owner.
+type Tx struct {
+	*sqlx.Tx
+	nested bool
+}
+
+// Commit commits the transaction unless it is nested
+func (tx *Tx) Commit() error {
+	if tx.nested {
+		return nil
+	}
+	return tx.Tx.Commit()
+}
+
+// Rollback rolls back the transaction unless it is nested
+func (tx *Tx) Rollback() error {
+	if tx.nested {
+		return nil
+	}
+	return tx.Tx.Rollback()
+}
+
+// txFromContext returns the transaction carried by ctx, if any
+func txFromContext(ctx context.Context) *sqlx.Tx {
+	tx, _ := ctx.Value(txKey{}).(*sqlx.Tx)
+	return tx
+}
+
+// Conn returns the active transaction for ctx, or the pool when there is none
+func (db *DB) Conn(ctx context.Context) Querier {
+	var q Querier = db.DB
+	if tx := txFromContext(ctx); tx != nil {
+		q = tx
+	}
+	if db.queryLog != nil {
+		return &loggedQuerier{Querier: q, log: db.queryLog}
+	}
+	return q
+}
+
+// BeginTx starts a new transaction, or joins the one already active on ctx
+func (db *DB) BeginTx(ctx context.Context) (*Tx, error) {
+	if tx := txFromContext(ctx); tx != nil {
+		return &Tx{Tx: tx, nested: true}, nil
+	}
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx}, nil
+}
+
+// WithTx runs fn inside a transaction carried on the context passed to fn.
+// Repository calls made with that context share the transaction; it is committed
+// when fn returns nil and rolled back otherwise. Nested calls join the outer transaction.
+func (db *DB) WithTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if txFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// Package dataexport builds personal data exports in the background and
+// notifies users when their archive is ready to download.
+package dataexport
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Retention is how long a finished export can be downloaded
+	Retention = 7 * 24 * time.Hour
+	// claimLease is how long a worker may spend on one export before another reclaims it
+	claimLease = 10 * time.Minute
+)
+
+// Store is the export persistence the worker needs
+type Store interface {
+	ClaimPendingExport(ctx context.Context, lease time.Duration) (*models.DataExport, error)
+	BuildExport(ctx context.Context, userID uuid.UUID) ([]byte, error)
+	CompleteExport(ctx context.Context, exportID uuid.UUID, archive []byte, expiresAt time.Time) error
+	FailExport(ctx context.Context, exportID uuid.UUID, reason string) error
+	PurgeExpiredExports(ctx context.Context) (int64, error)
+}
+
+// Notifier tells users their export is ready
+type Notifier interface {
+	Create(ctx context.Context, userID uuid.UUID, notificationType, title string, body *string, data interface{}) error
+}
+
+// Worker polls for queued exports and builds them one at a time
+type Worker struct {
+	store    Store
+	notifier Notifier
+	interval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWorker creates a worker polling every interval
+func NewWorker(store Store, notifier Notifier, interval time.Duration) *Worker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Worker{store: store, notifier: notifier, interval: interval, ctx: ctx, cancel: cancel}
+}
+
+// Start begins polling in the background
+func (w *Worker) Start() {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-ticker.C:
+				w.drain()
+			}
+		}
+	}()
+}
+
+// Stop waits for the current export to finish and stops the worker
+func (w *Worker) Stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+// drain builds queued exports until none are left, then purges expired ones
+func (w *Worker) drain() {
+	for w.ctx.Err() == nil {
+		export, err := w.store.ClaimPendingExport(w.ctx, claimLease)
+		if err != nil {
+			slog.Error("claim data export failed", "error", err)
+			return
+		}
+		if export == nil {
+			break
+		}
+		w.process(export)
+	}
+
+	if n, err := w.store.PurgeExpiredExports(w.ctx); err != nil {
+		slog.Error("purge data exports failed", "error", err)
+	} else if n > 0 {
+		slog.Info("purged expired data exports", "count", n)
+	}
+}
+
+func (w *Worker) process(export *models.DataExport) {
+	ctx := w.ctx
+	archive, err := w.store.BuildExport(ctx, export.UserID)
+	if err != nil {
+		slog.Error("build data export failed", "export_id", export.ExportID, "error", err)
+		if err := w.store.FailExport(ctx, export.ExportID, "export could not be generated"); err != nil {
+			slog.Error("mark data export failed", "export_id", export.ExportID, "error", err)
+		}
+		return
+	}
+
+	expiresAt := time.Now().Add(Retention)
+	if err := w.store.CompleteExport(ctx, export.ExportID, archive, expiresAt); err != nil {
+		slog.Error("store data export failed", "export_id", export.ExportID, "error", err)
+		return
+	}
+
+	body := "Your data export is ready. Download it from GET /api/v1/me/export before it expires."
+	err = w.notifier.Create(ctx, export.UserID, models.NotificationDataExportReady, "Your data export is ready", &body, map[string]interface{}{
+		"export_id":  export.ExportID,
+		"expires_at": expiresAt,
+	})
+	if err != nil {
+		slog.Error("notify data export failed", "export_id", export.ExportID, "error", err)
+	}
+	slog.Info("data export ready", "export_id", export.ExportID, "size_bytes", len(archive))
+}
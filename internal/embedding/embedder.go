@@ -0,0 +1,33 @@
+// Package embedding turns POI text into vectors for semantic search and keeps
+// the stored embeddings in sync with approved POIs.
+package embedding
+
+import (
+	"context"
+	"fmt"
+
+	"maukemana-backend/internal/config"
+)
+
+// Dimensions is the vector size stored in poi_embeddings; it must match the migration
+const Dimensions = 1536
+
+// Embedder converts texts into vectors of length Dimensions
+type Embedder interface {
+	// Model identifies the embedding model; vectors from different models are not comparable
+	Model() string
+	// Embed returns one vector per input text, in order
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// NewFromConfig builds the configured embedder. It returns nil when no provider is set.
+func NewFromConfig(cfg config.EmbeddingSettings) (Embedder, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case "openai":
+		return NewOpenAIEmbedder(cfg)
+	default:
+		return nil, fmt.Errorf("unknown embedding provider %q", cfg.Provider)
+	}
+}
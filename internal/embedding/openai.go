@@ -0,0 +1,110 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"maukemana-backend/internal/config"
+)
+
+const defaultOpenAIURL = "https://api.openai.com/v1"
+
+// OpenAIEmbedder calls the OpenAI embeddings API or any compatible endpoint
+type OpenAIEmbedder struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	model      string
+}
+
+// NewOpenAIEmbedder creates an OpenAI embedder from settings
+func NewOpenAIEmbedder(cfg config.EmbeddingSettings) (*OpenAIEmbedder, error) {
+	if cfg.APIKey == "" {
+		return nil, errors.New("EMBEDDING_API_KEY or OPENAI_API_KEY is required for the openai embedding provider")
+	}
+	baseURL := cfg.URL
+	if baseURL == "" {
+		baseURL = defaultOpenAIURL
+	}
+	return &OpenAIEmbedder{
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		baseURL:    baseURL,
+		apiKey:     cfg.APIKey,
+		model:      cfg.Model,
+	}, nil
+}
+
+// Model returns the configured model name
+func (e *OpenAIEmbedder) Model() string {
+	return e.model
+}
+
+// Embed requests embeddings for a batch of texts
+func (e *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	reqBody := map[string]interface{}{
+		"model": e.model,
+		"input": texts,
+	}
+	// text-embedding-3 models can be shortened to the stored size
+	if strings.HasPrefix(e.model, "text-embedding-3") {
+		reqBody["dimensions"] = Dimensions
+	}
+	payload, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("encode embedding request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/embeddings", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("build embedding request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.apiKey)
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return nil, fmt.Errorf("embedding provider returned %d: %s", resp.StatusCode, msg)
+	}
+
+	var result struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode embedding response: %w", err)
+	}
+	if len(result.Data) != len(texts) {
+		return nil, fmt.Errorf("embedding provider returned %d vectors for %d inputs", len(result.Data), len(texts))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, d := range result.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("embedding provider returned out-of-range index %d", d.Index)
+		}
+		if len(d.Embedding) != Dimensions {
+			return nil, fmt.Errorf("model %s returned %d dimensions, expected %d", e.model, len(d.Embedding), Dimensions)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	return vectors, nil
+}
@@ -0,0 +1,152 @@
+package embedding
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"maukemana-backend/internal/events"
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// backfillBatchSize is the number of POIs embedded per provider request
+const backfillBatchSize = 100
+
+// DocumentSource loads the POI text that gets embedded
+type DocumentSource interface {
+	GetSearchDocument(ctx context.Context, poiID uuid.UUID) (*repositories.POISearchDocument, error)
+	ListSearchDocuments(ctx context.Context, after uuid.UUID, limit int) ([]repositories.POISearchDocument, error)
+}
+
+// Store persists embeddings
+type Store interface {
+	GetContentHashes(ctx context.Context, model string, poiIDs []uuid.UUID) (map[uuid.UUID]string, error)
+	Upsert(ctx context.Context, poiID uuid.UUID, model, contentHash string, vector []float32) error
+	Delete(ctx context.Context, poiID uuid.UUID) error
+}
+
+// Syncer re-embeds POIs whose text changed
+type Syncer struct {
+	embedder Embedder
+	source   DocumentSource
+	store    Store
+}
+
+// NewSyncer creates a syncer
+func NewSyncer(embedder Embedder, source DocumentSource, store Store) *Syncer {
+	return &Syncer{embedder: embedder, source: source, store: store}
+}
+
+// Register subscribes the syncer to the POI lifecycle events on bus
+func (s *Syncer) Register(bus *events.Bus) {
+	bus.Subscribe(models.EventPOIApproved, s.handle)
+	bus.Subscribe(models.EventPOIUpdated, s.handle)
+	bus.Subscribe(models.EventPOIDeleted, s.handle)
+}
+
+func (s *Syncer) handle(ctx context.Context, event models.OutboxEvent) error {
+	doc, err := s.source.GetSearchDocument(ctx, event.AggregateID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return s.store.Delete(ctx, event.AggregateID)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = s.embed(ctx, []repositories.POISearchDocument{*doc})
+	return err
+}
+
+// Backfill embeds every approved POI whose text or model changed since it was
+// last embedded, and returns how many were (re-)embedded
+func (s *Syncer) Backfill(ctx context.Context) (int, error) {
+	total := 0
+	after := uuid.Nil
+	for {
+		docs, err := s.source.ListSearchDocuments(ctx, after, backfillBatchSize)
+		if err != nil {
+			return total, err
+		}
+		if len(docs) == 0 {
+			return total, nil
+		}
+		n, err := s.embed(ctx, docs)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		after = docs[len(docs)-1].ID
+		slog.Info("embedded batch", "embedded", n, "scanned", len(docs), "total", total)
+	}
+}
+
+// embed stores embeddings for the documents whose content hash changed
+func (s *Syncer) embed(ctx context.Context, docs []repositories.POISearchDocument) (int, error) {
+	model := s.embedder.Model()
+	ids := make([]uuid.UUID, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	existing, err := s.store.GetContentHashes(ctx, model, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	var pending []repositories.POISearchDocument
+	var texts, hashes []string
+	for _, doc := range docs {
+		text := DocumentText(doc)
+		hash := contentHash(text)
+		if existing[doc.ID] == hash {
+			continue
+		}
+		pending = append(pending, doc)
+		texts = append(texts, text)
+		hashes = append(hashes, hash)
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	vectors, err := s.embedder.Embed(ctx, texts)
+	if err != nil {
+		return 0, fmt.Errorf("embed documents: %w", err)
+	}
+	for i, doc := range pending {
+		if err := s.store.Upsert(ctx, doc.ID, model, hashes[i], vectors[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(pending), nil
+}
+
+// DocumentText is the text embedded for a POI: what it is, how it feels, and
+// how its owner describes it
+func DocumentText(doc repositories.POISearchDocument) string {
+	parts := []string{doc.Name}
+	if len(doc.CategoryNames) > 0 {
+		parts = append(parts, "Categories: "+strings.Join(doc.CategoryNames, ", "))
+	}
+	if doc.Cuisine != nil && *doc.Cuisine != "" {
+		parts = append(parts, "Cuisine: "+*doc.Cuisine)
+	}
+	if len(doc.Vibes) > 0 {
+		parts = append(parts, "Vibes: "+strings.Join(doc.Vibes, ", "))
+	}
+	if doc.Description != nil && *doc.Description != "" {
+		parts = append(parts, *doc.Description)
+	}
+	return strings.Join(parts, "\n")
+}
+
+func contentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
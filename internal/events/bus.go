@@ -0,0 +1,55 @@
+// Package events relays POI lifecycle events from the transactional outbox to
+// in-process subscribers and to external webhook subscriptions.
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"maukemana-backend/internal/models"
+)
+
+// Handler reacts to a published event
+type Handler func(ctx context.Context, event models.OutboxEvent) error
+
+// Bus fans published events out to in-process subscribers. Delivery is best
+// effort: handler errors are logged and the event is not redelivered. Consumers
+// that need guarantees should register a webhook instead.
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[string][]Handler // keyed by event type, "" receives every event
+}
+
+// NewBus creates an empty event bus
+func NewBus() *Bus {
+	return &Bus{subs: make(map[string][]Handler)}
+}
+
+// Subscribe registers h for eventType, or for every event when eventType is ""
+func (b *Bus) Subscribe(eventType string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[eventType] = append(b.subs[eventType], h)
+}
+
+// Publish runs the subscribers of the event in registration order
+func (b *Bus) Publish(ctx context.Context, event models.OutboxEvent) {
+	b.mu.RLock()
+	handlers := append(append([]Handler(nil), b.subs[event.EventType]...), b.subs[""]...)
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		if err := h(ctx, event); err != nil {
+			slog.Error("event handler failed",
+				"event_id", event.EventID, "event_type", event.EventType, "error", err)
+		}
+	}
+}
+
+// LogEvents is a Handler that logs every event at debug level
+func LogEvents(ctx context.Context, event models.OutboxEvent) error {
+	slog.DebugContext(ctx, "event published",
+		"event_id", event.EventID, "event_type", event.EventType, "aggregate_id", event.AggregateID)
+	return nil
+}
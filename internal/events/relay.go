@@ -0,0 +1,78 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"maukemana-backend/internal/models"
+)
+
+// relayBatchSize bounds how many outbox rows one poll publishes
+const relayBatchSize = 100
+
+// OutboxStore is the outbox access the relay needs
+type OutboxStore interface {
+	PublishPending(ctx context.Context, limit int) ([]models.OutboxEvent, error)
+}
+
+// Relay polls the outbox, queues webhook deliveries for new events and
+// publishes them on the bus
+type Relay struct {
+	store    OutboxStore
+	bus      *Bus
+	interval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRelay creates a relay polling every interval
+func NewRelay(store OutboxStore, bus *Bus, interval time.Duration) *Relay {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Relay{store: store, bus: bus, interval: interval, ctx: ctx, cancel: cancel}
+}
+
+// Start begins polling in the background
+func (r *Relay) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-ticker.C:
+				r.drain()
+			}
+		}
+	}()
+}
+
+// Stop waits for the current poll to finish and stops the relay
+func (r *Relay) Stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// drain publishes batches until the outbox is empty
+func (r *Relay) drain() {
+	for r.ctx.Err() == nil {
+		events, err := r.store.PublishPending(r.ctx, relayBatchSize)
+		if err != nil {
+			slog.Error("outbox relay failed", "error", err)
+			return
+		}
+		for _, event := range events {
+			r.bus.Publish(r.ctx, event)
+		}
+		if len(events) < relayBatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Webhook request headers
+const (
+	HeaderEvent     = "X-Maukemana-Event"
+	HeaderDelivery  = "X-Maukemana-Delivery"
+	HeaderTimestamp = "X-Maukemana-Timestamp"
+	HeaderSignature = "X-Maukemana-Signature"
+)
+
+const (
+	deliveryBatchSize   = 20
+	deliveryTimeout     = 10 * time.Second
+	deliveryLease       = time.Minute // Must exceed deliveryTimeout
+	maxDeliveryAttempts = 8
+	baseRetryDelay      = 30 * time.Second
+	maxRetryDelay       = 6 * time.Hour
+)
+
+// DeliveryStore is the delivery log access the dispatcher needs
+type DeliveryStore interface {
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.DueDelivery, error)
+	MarkDelivered(ctx context.Context, deliveryID uuid.UUID, statusCode int) error
+	MarkAttemptFailed(ctx context.Context, deliveryID uuid.UUID, statusCode *int, errMsg string, retryAt *time.Time) error
+}
+
+// Dispatcher sends queued webhook deliveries, retrying failures with
+// exponential backoff until maxDeliveryAttempts
+type Dispatcher struct {
+	store    DeliveryStore
+	client   *http.Client
+	interval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher polling for due deliveries every interval
+func NewDispatcher(store DeliveryStore, interval time.Duration) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		store:    store,
+		client:   &http.Client{Timeout: deliveryTimeout},
+		interval: interval,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start begins dispatching in the background
+func (d *Dispatcher) Start() {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-d.ctx.Done():
+				return
+			case <-ticker.C:
+				d.dispatchDue()
+			}
+		}
+	}()
+}
+
+// Stop waits for in-flight deliveries and stops the dispatcher
+func (d *Dispatcher) Stop() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) dispatchDue() {
+	due, err := d.store.ClaimDueDeliveries(d.ctx, deliveryBatchSize, deliveryLease)
+	if err != nil {
+		slog.Error("claim webhook deliveries failed", "error", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range due {
+		wg.Add(1)
+		go func(delivery models.DueDelivery) {
+			defer wg.Done()
+			d.deliver(delivery)
+		}(delivery)
+	}
+	wg.Wait()
+}
+
+// deliver sends one delivery and records the outcome. Outcomes are recorded
+// with a fresh context so a shutdown mid-request still releases the lease.
+func (d *Dispatcher) deliver(delivery models.DueDelivery) {
+	statusCode, err := d.send(d.ctx, delivery)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	l := slog.With("delivery_id", delivery.DeliveryID, "event_id", delivery.EventID, "url", delivery.URL)
+	if err == nil {
+		if err := d.store.MarkDelivered(ctx, delivery.DeliveryID, statusCode); err != nil {
+			l.Error("record webhook delivery failed", "error", err)
+		}
+		return
+	}
+
+	var code *int
+	if statusCode != 0 {
+		code = &statusCode
+	}
+	attempt := delivery.Attempts + 1
+	var retryAt *time.Time
+	if attempt < maxDeliveryAttempts {
+		at := time.Now().Add(retryDelay(attempt))
+		retryAt = &at
+	}
+	l.Warn("webhook delivery failed", "attempt", attempt, "status_code", statusCode, "error", err, "will_retry", retryAt != nil)
+
+	if err := d.store.MarkAttemptFailed(ctx, delivery.DeliveryID, code, err.Error(), retryAt); err != nil {
+		l.Error("record webhook delivery failure failed", "error", err)
+	}
+}
+
+// webhookBody is the JSON document POSTed to subscribers
+type webhookBody struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// send POSTs the signed payload; any 2xx response counts as delivered
+func (d *Dispatcher) send(ctx context.Context, delivery models.DueDelivery) (int, error) {
+	body, err := json.Marshal(webhookBody{
+		ID:        delivery.EventID,
+		Type:      delivery.EventType,
+		CreatedAt: delivery.CreatedAt,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("marshal webhook body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("build webhook request: %w", err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Maukemana-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.DeliveryID.String())
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, "sha256="+Sign(delivery.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign computes the hex HMAC-SHA256 of "timestamp.body" with the subscription
+// secret. Receivers recompute it to authenticate the payload and should reject
+// stale timestamps to prevent replays.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryDelay doubles from baseRetryDelay per attempt, capped at maxRetryDelay
+func retryDelay(attempt int) time.Duration {
+	delay := baseRetryDelay << (attempt - 1)
+	if delay <= 0 || delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
@@ -0,0 +1,116 @@
+// Package geohash encodes coordinates as geohash cells and finds the cells
+// around one, matching PostGIS ST_GeoHash.
+package geohash
+
+import (
+	"math"
+	"strings"
+)
+
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// MaxPrecision is the longest cell stored per POI
+const MaxPrecision = 9
+
+// Valid reports whether cell is a geohash of at most MaxPrecision characters
+func Valid(cell string) bool {
+	if cell == "" || len(cell) > MaxPrecision {
+		return false
+	}
+	for _, r := range cell {
+		if !strings.ContainsRune(base32, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Encode returns the cell of the given precision containing a point
+func Encode(lat, lng float64, precision int) string {
+	minLat, maxLat := -90.0, 90.0
+	minLng, maxLng := -180.0, 180.0
+	var sb strings.Builder
+	bit, ch, even := 0, 0, true
+	for sb.Len() < precision {
+		if even {
+			mid := (minLng + maxLng) / 2
+			if lng >= mid {
+				ch |= 1 << (4 - bit)
+				minLng = mid
+			} else {
+				maxLng = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if lat >= mid {
+				ch |= 1 << (4 - bit)
+				minLat = mid
+			} else {
+				maxLat = mid
+			}
+		}
+		even = !even
+		if bit < 4 {
+			bit++
+		} else {
+			sb.WriteByte(base32[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return sb.String()
+}
+
+// Bounds returns the south-west and north-east corners of a valid cell
+func Bounds(cell string) (minLat, minLng, maxLat, maxLng float64) {
+	minLat, maxLat = -90.0, 90.0
+	minLng, maxLng = -180.0, 180.0
+	even := true
+	for _, r := range cell {
+		idx := strings.IndexRune(base32, r)
+		for bit := 4; bit >= 0; bit-- {
+			on := idx&(1<<bit) != 0
+			if even {
+				mid := (minLng + maxLng) / 2
+				if on {
+					minLng = mid
+				} else {
+					maxLng = mid
+				}
+			} else {
+				mid := (minLat + maxLat) / 2
+				if on {
+					minLat = mid
+				} else {
+					maxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return minLat, minLng, maxLat, maxLng
+}
+
+// Neighbors returns the up to 8 cells of the same precision around a valid
+// cell. Longitude wraps around the antimeridian; there are no cells beyond
+// the poles.
+func Neighbors(cell string) []string {
+	minLat, minLng, maxLat, maxLng := Bounds(cell)
+	height, width := maxLat-minLat, maxLng-minLng
+	lat, lng := (minLat+maxLat)/2, (minLng+maxLng)/2
+
+	cells := make([]string, 0, 8)
+	for _, dLat := range []float64{-1, 0, 1} {
+		for _, dLng := range []float64{-1, 0, 1} {
+			if dLat == 0 && dLng == 0 {
+				continue
+			}
+			nLat := lat + dLat*height
+			if nLat < -90 || nLat > 90 {
+				continue
+			}
+			nLng := math.Mod(lng+dLng*width+540, 360) - 180
+			cells = append(cells, Encode(nLat, nLng, len(cell)))
+		}
+	}
+	return cells
+}
@@ -0,0 +1,216 @@
+// Package geoip estimates a coarse location from a client IP through a
+// geolocation web service (MaxMind GeoLite2/GeoIP2 or ipinfo), for searches
+// sent without coordinates.
+package geoip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"maukemana-backend/internal/config"
+)
+
+// requestTimeout bounds every call to the geolocation provider
+const requestTimeout = 2 * time.Second
+
+// Location is an estimated position, typically the centre of a city
+type Location struct {
+	Lat float64
+	Lng float64
+}
+
+// Provider looks up a public IP address. ok is false when the provider has no
+// location for it.
+type Provider interface {
+	Lookup(ctx context.Context, ip netip.Addr) (loc Location, ok bool, err error)
+}
+
+// NewFromConfig builds the configured provider wrapped in a cache. It returns
+// nil when no provider is set.
+func NewFromConfig(cfg config.GeoIPSettings) (*Locator, error) {
+	httpClient := &http.Client{Timeout: requestTimeout}
+
+	var p Provider
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case "maxmind":
+		if cfg.AccountID == "" || cfg.LicenseKey == "" {
+			return nil, fmt.Errorf("GEOIP_ACCOUNT_ID and GEOIP_LICENSE_KEY are required for provider %q", cfg.Provider)
+		}
+		p = &maxmindProvider{http: httpClient, baseURL: cfg.URL, accountID: cfg.AccountID, licenseKey: cfg.LicenseKey}
+	case "ipinfo":
+		if cfg.LicenseKey == "" {
+			return nil, fmt.Errorf("GEOIP_LICENSE_KEY is required for provider %q", cfg.Provider)
+		}
+		p = &ipinfoProvider{http: httpClient, token: cfg.LicenseKey}
+	default:
+		return nil, fmt.Errorf("unknown geoip provider %q", cfg.Provider)
+	}
+	return NewLocator(p, cfg.CacheTTL, cfg.CacheSize), nil
+}
+
+type cacheEntry struct {
+	loc       Location
+	ok        bool
+	expiresAt time.Time
+}
+
+// Locator caches provider answers per IP, including misses, and never asks
+// about private, loopback or otherwise non-routable addresses
+type Locator struct {
+	provider Provider
+	ttl      time.Duration
+	maxSize  int
+
+	mu      sync.Mutex
+	entries map[netip.Addr]cacheEntry
+}
+
+// NewLocator wraps a provider with a cache holding up to maxSize addresses
+func NewLocator(provider Provider, ttl time.Duration, maxSize int) *Locator {
+	return &Locator{provider: provider, ttl: ttl, maxSize: maxSize, entries: make(map[netip.Addr]cacheEntry)}
+}
+
+// Locate estimates where ip is. ok is false for unparseable or non-public
+// addresses and for addresses the provider cannot place.
+func (l *Locator) Locate(ctx context.Context, ip string) (Location, bool, error) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return Location{}, false, nil
+	}
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return Location{}, false, nil
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	e, hit := l.entries[addr]
+	l.mu.Unlock()
+	if hit && now.Before(e.expiresAt) {
+		return e.loc, e.ok, nil
+	}
+
+	loc, ok, err := l.provider.Lookup(ctx, addr)
+	if err != nil {
+		return Location{}, false, err
+	}
+
+	l.mu.Lock()
+	l.makeRoom(now)
+	l.entries[addr] = cacheEntry{loc: loc, ok: ok, expiresAt: now.Add(l.ttl)}
+	l.mu.Unlock()
+	return loc, ok, nil
+}
+
+// makeRoom evicts expired entries, then arbitrary ones, until one more fits.
+// Callers hold mu.
+func (l *Locator) makeRoom(now time.Time) {
+	if len(l.entries) < l.maxSize {
+		return
+	}
+	for k, e := range l.entries {
+		if !now.Before(e.expiresAt) {
+			delete(l.entries, k)
+		}
+	}
+	for k := range l.entries {
+		if len(l.entries) < l.maxSize {
+			return
+		}
+		delete(l.entries, k)
+	}
+}
+
+// maxmindProvider uses the MaxMind GeoIP2/GeoLite2 City web service. The
+// free GeoLite2 service lives at geolite.info, the paid one at geoip.maxmind.com.
+type maxmindProvider struct {
+	http       *http.Client
+	baseURL    string
+	accountID  string
+	licenseKey string
+}
+
+func (m *maxmindProvider) Lookup(ctx context.Context, ip netip.Addr) (Location, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.baseURL+"/geoip/v2.1/city/"+ip.String(), nil)
+	if err != nil {
+		return Location{}, false, fmt.Errorf("build maxmind request: %w", err)
+	}
+	req.SetBasicAuth(m.accountID, m.licenseKey)
+
+	var resp struct {
+		Location struct {
+			Latitude  *float64 `json:"latitude"`
+			Longitude *float64 `json:"longitude"`
+		} `json:"location"`
+	}
+	found, err := doJSON(m.http, req, "maxmind", &resp)
+	if err != nil || !found || resp.Location.Latitude == nil || resp.Location.Longitude == nil {
+		return Location{}, false, err
+	}
+	return Location{Lat: *resp.Location.Latitude, Lng: *resp.Location.Longitude}, true, nil
+}
+
+// ipinfoProvider uses the ipinfo.io API, which returns "lat,lng" in loc
+type ipinfoProvider struct {
+	http  *http.Client
+	token string
+}
+
+func (p *ipinfoProvider) Lookup(ctx context.Context, ip netip.Addr) (Location, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://ipinfo.io/"+ip.String()+"/json", nil)
+	if err != nil {
+		return Location{}, false, fmt.Errorf("build ipinfo request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+
+	var resp struct {
+		Loc string `json:"loc"`
+	}
+	found, err := doJSON(p.http, req, "ipinfo", &resp)
+	if err != nil || !found {
+		return Location{}, false, err
+	}
+	latStr, lngStr, ok := strings.Cut(resp.Loc, ",")
+	if !ok {
+		return Location{}, false, nil
+	}
+	lat, err1 := strconv.ParseFloat(latStr, 64)
+	lng, err2 := strconv.ParseFloat(lngStr, 64)
+	if err1 != nil || err2 != nil {
+		return Location{}, false, nil
+	}
+	return Location{Lat: lat, Lng: lng}, true, nil
+}
+
+// doJSON sends req and decodes a 2xx response into out. A 404 means the
+// address is unknown and is reported as not found rather than an error.
+func doJSON(client *http.Client, req *http.Request, provider string, out interface{}) (bool, error) {
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("%s request: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return false, fmt.Errorf("%s returned %d: %s", provider, resp.StatusCode, body)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("decode %s response: %w", provider, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,58 @@
+package gql
+
+import (
+	"context"
+	"errors"
+
+	"maukemana-backend/internal/services"
+)
+
+var (
+	errUnauthenticated = errors.New("authentication required")
+	errForbidden       = errors.New("not permitted")
+)
+
+type viewerKey struct{}
+type localeKey struct{}
+
+// WithViewer attaches the signed-in user to the context
+func WithViewer(ctx context.Context, viewer services.Actor) context.Context {
+	return context.WithValue(ctx, viewerKey{}, viewer)
+}
+
+// ViewerFrom returns the signed-in user, if any
+func ViewerFrom(ctx context.Context) (services.Actor, bool) {
+	viewer, ok := ctx.Value(viewerKey{}).(services.Actor)
+	return viewer, ok
+}
+
+func withLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// localeFrom returns the negotiated translation locale, "" for the default locale
+func localeFrom(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// guard enforces the access directive of a field: @auth lets any signed-in
+// viewer through, @hasPermission only those allowed by hasPermission.
+// graphql-go has no directive hooks, so the resolver of every annotated field
+// calls guard before resolving, passing nil for @auth.
+func guard(ctx context.Context, allowed func(services.Actor) bool) (services.Actor, error) {
+	viewer, ok := ViewerFrom(ctx)
+	if !ok {
+		return services.Actor{}, errUnauthenticated
+	}
+	if allowed != nil && !allowed(viewer) {
+		return viewer, errForbidden
+	}
+	return viewer, nil
+}
+
+// hasPermission is the @hasPermission(permission:) rule: the viewer's role
+// must grant the permission
+func hasPermission(perm services.Permission) func(services.Actor) bool {
+	return func(viewer services.Actor) bool { return viewer.Can(perm) }
+}
@@ -0,0 +1,89 @@
+package gql
+
+import (
+	"fmt"
+	"net/http"
+
+	"maukemana-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// Query limits guard against expensive nested queries
+const (
+	maxQueryDepth   = 8
+	maxParallelism  = 10
+	maxRequestBytes = 64 << 10
+)
+
+// Handler serves GraphQL over HTTP
+type Handler struct {
+	schema   *graphql.Schema
+	resolver *Resolver
+}
+
+// NewHandler parses the schema against the resolver. It fails if the schema
+// and resolver methods disagree.
+func NewHandler(resolver *Resolver, introspection bool) (*Handler, error) {
+	opts := []graphql.SchemaOpt{
+		graphql.MaxDepth(maxQueryDepth),
+		graphql.MaxParallelism(maxParallelism),
+	}
+	if !introspection {
+		opts = append(opts, graphql.DisableIntrospection())
+	}
+
+	schema, err := graphql.ParseSchema(schemaSDL, resolver, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("parse graphql schema: %w", err)
+	}
+	return &Handler{schema: schema, resolver: resolver}, nil
+}
+
+type request struct {
+	Query         string                 `json:"query" form:"query"`
+	OperationName string                 `json:"operationName" form:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Serve handles GET and POST /graphql. Authentication is optional; when the
+// auth middleware identified the caller, they become the viewer that the
+// resolvers of @auth and @hasPermission fields check.
+func (h *Handler) Serve(c *gin.Context) {
+	var req request
+	if c.Request.Method == http.MethodGet {
+		// GET is limited to queries without variables, mainly for cacheable reads
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+	} else {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxRequestBytes)
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": []gin.H{{"message": "invalid GraphQL request body"}}})
+			return
+		}
+	}
+	if req.Query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []gin.H{{"message": "query is required"}}})
+		return
+	}
+
+	ctx := withLoaders(c.Request.Context(), h.resolver.newLoaders())
+	if userID, ok := c.Get("user_id"); ok {
+		role := c.GetString("user_role")
+		if role == "" {
+			role = services.RoleUser
+		}
+		perms, _ := c.Get("permissions")
+		permSet, _ := perms.(services.PermissionSet)
+		ctx = WithViewer(ctx, services.Actor{UserID: userID.(uuid.UUID), Role: role, Permissions: permSet})
+	}
+	if locale := c.GetString("locale"); locale != c.GetString("default_locale") {
+		ctx = withLocale(ctx, locale)
+		c.Header("Content-Language", locale)
+	}
+
+	resp := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	c.JSON(http.StatusOK, resp)
+}
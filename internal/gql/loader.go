@@ -0,0 +1,98 @@
+package gql
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// batchWait is how long a loader collects keys before fetching them together.
+// Sibling fields resolve concurrently, so a short window is enough to gather them.
+const batchWait = 2 * time.Millisecond
+
+// maxBatchSize dispatches a batch early once it holds this many keys
+const maxBatchSize = 100
+
+// BatchFunc fetches values for a batch of keys. Keys missing from the result
+// resolve to the zero value.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader coalesces loads issued within batchWait into a single BatchFunc call
+// and caches results for the lifetime of the loader (one GraphQL request).
+type Loader[K comparable, V any] struct {
+	fetch BatchFunc[K, V]
+
+	mu      sync.Mutex
+	cache   map[K]*loadResult[V]
+	pending *loadBatch[K, V]
+}
+
+type loadResult[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type loadBatch[K comparable, V any] struct {
+	keys    []K
+	results []*loadResult[V]
+}
+
+// NewLoader creates a loader backed by fetch
+func NewLoader[K comparable, V any](fetch BatchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{fetch: fetch, cache: make(map[K]*loadResult[V])}
+}
+
+// Load returns the value for key, batching it with concurrent loads
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	res, ok := l.cache[key]
+	if !ok {
+		res = &loadResult[V]{done: make(chan struct{})}
+		l.cache[key] = res
+
+		if l.pending == nil {
+			b := &loadBatch[K, V]{}
+			l.pending = b
+			time.AfterFunc(batchWait, func() { l.dispatch(ctx, b) })
+		}
+		b := l.pending
+		b.keys = append(b.keys, key)
+		b.results = append(b.results, res)
+		if len(b.keys) >= maxBatchSize {
+			l.pending = nil
+			go l.run(ctx, b)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-res.done:
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// dispatch runs b when its wait window closes, unless it was already sent for being full
+func (l *Loader[K, V]) dispatch(ctx context.Context, b *loadBatch[K, V]) {
+	l.mu.Lock()
+	if l.pending != b {
+		l.mu.Unlock()
+		return
+	}
+	l.pending = nil
+	l.mu.Unlock()
+
+	l.run(ctx, b)
+}
+
+func (l *Loader[K, V]) run(ctx context.Context, b *loadBatch[K, V]) {
+	values, err := l.fetch(ctx, b.keys)
+	for i, key := range b.keys {
+		res := b.results[i]
+		res.value, res.err = values[key], err
+		close(res.done)
+	}
+}
@@ -0,0 +1,117 @@
+package gql
+
+import (
+	"context"
+
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// PhotoRepository defines the photo reads used by the GraphQL layer
+type PhotoRepository interface {
+	GetByPOIs(ctx context.Context, poiIDs []uuid.UUID) (map[uuid.UUID][]models.Photo, error)
+}
+
+// ReviewRepository defines the review reads used by the GraphQL layer
+type ReviewRepository interface {
+	GetByPOIs(ctx context.Context, poiIDs []uuid.UUID, viewerID *uuid.UUID, perPOI int) (map[uuid.UUID][]models.Review, error)
+}
+
+// CommentRepository defines the comment reads used by the GraphQL layer
+type CommentRepository interface {
+	GetByPOIs(ctx context.Context, poiIDs []uuid.UUID, viewerID *uuid.UUID, perPOI int) (map[uuid.UUID][]models.Comment, error)
+	GetRepliesByParents(ctx context.Context, parentIDs []uuid.UUID, viewerID *uuid.UUID) (map[uuid.UUID][]models.Comment, error)
+}
+
+// ProfileRepository defines the user profile reads used by the GraphQL layer
+type ProfileRepository interface {
+	GetProfilesByIDs(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]models.UserProfile, error)
+}
+
+// pageKey identifies a per-POI page of a related list
+type pageKey struct {
+	ID    uuid.UUID
+	Limit int
+}
+
+// Loaders batch the related-resource reads of one GraphQL request
+type Loaders struct {
+	Photos   *Loader[uuid.UUID, []models.Photo]
+	Reviews  *Loader[pageKey, []models.Review]
+	Comments *Loader[pageKey, []models.Comment]
+	Replies  *Loader[uuid.UUID, []models.Comment]
+	Profiles *Loader[uuid.UUID, *models.UserProfile]
+}
+
+// newLoaders creates a fresh set of loaders; they must not outlive the request
+func (r *Resolver) newLoaders() *Loaders {
+	return &Loaders{
+		Photos: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]models.Photo, error) {
+			return r.photos.GetByPOIs(ctx, ids)
+		}),
+		Reviews: NewLoader(func(ctx context.Context, keys []pageKey) (map[pageKey][]models.Review, error) {
+			return loadPages(ctx, keys, func(ctx context.Context, ids []uuid.UUID, perPOI int) (map[uuid.UUID][]models.Review, error) {
+				return r.reviews.GetByPOIs(ctx, ids, viewerID(ctx), perPOI)
+			})
+		}),
+		Comments: NewLoader(func(ctx context.Context, keys []pageKey) (map[pageKey][]models.Comment, error) {
+			return loadPages(ctx, keys, func(ctx context.Context, ids []uuid.UUID, perPOI int) (map[uuid.UUID][]models.Comment, error) {
+				return r.comments.GetByPOIs(ctx, ids, viewerID(ctx), perPOI)
+			})
+		}),
+		Replies: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]models.Comment, error) {
+			return r.comments.GetRepliesByParents(ctx, ids, viewerID(ctx))
+		}),
+		Profiles: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.UserProfile, error) {
+			profiles, err := r.profiles.GetProfilesByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			out := make(map[uuid.UUID]*models.UserProfile, len(profiles))
+			for id, p := range profiles {
+				out[id] = &p
+			}
+			return out, nil
+		}),
+	}
+}
+
+// viewerID returns the signed-in viewer, if any, so shadow-banned authors still see their own content
+func viewerID(ctx context.Context) *uuid.UUID {
+	if viewer, ok := ViewerFrom(ctx); ok {
+		return &viewer.UserID
+	}
+	return nil
+}
+
+// loadPages runs one query per distinct page size in the batch
+func loadPages[V any](ctx context.Context, keys []pageKey, fetch func(context.Context, []uuid.UUID, int) (map[uuid.UUID][]V, error)) (map[pageKey][]V, error) {
+	byLimit := make(map[int][]uuid.UUID)
+	for _, k := range keys {
+		byLimit[k.Limit] = append(byLimit[k.Limit], k.ID)
+	}
+
+	out := make(map[pageKey][]V, len(keys))
+	for limit, ids := range byLimit {
+		rows, err := fetch(ctx, ids, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			out[pageKey{ID: id, Limit: limit}] = rows[id]
+		}
+	}
+	return out, nil
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *Loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *Loaders {
+	l, _ := ctx.Value(loadersKey{}).(*Loaders)
+	return l
+}
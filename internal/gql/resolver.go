@@ -0,0 +1,191 @@
+package gql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/services"
+
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// maxPageSize caps first: arguments on lists
+const maxPageSize = 50
+
+// POIRepository defines the POI reads used by the GraphQL layer
+type POIRepository interface {
+	Search(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]repositories.POI, error)
+	GetByIDWithOptions(ctx context.Context, id uuid.UUID, opts repositories.POIReadOptions) (*repositories.POI, error)
+	GetByStatus(ctx context.Context, status string, limit, offset int) ([]repositories.POI, error)
+}
+
+// Resolver is the root GraphQL resolver
+type Resolver struct {
+	pois     POIRepository
+	photos   PhotoRepository
+	reviews  ReviewRepository
+	comments CommentRepository
+	profiles ProfileRepository
+}
+
+// NewResolver creates the root resolver over the existing repositories
+func NewResolver(pois POIRepository, photos PhotoRepository, reviews ReviewRepository, comments CommentRepository, profiles ProfileRepository) *Resolver {
+	return &Resolver{pois: pois, photos: photos, reviews: reviews, comments: comments, profiles: profiles}
+}
+
+// POIFilter mirrors the search filters of GET /api/v1/pois
+type POIFilter struct {
+	CategoryID       *graphql.ID
+	PriceRange       *int32
+	Cuisine          *string
+	Vibes            *[]string
+	HasWifi          *bool
+	HasActiveSpecial *bool
+	Lat              *float64
+	Lng              *float64
+	Radius           *float64
+	SortBy           *string
+}
+
+type pageArgs struct {
+	First  int32
+	Offset int32
+}
+
+// Poi resolves Query.poi
+func (r *Resolver) Poi(ctx context.Context, args struct{ ID graphql.ID }) (*POIResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	poi, err := r.pois.GetByIDWithOptions(ctx, id, repositories.POIReadOptions{Locale: localeFrom(ctx)})
+	if errors.Is(err, sql.ErrNoRows) {
+		// Missing POIs resolve to null rather than an error
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &POIResolver{poi: poi}, nil
+}
+
+// Pois resolves Query.pois. Only approved POIs are searchable.
+func (r *Resolver) Pois(ctx context.Context, args struct {
+	Filter *POIFilter
+	First  int32
+	Offset int32
+}) ([]*POIResolver, error) {
+	filters := map[string]interface{}{"status": string(services.POIStatusApproved)}
+	if f := args.Filter; f != nil {
+		if f.CategoryID != nil {
+			id, err := parseID(*f.CategoryID)
+			if err != nil {
+				return nil, err
+			}
+			filters["category_id"] = id
+		}
+		if f.PriceRange != nil {
+			filters["price_range"] = int(*f.PriceRange)
+		}
+		if f.Cuisine != nil {
+			filters["cuisine"] = *f.Cuisine
+		}
+		if f.Vibes != nil && len(*f.Vibes) > 0 {
+			filters["vibes"] = *f.Vibes
+		}
+		if f.HasWifi != nil && *f.HasWifi {
+			filters["has_wifi"] = true
+		}
+		if f.HasActiveSpecial != nil && *f.HasActiveSpecial {
+			filters["has_active_special"] = true
+		}
+		if f.Lat != nil {
+			filters["lat"] = *f.Lat
+		}
+		if f.Lng != nil {
+			filters["lng"] = *f.Lng
+		}
+		if f.Radius != nil {
+			filters["radius"] = *f.Radius
+		}
+		if f.SortBy != nil {
+			filters["sort_by"] = *f.SortBy
+		}
+	}
+	if locale := localeFrom(ctx); locale != "" {
+		filters["locale"] = locale
+	}
+
+	pois, err := r.pois.Search(ctx, filters, clampFirst(args.First), clampOffset(args.Offset))
+	if err != nil {
+		return nil, err
+	}
+	return wrapPOIs(pois), nil
+}
+
+// PendingPois resolves Query.pendingPois (@hasPermission(permission: "poi:approve"))
+func (r *Resolver) PendingPois(ctx context.Context, args pageArgs) ([]*POIResolver, error) {
+	if _, err := guard(ctx, hasPermission(services.PermPOIApprove)); err != nil {
+		return nil, err
+	}
+	pois, err := r.pois.GetByStatus(ctx, string(services.POIStatusPending), clampFirst(args.First), clampOffset(args.Offset))
+	if err != nil {
+		return nil, err
+	}
+	return wrapPOIs(pois), nil
+}
+
+// User resolves Query.user
+func (r *Resolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*ProfileResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	return loadProfile(ctx, &id)
+}
+
+// Me resolves Query.me (@auth)
+func (r *Resolver) Me(ctx context.Context) (*ProfileResolver, error) {
+	viewer, err := guard(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return loadProfile(ctx, &viewer.UserID)
+}
+
+func wrapPOIs(pois []repositories.POI) []*POIResolver {
+	out := make([]*POIResolver, len(pois))
+	for i := range pois {
+		out[i] = &POIResolver{poi: &pois[i]}
+	}
+	return out
+}
+
+func parseID(id graphql.ID) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(string(id))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid ID %q", id)
+	}
+	return parsed, nil
+}
+
+func clampFirst(first int32) int {
+	switch {
+	case first < 1:
+		return 1
+	case first > maxPageSize:
+		return maxPageSize
+	}
+	return int(first)
+}
+
+func clampOffset(offset int32) int {
+	if offset < 0 {
+		return 0
+	}
+	return int(offset)
+}
@@ -0,0 +1,117 @@
+package gql
+
+// schemaSDL is the GraphQL schema served at /graphql. Relations (photos,
+// reviews, comments, profiles) are resolved through per-request loaders so a
+// list of POIs costs one query per relation, not one per POI.
+const schemaSDL = `
+schema {
+	query: Query
+}
+
+# Requires a signed-in user (Clerk bearer token)
+directive @auth on FIELD_DEFINITION
+
+# Requires a signed-in user whose role grants the permission (e.g. "poi:approve")
+directive @hasPermission(permission: String!) on FIELD_DEFINITION
+
+scalar Time
+
+type Query {
+	poi(id: ID!): POI
+	pois(filter: POIFilter, first: Int = 20, offset: Int = 0): [POI!]!
+	user(id: ID!): UserProfile
+	me: UserProfile @auth
+	pendingPois(first: Int = 20, offset: Int = 0): [POI!]! @hasPermission(permission: "poi:approve")
+}
+
+input POIFilter {
+	categoryId: ID
+	priceRange: Int
+	cuisine: String
+	vibes: [String!]
+	hasWifi: Boolean
+	hasActiveSpecial: Boolean
+	lat: Float
+	lng: Float
+	radius: Float
+	# recommended | nearest | top_rated
+	sortBy: String
+}
+
+type POI {
+	id: ID!
+	name: String!
+	description: String
+	brand: String
+	website: String
+	latitude: Float!
+	longitude: Float!
+	coverImageUrl: String
+	priceRange: Int
+	cuisine: String
+	vibes: [String!]!
+	hasWifi: Boolean!
+	wifiQuality: String
+	noiseLevel: String
+	ratingAvg: Float!
+	reviewsCount: Int!
+	isVerified: Boolean!
+	status: String!
+	createdAt: Time!
+	updatedAt: Time!
+	founder: UserProfile
+	photos(first: Int = 20): [Photo!]!
+	reviews(first: Int = 10): [Review!]!
+	comments(first: Int = 10): [Comment!]!
+}
+
+type Photo {
+	id: ID!
+	url: String!
+	isHero: Boolean!
+	isPinned: Boolean!
+	isAdminOfficial: Boolean!
+	vibeCategory: String
+	upvotes: Int!
+	downvotes: Int!
+	score: Int!
+	createdAt: Time!
+	uploader: UserProfile
+}
+
+type Review {
+	id: ID!
+	rating: Int
+	wifiRating: Int
+	comfortRating: Int
+	foodRating: Int
+	valueRating: Int
+	noiseAccuracyRating: Int
+	photoAssetIds: [ID!]!
+	content: String
+	upvotes: Int!
+	downvotes: Int!
+	helpfulScore: Int!
+	createdAt: Time!
+	author: UserProfile
+}
+
+type Comment {
+	id: ID!
+	content: String!
+	createdAt: Time!
+	updatedAt: Time!
+	author: UserProfile
+	replies: [Comment!]!
+}
+
+type UserProfile {
+	id: ID!
+	name: String
+	username: String
+	avatarUrl: String
+	scoutLevel: Int!
+	globalXp: Int!
+	impactScore: Int!
+}
+`
@@ -0,0 +1,218 @@
+package gql
+
+import (
+	"context"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// POIResolver resolves the POI type
+type POIResolver struct {
+	poi *repositories.POI
+}
+
+func (p *POIResolver) ID() graphql.ID          { return graphql.ID(p.poi.PoiID.String()) }
+func (p *POIResolver) Name() string            { return p.poi.Name }
+func (p *POIResolver) Description() *string    { return p.poi.Description }
+func (p *POIResolver) Brand() *string          { return p.poi.Brand }
+func (p *POIResolver) Website() *string        { return p.poi.Website }
+func (p *POIResolver) Latitude() float64       { return p.poi.Latitude }
+func (p *POIResolver) Longitude() float64      { return p.poi.Longitude }
+func (p *POIResolver) CoverImageURL() *string  { return p.poi.CoverImageURL }
+func (p *POIResolver) Cuisine() *string        { return p.poi.Cuisine }
+func (p *POIResolver) Vibes() []string         { return nonNil(p.poi.Vibes) }
+func (p *POIResolver) HasWifi() bool           { return p.poi.HasWifi }
+func (p *POIResolver) WifiQuality() *string    { return p.poi.WifiQuality }
+func (p *POIResolver) NoiseLevel() *string     { return p.poi.NoiseLevel }
+func (p *POIResolver) RatingAvg() float64      { return p.poi.RatingAvg }
+func (p *POIResolver) ReviewsCount() int32     { return int32(p.poi.ReviewsCount) }
+func (p *POIResolver) IsVerified() bool        { return p.poi.IsVerified }
+func (p *POIResolver) Status() string          { return p.poi.Status }
+func (p *POIResolver) CreatedAt() graphql.Time { return graphql.Time{Time: p.poi.CreatedAt} }
+func (p *POIResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: p.poi.UpdatedAt} }
+
+func (p *POIResolver) PriceRange() *int32 {
+	if p.poi.PriceRange == nil {
+		return nil
+	}
+	v := int32(*p.poi.PriceRange)
+	return &v
+}
+
+// Founder is the founding user, falling back to the creator for older POIs
+func (p *POIResolver) Founder(ctx context.Context) (*ProfileResolver, error) {
+	id := p.poi.FoundingUserID
+	if id == nil {
+		id = p.poi.CreatedBy
+	}
+	return loadProfile(ctx, id)
+}
+
+func (p *POIResolver) Photos(ctx context.Context, args struct{ First int32 }) ([]*PhotoResolver, error) {
+	photos, err := loadersFrom(ctx).Photos.Load(ctx, p.poi.PoiID)
+	if err != nil {
+		return nil, err
+	}
+	if limit := clampFirst(args.First); len(photos) > limit {
+		photos = photos[:limit]
+	}
+	out := make([]*PhotoResolver, len(photos))
+	for i := range photos {
+		out[i] = &PhotoResolver{photo: &photos[i]}
+	}
+	return out, nil
+}
+
+func (p *POIResolver) Reviews(ctx context.Context, args struct{ First int32 }) ([]*ReviewResolver, error) {
+	reviews, err := loadersFrom(ctx).Reviews.Load(ctx, pageKey{ID: p.poi.PoiID, Limit: clampFirst(args.First)})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*ReviewResolver, len(reviews))
+	for i := range reviews {
+		out[i] = &ReviewResolver{review: &reviews[i]}
+	}
+	return out, nil
+}
+
+func (p *POIResolver) Comments(ctx context.Context, args struct{ First int32 }) ([]*CommentResolver, error) {
+	comments, err := loadersFrom(ctx).Comments.Load(ctx, pageKey{ID: p.poi.PoiID, Limit: clampFirst(args.First)})
+	if err != nil {
+		return nil, err
+	}
+	return wrapComments(comments), nil
+}
+
+// PhotoResolver resolves the Photo type
+type PhotoResolver struct {
+	photo *models.Photo
+}
+
+func (p *PhotoResolver) ID() graphql.ID          { return graphql.ID(p.photo.PhotoID.String()) }
+func (p *PhotoResolver) URL() string             { return p.photo.URL }
+func (p *PhotoResolver) IsHero() bool            { return p.photo.IsHero }
+func (p *PhotoResolver) IsPinned() bool          { return p.photo.IsPinned }
+func (p *PhotoResolver) IsAdminOfficial() bool   { return p.photo.IsAdminOfficial }
+func (p *PhotoResolver) VibeCategory() *string   { return p.photo.VibeCategory }
+func (p *PhotoResolver) Upvotes() int32          { return int32(p.photo.Upvotes) }
+func (p *PhotoResolver) Downvotes() int32        { return int32(p.photo.Downvotes) }
+func (p *PhotoResolver) Score() int32            { return int32(p.photo.Score) }
+func (p *PhotoResolver) CreatedAt() graphql.Time { return graphql.Time{Time: p.photo.CreatedAt} }
+
+func (p *PhotoResolver) Uploader(ctx context.Context) (*ProfileResolver, error) {
+	return loadProfile(ctx, p.photo.UserID)
+}
+
+// ReviewResolver resolves the Review type
+type ReviewResolver struct {
+	review *models.Review
+}
+
+func (r *ReviewResolver) ID() graphql.ID          { return graphql.ID(r.review.ReviewID.String()) }
+func (r *ReviewResolver) Content() *string        { return r.review.Content }
+func (r *ReviewResolver) Upvotes() int32          { return int32(r.review.Upvotes) }
+func (r *ReviewResolver) Downvotes() int32        { return int32(r.review.Downvotes) }
+func (r *ReviewResolver) HelpfulScore() int32     { return int32(r.review.HelpfulScore) }
+func (r *ReviewResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.review.CreatedAt} }
+
+func (r *ReviewResolver) Rating() *int32        { return optionalInt32(r.review.Rating) }
+func (r *ReviewResolver) WifiRating() *int32    { return optionalInt32(r.review.WifiRating) }
+func (r *ReviewResolver) ComfortRating() *int32 { return optionalInt32(r.review.ComfortRating) }
+func (r *ReviewResolver) FoodRating() *int32    { return optionalInt32(r.review.FoodRating) }
+func (r *ReviewResolver) ValueRating() *int32   { return optionalInt32(r.review.ValueRating) }
+func (r *ReviewResolver) NoiseAccuracyRating() *int32 {
+	return optionalInt32(r.review.NoiseAccuracyRating)
+}
+
+func (r *ReviewResolver) PhotoAssetIds() []graphql.ID {
+	ids := make([]graphql.ID, len(r.review.PhotoAssetIDs))
+	for i, id := range r.review.PhotoAssetIDs {
+		ids[i] = graphql.ID(id)
+	}
+	return ids
+}
+
+func optionalInt32(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	n := int32(*v)
+	return &n
+}
+
+func (r *ReviewResolver) Author(ctx context.Context) (*ProfileResolver, error) {
+	return loadProfile(ctx, &r.review.UserID)
+}
+
+// CommentResolver resolves the Comment type
+type CommentResolver struct {
+	comment *models.Comment
+}
+
+func (c *CommentResolver) ID() graphql.ID          { return graphql.ID(c.comment.CommentID.String()) }
+func (c *CommentResolver) Content() string         { return c.comment.Content }
+func (c *CommentResolver) CreatedAt() graphql.Time { return graphql.Time{Time: c.comment.CreatedAt} }
+func (c *CommentResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: c.comment.UpdatedAt} }
+
+func (c *CommentResolver) Author(ctx context.Context) (*ProfileResolver, error) {
+	return loadProfile(ctx, &c.comment.UserID)
+}
+
+func (c *CommentResolver) Replies(ctx context.Context) ([]*CommentResolver, error) {
+	replies, err := loadersFrom(ctx).Replies.Load(ctx, c.comment.CommentID)
+	if err != nil {
+		return nil, err
+	}
+	return wrapComments(replies), nil
+}
+
+func wrapComments(comments []models.Comment) []*CommentResolver {
+	out := make([]*CommentResolver, len(comments))
+	for i := range comments {
+		out[i] = &CommentResolver{comment: &comments[i]}
+	}
+	return out
+}
+
+// ProfileResolver resolves the UserProfile type
+type ProfileResolver struct {
+	profile *models.UserProfile
+}
+
+func (p *ProfileResolver) ID() graphql.ID     { return graphql.ID(p.profile.UserID.String()) }
+func (p *ProfileResolver) Name() *string      { return p.profile.Name }
+func (p *ProfileResolver) Username() *string  { return p.profile.Username }
+func (p *ProfileResolver) ScoutLevel() int32  { return int32(p.profile.ScoutLevel) }
+func (p *ProfileResolver) GlobalXp() int32    { return int32(p.profile.GlobalXP) }
+func (p *ProfileResolver) ImpactScore() int32 { return int32(p.profile.ImpactScore) }
+
+// AvatarURL prefers the gamification avatar over the Clerk picture
+func (p *ProfileResolver) AvatarURL() *string {
+	if p.profile.AvatarURL != nil {
+		return p.profile.AvatarURL
+	}
+	return p.profile.PictureURL
+}
+
+// loadProfile batches a profile lookup; a nil or unknown user resolves to null
+func loadProfile(ctx context.Context, userID *uuid.UUID) (*ProfileResolver, error) {
+	if userID == nil {
+		return nil, nil
+	}
+	profile, err := loadersFrom(ctx).Profiles.Load(ctx, *userID)
+	if err != nil || profile == nil {
+		return nil, err
+	}
+	return &ProfileResolver{profile: profile}, nil
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,391 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"maukemana-backend/internal/auth"
+	"maukemana-backend/internal/imaging"
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AccountRepository defines the data access needed for data exports and account deletion
+type AccountRepository interface {
+	GetLatestExport(ctx context.Context, userID uuid.UUID) (*models.DataExport, error)
+	CreateExport(ctx context.Context, userID uuid.UUID) (*models.DataExport, error)
+	GetExportArchive(ctx context.Context, exportID uuid.UUID) ([]byte, error)
+	Anonymize(ctx context.Context, userID uuid.UUID) (*string, error)
+	SetAvatar(ctx context.Context, userID, assetID uuid.UUID, pictureURL string) (*uuid.UUID, error)
+	FindLinkCandidates(ctx context.Context, userID uuid.UUID, emails []string) ([]models.LinkCandidate, error)
+	MergeAccounts(ctx context.Context, sourceID, targetID uuid.UUID) (*models.AccountMerge, error)
+}
+
+// AccountUsers resolves sign-in identities to users and drops users from the
+// auth cache after their account changes
+type AccountUsers interface {
+	UserInvalidator
+	GetByClerkID(ctx context.Context, clerkID string) (*repositories.User, error)
+}
+
+// NotificationRepository defines the data access needed for in-app notifications
+type NotificationRepository interface {
+	ListForUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]models.Notification, error)
+	MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error
+}
+
+// AccountHandler serves the user's own data: export, deletion, linking, notifications and avatar
+type AccountHandler struct {
+	repo          AccountRepository
+	notifications NotificationRepository
+	users         AccountUsers
+	imaging       *imaging.Service // nil when R2 is not configured
+}
+
+// NewAccountHandler creates a new account handler
+func NewAccountHandler(repo AccountRepository, notifications NotificationRepository, users AccountUsers) *AccountHandler {
+	return &AccountHandler{repo: repo, notifications: notifications, users: users}
+}
+
+// UseImaging enables avatar uploads
+func (h *AccountHandler) UseImaging(svc *imaging.Service) {
+	h.imaging = svc
+}
+
+// ExportData handles GET /api/v1/me/export. The first call queues an export
+// and answers 202; once the archive is built (the user is notified) the same
+// call downloads it. refresh=true queues a new export.
+func (h *AccountHandler) ExportData(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	ctx := c.Request.Context()
+	export, err := h.repo.GetLatestExport(ctx, actor.UserID)
+	if err != nil && !errors.Is(err, repositories.ErrExportNotFound) {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	inProgress := export != nil && (export.Status == models.ExportPending || export.Status == models.ExportProcessing)
+	if export == nil || export.Status == models.ExportFailed || (c.Query("refresh") == "true" && !inProgress) {
+		if export, err = h.repo.CreateExport(ctx, actor.UserID); err != nil {
+			utils.SendInternalError(c, err)
+			return
+		}
+		inProgress = true
+	}
+
+	if inProgress {
+		utils.SendAccepted(c, "Data export is being prepared; you will be notified when it is ready", export)
+		return
+	}
+
+	archive, err := h.repo.GetExportArchive(ctx, export.ExportID)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	filename := "maukemana-export-" + export.CreatedAt.UTC().Format("20060102") + ".json"
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/json", archive)
+}
+
+// DeleteAccount handles DELETE /api/v1/me?confirm=true. Private data is
+// deleted, published contributions are kept anonymously, and the sign-in
+// identity is removed.
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	if c.Query("confirm") != "true" {
+		utils.SendError(c, http.StatusBadRequest, "account deletion must be confirmed with confirm=true", nil)
+		return
+	}
+
+	ctx := c.Request.Context()
+	clerkID, err := h.repo.Anonymize(ctx, actor.UserID)
+	if errors.Is(err, repositories.ErrAccountNotFound) {
+		utils.SendError(c, http.StatusNotFound, "account not found", err)
+		return
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	h.users.Invalidate(actor.UserID)
+
+	// The account is already anonymized; a leftover identity only lets the
+	// user sign in to a fresh, empty account
+	if clerkID != nil {
+		if err := auth.DeleteUser(ctx, *clerkID); err != nil {
+			slog.WarnContext(ctx, "failed to delete identity provider user", "user_id", actor.UserID, "error", err)
+		}
+	}
+
+	utils.SendSuccess(c, "Account deleted", gin.H{"user_id": actor.UserID})
+}
+
+// ListLinkCandidates handles GET /api/v1/me/link-account. It lists other
+// accounts registered with one of the caller's verified email addresses.
+func (h *AccountHandler) ListLinkCandidates(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	candidates, ok := h.linkCandidates(c, actor.UserID)
+	if !ok {
+		return
+	}
+	utils.SendSuccess(c, "Link candidates retrieved", candidates)
+}
+
+// LinkAccountRequest is the body for POST /api/v1/me/link-account. The
+// account to link is proven either by a session token signed in as it, or by
+// its email being a verified address of the caller's identity.
+type LinkAccountRequest struct {
+	Token  string     `json:"token"`
+	UserID *uuid.UUID `json:"user_id"`
+}
+
+// LinkAccount handles POST /api/v1/me/link-account. The other account is
+// merged into the caller's: its POIs, reviews, saved POIs, photos and blocks
+// move over, it is retired, and its sign-in identity opens the caller's account.
+func (h *AccountHandler) LinkAccount(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var req LinkAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	if (req.Token == "") == (req.UserID == nil) {
+		utils.SendError(c, http.StatusBadRequest, "provide either token or user_id", nil)
+		return
+	}
+
+	ctx := c.Request.Context()
+	var sourceID uuid.UUID
+	if req.Token != "" {
+		claims, err := auth.VerifyToken(req.Token)
+		if err != nil {
+			utils.SendError(c, http.StatusForbidden, "token does not prove access to the account", nil)
+			return
+		}
+		source, err := h.users.GetByClerkID(ctx, claims.Subject)
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.SendError(c, http.StatusNotFound, "account not found", nil)
+			return
+		}
+		if err != nil {
+			utils.SendInternalError(c, err)
+			return
+		}
+		sourceID = source.UserID
+	} else {
+		candidates, ok := h.linkCandidates(c, actor.UserID)
+		if !ok {
+			return
+		}
+		found := false
+		for _, cand := range candidates {
+			found = found || cand.UserID == *req.UserID
+		}
+		if !found {
+			utils.SendError(c, http.StatusForbidden, "account does not share a verified email address with yours", nil)
+			return
+		}
+		sourceID = *req.UserID
+	}
+	if sourceID == actor.UserID {
+		utils.SendError(c, http.StatusBadRequest, "cannot link an account to itself", nil)
+		return
+	}
+
+	merge, err := h.repo.MergeAccounts(ctx, sourceID, actor.UserID)
+	if errors.Is(err, repositories.ErrAccountNotFound) {
+		utils.SendError(c, http.StatusNotFound, "account not found", err)
+		return
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	h.users.Invalidate(sourceID)
+	h.users.Invalidate(actor.UserID)
+
+	slog.InfoContext(ctx, "accounts linked", "source_user_id", sourceID, "target_user_id", actor.UserID, "merge_id", merge.MergeID)
+	utils.SendSuccess(c, "Account linked", merge)
+}
+
+// linkCandidates looks up the accounts sharing a verified email with the
+// caller's identity, answering the request itself on failure
+func (h *AccountHandler) linkCandidates(c *gin.Context, userID uuid.UUID) ([]models.LinkCandidate, bool) {
+	clerkUser, err := auth.GetUser(c.GetString("clerk_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadGateway, "failed to fetch user info from the identity provider", nil)
+		return nil, false
+	}
+	candidates, err := h.repo.FindLinkCandidates(c.Request.Context(), userID, auth.VerifiedEmails(clerkUser))
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return nil, false
+	}
+	return candidates, true
+}
+
+// ListNotifications handles GET /api/v1/me/notifications?unread=true
+func (h *AccountHandler) ListNotifications(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	page, limit := utils.GetPagination(c)
+	offset := utils.GetOffset(page, limit)
+	notifications, err := h.notifications.ListForUser(c.Request.Context(), actor.UserID, c.Query("unread") == "true", limit, offset)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendPaginated(c, "Notifications retrieved", notifications, page, limit, len(notifications)+offset)
+}
+
+// MarkNotificationRead handles POST /api/v1/me/notifications/:id/read
+func (h *AccountHandler) MarkNotificationRead(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid notification ID format", err)
+		return
+	}
+
+	if err := h.notifications.MarkRead(c.Request.Context(), actor.UserID, id); err != nil {
+		if errors.Is(err, repositories.ErrNotificationNotFound) {
+			utils.SendError(c, http.StatusNotFound, "notification not found", err)
+			return
+		}
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Notification marked as read", gin.H{"notification_id": id})
+}
+
+// avatarRendition is the profile rendition users.picture_url links to
+const avatarRendition = "profile_200"
+
+// SetAvatarRequest is the body for POST /api/v1/me/avatar. AssetID is the ID
+// returned by upload finalization: the asset's, or the job's while it ran.
+type SetAvatarRequest struct {
+	AssetID  uuid.UUID           `json:"asset_id" binding:"required"`
+	CropData *imaging.CropConfig `json:"crop_data"`
+}
+
+// SetAvatar handles POST /api/v1/me/avatar. The caller's own processed upload
+// becomes their picture; the profile ladder is rendered from its original
+// when it has none yet or a crop is given.
+func (h *AccountHandler) SetAvatar(c *gin.Context) {
+	if h.imaging == nil {
+		utils.SendError(c, http.StatusServiceUnavailable, "image processing is not configured", nil)
+		return
+	}
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var req SetAvatarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+
+	asset, ok := h.imaging.GetAssetByID(c.Request.Context(), req.AssetID)
+	if !ok {
+		job, found := h.imaging.GetJobByID(c.Request.Context(), req.AssetID)
+		if !found || job.UserID != actor.UserID {
+			utils.SendError(c, http.StatusNotFound, "asset not found", nil)
+			return
+		}
+		if job.AssetID == nil {
+			utils.SendError(c, http.StatusConflict, "upload is still processing", nil)
+			return
+		}
+		if asset, ok = h.imaging.GetAssetByID(c.Request.Context(), *job.AssetID); !ok {
+			utils.SendError(c, http.StatusNotFound, "asset not found", nil)
+			return
+		}
+	}
+	if asset.CreatedByUserID != actor.UserID {
+		utils.SendError(c, http.StatusForbidden, "only your own uploads can be used as avatar", nil)
+		return
+	}
+	if asset.Status != imaging.StatusReady {
+		utils.SendError(c, http.StatusConflict, "upload is still processing", nil)
+		return
+	}
+	if asset.ModerationStatus.Blocked() {
+		utils.SendError(c, http.StatusUnprocessableEntity, "image is not available", nil)
+		return
+	}
+
+	var jobID *uuid.UUID
+	if req.CropData != nil || !hasRenditions(asset, imaging.RenditionPrefix("profile")) {
+		id, err := h.imaging.QueueReprocessing(imaging.OriginalKey(asset.ContentHash), "profile", actor.UserID, req.CropData)
+		if err != nil {
+			utils.SendError(c, http.StatusServiceUnavailable, "processing queue is unavailable, try again later", nil)
+			return
+		}
+		jobID = &id
+	}
+
+	ctx := c.Request.Context()
+	pictureURL := h.imaging.GetDerivativeURL(asset.ContentHash, avatarRendition)
+	previous, err := h.repo.SetAvatar(ctx, actor.UserID, asset.ID, pictureURL)
+	if errors.Is(err, repositories.ErrAccountNotFound) {
+		utils.SendError(c, http.StatusNotFound, "account not found", err)
+		return
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	h.users.Invalidate(actor.UserID)
+	if previous != nil && *previous != asset.ID {
+		slog.InfoContext(ctx, "avatar replaced", "user_id", actor.UserID, "previous_asset_id", *previous, "asset_id", asset.ID)
+	}
+
+	resp := gin.H{"picture_url": pictureURL, "asset_id": asset.ID}
+	if jobID != nil {
+		resp["job_id"] = jobID
+		resp["status_url"] = "/api/v1/assets/" + jobID.String()
+	}
+	utils.SendSuccess(c, "Avatar updated", resp)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchAnalyticsRepository defines the data access needed for search reports
+type SearchAnalyticsRepository interface {
+	Summarize(ctx context.Context, days, limit int) (*models.SearchSummary, error)
+}
+
+// HeatmapRepository aggregates POIs on a geographic grid
+type HeatmapRepository interface {
+	Heatmap(ctx context.Context, bbox models.BBox, metric string, cellSize float64) (*models.Heatmap, error)
+}
+
+// AnalyticsHandler serves product analytics reports (requires analytics:view)
+type AnalyticsHandler struct {
+	searches SearchAnalyticsRepository
+	heatmaps HeatmapRepository
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(searches SearchAnalyticsRepository, heatmaps HeatmapRepository) *AnalyticsHandler {
+	return &AnalyticsHandler{searches: searches, heatmaps: heatmaps}
+}
+
+// SearchSummary handles GET /api/v1/admin/analytics/search?days=7&limit=20
+func (h *AnalyticsHandler) SearchSummary(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil || days < 1 || days > 90 {
+		utils.SendError(c, http.StatusBadRequest, "days must be between 1 and 90", err)
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		utils.SendError(c, http.StatusBadRequest, "limit must be between 1 and 100", err)
+		return
+	}
+
+	summary, err := h.searches.Summarize(c.Request.Context(), days, limit)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Search analytics retrieved", summary)
+}
+
+// Heatmap handles GET /api/v1/analytics/heatmap?bbox=west,south,east,north&metric=poi_density|avg_rating&resolution=50.
+// The longer edge of the box is split into resolution cells; empty cells are omitted.
+func (h *AnalyticsHandler) Heatmap(c *gin.Context) {
+	bbox, err := parseBBox(c.Query("bbox"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	metric := c.DefaultQuery("metric", models.HeatmapPOIDensity)
+	if metric != models.HeatmapPOIDensity && metric != models.HeatmapAvgRating {
+		utils.SendError(c, http.StatusBadRequest, "metric must be poi_density or avg_rating", nil)
+		return
+	}
+	resolution, err := strconv.Atoi(c.DefaultQuery("resolution", "50"))
+	if err != nil || resolution < 1 || resolution > 200 {
+		utils.SendError(c, http.StatusBadRequest, "resolution must be between 1 and 200", err)
+		return
+	}
+
+	cellSize := max(bbox.East-bbox.West, bbox.North-bbox.South) / float64(resolution)
+	heatmap, err := h.heatmaps.Heatmap(c.Request.Context(), bbox, metric, cellSize)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Heatmap retrieved", heatmap)
+}
+
+// parseBBox parses "west,south,east,north", the GeoJSON bounding box order
+func parseBBox(s string) (models.BBox, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return models.BBox{}, fmt.Errorf("bbox must be west,south,east,north")
+	}
+	var v [4]float64
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return models.BBox{}, fmt.Errorf("bbox must be west,south,east,north")
+		}
+		v[i] = f
+	}
+	b := models.BBox{West: v[0], South: v[1], East: v[2], North: v[3]}
+	if b.West >= b.East || b.South >= b.North || b.West < -180 || b.East > 180 || b.South < -90 || b.North > 90 {
+		return models.BBox{}, fmt.Errorf("bbox %q is not a valid west,south,east,north box", s)
+	}
+	return b, nil
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// AnnouncementRepository defines the data access needed for announcements
+type AnnouncementRepository interface {
+	ListActive(ctx context.Context, viewer repositories.AnnouncementViewer) ([]models.Announcement, error)
+	List(ctx context.Context, limit, offset int) ([]models.Announcement, error)
+	Create(ctx context.Context, a *models.Announcement) (*models.Announcement, error)
+	Update(ctx context.Context, a *models.Announcement) (*models.Announcement, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Dismiss(ctx context.Context, id, userID uuid.UUID) error
+}
+
+// AnnouncementHandler serves in-app announcements (admin routes require announcement:manage)
+type AnnouncementHandler struct {
+	repo AnnouncementRepository
+}
+
+// NewAnnouncementHandler creates a new announcement handler
+func NewAnnouncementHandler(repo AnnouncementRepository) *AnnouncementHandler {
+	return &AnnouncementHandler{repo: repo}
+}
+
+// AnnouncementRequest is the body for creating or replacing an announcement
+type AnnouncementRequest struct {
+	Title       string     `json:"title" binding:"required,max=255"`
+	Body        *string    `json:"body" binding:"omitempty,max=5000"`
+	LinkURL     *string    `json:"link_url" binding:"omitempty,url,max=2048"`
+	Severity    string     `json:"severity" binding:"omitempty,oneof=info warning critical"`
+	Audience    string     `json:"audience" binding:"omitempty,oneof=all signed_in signed_out"`
+	Roles       []string   `json:"roles" binding:"max=20,dive,required,max=50"`
+	Dismissible *bool      `json:"dismissible"`
+	StartsAt    *time.Time `json:"starts_at"` // Default now
+	EndsAt      *time.Time `json:"ends_at"`   // Default open-ended
+}
+
+// toModel validates the window and fills defaults
+func (req *AnnouncementRequest) toModel() (*models.Announcement, error) {
+	a := &models.Announcement{
+		Title:       req.Title,
+		Body:        req.Body,
+		LinkURL:     req.LinkURL,
+		Severity:    req.Severity,
+		Audience:    req.Audience,
+		Roles:       pq.StringArray(req.Roles),
+		Dismissible: req.Dismissible == nil || *req.Dismissible,
+		StartsAt:    time.Now(),
+		EndsAt:      req.EndsAt,
+	}
+	if a.Severity == "" {
+		a.Severity = "info"
+	}
+	if a.Audience == "" {
+		a.Audience = "all"
+	}
+	if a.Roles == nil {
+		a.Roles = pq.StringArray{}
+	}
+	if len(a.Roles) > 0 && a.Audience == "signed_out" {
+		return nil, errors.New("roles only apply to signed-in users")
+	}
+	if req.StartsAt != nil {
+		a.StartsAt = *req.StartsAt
+	}
+	if a.EndsAt != nil && !a.EndsAt.After(a.StartsAt) {
+		return nil, errors.New("ends_at must be after starts_at")
+	}
+	return a, nil
+}
+
+// ListActiveAnnouncements handles GET /api/v1/announcements/active. Signed-in
+// users do not get announcements they dismissed; signed-out clients keep their
+// own dismissals.
+func (h *AnnouncementHandler) ListActiveAnnouncements(c *gin.Context) {
+	var viewer repositories.AnnouncementViewer
+	if actor, ok := actorFromContext(c); ok {
+		viewer.UserID = &actor.UserID
+		viewer.Role = actor.Role
+	}
+
+	announcements, err := h.repo.ListActive(c.Request.Context(), viewer)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	if viewer.UserID != nil {
+		c.Header("Cache-Control", "private, no-store")
+	} else {
+		c.Header("Cache-Control", "public, max-age=60")
+	}
+	utils.SendSuccess(c, "Announcements retrieved", announcements)
+}
+
+// DismissAnnouncement handles POST /api/v1/announcements/:id/dismiss
+func (h *AnnouncementHandler) DismissAnnouncement(c *gin.Context) {
+	id, ok := parseAnnouncementID(c)
+	if !ok {
+		return
+	}
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	if err := h.repo.Dismiss(c.Request.Context(), id, actor.UserID); err != nil {
+		sendAnnouncementError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Announcement dismissed", gin.H{"announcement_id": id})
+}
+
+// ListAnnouncements handles GET /api/v1/admin/announcements, including
+// scheduled and expired ones
+func (h *AnnouncementHandler) ListAnnouncements(c *gin.Context) {
+	page, limit := utils.GetPagination(c)
+	offset := utils.GetOffset(page, limit)
+
+	announcements, err := h.repo.List(c.Request.Context(), limit, offset)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendPaginated(c, "Announcements retrieved", announcements, page, limit, len(announcements)+offset)
+}
+
+// CreateAnnouncement handles POST /api/v1/admin/announcements
+func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
+	var input AnnouncementRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	announcement, err := input.toModel()
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if actor, ok := actorFromContext(c); ok {
+		announcement.CreatedBy = &actor.UserID
+	}
+
+	created, err := h.repo.Create(c.Request.Context(), announcement)
+	if err != nil {
+		sendAnnouncementError(c, err)
+		return
+	}
+
+	utils.SendCreated(c, "Announcement created", created)
+}
+
+// UpdateAnnouncement handles PUT /api/v1/admin/announcements/:id
+func (h *AnnouncementHandler) UpdateAnnouncement(c *gin.Context) {
+	id, ok := parseAnnouncementID(c)
+	if !ok {
+		return
+	}
+
+	var input AnnouncementRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	announcement, err := input.toModel()
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	announcement.AnnouncementID = id
+
+	updated, err := h.repo.Update(c.Request.Context(), announcement)
+	if err != nil {
+		sendAnnouncementError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Announcement updated", updated)
+}
+
+// DeleteAnnouncement handles DELETE /api/v1/admin/announcements/:id
+func (h *AnnouncementHandler) DeleteAnnouncement(c *gin.Context) {
+	id, ok := parseAnnouncementID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Delete(c.Request.Context(), id); err != nil {
+		sendAnnouncementError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Announcement deleted", gin.H{"announcement_id": id})
+}
+
+func parseAnnouncementID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid announcement ID format", err)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// sendAnnouncementError maps announcement repository errors to HTTP responses
+func sendAnnouncementError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrAnnouncementNotFound):
+		utils.SendError(c, http.StatusNotFound, "announcement not found", err)
+	case errors.Is(err, repositories.ErrAnnouncementNotDismissible):
+		utils.SendError(c, http.StatusConflict, err.Error(), err)
+	default:
+		utils.SendInternalError(c, err)
+	}
+}
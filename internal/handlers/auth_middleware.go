@@ -3,14 +3,23 @@ package handlers
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"log/slog"
 	"net/http"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"golang.org/x/sync/singleflight"
 
 	"maukemana-backend/internal/auth"
 	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/services"
 	"maukemana-backend/internal/utils"
 )
 
@@ -22,9 +31,36 @@ type UserRepository interface {
 	Create(ctx context.Context, email, name, picture, clerkID, role string) (*repositories.User, error)
 }
 
+// authDuration records time spent authenticating a request, up to but not
+// including the handler
+var authDuration = newAuthDuration()
+
+func newAuthDuration() metric.Float64Histogram {
+	h, err := otel.Meter("maukemana-backend/internal/handlers").Float64Histogram("auth.duration",
+		metric.WithDescription("Token verification and user lookup latency"),
+		metric.WithUnit("s"))
+	if err != nil {
+		slog.Warn("failed to create auth duration histogram", "error", err)
+		return nil
+	}
+	return h
+}
+
+// UserInvalidator drops a user from the auth cache so a role or account
+// change applies on their next request
+type UserInvalidator interface {
+	Invalidate(userID uuid.UUID)
+}
+
+// PermissionLoader resolves the permissions granted to a role
+type PermissionLoader interface {
+	PermissionsForRole(ctx context.Context, role string) ([]string, error)
+}
+
 // AuthHandler handles authentication routes (Clerk integration mostly happens in middleware)
 type AuthHandler struct {
-	repo UserRepository
+	repo  UserRepository
+	terms TermsRepository
 }
 
 // NewAuthHandler creates a new auth handler
@@ -34,9 +70,24 @@ func NewAuthHandler(repo UserRepository) *AuthHandler {
 	}
 }
 
-// AuthMiddleware validates Clerk token and syncs user to DB
-func AuthMiddleware(repo UserRepository) gin.HandlerFunc {
+// UseTerms adds the accepted and current terms of service versions to GetMe
+func (h *AuthHandler) UseTerms(terms TermsRepository) {
+	h.terms = terms
+}
+
+// clerkSyncTimeout bounds the Clerk lookup and insert of a first sign-in
+const clerkSyncTimeout = 15 * time.Second
+
+// AuthMiddleware validates Clerk token, syncs user to DB and loads the
+// permissions of the user's role into the context under "permissions".
+// Pass a repositories.CachedUserRepository to keep warm requests off the DB.
+func AuthMiddleware(repo UserRepository, perms PermissionLoader) gin.HandlerFunc {
+	// Parallel first requests from a new user share one Clerk lookup and insert
+	var syncs singleflight.Group
+
 	return func(c *gin.Context) {
+		start := time.Now()
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			utils.SendError(c, http.StatusUnauthorized, "Unauthorized: missing token", nil)
@@ -45,7 +96,7 @@ func AuthMiddleware(repo UserRepository) gin.HandlerFunc {
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: invalid header format"})
+			utils.SendError(c, http.StatusUnauthorized, "Unauthorized: invalid header format", nil)
 			return
 		}
 
@@ -58,106 +109,126 @@ func AuthMiddleware(repo UserRepository) gin.HandlerFunc {
 
 		// Lazy Sync
 		clerkID := claims.Subject
-		var userEmail string
-		var userID uuid.UUID
-		var displayName sql.NullString
-		var dbRole sql.NullString
+		synced := false
 
 		// 1. Check if user exists by Clerk ID -- AND fetch role
 		user, err := repo.GetByClerkID(c.Request.Context(), clerkID)
-
-		if err == nil {
-			// Found user in DB
-			userID = user.UserID
-			userEmail = user.Email
-			displayName = user.Name
-			dbRole = user.Role
-		} else if err == sql.ErrNoRows {
+		if err == sql.ErrNoRows {
 			// 2. User NOT found by Clerk ID. We need to sync.
-			clerkUser, err := auth.GetUser(clerkID)
-			if err != nil {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Failed to fetch user info from Clerk"})
-				return
-			}
-
-			if len(clerkUser.EmailAddresses) == 0 {
-				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "User has no email address"})
-				return
-			}
-			primaryEmail := clerkUser.EmailAddresses[0].EmailAddress
-			userEmail = primaryEmail
-
-			var name string
-			if clerkUser.FirstName != nil {
-				name = *clerkUser.FirstName
-				if clerkUser.LastName != nil {
-					name += " " + *clerkUser.LastName
-				}
-			}
-			displayName = sql.NullString{String: name, Valid: name != ""}
-
-			// 3. Check if user exists by Email (Migrate legacy user)
-			legacyUser, err := repo.GetByEmail(c.Request.Context(), primaryEmail)
-
+			var v interface{}
+			// The sync is shared with every concurrent request for the user, so
+			// it must not be cancelled when the request that started it goes away
+			v, err, _ = syncs.Do(clerkID, func() (interface{}, error) {
+				ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), clerkSyncTimeout)
+				defer cancel()
+				return syncClerkUser(ctx, repo, clerkID)
+			})
 			if err == nil {
-				// Legacy user found, update with clerk_id
-				userID = legacyUser.UserID
-				err = repo.UpdateClerkID(c.Request.Context(), userID, clerkID)
-				if err != nil {
-					c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update legacy user"})
-					return
-				}
-				// Also fetch role/name if needed, but assuming legacy user has them.
-				// For now taking from legacyUser if we want, or just proceed.
-				// The original code didn't re-fetch legacy user details other than ID.
-				// But we need 'dbRole' for context.
-				dbRole = legacyUser.Role
-				if legacyUser.Name.Valid {
-					displayName = legacyUser.Name
-				}
-			} else if err == sql.ErrNoRows {
-				// 4. Create new user
-				var picture string
-				if clerkUser.ImageURL != nil {
-					picture = *clerkUser.ImageURL
-				}
-
-				newUser, err := repo.Create(c.Request.Context(), primaryEmail, name, picture, clerkID, "user")
-				if err != nil {
-					c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
-					return
-				}
-				userID = newUser.UserID
-				dbRole = newUser.Role
-			} else {
-				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+				user = v.(*repositories.User)
+				synced = true
+			}
+		}
+		if err != nil {
+			var se *syncError
+			if errors.As(err, &se) {
+				utils.SendError(c, se.status, se.message, nil)
 				return
 			}
-		} else {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			utils.SendInternalError(c, err)
 			return
 		}
 
 		// Set context
 		// Extract values from NullString with defaults
 		finalDisplayName := ""
-		if displayName.Valid {
-			finalDisplayName = displayName.String
+		if user.Name.Valid {
+			finalDisplayName = user.Name.String
 		}
 		finalRole := "user"
-		if dbRole.Valid && dbRole.String != "" {
-			finalRole = dbRole.String
+		if user.Role.Valid && user.Role.String != "" {
+			finalRole = user.Role.String
 		}
 
-		c.Set("user_id", userID)
-		c.Set("email", userEmail)
+		granted, err := perms.PermissionsForRole(c.Request.Context(), finalRole)
+		if err != nil {
+			utils.SendInternalError(c, err)
+			return
+		}
+
+		c.Set("user_id", user.UserID)
+		c.Set("clerk_id", clerkID)
+		c.Set("email", user.Email)
 		c.Set("display_name", finalDisplayName)
 		c.Set("user_role", finalRole)
+		c.Set("permissions", services.NewPermissionSet(granted))
+
+		if authDuration != nil {
+			authDuration.Record(c.Request.Context(), time.Since(start).Seconds(),
+				metric.WithAttributes(attribute.Bool("auth.synced", synced)))
+		}
 
 		c.Next()
 	}
 }
 
+// syncError carries the response for a failed first-time user sync
+type syncError struct {
+	status  int
+	message string
+}
+
+func (e *syncError) Error() string { return e.message }
+
+// syncClerkUser links or creates the local user for a Clerk ID seen for the
+// first time. Legacy users are matched by email.
+func syncClerkUser(ctx context.Context, repo UserRepository, clerkID string) (*repositories.User, error) {
+	clerkUser, err := auth.GetUser(clerkID)
+	if err != nil {
+		return nil, &syncError{http.StatusUnauthorized, "Failed to fetch user info from Clerk"}
+	}
+
+	if len(clerkUser.EmailAddresses) == 0 {
+		return nil, &syncError{http.StatusBadRequest, "User has no email address"}
+	}
+	primaryEmail := clerkUser.EmailAddresses[0].EmailAddress
+
+	var name string
+	if clerkUser.FirstName != nil {
+		name = *clerkUser.FirstName
+		if clerkUser.LastName != nil {
+			name += " " + *clerkUser.LastName
+		}
+	}
+
+	// 3. Check if user exists by Email (Migrate legacy user)
+	legacyUser, err := repo.GetByEmail(ctx, primaryEmail)
+	if err == nil {
+		// Legacy user found, update with clerk_id
+		if err := repo.UpdateClerkID(ctx, legacyUser.UserID, clerkID); err != nil {
+			return nil, &syncError{http.StatusInternalServerError, "Failed to update legacy user"}
+		}
+		if !legacyUser.Name.Valid {
+			legacyUser.Name = sql.NullString{String: name, Valid: name != ""}
+		}
+		return legacyUser, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	// 4. Create new user
+	var picture string
+	if clerkUser.ImageURL != nil {
+		picture = *clerkUser.ImageURL
+	}
+
+	newUser, err := repo.Create(ctx, primaryEmail, name, picture, clerkID, "user")
+	if err != nil {
+		return nil, &syncError{http.StatusInternalServerError, "Failed to create user"}
+	}
+	return newUser, nil
+}
+
 // GetMe returns the current user's info
 func (h *AuthHandler) GetMe(c *gin.Context) {
 	userID, _ := c.Get("user_id")
@@ -166,10 +237,51 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 	role, _ := c.Get("user_role")
 	// userID is uuid.UUID
 
-	utils.SendSuccess(c, "User profile retrieved", gin.H{
+	permissions := []services.Permission{}
+	if set, ok := c.Get("permissions"); ok {
+		for p := range set.(services.PermissionSet) {
+			permissions = append(permissions, p)
+		}
+	}
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i] < permissions[j] })
+
+	data := gin.H{
 		"user_id":      userID,
 		"email":        email,
 		"display_name": displayName,
 		"role":         role,
-	})
+		"permissions":  permissions,
+	}
+	if h.terms != nil {
+		accepted, err := h.terms.AcceptedVersion(c.Request.Context(), userID.(uuid.UUID))
+		if err != nil {
+			utils.SendInternalError(c, err)
+			return
+		}
+		current, err := h.terms.CurrentVersion(c.Request.Context())
+		if err != nil {
+			utils.SendInternalError(c, err)
+			return
+		}
+		data["tos_accepted_version"] = accepted
+		data["tos_current_version"] = nil
+		if current != nil {
+			data["tos_current_version"] = current.Version
+		}
+	}
+
+	utils.SendSuccess(c, "User profile retrieved", data)
+}
+
+// OptionalAuthMiddleware authenticates the caller when an Authorization header is
+// present and lets anonymous requests through. Invalid tokens are still rejected.
+func OptionalAuthMiddleware(repo UserRepository, perms PermissionLoader) gin.HandlerFunc {
+	required := AuthMiddleware(repo, perms)
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		required(c)
+	}
 }
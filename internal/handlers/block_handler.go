@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BlockRepository defines the data access needed for blocking users
+type BlockRepository interface {
+	Block(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	ListBlocked(ctx context.Context, blockerID uuid.UUID, limit, offset int) ([]models.BlockedUser, error)
+}
+
+// BlockHandler lets users block others. Blocked users' comments and reviews
+// are filtered out of every listing the blocker sees.
+type BlockHandler struct {
+	repo BlockRepository
+}
+
+// NewBlockHandler creates a new block handler
+func NewBlockHandler(repo BlockRepository) *BlockHandler {
+	return &BlockHandler{repo: repo}
+}
+
+// BlockUser handles POST /api/v1/users/:id/block
+func (h *BlockHandler) BlockUser(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	blockedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid user ID format", err)
+		return
+	}
+	if blockedID == actor.UserID {
+		utils.SendError(c, http.StatusBadRequest, "you cannot block yourself", nil)
+		return
+	}
+
+	err = h.repo.Block(c.Request.Context(), actor.UserID, blockedID)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		utils.SendError(c, http.StatusNotFound, "user not found", nil)
+		return
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "User blocked", gin.H{"user_id": blockedID})
+}
+
+// ListBlocks handles GET /api/v1/me/blocks
+func (h *BlockHandler) ListBlocks(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	page, limit := utils.GetPagination(c)
+	offset := utils.GetOffset(page, limit)
+	blocked, err := h.repo.ListBlocked(c.Request.Context(), actor.UserID, limit, offset)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendPaginated(c, "Blocked users retrieved", blocked, page, limit, len(blocked)+offset)
+}
+
+// UnblockUser handles DELETE /api/v1/me/blocks/:id
+func (h *BlockHandler) UnblockUser(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	blockedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid user ID format", err)
+		return
+	}
+
+	if err := h.repo.Unblock(c.Request.Context(), actor.UserID, blockedID); err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "User unblocked", nil)
+}
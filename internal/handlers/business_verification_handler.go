@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/services"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Proof documents: accepted types, size and how long reviewers' links last
+const (
+	verificationDocumentMaxBytes = 10 << 20
+	verificationDocumentURLTTL   = 10 * time.Minute
+)
+
+var verificationDocumentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// BusinessVerificationRepository defines the data access needed for business verification
+type BusinessVerificationRepository interface {
+	Create(ctx context.Context, poiID, requesterID uuid.UUID, businessName, note *string, documentKeys []string) (*models.BusinessVerification, error)
+	GetByID(ctx context.Context, verificationID uuid.UUID) (*models.BusinessVerification, error)
+	GetLatestForPOI(ctx context.Context, poiID uuid.UUID) (*models.BusinessVerification, error)
+	ListByStatus(ctx context.Context, status string, limit, offset int) ([]models.BusinessVerification, error)
+	Approve(ctx context.Context, verificationID, reviewerID uuid.UUID, reviewNote *string) (*models.BusinessVerification, error)
+	Reject(ctx context.Context, verificationID, reviewerID uuid.UUID, reviewNote *string) (*models.BusinessVerification, error)
+	Withdraw(ctx context.Context, verificationID, requesterID uuid.UUID) error
+}
+
+// DocumentStorage stores private documents behind presigned URLs
+type DocumentStorage interface {
+	GeneratePresignedURLWithMaxSize(ctx context.Context, key string, contentType string, maxSizeBytes int64) (string, error)
+	PresignGetURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// VerificationNotifier tells requesters the outcome of their verification
+type VerificationNotifier interface {
+	VerificationDecided(ctx context.Context, v *models.BusinessVerification) error
+}
+
+// BusinessVerificationHandler handles "verified business" requests by POI
+// owners and their review
+type BusinessVerificationHandler struct {
+	repo     BusinessVerificationRepository
+	poiRepo  POIRepository
+	docs     DocumentStorage      // nil when R2 is not configured
+	notifier VerificationNotifier // nil when email is not configured
+}
+
+// NewBusinessVerificationHandler creates a new business verification handler
+func NewBusinessVerificationHandler(repo BusinessVerificationRepository, poiRepo POIRepository) *BusinessVerificationHandler {
+	return &BusinessVerificationHandler{repo: repo, poiRepo: poiRepo}
+}
+
+// UseDocumentStorage enables proof document uploads
+func (h *BusinessVerificationHandler) UseDocumentStorage(docs DocumentStorage) {
+	h.docs = docs
+}
+
+// UseNotifier emails requesters when their verification is decided
+func (h *BusinessVerificationHandler) UseNotifier(n VerificationNotifier) {
+	h.notifier = n
+}
+
+// PresignDocumentRequest is the body for POST /api/v1/pois/:id/verification/documents
+type PresignDocumentRequest struct {
+	Filename    string `json:"filename" binding:"max=255"`
+	ContentType string `json:"content_type" binding:"required"`
+	SizeBytes   int64  `json:"size_bytes" binding:"required,min=1"`
+}
+
+// RequestVerificationRequest is the body for POST /api/v1/pois/:id/verification
+type RequestVerificationRequest struct {
+	BusinessName *string  `json:"business_name" binding:"omitempty,max=255"`
+	Note         *string  `json:"note" binding:"omitempty,max=2000"`
+	DocumentKeys []string `json:"document_keys" binding:"required,min=1,max=10,dive,required"`
+}
+
+// ReviewVerificationRequest is the optional body for approving or rejecting a request
+type ReviewVerificationRequest struct {
+	Note *string `json:"note"`
+}
+
+// documentPrefix is where the proof documents of a requester for a POI live
+func documentPrefix(poiID, userID uuid.UUID) string {
+	return fmt.Sprintf("documents/verification/%s/%s/", poiID, userID)
+}
+
+// PresignDocument handles POST /api/v1/pois/:id/verification/documents (owner).
+// The returned key is passed to RequestVerification once uploaded.
+func (h *BusinessVerificationHandler) PresignDocument(c *gin.Context) {
+	if h.docs == nil {
+		utils.SendError(c, http.StatusServiceUnavailable, "document storage is not configured", nil)
+		return
+	}
+	poi, actor, ok := h.loadOwnedPOI(c)
+	if !ok {
+		return
+	}
+
+	var req PresignDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	ext, allowed := verificationDocumentTypes[req.ContentType]
+	if !allowed {
+		utils.SendError(c, http.StatusBadRequest, "invalid content type, allowed: application/pdf, image/jpeg, image/png", nil)
+		return
+	}
+	if req.SizeBytes > verificationDocumentMaxBytes {
+		utils.SendError(c, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("file size %d exceeds maximum %d bytes for documents", req.SizeBytes, verificationDocumentMaxBytes), nil)
+		return
+	}
+	if e := strings.ToLower(filepath.Ext(req.Filename)); e == ".pdf" || e == ".png" || e == ".jpg" || e == ".jpeg" {
+		ext = e
+	}
+
+	key := documentPrefix(poi.PoiID, actor.UserID) + uuid.NewString() + ext
+	uploadURL, err := h.docs.GeneratePresignedURLWithMaxSize(c.Request.Context(), key, req.ContentType, req.SizeBytes)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Presigned URL generated", gin.H{
+		"key":               key,
+		"upload_url":        uploadURL,
+		"upload_expires_at": time.Now().Add(15 * time.Minute).Format(time.RFC3339),
+		"max_size_bytes":    verificationDocumentMaxBytes,
+	})
+}
+
+// RequestVerification handles POST /api/v1/pois/:id/verification (owner)
+func (h *BusinessVerificationHandler) RequestVerification(c *gin.Context) {
+	poi, actor, ok := h.loadOwnedPOI(c)
+	if !ok {
+		return
+	}
+
+	var req RequestVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	prefix := documentPrefix(poi.PoiID, actor.UserID)
+	for _, key := range req.DocumentKeys {
+		if !strings.HasPrefix(key, prefix) || strings.Contains(key, "..") {
+			utils.SendError(c, http.StatusForbidden, "document was not uploaded for this verification", nil)
+			return
+		}
+	}
+
+	verification, err := h.repo.Create(c.Request.Context(), poi.PoiID, actor.UserID, req.BusinessName, req.Note, req.DocumentKeys)
+	if err != nil {
+		sendVerificationError(c, err)
+		return
+	}
+
+	utils.SendCreated(c, "Verification requested", verification)
+}
+
+// GetVerification handles GET /api/v1/pois/:id/verification (owner or poi:verify)
+func (h *BusinessVerificationHandler) GetVerification(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	poi, err := h.poiRepo.GetByID(ctx, poiID)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return
+	}
+	if !actor.Can(services.PermPOIVerify) && !isPOIOwner(poi, actor.UserID) {
+		utils.SendError(c, http.StatusForbidden, "only the POI owner can view its verification", nil)
+		return
+	}
+
+	verification, err := h.repo.GetLatestForPOI(ctx, poiID)
+	if err != nil {
+		sendVerificationError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Verification retrieved", verification)
+}
+
+// WithdrawVerification handles DELETE /api/v1/pois/:id/verification/:verification_id (requester only)
+func (h *BusinessVerificationHandler) WithdrawVerification(c *gin.Context) {
+	verificationID, err := uuid.Parse(c.Param("verification_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid verification ID format", err)
+		return
+	}
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	if err := h.repo.Withdraw(c.Request.Context(), verificationID, actor.UserID); err != nil {
+		if errors.Is(err, repositories.ErrVerificationNotPending) {
+			utils.SendError(c, http.StatusConflict, "verification not found or already reviewed", err)
+			return
+		}
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Verification withdrawn", gin.H{"verification_id": verificationID})
+}
+
+// ListVerifications handles GET /api/v1/admin/verifications?status= (requires poi:verify)
+func (h *BusinessVerificationHandler) ListVerifications(c *gin.Context) {
+	status := c.DefaultQuery("status", "pending")
+	page, limit := utils.GetPagination(c)
+	offset := utils.GetOffset(page, limit)
+
+	verifications, err := h.repo.ListByStatus(c.Request.Context(), status, limit, offset)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendPaginated(c, "Verifications retrieved", verifications, page, limit, len(verifications)+offset)
+}
+
+// ReviewVerification handles GET /api/v1/admin/verifications/:id (requires
+// poi:verify), including short-lived links to the proof documents
+func (h *BusinessVerificationHandler) ReviewVerification(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	verificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid verification ID format", err)
+		return
+	}
+	verification, err := h.repo.GetByID(ctx, verificationID)
+	if err != nil {
+		sendVerificationError(c, err)
+		return
+	}
+
+	if h.docs != nil {
+		for _, key := range verification.DocumentKeys {
+			url, err := h.docs.PresignGetURL(ctx, key, verificationDocumentURLTTL)
+			if err != nil {
+				slog.WarnContext(ctx, "failed to sign verification document", "key", key, "error", err)
+				continue
+			}
+			verification.DocumentURLs = append(verification.DocumentURLs, url)
+		}
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	utils.SendSuccess(c, "Verification retrieved", verification)
+}
+
+// ApproveVerification handles POST /api/v1/admin/verifications/:id/approve (requires poi:verify)
+func (h *BusinessVerificationHandler) ApproveVerification(c *gin.Context) {
+	verificationID, reviewer, note, ok := h.loadForReview(c)
+	if !ok {
+		return
+	}
+
+	verification, err := h.repo.Approve(c.Request.Context(), verificationID, reviewer.UserID, note)
+	if err != nil {
+		sendVerificationError(c, err)
+		return
+	}
+
+	h.notifyDecision(c.Request.Context(), verification)
+	utils.SendSuccess(c, "Verification approved", verification)
+}
+
+// RejectVerification handles POST /api/v1/admin/verifications/:id/reject (requires poi:verify)
+func (h *BusinessVerificationHandler) RejectVerification(c *gin.Context) {
+	verificationID, reviewer, note, ok := h.loadForReview(c)
+	if !ok {
+		return
+	}
+
+	verification, err := h.repo.Reject(c.Request.Context(), verificationID, reviewer.UserID, note)
+	if err != nil {
+		sendVerificationError(c, err)
+		return
+	}
+
+	h.notifyDecision(c.Request.Context(), verification)
+	utils.SendSuccess(c, "Verification rejected", verification)
+}
+
+// notifyDecision emails the requester; failures never undo the decision
+func (h *BusinessVerificationHandler) notifyDecision(ctx context.Context, verification *models.BusinessVerification) {
+	if h.notifier == nil {
+		return
+	}
+	if err := h.notifier.VerificationDecided(ctx, verification); err != nil {
+		slog.WarnContext(ctx, "failed to notify verification decision", "verification_id", verification.VerificationID, "error", err)
+	}
+}
+
+// loadOwnedPOI resolves the POI in the URL and checks the caller owns it.
+// It writes the error response itself and returns ok=false on failure.
+func (h *BusinessVerificationHandler) loadOwnedPOI(c *gin.Context) (*repositories.POI, services.Actor, bool) {
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return nil, services.Actor{}, false
+	}
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return nil, services.Actor{}, false
+	}
+	poi, err := h.poiRepo.GetByID(c.Request.Context(), poiID)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return nil, services.Actor{}, false
+	}
+	if !isPOIOwner(poi, actor.UserID) {
+		utils.SendError(c, http.StatusForbidden, "only the POI owner can request verification", nil)
+		return nil, services.Actor{}, false
+	}
+	return poi, actor, true
+}
+
+// loadForReview parses the request ID and optional review note
+func (h *BusinessVerificationHandler) loadForReview(c *gin.Context) (uuid.UUID, services.Actor, *string, bool) {
+	verificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid verification ID format", err)
+		return uuid.Nil, services.Actor{}, nil, false
+	}
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return uuid.Nil, services.Actor{}, nil, false
+	}
+
+	var input ReviewVerificationRequest
+	// Body is optional
+	_ = c.ShouldBindJSON(&input)
+
+	return verificationID, actor, input.Note, true
+}
+
+// sendVerificationError maps business verification errors to HTTP responses
+func sendVerificationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrVerificationNotFound):
+		utils.SendError(c, http.StatusNotFound, "business verification not found", err)
+	case errors.Is(err, repositories.ErrVerificationNotPending):
+		utils.SendError(c, http.StatusConflict, "business verification already reviewed", err)
+	case errors.Is(err, repositories.ErrVerificationOpen), errors.Is(err, repositories.ErrAlreadyVerified):
+		utils.SendError(c, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, sql.ErrNoRows):
+		utils.SendError(c, http.StatusNotFound, "POI not found", nil)
+	default:
+		utils.SendInternalError(c, err)
+	}
+}
@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"context"
-	"net/http"
 
 	"github.com/gin-gonic/gin"
 
@@ -12,7 +11,7 @@ import (
 
 // CategoryRepository defines the interface for category data access
 type CategoryRepository interface {
-	GetAll(ctx context.Context) ([]repositories.Category, error)
+	GetAll(ctx context.Context, locales []string) ([]repositories.Category, error)
 }
 
 // CategoryHandler handles category-related HTTP requests
@@ -25,20 +24,22 @@ func NewCategoryHandler(repo CategoryRepository) *CategoryHandler {
 	return &CategoryHandler{repo: repo}
 }
 
-// GetCategories handles GET /api/v1/categories
+// GetCategories handles GET /api/v1/categories (labels follow ?lang= / Accept-Language)
 func (h *CategoryHandler) GetCategories(c *gin.Context) {
-	categories, err := h.repo.GetAll(c.Request.Context())
+	categories, err := h.repo.GetAll(c.Request.Context(), labelLocales(c))
 	if err != nil {
 		utils.SendInternalError(c, err)
 		return
 	}
 
+	c.Header("Content-Language", c.GetString("locale"))
 	utils.SendSuccess(c, "Categories retrieved", gin.H{"data": categories})
 }
 
 // VocabularyRepository defines the interface for vocabulary data access
 type VocabularyRepository interface {
-	GetActive(ctx context.Context, vocabType string) ([]repositories.Vocabulary, error)
+	GetActive(ctx context.Context, vocabType string, locales []string) ([]repositories.Vocabulary, error)
+	GetAmenities(ctx context.Context, locales []string) ([]repositories.Amenity, error)
 }
 
 // VocabularyHandler handles vocabulary-related HTTP requests
@@ -51,15 +52,42 @@ func NewVocabularyHandler(repo VocabularyRepository) *VocabularyHandler {
 	return &VocabularyHandler{repo: repo}
 }
 
-// GetVocabularies handles GET /api/v1/vocabularies
+// GetVocabularies handles GET /api/v1/vocabularies (labels follow ?lang= / Accept-Language)
 func (h *VocabularyHandler) GetVocabularies(c *gin.Context) {
 	vocabType := c.Query("type")
 
-	vocabularies, err := h.repo.GetActive(c.Request.Context(), vocabType)
+	vocabularies, err := h.repo.GetActive(c.Request.Context(), vocabType, labelLocales(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	c.Header("Content-Language", c.GetString("locale"))
+	utils.SendSuccess(c, "Vocabularies retrieved", vocabularies)
+}
+
+// GetAmenities handles GET /api/v1/amenities, the keys POIs are tagged and
+// searched with (labels follow ?lang= / Accept-Language)
+func (h *VocabularyHandler) GetAmenities(c *gin.Context) {
+	amenities, err := h.repo.GetAmenities(c.Request.Context(), labelLocales(c))
+	if err != nil {
+		utils.SendInternalError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": vocabularies})
+	c.Header("Content-Language", c.GetString("locale"))
+	utils.SendSuccess(c, "Amenities retrieved", amenities)
+}
+
+// labelLocales returns the locales to try for display labels, in preference order:
+// the negotiated locale, then the default locale
+func labelLocales(c *gin.Context) []string {
+	locales := []string{}
+	if l := c.GetString("locale"); l != "" {
+		locales = append(locales, l)
+	}
+	if def := c.GetString("default_locale"); def != "" && (len(locales) == 0 || locales[0] != def) {
+		locales = append(locales, def)
+	}
+	return locales
 }
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/spam"
+	"maukemana-backend/internal/textmod"
+	"maukemana-backend/internal/utils"
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -13,19 +15,31 @@ import (
 
 type CommentRepository interface {
 	Create(ctx context.Context, comment *models.Comment) error
-	GetByPOI(ctx context.Context, poiID uuid.UUID, limit, offset int) ([]models.Comment, error)
-	GetReplies(ctx context.Context, parentID uuid.UUID) ([]models.Comment, error)
+	GetByPOI(ctx context.Context, poiID uuid.UUID, viewerID *uuid.UUID, limit, offset int) ([]models.Comment, error)
+	GetReplies(ctx context.Context, parentID uuid.UUID, viewerID *uuid.UUID) ([]models.Comment, error)
 	Delete(ctx context.Context, commentID uuid.UUID, userID uuid.UUID) error
 }
 
 type CommentHandler struct {
 	commentRepo CommentRepository
+	spam        SpamGuard
+	textMod     TextModerator
 }
 
 func NewCommentHandler(commentRepo CommentRepository) *CommentHandler {
 	return &CommentHandler{commentRepo: commentRepo}
 }
 
+// UseSpamGuard screens comments with the anti-spam heuristics before they are saved
+func (h *CommentHandler) UseSpamGuard(g SpamGuard) {
+	h.spam = g
+}
+
+// UseTextModerator masks profanity and contact details in comments before they are saved
+func (h *CommentHandler) UseTextModerator(m TextModerator) {
+	h.textMod = m
+}
+
 // Helper to get user ID from context
 func getUserID(c *gin.Context) (uuid.UUID, error) {
 	userIDStr, exists := c.Get("user_id")
@@ -56,19 +70,33 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 	poiIDStr := c.Param("id")
 	poiID, err := uuid.Parse(poiIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid POI ID"})
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
 		return
 	}
 
 	userID, err := getUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
 
 	var input CreateCommentInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.SendValidationError(c, err)
+		return
+	}
+
+	if h.spam != nil {
+		if err := h.spam.Check(c.Request.Context(), userID, spam.KindComment, poiID, input.Content); err != nil {
+			if !sendSpamError(c, err) {
+				utils.SendInternalError(c, err)
+			}
+			return
+		}
+	}
+
+	moderation, ok := moderateText(c, h.textMod, textmod.FieldComment, &input.Content)
+	if !ok {
 		return
 	}
 
@@ -80,63 +108,63 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 	}
 
 	if err := h.commentRepo.Create(c.Request.Context(), comment); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
+		utils.SendInternalError(c, err)
 		return
 	}
+	recordModeration(c.Request.Context(), h.textMod, comment.CommentID, &userID, moderation)
 
-	c.JSON(http.StatusCreated, comment)
+	utils.SendCreated(c, "Comment created", comment)
 }
 
 func (h *CommentHandler) GetCommentsByPOI(c *gin.Context) {
 	poiIDStr := c.Param("id")
 	poiID, err := uuid.Parse(poiIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid POI ID"})
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
 		return
 	}
 
-	limit := 50
-	offset := 0
+	page, limit := utils.GetPagination(c)
+	offset := utils.GetOffset(page, limit)
 
-	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
-		limit = l
-	}
-	if o, err := strconv.Atoi(c.Query("offset")); err == nil && o >= 0 {
-		offset = o
+	// Shadow-banned users still see their own comments
+	var viewerID *uuid.UUID
+	if userID, err := getUserID(c); err == nil {
+		viewerID = &userID
 	}
 
-	comments, err := h.commentRepo.GetByPOI(c.Request.Context(), poiID, limit, offset)
+	comments, err := h.commentRepo.GetByPOI(c.Request.Context(), poiID, viewerID, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		utils.SendInternalError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, comments)
+	utils.SendPaginated(c, "Comments retrieved", comments, page, limit, len(comments)+offset)
 }
 
 func (h *CommentHandler) DeleteComment(c *gin.Context) {
 	commentIDStr := c.Param("id")
 	commentID, err := uuid.Parse(commentIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Comment ID"})
+		utils.SendError(c, http.StatusBadRequest, "invalid comment ID format", err)
 		return
 	}
 
 	userID, err := getUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
 
 	err = h.commentRepo.Delete(c.Request.Context(), commentID, userID)
 	if err != nil {
 		if err.Error() == "not found" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Not found or permission denied"})
+			utils.SendError(c, http.StatusForbidden, "comment not found or permission denied", nil)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+		utils.SendInternalError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted"})
+	utils.SendSuccess(c, "Comment deleted", gin.H{"comment_id": commentID})
 }
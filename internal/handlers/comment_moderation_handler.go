@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CommentModerationRepository defines the moderator actions on comments
+type CommentModerationRepository interface {
+	HideComment(ctx context.Context, commentID, actorID uuid.UUID, reason string) (*models.Comment, error)
+	DeleteAnyComment(ctx context.Context, commentID, actorID uuid.UUID, reason string) error
+	DeleteCommentsByUser(ctx context.Context, userID, actorID uuid.UUID, reason string) (int, error)
+	ListFlagged(ctx context.Context, limit, offset int) ([]models.FlaggedComment, error)
+}
+
+// CommentModerationHandler lets moderators hide and delete any comment
+// (requires comment:moderate). Every action is written to the audit log.
+type CommentModerationHandler struct {
+	repo CommentModerationRepository
+}
+
+// NewCommentModerationHandler creates a new comment moderation handler
+func NewCommentModerationHandler(repo CommentModerationRepository) *CommentModerationHandler {
+	return &CommentModerationHandler{repo: repo}
+}
+
+// ModerationReasonRequest is the body of the comment moderation actions
+type ModerationReasonRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// bindModeration reads the acting moderator and the reason for an action. It
+// reports false after sending an error response.
+func bindModeration(c *gin.Context) (uuid.UUID, string, bool) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return uuid.Nil, "", false
+	}
+	var input ModerationReasonRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return uuid.Nil, "", false
+	}
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		utils.SendError(c, http.StatusBadRequest, "reason is required", nil)
+		return uuid.Nil, "", false
+	}
+	return actor.UserID, reason, true
+}
+
+// ListFlagged handles GET /api/v1/admin/comments/flagged
+func (h *CommentModerationHandler) ListFlagged(c *gin.Context) {
+	page, limit := utils.GetPagination(c)
+	offset := utils.GetOffset(page, limit)
+	comments, err := h.repo.ListFlagged(c.Request.Context(), limit, offset)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendPaginated(c, "Flagged comments retrieved", comments, page, limit, len(comments)+offset)
+}
+
+// HideComment handles POST /api/v1/admin/comments/:id/hide
+func (h *CommentModerationHandler) HideComment(c *gin.Context) {
+	commentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid comment ID format", err)
+		return
+	}
+	actorID, reason, ok := bindModeration(c)
+	if !ok {
+		return
+	}
+
+	comment, err := h.repo.HideComment(c.Request.Context(), commentID, actorID, reason)
+	if errors.Is(err, repositories.ErrCommentNotFound) {
+		utils.SendError(c, http.StatusNotFound, "comment not found", nil)
+		return
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Comment hidden", comment)
+}
+
+// DeleteComment handles DELETE /api/v1/admin/comments/:id; replies are deleted with it
+func (h *CommentModerationHandler) DeleteComment(c *gin.Context) {
+	commentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid comment ID format", err)
+		return
+	}
+	actorID, reason, ok := bindModeration(c)
+	if !ok {
+		return
+	}
+
+	err = h.repo.DeleteAnyComment(c.Request.Context(), commentID, actorID, reason)
+	if errors.Is(err, repositories.ErrCommentNotFound) {
+		utils.SendError(c, http.StatusNotFound, "comment not found", nil)
+		return
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Comment deleted", nil)
+}
+
+// DeleteUserComments handles DELETE /api/v1/admin/users/:id/comments. Only the
+// comments of a banned user can be purged in bulk.
+func (h *CommentModerationHandler) DeleteUserComments(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid user ID format", err)
+		return
+	}
+	actorID, reason, ok := bindModeration(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.repo.DeleteCommentsByUser(c.Request.Context(), userID, actorID, reason)
+	switch {
+	case errors.Is(err, repositories.ErrUserNotFound):
+		utils.SendError(c, http.StatusNotFound, "user not found", nil)
+		return
+	case errors.Is(err, repositories.ErrUserNotBanned):
+		utils.SendError(c, http.StatusConflict, "only the comments of a banned user can be deleted in bulk", nil)
+		return
+	case err != nil:
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "User comments deleted", gin.H{"deleted": deleted})
+}
@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/services"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// proposalAcceptedXP is awarded to a proposer when their edit is accepted
+const proposalAcceptedXP = 25
+
+// EditProposalRepository defines the data access needed for community edit proposals
+type EditProposalRepository interface {
+	Create(ctx context.Context, poiID, proposerID uuid.UUID, changes map[string]json.RawMessage, note *string) (*models.EditProposal, error)
+	GetByID(ctx context.Context, proposalID uuid.UUID) (*models.EditProposal, error)
+	ListByPOI(ctx context.Context, poiID uuid.UUID, status string, limit, offset int) ([]models.EditProposal, error)
+	ListByStatus(ctx context.Context, status string, limit, offset int) ([]models.EditProposal, error)
+	ListByProposer(ctx context.Context, proposerID uuid.UUID, limit, offset int) ([]models.EditProposal, error)
+	Accept(ctx context.Context, proposalID, reviewerID uuid.UUID, reviewNote *string, xp int) (*models.EditProposal, error)
+	Reject(ctx context.Context, proposalID, reviewerID uuid.UUID, reviewNote *string) (*models.EditProposal, error)
+	Withdraw(ctx context.Context, proposalID, proposerID uuid.UUID) error
+}
+
+// EditProposalHandler handles community edit proposals for POIs
+type EditProposalHandler struct {
+	repo    EditProposalRepository
+	poiRepo POIRepository
+}
+
+// NewEditProposalHandler creates a new edit proposal handler
+func NewEditProposalHandler(repo EditProposalRepository, poiRepo POIRepository) *EditProposalHandler {
+	return &EditProposalHandler{repo: repo, poiRepo: poiRepo}
+}
+
+// CreateProposalRequest is the body for POST /api/v1/pois/:id/proposals
+type CreateProposalRequest struct {
+	Changes map[string]json.RawMessage `json:"changes" binding:"required"`
+	Note    *string                    `json:"note"`
+}
+
+// ReviewProposalRequest is the body for accepting or rejecting a proposal
+type ReviewProposalRequest struct {
+	Note *string `json:"note"`
+}
+
+// CreateProposal handles POST /api/v1/pois/:id/proposals
+func (h *EditProposalHandler) CreateProposal(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	poi, err := h.poiRepo.GetByID(ctx, poiID)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return
+	}
+	if poi.Status != "approved" {
+		utils.SendError(c, http.StatusConflict, "edits can only be proposed for published POIs", nil)
+		return
+	}
+	if isPOIOwner(poi, actor.UserID) {
+		utils.SendError(c, http.StatusConflict, "owners can edit their POI directly", nil)
+		return
+	}
+
+	var input CreateProposalRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+
+	proposal, err := h.repo.Create(ctx, poiID, actor.UserID, input.Changes, input.Note)
+	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidProposal) {
+			utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.SendError(c, http.StatusNotFound, "POI not found", err)
+			return
+		}
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendCreated(c, "Edit proposal submitted", proposal)
+}
+
+// GetProposals handles GET /api/v1/pois/:id/proposals?status=
+func (h *EditProposalHandler) GetProposals(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+
+	page, limit := utils.GetPagination(c)
+	offset := utils.GetOffset(page, limit)
+
+	proposals, err := h.repo.ListByPOI(ctx, poiID, c.Query("status"), limit, offset)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendPaginated(c, "Edit proposals retrieved", proposals, page, limit, len(proposals)+offset)
+}
+
+// GetMyProposals handles GET /api/v1/pois/my-proposals
+func (h *EditProposalHandler) GetMyProposals(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	page, limit := utils.GetPagination(c)
+	offset := utils.GetOffset(page, limit)
+
+	proposals, err := h.repo.ListByProposer(ctx, actor.UserID, limit, offset)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendPaginated(c, "Edit proposals retrieved", proposals, page, limit, len(proposals)+offset)
+}
+
+// GetPendingProposals handles GET /api/v1/admin/proposals?status= (requires poi:merge)
+func (h *EditProposalHandler) GetPendingProposals(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	status := c.DefaultQuery("status", "pending")
+	page, limit := utils.GetPagination(c)
+	offset := utils.GetOffset(page, limit)
+
+	proposals, err := h.repo.ListByStatus(ctx, status, limit, offset)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendPaginated(c, "Edit proposals retrieved", proposals, page, limit, len(proposals)+offset)
+}
+
+// AcceptProposal handles POST /api/v1/pois/:id/proposals/:proposal_id/accept (owner or poi:merge)
+func (h *EditProposalHandler) AcceptProposal(c *gin.Context) {
+	proposal, reviewer, note, ok := h.loadForReview(c)
+	if !ok {
+		return
+	}
+
+	updated, err := h.repo.Accept(c.Request.Context(), proposal.ProposalID, reviewer.UserID, note, proposalAcceptedXP)
+	if err != nil {
+		sendProposalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Edit proposal accepted", updated)
+}
+
+// RejectProposal handles POST /api/v1/pois/:id/proposals/:proposal_id/reject (owner or poi:merge)
+func (h *EditProposalHandler) RejectProposal(c *gin.Context) {
+	proposal, reviewer, note, ok := h.loadForReview(c)
+	if !ok {
+		return
+	}
+
+	updated, err := h.repo.Reject(c.Request.Context(), proposal.ProposalID, reviewer.UserID, note)
+	if err != nil {
+		sendProposalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Edit proposal rejected", updated)
+}
+
+// WithdrawProposal handles DELETE /api/v1/pois/:id/proposals/:proposal_id (proposer only)
+func (h *EditProposalHandler) WithdrawProposal(c *gin.Context) {
+	proposalID, err := uuid.Parse(c.Param("proposal_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid proposal ID format", err)
+		return
+	}
+
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	if err := h.repo.Withdraw(c.Request.Context(), proposalID, actor.UserID); err != nil {
+		if errors.Is(err, repositories.ErrProposalNotPending) {
+			utils.SendError(c, http.StatusConflict, "proposal not found or already reviewed", err)
+			return
+		}
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Edit proposal withdrawn", gin.H{"proposal_id": proposalID})
+}
+
+// loadForReview resolves the proposal in the URL and checks the caller may review it.
+// It writes the error response itself and returns ok=false on failure.
+func (h *EditProposalHandler) loadForReview(c *gin.Context) (*models.EditProposal, services.Actor, *string, bool) {
+	ctx := c.Request.Context()
+
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return nil, services.Actor{}, nil, false
+	}
+	proposalID, err := uuid.Parse(c.Param("proposal_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid proposal ID format", err)
+		return nil, services.Actor{}, nil, false
+	}
+
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return nil, services.Actor{}, nil, false
+	}
+
+	proposal, err := h.repo.GetByID(ctx, proposalID)
+	if err != nil || proposal.PoiID != poiID {
+		sendProposalError(c, repositories.ErrProposalNotFound)
+		return nil, services.Actor{}, nil, false
+	}
+
+	poi, err := h.poiRepo.GetByID(ctx, poiID)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return nil, services.Actor{}, nil, false
+	}
+	if !actor.Can(services.PermPOIMerge) && !isPOIOwner(poi, actor.UserID) {
+		utils.SendError(c, http.StatusForbidden, "only the POI owner or a moderator can review proposals", nil)
+		return nil, services.Actor{}, nil, false
+	}
+
+	var input ReviewProposalRequest
+	// Body is optional
+	_ = c.ShouldBindJSON(&input)
+
+	return proposal, actor, input.Note, true
+}
+
+// isPOIOwner reports whether userID created or founded the POI
+func isPOIOwner(poi *repositories.POI, userID uuid.UUID) bool {
+	return (poi.CreatedBy != nil && *poi.CreatedBy == userID) ||
+		(poi.FoundingUserID != nil && *poi.FoundingUserID == userID)
+}
+
+// sendProposalError maps edit proposal errors to HTTP responses
+func sendProposalError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrProposalNotFound):
+		utils.SendError(c, http.StatusNotFound, "edit proposal not found", err)
+	case errors.Is(err, repositories.ErrProposalNotPending):
+		utils.SendError(c, http.StatusConflict, "edit proposal already reviewed", err)
+	case errors.Is(err, repositories.ErrInvalidProposal):
+		utils.SendError(c, http.StatusUnprocessableEntity, err.Error(), nil)
+	default:
+		utils.SendInternalError(c, err)
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// EmailPreferenceRepository defines the data access needed for email preferences
+type EmailPreferenceRepository interface {
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*models.EmailPreferences, error)
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, input repositories.UpdateEmailPreferencesInput) (*models.EmailPreferences, error)
+	Unsubscribe(ctx context.Context, userID uuid.UUID, category string) error
+}
+
+// UnsubscribeTokens verifies the signed unsubscribe links sent in emails
+type UnsubscribeTokens interface {
+	Verify(token string) (uuid.UUID, string, error)
+}
+
+// EmailHandler serves email preferences and unsubscribe links
+type EmailHandler struct {
+	repo   EmailPreferenceRepository
+	tokens UnsubscribeTokens // nil when no signing secret is configured
+}
+
+// NewEmailHandler creates a new email handler
+func NewEmailHandler(repo EmailPreferenceRepository, tokens UnsubscribeTokens) *EmailHandler {
+	return &EmailHandler{repo: repo, tokens: tokens}
+}
+
+// GetPreferences handles GET /api/v1/me/email-preferences
+func (h *EmailHandler) GetPreferences(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	prefs, err := h.repo.GetPreferences(c.Request.Context(), actor.UserID)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Email preferences retrieved", prefs)
+}
+
+// UpdatePreferences handles PUT /api/v1/me/email-preferences; omitted categories are kept
+func (h *EmailHandler) UpdatePreferences(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var input repositories.UpdateEmailPreferencesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+
+	prefs, err := h.repo.UpdatePreferences(c.Request.Context(), actor.UserID, input)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Email preferences updated", prefs)
+}
+
+// Unsubscribe handles GET and POST /api/v1/email/unsubscribe?token=. The token
+// from an email link turns off that email's category without signing in; POST
+// is the RFC 8058 one-click unsubscribe mail clients send.
+func (h *EmailHandler) Unsubscribe(c *gin.Context) {
+	if h.tokens == nil {
+		utils.SendError(c, http.StatusServiceUnavailable, "unsubscribe links are not configured", nil)
+		return
+	}
+
+	userID, category, err := h.tokens.Verify(c.Query("token"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid or expired unsubscribe link", err)
+		return
+	}
+
+	if err := h.repo.Unsubscribe(c.Request.Context(), userID, category); err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Unsubscribed", gin.H{"category": category})
+}
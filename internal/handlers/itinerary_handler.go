@@ -0,0 +1,625 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/services"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ItineraryRepository defines the data access needed for itineraries and collaboration
+type ItineraryRepository interface {
+	ListForUser(ctx context.Context, userID uuid.UUID) ([]models.Itinerary, error)
+	GetForUser(ctx context.Context, id, userID uuid.UUID) (*models.Itinerary, error)
+	GetByShareToken(ctx context.Context, token string) (*models.Itinerary, error)
+	Create(ctx context.Context, it *models.Itinerary) (*models.Itinerary, error)
+	Update(ctx context.Context, it *models.Itinerary) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	SetShareToken(ctx context.Context, id uuid.UUID, token *string) error
+
+	ListItems(ctx context.Context, itineraryID uuid.UUID) ([]models.ItineraryItem, error)
+	CreateItem(ctx context.Context, item *models.ItineraryItem, userID uuid.UUID) (*models.ItineraryItem, error)
+	UpdateItem(ctx context.Context, item *models.ItineraryItem, expectedVersion int, userID uuid.UUID) (*models.ItineraryItem, error)
+	DeleteItem(ctx context.Context, itineraryID, itemID uuid.UUID, expectedVersion int) (*models.ItineraryItem, error)
+
+	ListCollaborators(ctx context.Context, itineraryID uuid.UUID) ([]models.ItineraryCollaborator, error)
+	Invite(ctx context.Context, itineraryID, userID, invitedBy uuid.UUID, role string) error
+	UpdateCollaboratorRole(ctx context.Context, itineraryID, userID uuid.UUID, role string) error
+	RemoveCollaborator(ctx context.Context, itineraryID, userID uuid.UUID) error
+	AcceptInvitation(ctx context.Context, itineraryID, userID uuid.UUID) error
+	ListInvitations(ctx context.Context, userID uuid.UUID) ([]models.Itinerary, error)
+}
+
+// UserLookup resolves invitees by email
+type UserLookup interface {
+	GetByEmail(ctx context.Context, email string) (*repositories.User, error)
+}
+
+// itineraryRoleRank orders roles so a handler can require a minimum
+var itineraryRoleRank = map[string]int{
+	models.ItineraryRoleView:  1,
+	models.ItineraryRoleEdit:  2,
+	models.ItineraryRoleOwner: 3,
+}
+
+// ItineraryHandler handles itineraries, share links and collaborators
+type ItineraryHandler struct {
+	repo  ItineraryRepository
+	users UserLookup
+}
+
+// NewItineraryHandler creates a new itinerary handler
+func NewItineraryHandler(repo ItineraryRepository, users UserLookup) *ItineraryHandler {
+	return &ItineraryHandler{repo: repo, users: users}
+}
+
+// ItineraryRequest is the body for creating or replacing an itinerary
+type ItineraryRequest struct {
+	Title       string  `json:"title" binding:"required,max=255"`
+	Description *string `json:"description"`
+	StartDate   *string `json:"start_date"` // YYYY-MM-DD
+	EndDate     *string `json:"end_date"`   // YYYY-MM-DD
+	IsPublic    bool    `json:"is_public"`
+}
+
+// toModel validates the request and converts it to an Itinerary
+func (req *ItineraryRequest) toModel() (*models.Itinerary, error) {
+	it := &models.Itinerary{Title: req.Title, Description: req.Description, IsPublic: req.IsPublic}
+	var err error
+	if it.StartDate, err = parseItineraryDate(req.StartDate); err != nil {
+		return nil, err
+	}
+	if it.EndDate, err = parseItineraryDate(req.EndDate); err != nil {
+		return nil, err
+	}
+	if it.StartDate != nil && it.EndDate != nil && it.EndDate.Before(*it.StartDate) {
+		return nil, errors.New("end_date must not be before start_date")
+	}
+	return it, nil
+}
+
+func parseItineraryDate(s *string) (*time.Time, error) {
+	if s == nil || *s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", *s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", *s)
+	}
+	return &t, nil
+}
+
+// ItineraryItemRequest is the body for adding or replacing an itinerary item.
+// Version is required on update and must match the version the client read.
+type ItineraryItemRequest struct {
+	PoiID       uuid.UUID  `json:"poi_id" binding:"required"`
+	Day         int        `json:"day" binding:"min=1"`
+	OrderIndex  int        `json:"order_index" binding:"min=0"`
+	PlannedTime *time.Time `json:"planned_time"`
+	Duration    *int       `json:"duration" binding:"omitempty,min=0"` // Minutes
+	Notes       *string    `json:"notes" binding:"omitempty,max=2000"`
+	Version     int        `json:"version"`
+}
+
+// CollaboratorRequest invites a user by email
+type CollaboratorRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=view edit"`
+}
+
+// CollaboratorRoleRequest changes a collaborator's role
+type CollaboratorRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=view edit"`
+}
+
+// ListItineraries handles GET /api/v1/itineraries (owned and shared with the user)
+func (h *ItineraryHandler) ListItineraries(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	itineraries, err := h.repo.ListForUser(c.Request.Context(), actor.UserID)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	for i := range itineraries {
+		hideShareToken(&itineraries[i])
+	}
+
+	utils.SendSuccess(c, "Itineraries retrieved", itineraries)
+}
+
+// CreateItinerary handles POST /api/v1/itineraries
+func (h *ItineraryHandler) CreateItinerary(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var input ItineraryRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	it, err := input.toModel()
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	it.UserID = actor.UserID
+
+	created, err := h.repo.Create(c.Request.Context(), it)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendCreated(c, "Itinerary created", created)
+}
+
+// GetItinerary handles GET /api/v1/itineraries/:id
+func (h *ItineraryHandler) GetItinerary(c *gin.Context) {
+	it, _, ok := h.load(c, models.ItineraryRoleView)
+	if !ok {
+		return
+	}
+
+	items, err := h.repo.ListItems(c.Request.Context(), it.ItineraryID)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	hideShareToken(it)
+	utils.SendSuccess(c, "Itinerary retrieved", gin.H{"itinerary": it, "items": items})
+}
+
+// UpdateItinerary handles PUT /api/v1/itineraries/:id (editors and owner)
+func (h *ItineraryHandler) UpdateItinerary(c *gin.Context) {
+	existing, actor, ok := h.load(c, models.ItineraryRoleEdit)
+	if !ok {
+		return
+	}
+
+	var input ItineraryRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	it, err := input.toModel()
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	// Only the owner decides who can see the itinerary
+	if existing.Role != models.ItineraryRoleOwner {
+		it.IsPublic = existing.IsPublic
+	}
+	it.ItineraryID = existing.ItineraryID
+
+	ctx := c.Request.Context()
+	if err := h.repo.Update(ctx, it); err != nil {
+		sendItineraryError(c, err)
+		return
+	}
+	updated, err := h.repo.GetForUser(ctx, it.ItineraryID, actor.UserID)
+	if err != nil {
+		sendItineraryError(c, err)
+		return
+	}
+
+	hideShareToken(updated)
+	utils.SendSuccess(c, "Itinerary updated", updated)
+}
+
+// DeleteItinerary handles DELETE /api/v1/itineraries/:id (owner only)
+func (h *ItineraryHandler) DeleteItinerary(c *gin.Context) {
+	it, _, ok := h.load(c, models.ItineraryRoleOwner)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Delete(c.Request.Context(), it.ItineraryID); err != nil {
+		sendItineraryError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Itinerary deleted", gin.H{"itinerary_id": it.ItineraryID})
+}
+
+// CreateShareLink handles POST /api/v1/itineraries/:id/share (owner only).
+// Each call issues a new token, invalidating the previous link.
+func (h *ItineraryHandler) CreateShareLink(c *gin.Context) {
+	it, _, ok := h.load(c, models.ItineraryRoleOwner)
+	if !ok {
+		return
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	if err := h.repo.SetShareToken(c.Request.Context(), it.ItineraryID, &token); err != nil {
+		sendItineraryError(c, err)
+		return
+	}
+
+	utils.SendCreated(c, "Share link created", gin.H{
+		"share_token": token,
+		"share_path":  "/api/v1/shared/itineraries/" + token,
+	})
+}
+
+// RevokeShareLink handles DELETE /api/v1/itineraries/:id/share (owner only)
+func (h *ItineraryHandler) RevokeShareLink(c *gin.Context) {
+	it, _, ok := h.load(c, models.ItineraryRoleOwner)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.SetShareToken(c.Request.Context(), it.ItineraryID, nil); err != nil {
+		sendItineraryError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Share link revoked", gin.H{"itinerary_id": it.ItineraryID})
+}
+
+// GetSharedItinerary handles GET /api/v1/shared/itineraries/:token (public, read-only)
+func (h *ItineraryHandler) GetSharedItinerary(c *gin.Context) {
+	ctx := c.Request.Context()
+	it, err := h.repo.GetByShareToken(ctx, c.Param("token"))
+	if err != nil {
+		sendItineraryError(c, err)
+		return
+	}
+
+	items, err := h.repo.ListItems(ctx, it.ItineraryID)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	utils.SendSuccess(c, "Itinerary retrieved", gin.H{"itinerary": it, "items": items})
+}
+
+// AddItem handles POST /api/v1/itineraries/:id/items (editors and owner)
+func (h *ItineraryHandler) AddItem(c *gin.Context) {
+	it, actor, ok := h.load(c, models.ItineraryRoleEdit)
+	if !ok {
+		return
+	}
+
+	var input ItineraryItemRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+
+	item, err := h.repo.CreateItem(c.Request.Context(), input.toModel(it.ItineraryID), actor.UserID)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendCreated(c, "Itinerary item added", item)
+}
+
+// UpdateItem handles PUT /api/v1/itineraries/:id/items/:item_id (editors and owner).
+// A stale version yields 409 with the current item.
+func (h *ItineraryHandler) UpdateItem(c *gin.Context) {
+	it, actor, ok := h.load(c, models.ItineraryRoleEdit)
+	if !ok {
+		return
+	}
+	itemID, err := uuid.Parse(c.Param("item_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid item ID format", err)
+		return
+	}
+
+	var input ItineraryItemRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	if input.Version < 1 {
+		utils.SendError(c, http.StatusBadRequest, "version is required", nil)
+		return
+	}
+
+	item := input.toModel(it.ItineraryID)
+	item.ItemID = itemID
+	updated, err := h.repo.UpdateItem(c.Request.Context(), item, input.Version, actor.UserID)
+	if err != nil {
+		sendItemError(c, updated, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Itinerary item updated", updated)
+}
+
+// DeleteItem handles DELETE /api/v1/itineraries/:id/items/:item_id?version= (editors and owner)
+func (h *ItineraryHandler) DeleteItem(c *gin.Context) {
+	it, _, ok := h.load(c, models.ItineraryRoleEdit)
+	if !ok {
+		return
+	}
+	itemID, err := uuid.Parse(c.Param("item_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid item ID format", err)
+		return
+	}
+	version, err := strconv.Atoi(c.Query("version"))
+	if err != nil || version < 1 {
+		utils.SendError(c, http.StatusBadRequest, "version query parameter is required", nil)
+		return
+	}
+
+	current, err := h.repo.DeleteItem(c.Request.Context(), it.ItineraryID, itemID, version)
+	if err != nil {
+		sendItemError(c, current, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Itinerary item deleted", gin.H{"item_id": itemID})
+}
+
+// ListCollaborators handles GET /api/v1/itineraries/:id/collaborators (any member)
+func (h *ItineraryHandler) ListCollaborators(c *gin.Context) {
+	it, _, ok := h.load(c, models.ItineraryRoleView)
+	if !ok {
+		return
+	}
+
+	collaborators, err := h.repo.ListCollaborators(c.Request.Context(), it.ItineraryID)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Collaborators retrieved", collaborators)
+}
+
+// InviteCollaborator handles POST /api/v1/itineraries/:id/collaborators (owner only)
+func (h *ItineraryHandler) InviteCollaborator(c *gin.Context) {
+	it, actor, ok := h.load(c, models.ItineraryRoleOwner)
+	if !ok {
+		return
+	}
+
+	var input CollaboratorRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	invitee, err := h.users.GetByEmail(ctx, strings.TrimSpace(input.Email))
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.SendError(c, http.StatusNotFound, "no user with that email", nil)
+		return
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	if invitee.UserID == it.UserID {
+		utils.SendError(c, http.StatusBadRequest, "the owner cannot be invited", nil)
+		return
+	}
+
+	if err := h.repo.Invite(ctx, it.ItineraryID, invitee.UserID, actor.UserID, input.Role); err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendCreated(c, "Collaborator invited", gin.H{"user_id": invitee.UserID, "role": input.Role})
+}
+
+// UpdateCollaborator handles PUT /api/v1/itineraries/:id/collaborators/:user_id (owner only)
+func (h *ItineraryHandler) UpdateCollaborator(c *gin.Context) {
+	it, _, ok := h.load(c, models.ItineraryRoleOwner)
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid user ID format", err)
+		return
+	}
+
+	var input CollaboratorRoleRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+
+	if err := h.repo.UpdateCollaboratorRole(c.Request.Context(), it.ItineraryID, userID, input.Role); err != nil {
+		sendItineraryError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Collaborator updated", gin.H{"user_id": userID, "role": input.Role})
+}
+
+// RemoveCollaborator handles DELETE /api/v1/itineraries/:id/collaborators/:user_id.
+// The owner can remove anyone; collaborators can remove themselves.
+func (h *ItineraryHandler) RemoveCollaborator(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	itineraryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid itinerary ID format", err)
+		return
+	}
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid user ID format", err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if userID != actor.UserID {
+		it, err := h.repo.GetForUser(ctx, itineraryID, actor.UserID)
+		if err != nil {
+			sendItineraryError(c, err)
+			return
+		}
+		if it.Role != models.ItineraryRoleOwner {
+			utils.SendError(c, http.StatusForbidden, "only the owner can remove collaborators", nil)
+			return
+		}
+	}
+
+	if err := h.repo.RemoveCollaborator(ctx, itineraryID, userID); err != nil {
+		sendItineraryError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Collaborator removed", gin.H{"user_id": userID})
+}
+
+// ListInvitations handles GET /api/v1/itineraries/invitations
+func (h *ItineraryHandler) ListInvitations(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	invitations, err := h.repo.ListInvitations(c.Request.Context(), actor.UserID)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Invitations retrieved", invitations)
+}
+
+// AcceptInvitation handles POST /api/v1/itineraries/:id/invitation/accept
+func (h *ItineraryHandler) AcceptInvitation(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	itineraryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid itinerary ID format", err)
+		return
+	}
+
+	if err := h.repo.AcceptInvitation(c.Request.Context(), itineraryID, actor.UserID); err != nil {
+		sendItineraryError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Invitation accepted", gin.H{"itinerary_id": itineraryID})
+}
+
+// load parses :id, fetches the itinerary for the caller and enforces a minimum role
+func (h *ItineraryHandler) load(c *gin.Context, minRole string) (*models.Itinerary, services.Actor, bool) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return nil, actor, false
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid itinerary ID format", err)
+		return nil, actor, false
+	}
+
+	it, err := h.repo.GetForUser(c.Request.Context(), id, actor.UserID)
+	if err != nil {
+		sendItineraryError(c, err)
+		return nil, actor, false
+	}
+	if itineraryRoleRank[it.Role] < itineraryRoleRank[minRole] {
+		utils.SendError(c, http.StatusForbidden, "insufficient itinerary permissions", nil)
+		return nil, actor, false
+	}
+	return it, actor, true
+}
+
+func (req *ItineraryItemRequest) toModel(itineraryID uuid.UUID) *models.ItineraryItem {
+	return &models.ItineraryItem{
+		ItineraryID: itineraryID,
+		PoiID:       req.PoiID,
+		Day:         req.Day,
+		OrderIndex:  req.OrderIndex,
+		PlannedTime: req.PlannedTime,
+		Duration:    req.Duration,
+		Notes:       req.Notes,
+	}
+}
+
+// hideShareToken strips the share link from responses to non-owners
+func hideShareToken(it *models.Itinerary) {
+	if it.Role != models.ItineraryRoleOwner {
+		it.ShareToken = nil
+		it.ShareCreatedAt = nil
+	}
+}
+
+// newShareToken generates an unguessable URL-safe share token
+func newShareToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate share token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// sendItineraryError maps itinerary repository errors to HTTP responses
+func sendItineraryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrItineraryNotFound):
+		utils.SendError(c, http.StatusNotFound, "itinerary not found", err)
+	case errors.Is(err, repositories.ErrCollaboratorNotFound):
+		utils.SendError(c, http.StatusNotFound, "invitation not found", err)
+	default:
+		utils.SendInternalError(c, err)
+	}
+}
+
+// sendItemError maps item write errors, returning the current item on a version conflict
+func sendItemError(c *gin.Context, current *models.ItineraryItem, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrItineraryItemConflict):
+		c.AbortWithStatusJSON(http.StatusConflict, utils.Response{
+			Success: false,
+			Message: "itinerary item was modified by someone else",
+			Data:    gin.H{"current": current},
+			Error:   err.Error(),
+		})
+	case errors.Is(err, repositories.ErrItineraryItemNotFound):
+		utils.SendError(c, http.StatusNotFound, "itinerary item not found", err)
+	default:
+		utils.SendInternalError(c, err)
+	}
+}
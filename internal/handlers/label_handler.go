@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// LabelRepository defines the data access needed to manage display labels
+type LabelRepository interface {
+	List(ctx context.Context, entityType string, entityID uuid.UUID) ([]repositories.DisplayLabel, error)
+	Upsert(ctx context.Context, entityType string, entityID uuid.UUID, locale, label string, updatedBy uuid.UUID) (*repositories.DisplayLabel, error)
+	Delete(ctx context.Context, entityType string, entityID uuid.UUID, locale string) error
+}
+
+// LabelHandler manages localized labels for categories and vocabularies (routes require taxonomy:manage)
+type LabelHandler struct {
+	repo      LabelRepository
+	supported []string
+}
+
+// NewLabelHandler creates a new label handler
+func NewLabelHandler(repo LabelRepository, supported []string) *LabelHandler {
+	return &LabelHandler{repo: repo, supported: supported}
+}
+
+// LabelRequest is the body for PUT .../labels/:locale
+type LabelRequest struct {
+	Label string `json:"label" binding:"required,max=255"`
+}
+
+// ListLabels returns a handler for GET /api/v1/admin/{categories|vocabularies}/:id/labels
+func (h *LabelHandler) ListLabels(entityType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entityID, ok := h.parseEntityID(c)
+		if !ok {
+			return
+		}
+
+		labels, err := h.repo.List(c.Request.Context(), entityType, entityID)
+		if err != nil {
+			utils.SendInternalError(c, err)
+			return
+		}
+
+		utils.SendSuccess(c, "Labels retrieved", labels)
+	}
+}
+
+// UpsertLabel returns a handler for PUT /api/v1/admin/{categories|vocabularies}/:id/labels/:locale
+func (h *LabelHandler) UpsertLabel(entityType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entityID, ok := h.parseEntityID(c)
+		if !ok {
+			return
+		}
+		locale, ok := h.locale(c)
+		if !ok {
+			return
+		}
+
+		var input LabelRequest
+		if err := c.ShouldBindJSON(&input); err != nil {
+			utils.SendValidationError(c, err)
+			return
+		}
+
+		actor, _ := actorFromContext(c)
+		label, err := h.repo.Upsert(c.Request.Context(), entityType, entityID, locale, input.Label, actor.UserID)
+		if err != nil {
+			sendLabelError(c, err)
+			return
+		}
+
+		utils.SendSuccess(c, "Label saved", label)
+	}
+}
+
+// DeleteLabel returns a handler for DELETE /api/v1/admin/{categories|vocabularies}/:id/labels/:locale
+func (h *LabelHandler) DeleteLabel(entityType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entityID, ok := h.parseEntityID(c)
+		if !ok {
+			return
+		}
+		locale, ok := h.locale(c)
+		if !ok {
+			return
+		}
+
+		if err := h.repo.Delete(c.Request.Context(), entityType, entityID, locale); err != nil {
+			sendLabelError(c, err)
+			return
+		}
+
+		utils.SendSuccess(c, "Label deleted", gin.H{"entity_id": entityID, "locale": locale})
+	}
+}
+
+// parseEntityID parses the :id param
+func (h *LabelHandler) parseEntityID(c *gin.Context) (uuid.UUID, bool) {
+	entityID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid ID format", err)
+		return uuid.Nil, false
+	}
+	return entityID, true
+}
+
+// locale validates the :locale param against the supported locales
+func (h *LabelHandler) locale(c *gin.Context) (string, bool) {
+	locale := c.Param("locale")
+	if !slices.Contains(h.supported, locale) {
+		utils.SendError(c, http.StatusBadRequest, "unsupported locale", nil)
+		return "", false
+	}
+	return locale, true
+}
+
+// sendLabelError maps label repository errors to HTTP responses
+func sendLabelError(c *gin.Context, err error) {
+	if errors.Is(err, repositories.ErrLabelNotFound) {
+		utils.SendError(c, http.StatusNotFound, "not found", err)
+		return
+	}
+	utils.SendInternalError(c, err)
+}
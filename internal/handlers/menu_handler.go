@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/services"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// defaultMenuCurrency is used for menu items that omit a currency
+const defaultMenuCurrency = models.DefaultCurrency
+
+// MenuRepository defines the data access needed for POI menus
+type MenuRepository interface {
+	GetByPOI(ctx context.Context, poiID uuid.UUID) ([]models.MenuSection, error)
+	ReplaceMenu(ctx context.Context, poiID uuid.UUID, sections []models.MenuSection) error
+}
+
+// MenuHandler handles the /pois/:id/menu sub-resource
+type MenuHandler struct {
+	repo    MenuRepository
+	poiRepo POIRepository
+}
+
+// NewMenuHandler creates a new menu handler
+func NewMenuHandler(repo MenuRepository, poiRepo POIRepository) *MenuHandler {
+	return &MenuHandler{repo: repo, poiRepo: poiRepo}
+}
+
+// MenuItemRequest is a single item in a menu update
+type MenuItemRequest struct {
+	Name         string     `json:"name" binding:"required,max=200"`
+	Description  *string    `json:"description"`
+	Price        *float64   `json:"price" binding:"omitempty,min=0"`
+	Currency     string     `json:"currency" binding:"omitempty,len=3"`
+	DietaryTags  []string   `json:"dietary_tags"`
+	PhotoAssetID *uuid.UUID `json:"photo_asset_id"`
+	IsAvailable  *bool      `json:"is_available"`
+	IsFeatured   bool       `json:"is_featured"`
+}
+
+// MenuSectionRequest is a section in a menu update
+type MenuSectionRequest struct {
+	Name        string            `json:"name" binding:"required,max=120"`
+	Description *string           `json:"description"`
+	Items       []MenuItemRequest `json:"items" binding:"dive"`
+}
+
+// UpdateMenuRequest is the body for PUT /api/v1/pois/:id/menu
+type UpdateMenuRequest struct {
+	Sections []MenuSectionRequest `json:"sections" binding:"dive"`
+}
+
+// GetMenu handles GET /api/v1/pois/:id/menu
+func (h *MenuHandler) GetMenu(c *gin.Context) {
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+
+	sections, err := h.repo.GetByPOI(c.Request.Context(), poiID)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Menu retrieved", gin.H{"poi_id": poiID, "sections": sections})
+}
+
+// UpdateMenu handles PUT /api/v1/pois/:id/menu (owner or poi:merge).
+// The request replaces the whole menu; section and item order follow the arrays.
+func (h *MenuHandler) UpdateMenu(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	poi, err := h.poiRepo.GetByID(ctx, poiID)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return
+	}
+	if !actor.Can(services.PermPOIMerge) && !isPOIOwner(poi, actor.UserID) {
+		utils.SendError(c, http.StatusForbidden, "only the POI owner can edit the menu", nil)
+		return
+	}
+
+	var input UpdateMenuRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+
+	sections := make([]models.MenuSection, 0, len(input.Sections))
+	for _, s := range input.Sections {
+		section := models.MenuSection{Name: s.Name, Description: s.Description}
+		for _, it := range s.Items {
+			item := models.MenuItem{
+				Name:         it.Name,
+				Description:  it.Description,
+				Price:        it.Price,
+				Currency:     strings.ToUpper(it.Currency),
+				DietaryTags:  pq.StringArray(it.DietaryTags),
+				PhotoAssetID: it.PhotoAssetID,
+				IsAvailable:  it.IsAvailable == nil || *it.IsAvailable,
+				IsFeatured:   it.IsFeatured,
+			}
+			if item.Currency == "" {
+				item.Currency = defaultMenuCurrency
+			}
+			if !models.IsCurrencyCode(item.Currency) {
+				utils.SendError(c, http.StatusBadRequest, fmt.Sprintf("currency %q is not an ISO 4217 currency code", it.Currency), nil)
+				return
+			}
+			section.Items = append(section.Items, item)
+		}
+		sections = append(sections, section)
+	}
+
+	if err := h.repo.ReplaceMenu(ctx, poiID, sections); err != nil {
+		if errors.Is(err, repositories.ErrUnknownMenuPhoto) {
+			utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	updated, err := h.repo.GetByPOI(ctx, poiID)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Menu updated", gin.H{"poi_id": poiID, "sections": updated})
+}
@@ -1,9 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"regexp"
+	"strings"
 
+	"maukemana-backend/internal/imaging"
+	"maukemana-backend/internal/models"
 	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/services"
 	"maukemana-backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -11,11 +17,23 @@ import (
 )
 
 type PhotoHandler struct {
-	repo *repositories.PhotoRepository
+	repo    *repositories.PhotoRepository
+	poiRepo POIRepository
+	imaging *imaging.Service // nil when R2 is not configured
 }
 
-func NewPhotoHandler(repo *repositories.PhotoRepository) *PhotoHandler {
-	return &PhotoHandler{repo: repo}
+func NewPhotoHandler(repo *repositories.PhotoRepository, poiRepo POIRepository) *PhotoHandler {
+	return &PhotoHandler{repo: repo, poiRepo: poiRepo}
+}
+
+// UseImaging enables promoting gallery photos to cover
+func (h *PhotoHandler) UseImaging(svc *imaging.Service) {
+	h.imaging = svc
+}
+
+// ReorderPhotosRequest is the body for PUT /api/v1/pois/:id/photos/order
+type ReorderPhotosRequest struct {
+	PhotoIDs []uuid.UUID `json:"photo_ids" binding:"required,max=200"`
 }
 
 // VotePhoto handles upvoting/downvoting a photo with Reddit-style toggle
@@ -76,3 +94,186 @@ func (h *PhotoHandler) VotePhoto(c *gin.Context) {
 		"user_vote": userVote, // 1=upvoted, -1=downvoted, 0=no vote
 	})
 }
+
+// ReorderPhotos handles PUT /api/v1/pois/:id/photos/order, letting the owner
+// set the gallery order. Photos left out of the list follow the ordered ones.
+func (h *PhotoHandler) ReorderPhotos(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	poi, err := h.poiRepo.GetByID(ctx, poiID)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return
+	}
+	if !actor.Can(services.PermPOIMerge) && !isPOIOwner(poi, actor.UserID) {
+		utils.SendError(c, http.StatusForbidden, "only the POI owner can order the gallery", nil)
+		return
+	}
+
+	var input ReorderPhotosRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	seen := make(map[uuid.UUID]bool, len(input.PhotoIDs))
+	for _, id := range input.PhotoIDs {
+		if seen[id] {
+			utils.SendError(c, http.StatusBadRequest, "photo "+id.String()+" is listed more than once", nil)
+			return
+		}
+		seen[id] = true
+	}
+
+	if err := h.repo.ReorderPOIPhotos(ctx, poiID, input.PhotoIDs); err != nil {
+		if errors.Is(err, repositories.ErrUnknownPhoto) {
+			utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	photos, err := h.repo.GetByPOIs(ctx, []uuid.UUID{poiID})
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	gallery := photos[poiID]
+	if gallery == nil {
+		gallery = []models.Photo{}
+	}
+
+	utils.SendSuccess(c, "Gallery order updated", gin.H{"poi_id": poiID, "photos": gallery})
+}
+
+// coverRendition is the cover rendition POIs link to
+const coverRendition = "cover_1200"
+
+// imageURLHash extracts the content hash from a processed upload URL
+// (/img/<content hash>/<rendition>)
+var imageURLHash = regexp.MustCompile(`/img/([0-9a-f]{64})/`)
+
+// SetCoverRequest is the optional body for POST /api/v1/pois/:id/photos/:photo_id/set-cover
+type SetCoverRequest struct {
+	CropData *imaging.CropConfig `json:"crop_data"`
+}
+
+// SetCover handles POST /api/v1/pois/:id/photos/:photo_id/set-cover. It renders
+// the cover ladder from the photo's original, with an optional crop, and points
+// the POI's cover at it. Until the cover renditions are ready /img serves the
+// photo's gallery renditions for them, so the cover is never broken.
+func (h *PhotoHandler) SetCover(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if h.imaging == nil {
+		utils.SendError(c, http.StatusServiceUnavailable, "image processing is not configured", nil)
+		return
+	}
+
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+	photoID, err := uuid.Parse(c.Param("photo_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid photo ID format", err)
+		return
+	}
+
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	poi, err := h.poiRepo.GetByID(ctx, poiID)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return
+	}
+	if !actor.Can(services.PermPOIMerge) && !isPOIOwner(poi, actor.UserID) {
+		utils.SendError(c, http.StatusForbidden, "only the POI owner can change the cover", nil)
+		return
+	}
+
+	var input SetCoverRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			utils.SendValidationError(c, err)
+			return
+		}
+	}
+
+	photo, err := h.repo.GetPOIPhoto(ctx, poiID, photoID)
+	if errors.Is(err, repositories.ErrUnknownPhoto) {
+		utils.SendError(c, http.StatusNotFound, "photo not found", nil)
+		return
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	m := imageURLHash.FindStringSubmatch(photo.URL)
+	if m == nil {
+		utils.SendError(c, http.StatusUnprocessableEntity, "photo was not uploaded through the image pipeline", nil)
+		return
+	}
+	hash := m[1]
+	asset, ok := h.imaging.GetAsset(c.Request.Context(), hash)
+	if !ok || asset.Status != imaging.StatusReady || asset.ModerationStatus.Blocked() {
+		utils.SendError(c, http.StatusUnprocessableEntity, "photo image is not available", nil)
+		return
+	}
+
+	// Existing cover renditions are reused unless the crop changes them
+	var jobID *uuid.UUID
+	if input.CropData != nil || !hasRenditions(asset, imaging.RenditionPrefix("cover")) {
+		id, err := h.imaging.QueueReprocessing(imaging.OriginalKey(hash), "cover", actor.UserID, input.CropData)
+		if err != nil {
+			utils.SendError(c, http.StatusServiceUnavailable, "processing queue is unavailable, try again later", nil)
+			return
+		}
+		jobID = &id
+	}
+
+	coverURL := h.imaging.GetDerivativeURL(hash, coverRendition)
+	if err := h.repo.SetCoverFromPhoto(ctx, poiID, photoID, coverURL); err != nil {
+		if errors.Is(err, repositories.ErrUnknownPhoto) {
+			utils.SendError(c, http.StatusNotFound, "photo not found", nil)
+			return
+		}
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	resp := gin.H{"poi_id": poiID, "photo_id": photoID, "cover_image_url": coverURL}
+	if jobID != nil {
+		resp["job_id"] = jobID
+		resp["status_url"] = "/api/v1/assets/" + jobID.String()
+	}
+	utils.SendSuccess(c, "Cover updated", resp)
+}
+
+// hasRenditions reports whether asset has derivatives named with prefix
+func hasRenditions(asset *imaging.ImageAsset, prefix string) bool {
+	for _, d := range asset.Derivatives {
+		if strings.HasPrefix(d.RenditionName, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/services"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// POIDraftRepository defines the data access needed for draft autosave
+type POIDraftRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*repositories.POI, error)
+	SaveDraft(ctx context.Context, poiID uuid.UUID, fields map[string]json.RawMessage, savedAt time.Time, editedBy *uuid.UUID) (*repositories.DraftSaveResult, error)
+	MissingForSubmission(ctx context.Context, poiID uuid.UUID) ([]string, error)
+	GetCompleteness(ctx context.Context, poiID uuid.UUID) (*repositories.Completeness, error)
+}
+
+// POIDraftHandler serves autosave for the multi-step submission wizard
+type POIDraftHandler struct {
+	repo POIDraftRepository
+}
+
+// NewPOIDraftHandler creates a new draft handler
+func NewPOIDraftHandler(repo POIDraftRepository) *POIDraftHandler {
+	return &POIDraftHandler{repo: repo}
+}
+
+// SaveDraftRequest is the body for PATCH /api/v1/pois/:id/draft
+type SaveDraftRequest struct {
+	Fields map[string]json.RawMessage `json:"fields" binding:"required"`
+	// When the client captured the input; orders autosaves per section. Defaults to now.
+	ClientSavedAt *time.Time `json:"client_saved_at"`
+}
+
+// SaveDraftResponse reports the applied autosave and what submission still needs
+type SaveDraftResponse struct {
+	PoiID uuid.UUID `json:"poi_id"`
+	repositories.DraftSaveResult
+	MissingFields []string `json:"missing_fields"`
+	ReadyToSubmit bool     `json:"ready_to_submit"`
+}
+
+// SaveDraft handles PATCH /api/v1/pois/:id/draft (owner or poi:merge). Any
+// subset of wizard fields is accepted without the checks full submission
+// needs; the response lists the fields still required to submit.
+func (h *POIDraftHandler) SaveDraft(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var input SaveDraftRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	if err := repositories.ValidateDraftFields(input.Fields); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+
+	poi, err := h.repo.GetByID(ctx, poiID)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return
+	}
+	if !isPOIOwner(poi, actor.UserID) && !actor.Can(services.PermPOIMerge) {
+		utils.SendError(c, http.StatusForbidden, "not authorized to edit this POI", nil)
+		return
+	}
+
+	savedAt := time.Now()
+	if input.ClientSavedAt != nil && input.ClientSavedAt.Before(savedAt) {
+		// Clocks ahead of the server would block later saves from other devices
+		savedAt = *input.ClientSavedAt
+	}
+
+	result, err := h.repo.SaveDraft(ctx, poiID, input.Fields, savedAt, &actor.UserID)
+	if errors.Is(err, repositories.ErrPOINotEditableDraft) {
+		utils.SendError(c, http.StatusConflict, err.Error(), err)
+		return
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	missing, err := h.repo.MissingForSubmission(ctx, poiID)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Draft saved", SaveDraftResponse{
+		PoiID:           poiID,
+		DraftSaveResult: *result,
+		MissingFields:   missing,
+		ReadyToSubmit:   len(missing) == 0,
+	})
+}
+
+// Completeness handles GET /api/v1/pois/:id/completeness (owner, poi:merge or
+// poi:approve). The wizard gates its submit button on ready_to_submit.
+func (h *POIDraftHandler) Completeness(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	poi, err := h.repo.GetByID(ctx, poiID)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return
+	}
+	if !isPOIOwner(poi, actor.UserID) && !actor.Can(services.PermPOIMerge) && !actor.Can(services.PermPOIApprove) {
+		utils.SendError(c, http.StatusForbidden, "not authorized to view this POI", nil)
+		return
+	}
+
+	completeness, err := h.repo.GetCompleteness(ctx, poiID)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "POI completeness retrieved", completeness)
+}
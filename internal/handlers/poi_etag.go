@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"maukemana-backend/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// poiETag derives a strong ETag from the POI version, the negotiated locale and
+// the query parameters that shape the representation (fields, include).
+func poiETag(v *repositories.POIVersion, c *gin.Context) string {
+	h := sha256.New()
+	h.Write([]byte(v.UpdatedAt.UTC().Format(time.RFC3339Nano)))
+	h.Write([]byte{0})
+	h.Write([]byte(v.PhotosVersion))
+	h.Write([]byte{0})
+	h.Write([]byte(v.ReviewsVersion))
+	h.Write([]byte{0})
+	h.Write([]byte(v.SpecialsVersion))
+	h.Write([]byte{0})
+	h.Write([]byte(v.I18nVersion))
+	h.Write([]byte{0})
+	h.Write([]byte(c.GetString("locale")))
+	h.Write([]byte{0})
+	h.Write([]byte(c.Request.URL.Query().Encode()))
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// etagMatches implements the weak comparison used by If-None-Match
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// notModified sets the validators on the response and reports whether the
+// request's conditional headers allow a 304. If-None-Match takes precedence
+// over If-Modified-Since (RFC 9110 §13.2.2).
+func notModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	c.Header("Cache-Control", "no-cache")
+
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		return etagMatches(inm, etag)
+	}
+	if ims := c.GetHeader("If-Modified-Since"); ims != "" {
+		if t, err := http.ParseTime(ims); err == nil {
+			return !lastModified.Truncate(time.Second).After(t)
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// includedReviewsLimit caps the reviews embedded by ?include=reviews
+const includedReviewsLimit = 10
+
+// ReviewRepository defines the review reads used by POI includes
+type ReviewRepository interface {
+	GetByPOI(ctx context.Context, poiID uuid.UUID, viewerID *uuid.UUID, limit, offset int) ([]models.Review, error)
+}
+
+// POIRelations are the optional related resources a POI response can include
+type POIRelations struct {
+	Menus   MenuRepository
+	Reviews ReviewRepository
+}
+
+// poiIncludes lists the relations accepted by ?include= on the POI detail
+var poiIncludes = map[string]bool{
+	"menu":    true,
+	"reviews": true,
+}
+
+// parseFields reads ?fields= and validates it against the POI column whitelist
+func parseFields(c *gin.Context, detail bool) ([]string, error) {
+	fields := repositories.ParseCommaSeparated(c.Query("fields"))
+	if err := repositories.ValidatePOIFields(fields, detail); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// parseIncludes reads ?include= and rejects unknown relations
+func parseIncludes(c *gin.Context) ([]string, error) {
+	includes := repositories.ParseCommaSeparated(c.Query("include"))
+	var unknown []string
+	for _, inc := range includes {
+		if !poiIncludes[inc] {
+			unknown = append(unknown, inc)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown include: %s", strings.Join(unknown, ", "))
+	}
+	return includes, nil
+}
+
+// loadIncludes attaches the requested relations to the POI. Included reviews
+// are filtered for viewerID like the review list: authors they blocked are
+// left out, and their own reviews are kept if they are shadow-banned.
+func (h *POIHandler) loadIncludes(ctx context.Context, poi *repositories.POI, includes []string, viewerID *uuid.UUID) error {
+	for _, inc := range includes {
+		switch inc {
+		case "menu":
+			if h.relations.Menus == nil {
+				continue
+			}
+			menu, err := h.relations.Menus.GetByPOI(ctx, poi.PoiID)
+			if err != nil {
+				return err
+			}
+			poi.Menu = menu
+		case "reviews":
+			if h.relations.Reviews == nil {
+				continue
+			}
+			reviews, err := h.relations.Reviews.GetByPOI(ctx, poi.PoiID, viewerID, includedReviewsLimit, 0)
+			if err != nil {
+				return err
+			}
+			poi.Reviews = reviews
+		}
+	}
+	return nil
+}
+
+// projectFields trims a marshalled POI down to the requested fields plus any
+// included relations. Sparse queries leave unselected columns at their zero
+// value, so they must not reach the response.
+func projectFields(v interface{}, fields, includes []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return v, nil
+	}
+
+	keep := make(map[string]bool, len(fields)+len(includes)+1)
+	keep["poi_id"] = true
+	for _, f := range fields {
+		keep[f] = true
+	}
+	for _, inc := range includes {
+		keep[inc] = true
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal poi: %w", err)
+	}
+
+	project := func(obj map[string]json.RawMessage) map[string]json.RawMessage {
+		out := make(map[string]json.RawMessage, len(keep))
+		for k, val := range obj {
+			if keep[k] {
+				out[k] = val
+			}
+		}
+		return out
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(string(raw)), "[") {
+		var list []map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, fmt.Errorf("unmarshal poi list: %w", err)
+		}
+		out := make([]map[string]json.RawMessage, len(list))
+		for i, obj := range list {
+			out[i] = project(obj)
+		}
+		return out, nil
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, fmt.Errorf("unmarshal poi: %w", err)
+	}
+	return project(obj), nil
+}
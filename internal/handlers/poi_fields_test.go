@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+)
+
+// blockingReviews serves reviews the way ReviewRepository does for blocks:
+// authors the viewer blocked are left out
+type blockingReviews struct {
+	reviews []models.Review
+	blocks  map[uuid.UUID][]uuid.UUID // blocker -> blocked authors
+}
+
+func (r *blockingReviews) GetByPOI(ctx context.Context, poiID uuid.UUID, viewerID *uuid.UUID, limit, offset int) ([]models.Review, error) {
+	blocked := map[uuid.UUID]bool{}
+	if viewerID != nil {
+		for _, id := range r.blocks[*viewerID] {
+			blocked[id] = true
+		}
+	}
+	var out []models.Review
+	for _, review := range r.reviews {
+		if review.PoiID == poiID && !blocked[review.UserID] {
+			out = append(out, review)
+		}
+	}
+	return out, nil
+}
+
+func TestLoadIncludesDropsBlockedAuthorsReviews(t *testing.T) {
+	poiID, viewer, blockedAuthor, otherAuthor := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	h := &POIHandler{relations: POIRelations{Reviews: &blockingReviews{
+		reviews: []models.Review{
+			{ReviewID: uuid.New(), PoiID: poiID, UserID: blockedAuthor},
+			{ReviewID: uuid.New(), PoiID: poiID, UserID: otherAuthor},
+		},
+		blocks: map[uuid.UUID][]uuid.UUID{viewer: {blockedAuthor}},
+	}}}
+
+	poi := &repositories.POI{PoiID: poiID}
+	if err := h.loadIncludes(context.Background(), poi, []string{"reviews"}, &viewer); err != nil {
+		t.Fatal(err)
+	}
+	if len(poi.Reviews) != 1 || poi.Reviews[0].UserID != otherAuthor {
+		t.Fatalf("included reviews by %v, want only the unblocked author %s", reviewAuthors(poi.Reviews), otherAuthor)
+	}
+
+	anonymous := &repositories.POI{PoiID: poiID}
+	if err := h.loadIncludes(context.Background(), anonymous, []string{"reviews"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(anonymous.Reviews) != 2 {
+		t.Fatalf("anonymous viewer got %d reviews, want 2", len(anonymous.Reviews))
+	}
+}
+
+func reviewAuthors(reviews []models.Review) []uuid.UUID {
+	ids := make([]uuid.UUID, len(reviews))
+	for i, r := range reviews {
+		ids[i] = r.UserID
+	}
+	return ids
+}
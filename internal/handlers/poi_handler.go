@@ -2,7 +2,14 @@ package handlers
 
 import (
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -10,39 +17,172 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"maukemana-backend/internal/geohash"
+	"maukemana-backend/internal/geoip"
+	"maukemana-backend/internal/models"
 	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/routing"
 	"maukemana-backend/internal/services"
+	"maukemana-backend/internal/textmod"
 	"maukemana-backend/internal/utils"
 )
 
 // POIRepository defines the interface for POI data access
 type POIRepository interface {
 	Search(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]repositories.POI, error)
+	SearchFacets(ctx context.Context, filters map[string]interface{}, facets []string) (map[string]map[string]int, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*repositories.POI, error)
+	GetByIDWithOptions(ctx context.Context, id uuid.UUID, opts repositories.POIReadOptions) (*repositories.POI, error)
+	GetVersion(ctx context.Context, id uuid.UUID) (*repositories.POIVersion, error)
 	Create(ctx context.Context, input repositories.CreatePOIInput) (*repositories.POI, error)
-	UpdateFull(ctx context.Context, id uuid.UUID, input repositories.UpdateFullInput) error
+	UpdateFull(ctx context.Context, id uuid.UUID, input repositories.UpdateFullInput, editedBy *uuid.UUID) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	ReplaceRejectionFeedback(ctx context.Context, poiID uuid.UUID, items []repositories.RejectionFeedback, createdBy *uuid.UUID) error
+	GetRejectionFeedback(ctx context.Context, poiID uuid.UUID) ([]repositories.RejectionFeedback, error)
 	GetByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]repositories.POI, int, error)
 	GetNearby(ctx context.Context, lat, lng float64, radius, limit int) ([]repositories.POIWithDistance, error)
-	UpdateStatus(ctx context.Context, id uuid.UUID, status string, reason *string) error
 	GetByUserAndStatus(ctx context.Context, userID uuid.UUID, status string, limit, offset int) ([]repositories.POI, error)
-	GetByStatus(ctx context.Context, status string, limit, offset int) ([]repositories.POI, error)
+	GetByStatusOrdered(ctx context.Context, status, sort string, overdueOnly bool, limit, offset int) ([]repositories.POI, error)
+	GetQueueMetrics(ctx context.Context, days int) (*models.QueueMetrics, error)
+	AssignReviewer(ctx context.Context, poiID, assignee uuid.UUID, timeout time.Duration, force bool) (*repositories.ReviewAssignment, error)
+	ReleaseReviewer(ctx context.Context, poiID, userID uuid.UUID, force bool) error
+	RecordTakedown(ctx context.Context, poiID uuid.UUID, reasonCode string, note *string, previousStatus string, takenDownBy uuid.UUID) (*repositories.Takedown, error)
+	GetTakedown(ctx context.Context, poiID uuid.UUID) (*repositories.Takedown, error)
+	ListRecentlyApproved(ctx context.Context, filter repositories.RecentPOIFilter) ([]repositories.RecentPOI, error)
+	GetNearbySimilar(ctx context.Context, poiID uuid.UUID, radiusMeters, limit int) ([]repositories.NearbySimilarPOI, error)
+	GetDistances(ctx context.Context, lat, lng float64, ids []uuid.UUID) ([]repositories.POIDistance, error)
+	GetTile(ctx context.Context, cells []string, limit int) ([]repositories.TilePOI, error)
 }
 
+// TextSearcher ranks approved POIs for a free-text query (external search index)
+type TextSearcher interface {
+	Search(ctx context.Context, query string, limit int) ([]uuid.UUID, error)
+}
+
+// TravelTimeEstimator computes travel legs from one origin to many destinations
+type TravelTimeEstimator interface {
+	Legs(ctx context.Context, mode routing.Mode, origin routing.Point, dests []routing.Point) ([]routing.Leg, error)
+}
+
+// ServiceAreaLocator tells whether coordinates lie inside an active service area
+type ServiceAreaLocator interface {
+	InServiceArea(ctx context.Context, lat, lng float64) (bool, error)
+}
+
+// SearchRecorder samples public searches for analytics
+type SearchRecorder interface {
+	RecordSearch(query string, filters map[string]interface{}, resultCount int)
+}
+
+// IPLocator estimates a coarse location from a client IP
+type IPLocator interface {
+	Locate(ctx context.Context, ip string) (geoip.Location, bool, error)
+}
+
+// SessionTracker remembers what signed-out clients browse in their anonymous session
+type SessionTracker interface {
+	RecordView(ctx context.Context, sessionID string, poiID uuid.UUID) error
+	RecordSearch(ctx context.Context, sessionID string, params map[string]string) error
+}
+
+// FilterOptionsSource derives search filter options and their POI counts from live data
+type FilterOptionsSource interface {
+	GetFilterOptions(ctx context.Context, area string, locales []string) (*repositories.FilterOptions, error)
+}
+
+// ViewRecorder counts POI detail views for trending
+type ViewRecorder interface {
+	RecordView(poiID uuid.UUID, viewer string)
+}
+
+// textSearchCandidates caps how many index matches are filtered and paginated in the database
+const textSearchCandidates = 500
+
 // POIHandler handles POI-related HTTP requests
 type POIHandler struct {
 	repo             POIRepository
 	geocodingService services.GeocodingService
+	workflow         *services.POIWorkflowService
+	filters          *services.FilterService
+	relations        POIRelations
+	textSearch       TextSearcher
+	travelTimes      TravelTimeEstimator
+	serviceAreas     ServiceAreaLocator
+	textMod          TextModerator
+	searchEvents     SearchRecorder
+	views            ViewRecorder
+	sessions         SessionTracker
+	ipLocator        IPLocator
+	filterOptions    FilterOptionsSource
+
+	assignmentTimeout time.Duration
 }
 
 // NewPOIHandler creates a new POI handler
-func NewPOIHandler(repo POIRepository, geocodingService services.GeocodingService) *POIHandler {
+func NewPOIHandler(repo POIRepository, geocodingService services.GeocodingService, workflow *services.POIWorkflowService, relations POIRelations) *POIHandler {
 	return &POIHandler{
 		repo:             repo,
 		geocodingService: geocodingService,
+		workflow:         workflow,
+		filters:          services.NewFilterService(),
+		relations:        relations,
+
+		assignmentTimeout: time.Hour,
 	}
 }
 
+// UseAssignmentTimeout sets how long a reviewer keeps a pending POI assigned
+func (h *POIHandler) UseAssignmentTimeout(d time.Duration) {
+	h.assignmentTimeout = d
+}
+
+// UseServiceAreas rejects new POIs located outside every active service area
+func (h *POIHandler) UseServiceAreas(areas ServiceAreaLocator) {
+	h.serviceAreas = areas
+}
+
+// UseTextSearch routes ?q= searches through an external search index
+func (h *POIHandler) UseTextSearch(ts TextSearcher) {
+	h.textSearch = ts
+}
+
+// UseTravelTimes enables travel_mode on nearby searches
+func (h *POIHandler) UseTravelTimes(t TravelTimeEstimator) {
+	h.travelTimes = t
+}
+
+// UseTextModerator masks profanity in POI descriptions and flags misplaced contact details
+func (h *POIHandler) UseTextModerator(m TextModerator) {
+	h.textMod = m
+}
+
+// UseSearchAnalytics records sampled first-page public searches
+func (h *POIHandler) UseSearchAnalytics(r SearchRecorder) {
+	h.searchEvents = r
+}
+
+// UseIPLocator lets nearest-first searches sent without coordinates fall
+// back to a location estimated from the client IP
+func (h *POIHandler) UseIPLocator(l IPLocator) {
+	h.ipLocator = l
+}
+
+// UseSessions remembers recent views and searches of anonymous sessions (X-Session-ID)
+func (h *POIHandler) UseSessions(s SessionTracker) {
+	h.sessions = s
+}
+
+// UseFilterOptions serves vocabulary-backed filter options and live POI
+// counts from GetFilterOptions. Without it only the fixed options are listed.
+func (h *POIHandler) UseFilterOptions(s FilterOptionsSource) {
+	h.filterOptions = s
+}
+
+// UseViewRecorder counts POI detail views
+func (h *POIHandler) UseViewRecorder(r ViewRecorder) {
+	h.views = r
+}
+
 // SearchPOIs handles GET /api/v1/pois
 func (h *POIHandler) SearchPOIs(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -51,163 +191,268 @@ func (h *POIHandler) SearchPOIs(c *gin.Context) {
 	page, limit := utils.GetPagination(c)
 	offset := utils.GetOffset(page, limit)
 
-	// Build filters from query params
-	filters := make(map[string]interface{})
-
-	// Category filter
-	if category := c.Query("category_id"); category != "" {
-		if catID, err := uuid.Parse(category); err == nil {
-			filters["category_id"] = catID
-		}
+	// Contradictory filters and statuses the caller may not see fail before the query
+	actor, _ := actorFromContext(c)
+	query, err := h.filters.Normalize(c.Request.URL.Query(), actor)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
 	}
-
-	// Legacy has_wifi boolean filter
-	if hasWifi := c.Query("has_wifi"); hasWifi == "true" {
-		filters["has_wifi"] = true
+	filters, err := repositories.ParseSearchFilters(query)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
 	}
-
-	// Price range filter
-	if priceRange := c.Query("price_range"); priceRange != "" {
-		if pr, err := strconv.Atoi(priceRange); err == nil {
-			filters["price_range"] = pr
+	status := filters["status"].(string)
+
+	// Without coordinates, nearest-first is measured from the client IP's estimated location
+	approximate := false
+	if filters["sort_by"] == "nearest" && filters["lat"] == nil && filters["lng"] == nil {
+		if loc, ok := h.approximateLocation(c); ok {
+			filters["lat"], filters["lng"] = loc.Lat, loc.Lng
+			approximate = true
 		}
 	}
 
-	// Status filter - defaults to "approved" for public feed
-	status := c.Query("status")
-	if status == "" {
-		status = "approved"
+	// Free-text search: the index ranks candidates, the database applies the
+	// remaining filters. Without an index (or if it fails) the database matches text itself.
+	q := strings.TrimSpace(c.Query("q"))
+	if q != "" {
+		if ids, ok := h.searchIndex(ctx, q, status); ok {
+			filters["match_ids"] = ids
+		} else {
+			filters["q"] = q
+		}
 	}
-	filters["status"] = status
 
-	// WiFi quality filter (string: none|slow|moderate|fast|excellent)
-	if wifiQuality := c.Query("wifi_quality"); wifiQuality != "" {
-		filters["wifi_quality"] = wifiQuality
+	// Sparse fieldsets, e.g. fields=name,latitude,longitude,cover_image_url for map pins
+	fields, err := parseFields(c, false)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
 	}
-
-	// Noise level filter (string: silent|quiet|moderate|lively|loud)
-	if noiseLevel := c.Query("noise_level"); noiseLevel != "" {
-		filters["noise_level"] = noiseLevel
+	if len(fields) > 0 {
+		filters["fields"] = fields
+	}
+	if locale := translationLocale(c); locale != "" {
+		filters["locale"] = locale
 	}
 
-	// Power outlets filter (string: none|limited|moderate|plenty)
-	if powerOutlets := c.Query("power_outlets"); powerOutlets != "" {
-		filters["power_outlets"] = powerOutlets
+	// Per-value result counts for the filter sheet, e.g. facets=vibes,price_range
+	facets, err := repositories.ParseSearchFacets(query.Get("facets"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
 	}
 
-	// Cuisine filter (string)
-	if cuisine := c.Query("cuisine"); cuisine != "" {
-		filters["cuisine"] = cuisine
+	pois, err := h.repo.Search(ctx, filters, limit, offset)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
 	}
 
-	// Has AC filter (boolean)
-	if hasAC := c.Query("has_ac"); hasAC == "true" {
-		filters["has_ac"] = true
-	} else if hasAC == "false" {
-		filters["has_ac"] = false
+	// Only public first pages count as searches
+	if h.searchEvents != nil && offset == 0 && status == string(services.POIStatusApproved) {
+		h.searchEvents.RecordSearch(q, filters, len(pois))
+	}
+	if sessionID, ok := sessionIDFromRequest(c); ok && h.sessions != nil && offset == 0 && status == string(services.POIStatusApproved) {
+		if params := searchParams(query); len(params) > 0 {
+			if err := h.sessions.RecordSearch(ctx, sessionID, params); err != nil {
+				slog.WarnContext(ctx, "record session search failed", "error", err)
+			}
+		}
 	}
 
-	// Vibes filter (comma-separated array)
-	if vibes := c.Query("vibes"); vibes != "" {
-		filters["vibes"] = parseCommaSeparated(vibes)
+	data, err := projectFields(pois, fields, nil)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
 	}
 
-	// Crowd type filter (comma-separated array)
-	if crowdType := c.Query("crowd_type"); crowdType != "" {
-		filters["crowd_type"] = parseCommaSeparated(crowdType)
+	// Note: We currently don't have a total count from the repo, so we use the slice length + offset as a proxy or just the length.
+	// Ideally, the repo should return total count. For now, this standardizes the structure.
+	meta := utils.NewPagination(page, limit, len(pois)+offset)
+	meta.ApproximateLocation = approximate
+	if len(facets) > 0 {
+		if meta.Facets, err = h.repo.SearchFacets(ctx, filters, facets); err != nil {
+			utils.SendInternalError(c, err)
+			return
+		}
 	}
+	utils.SendPaginatedMeta(c, "POIs retrieved successfully", data, meta)
+}
 
-	// Dietary options filter (comma-separated array)
-	if dietaryOptions := c.Query("dietary_options"); dietaryOptions != "" {
-		filters["dietary_options"] = parseCommaSeparated(dietaryOptions)
+// approximateLocation estimates where the client is from its IP. Lookup
+// failures are logged and treated as unknown.
+func (h *POIHandler) approximateLocation(c *gin.Context) (geoip.Location, bool) {
+	if h.ipLocator == nil {
+		return geoip.Location{}, false
+	}
+	loc, ok, err := h.ipLocator.Locate(c.Request.Context(), c.ClientIP())
+	if err != nil {
+		slog.WarnContext(c.Request.Context(), "ip geolocation failed", "error", err)
+		return geoip.Location{}, false
 	}
+	return loc, ok
+}
 
-	// Seating options filter (comma-separated array)
-	if seatingOptions := c.Query("seating_options"); seatingOptions != "" {
-		filters["seating_options"] = parseCommaSeparated(seatingOptions)
+// searchIndex queries the text index. The index only holds approved POIs, so
+// other statuses always use the database.
+func (h *POIHandler) searchIndex(ctx context.Context, q, status string) ([]uuid.UUID, bool) {
+	if h.textSearch == nil || status != string(services.POIStatusApproved) {
+		return nil, false
+	}
+	ids, err := h.textSearch.Search(ctx, q, textSearchCandidates)
+	if err != nil {
+		slog.WarnContext(ctx, "search index unavailable, falling back to database", "error", err)
+		return nil, false
 	}
+	return ids, true
+}
+
+// GetPOI handles GET /api/v1/pois/:id
+func (h *POIHandler) GetPOI(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
 
-	// Parking options filter (comma-separated array)
-	if parkingOptions := c.Query("parking_options"); parkingOptions != "" {
-		filters["parking_options"] = parseCommaSeparated(parkingOptions)
+	poiID, err := uuid.Parse(id)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
 	}
 
-	// Sort by filter (string: recommended|nearest|top_rated)
-	if sortBy := c.Query("sort_by"); sortBy != "" {
-		filters["sort_by"] = sortBy
+	// Sparse fieldsets and related resources: ?fields=name,latitude&include=menu,reviews
+	fields, err := parseFields(c, true)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	includes, err := parseIncludes(c)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
 	}
 
-	// Lat/Lng parsing (needed for sort_by=nearest OR radius filter)
-	if latStr := c.Query("lat"); latStr != "" {
-		if lat, err := strconv.ParseFloat(latStr, 64); err == nil {
-			filters["lat"] = lat
-		}
+	// Conditional request: answer 304 from the version fingerprint before loading the POI
+	version, err := h.repo.GetVersion(ctx, poiID)
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return
 	}
-	if lngStr := c.Query("lng"); lngStr != "" {
-		if lng, err := strconv.ParseFloat(lngStr, 64); err == nil {
-			filters["lng"] = lng
-		}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	if version.Status == string(services.POIStatusTakenDown) {
+		h.sendGone(c, poiID)
+		return
+	}
+	h.recordView(c, poiID)
+	if notModified(c, poiETag(version, c), version.LastModified) {
+		c.Status(http.StatusNotModified)
+		return
 	}
 
-	// Radius filter (meters)
-	if radiusStr := c.Query("radius"); radiusStr != "" {
-		if radius, err := strconv.ParseFloat(radiusStr, 64); err == nil {
-			filters["radius"] = radius
+	poi, err := h.repo.GetByIDWithOptions(ctx, poiID, repositories.POIReadOptions{
+		Fields: fields,
+		Locale: translationLocale(c),
+	})
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return
+	}
+	setContentLanguage(c, poi)
+
+	var viewerID *uuid.UUID
+	if actor, ok := actorFromContext(c); ok {
+		viewerID = &actor.UserID
+		// The submitter and moderators see what a rejection asked to change
+		if isPOIOwner(poi, actor.UserID) || actor.Can(services.PermPOIApprove) {
+			if poi.RequiresChanges, err = h.repo.GetRejectionFeedback(ctx, poiID); err != nil {
+				utils.SendInternalError(c, err)
+				return
+			}
 		}
 	}
 
-	// WiFi Speed Min filter
-	if wifiSpeedMinStr := c.Query("wifi_speed_min"); wifiSpeedMinStr != "" {
-		if speed, err := strconv.Atoi(wifiSpeedMinStr); err == nil {
-			filters["wifi_speed_min"] = speed
-		}
+	if err := h.loadIncludes(ctx, poi, includes, viewerID); err != nil {
+		utils.SendInternalError(c, err)
+		return
 	}
 
-	pois, err := h.repo.Search(ctx, filters, limit, offset)
+	data, err := projectFields(poi, fields, includes)
 	if err != nil {
 		utils.SendInternalError(c, err)
 		return
 	}
 
-	// Note: We currently don't have a total count from the repo, so we use the slice length + offset as a proxy or just the length.
-	// Ideally, the repo should return total count. For now, this standardizes the structure.
-	utils.SendPaginated(c, "POIs retrieved successfully", pois, page, limit, len(pois)+offset)
+	utils.SendSuccess(c, "POI details retrieved", data)
 }
 
-// parseCommaSeparated splits a comma-separated string into a slice of strings
-func parseCommaSeparated(s string) []string {
-	if s == "" {
-		return nil
-	}
-	parts := strings.Split(s, ",")
-	result := make([]string, 0, len(parts))
-	for _, p := range parts {
-		trimmed := strings.TrimSpace(p)
-		if trimmed != "" {
-			result = append(result, trimmed)
+// recordView counts a detail view by the signed-in user, or else by anonymous
+// session or client IP and user agent, and adds it to the anonymous session's
+// recently viewed POIs. Crawlers are not counted.
+func (h *POIHandler) recordView(c *gin.Context, poiID uuid.UUID) {
+	actor, signedIn := actorFromContext(c)
+	sessionID, hasSession := sessionIDFromRequest(c)
+	if !signedIn && hasSession && h.sessions != nil {
+		if err := h.sessions.RecordView(c.Request.Context(), sessionID, poiID); err != nil {
+			slog.WarnContext(c.Request.Context(), "record session view failed", "error", err)
 		}
 	}
-	return result
-}
-
-// GetPOI handles GET /api/v1/pois/:id
-func (h *POIHandler) GetPOI(c *gin.Context) {
-	ctx := c.Request.Context()
-	id := c.Param("id")
 
-	poiID, err := uuid.Parse(id)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid POI ID format"})
+	if h.views == nil {
 		return
 	}
+	switch ua := c.Request.UserAgent(); {
+	case signedIn:
+		h.views.RecordView(poiID, "u:"+actor.UserID.String())
+	case isCrawler(ua):
+	case hasSession:
+		h.views.RecordView(poiID, "s:"+sessionID)
+	default:
+		h.views.RecordView(poiID, "c:"+c.ClientIP()+"|"+ua)
+	}
+}
 
-	poi, err := h.repo.GetByID(ctx, poiID)
-	if err != nil {
-		utils.SendError(c, http.StatusNotFound, "POI not found", err)
-		return
+// isCrawler reports whether a user agent looks like a bot
+func isCrawler(ua string) bool {
+	ua = strings.ToLower(ua)
+	if ua == "" {
+		return true
+	}
+	for _, marker := range []string{"bot", "crawl", "spider", "slurp", "preview", "curl", "wget"} {
+		if strings.Contains(ua, marker) {
+			return true
+		}
 	}
+	return false
+}
 
-	utils.SendSuccess(c, "POI details retrieved", poi)
+// PriceMetadata is the currency-aware average spend per person of a POI
+type PriceMetadata struct {
+	PriceCurrency *string  `json:"price_currency"` // ISO 4217, defaults to IDR
+	AvgSpendMin   *float64 `json:"avg_spend_min"`
+	AvgSpendMax   *float64 `json:"avg_spend_max"`
+}
+
+// validate upper-cases the currency code and checks it against ISO 4217 and
+// that the spend range is non-negative and ordered
+func (p *PriceMetadata) validate() error {
+	if p.PriceCurrency != nil {
+		code := strings.ToUpper(strings.TrimSpace(*p.PriceCurrency))
+		if !models.IsCurrencyCode(code) {
+			return fmt.Errorf("price_currency %q is not an ISO 4217 currency code", *p.PriceCurrency)
+		}
+		p.PriceCurrency = &code
+	}
+	if (p.AvgSpendMin != nil && *p.AvgSpendMin < 0) || (p.AvgSpendMax != nil && *p.AvgSpendMax < 0) {
+		return errors.New("avg_spend_min and avg_spend_max must not be negative")
+	}
+	if p.AvgSpendMin != nil && p.AvgSpendMax != nil && *p.AvgSpendMin > *p.AvgSpendMax {
+		return errors.New("avg_spend_min must not exceed avg_spend_max")
+	}
+	return nil
 }
 
 // CreatePOIRequest represents the JSON input for creating a POI
@@ -240,8 +485,9 @@ type CreatePOIRequest struct {
 	MusicType   *string  `json:"music_type"`
 	Cleanliness *string  `json:"cleanliness"`
 	// Food & Drink
-	Cuisine        *string  `json:"cuisine"`
-	PriceRange     *int     `json:"price_range"`
+	Cuisine    *string `json:"cuisine"`
+	PriceRange *int    `json:"price_range"` // Legacy 1-4 band
+	PriceMetadata
 	DietaryOptions []string `json:"dietary_options"`
 	FeaturedItems  []string `json:"featured_items"`
 	Specials       []string `json:"specials"`
@@ -254,9 +500,12 @@ type CreatePOIRequest struct {
 	// Social & Lifestyle
 	KidsFriendly   bool     `json:"kids_friendly"`
 	PetFriendly    []string `json:"pet_friendly"`
+	PetPolicy      *string  `json:"pet_policy"`
 	SmokerFriendly bool     `json:"smoker_friendly"`
 	HappyHourInfo  *string  `json:"happy_hour_info"`
 	LoyaltyProgram *string  `json:"loyalty_program"`
+	// Amenity keys from GET /api/v1/amenities; omit to keep the current ones
+	Amenities []string `json:"amenities" binding:"omitempty,max=50,dive,max=100"`
 	// Contact
 	Phone       *string                `json:"phone"`
 	Email       *string                `json:"email"`
@@ -271,7 +520,11 @@ func (h *POIHandler) CreatePOI(c *gin.Context) {
 
 	var input CreatePOIRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.SendValidationError(c, err)
+		return
+	}
+	if err := input.PriceMetadata.validate(); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
 		return
 	}
 
@@ -283,10 +536,23 @@ func (h *POIHandler) CreatePOI(c *gin.Context) {
 		}
 	}
 
+	if h.serviceAreas != nil {
+		inside, err := h.serviceAreas.InServiceArea(ctx, input.Latitude, input.Longitude)
+		if err != nil {
+			utils.SendInternalError(c, err)
+			return
+		}
+		if !inside {
+			utils.SendError(c, http.StatusUnprocessableEntity, services.ErrOutsideServiceArea.Error(), nil)
+			return
+		}
+	}
+
 	// Auto-calculate district via reverse geocoding for ALL new POIs
 	addrDetails, err := h.geocodingService.ReverseGeocode(input.Latitude, input.Longitude)
 	if err != nil {
-		// Log but continue
+		// Continue without the geocoded address fields
+		slog.WarnContext(ctx, "reverse geocoding failed", "error", err)
 	}
 
 	// Determine address fields: prefer Geocoded for hierarchy, User Input for street line
@@ -303,6 +569,11 @@ func (h *POIHandler) CreatePOI(c *gin.Context) {
 		postalCode = &addrDetails.PostalCode
 	}
 
+	moderation, ok := moderateText(c, h.textMod, textmod.FieldPOIDescription, input.Description)
+	if !ok {
+		return
+	}
+
 	// Determine status: user provided or default 'draft'
 	initialStatus := "draft"
 	if input.Status != nil && *input.Status == "pending" {
@@ -344,6 +615,9 @@ func (h *POIHandler) CreatePOI(c *gin.Context) {
 		// Food & Drink
 		Cuisine:        input.Cuisine,
 		PriceRange:     input.PriceRange,
+		PriceCurrency:  input.PriceCurrency,
+		AvgSpendMin:    input.AvgSpendMin,
+		AvgSpendMax:    input.AvgSpendMax,
 		DietaryOptions: input.DietaryOptions,
 		FeaturedItems:  input.FeaturedItems,
 		Specials:       input.Specials,
@@ -356,9 +630,11 @@ func (h *POIHandler) CreatePOI(c *gin.Context) {
 		// Social & Lifestyle
 		KidsFriendly:   input.KidsFriendly,
 		PetFriendly:    input.PetFriendly,
+		PetPolicy:      input.PetPolicy,
 		SmokerFriendly: input.SmokerFriendly,
 		HappyHourInfo:  input.HappyHourInfo,
 		LoyaltyProgram: input.LoyaltyProgram,
+		Amenities:      input.Amenities,
 		// Contact
 		Phone:       input.Phone,
 		Email:       input.Email,
@@ -368,10 +644,15 @@ func (h *POIHandler) CreatePOI(c *gin.Context) {
 		CreatedBy:     createdBy,
 		InitialStatus: &initialStatus,
 	})
+	if errors.Is(err, repositories.ErrInvalidAmenity) {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
 	if err != nil {
 		utils.SendInternalError(c, err)
 		return
 	}
+	recordModeration(ctx, h.textMod, poi.PoiID, createdBy, moderation)
 
 	// Use Created (201) and return the created object
 	utils.SendCreated(c, "POI created successfully", poi)
@@ -407,8 +688,9 @@ type UpdatePOIRequest struct {
 	MusicType   *string  `json:"music_type"`
 	Cleanliness *string  `json:"cleanliness"`
 	// Food & Drink
-	Cuisine        *string  `json:"cuisine"`
-	PriceRange     *int     `json:"price_range"`
+	Cuisine    *string `json:"cuisine"`
+	PriceRange *int    `json:"price_range"` // Legacy 1-4 band
+	PriceMetadata
 	DietaryOptions []string `json:"dietary_options"`
 	FeaturedItems  []string `json:"featured_items"`
 	Specials       []string `json:"specials"`
@@ -421,9 +703,12 @@ type UpdatePOIRequest struct {
 	// Social & Lifestyle
 	KidsFriendly   bool     `json:"kids_friendly"`
 	PetFriendly    []string `json:"pet_friendly"`
+	PetPolicy      *string  `json:"pet_policy"`
 	SmokerFriendly bool     `json:"smoker_friendly"`
 	HappyHourInfo  *string  `json:"happy_hour_info"`
 	LoyaltyProgram *string  `json:"loyalty_program"`
+	// Amenity keys from GET /api/v1/amenities; omit to keep the current ones
+	Amenities []string `json:"amenities" binding:"omitempty,max=50,dive,max=100"`
 	// Contact
 	Phone       *string                `json:"phone"`
 	Email       *string                `json:"email"`
@@ -432,7 +717,7 @@ type UpdatePOIRequest struct {
 }
 
 // UpdatePOI handles PUT /api/v1/pois/:id
-// Authorized for: POI owner OR admin
+// Authorized for: POI owner OR holder of poi:merge
 func (h *POIHandler) UpdatePOI(c *gin.Context) {
 	ctx := c.Request.Context()
 	id := c.Param("id")
@@ -452,10 +737,10 @@ func (h *POIHandler) UpdatePOI(c *gin.Context) {
 
 	// Get user info from context
 	userIDVal, userIDExists := c.Get("user_id")
-	role, _ := c.Get("user_role")
-	isAdmin := role == "admin"
+	actor, _ := actorFromContext(c)
+	canEditAny := actor.Can(services.PermPOIMerge)
 
-	// Authorization check: owner or admin
+	// Authorization check: owner or holder of poi:merge
 	// Special case: if created_by is NULL (orphan POI), allow any authenticated user to claim it
 	isOwner := false
 	if poi.CreatedBy != nil && userIDExists {
@@ -468,7 +753,7 @@ func (h *POIHandler) UpdatePOI(c *gin.Context) {
 	// This handles legacy POIs that were created before ownership tracking
 	isOrphanPOI := poi.CreatedBy == nil
 
-	if !isOwner && !isAdmin && !isOrphanPOI {
+	if !isOwner && !canEditAny && !isOrphanPOI {
 		utils.SendError(c, http.StatusForbidden, "not authorized to edit this POI", nil)
 		return
 	}
@@ -483,6 +768,15 @@ func (h *POIHandler) UpdatePOI(c *gin.Context) {
 		utils.SendError(c, http.StatusBadRequest, "invalid request body", err)
 		return
 	}
+	if err := input.PriceMetadata.validate(); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	moderation, ok := moderateText(c, h.textMod, textmod.FieldPOIDescription, input.Description)
+	if !ok {
+		return
+	}
 
 	err = h.repo.UpdateFull(ctx, poiID, repositories.UpdateFullInput{
 		Name:                 input.Name,
@@ -510,6 +804,9 @@ func (h *POIHandler) UpdatePOI(c *gin.Context) {
 		Cleanliness:          input.Cleanliness,
 		Cuisine:              input.Cuisine,
 		PriceRange:           input.PriceRange,
+		PriceCurrency:        input.PriceCurrency,
+		AvgSpendMin:          input.AvgSpendMin,
+		AvgSpendMax:          input.AvgSpendMax,
 		DietaryOptions:       input.DietaryOptions,
 		FeaturedItems:        input.FeaturedItems,
 		Specials:             input.Specials,
@@ -520,18 +817,25 @@ func (h *POIHandler) UpdatePOI(c *gin.Context) {
 		WaitTimeEstimate:     input.WaitTimeEstimate,
 		KidsFriendly:         input.KidsFriendly,
 		PetFriendly:          input.PetFriendly,
+		PetPolicy:            input.PetPolicy,
 		SmokerFriendly:       input.SmokerFriendly,
 		HappyHourInfo:        input.HappyHourInfo,
 		LoyaltyProgram:       input.LoyaltyProgram,
+		Amenities:            input.Amenities,
 		Phone:                input.Phone,
 		Email:                input.Email,
 		Website:              input.Website,
 		SocialLinks:          input.SocialLinks,
-	})
+	}, &actor.UserID)
+	if errors.Is(err, repositories.ErrInvalidAmenity) {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
 	if err != nil {
 		utils.SendInternalError(c, err)
 		return
 	}
+	recordModeration(ctx, h.textMod, poiID, &actor.UserID, moderation)
 
 	utils.SendSuccess(c, "POI updated successfully", gin.H{"poi_id": poiID})
 }
@@ -543,7 +847,7 @@ func (h *POIHandler) DeletePOI(c *gin.Context) {
 
 	poiID, err := uuid.Parse(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid POI ID format"})
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
 		return
 	}
 
@@ -580,42 +884,319 @@ func (h *POIHandler) GetMyPOIs(c *gin.Context) {
 	utils.SendPaginated(c, "User POIs retrieved", pois, page, limit, total)
 }
 
-// GetNearbyPOIs handles GET /api/v1/pois/nearby
-func (h *POIHandler) GetNearbyPOIs(c *gin.Context) {
+// Tile cells are geohashes of 5 to 7 characters (~4.9 km down to ~150 m wide)
+const (
+	tileMinPrecision = 5
+	tileMaxPrecision = 7
+	tileMaxPOIs      = 2000
+)
+
+// GetTile handles GET /api/v1/pois/tiles/:cell, returning the approved POIs in
+// a geohash cell and the 8 cells around it so clients can sync the map tile by
+// tile. Responses carry an ETag over their content and may be cached briefly
+// by shared caches; truncated is set when the tile holds more than can be returned.
+func (h *POIHandler) GetTile(c *gin.Context) {
+	cell := strings.ToLower(c.Param("cell"))
+	if !geohash.Valid(cell) || len(cell) < tileMinPrecision || len(cell) > tileMaxPrecision {
+		utils.SendError(c, http.StatusBadRequest, fmt.Sprintf("cell must be a geohash of %d to %d characters", tileMinPrecision, tileMaxPrecision), nil)
+		return
+	}
+	cells := append([]string{cell}, geohash.Neighbors(cell)...)
+
+	pois, err := h.repo.GetTile(c.Request.Context(), cells, tileMaxPOIs+1)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	truncated := len(pois) > tileMaxPOIs
+	if truncated {
+		pois = pois[:tileMaxPOIs]
+	}
+
+	etag, lastModified := tileETag(cells, pois)
+	if notModified(c, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=300, stale-while-revalidate=86400")
+
+	utils.SendSuccess(c, "POI tile retrieved", gin.H{
+		"cell":      cell,
+		"cells":     cells,
+		"pois":      pois,
+		"truncated": truncated,
+	})
+}
+
+// tileETag fingerprints the POIs of a tile, so edits, approvals and removals
+// all change it, and returns the latest POI update
+func tileETag(cells []string, pois []repositories.TilePOI) (string, time.Time) {
+	h := sha256.New()
+	h.Write([]byte(strings.Join(cells, ",")))
+	lastModified := time.Unix(0, 0)
+	for _, p := range pois {
+		h.Write([]byte{0})
+		h.Write(p.PoiID[:])
+		h.Write([]byte(p.UpdatedAt.UTC().Format(time.RFC3339Nano)))
+		if p.UpdatedAt.After(lastModified) {
+			lastModified = p.UpdatedAt
+		}
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`, lastModified
+}
+
+// DistancesRequest is the body for POST /api/v1/pois/distances
+type DistancesRequest struct {
+	Lat    *float64    `json:"lat" binding:"required,min=-90,max=90"`
+	Lng    *float64    `json:"lng" binding:"required,min=-180,max=180"`
+	POIIDs []uuid.UUID `json:"poi_ids" binding:"required,min=1,max=200"` // At most 200 per request
+}
+
+// GetDistances handles POST /api/v1/pois/distances, returning how far each
+// POI is from the given point (e.g. for a list of saved POIs). IDs that do
+// not exist or have no location are reported in missing.
+func (h *POIHandler) GetDistances(c *gin.Context) {
+	var input DistancesRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+
+	// Duplicates are answered once
+	seen := make(map[uuid.UUID]bool, len(input.POIIDs))
+	ids := make([]uuid.UUID, 0, len(input.POIIDs))
+	for _, id := range input.POIIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	distances, err := h.repo.GetDistances(c.Request.Context(), *input.Lat, *input.Lng, ids)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	missing := []uuid.UUID{}
+	if len(distances) < len(ids) {
+		found := make(map[uuid.UUID]bool, len(distances))
+		for _, d := range distances {
+			found[d.PoiID] = true
+		}
+		for _, id := range ids {
+			if !found[id] {
+				missing = append(missing, id)
+			}
+		}
+	}
+
+	utils.SendSuccess(c, "POI distances retrieved", gin.H{
+		"origin":    gin.H{"lat": *input.Lat, "lng": *input.Lng},
+		"distances": distances,
+		"missing":   missing,
+	})
+}
+
+// GetNearbySimilar handles GET /api/v1/pois/:id/nearby-similar?radius=2000&limit=10
+// for the related places section of the detail page
+func (h *POIHandler) GetNearbySimilar(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	lat, err := strconv.ParseFloat(c.Query("lat"), 64)
+	poiID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid latitude"})
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+	radius, err := strconv.Atoi(c.DefaultQuery("radius", "2000"))
+	if err != nil || radius < 100 || radius > 20000 {
+		utils.SendError(c, http.StatusBadRequest, "radius must be between 100 and 20000 meters", err)
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 50 {
+		utils.SendError(c, http.StatusBadRequest, "limit must be between 1 and 50", err)
+		return
+	}
+
+	if _, err := h.repo.GetVersion(ctx, poiID); errors.Is(err, sql.ErrNoRows) {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return
+	} else if err != nil {
+		utils.SendInternalError(c, err)
 		return
 	}
 
-	lng, err := strconv.ParseFloat(c.Query("lng"), 64)
+	pois, err := h.repo.GetNearbySimilar(ctx, poiID, radius, limit)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid longitude"})
+		utils.SendInternalError(c, err)
 		return
 	}
 
+	utils.SendSuccess(c, "Similar nearby POIs retrieved", gin.H{
+		"data":   pois,
+		"count":  len(pois),
+		"radius": radius,
+	})
+}
+
+// GetRecentPOIs handles GET /api/v1/pois/recent?days=7&area=&category_id=&cursor=&limit=20
+// for the "New places" section. Pass next_cursor from a response as cursor to
+// get the following page.
+func (h *POIHandler) GetRecentPOIs(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil || days < 1 || days > 90 {
+		utils.SendError(c, http.StatusBadRequest, "days must be between 1 and 90", err)
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 50 {
+		utils.SendError(c, http.StatusBadRequest, "limit must be between 1 and 50", err)
+		return
+	}
+
+	filter := repositories.RecentPOIFilter{
+		Since: time.Now().AddDate(0, 0, -days),
+		Area:  c.Query("area"),
+		Limit: limit,
+	}
+	if raw := c.Query("category_id"); raw != "" {
+		categoryID, err := uuid.Parse(raw)
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "invalid category_id format", err)
+			return
+		}
+		filter.CategoryID = &categoryID
+	}
+	if raw := c.Query("cursor"); raw != "" {
+		after, afterID, err := decodeTimeCursor(raw)
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "invalid cursor", err)
+			return
+		}
+		filter.After, filter.AfterID = &after, afterID
+	}
+
+	pois, err := h.repo.ListRecentlyApproved(c.Request.Context(), filter)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	var nextCursor *string
+	if len(pois) == limit {
+		last := pois[len(pois)-1]
+		cursor := encodeTimeCursor(last.ApprovedAt, last.PoiID)
+		nextCursor = &cursor
+	}
+
+	utils.SendSuccess(c, "Recent POIs retrieved", gin.H{
+		"pois":        pois,
+		"next_cursor": nextCursor,
+	})
+}
+
+// GetNearbyPOIs handles GET /api/v1/pois/nearby
+func (h *POIHandler) GetNearbyPOIs(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Without coordinates, search around the client IP's estimated location
+	var lat, lng float64
+	approximate := false
+	if c.Query("lat") == "" && c.Query("lng") == "" {
+		loc, ok := h.approximateLocation(c)
+		if !ok {
+			utils.SendError(c, http.StatusBadRequest, "lat and lng are required", nil)
+			return
+		}
+		lat, lng, approximate = loc.Lat, loc.Lng, true
+	} else {
+		var err error
+		if lat, err = strconv.ParseFloat(c.Query("lat"), 64); err != nil {
+			utils.SendError(c, http.StatusBadRequest, "invalid latitude", err)
+			return
+		}
+		if lng, err = strconv.ParseFloat(c.Query("lng"), 64); err != nil {
+			utils.SendError(c, http.StatusBadRequest, "invalid longitude", err)
+			return
+		}
+	}
+
 	radius, _ := strconv.Atoi(c.DefaultQuery("radius", "5000"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
 
+	var mode routing.Mode
+	if tm := c.Query("travel_mode"); tm != "" {
+		var ok bool
+		if mode, ok = routing.ParseMode(tm); !ok {
+			utils.SendError(c, http.StatusBadRequest, "travel_mode must be walking, cycling or driving", nil)
+			return
+		}
+	}
+
 	pois, err := h.repo.GetNearby(ctx, lat, lng, radius, limit)
 	if err != nil {
 		utils.SendInternalError(c, err)
 		return
 	}
 
-	utils.SendSuccess(c, "Nearby POIs retrieved", gin.H{
+	resp := gin.H{
 		"data":   pois,
 		"count":  len(pois),
 		"center": gin.H{"lat": lat, "lng": lng},
 		"radius": radius,
+	}
+	if approximate {
+		resp["approximate_location"] = true
+	}
+	if mode != "" && h.addTravelTimes(ctx, mode, routing.Point{Lat: lat, Lng: lng}, pois) {
+		resp["travel_mode"] = mode
+	}
+
+	utils.SendSuccess(c, "Nearby POIs retrieved", resp)
+}
+
+// addTravelTimes fills in travel estimates and orders POIs by travel time,
+// unreachable ones last. On provider failure the straight-line results are
+// left untouched and false is returned.
+func (h *POIHandler) addTravelTimes(ctx context.Context, mode routing.Mode, origin routing.Point, pois []repositories.POIWithDistance) bool {
+	if h.travelTimes == nil || len(pois) == 0 {
+		return false
+	}
+
+	dests := make([]routing.Point, len(pois))
+	for i, p := range pois {
+		dests[i] = routing.Point{Lat: p.Latitude, Lng: p.Longitude}
+	}
+	legs, err := h.travelTimes.Legs(ctx, mode, origin, dests)
+	if err != nil {
+		slog.WarnContext(ctx, "routing provider unavailable, returning straight-line distances", "error", err)
+		return false
+	}
+
+	for i, leg := range legs {
+		if leg.OK {
+			pois[i].TravelDurationSeconds = &leg.DurationSeconds
+			pois[i].TravelDistanceMeters = &leg.DistanceMeters
+		}
+	}
+	sort.SliceStable(pois, func(i, j int) bool {
+		a, b := pois[i].TravelDurationSeconds, pois[j].TravelDurationSeconds
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return *a < *b
 	})
+	return true
 }
 
-// GetFilterOptions handles GET /api/v1/pois/filter-options
+// GetFilterOptions handles GET /api/v1/pois/filter-options?area=<slug>. Every
+// option counts the approved POIs in the area (everywhere without one) that
+// match it, so clients can hide empty filters. Vibes, crowd types, dietary,
+// seating and parking options, cuisines and amenities come from the
+// vocabularies; labels follow ?lang= / Accept-Language.
 func (h *POIHandler) GetFilterOptions(c *gin.Context) {
-	utils.SendSuccess(c, "Filter options retrieved", gin.H{
+	data := gin.H{
 		"sort_options": []gin.H{
 			{"value": "recommended", "label": "Recommended"},
 			{"value": "nearest", "label": "Nearest"},
@@ -647,46 +1228,6 @@ func (h *POIHandler) GetFilterOptions(c *gin.Context) {
 			{"value": "moderate", "label": "Mid"},
 			{"value": "plenty", "label": "Many"},
 		},
-		"vibes": []gin.H{
-			{"value": "industrial", "label": "Industrial", "icon": "factory"},
-			{"value": "cozy", "label": "Cozy", "icon": "chair"},
-			{"value": "tropical", "label": "Tropical", "icon": "potted_plant"},
-			{"value": "minimalist", "label": "Minimalist", "icon": "crop_square"},
-			{"value": "luxury", "label": "Luxury", "icon": "diamond"},
-			{"value": "retro", "label": "Retro", "icon": "radio"},
-			{"value": "nature", "label": "Nature", "icon": "park"},
-		},
-		"crowd_types": []gin.H{
-			{"value": "quiet_study", "label": "Quiet / Study"},
-			{"value": "social_lively", "label": "Social / Lively"},
-			{"value": "business", "label": "Business"},
-		},
-		"dietary_options": []gin.H{
-			{"value": "vegan", "label": "Vegan"},
-			{"value": "vegetarian", "label": "Vegetarian"},
-			{"value": "halal", "label": "Halal"},
-			{"value": "gluten_free", "label": "Gluten-Free"},
-			{"value": "nut_free", "label": "Nut-Free"},
-		},
-		"seating_options": []gin.H{
-			{"value": "ergonomic", "label": "Ergonomic", "icon": "chair"},
-			{"value": "communal", "label": "Communal", "icon": "table_restaurant"},
-			{"value": "high-tops", "label": "High-tops", "icon": "countertops"},
-			{"value": "outdoor", "label": "Outdoor", "icon": "deck"},
-			{"value": "private-booths", "label": "Private Booths", "icon": "meeting_room"},
-		},
-		"parking_options": []gin.H{
-			{"value": "car", "label": "Car Parking"},
-			{"value": "motorcycle", "label": "Motorcycle"},
-			{"value": "valet", "label": "Valet Service"},
-		},
-		"cuisines": []gin.H{
-			{"value": "italian", "label": "Italian"},
-			{"value": "japanese", "label": "Japanese"},
-			{"value": "mexican", "label": "Mexican"},
-			{"value": "fusion", "label": "Fusion"},
-			{"value": "cafe", "label": "Cafe"},
-		},
 		"quick_filters": []gin.H{
 			{
 				"id":    "deep_work",
@@ -717,117 +1258,261 @@ func (h *POIHandler) GetFilterOptions(c *gin.Context) {
 			},
 		},
 		"timestamp": time.Now().Unix(),
-	})
+	}
+
+	if h.filterOptions != nil {
+		options, err := h.filterOptions.GetFilterOptions(c.Request.Context(), c.Query("area"), labelLocales(c))
+		if errors.Is(err, repositories.ErrServiceAreaNotFound) {
+			utils.SendError(c, http.StatusNotFound, "Service area not found", err)
+			return
+		}
+		if err != nil {
+			utils.SendInternalError(c, err)
+			return
+		}
+		for key, param := range map[string]string{
+			"price_ranges":  "price_range",
+			"wifi_quality":  "wifi_quality",
+			"noise_levels":  "noise_level",
+			"power_outlets": "power_outlets",
+		} {
+			for _, option := range data[key].([]gin.H) {
+				if option["value"] == "any" {
+					option["count"] = options.TotalPOIs
+				} else {
+					option["count"] = options.Count(param, fmt.Sprint(option["value"]))
+				}
+			}
+		}
+		data["vibes"] = options.Vibes
+		data["crowd_types"] = options.CrowdTypes
+		data["dietary_options"] = options.DietaryOptions
+		data["seating_options"] = options.SeatingOptions
+		data["parking_options"] = options.ParkingOptions
+		data["cuisines"] = options.Cuisines
+		data["amenities"] = options.Amenities
+		data["total_pois"] = options.TotalPOIs
+		data["generated_at"] = options.GeneratedAt
+		c.Header("Content-Language", c.GetString("locale"))
+	}
+
+	utils.SendSuccess(c, "Filter options retrieved", data)
 }
 
 // SubmitPOI handles POST /api/v1/pois/:id/submit
 func (h *POIHandler) SubmitPOI(c *gin.Context) {
-	ctx := c.Request.Context()
-	id := c.Param("id")
+	h.transitionPOI(c, services.POIStatusPending, nil, "POI submitted for review")
+}
 
-	poiID, err := uuid.Parse(id)
-	if err != nil {
-		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+// ApprovePOI handles POST /api/v1/pois/:id/approve?force=true (requires poi:approve)
+func (h *POIHandler) ApprovePOI(c *gin.Context) {
+	// TODO: Trigger XP reward logic (+100 XP) for the user who submitted/created this POI (BE-104)
+	// via h.workflow.Subscribe once the XP module exists
+	h.transitionPOI(c, services.POIStatusApproved, nil, "POI approved")
+}
+
+// RejectPOIRequest for rejection reason
+type RejectPOIRequest struct {
+	Reason   string                     `json:"reason" binding:"required"`
+	Feedback []RejectionFeedbackRequest `json:"feedback" binding:"max=50,dive"`
+}
+
+// RejectionFeedbackRequest is a comment on a section, or one field of it,
+// that the submitter must address
+type RejectionFeedbackRequest struct {
+	Section string  `json:"section" binding:"required"`
+	Field   *string `json:"field" binding:"omitempty,max=64"`
+	Comment string  `json:"comment" binding:"required,max=1000"`
+}
+
+// RejectPOI handles POST /api/v1/pois/:id/reject?force=true (requires poi:approve)
+func (h *POIHandler) RejectPOI(c *gin.Context) {
+	var input RejectPOIRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
 		return
 	}
 
-	// Verify ownership
-	poi, err := h.repo.GetByID(ctx, poiID)
+	feedback := make([]repositories.RejectionFeedback, 0, len(input.Feedback))
+	for _, item := range input.Feedback {
+		if !repositories.IsPOISection(item.Section) {
+			utils.SendError(c, http.StatusBadRequest, fmt.Sprintf("unknown section %q", item.Section), nil)
+			return
+		}
+		feedback = append(feedback, repositories.RejectionFeedback{Section: item.Section, Field: item.Field, Comment: item.Comment})
+	}
+
+	poiID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
 		return
 	}
-
-	// Get user from context
-	_, exists := c.Get("user_id")
-	if !exists {
+	actor, ok := actorFromContext(c)
+	if !ok {
 		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
 
-	// Ownership check removed as per requirement "anyone can submit POI"
-	// if poi.CreatedBy == nil || *poi.CreatedBy != userID.(uuid.UUID) {
-	// 	c.JSON(http.StatusForbidden, gin.H{"error": "not authorized to submit this POI"})
-	// 	return
-	// }
-
-	// Allow draft, rejected, approved, and pending POIs to be submitted/re-submitted
-	if poi.Status != "draft" && poi.Status != "rejected" && poi.Status != "approved" && poi.Status != "pending" {
-		utils.SendError(c, http.StatusBadRequest, "can only submit draft, rejected, pending, or approved POIs", nil)
+	// The feedback replaces any from an earlier rejection, in the same transaction
+	event, err := h.workflow.TransitionWith(reviewContext(c), poiID, services.POIStatusRejected, actor, &input.Reason,
+		func(ctx context.Context, _ services.TransitionEvent) error {
+			return h.repo.ReplaceRejectionFeedback(ctx, poiID, feedback, &actor.UserID)
+		})
+	if err != nil {
+		sendWorkflowError(c, err)
 		return
 	}
 
-	if err := h.repo.UpdateStatus(ctx, poiID, "pending", nil); err != nil {
-		utils.SendInternalError(c, err)
-		return
-	}
+	utils.SendSuccess(c, "POI rejected", gin.H{
+		"poi_id":           poiID,
+		"status":           event.To,
+		"previous_status":  event.From,
+		"requires_changes": feedback,
+	})
+}
 
-	utils.SendSuccess(c, "POI submitted for review", nil)
+// ArchivePOI handles POST /api/v1/pois/:id/archive (requires poi:approve)
+func (h *POIHandler) ArchivePOI(c *gin.Context) {
+	h.transitionPOI(c, services.POIStatusArchived, nil, "POI archived")
 }
 
-// ApprovePOI handles POST /api/v1/pois/:id/approve (admin only)
-func (h *POIHandler) ApprovePOI(c *gin.Context) {
-	ctx := c.Request.Context()
-	id := c.Param("id")
+// transitionPOI runs a single workflow transition for the POI in the :id param
+func (h *POIHandler) transitionPOI(c *gin.Context, to services.POIStatus, reason *string, message string) {
+	ctx := reviewContext(c)
 
-	poiID, err := uuid.Parse(id)
+	poiID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid POI ID format"})
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
 		return
 	}
 
-	// Check admin role from context
-	role, exists := c.Get("user_role")
-	if !exists || role != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
 
-	if err := h.repo.UpdateStatus(ctx, poiID, "approved", nil); err != nil {
-		utils.SendInternalError(c, err)
+	event, err := h.workflow.Transition(ctx, poiID, to, actor, reason)
+	if err != nil {
+		sendWorkflowError(c, err)
 		return
 	}
 
-	// TODO: Trigger XP reward logic (+100 XP) for the user who submitted/created this POI (BE-104)
-
-	utils.SendSuccess(c, "POI approved", nil)
+	utils.SendSuccess(c, message, gin.H{
+		"poi_id":          poiID,
+		"status":          event.To,
+		"previous_status": event.From,
+	})
 }
 
-// RejectPOIRequest for rejection reason
-type RejectPOIRequest struct {
-	Reason string `json:"reason" binding:"required"`
+// maxBatchStatusItems caps the number of POIs in a single batch request
+const maxBatchStatusItems = 100
+
+// BatchStatusRequest is the body for POST /api/v1/admin/pois/batch-status
+type BatchStatusRequest struct {
+	PoiIDs []uuid.UUID `json:"poi_ids" binding:"required,min=1"`
+	Status string      `json:"status" binding:"required,oneof=approved rejected"`
+	Reason string      `json:"reason"`
+	Force  bool        `json:"force"` // Decide on POIs assigned to other reviewers too
 }
 
-// RejectPOI handles POST /api/v1/pois/:id/reject (admin only)
-func (h *POIHandler) RejectPOI(c *gin.Context) {
+// BatchUpdateStatus handles POST /api/v1/admin/pois/batch-status (requires poi:approve)
+func (h *POIHandler) BatchUpdateStatus(c *gin.Context) {
 	ctx := c.Request.Context()
-	id := c.Param("id")
 
-	poiID, err := uuid.Parse(id)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid POI ID format"})
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
 
-	// Check admin role
-	role, exists := c.Get("user_role")
-	if !exists || role != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+	var input BatchStatusRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
 		return
 	}
-
-	var input RejectPOIRequest
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if len(input.PoiIDs) > maxBatchStatusItems {
+		utils.SendError(c, http.StatusBadRequest, fmt.Sprintf("at most %d POIs per batch", maxBatchStatusItems), nil)
 		return
 	}
 
-	if err := h.repo.UpdateStatus(ctx, poiID, "rejected", &input.Reason); err != nil {
+	var reason *string
+	if input.Status == string(services.POIStatusRejected) {
+		if strings.TrimSpace(input.Reason) == "" {
+			utils.SendError(c, http.StatusBadRequest, "reason is required when rejecting", nil)
+			return
+		}
+		reason = &input.Reason
+	}
+
+	// Drop duplicate IDs so each POI is reported once
+	seen := make(map[uuid.UUID]bool, len(input.PoiIDs))
+	ids := make([]uuid.UUID, 0, len(input.PoiIDs))
+	for _, id := range input.PoiIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if input.Force {
+		ctx = services.OverrideAssignment(ctx)
+	}
+	results, err := h.workflow.BatchTransition(ctx, ids, services.POIStatus(input.Status), actor, reason)
+	if err != nil {
 		utils.SendInternalError(c, err)
 		return
 	}
 
-	utils.SendSuccess(c, "POI rejected", nil)
+	updated := 0
+	for _, r := range results {
+		if r.Result == "updated" {
+			updated++
+		}
+	}
+
+	utils.SendSuccess(c, "Batch status update completed", gin.H{
+		"status":  input.Status,
+		"updated": updated,
+		"failed":  len(results) - updated,
+		"results": results,
+	})
+}
+
+// actorFromContext builds a workflow actor from the values set by AuthMiddleware
+func actorFromContext(c *gin.Context) (services.Actor, bool) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		return services.Actor{}, false
+	}
+	role, _ := c.Get("user_role")
+	roleStr, _ := role.(string)
+	if roleStr == "" {
+		roleStr = services.RoleUser
+	}
+	perms, _ := c.Get("permissions")
+	permSet, _ := perms.(services.PermissionSet)
+	return services.Actor{UserID: userID.(uuid.UUID), Role: roleStr, Permissions: permSet}, true
+}
+
+// sendWorkflowError maps workflow errors to HTTP responses
+func sendWorkflowError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrPOINotFound):
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+	case errors.Is(err, services.ErrTransitionForbidden):
+		utils.SendError(c, http.StatusForbidden, "not permitted to change this POI's status", err)
+	case errors.Is(err, services.ErrInvalidTransition):
+		utils.SendError(c, http.StatusConflict, "status change not allowed", err)
+	case errors.Is(err, services.ErrReasonRequired):
+		utils.SendError(c, http.StatusBadRequest, "reason is required", err)
+	case errors.Is(err, services.ErrOutsideServiceArea):
+		utils.SendError(c, http.StatusUnprocessableEntity, err.Error(), err)
+	case errors.Is(err, services.ErrAssignedToOther):
+		utils.SendError(c, http.StatusConflict, "POI is assigned to another reviewer; retry with force=true to override", err)
+	default:
+		utils.SendInternalError(c, err)
+	}
 }
 
 // GetMyDrafts handles GET /api/v1/pois/my-drafts
@@ -836,7 +1521,7 @@ func (h *POIHandler) GetMyDrafts(c *gin.Context) {
 
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
 
@@ -852,20 +1537,15 @@ func (h *POIHandler) GetMyDrafts(c *gin.Context) {
 	utils.SendPaginated(c, "Drafts retrieved", pois, page, limit, len(pois)+offset)
 }
 
-// GetPendingPOIs handles GET /api/v1/pois/pending (admin only)
+// GetPendingPOIs handles GET /api/v1/pois/pending?sort=completeness&overdue=true (requires poi:approve)
 func (h *POIHandler) GetPendingPOIs(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	role, exists := c.Get("user_role")
-	if !exists || role != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
-		return
-	}
-
 	page, limit := utils.GetPagination(c)
 	offset := utils.GetOffset(page, limit)
+	overdue := c.Query("overdue") == "true"
 
-	pois, err := h.repo.GetByStatus(ctx, "pending", limit, offset)
+	pois, err := h.repo.GetByStatusOrdered(ctx, "pending", c.Query("sort"), overdue, limit, offset)
 	if err != nil {
 		utils.SendInternalError(c, err)
 		return
@@ -874,21 +1554,15 @@ func (h *POIHandler) GetPendingPOIs(c *gin.Context) {
 	utils.SendPaginated(c, "Pending POIs retrieved", pois, page, limit, len(pois)+offset)
 }
 
-// GetAdminPOIs handles GET /api/v1/pois/admin-list?status=... (admin only)
+// GetAdminPOIs handles GET /api/v1/pois/admin-list?status=...&sort=completeness (requires poi:approve)
 func (h *POIHandler) GetAdminPOIs(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	role, exists := c.Get("user_role")
-	if !exists || role != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
-		return
-	}
-
 	status := c.DefaultQuery("status", "pending")
 	page, limit := utils.GetPagination(c)
 	offset := utils.GetOffset(page, limit)
 
-	pois, err := h.repo.GetByStatus(ctx, status, limit, offset)
+	pois, err := h.repo.GetByStatusOrdered(ctx, status, c.Query("sort"), false, limit, offset)
 	if err != nil {
 		utils.SendInternalError(c, err)
 		return
@@ -896,3 +1570,20 @@ func (h *POIHandler) GetAdminPOIs(c *gin.Context) {
 
 	utils.SendPaginated(c, "Admin POI list retrieved", pois, page, limit, len(pois)+offset)
 }
+
+// GetQueueMetrics handles GET /api/v1/admin/queue/metrics?days=30 (requires poi:approve)
+func (h *POIHandler) GetQueueMetrics(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 365 {
+		utils.SendError(c, http.StatusBadRequest, "days must be between 1 and 365", err)
+		return
+	}
+
+	metrics, err := h.repo.GetQueueMetrics(c.Request.Context(), days)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Moderation queue metrics retrieved", metrics)
+}
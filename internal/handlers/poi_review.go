@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/services"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// reviewContext is the request context, marked to override reviewer
+// assignments when the request asks for ?force=true
+func reviewContext(c *gin.Context) context.Context {
+	if c.Query("force") == "true" {
+		return services.OverrideAssignment(c.Request.Context())
+	}
+	return c.Request.Context()
+}
+
+// AssignReviewerRequest is the body for POST /api/v1/admin/pois/:id/assign
+type AssignReviewerRequest struct {
+	UserID *uuid.UUID `json:"user_id"` // Defaults to the caller
+	Force  bool       `json:"force"`   // Take the POI over from another reviewer
+}
+
+// AssignReviewer handles POST /api/v1/admin/pois/:id/assign (requires poi:approve)
+func (h *POIHandler) AssignReviewer(c *gin.Context) {
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var input AssignReviewerRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			utils.SendValidationError(c, err)
+			return
+		}
+	}
+	assignee := actor.UserID
+	if input.UserID != nil {
+		assignee = *input.UserID
+	}
+
+	assignment, err := h.repo.AssignReviewer(c.Request.Context(), poiID, assignee, h.assignmentTimeout, input.Force)
+	if err != nil {
+		sendAssignmentError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Reviewer assigned", assignment)
+}
+
+// ReleaseReviewer handles DELETE /api/v1/admin/pois/:id/assign?force=true (requires poi:approve)
+func (h *POIHandler) ReleaseReviewer(c *gin.Context) {
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	if err := h.repo.ReleaseReviewer(c.Request.Context(), poiID, actor.UserID, c.Query("force") == "true"); err != nil {
+		sendAssignmentError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Reviewer released", gin.H{"poi_id": poiID})
+}
+
+// sendAssignmentError maps reviewer assignment errors to HTTP responses
+func sendAssignmentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+	case errors.Is(err, repositories.ErrAssigneeNotReviewer):
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, repositories.ErrNotPendingReview):
+		utils.SendError(c, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, repositories.ErrReviewerAssigned):
+		utils.SendError(c, http.StatusConflict, err.Error()+"; retry with force to take it over", nil)
+	default:
+		utils.SendInternalError(c, err)
+	}
+}
@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
-	"time"
 
 	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/textmod"
 	"maukemana-backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -16,19 +18,25 @@ import (
 // POISectionRepository defines the interface for POI section data access
 type POISectionRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*repositories.POI, error)
-	UpdateProfile(ctx context.Context, poiID uuid.UUID, input repositories.CreatePOIInput) error
-	UpdateLocation(ctx context.Context, poiID uuid.UUID, input repositories.CreatePOIInput) error
-	UpdateOperations(ctx context.Context, poiID uuid.UUID, input repositories.CreatePOIInput) error
-	UpdateWorkProd(ctx context.Context, poiID uuid.UUID, input repositories.CreatePOIInput) error
-	UpdateAtmosphere(ctx context.Context, poiID uuid.UUID, input repositories.CreatePOIInput) error
-	UpdateFoodDrink(ctx context.Context, poiID uuid.UUID, input repositories.CreatePOIInput) error
-	UpdateSocial(ctx context.Context, poiID uuid.UUID, input repositories.CreatePOIInput) error
-	UpdateContact(ctx context.Context, poiID uuid.UUID, input repositories.CreatePOIInput) error
+	GetSectionEdit(ctx context.Context, poiID uuid.UUID, section string) (*repositories.SectionEdit, error)
+	UpdateProfile(ctx context.Context, poiID uuid.UUID, input repositories.CreatePOIInput, editedBy *uuid.UUID) (*repositories.POI, error)
+	UpdateLocation(ctx context.Context, poiID uuid.UUID, input repositories.CreatePOIInput, editedBy *uuid.UUID) (*repositories.POI, error)
+	UpdateOperations(ctx context.Context, poiID uuid.UUID, input repositories.CreatePOIInput, editedBy *uuid.UUID) (*repositories.POI, error)
+	UpdateWorkProd(ctx context.Context, poiID uuid.UUID, input repositories.CreatePOIInput, editedBy *uuid.UUID) (*repositories.POI, error)
+	UpdateAtmosphere(ctx context.Context, poiID uuid.UUID, input repositories.CreatePOIInput, editedBy *uuid.UUID) (*repositories.POI, error)
+	UpdateFoodDrink(ctx context.Context, poiID uuid.UUID, input repositories.CreatePOIInput, editedBy *uuid.UUID) (*repositories.POI, error)
+	UpdateSocial(ctx context.Context, poiID uuid.UUID, input repositories.CreatePOIInput, editedBy *uuid.UUID) (*repositories.POI, error)
+	UpdateContact(ctx context.Context, poiID uuid.UUID, input repositories.CreatePOIInput, editedBy *uuid.UUID) (*repositories.POI, error)
+	UpdateAccessibility(ctx context.Context, poiID uuid.UUID, input repositories.CreatePOIInput, editedBy *uuid.UUID) (*repositories.POI, error)
 }
 
+// tableHeights are the accepted accessibility table_heights values
+var tableHeights = map[string]bool{"low": true, "standard": true, "high": true, "adjustable": true}
+
 // POISectionHandler handles requests for specific POI sections
 type POISectionHandler struct {
-	repo POISectionRepository
+	repo    POISectionRepository
+	textMod TextModerator
 }
 
 // NewPOISectionHandler creates a new POISectionHandler
@@ -36,32 +44,9 @@ func NewPOISectionHandler(repo POISectionRepository) *POISectionHandler {
 	return &POISectionHandler{repo: repo}
 }
 
-// getPOIWithRetry attempts to fetch a POI with retry logic for transient errors
-// This helps handle database contention during concurrent read/write operations
-func (h *POISectionHandler) getPOIWithRetry(ctx context.Context, poiID uuid.UUID) (*repositories.POI, error) {
-	var poi *repositories.POI
-	var err error
-
-	// Try up to 3 times with exponential backoff
-	delays := []time.Duration{0, 50 * time.Millisecond, 100 * time.Millisecond}
-
-	for i, delay := range delays {
-		if delay > 0 {
-			time.Sleep(delay)
-		}
-
-		poi, err = h.repo.GetByID(ctx, poiID)
-		if err == nil {
-			return poi, nil
-		}
-
-		// If it's the last attempt, return the error
-		if i == len(delays)-1 {
-			return nil, err
-		}
-	}
-
-	return nil, err
+// UseTextModerator masks profanity in POI descriptions and flags misplaced contact details
+func (h *POISectionHandler) UseTextModerator(m TextModerator) {
+	h.textMod = m
 }
 
 // GetPOIProfile handles GET /api/v1/pois/:id/section/profile
@@ -72,29 +57,13 @@ func (h *POISectionHandler) GetPOIProfile(c *gin.Context) {
 		return
 	}
 
-	poi, err := h.getPOIWithRetry(c.Request.Context(), poiID)
+	poi, err := h.repo.GetByID(c.Request.Context(), poiID)
 	if err != nil {
 		utils.SendError(c, http.StatusNotFound, "POI not found", err)
 		return
 	}
 
-	// map to profile structure
-	response := map[string]interface{}{
-		"name":               poi.Name,
-		"brand_name":         poi.Brand,
-		"categories":         poi.CategoryNames,
-		"description":        poi.Description,
-		"cover_image_url":    poi.CoverImageURL,
-		"gallery_image_urls": poi.GalleryImageURLs,
-		"category_ids":       poi.CategoryIDs,
-	}
-
-	if len(poi.CategoryNames) == 0 && poi.CategoryID != nil {
-		// Fallback if joined query failed but single ID exists w/o name? Unlikely with subquery but safe
-		// Actually subquery handles both.
-	}
-
-	utils.SendSuccess(c, "POI profile retrieved", response)
+	h.sendSection(c, "POI profile retrieved", "profile", poi, profileSection(poi))
 }
 
 // UpdatePOIProfile handles PUT /api/v1/pois/:id/section/profile
@@ -126,6 +95,11 @@ func (h *POISectionHandler) UpdatePOIProfile(c *gin.Context) {
 		return
 	}
 
+	moderation, ok := moderateText(c, h.textMod, textmod.FieldPOIDescription, req.Description)
+	if !ok {
+		return
+	}
+
 	updateInput := repositories.CreatePOIInput{
 		Name:             req.Name,
 		BrandName:        req.BrandName,
@@ -136,12 +110,14 @@ func (h *POISectionHandler) UpdatePOIProfile(c *gin.Context) {
 		CategoryIDs:      req.CategoryIDs,
 	}
 
-	if err := h.repo.UpdateProfile(c.Request.Context(), poiID, updateInput); err != nil {
-		utils.SendInternalError(c, err)
+	poi, err := h.repo.UpdateProfile(c.Request.Context(), poiID, updateInput, editorID(c))
+	if err != nil {
+		sendSectionError(c, err)
 		return
 	}
+	recordModeration(c.Request.Context(), h.textMod, poiID, editorID(c), moderation)
 
-	utils.SendSuccess(c, "POI profile updated", nil)
+	h.sendSection(c, "POI profile updated", "profile", poi, profileSection(poi))
 }
 
 // GetPOILocation handles GET /api/v1/pois/:id/section/location
@@ -152,36 +128,13 @@ func (h *POISectionHandler) GetPOILocation(c *gin.Context) {
 		return
 	}
 
-	poi, err := h.getPOIWithRetry(c.Request.Context(), poiID)
+	poi, err := h.repo.GetByID(c.Request.Context(), poiID)
 	if err != nil {
 		utils.SendError(c, http.StatusNotFound, "POI not found", err)
 		return
 	}
 
-	// Extract lat/long from POINT if we can, or rely on separate storage if any
-	// The repo GetByID returns a struct that doesn't explicitly expose Lat/Long usually
-	// unless mapped from PostGIS. Let's assume we need to deal with that, but for now
-	// we'll return what's available.
-	// Actually, GetByID query implies it fetches everything but Lat/Long distinct columns
-	// are NOT in the struct shown! The repository normally needs ST_X/ST_Y selection.
-	// Let's defer strict lat/long fix and focus on structure.
-
-	address := ""
-	if poi.Address != nil {
-		address = *poi.Address
-	}
-
-	response := map[string]interface{}{
-		"address":               address,
-		"floor_unit":            poi.FloorUnit,
-		"latitude":              poi.Latitude,
-		"longitude":             poi.Longitude,
-		"public_transport":      poi.PublicTransport,
-		"parking_options":       poi.ParkingOptions,
-		"wheelchair_accessible": poi.IsWheelchairAccessible,
-	}
-
-	utils.SendSuccess(c, "POI location retrieved", response)
+	h.sendSection(c, "POI location retrieved", "location", poi, locationSection(poi))
 }
 
 // GetPOIOperations handles GET /api/v1/pois/:id/section/operations
@@ -192,27 +145,13 @@ func (h *POISectionHandler) GetPOIOperations(c *gin.Context) {
 		return
 	}
 
-	poi, err := h.getPOIWithRetry(c.Request.Context(), poiID)
+	poi, err := h.repo.GetByID(c.Request.Context(), poiID)
 	if err != nil {
 		utils.SendError(c, http.StatusNotFound, "POI not found", err)
 		return
 	}
 
-	// Unmarshal open_hours if present
-	var openHours map[string]interface{}
-	if poi.OpenHours != nil {
-		_ = json.Unmarshal(*poi.OpenHours, &openHours)
-	}
-
-	response := map[string]interface{}{
-		"open_hours":           openHours,
-		"reservation_required": poi.ReservationRequired,
-		"reservation_platform": poi.ReservationPlatform,
-		"payment_options":      poi.PaymentOptions,
-		"wait_time_estimate":   poi.WaitTimeEstimate,
-	}
-
-	utils.SendSuccess(c, "POI operations retrieved", response)
+	h.sendSection(c, "POI operations retrieved", "operations", poi, operationsSection(poi))
 }
 
 // UpdatePOIOperations handles PUT /api/v1/pois/:id/section/operations
@@ -244,12 +183,13 @@ func (h *POISectionHandler) UpdatePOIOperations(c *gin.Context) {
 		WaitTimeEstimate:    req.WaitTimeEstimate,
 	}
 
-	if err := h.repo.UpdateOperations(c.Request.Context(), poiID, updateInput); err != nil {
-		utils.SendInternalError(c, err)
+	poi, err := h.repo.UpdateOperations(c.Request.Context(), poiID, updateInput, editorID(c))
+	if err != nil {
+		sendSectionError(c, err)
 		return
 	}
 
-	utils.SendSuccess(c, "POI operations updated", nil)
+	h.sendSection(c, "POI operations updated", "operations", poi, operationsSection(poi))
 }
 
 // GetPOISocial handles GET /api/v1/pois/:id/section/social
@@ -260,22 +200,13 @@ func (h *POISectionHandler) GetPOISocial(c *gin.Context) {
 		return
 	}
 
-	poi, err := h.getPOIWithRetry(c.Request.Context(), poiID)
+	poi, err := h.repo.GetByID(c.Request.Context(), poiID)
 	if err != nil {
 		utils.SendError(c, http.StatusNotFound, "POI not found", err)
 		return
 	}
 
-	response := map[string]interface{}{
-		"kids_friendly":   poi.KidsFriendly,
-		"pet_friendly":    poi.PetFriendly,
-		"pet_policy":      poi.PetPolicy,
-		"smoker_friendly": poi.SmokerFriendly,
-		"happy_hour_info": poi.HappyHourInfo,
-		"loyalty_program": poi.LoyaltyProgram,
-	}
-
-	utils.SendSuccess(c, "POI social retrieved", response)
+	h.sendSection(c, "POI social retrieved", "social", poi, socialSection(poi))
 }
 
 // UpdatePOISocial handles PUT /api/v1/pois/:id/section/social
@@ -309,12 +240,13 @@ func (h *POISectionHandler) UpdatePOISocial(c *gin.Context) {
 		LoyaltyProgram: req.LoyaltyProgram,
 	}
 
-	if err := h.repo.UpdateSocial(c.Request.Context(), poiID, updateInput); err != nil {
-		utils.SendInternalError(c, err)
+	poi, err := h.repo.UpdateSocial(c.Request.Context(), poiID, updateInput, editorID(c))
+	if err != nil {
+		sendSectionError(c, err)
 		return
 	}
 
-	utils.SendSuccess(c, "POI social updated", nil)
+	h.sendSection(c, "POI social updated", "social", poi, socialSection(poi))
 }
 
 // GetPOIContact handles GET /api/v1/pois/:id/section/contact
@@ -325,26 +257,13 @@ func (h *POISectionHandler) GetPOIContact(c *gin.Context) {
 		return
 	}
 
-	poi, err := h.getPOIWithRetry(c.Request.Context(), poiID)
+	poi, err := h.repo.GetByID(c.Request.Context(), poiID)
 	if err != nil {
 		utils.SendError(c, http.StatusNotFound, "POI not found", err)
 		return
 	}
 
-	// Unmarshal social links
-	var socialLinks map[string]interface{}
-	if poi.SocialLinks != nil {
-		_ = json.Unmarshal(*poi.SocialLinks, &socialLinks)
-	}
-
-	response := map[string]interface{}{
-		"phone":        poi.Phone,
-		"email":        poi.Email,
-		"website":      poi.Website,
-		"social_links": socialLinks,
-	}
-
-	utils.SendSuccess(c, "POI contact retrieved", response)
+	h.sendSection(c, "POI contact retrieved", "contact", poi, contactSection(poi))
 }
 
 // UpdatePOIContact handles PUT /api/v1/pois/:id/section/contact
@@ -379,12 +298,13 @@ func (h *POISectionHandler) UpdatePOIContact(c *gin.Context) {
 		SocialLinks: req.SocialLinks,
 	}
 
-	if err := h.repo.UpdateContact(c.Request.Context(), poiID, updateInput); err != nil {
-		utils.SendInternalError(c, err)
+	poi, err := h.repo.UpdateContact(c.Request.Context(), poiID, updateInput, editorID(c))
+	if err != nil {
+		sendSectionError(c, err)
 		return
 	}
 
-	utils.SendSuccess(c, "POI contact updated", nil)
+	h.sendSection(c, "POI contact updated", "contact", poi, contactSection(poi))
 }
 
 // GetPOIWorkProd handles GET /api/v1/pois/:id/section/work-prod
@@ -395,21 +315,13 @@ func (h *POISectionHandler) GetPOIWorkProd(c *gin.Context) {
 		return
 	}
 
-	poi, err := h.getPOIWithRetry(c.Request.Context(), poiID)
+	poi, err := h.repo.GetByID(c.Request.Context(), poiID)
 	if err != nil {
 		utils.SendError(c, http.StatusNotFound, "POI not found", err)
 		return
 	}
 
-	response := map[string]interface{}{
-		"wifi_quality":    poi.WifiQuality,
-		"power_outlets":   poi.PowerOutlets,
-		"seating_options": poi.SeatingOptions,
-		"noise_level":     poi.NoiseLevel,
-		"has_ac":          poi.HasAC,
-	}
-
-	utils.SendSuccess(c, "POI work & prod retrieved", response)
+	h.sendSection(c, "POI work & prod retrieved", "work-prod", poi, workProdSection(poi))
 }
 
 // UpdatePOIWorkProd handles PUT /api/v1/pois/:id/section/work-prod
@@ -441,12 +353,13 @@ func (h *POISectionHandler) UpdatePOIWorkProd(c *gin.Context) {
 		HasAC:          req.HasAC,
 	}
 
-	if err := h.repo.UpdateWorkProd(c.Request.Context(), poiID, updateInput); err != nil {
-		utils.SendInternalError(c, err)
+	poi, err := h.repo.UpdateWorkProd(c.Request.Context(), poiID, updateInput, editorID(c))
+	if err != nil {
+		sendSectionError(c, err)
 		return
 	}
 
-	utils.SendSuccess(c, "POI work & prod updated", nil)
+	h.sendSection(c, "POI work & prod updated", "work-prod", poi, workProdSection(poi))
 }
 
 // GetPOIAtmosphere handles GET /api/v1/pois/:id/section/atmosphere
@@ -457,21 +370,13 @@ func (h *POISectionHandler) GetPOIAtmosphere(c *gin.Context) {
 		return
 	}
 
-	poi, err := h.getPOIWithRetry(c.Request.Context(), poiID)
+	poi, err := h.repo.GetByID(c.Request.Context(), poiID)
 	if err != nil {
 		utils.SendError(c, http.StatusNotFound, "POI not found", err)
 		return
 	}
 
-	response := map[string]interface{}{
-		"vibes":       poi.Vibes,
-		"crowd_type":  poi.CrowdType,
-		"lighting":    poi.Lighting,
-		"music_type":  poi.MusicType,
-		"cleanliness": poi.Cleanliness,
-	}
-
-	utils.SendSuccess(c, "POI atmosphere retrieved", response)
+	h.sendSection(c, "POI atmosphere retrieved", "atmosphere", poi, atmosphereSection(poi))
 }
 
 // UpdatePOIAtmosphere handles PUT /api/v1/pois/:id/section/atmosphere
@@ -503,12 +408,13 @@ func (h *POISectionHandler) UpdatePOIAtmosphere(c *gin.Context) {
 		Cleanliness: req.Cleanliness,
 	}
 
-	if err := h.repo.UpdateAtmosphere(c.Request.Context(), poiID, updateInput); err != nil {
-		utils.SendInternalError(c, err)
+	poi, err := h.repo.UpdateAtmosphere(c.Request.Context(), poiID, updateInput, editorID(c))
+	if err != nil {
+		sendSectionError(c, err)
 		return
 	}
 
-	utils.SendSuccess(c, "POI atmosphere updated", nil)
+	h.sendSection(c, "POI atmosphere updated", "atmosphere", poi, atmosphereSection(poi))
 }
 
 // GetPOIFoodDrink handles GET /api/v1/pois/:id/section/food-drink
@@ -519,26 +425,13 @@ func (h *POISectionHandler) GetPOIFoodDrink(c *gin.Context) {
 		return
 	}
 
-	poi, err := h.getPOIWithRetry(c.Request.Context(), poiID)
+	poi, err := h.repo.GetByID(c.Request.Context(), poiID)
 	if err != nil {
 		utils.SendError(c, http.StatusNotFound, "POI not found", err)
 		return
 	}
 
-	response := map[string]interface{}{
-		"cuisine":         poi.Cuisine,
-		"price_range":     poi.PriceRange,
-		"dietary_options": poi.FoodOptions,   // Note: mapped to food_options
-		"featured_items":  poi.FeaturedItems, // Note: Not in POI struct yet? Assuming GetByID fetches distinct cols or added in previous steps
-		"specials":        poi.Specials,      // Note: Not in POI struct yet? Assuming GetByID fetches distinct cols or added in previous steps
-	}
-	// Note: FeaturedItems and Specials were likely added to POI struct in previous steps.
-	// If not, this will fail. Step 1307 showed POI struct ending at line 60.
-	// I should verify if POI struct has FeaturedItems or Specials.
-	// Actually, previous session "Updated the POI struct to include... FeaturedItems, Specials" (Step 1236 summary).
-	// So it should be fine.
-
-	utils.SendSuccess(c, "POI food & drink retrieved", response)
+	h.sendSection(c, "POI food & drink retrieved", "food-drink", poi, foodDrinkSection(poi))
 }
 
 // UpdatePOIFoodDrink handles PUT /api/v1/pois/:id/section/food-drink
@@ -550,8 +443,9 @@ func (h *POISectionHandler) UpdatePOIFoodDrink(c *gin.Context) {
 	}
 
 	type FoodDrinkRequest struct {
-		Cuisine        *string  `json:"cuisine"`
-		PriceRange     *int     `json:"price_range"`
+		Cuisine    *string `json:"cuisine"`
+		PriceRange *int    `json:"price_range"`
+		PriceMetadata
 		DietaryOptions []string `json:"dietary_options"`
 		FeaturedItems  []string `json:"featured_items"`
 		Specials       []string `json:"specials"`
@@ -561,21 +455,95 @@ func (h *POISectionHandler) UpdatePOIFoodDrink(c *gin.Context) {
 		utils.SendError(c, http.StatusBadRequest, "invalid request body", err)
 		return
 	}
+	if err := req.PriceMetadata.validate(); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
 
 	updateInput := repositories.CreatePOIInput{
 		Cuisine:        req.Cuisine,
 		PriceRange:     req.PriceRange,
+		PriceCurrency:  req.PriceCurrency,
+		AvgSpendMin:    req.AvgSpendMin,
+		AvgSpendMax:    req.AvgSpendMax,
 		DietaryOptions: req.DietaryOptions,
 		FeaturedItems:  req.FeaturedItems,
 		Specials:       req.Specials,
 	}
 
-	if err := h.repo.UpdateFoodDrink(c.Request.Context(), poiID, updateInput); err != nil {
-		utils.SendInternalError(c, err)
+	poi, err := h.repo.UpdateFoodDrink(c.Request.Context(), poiID, updateInput, editorID(c))
+	if err != nil {
+		sendSectionError(c, err)
+		return
+	}
+
+	h.sendSection(c, "POI food & drink updated", "food-drink", poi, foodDrinkSection(poi))
+}
+
+// GetPOIAccessibility handles GET /api/v1/pois/:id/section/accessibility
+func (h *POISectionHandler) GetPOIAccessibility(c *gin.Context) {
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID", err)
+		return
+	}
+
+	poi, err := h.repo.GetByID(c.Request.Context(), poiID)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return
+	}
+
+	h.sendSection(c, "POI accessibility retrieved", "accessibility", poi, accessibilitySection(poi))
+}
+
+// UpdatePOIAccessibility handles PUT /api/v1/pois/:id/section/accessibility
+func (h *POISectionHandler) UpdatePOIAccessibility(c *gin.Context) {
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID", err)
+		return
+	}
+
+	// Omitted booleans are stored as unknown (null)
+	type AccessibilityRequest struct {
+		WheelchairAccessible bool     `json:"wheelchair_accessible"`
+		StepFreeEntrance     *bool    `json:"step_free_entrance"`
+		AccessibleRestroom   *bool    `json:"accessible_restroom"`
+		TableHeights         []string `json:"table_heights"`
+		BrailleMenu          *bool    `json:"braille_menu"`
+		AccessibleParking    *bool    `json:"accessible_parking"`
+		AccessibilityNotes   *string  `json:"accessibility_notes" binding:"omitempty,max=1000"`
+	}
+	var req AccessibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid request body", err)
 		return
 	}
+	for _, height := range req.TableHeights {
+		if !tableHeights[height] {
+			utils.SendError(c, http.StatusBadRequest, "table_heights must be low, standard, high or adjustable", nil)
+			return
+		}
+	}
+
+	updateInput := repositories.CreatePOIInput{
+		WheelchairAccessible: req.WheelchairAccessible,
+		StepFreeEntrance:     req.StepFreeEntrance,
+		AccessibleRestroom:   req.AccessibleRestroom,
+		TableHeights:         req.TableHeights,
+		BrailleMenu:          req.BrailleMenu,
+		AccessibleParking:    req.AccessibleParking,
+		AccessibilityNotes:   req.AccessibilityNotes,
+	}
 
-	utils.SendSuccess(c, "POI food & drink updated", nil)
+	poi, err := h.repo.UpdateAccessibility(c.Request.Context(), poiID, updateInput, editorID(c))
+	if err != nil {
+		sendSectionError(c, err)
+		return
+	}
+
+	h.sendSection(c, "POI accessibility updated", "accessibility", poi, accessibilitySection(poi))
 }
 
 // UpdatePOILocation handles PUT /api/v1/pois/:id/section/location
@@ -611,10 +579,175 @@ func (h *POISectionHandler) UpdatePOILocation(c *gin.Context) {
 		WheelchairAccessible: req.WheelchairAccessible,
 	}
 
-	if err := h.repo.UpdateLocation(c.Request.Context(), poiID, updateInput); err != nil {
+	poi, err := h.repo.UpdateLocation(c.Request.Context(), poiID, updateInput, editorID(c))
+	if err != nil {
+		sendSectionError(c, err)
+		return
+	}
+
+	h.sendSection(c, "POI location updated", "location", poi, locationSection(poi))
+}
+
+// sendSection answers with the fields of a section and when and by whom it
+// was last changed. Sections not edited since tracking began report the POI's
+// updated_at and no editor.
+func (h *POISectionHandler) sendSection(c *gin.Context, message, section string, poi *repositories.POI, data map[string]interface{}) {
+	edit, err := h.repo.GetSectionEdit(c.Request.Context(), poi.PoiID, section)
+	if err != nil {
 		utils.SendInternalError(c, err)
 		return
 	}
 
-	utils.SendSuccess(c, "POI location updated", nil)
+	data["updated_at"] = poi.UpdatedAt
+	data["last_edited_by"] = nil
+	if edit != nil {
+		data["updated_at"] = edit.UpdatedAt
+		if edit.EditedBy != nil {
+			data["last_edited_by"] = gin.H{"user_id": edit.EditedBy, "name": edit.EditorName}
+		}
+	}
+	utils.SendSuccess(c, message, data)
+}
+
+// editorID returns the signed-in user making a change, if any
+func editorID(c *gin.Context) *uuid.UUID {
+	if actor, ok := actorFromContext(c); ok {
+		return &actor.UserID
+	}
+	return nil
+}
+
+// sendSectionError answers a failed section update; the update matches no
+// row when the POI does not exist
+func sendSectionError(c *gin.Context, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return
+	}
+	utils.SendInternalError(c, err)
+}
+
+// profileSection is the profile section of a POI
+func profileSection(poi *repositories.POI) map[string]interface{} {
+	return map[string]interface{}{
+		"name":               poi.Name,
+		"brand_name":         poi.Brand,
+		"categories":         poi.CategoryNames,
+		"description":        poi.Description,
+		"cover_image_url":    poi.CoverImageURL,
+		"gallery_image_urls": poi.GalleryImageURLs,
+		"category_ids":       poi.CategoryIDs,
+	}
+}
+
+// locationSection is the location section of a POI
+func locationSection(poi *repositories.POI) map[string]interface{} {
+	address := ""
+	if poi.Address != nil {
+		address = *poi.Address
+	}
+
+	return map[string]interface{}{
+		"address":               address,
+		"floor_unit":            poi.FloorUnit,
+		"latitude":              poi.Latitude,
+		"longitude":             poi.Longitude,
+		"public_transport":      poi.PublicTransport,
+		"parking_options":       poi.ParkingOptions,
+		"wheelchair_accessible": poi.IsWheelchairAccessible,
+	}
+}
+
+// operationsSection is the operations section of a POI
+func operationsSection(poi *repositories.POI) map[string]interface{} {
+	// Unmarshal open_hours if present
+	var openHours map[string]interface{}
+	if poi.OpenHours != nil {
+		_ = json.Unmarshal(*poi.OpenHours, &openHours)
+	}
+
+	return map[string]interface{}{
+		"open_hours":           openHours,
+		"reservation_required": poi.ReservationRequired,
+		"reservation_platform": poi.ReservationPlatform,
+		"payment_options":      poi.PaymentOptions,
+		"wait_time_estimate":   poi.WaitTimeEstimate,
+	}
+}
+
+// socialSection is the social section of a POI
+func socialSection(poi *repositories.POI) map[string]interface{} {
+	return map[string]interface{}{
+		"kids_friendly":   poi.KidsFriendly,
+		"pet_friendly":    poi.PetFriendly,
+		"pet_policy":      poi.PetPolicy,
+		"smoker_friendly": poi.SmokerFriendly,
+		"happy_hour_info": poi.HappyHourInfo,
+		"loyalty_program": poi.LoyaltyProgram,
+	}
+}
+
+// contactSection is the contact section of a POI
+func contactSection(poi *repositories.POI) map[string]interface{} {
+	// Unmarshal social links
+	var socialLinks map[string]interface{}
+	if poi.SocialLinks != nil {
+		_ = json.Unmarshal(*poi.SocialLinks, &socialLinks)
+	}
+
+	return map[string]interface{}{
+		"phone":        poi.Phone,
+		"email":        poi.Email,
+		"website":      poi.Website,
+		"social_links": socialLinks,
+	}
+}
+
+// workProdSection is the work & prod section of a POI
+func workProdSection(poi *repositories.POI) map[string]interface{} {
+	return map[string]interface{}{
+		"wifi_quality":    poi.WifiQuality,
+		"power_outlets":   poi.PowerOutlets,
+		"seating_options": poi.SeatingOptions,
+		"noise_level":     poi.NoiseLevel,
+		"has_ac":          poi.HasAC,
+	}
+}
+
+// atmosphereSection is the atmosphere section of a POI
+func atmosphereSection(poi *repositories.POI) map[string]interface{} {
+	return map[string]interface{}{
+		"vibes":       poi.Vibes,
+		"crowd_type":  poi.CrowdType,
+		"lighting":    poi.Lighting,
+		"music_type":  poi.MusicType,
+		"cleanliness": poi.Cleanliness,
+	}
+}
+
+// foodDrinkSection is the food & drink section of a POI
+func foodDrinkSection(poi *repositories.POI) map[string]interface{} {
+	return map[string]interface{}{
+		"cuisine":         poi.Cuisine,
+		"price_range":     poi.PriceRange,
+		"price_currency":  poi.PriceCurrency,
+		"avg_spend_min":   poi.AvgSpendMin,
+		"avg_spend_max":   poi.AvgSpendMax,
+		"dietary_options": poi.FoodOptions, // Stored as food_options
+		"featured_items":  poi.FeaturedItems,
+		"specials":        poi.Specials,
+	}
+}
+
+// accessibilitySection is the accessibility section of a POI
+func accessibilitySection(poi *repositories.POI) map[string]interface{} {
+	return map[string]interface{}{
+		"wheelchair_accessible": poi.IsWheelchairAccessible,
+		"step_free_entrance":    poi.StepFreeEntrance,
+		"accessible_restroom":   poi.AccessibleRestroom,
+		"table_heights":         poi.TableHeights,
+		"braille_menu":          poi.BrailleMenu,
+		"accessible_parking":    poi.AccessibleParking,
+		"accessibility_notes":   poi.AccessibilityNotes,
+	}
 }
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/services"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TakedownRequest is the body for POST /api/v1/admin/pois/:id/takedown
+type TakedownRequest struct {
+	ReasonCode string  `json:"reason_code" binding:"required"`
+	Note       *string `json:"note" binding:"omitempty,max=2000"` // Internal record, never shown publicly
+}
+
+// TakedownPOI handles POST /api/v1/admin/pois/:id/takedown (requires poi:takedown).
+// The POI is unpublished at once from any status and its public page answers
+// 410 Gone from then on.
+func (h *POIHandler) TakedownPOI(c *gin.Context) {
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var input TakedownRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	reason, ok := repositories.TakedownReasons[input.ReasonCode]
+	if !ok {
+		utils.SendError(c, http.StatusBadRequest, "unknown reason_code", nil)
+		return
+	}
+	if input.Note != nil && strings.TrimSpace(*input.Note) == "" {
+		input.Note = nil
+	}
+
+	var takedown *repositories.Takedown
+	_, err = h.workflow.TransitionWith(c.Request.Context(), poiID, services.POIStatusTakenDown, actor, &reason,
+		func(ctx context.Context, event services.TransitionEvent) error {
+			var err error
+			takedown, err = h.repo.RecordTakedown(ctx, poiID, input.ReasonCode, input.Note, string(event.From), actor.UserID)
+			return err
+		})
+	if err != nil {
+		sendWorkflowError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "POI taken down", takedown)
+}
+
+// sendGone answers a request for a taken-down POI with its public reason
+func (h *POIHandler) sendGone(c *gin.Context, poiID uuid.UUID) {
+	takedown, err := h.repo.GetTakedown(c.Request.Context(), poiID)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	data := gin.H{"poi_id": poiID, "status": services.POIStatusTakenDown}
+	if takedown != nil {
+		data["reason_code"] = takedown.ReasonCode
+		data["reason"] = repositories.TakedownReasons[takedown.ReasonCode]
+		data["taken_down_at"] = takedown.CreatedAt
+	}
+	c.JSON(http.StatusGone, utils.Response{
+		Success: false,
+		Message: "This place was removed for legal or safety reasons",
+		Data:    data,
+	})
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"maukemana-backend/internal/utils"
+	"maukemana-backend/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// POIValidator runs the automated pre-approval checks
+type POIValidator interface {
+	Validate(ctx context.Context, poiID uuid.UUID) (*validation.Report, error)
+}
+
+// POIValidationHandler serves pre-approval validation reports to moderators
+type POIValidationHandler struct {
+	validator POIValidator
+}
+
+// NewPOIValidationHandler creates a new validation handler
+func NewPOIValidationHandler(validator POIValidator) *POIValidationHandler {
+	return &POIValidationHandler{validator: validator}
+}
+
+// GetValidation handles GET /api/v1/admin/pois/:id/validation (requires poi:approve)
+func (h *POIValidationHandler) GetValidation(c *gin.Context) {
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+
+	report, err := h.validator.Validate(c.Request.Context(), poiID)
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "POI validation report", report)
+}
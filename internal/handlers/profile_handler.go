@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ProfileRepository defines the data access needed for public profiles
+type ProfileRepository interface {
+	GetPublicProfile(ctx context.Context, userID uuid.UUID, viewer *uuid.UUID) (*models.PublicProfile, error)
+	GetPrivacy(ctx context.Context, userID uuid.UUID) (*models.ProfilePrivacy, error)
+	UpdatePrivacy(ctx context.Context, userID uuid.UUID, input repositories.UpdateProfilePrivacyInput) (*models.ProfilePrivacy, error)
+	ResolveUsername(ctx context.Context, username string) (uuid.UUID, error)
+	SetUsername(ctx context.Context, userID uuid.UUID, username string) (*models.Username, error)
+}
+
+// ProfileHandler serves public user profiles and their privacy settings
+type ProfileHandler struct {
+	repo ProfileRepository
+}
+
+// NewProfileHandler creates a new profile handler
+func NewProfileHandler(repo ProfileRepository) *ProfileHandler {
+	return &ProfileHandler{repo: repo}
+}
+
+// GetPublicProfile handles GET /api/v1/users/:id/public-profile, where :id is a
+// user ID or @username. Counts and recent contributions only include approved POIs.
+func (h *ProfileHandler) GetPublicProfile(c *gin.Context) {
+	ctx := c.Request.Context()
+	var userID uuid.UUID
+	if name, ok := strings.CutPrefix(c.Param("id"), "@"); ok {
+		id, err := h.repo.ResolveUsername(ctx, name)
+		if errors.Is(err, repositories.ErrProfileNotFound) {
+			utils.SendError(c, http.StatusNotFound, "profile not found", nil)
+			return
+		}
+		if err != nil {
+			utils.SendInternalError(c, err)
+			return
+		}
+		userID = id
+	} else {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "invalid user ID format", err)
+			return
+		}
+		userID = id
+	}
+	var viewerID *uuid.UUID
+	if actor, ok := actorFromContext(c); ok {
+		viewerID = &actor.UserID
+	}
+
+	profile, err := h.repo.GetPublicProfile(ctx, userID, viewerID)
+	if errors.Is(err, repositories.ErrProfileNotFound) {
+		utils.SendError(c, http.StatusNotFound, "profile not found", nil)
+		return
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Profile retrieved", profile)
+}
+
+// GetPrivacy handles GET /api/v1/me/profile-privacy
+func (h *ProfileHandler) GetPrivacy(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	privacy, err := h.repo.GetPrivacy(c.Request.Context(), actor.UserID)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Profile privacy retrieved", privacy)
+}
+
+// UpdatePrivacy handles PUT /api/v1/me/profile-privacy; omitted settings are kept
+func (h *ProfileHandler) UpdatePrivacy(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var input repositories.UpdateProfilePrivacyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+
+	privacy, err := h.repo.UpdatePrivacy(c.Request.Context(), actor.UserID, input)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Profile privacy updated", privacy)
+}
+
+// usernamePattern allows 3-30 letters, digits and underscores
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{3,30}$`)
+
+// reservedUsernames cannot be claimed, in any case, because they read as
+// official accounts or collide with routes
+var reservedUsernames = map[string]bool{
+	"admin": true, "administrator": true, "root": true, "system": true, "support": true,
+	"help": true, "staff": true, "moderator": true, "mod": true, "official": true,
+	"maukemana": true, "api": true, "www": true, "me": true, "settings": true,
+	"anonymous": true, "deleted": true, "null": true, "undefined": true,
+}
+
+// SetUsernameRequest is the body for PUT /api/v1/me/username
+type SetUsernameRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// SetUsername handles PUT /api/v1/me/username. Usernames are unique regardless
+// of case and can change once per cooldown period (30 days).
+func (h *ProfileHandler) SetUsername(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var req SetUsernameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	username := strings.TrimPrefix(strings.TrimSpace(req.Username), "@")
+	if !usernamePattern.MatchString(username) {
+		utils.SendError(c, http.StatusBadRequest, "username must be 3-30 letters, digits or underscores", nil)
+		return
+	}
+	lower := strings.ToLower(username)
+	if reservedUsernames[lower] || strings.HasPrefix(lower, "maukemana") {
+		utils.SendError(c, http.StatusUnprocessableEntity, "username is reserved", nil)
+		return
+	}
+
+	result, err := h.repo.SetUsername(c.Request.Context(), actor.UserID, username)
+	switch {
+	case errors.Is(err, repositories.ErrUsernameTaken):
+		utils.SendError(c, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, repositories.ErrUsernameCooldown):
+		c.Header("Retry-After", strconv.Itoa(int(time.Until(*result.NextChangeAt).Seconds())+1))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, utils.Response{
+			Success: false,
+			Message: "username can be changed again after " + result.NextChangeAt.UTC().Format(time.RFC3339),
+			Error:   gin.H{"code": "username_cooldown", "next_change_at": result.NextChangeAt},
+		})
+	case err != nil:
+		utils.SendInternalError(c, err)
+	default:
+		utils.SendSuccess(c, "Username updated", result)
+	}
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// QuestionRepository defines the data access used by the questions sub-resource
+type QuestionRepository interface {
+	CreateQuestion(ctx context.Context, q *models.Question) error
+	GetQuestion(ctx context.Context, poiID, questionID uuid.UUID) (*models.Question, error)
+	ListQuestions(ctx context.Context, poiID uuid.UUID, viewerID *uuid.UUID, limit, offset int) ([]models.Question, error)
+	CreateAnswer(ctx context.Context, questionID, userID uuid.UUID, body string) (*models.Answer, error)
+	AcceptAnswer(ctx context.Context, questionID, answerID uuid.UUID) (*models.Answer, error)
+}
+
+// InAppNotifier creates in-app notifications
+type InAppNotifier interface {
+	Create(ctx context.Context, userID uuid.UUID, notificationType, title string, body *string, data interface{}) error
+}
+
+// QuestionHandler handles the /pois/:id/questions sub-resource
+type QuestionHandler struct {
+	repo     QuestionRepository
+	poiRepo  POIRepository
+	notifier InAppNotifier // nil disables notifications
+}
+
+// NewQuestionHandler creates a new question handler
+func NewQuestionHandler(repo QuestionRepository, poiRepo POIRepository) *QuestionHandler {
+	return &QuestionHandler{repo: repo, poiRepo: poiRepo}
+}
+
+// UseNotifier notifies POI owners of new questions, askers of new answers and
+// answerers when their answer is accepted
+func (h *QuestionHandler) UseNotifier(n InAppNotifier) {
+	h.notifier = n
+}
+
+// QuestionRequest is the body for asking a question or answering one
+type QuestionRequest struct {
+	Body string `json:"body" binding:"required,max=2000"`
+}
+
+// ListQuestions handles GET /api/v1/pois/:id/questions
+func (h *QuestionHandler) ListQuestions(c *gin.Context) {
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+
+	var viewerID *uuid.UUID
+	if actor, ok := actorFromContext(c); ok {
+		viewerID = &actor.UserID
+	}
+
+	page, limit := utils.GetPagination(c)
+	offset := utils.GetOffset(page, limit)
+	questions, err := h.repo.ListQuestions(c.Request.Context(), poiID, viewerID, limit, offset)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendPaginated(c, "Questions retrieved", questions, page, limit, len(questions)+offset)
+}
+
+// AskQuestion handles POST /api/v1/pois/:id/questions on an approved POI
+func (h *QuestionHandler) AskQuestion(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+	body, ok := bindQuestionBody(c)
+	if !ok {
+		return
+	}
+
+	poi, err := h.poiRepo.GetByID(c.Request.Context(), poiID)
+	if err != nil || poi.Status != "approved" {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return
+	}
+
+	question := &models.Question{PoiID: poiID, UserID: actor.UserID, Body: body}
+	if err := h.repo.CreateQuestion(c.Request.Context(), question); err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	if owner := poiOwner(poi); owner != nil && *owner != actor.UserID {
+		h.notify(c.Request.Context(), *owner, models.NotificationQuestionAsked,
+			"New question about "+poi.Name, &question.Body, gin.H{"poi_id": poiID, "question_id": question.QuestionID})
+	}
+
+	utils.SendCreated(c, "Question posted", question)
+}
+
+// AnswerQuestion handles POST /api/v1/pois/:id/questions/:question_id/answers
+func (h *QuestionHandler) AnswerQuestion(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	poiID, questionID, ok := parseQuestionPath(c)
+	if !ok {
+		return
+	}
+	body, ok := bindQuestionBody(c)
+	if !ok {
+		return
+	}
+
+	question, ok := h.getQuestion(c, poiID, questionID)
+	if !ok {
+		return
+	}
+
+	answer, err := h.repo.CreateAnswer(c.Request.Context(), questionID, actor.UserID, body)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	if question.UserID != actor.UserID {
+		h.notify(c.Request.Context(), question.UserID, models.NotificationQuestionAnswered,
+			"Your question has a new answer", &answer.Body,
+			gin.H{"poi_id": poiID, "question_id": questionID, "answer_id": answer.AnswerID})
+	}
+
+	utils.SendCreated(c, "Answer posted", answer)
+}
+
+// AcceptAnswer handles POST /api/v1/pois/:id/questions/:question_id/answers/:answer_id/accept.
+// Only the asker or the POI owner can accept an answer.
+func (h *QuestionHandler) AcceptAnswer(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	poiID, questionID, ok := parseQuestionPath(c)
+	if !ok {
+		return
+	}
+	answerID, err := uuid.Parse(c.Param("answer_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid answer ID format", err)
+		return
+	}
+
+	question, ok := h.getQuestion(c, poiID, questionID)
+	if !ok {
+		return
+	}
+	if question.UserID != actor.UserID {
+		poi, err := h.poiRepo.GetByID(c.Request.Context(), poiID)
+		if err != nil {
+			utils.SendInternalError(c, err)
+			return
+		}
+		if !isPOIOwner(poi, actor.UserID) {
+			utils.SendError(c, http.StatusForbidden, "only the asker or the POI owner can accept an answer", nil)
+			return
+		}
+	}
+
+	answer, err := h.repo.AcceptAnswer(c.Request.Context(), questionID, answerID)
+	if errors.Is(err, repositories.ErrAnswerNotFound) {
+		utils.SendError(c, http.StatusNotFound, "answer not found", nil)
+		return
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	if answer.UserID != actor.UserID {
+		h.notify(c.Request.Context(), answer.UserID, models.NotificationAnswerAccepted,
+			"Your answer was accepted", nil,
+			gin.H{"poi_id": poiID, "question_id": questionID, "answer_id": answerID})
+	}
+
+	utils.SendSuccess(c, "Answer accepted", answer)
+}
+
+// getQuestion loads a question of the POI. It reports false after sending an error response.
+func (h *QuestionHandler) getQuestion(c *gin.Context, poiID, questionID uuid.UUID) (*models.Question, bool) {
+	question, err := h.repo.GetQuestion(c.Request.Context(), poiID, questionID)
+	if errors.Is(err, repositories.ErrQuestionNotFound) {
+		utils.SendError(c, http.StatusNotFound, "question not found", nil)
+		return nil, false
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return nil, false
+	}
+	return question, true
+}
+
+// notify creates an in-app notification. Failures are logged: the question or
+// answer is saved either way.
+func (h *QuestionHandler) notify(ctx context.Context, userID uuid.UUID, notificationType, title string, body *string, data gin.H) {
+	if h.notifier == nil {
+		return
+	}
+	if err := h.notifier.Create(ctx, userID, notificationType, title, body, data); err != nil {
+		slog.WarnContext(ctx, "failed to create notification", "type", notificationType, "user_id", userID, "error", err)
+	}
+}
+
+// parseQuestionPath reads the POI and question IDs of the request path. It
+// reports false after sending an error response.
+func parseQuestionPath(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	questionID, err := uuid.Parse(c.Param("question_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid question ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return poiID, questionID, true
+}
+
+// bindQuestionBody reads the trimmed text of a question or answer. It reports
+// false after sending an error response.
+func bindQuestionBody(c *gin.Context) (string, bool) {
+	var input QuestionRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return "", false
+	}
+	body := strings.TrimSpace(input.Body)
+	if body == "" {
+		utils.SendError(c, http.StatusBadRequest, "body is required", nil)
+		return "", false
+	}
+	return body, true
+}
+
+// poiOwner returns the user who answers for the POI: its creator, or else its founder
+func poiOwner(poi *repositories.POI) *uuid.UUID {
+	if poi.CreatedBy != nil {
+		return poi.CreatedBy
+	}
+	return poi.FoundingUserID
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"maukemana-backend/internal/imaging"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RenditionLadderStore defines the rendition ladder operations the handler needs
+type RenditionLadderStore interface {
+	SaveRenditionLadder(ctx context.Context, category string, renditions []imaging.RenditionConfig, updatedBy *uuid.UUID) (*imaging.Ladder, error)
+	CountOutdatedAssets(ctx context.Context) (map[string]int, error)
+}
+
+// RenditionLadderHandler serves the per-category rendition ladders (requires
+// imaging:admin). Changes apply to this instance at once and to the others on
+// their next ladder reload; existing assets keep their derivatives until a
+// reprocess run with outdated_only renders them again.
+type RenditionLadderHandler struct {
+	store   RenditionLadderStore
+	ladders *imaging.Ladders
+}
+
+// NewRenditionLadderHandler creates a new rendition ladder handler
+func NewRenditionLadderHandler(store RenditionLadderStore, ladders *imaging.Ladders) *RenditionLadderHandler {
+	return &RenditionLadderHandler{store: store, ladders: ladders}
+}
+
+// RenditionLadderResponse is a category's current ladder with the number of
+// ready assets rendered with another version of it
+type RenditionLadderResponse struct {
+	imaging.Ladder
+	OutdatedAssets int `json:"outdated_assets"`
+}
+
+// UpdateRenditionLadderRequest replaces a category's ladder
+type UpdateRenditionLadderRequest struct {
+	Renditions []imaging.RenditionConfig `json:"renditions" binding:"required"`
+}
+
+// ListLadders handles GET /api/v1/admin/imaging/ladders
+func (h *RenditionLadderHandler) ListLadders(c *gin.Context) {
+	outdated, err := h.store.CountOutdatedAssets(c.Request.Context())
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	ladders := h.ladders.All()
+	resp := make([]RenditionLadderResponse, 0, len(ladders))
+	for _, l := range ladders {
+		resp = append(resp, RenditionLadderResponse{Ladder: l, OutdatedAssets: outdated[l.Category]})
+	}
+	utils.SendSuccess(c, "Rendition ladders retrieved", resp)
+}
+
+// UpdateLadder handles PUT /api/v1/admin/imaging/ladders/:category
+func (h *RenditionLadderHandler) UpdateLadder(c *gin.Context) {
+	category, ok := h.ladderCategory(c)
+	if !ok {
+		return
+	}
+	var req UpdateRenditionLadderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	if err := imaging.ValidateLadder(category, req.Renditions); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	h.save(c, category, req.Renditions, "Rendition ladder updated")
+}
+
+// ResetLadder handles DELETE /api/v1/admin/imaging/ladders/:category,
+// returning the category to the compiled-in ladder
+func (h *RenditionLadderHandler) ResetLadder(c *gin.Context) {
+	category, ok := h.ladderCategory(c)
+	if !ok {
+		return
+	}
+	if current := h.ladders.Get(category); !current.Override {
+		utils.SendSuccess(c, "Rendition ladder already uses the default", current)
+		return
+	}
+	h.save(c, category, nil, "Rendition ladder reset to the default")
+}
+
+func (h *RenditionLadderHandler) save(c *gin.Context, category string, renditions []imaging.RenditionConfig, message string) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	ctx := c.Request.Context()
+
+	ladder, err := h.store.SaveRenditionLadder(ctx, category, renditions, &actor.UserID)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	if err := h.ladders.Load(ctx); err != nil {
+		slog.WarnContext(ctx, "failed to reload rendition ladders", "error", err)
+	}
+	if ladder.Renditions == nil {
+		ladder.Renditions = imaging.GetRenditionsForCategory(category)
+	}
+	utils.SendSuccess(c, message, ladder)
+}
+
+func (h *RenditionLadderHandler) ladderCategory(c *gin.Context) (string, bool) {
+	category := c.Param("category")
+	if !slices.Contains(imaging.LadderCategories, category) {
+		utils.SendError(c, http.StatusNotFound, "unknown rendition ladder category", nil)
+		return "", false
+	}
+	return category, true
+}
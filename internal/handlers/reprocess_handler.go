@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"maukemana-backend/internal/imaging"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReprocessStore defines the reprocess run operations the handler needs
+type ReprocessStore interface {
+	StartReprocessRun(ctx context.Context, category *string, outdatedOnly bool, batchSize, batchIntervalMS int, startedBy *uuid.UUID) (*imaging.ReprocessRun, error)
+	GetActiveReprocessRun(ctx context.Context) (*imaging.ReprocessRun, error)
+	GetLatestReprocessRun(ctx context.Context) (*imaging.ReprocessRun, error)
+	FinishReprocessRun(ctx context.Context, runID uuid.UUID, status, lastError string) error
+}
+
+// ReprocessRunner drives a run in the background
+type ReprocessRunner interface {
+	Start(run *imaging.ReprocessRun)
+}
+
+// ReprocessHandler serves reprocess-all runs over ready image assets (requires imaging:admin)
+type ReprocessHandler struct {
+	store  ReprocessStore
+	runner ReprocessRunner
+}
+
+// NewReprocessHandler creates a new reprocess handler
+func NewReprocessHandler(store ReprocessStore, runner ReprocessRunner) *ReprocessHandler {
+	return &ReprocessHandler{store: store, runner: runner}
+}
+
+// StartReprocessRequest configures a reprocess run. OutdatedOnly limits it to
+// assets rendered with an older version of their rendition ladder. Resume
+// continues the running run (e.g. after a restart) instead of starting a new one.
+type StartReprocessRequest struct {
+	Category        string `json:"category"`
+	OutdatedOnly    bool   `json:"outdated_only"`
+	BatchSize       int    `json:"batch_size" binding:"omitempty,min=1,max=500"`
+	BatchIntervalMS int    `json:"batch_interval_ms" binding:"omitempty,min=0,max=60000"`
+	Resume          bool   `json:"resume"`
+}
+
+// StartReprocess handles POST /api/v1/admin/imaging/reprocess
+func (h *ReprocessHandler) StartReprocess(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	var req StartReprocessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	ctx := c.Request.Context()
+
+	if req.Resume {
+		run, err := h.store.GetActiveReprocessRun(ctx)
+		if err != nil {
+			utils.SendInternalError(c, err)
+			return
+		}
+		if run == nil {
+			utils.SendError(c, http.StatusNotFound, "no reprocess run to resume", nil)
+			return
+		}
+		h.runner.Start(run)
+		utils.SendAccepted(c, "Reprocess run resumed", run)
+		return
+	}
+
+	batchSize := req.BatchSize
+	if batchSize == 0 {
+		batchSize = 50
+	}
+	var category *string
+	if name := strings.TrimSpace(req.Category); name != "" {
+		category = &name
+	}
+
+	run, err := h.store.StartReprocessRun(ctx, category, req.OutdatedOnly, batchSize, req.BatchIntervalMS, &actor.UserID)
+	if err != nil {
+		if errors.Is(err, imaging.ErrReprocessRunning) {
+			utils.SendError(c, http.StatusConflict, "a reprocess run is already running; cancel it or resume it", err)
+			return
+		}
+		utils.SendInternalError(c, err)
+		return
+	}
+	h.runner.Start(run)
+	utils.SendAccepted(c, "Reprocess run started", run)
+}
+
+// GetReprocess handles GET /api/v1/admin/imaging/reprocess, reporting the latest run
+func (h *ReprocessHandler) GetReprocess(c *gin.Context) {
+	run, err := h.store.GetLatestReprocessRun(c.Request.Context())
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	if run == nil {
+		utils.SendError(c, http.StatusNotFound, "no reprocess runs", nil)
+		return
+	}
+	utils.SendSuccess(c, "Reprocess run retrieved", run)
+}
+
+// CancelReprocess handles POST /api/v1/admin/imaging/reprocess/cancel. Jobs
+// already queued still run; no further batches are queued.
+func (h *ReprocessHandler) CancelReprocess(c *gin.Context) {
+	ctx := c.Request.Context()
+	run, err := h.store.GetActiveReprocessRun(ctx)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	if run == nil {
+		utils.SendError(c, http.StatusNotFound, "no running reprocess run", nil)
+		return
+	}
+	if err := h.store.FinishReprocessRun(ctx, run.RunID, imaging.ReprocessCancelled, ""); err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	run.Status = imaging.ReprocessCancelled
+	utils.SendSuccess(c, "Reprocess run cancelled", run)
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/spam"
+	"maukemana-backend/internal/textmod"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReviewWriter defines the review data access used by the reviews sub-resource
+type ReviewWriter interface {
+	ListByPOI(ctx context.Context, poiID uuid.UUID, viewerID *uuid.UUID, sort string, limit, offset int) ([]models.Review, error)
+	Upsert(ctx context.Context, review *models.Review, photoAssetIDs []uuid.UUID) (*models.Review, error)
+	VoteWithToggle(ctx context.Context, reviewID, userID uuid.UUID, voteType int) (int, int, error)
+}
+
+// ReviewHandler handles the /pois/:id/reviews sub-resource
+type ReviewHandler struct {
+	repo    ReviewWriter
+	poiRepo POIRepository
+	spam    SpamGuard
+	textMod TextModerator
+}
+
+// NewReviewHandler creates a new review handler
+func NewReviewHandler(repo ReviewWriter, poiRepo POIRepository) *ReviewHandler {
+	return &ReviewHandler{repo: repo, poiRepo: poiRepo}
+}
+
+// UseSpamGuard screens reviews with the anti-spam heuristics before they are saved
+func (h *ReviewHandler) UseSpamGuard(g SpamGuard) {
+	h.spam = g
+}
+
+// UseTextModerator masks profanity and contact details in reviews before they are saved
+func (h *ReviewHandler) UseTextModerator(m TextModerator) {
+	h.textMod = m
+}
+
+// ReviewRequest is the body for writing a review. Dimension ratings are optional.
+type ReviewRequest struct {
+	Rating              int         `json:"rating" binding:"required,min=1,max=5"`
+	Content             *string     `json:"content" binding:"omitempty,max=5000"`
+	WifiRating          *int        `json:"wifi_rating" binding:"omitempty,min=1,max=5"`
+	ComfortRating       *int        `json:"comfort_rating" binding:"omitempty,min=1,max=5"`
+	FoodRating          *int        `json:"food_rating" binding:"omitempty,min=1,max=5"`
+	ValueRating         *int        `json:"value_rating" binding:"omitempty,min=1,max=5"`
+	NoiseAccuracyRating *int        `json:"noise_accuracy_rating" binding:"omitempty,min=1,max=5"` // How well the listed noise level matches
+	PhotoAssetIDs       []uuid.UUID `json:"photo_asset_ids" binding:"omitempty,max=6,unique"`      // Image assets uploaded by the reviewer
+}
+
+// ListReviews handles GET /api/v1/pois/:id/reviews?sort=helpful|newest. Signed-in
+// callers get their own vote on each review.
+func (h *ReviewHandler) ListReviews(c *gin.Context) {
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+
+	var viewerID *uuid.UUID
+	if actor, ok := actorFromContext(c); ok {
+		viewerID = &actor.UserID
+	}
+
+	page, limit := utils.GetPagination(c)
+	sort := c.DefaultQuery("sort", repositories.ReviewSortHelpful)
+	offset := utils.GetOffset(page, limit)
+	reviews, err := h.repo.ListByPOI(c.Request.Context(), poiID, viewerID, sort, limit, offset)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendPaginated(c, "Reviews retrieved", reviews, page, limit, len(reviews)+offset)
+}
+
+// UpsertReview handles PUT /api/v1/pois/:id/reviews/mine, creating or
+// replacing the caller's review of an approved POI
+func (h *ReviewHandler) UpsertReview(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+
+	var input ReviewRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+
+	poi, err := h.poiRepo.GetByID(c.Request.Context(), poiID)
+	if err != nil || poi.Status != "approved" {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return
+	}
+
+	if h.spam != nil {
+		content := ""
+		if input.Content != nil {
+			content = *input.Content
+		}
+		if err := h.spam.Check(c.Request.Context(), actor.UserID, spam.KindReview, poiID, content); err != nil {
+			if !sendSpamError(c, err) {
+				utils.SendInternalError(c, err)
+			}
+			return
+		}
+	}
+
+	moderation, ok := moderateText(c, h.textMod, textmod.FieldReview, input.Content)
+	if !ok {
+		return
+	}
+
+	rating := input.Rating
+	saved, err := h.repo.Upsert(c.Request.Context(), &models.Review{
+		PoiID:               poiID,
+		UserID:              actor.UserID,
+		Rating:              &rating,
+		Content:             input.Content,
+		WifiRating:          input.WifiRating,
+		ComfortRating:       input.ComfortRating,
+		FoodRating:          input.FoodRating,
+		ValueRating:         input.ValueRating,
+		NoiseAccuracyRating: input.NoiseAccuracyRating,
+	}, input.PhotoAssetIDs)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUnknownReviewPhoto) {
+			utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		utils.SendInternalError(c, err)
+		return
+	}
+	recordModeration(c.Request.Context(), h.textMod, saved.ReviewID, &actor.UserID, moderation)
+
+	utils.SendSuccess(c, "Review saved", saved)
+}
+
+// VoteReview handles POST /api/v1/reviews/:review_id/vote with helpful/unhelpful toggle
+func (h *ReviewHandler) VoteReview(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	reviewID, err := uuid.Parse(c.Param("review_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid review ID format", err)
+		return
+	}
+
+	var input struct {
+		VoteType string `json:"vote_type" binding:"required,oneof=helpful unhelpful"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	voteInt := 1
+	if input.VoteType == "unhelpful" {
+		voteInt = -1
+	}
+
+	newScore, userVote, err := h.repo.VoteWithToggle(c.Request.Context(), reviewID, actor.UserID, voteInt)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrReviewNotFound):
+			utils.SendError(c, http.StatusNotFound, "review not found", err)
+		case errors.Is(err, repositories.ErrOwnReviewVote):
+			utils.SendError(c, http.StatusForbidden, err.Error(), nil)
+		default:
+			utils.SendInternalError(c, err)
+		}
+		return
+	}
+
+	utils.SendSuccess(c, "Vote registered", gin.H{
+		"review_id":     reviewID,
+		"helpful_score": newScore,
+		"user_vote":     userVote, // 1=helpful, -1=unhelpful, 0=no vote
+	})
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RoleRepository defines the data access needed to manage roles
+type RoleRepository interface {
+	ListRoles(ctx context.Context) ([]repositories.Role, error)
+	SetUserRole(ctx context.Context, userID uuid.UUID, role string) error
+}
+
+// RoleHandler lists roles and assigns them to users (routes require user:manage)
+type RoleHandler struct {
+	repo  RoleRepository
+	users UserInvalidator
+}
+
+// NewRoleHandler creates a new role handler
+func NewRoleHandler(repo RoleRepository, users UserInvalidator) *RoleHandler {
+	return &RoleHandler{repo: repo, users: users}
+}
+
+// SetUserRoleRequest is the body for PUT /api/v1/admin/users/:id/role
+type SetUserRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// ListRoles handles GET /api/v1/admin/roles
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.repo.ListRoles(c.Request.Context())
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Roles retrieved", roles)
+}
+
+// SetUserRole handles PUT /api/v1/admin/users/:id/role. The role takes effect
+// on the user's next request.
+func (h *RoleHandler) SetUserRole(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid user ID format", err)
+		return
+	}
+
+	var input SetUserRoleRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+
+	err = h.repo.SetUserRole(c.Request.Context(), userID, input.Role)
+	switch {
+	case errors.Is(err, repositories.ErrRoleNotFound):
+		utils.SendError(c, http.StatusBadRequest, "unknown role", err)
+	case errors.Is(err, repositories.ErrUserNotFound):
+		utils.SendError(c, http.StatusNotFound, "user not found", err)
+	case err != nil:
+		utils.SendInternalError(c, err)
+	default:
+		h.users.Invalidate(userID)
+		utils.SendSuccess(c, "User role updated", gin.H{"user_id": userID, "role": input.Role})
+	}
+}
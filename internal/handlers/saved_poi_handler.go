@@ -4,8 +4,8 @@ import (
 	"context"
 	"maukemana-backend/internal/logger"
 	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/utils"
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -32,7 +32,7 @@ func NewSavedPOIHandler(repo SavedPOIRepository) *SavedPOIHandler {
 func (h *SavedPOIHandler) ToggleSave(c *gin.Context) {
 	userIDVal, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
 	userID := userIDVal.(uuid.UUID)
@@ -41,14 +41,14 @@ func (h *SavedPOIHandler) ToggleSave(c *gin.Context) {
 	poiIDStr := c.Param("id")
 	poiID, err := uuid.Parse(poiIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid POI ID"})
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
 		return
 	}
 
 	// 3. Check current status
 	isSaved, err := h.repo.IsSaved(c.Request.Context(), userID, poiID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		utils.SendInternalError(c, err)
 		return
 	}
 
@@ -57,18 +57,18 @@ func (h *SavedPOIHandler) ToggleSave(c *gin.Context) {
 		err = h.repo.UnsavePOI(c.Request.Context(), userID, poiID)
 		if err != nil {
 			logger.L().Error("Failed to unsave POI", "error", err, "user_id", userID, "poi_id", poiID)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unsave POI"})
+			utils.SendInternalError(c, err)
 			return
 		}
-		c.JSON(http.StatusOK, gin.H{"is_saved": false, "message": "POI unsaved"})
+		utils.SendSuccess(c, "POI unsaved", gin.H{"is_saved": false})
 	} else {
 		err = h.repo.SavePOI(c.Request.Context(), userID, poiID)
 		if err != nil {
 			logger.L().Error("Failed to save POI", "error", err, "user_id", userID, "poi_id", poiID)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save POI"})
+			utils.SendInternalError(c, err)
 			return
 		}
-		c.JSON(http.StatusOK, gin.H{"is_saved": true, "message": "POI saved"})
+		utils.SendSuccess(c, "POI saved", gin.H{"is_saved": true})
 	}
 }
 
@@ -76,18 +76,18 @@ func (h *SavedPOIHandler) ToggleSave(c *gin.Context) {
 func (h *SavedPOIHandler) GetMySavedPOIs(c *gin.Context) {
 	userIDVal, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
 	userID := userIDVal.(uuid.UUID)
 
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	page, limit := utils.GetPagination(c)
+	offset := utils.GetOffset(page, limit)
 
 	pois, err := h.repo.GetSavedPOIs(c.Request.Context(), userID, limit, offset)
 	if err != nil {
 		logger.L().Error("Failed to fetch saved POIs", "error", err, "user_id", userID)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch saved POIs"})
+		utils.SendInternalError(c, err)
 		return
 	}
 
@@ -96,5 +96,5 @@ func (h *SavedPOIHandler) GetMySavedPOIs(c *gin.Context) {
 		pois = []repositories.POI{}
 	}
 
-	c.JSON(http.StatusOK, gin.H{"pois": pois})
+	utils.SendPaginated(c, "Saved POIs retrieved", pois, page, limit, len(pois)+offset)
 }
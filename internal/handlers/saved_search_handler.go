@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SavedSearchRepository defines the data access needed for saved searches
+type SavedSearchRepository interface {
+	ListForUser(ctx context.Context, userID uuid.UUID) ([]models.SavedSearch, error)
+	Create(ctx context.Context, s *models.SavedSearch) (*models.SavedSearch, error)
+	Update(ctx context.Context, s *models.SavedSearch) (*models.SavedSearch, error)
+	Delete(ctx context.Context, userID, searchID uuid.UUID) error
+}
+
+// SavedSearchHandler serves a user's saved searches and alert subscriptions
+type SavedSearchHandler struct {
+	repo SavedSearchRepository
+}
+
+// NewSavedSearchHandler creates a new saved search handler
+func NewSavedSearchHandler(repo SavedSearchRepository) *SavedSearchHandler {
+	return &SavedSearchHandler{repo: repo}
+}
+
+// savedSearchIgnoredParams are GET /api/v1/pois parameters that do not
+// describe which POIs match, so they are not kept
+var savedSearchIgnoredParams = map[string]bool{
+	"status": true, "page": true, "limit": true, "fields": true, "locale": true, "facets": true,
+}
+
+// SavedSearchRequest is the body for creating or replacing a saved search
+type SavedSearchRequest struct {
+	Name          string            `json:"name" binding:"required,max=100"`
+	Query         map[string]string `json:"query" binding:"required,max=40,dive,keys,required,max=50,endkeys,max=500"` // GET /api/v1/pois parameters
+	AlertsEnabled bool              `json:"alerts_enabled"`
+}
+
+// toModel validates the query with the same rules as a search
+func (req *SavedSearchRequest) toModel() (*models.SavedSearch, error) {
+	values := url.Values{}
+	params := make(map[string]string, len(req.Query))
+	for k, v := range req.Query {
+		if savedSearchIgnoredParams[k] || v == "" {
+			continue
+		}
+		values.Set(k, v)
+		params[k] = v
+	}
+	if len(params) == 0 {
+		return nil, errors.New("query must contain at least one filter")
+	}
+	if _, err := repositories.ParseSearchFilters(values); err != nil {
+		return nil, err
+	}
+
+	query, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	return &models.SavedSearch{Name: req.Name, Query: query, AlertsEnabled: req.AlertsEnabled}, nil
+}
+
+// ListSavedSearches handles GET /api/v1/me/saved-searches
+func (h *SavedSearchHandler) ListSavedSearches(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	searches, err := h.repo.ListForUser(c.Request.Context(), actor.UserID)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Saved searches retrieved", searches)
+}
+
+// CreateSavedSearch handles POST /api/v1/me/saved-searches
+func (h *SavedSearchHandler) CreateSavedSearch(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var input SavedSearchRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	search, err := input.toModel()
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	search.UserID = actor.UserID
+
+	created, err := h.repo.Create(c.Request.Context(), search)
+	if err != nil {
+		sendSavedSearchError(c, err)
+		return
+	}
+
+	utils.SendCreated(c, "Saved search created", created)
+}
+
+// UpdateSavedSearch handles PUT /api/v1/me/saved-searches/:id
+func (h *SavedSearchHandler) UpdateSavedSearch(c *gin.Context) {
+	id, ok := parseSavedSearchID(c)
+	if !ok {
+		return
+	}
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var input SavedSearchRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	search, err := input.toModel()
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	search.SearchID, search.UserID = id, actor.UserID
+
+	updated, err := h.repo.Update(c.Request.Context(), search)
+	if err != nil {
+		sendSavedSearchError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Saved search updated", updated)
+}
+
+// DeleteSavedSearch handles DELETE /api/v1/me/saved-searches/:id
+func (h *SavedSearchHandler) DeleteSavedSearch(c *gin.Context) {
+	id, ok := parseSavedSearchID(c)
+	if !ok {
+		return
+	}
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	if err := h.repo.Delete(c.Request.Context(), actor.UserID, id); err != nil {
+		sendSavedSearchError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Saved search deleted", gin.H{"search_id": id})
+}
+
+func parseSavedSearchID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid saved search ID format", err)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// sendSavedSearchError maps saved search repository errors to HTTP responses
+func sendSavedSearchError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrSavedSearchNotFound):
+		utils.SendError(c, http.StatusNotFound, "saved search not found", err)
+	case errors.Is(err, repositories.ErrSavedSearchLimit):
+		utils.SendError(c, http.StatusConflict, fmt.Sprintf("at most %d saved searches are allowed", repositories.MaxSavedSearches), err)
+	default:
+		utils.SendInternalError(c, err)
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/services"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// QueryEmbedder embeds free-text search queries
+type QueryEmbedder interface {
+	Model() string
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// EmbeddingSearcher runs vector similarity queries over POI embeddings
+type EmbeddingSearcher interface {
+	Search(ctx context.Context, q repositories.SemanticQuery) ([]repositories.SemanticMatch, error)
+}
+
+const (
+	semanticSearchDefaultLimit = 20
+	semanticSearchMaxLimit     = 50
+)
+
+// SemanticSearchHandler serves vibe-style natural language POI search
+type SemanticSearchHandler struct {
+	embedder QueryEmbedder
+	searcher EmbeddingSearcher
+	pois     POIRepository
+}
+
+// NewSemanticSearchHandler creates a new semantic search handler. A nil
+// embedder disables the endpoint.
+func NewSemanticSearchHandler(embedder QueryEmbedder, searcher EmbeddingSearcher, pois POIRepository) *SemanticSearchHandler {
+	return &SemanticSearchHandler{embedder: embedder, searcher: searcher, pois: pois}
+}
+
+// SemanticResult is a POI with its similarity to the query
+type SemanticResult struct {
+	POI            repositories.POI `json:"poi"`
+	Similarity     float64          `json:"similarity"`
+	DistanceMeters *float64         `json:"distance_meters,omitempty"`
+}
+
+// Search handles GET /api/v1/pois/semantic-search?q=&lat=&lng=&radius=&limit=
+func (h *SemanticSearchHandler) Search(c *gin.Context) {
+	if h.embedder == nil {
+		utils.SendError(c, http.StatusServiceUnavailable, "semantic search is not configured", nil)
+		return
+	}
+
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		utils.SendError(c, http.StatusBadRequest, "q is required", nil)
+		return
+	}
+
+	query := repositories.SemanticQuery{Model: h.embedder.Model(), Limit: semanticSearchDefaultLimit}
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		query.Limit = min(l, semanticSearchMaxLimit)
+	}
+
+	if radiusStr := c.Query("radius"); radiusStr != "" {
+		radius, err := strconv.ParseFloat(radiusStr, 64)
+		if err != nil || radius <= 0 {
+			utils.SendError(c, http.StatusBadRequest, "invalid radius", nil)
+			return
+		}
+		lat, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+		lng, lngErr := strconv.ParseFloat(c.Query("lng"), 64)
+		if latErr != nil || lngErr != nil {
+			utils.SendError(c, http.StatusBadRequest, "lat and lng are required with radius", nil)
+			return
+		}
+		query.Lat, query.Lng, query.Radius = lat, lng, radius
+	}
+
+	ctx := c.Request.Context()
+	vectors, err := h.embedder.Embed(ctx, []string{q})
+	if err != nil {
+		slog.WarnContext(ctx, "embedding provider unavailable", "error", err)
+		utils.SendError(c, http.StatusServiceUnavailable, "semantic search is temporarily unavailable", nil)
+		return
+	}
+	query.Vector = vectors[0]
+
+	matches, err := h.searcher.Search(ctx, query)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	if len(matches) == 0 {
+		utils.SendSuccess(c, "Semantic search results", gin.H{"data": []SemanticResult{}, "count": 0})
+		return
+	}
+
+	ids := make([]uuid.UUID, len(matches))
+	for i, m := range matches {
+		ids[i] = m.POIID
+	}
+	filters := map[string]interface{}{
+		"status":    string(services.POIStatusApproved),
+		"match_ids": ids,
+	}
+	if locale := translationLocale(c); locale != "" {
+		filters["locale"] = locale
+	}
+	pois, err := h.pois.Search(ctx, filters, len(ids), 0)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	byID := make(map[uuid.UUID]repositories.POI, len(pois))
+	for _, p := range pois {
+		byID[p.PoiID] = p
+	}
+	results := make([]SemanticResult, 0, len(matches))
+	for _, m := range matches {
+		if p, ok := byID[m.POIID]; ok {
+			results = append(results, SemanticResult{POI: p, Similarity: m.Similarity, DistanceMeters: m.DistanceMeters})
+		}
+	}
+
+	utils.SendSuccess(c, "Semantic search results", gin.H{"data": results, "count": len(results)})
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ServiceAreaRepository defines the data access needed to manage service areas
+type ServiceAreaRepository interface {
+	List(ctx context.Context) ([]models.ServiceArea, error)
+	ListActive(ctx context.Context) ([]models.ServiceArea, error)
+	Create(ctx context.Context, area *models.ServiceArea) (*models.ServiceArea, error)
+	Update(ctx context.Context, area *models.ServiceArea) (*models.ServiceArea, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetSummary(ctx context.Context, slug string) (*repositories.AreaSummary, error)
+}
+
+// ServiceAreaHandler serves service areas (admin routes require area:manage)
+type ServiceAreaHandler struct {
+	repo ServiceAreaRepository
+}
+
+// NewServiceAreaHandler creates a new service area handler
+func NewServiceAreaHandler(repo ServiceAreaRepository) *ServiceAreaHandler {
+	return &ServiceAreaHandler{repo: repo}
+}
+
+// ServiceAreaRequest is the body for creating or replacing a service area
+type ServiceAreaRequest struct {
+	Slug     string          `json:"slug" binding:"required,max=64"`
+	Name     string          `json:"name" binding:"required,max=255"`
+	Boundary json.RawMessage `json:"boundary" binding:"required"` // GeoJSON Polygon or MultiPolygon
+	IsActive *bool           `json:"is_active"`
+}
+
+// serviceAreaSlug matches URL-safe slugs such as jakarta-selatan
+var serviceAreaSlug = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// validate checks the slug and that the boundary is a GeoJSON polygon geometry
+func (req *ServiceAreaRequest) validate() error {
+	if !serviceAreaSlug.MatchString(req.Slug) {
+		return errors.New("slug must be lowercase letters, digits and dashes")
+	}
+	var geometry struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}
+	if err := json.Unmarshal(req.Boundary, &geometry); err != nil {
+		return errors.New("boundary must be a GeoJSON geometry")
+	}
+	if geometry.Type != "Polygon" && geometry.Type != "MultiPolygon" {
+		return errors.New("boundary must be a GeoJSON Polygon or MultiPolygon")
+	}
+	if len(geometry.Coordinates) == 0 {
+		return errors.New("boundary has no coordinates")
+	}
+	return nil
+}
+
+// ListActiveServiceAreas handles GET /api/v1/service-areas
+func (h *ServiceAreaHandler) ListActiveServiceAreas(c *gin.Context) {
+	areas, err := h.repo.ListActive(c.Request.Context())
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Service areas retrieved", areas)
+}
+
+// GetAreaSummary handles GET /api/v1/areas/:slug/summary for area landing pages
+func (h *ServiceAreaHandler) GetAreaSummary(c *gin.Context) {
+	summary, err := h.repo.GetSummary(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		sendServiceAreaError(c, err)
+		return
+	}
+
+	// Summaries are recomputed hourly
+	c.Header("Cache-Control", "public, max-age=3600")
+	utils.SendSuccess(c, "Area summary retrieved", summary)
+}
+
+// ListServiceAreas handles GET /api/v1/admin/service-areas
+func (h *ServiceAreaHandler) ListServiceAreas(c *gin.Context) {
+	areas, err := h.repo.List(c.Request.Context())
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Service areas retrieved", areas)
+}
+
+// CreateServiceArea handles POST /api/v1/admin/service-areas
+func (h *ServiceAreaHandler) CreateServiceArea(c *gin.Context) {
+	var input ServiceAreaRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	if err := input.validate(); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	created, err := h.repo.Create(c.Request.Context(), &models.ServiceArea{
+		Slug:     input.Slug,
+		Name:     input.Name,
+		Boundary: input.Boundary,
+		IsActive: input.IsActive == nil || *input.IsActive,
+	})
+	if err != nil {
+		sendServiceAreaError(c, err)
+		return
+	}
+
+	utils.SendCreated(c, "Service area created", created)
+}
+
+// UpdateServiceArea handles PUT /api/v1/admin/service-areas/:id
+func (h *ServiceAreaHandler) UpdateServiceArea(c *gin.Context) {
+	id, ok := parseServiceAreaID(c)
+	if !ok {
+		return
+	}
+
+	var input ServiceAreaRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	if err := input.validate(); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	updated, err := h.repo.Update(c.Request.Context(), &models.ServiceArea{
+		AreaID:   id,
+		Slug:     input.Slug,
+		Name:     input.Name,
+		Boundary: input.Boundary,
+		IsActive: input.IsActive == nil || *input.IsActive,
+	})
+	if err != nil {
+		sendServiceAreaError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Service area updated", updated)
+}
+
+// DeleteServiceArea handles DELETE /api/v1/admin/service-areas/:id
+func (h *ServiceAreaHandler) DeleteServiceArea(c *gin.Context) {
+	id, ok := parseServiceAreaID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Delete(c.Request.Context(), id); err != nil {
+		sendServiceAreaError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Service area deleted", gin.H{"area_id": id})
+}
+
+func parseServiceAreaID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid service area ID format", err)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// sendServiceAreaError maps service area repository errors to HTTP responses
+func sendServiceAreaError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrServiceAreaNotFound):
+		utils.SendError(c, http.StatusNotFound, "service area not found", err)
+	case errors.Is(err, repositories.ErrServiceAreaSlugTaken):
+		utils.SendError(c, http.StatusConflict, err.Error(), err)
+	case errors.Is(err, repositories.ErrInvalidBoundary):
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+	default:
+		utils.SendInternalError(c, err)
+	}
+}
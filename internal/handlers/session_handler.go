@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// sessionHeader carries the anonymous session ID of a signed-out client
+const sessionHeader = "X-Session-ID"
+
+// sessionIDPattern matches IDs issued by CreateSession (32 random bytes, base64url)
+var sessionIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{43}$`)
+
+// SessionRepository defines the data access needed for anonymous sessions
+type SessionRepository interface {
+	Create(ctx context.Context, sessionID string) (*models.AnonymousSession, error)
+	Get(ctx context.Context, sessionID string) (*models.AnonymousSession, error)
+	SavePOI(ctx context.Context, sessionID string, poiID uuid.UUID) error
+	UnsavePOI(ctx context.Context, sessionID string, poiID uuid.UUID) error
+	Merge(ctx context.Context, sessionID string, userID uuid.UUID) (int64, error)
+}
+
+// SessionHandler serves anonymous browsing sessions: recent views and
+// searches, and a temporary saved list merged into the account on login
+type SessionHandler struct {
+	repo SessionRepository
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(repo SessionRepository) *SessionHandler {
+	return &SessionHandler{repo: repo}
+}
+
+// CreateSession handles POST /api/v1/sessions
+func (h *SessionHandler) CreateSession(c *gin.Context) {
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	session, err := h.repo.Create(c.Request.Context(), base64.RawURLEncoding.EncodeToString(id))
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendCreated(c, "Session created", session)
+}
+
+// GetSession handles GET /api/v1/session
+func (h *SessionHandler) GetSession(c *gin.Context) {
+	sessionID, ok := requireSessionID(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.repo.Get(c.Request.Context(), sessionID)
+	if err != nil {
+		sendSessionError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Session retrieved", session)
+}
+
+// SaveSessionPOI handles POST /api/v1/session/saved-pois/:id
+func (h *SessionHandler) SaveSessionPOI(c *gin.Context) {
+	sessionID, ok := requireSessionID(c)
+	if !ok {
+		return
+	}
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+
+	if err := h.repo.SavePOI(c.Request.Context(), sessionID, poiID); err != nil {
+		sendSessionError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "POI saved", gin.H{"poi_id": poiID})
+}
+
+// UnsaveSessionPOI handles DELETE /api/v1/session/saved-pois/:id
+func (h *SessionHandler) UnsaveSessionPOI(c *gin.Context) {
+	sessionID, ok := requireSessionID(c)
+	if !ok {
+		return
+	}
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+
+	if err := h.repo.UnsavePOI(c.Request.Context(), sessionID, poiID); err != nil {
+		sendSessionError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "POI unsaved", gin.H{"poi_id": poiID})
+}
+
+// MergeSession handles POST /api/v1/me/session/merge. The session's saved
+// POIs are added to the user's saved POIs and the session is ended.
+func (h *SessionHandler) MergeSession(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	sessionID, ok := requireSessionID(c)
+	if !ok {
+		return
+	}
+
+	merged, err := h.repo.Merge(c.Request.Context(), sessionID, actor.UserID)
+	if err != nil {
+		sendSessionError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Session merged", gin.H{"merged_saved_pois": merged})
+}
+
+// sessionIDFromRequest returns the anonymous session ID sent by the client, if well-formed
+func sessionIDFromRequest(c *gin.Context) (string, bool) {
+	id := c.GetHeader(sessionHeader)
+	if !sessionIDPattern.MatchString(id) {
+		return "", false
+	}
+	return id, true
+}
+
+func requireSessionID(c *gin.Context) (string, bool) {
+	id, ok := sessionIDFromRequest(c)
+	if !ok {
+		utils.SendError(c, http.StatusBadRequest, "missing or invalid "+sessionHeader+" header", nil)
+		return "", false
+	}
+	return id, true
+}
+
+// searchParams keeps the search parameters that describe which POIs match
+func searchParams(query url.Values) map[string]string {
+	params := make(map[string]string, len(query))
+	for k := range query {
+		if v := query.Get(k); v != "" && !savedSearchIgnoredParams[k] {
+			params[k] = v
+		}
+	}
+	return params
+}
+
+// sendSessionError maps anonymous session repository errors to HTTP responses
+func sendSessionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrSessionNotFound):
+		utils.SendError(c, http.StatusNotFound, "session not found", err)
+	case errors.Is(err, repositories.ErrSessionPOINotFound):
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+	case errors.Is(err, repositories.ErrSessionSavedLimit):
+		utils.SendError(c, http.StatusConflict, fmt.Sprintf("at most %d POIs can be saved without signing in", repositories.MaxSessionSavedPOIs), err)
+	default:
+		utils.SendInternalError(c, err)
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/spam"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SpamGuard screens comments and reviews before they are stored
+type SpamGuard interface {
+	Check(ctx context.Context, userID uuid.UUID, kind string, targetID uuid.UUID, content string) error
+}
+
+// SpamRepository defines the user flags managed by admins
+type SpamRepository interface {
+	ListFlaggedUsers(ctx context.Context) ([]repositories.UserSpamStatus, error)
+	SetUserSpamStatus(ctx context.Context, userID uuid.UUID, shadowBanned, spamExempt *bool) (*repositories.UserSpamStatus, error)
+}
+
+// SpamHandler serves the admin overrides of the anti-spam heuristics (requires user:manage)
+type SpamHandler struct {
+	repo SpamRepository
+}
+
+// NewSpamHandler creates a new spam handler
+func NewSpamHandler(repo SpamRepository) *SpamHandler {
+	return &SpamHandler{repo: repo}
+}
+
+// SpamStatusRequest is the body for changing a user's spam flags; omitted flags are kept
+type SpamStatusRequest struct {
+	ShadowBanned *bool `json:"shadow_banned"`
+	SpamExempt   *bool `json:"spam_exempt"`
+}
+
+// ListFlaggedUsers handles GET /api/v1/admin/users/spam-status
+func (h *SpamHandler) ListFlaggedUsers(c *gin.Context) {
+	users, err := h.repo.ListFlaggedUsers(c.Request.Context())
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Flagged users retrieved", users)
+}
+
+// UpdateSpamStatus handles PUT /api/v1/admin/users/:id/spam-status
+func (h *SpamHandler) UpdateSpamStatus(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid user ID format", err)
+		return
+	}
+
+	var input SpamStatusRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	if input.ShadowBanned == nil && input.SpamExempt == nil {
+		utils.SendError(c, http.StatusBadRequest, "shadow_banned or spam_exempt is required", nil)
+		return
+	}
+
+	status, err := h.repo.SetUserSpamStatus(c.Request.Context(), userID, input.ShadowBanned, input.SpamExempt)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			utils.SendError(c, http.StatusNotFound, "user not found", err)
+			return
+		}
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Spam status updated", status)
+}
+
+// sendSpamError maps a spam guard rejection to an HTTP response. It reports
+// whether err was handled.
+func sendSpamError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, spam.ErrTooFast):
+		utils.SendError(c, http.StatusTooManyRequests, err.Error(), nil)
+	case errors.Is(err, spam.ErrDuplicate):
+		utils.SendError(c, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, spam.ErrTooManyLinks):
+		utils.SendError(c, http.StatusUnprocessableEntity, err.Error(), nil)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/services"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// defaultSpecialTimezone is used when a special does not specify one
+const defaultSpecialTimezone = "Asia/Jakarta"
+
+// SpecialRepository defines the data access needed for POI specials
+type SpecialRepository interface {
+	ListByPOI(ctx context.Context, poiID uuid.UUID, includeExpired bool) ([]models.Special, error)
+	GetByID(ctx context.Context, specialID uuid.UUID) (*models.Special, error)
+	Create(ctx context.Context, s *models.Special) (*models.Special, error)
+	Update(ctx context.Context, s *models.Special) (*models.Special, error)
+	Delete(ctx context.Context, specialID uuid.UUID) error
+}
+
+// SpecialHandler handles the /pois/:id/specials sub-resource
+type SpecialHandler struct {
+	repo    SpecialRepository
+	poiRepo POIRepository
+}
+
+// NewSpecialHandler creates a new special handler
+func NewSpecialHandler(repo SpecialRepository, poiRepo POIRepository) *SpecialHandler {
+	return &SpecialHandler{repo: repo, poiRepo: poiRepo}
+}
+
+// SpecialRequest is the body for creating or replacing a special
+type SpecialRequest struct {
+	Title          string     `json:"title" binding:"required,max=200"`
+	Description    *string    `json:"description"`
+	StartsAt       *time.Time `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at"`
+	Recurrence     string     `json:"recurrence" binding:"omitempty,oneof=none daily weekly monthly"`
+	RecurrenceDays []int64    `json:"recurrence_days"`
+	DailyStartTime *string    `json:"daily_start_time"` // HH:MM
+	DailyEndTime   *string    `json:"daily_end_time"`   // HH:MM
+	Timezone       string     `json:"timezone"`
+}
+
+// toModel validates the request and converts it to a Special. startsAt is used
+// when the request has no starts_at: now on create, the stored start on update.
+func (req *SpecialRequest) toModel(startsAt time.Time) (*models.Special, error) {
+	s := &models.Special{
+		Title:          req.Title,
+		Description:    req.Description,
+		StartsAt:       startsAt,
+		EndsAt:         req.EndsAt,
+		Recurrence:     req.Recurrence,
+		RecurrenceDays: pq.Int64Array(req.RecurrenceDays),
+		DailyStartTime: req.DailyStartTime,
+		DailyEndTime:   req.DailyEndTime,
+		Timezone:       req.Timezone,
+	}
+	if req.StartsAt != nil {
+		s.StartsAt = *req.StartsAt
+	}
+	if s.Recurrence == "" {
+		s.Recurrence = models.RecurrenceNone
+	}
+	if s.Timezone == "" {
+		s.Timezone = defaultSpecialTimezone
+	}
+
+	if s.EndsAt != nil && !s.EndsAt.After(s.StartsAt) {
+		return nil, errors.New("ends_at must be after starts_at")
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", s.Timezone)
+	}
+
+	switch s.Recurrence {
+	case models.RecurrenceWeekly, models.RecurrenceMonthly:
+		lo, hi := int64(0), int64(6)
+		if s.Recurrence == models.RecurrenceMonthly {
+			lo, hi = 1, 31
+		}
+		if len(s.RecurrenceDays) == 0 {
+			return nil, fmt.Errorf("recurrence_days is required for %s specials", s.Recurrence)
+		}
+		for _, d := range s.RecurrenceDays {
+			if d < lo || d > hi {
+				return nil, fmt.Errorf("recurrence_days must be between %d and %d", lo, hi)
+			}
+		}
+	default:
+		s.RecurrenceDays = nil
+	}
+
+	if (s.DailyStartTime == nil) != (s.DailyEndTime == nil) {
+		return nil, errors.New("daily_start_time and daily_end_time must be set together")
+	}
+	for _, t := range []*string{s.DailyStartTime, s.DailyEndTime} {
+		if t == nil {
+			continue
+		}
+		if _, err := time.Parse("15:04", *t); err != nil {
+			return nil, fmt.Errorf("invalid time %q, expected HH:MM", *t)
+		}
+	}
+
+	return s, nil
+}
+
+// ListSpecials handles GET /api/v1/pois/:id/specials?include_expired=true
+func (h *SpecialHandler) ListSpecials(c *gin.Context) {
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+
+	specials, err := h.repo.ListByPOI(c.Request.Context(), poiID, c.Query("include_expired") == "true")
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Specials retrieved", specials)
+}
+
+// CreateSpecial handles POST /api/v1/pois/:id/specials (owner or poi:merge)
+func (h *SpecialHandler) CreateSpecial(c *gin.Context) {
+	poiID, actor, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var input SpecialRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	special, err := input.toModel(time.Now())
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	special.PoiID = poiID
+	special.CreatedBy = &actor
+
+	created, err := h.repo.Create(c.Request.Context(), special)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendCreated(c, "Special created", created)
+}
+
+// UpdateSpecial handles PUT /api/v1/pois/:id/specials/:special_id (owner or poi:merge)
+func (h *SpecialHandler) UpdateSpecial(c *gin.Context) {
+	poiID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	current, ok := h.specialInPOI(c, poiID)
+	if !ok {
+		return
+	}
+
+	var input SpecialRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	special, err := input.toModel(current.StartsAt)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	special.SpecialID = current.SpecialID
+
+	updated, err := h.repo.Update(c.Request.Context(), special)
+	if err != nil {
+		sendSpecialError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Special updated", updated)
+}
+
+// DeleteSpecial handles DELETE /api/v1/pois/:id/specials/:special_id (owner or poi:merge)
+func (h *SpecialHandler) DeleteSpecial(c *gin.Context) {
+	poiID, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	special, ok := h.specialInPOI(c, poiID)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Delete(c.Request.Context(), special.SpecialID); err != nil {
+		sendSpecialError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Special deleted", gin.H{"special_id": special.SpecialID})
+}
+
+// authorize checks that the caller owns the POI in the :id param or holds poi:merge.
+// It writes the error response itself and returns ok=false on failure.
+func (h *SpecialHandler) authorize(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	poi, err := h.poiRepo.GetByID(c.Request.Context(), poiID)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	if !actor.Can(services.PermPOIMerge) && !isPOIOwner(poi, actor.UserID) {
+		utils.SendError(c, http.StatusForbidden, "not authorized to manage specials for this POI", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return poiID, actor.UserID, true
+}
+
+// specialInPOI loads the special in :special_id and checks it belongs to poiID
+func (h *SpecialHandler) specialInPOI(c *gin.Context, poiID uuid.UUID) (*models.Special, bool) {
+	specialID, err := uuid.Parse(c.Param("special_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid special ID format", err)
+		return nil, false
+	}
+
+	special, err := h.repo.GetByID(c.Request.Context(), specialID)
+	if err != nil {
+		sendSpecialError(c, err)
+		return nil, false
+	}
+	if special.PoiID != poiID {
+		sendSpecialError(c, repositories.ErrSpecialNotFound)
+		return nil, false
+	}
+	return special, true
+}
+
+// sendSpecialError maps special repository errors to HTTP responses
+func sendSpecialError(c *gin.Context, err error) {
+	if errors.Is(err, repositories.ErrSpecialNotFound) {
+		utils.SendError(c, http.StatusNotFound, "special not found", err)
+		return
+	}
+	utils.SendInternalError(c, err)
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpecialRequestWithoutStartKeepsGivenStart(t *testing.T) {
+	stored := time.Now().Add(-48 * time.Hour)
+	ends := stored.Add(24 * time.Hour) // already over
+	req := SpecialRequest{Title: "Happy hour", EndsAt: &ends}
+
+	special, err := req.toModel(stored)
+	if err != nil {
+		t.Fatalf("update without starts_at: %v", err)
+	}
+	if !special.StartsAt.Equal(stored) {
+		t.Fatalf("starts_at = %v, want the stored %v", special.StartsAt, stored)
+	}
+}
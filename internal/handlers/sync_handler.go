@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SyncRepository defines the data access needed for offline delta sync
+type SyncRepository interface {
+	ListChangesSince(ctx context.Context, after *repositories.SyncCursor, area string, limit int) ([]repositories.SyncPOI, error)
+}
+
+// SyncHandler serves change feeds that let the mobile app keep a local cache
+type SyncHandler struct {
+	repo SyncRepository
+}
+
+// NewSyncHandler creates a new sync handler
+func NewSyncHandler(repo SyncRepository) *SyncHandler {
+	return &SyncHandler{repo: repo}
+}
+
+// SyncPOIs handles GET /api/v1/sync/pois?since=&area=&limit=500. since is the
+// next_cursor of the previous response or an RFC 3339 timestamp; without it
+// every approved POI is returned. Changes come oldest first; deleted or
+// unpublished POIs appear as tombstones. Clients keep requesting with
+// next_cursor while has_more is set, and store it for the next sync.
+func (h *SyncHandler) SyncPOIs(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "500"))
+	if err != nil || limit < 1 || limit > 1000 {
+		utils.SendError(c, http.StatusBadRequest, "limit must be between 1 and 1000", err)
+		return
+	}
+
+	var after *repositories.SyncCursor
+	since := c.Query("since")
+	if since != "" {
+		if t, err := time.Parse(time.RFC3339Nano, since); err == nil {
+			after = &repositories.SyncCursor{ChangedAt: t}
+		} else if t, id, err := decodeTimeCursor(since); err == nil {
+			after = &repositories.SyncCursor{ChangedAt: t, PoiID: id}
+		} else {
+			utils.SendError(c, http.StatusBadRequest, "since must be a sync cursor or an RFC 3339 timestamp", err)
+			return
+		}
+	}
+
+	changes, err := h.repo.ListChangesSince(c.Request.Context(), after, c.Query("area"), limit+1)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	hasMore := len(changes) > limit
+	if hasMore {
+		changes = changes[:limit]
+	}
+
+	// With nothing new the client keeps its position
+	nextCursor := since
+	if len(changes) > 0 {
+		last := changes[len(changes)-1]
+		nextCursor = encodeTimeCursor(last.ChangedAt, last.PoiID)
+	}
+
+	c.Header("Cache-Control", "no-store")
+	utils.SendSuccess(c, "POI changes retrieved", gin.H{
+		"changes":     changes,
+		"next_cursor": nextCursor,
+		"has_more":    hasMore,
+	})
+}
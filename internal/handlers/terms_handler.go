@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TermsRepository defines the data access for terms of service versions
+type TermsRepository interface {
+	CurrentVersion(ctx context.Context) (*models.TermsVersion, error)
+	AcceptedVersion(ctx context.Context, userID uuid.UUID) (*int, error)
+	Accept(ctx context.Context, userID uuid.UUID, version int, ip, userAgent string) error
+}
+
+// TermsHandler serves the terms of service and records their acceptance
+type TermsHandler struct {
+	repo TermsRepository
+}
+
+// NewTermsHandler creates a new terms handler
+func NewTermsHandler(repo TermsRepository) *TermsHandler {
+	return &TermsHandler{repo: repo}
+}
+
+// GetCurrent handles GET /api/v1/tos
+func (h *TermsHandler) GetCurrent(c *gin.Context) {
+	current, err := h.repo.CurrentVersion(c.Request.Context())
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	if current == nil {
+		utils.SendError(c, http.StatusNotFound, "no terms of service published", nil)
+		return
+	}
+	utils.SendSuccess(c, "Terms of service retrieved", current)
+}
+
+// AcceptTermsRequest is the body for POST /api/v1/me/accept-tos
+type AcceptTermsRequest struct {
+	Version int `json:"version" binding:"required,min=1"`
+}
+
+// AcceptTerms handles POST /api/v1/me/accept-tos
+func (h *TermsHandler) AcceptTerms(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	var input AcceptTermsRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+
+	err := h.repo.Accept(c.Request.Context(), actor.UserID, input.Version, c.ClientIP(), c.Request.UserAgent())
+	if errors.Is(err, repositories.ErrTermsNotCurrent) {
+		utils.SendError(c, http.StatusConflict, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Terms of service accepted", gin.H{"tos_accepted_version": input.Version})
+}
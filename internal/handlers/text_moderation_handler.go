@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/textmod"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TextModerator sanitizes user generated text and keeps the originals of changed text
+type TextModerator interface {
+	Screen(ctx context.Context, field, text string) (*textmod.Outcome, error)
+	Record(ctx context.Context, targetID uuid.UUID, userID *uuid.UUID, out *textmod.Outcome) error
+}
+
+// moderateText replaces *text with its sanitized version. It returns nil when
+// there is nothing to moderate, and reports false after sending an error response.
+func moderateText(c *gin.Context, m TextModerator, field string, text *string) (*textmod.Outcome, bool) {
+	if m == nil || text == nil {
+		return nil, true
+	}
+	out, err := m.Screen(c.Request.Context(), field, *text)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return nil, false
+	}
+	*text = out.Text
+	return out, true
+}
+
+// recordModeration stores the original of text already saved under targetID.
+// Failures are logged: the sanitized text is stored either way.
+func recordModeration(ctx context.Context, m TextModerator, targetID uuid.UUID, userID *uuid.UUID, out *textmod.Outcome) {
+	if m == nil || out == nil {
+		return
+	}
+	if err := m.Record(ctx, targetID, userID, out); err != nil {
+		slog.WarnContext(ctx, "failed to record text moderation", "field", out.Field, "target_id", targetID, "error", err)
+	}
+}
+
+// TextModerationRepository defines the review queue of flagged text
+type TextModerationRepository interface {
+	ListPending(ctx context.Context, field string, limit, offset int) ([]repositories.TextModeration, error)
+	Resolve(ctx context.Context, id, reviewerID uuid.UUID) (*repositories.TextModeration, error)
+}
+
+// TextModerationHandler serves the manual review of flagged text (requires poi:approve)
+type TextModerationHandler struct {
+	repo TextModerationRepository
+}
+
+// NewTextModerationHandler creates a new text moderation handler
+func NewTextModerationHandler(repo TextModerationRepository) *TextModerationHandler {
+	return &TextModerationHandler{repo: repo}
+}
+
+// ListPending handles GET /api/v1/admin/text-moderation?field=comment
+func (h *TextModerationHandler) ListPending(c *gin.Context) {
+	field := c.Query("field")
+	switch field {
+	case "", textmod.FieldPOIDescription, textmod.FieldComment, textmod.FieldReview:
+	default:
+		utils.SendError(c, http.StatusBadRequest, "field must be poi_description, comment or review", nil)
+		return
+	}
+
+	page, limit := utils.GetPagination(c)
+	offset := utils.GetOffset(page, limit)
+	entries, err := h.repo.ListPending(c.Request.Context(), field, limit, offset)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendPaginated(c, "Flagged text retrieved", entries, page, limit, len(entries)+offset)
+}
+
+// Resolve handles POST /api/v1/admin/text-moderation/:id/resolve
+func (h *TextModerationHandler) Resolve(c *gin.Context) {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid moderation ID format", err)
+		return
+	}
+
+	entry, err := h.repo.Resolve(c.Request.Context(), id, actor.UserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrTextModerationNotFound) {
+			utils.SendError(c, http.StatusNotFound, err.Error(), err)
+			return
+		}
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Flagged text resolved", entry)
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/services"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TranslationRepository defines the data access needed for POI translations
+type TranslationRepository interface {
+	ListByPOI(ctx context.Context, poiID uuid.UUID) ([]models.POITranslation, error)
+	Upsert(ctx context.Context, t *models.POITranslation) (*models.POITranslation, error)
+	Delete(ctx context.Context, poiID uuid.UUID, locale string) error
+}
+
+// TranslationHandler handles the /pois/:id/translations sub-resource
+type TranslationHandler struct {
+	repo      TranslationRepository
+	poiRepo   POIRepository
+	supported []string // First entry is the default locale
+}
+
+// NewTranslationHandler creates a new translation handler. supported lists the
+// locales the API serves, default locale first.
+func NewTranslationHandler(repo TranslationRepository, poiRepo POIRepository, supported []string) *TranslationHandler {
+	return &TranslationHandler{repo: repo, poiRepo: poiRepo, supported: supported}
+}
+
+// TranslationRequest is the body for PUT /api/v1/pois/:id/translations/:locale
+type TranslationRequest struct {
+	Name        *string `json:"name" binding:"omitempty,max=255"`
+	Description *string `json:"description"`
+}
+
+// ListTranslations handles GET /api/v1/pois/:id/translations
+func (h *TranslationHandler) ListTranslations(c *gin.Context) {
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return
+	}
+
+	translations, err := h.repo.ListByPOI(c.Request.Context(), poiID)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Translations retrieved", gin.H{
+		"default_locale": h.supported[0],
+		"translations":   translations,
+	})
+}
+
+// UpsertTranslation handles PUT /api/v1/pois/:id/translations/:locale (owner or poi:merge)
+func (h *TranslationHandler) UpsertTranslation(c *gin.Context) {
+	poiID, actor, locale, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var input TranslationRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	if input.Name == nil && input.Description == nil {
+		utils.SendError(c, http.StatusBadRequest, "name or description is required", nil)
+		return
+	}
+
+	translation, err := h.repo.Upsert(c.Request.Context(), &models.POITranslation{
+		PoiID:       poiID,
+		Locale:      locale,
+		Name:        input.Name,
+		Description: input.Description,
+		UpdatedBy:   &actor,
+	})
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Translation saved", translation)
+}
+
+// DeleteTranslation handles DELETE /api/v1/pois/:id/translations/:locale (owner or poi:merge)
+func (h *TranslationHandler) DeleteTranslation(c *gin.Context) {
+	poiID, _, locale, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Delete(c.Request.Context(), poiID, locale); err != nil {
+		if errors.Is(err, repositories.ErrTranslationNotFound) {
+			utils.SendError(c, http.StatusNotFound, "translation not found", err)
+			return
+		}
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Translation deleted", gin.H{"poi_id": poiID, "locale": locale})
+}
+
+// authorize validates the :id and :locale params and checks the caller owns the
+// POI or holds poi:merge. It writes the error response itself and returns ok=false on failure.
+func (h *TranslationHandler) authorize(c *gin.Context) (uuid.UUID, uuid.UUID, string, bool) {
+	poiID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid POI ID format", err)
+		return uuid.Nil, uuid.Nil, "", false
+	}
+
+	locale := c.Param("locale")
+	if locale == h.supported[0] {
+		utils.SendError(c, http.StatusBadRequest, "default locale content is edited on the POI itself", nil)
+		return uuid.Nil, uuid.Nil, "", false
+	}
+	if !slices.Contains(h.supported, locale) {
+		utils.SendError(c, http.StatusBadRequest, "unsupported locale", nil)
+		return uuid.Nil, uuid.Nil, "", false
+	}
+
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return uuid.Nil, uuid.Nil, "", false
+	}
+
+	poi, err := h.poiRepo.GetByID(c.Request.Context(), poiID)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "POI not found", err)
+		return uuid.Nil, uuid.Nil, "", false
+	}
+	if !actor.Can(services.PermPOIMerge) && !isPOIOwner(poi, actor.UserID) {
+		utils.SendError(c, http.StatusForbidden, "not authorized to translate this POI", nil)
+		return uuid.Nil, uuid.Nil, "", false
+	}
+
+	return poiID, actor.UserID, locale, true
+}
+
+// translationLocale returns the negotiated locale when it differs from the
+// default, i.e. when translated content should be overlaid
+func translationLocale(c *gin.Context) string {
+	locale := c.GetString("locale")
+	if locale == "" || locale == c.GetString("default_locale") {
+		return ""
+	}
+	return locale
+}
+
+// setContentLanguage reports the locale the POI content was actually served in
+func setContentLanguage(c *gin.Context, poi *repositories.POI) {
+	if poi.ContentLocale != nil {
+		c.Header("Content-Language", *poi.ContentLocale)
+	} else if def := c.GetString("default_locale"); def != "" {
+		c.Header("Content-Language", def)
+	}
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrendingRepository defines the data access needed to rank trending POIs
+type TrendingRepository interface {
+	Trending(ctx context.Context, area string, limit int) ([]models.TrendingPOI, error)
+}
+
+// TrendingHandler serves the "Trending now" carousel
+type TrendingHandler struct {
+	repo TrendingRepository
+}
+
+// NewTrendingHandler creates a new trending handler
+func NewTrendingHandler(repo TrendingRepository) *TrendingHandler {
+	return &TrendingHandler{repo: repo}
+}
+
+// GetTrendingPOIs handles GET /api/v1/pois/trending?area=&limit=10
+func (h *TrendingHandler) GetTrendingPOIs(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > repositories.TrendingMaxLimit {
+		utils.SendError(c, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", repositories.TrendingMaxLimit), err)
+		return
+	}
+
+	pois, err := h.repo.Trending(c.Request.Context(), c.Query("area"), limit)
+	if err != nil {
+		sendServiceAreaError(c, err)
+		return
+	}
+
+	// Rankings are recomputed every few minutes
+	c.Header("Cache-Control", "public, max-age=300")
+	utils.SendSuccess(c, "Trending POIs retrieved", pois)
+}
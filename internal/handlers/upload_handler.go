@@ -1,41 +1,83 @@
 package handlers
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"maukemana-backend/internal/breaker"
 	"maukemana-backend/internal/imaging"
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/services"
 	"maukemana-backend/internal/storage"
 	"maukemana-backend/internal/utils"
 )
 
 // UploadHandler handles file upload operations
 type UploadHandler struct {
-	r2             *storage.R2Client
+	store          storage.Provider
 	imagingService *imaging.Service
+	originalURLTTL time.Duration // Lifetime of signed original URLs
+	multipart      MultipartUploadStore
+	uploads        UploadStore
+}
+
+// uploadRecordTTL is how long a presigned upload can be finalized; uploads
+// still open after it are deleted as abandoned
+const uploadRecordTTL = time.Hour
+
+// UploadStore tracks presigned uploads from presign to finalize
+type UploadStore interface {
+	Create(ctx context.Context, u *models.Upload) (*models.Upload, error)
+	Get(ctx context.Context, uploadID, userID uuid.UUID) (*models.Upload, error)
+	GetByKey(ctx context.Context, key string, userID uuid.UUID) (*models.Upload, error)
+	RecordProgress(ctx context.Context, uploadID uuid.UUID, bytesUploaded int64) (*models.Upload, error)
+	MarkFinalized(ctx context.Context, uploadID uuid.UUID) error
+	Reject(ctx context.Context, rejection *models.UploadRejection) error
+	ListRejections(ctx context.Context, userID *uuid.UUID, limit, offset int) ([]models.UploadRejection, error)
 }
 
 // NewUploadHandler creates a new upload handler
-// NewUploadHandler creates a new upload handler
-func NewUploadHandler(r2 *storage.R2Client, imagingService *imaging.Service) *UploadHandler {
+func NewUploadHandler(store storage.Provider, imagingService *imaging.Service) *UploadHandler {
 	return &UploadHandler{
-		r2:             r2,
+		store:          store,
 		imagingService: imagingService,
+		originalURLTTL: 5 * time.Minute,
+	}
+}
+
+// UseOriginalURLTTL sets how long signed original URLs stay valid
+func (h *UploadHandler) UseOriginalURLTTL(ttl time.Duration) {
+	if ttl > 0 {
+		h.originalURLTTL = ttl
 	}
 }
 
+// UseUploadRecords enables tracking uploads, progress heartbeats and the
+// finalize check of the stored object
+func (h *UploadHandler) UseUploadRecords(store UploadStore) {
+	h.uploads = store
+}
+
 // PresignRequest represents the request for a presigned URL
 type PresignRequest struct {
 	Filename    string `json:"filename" binding:"required"`
 	ContentType string `json:"content_type" binding:"required"`
-	Category    string `json:"category"` // "cover", "gallery", "profile", "general"
+	SizeBytes   int64  `json:"size_bytes" binding:"required,gt=0"` // Exact file size; the presigned PUT only accepts this length
+	Category    string `json:"category"`                           // "cover", "gallery", "profile", "general"
 }
 
 // PresignResponse contains the presigned URL and upload information
@@ -55,6 +97,10 @@ type FinalizeRequest struct {
 	UploadKey string              `json:"upload_key" binding:"required"`
 	Category  string              `json:"category"`
 	CropData  *imaging.CropConfig `json:"crop_data"`
+	// Optional SHA-256 (hex) of the uploaded file. When an asset with this hash
+	// is already ready and the stored object hashes to it, the upload is
+	// discarded and linked to the asset without processing.
+	ContentHash string `json:"content_hash" binding:"omitempty,len=64,hexadecimal"`
 }
 
 // ReprocessRequest represents the request to reprocess an existing asset
@@ -81,6 +127,7 @@ type AssetStatusResponse struct {
 	CreatedAt   string                     `json:"created_at"`
 	ProcessedAt string                     `json:"processed_at,omitempty"`
 	Error       string                     `json:"error,omitempty"`
+	Moderation  string                     `json:"moderation_status,omitempty"`
 }
 
 // OriginalInfo contains information about the original image
@@ -99,48 +146,28 @@ type DerivativeInfo struct {
 	URLPattern string   `json:"url_pattern"`
 }
 
-// GetPresignedURL generates a presigned URL for direct upload to R2
-func (h *UploadHandler) GetPresignedURL(c *gin.Context) {
-	ctx := c.Request.Context()
-
-	var req PresignRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.SendValidationError(c, err)
-		return
-	}
-
-	// Validate content type - expanded list
-	allowedTypes := map[string]bool{
-		"image/jpeg": true,
-		"image/png":  true,
-		"image/webp": true,
-		"image/gif":  true,
-		"image/heic": true,
-		"image/heif": true,
-		"image/avif": true,
-	}
-	if !allowedTypes[req.ContentType] {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid content type",
-			"allowed": []string{"image/jpeg", "image/png", "image/webp", "image/gif", "image/heic", "image/avif"},
-		})
-		return
-	}
-
-	// Get user ID from context
-	userIDVal, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-		return
-	}
-	userID := userIDVal.(uuid.UUID)
+// allowedUploadTypes are the content types accepted for uploads
+var allowedUploadTypes = []string{"image/jpeg", "image/png", "image/webp", "image/gif", "image/heic", "image/avif"}
+
+// uploadContentTypes are the content types accepted for uploads, including aliases
+var uploadContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+	"image/gif":  true,
+	"image/heic": true,
+	"image/heif": true,
+	"image/avif": true,
+}
 
-	// Generate unique upload key
-	// Format: uploads/tmp/{user_id}/{timestamp}_{uuid}.{ext}
-	ext := filepath.Ext(req.Filename)
+// uploadKey returns the temporary storage key of a new upload:
+// uploads/tmp/{user_id}/{category}/{timestamp}_{id}.{ext}. Finalize only
+// accepts keys under the caller's own prefix.
+func uploadKey(userID, uploadID uuid.UUID, category, filename, contentType string) string {
+	ext := filepath.Ext(filename)
 	if ext == "" {
 		// Infer extension from content type
-		switch req.ContentType {
+		switch contentType {
 		case "image/jpeg":
 			ext = ".jpg"
 		case "image/png":
@@ -157,6 +184,37 @@ func (h *UploadHandler) GetPresignedURL(c *gin.Context) {
 			ext = ".bin"
 		}
 	}
+	return fmt.Sprintf("uploads/tmp/%s/%s/%d_%s%s",
+		userID.String(),
+		category,
+		time.Now().Unix(),
+		uploadID.String()[:8],
+		ext,
+	)
+}
+
+// GetPresignedURL generates a presigned URL for direct upload to R2
+func (h *UploadHandler) GetPresignedURL(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req PresignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+
+	if !uploadContentTypes[req.ContentType] {
+		utils.SendError(c, http.StatusBadRequest, "invalid content type, allowed: "+strings.Join(allowedUploadTypes, ", "), nil)
+		return
+	}
+
+	// Get user ID from context
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	userID := userIDVal.(uuid.UUID)
 
 	category := req.Category
 	if category == "" {
@@ -164,19 +222,19 @@ func (h *UploadHandler) GetPresignedURL(c *gin.Context) {
 	}
 
 	uploadID := uuid.New()
-	key := fmt.Sprintf("uploads/tmp/%s/%s/%d_%s%s",
-		userID.String(),
-		category,
-		time.Now().Unix(),
-		uploadID.String()[:8],
-		ext,
-	)
+	key := uploadKey(userID, uploadID, category, req.Filename, req.ContentType)
 
 	// Get size limits for category
 	limits := imaging.GetCategoryLimits(category)
+	if req.SizeBytes > limits.MaxBytes {
+		utils.SendError(c, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("file size %d exceeds maximum %d bytes for %s uploads", req.SizeBytes, limits.MaxBytes, category), nil)
+		return
+	}
 
-	// Generate presigned URL
-	uploadURL, err := h.r2.GeneratePresignedURL(ctx, key, req.ContentType)
+	// Generate presigned URL bound to the declared size, so the PUT cannot
+	// upload more than the category allows
+	uploadURL, err := h.store.GeneratePresignedURLWithMaxSize(ctx, key, req.ContentType, req.SizeBytes)
 	if err != nil {
 		utils.SendInternalError(c, err)
 		return
@@ -184,15 +242,31 @@ func (h *UploadHandler) GetPresignedURL(c *gin.Context) {
 
 	expiresAt := time.Now().Add(15 * time.Minute)
 
+	if h.uploads != nil {
+		_, err := h.uploads.Create(ctx, &models.Upload{
+			UploadID:          uploadID,
+			UserID:            userID,
+			StorageKey:        key,
+			Category:          category,
+			ContentType:       req.ContentType,
+			ExpectedSizeBytes: req.SizeBytes,
+			ExpiresAt:         time.Now().Add(uploadRecordTTL),
+		})
+		if err != nil {
+			utils.SendInternalError(c, err)
+			return
+		}
+	}
+
 	utils.SendSuccess(c, "Presigned URL generated", PresignResponse{
 		UploadID:        uploadID.String(),
 		UploadURL:       uploadURL,
 		UploadExpiresAt: expiresAt.Format(time.RFC3339),
 		MaxSizeBytes:    limits.MaxBytes,
-		AllowedTypes:    []string{"image/jpeg", "image/png", "image/webp", "image/gif", "image/heic", "image/avif"},
+		AllowedTypes:    allowedUploadTypes,
 		Key:             key,
 		// Legacy: also include public_url for backward compatibility
-		PublicURL: h.r2.GetPublicURL(key),
+		PublicURL: h.store.GetPublicURL(key),
 	})
 }
 
@@ -207,7 +281,7 @@ func (h *UploadHandler) FinalizeUpload(c *gin.Context) {
 	// Get user ID from context
 	userIDVal, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
 	userID := userIDVal.(uuid.UUID)
@@ -215,7 +289,7 @@ func (h *UploadHandler) FinalizeUpload(c *gin.Context) {
 	// Verify the upload key belongs to this user
 	expectedPrefix := fmt.Sprintf("uploads/tmp/%s/", userID.String())
 	if !strings.HasPrefix(req.UploadKey, expectedPrefix) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "not authorized for this upload"})
+		utils.SendError(c, http.StatusForbidden, "not authorized for this upload", nil)
 		return
 	}
 
@@ -224,30 +298,314 @@ func (h *UploadHandler) FinalizeUpload(c *gin.Context) {
 		category = "general"
 	}
 
+	// The object must be stored in full before it is processed
+	var upload *models.Upload
+	if h.uploads != nil {
+		var ok bool
+		if upload, ok = h.verifyUpload(c, req.UploadKey, userID); !ok {
+			return
+		}
+	}
+
+	if !h.inspectUpload(c, req.UploadKey, userID, upload) {
+		return
+	}
+
+	// Same image as an existing asset: link to it instead of processing again.
+	// Crops render new derivatives, so they always go through the pipeline.
+	if req.ContentHash != "" && req.CropData == nil {
+		hash := strings.ToLower(req.ContentHash)
+		if asset, ok := h.imagingService.GetAsset(c.Request.Context(), hash); ok && asset.Status == imaging.StatusReady && !asset.ModerationStatus.Blocked() && h.storedHashMatches(c.Request.Context(), req.UploadKey, hash) {
+			if err := h.store.DeleteObject(c.Request.Context(), req.UploadKey); err != nil {
+				slog.Warn("failed to delete duplicate upload", "key", req.UploadKey, "error", err)
+			}
+			h.markFinalized(c, upload)
+			utils.SendSuccess(c, "Upload matches an existing asset", FinalizeResponse{
+				AssetID:     asset.ID.String(),
+				ContentHash: asset.ContentHash,
+				Status:      string(imaging.StatusReady),
+				StatusURL:   fmt.Sprintf("/api/v1/assets/%s", asset.ID.String()),
+			})
+			return
+		}
+	}
+
 	// Queue for async processing
 	jobID, err := h.imagingService.QueueProcessing(req.UploadKey, category, userID, req.CropData)
 	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "processing queue is full, try again later"})
+		utils.SendError(c, http.StatusServiceUnavailable, "processing queue is full, try again later", nil)
 		return
 	}
+	h.markFinalized(c, upload)
+
+	utils.SendAccepted(c, "Processing queued", FinalizeResponse{
+		AssetID:                    jobID.String(),
+		Status:                     "processing",
+		EstimatedCompletionSeconds: 5,
+		StatusURL:                  fmt.Sprintf("/api/v1/assets/%s", jobID.String()),
+	})
+}
+
+// storedHashMatches reports whether the stored object of key hashes to the
+// client's content hash. The hash is only a hint: a wrong or forged one must
+// not link the upload to someone else's asset, so on a mismatch or a read
+// error the upload is processed like any other.
+func (h *UploadHandler) storedHashMatches(ctx context.Context, key, hash string) bool {
+	stream, err := h.store.GetObjectStream(ctx, key, "")
+	if err != nil {
+		slog.WarnContext(ctx, "failed to read upload for hash check", "key", key, "error", err)
+		return false
+	}
+	defer stream.Body.Close()
+
+	sum := sha256.New()
+	if _, err := io.Copy(sum, stream.Body); err != nil {
+		slog.WarnContext(ctx, "failed to read upload for hash check", "key", key, "error", err)
+		return false
+	}
+	if hex.EncodeToString(sum.Sum(nil)) != hash {
+		slog.WarnContext(ctx, "upload content hash does not match the stored object", "key", key)
+		return false
+	}
+	return true
+}
+
+// verifyUpload checks that the upload record of key is open and that the
+// stored object has the declared size, writing the error response when not
+func (h *UploadHandler) verifyUpload(c *gin.Context, key string, userID uuid.UUID) (*models.Upload, bool) {
+	ctx := c.Request.Context()
+	upload, err := h.uploads.GetByKey(ctx, key, userID)
+	if errors.Is(err, repositories.ErrUploadNotFound) {
+		utils.SendError(c, http.StatusNotFound, err.Error(), nil)
+		return nil, false
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return nil, false
+	}
+	switch {
+	case upload.Status == models.UploadFinalized:
+		utils.SendError(c, http.StatusConflict, "upload is already finalized", nil)
+		return nil, false
+	case upload.Status == models.UploadExpired || time.Now().After(upload.ExpiresAt):
+		utils.SendError(c, http.StatusGone, "upload expired", nil)
+		return nil, false
+	}
+
+	object, err := h.store.StatObject(ctx, key)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		sendUploadMissing(c)
+		return nil, false
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return nil, false
+	}
+	if object.Size != upload.ExpectedSizeBytes {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, utils.Response{
+			Success: false,
+			Message: "uploaded file size does not match the declared size",
+			Error:   gin.H{"code": "size_mismatch", "expected_size_bytes": upload.ExpectedSizeBytes, "actual_size_bytes": object.Size},
+		})
+		return nil, false
+	}
+	return upload, true
+}
+
+// sendUploadMissing answers finalize for a key with no stored object
+func sendUploadMissing(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusConflict, utils.Response{
+		Success: false,
+		Message: "the file has not been uploaded yet",
+		Error:   gin.H{"code": "upload_missing"},
+	})
+}
+
+// inspectUpload sniffs the start of the stored object before it is queued.
+// Presigned URLs let clients store any bytes under an image key, so SVG, PDF,
+// markup and scripts are rejected here whatever the extension or declared
+// type, and formats this server's libvips cannot decode get a clear 422
+// instead of failing in the pipeline.
+func (h *UploadHandler) inspectUpload(c *gin.Context, key string, userID uuid.UUID, upload *models.Upload) bool {
+	ctx := c.Request.Context()
+	var head []byte
+	stream, err := h.store.GetObjectStream(ctx, key, fmt.Sprintf("bytes=0-%d", imaging.SniffBytes-1))
+	switch {
+	case errors.Is(err, storage.ErrObjectNotFound):
+		sendUploadMissing(c)
+		return false
+	case errors.Is(err, storage.ErrInvalidRange):
+		// Empty object; sniffing rejects it below
+	case err != nil:
+		utils.SendInternalError(c, err)
+		return false
+	default:
+		head, err = io.ReadAll(io.LimitReader(stream.Body, imaging.SniffBytes))
+		stream.Body.Close()
+		if err != nil {
+			utils.SendInternalError(c, err)
+			return false
+		}
+	}
+
+	format, err := imaging.SniffUpload(head)
+	var rejection *imaging.ContentRejection
+	if errors.As(err, &rejection) {
+		h.rejectUpload(c, key, userID, upload, rejection)
+		return false
+	}
+	if !imaging.CanDecode(format) {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, utils.Response{
+			Success: false,
+			Message: format + " images are not supported by this server; convert the file to JPEG or PNG and upload it again",
+			Error:   gin.H{"code": "format_unsupported", "format": format},
+		})
+		return false
+	}
+	return true
+}
+
+// rejectUpload deletes an upload that failed content sniffing, records it for
+// abuse monitoring and answers 422
+func (h *UploadHandler) rejectUpload(c *gin.Context, key string, userID uuid.UUID, upload *models.Upload, rejection *imaging.ContentRejection) {
+	ctx := c.Request.Context()
+	slog.WarnContext(ctx, "upload rejected by content sniffing",
+		"user_id", userID, "key", key, "reason", rejection.Reason, "detail", rejection.Detail)
+	imaging.RecordRejection(ctx, rejection.Reason, "finalize")
+
+	if err := h.store.DeleteObject(ctx, key); err != nil {
+		slog.WarnContext(ctx, "failed to delete rejected upload", "key", key, "error", err)
+	}
+	if h.uploads != nil {
+		record := &models.UploadRejection{
+			UserID:     &userID,
+			StorageKey: key,
+			Reason:     rejection.Reason,
+			Detail:     rejection.Detail,
+		}
+		if upload != nil {
+			record.UploadID = &upload.UploadID
+			record.DeclaredContentType = &upload.ContentType
+		}
+		if ip := c.ClientIP(); ip != "" {
+			record.IPAddress = &ip
+		}
+		if ua := c.Request.UserAgent(); ua != "" {
+			record.UserAgent = &ua
+		}
+		if err := h.uploads.Reject(ctx, record); err != nil {
+			slog.ErrorContext(ctx, "failed to record upload rejection", "key", key, "error", err)
+		}
+	}
 
-	c.JSON(http.StatusAccepted, gin.H{
-		"success": true,
-		"data": FinalizeResponse{
-			AssetID:                    jobID.String(),
-			Status:                     "processing",
-			EstimatedCompletionSeconds: 5,
-			StatusURL:                  fmt.Sprintf("/api/v1/assets/%s", jobID.String()),
-		},
+	c.AbortWithStatusJSON(http.StatusUnprocessableEntity, utils.Response{
+		Success: false,
+		Message: "the uploaded file is not a supported image",
+		Error:   gin.H{"code": "content_rejected", "reason": rejection.Reason},
 	})
 }
 
+// ListUploadRejections handles GET /api/v1/admin/imaging/rejections?user_id=
+// (requires imaging:admin), listing uploads rejected by content sniffing
+func (h *UploadHandler) ListUploadRejections(c *gin.Context) {
+	if h.uploads == nil {
+		utils.SendError(c, http.StatusServiceUnavailable, "upload tracking is not configured", nil)
+		return
+	}
+	var userID *uuid.UUID
+	if raw := c.Query("user_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "invalid user_id format", err)
+			return
+		}
+		userID = &id
+	}
+	page, limit := utils.GetPagination(c)
+	offset := utils.GetOffset(page, limit)
+
+	rejections, err := h.uploads.ListRejections(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendPaginated(c, "Upload rejections retrieved", rejections, page, limit, len(rejections)+offset)
+}
+
+// markFinalized records that upload was handed to the pipeline. A failure is
+// only logged: the expiry worker then deletes a key the pipeline has moved.
+func (h *UploadHandler) markFinalized(c *gin.Context, upload *models.Upload) {
+	if upload == nil {
+		return
+	}
+	if err := h.uploads.MarkFinalized(c.Request.Context(), upload.UploadID); err != nil {
+		slog.WarnContext(c.Request.Context(), "failed to mark upload finalized", "upload_id", upload.UploadID, "error", err)
+	}
+}
+
+// HeartbeatRequest reports how much of an upload the client has sent
+type HeartbeatRequest struct {
+	BytesUploaded int64 `json:"bytes_uploaded" binding:"min=0"`
+}
+
+// UploadHeartbeat handles POST /api/v1/uploads/:id/heartbeat. Clients report
+// progress while sending the file to storage; progress never moves backwards.
+func (h *UploadHandler) UploadHeartbeat(c *gin.Context) {
+	if h.uploads == nil {
+		utils.SendError(c, http.StatusServiceUnavailable, "upload tracking is not configured", nil)
+		return
+	}
+	ctx := c.Request.Context()
+
+	uploadID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid upload ID format", err)
+		return
+	}
+	var req HeartbeatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	upload, err := h.uploads.Get(ctx, uploadID, actor.UserID)
+	if errors.Is(err, repositories.ErrUploadNotFound) {
+		utils.SendError(c, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	if req.BytesUploaded > upload.ExpectedSizeBytes {
+		utils.SendError(c, http.StatusBadRequest, fmt.Sprintf("bytes_uploaded exceeds the declared size of %d bytes", upload.ExpectedSizeBytes), nil)
+		return
+	}
+
+	upload, err = h.uploads.RecordProgress(ctx, uploadID, req.BytesUploaded)
+	if errors.Is(err, repositories.ErrUploadNotFound) {
+		utils.SendError(c, http.StatusConflict, "upload is no longer open", nil)
+		return
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	utils.SendSuccess(c, "Upload progress recorded", upload)
+}
+
 // GetAssetStatus returns the processing status and derivatives of an asset
 func (h *UploadHandler) GetAssetStatus(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID format"})
+		utils.SendError(c, http.StatusBadRequest, "invalid ID format", err)
 		return
 	}
 
@@ -258,17 +616,17 @@ func (h *UploadHandler) GetAssetStatus(c *gin.Context) {
 	var exists bool
 
 	// 1. Try to find asset by ID
-	asset, exists = h.imagingService.GetAssetByID(id)
+	asset, exists = h.imagingService.GetAssetByID(c.Request.Context(), id)
 	slog.Debug("GetAssetStatus: asset lookup result", "id", id, "found_as_asset", exists)
 
 	// 2. If not found, try to find job by ID
 	if !exists {
-		job, exists = h.imagingService.GetJobByID(id)
+		job, exists = h.imagingService.GetJobByID(c.Request.Context(), id)
 		slog.Debug("GetAssetStatus: job lookup result", "id", id, "found_as_job", exists)
 		if exists && job.AssetID != nil {
 			// Job finished, check the linked asset
 			slog.Debug("GetAssetStatus: job has linked asset", "job_id", id, "asset_id", *job.AssetID)
-			asset, exists = h.imagingService.GetAssetByID(*job.AssetID)
+			asset, exists = h.imagingService.GetAssetByID(c.Request.Context(), *job.AssetID)
 		}
 	}
 
@@ -285,7 +643,7 @@ func (h *UploadHandler) GetAssetStatus(c *gin.Context) {
 			return
 		}
 		slog.Warn("GetAssetStatus: neither asset nor job found", "id", id)
-		c.JSON(http.StatusNotFound, gin.H{"error": "asset not found", "lookup_id": id.String()})
+		utils.SendError(c, http.StatusNotFound, "asset not found", nil)
 		return
 	}
 
@@ -296,6 +654,7 @@ func (h *UploadHandler) GetAssetStatus(c *gin.Context) {
 		Status:      string(asset.Status),
 		CreatedAt:   asset.CreatedAt.Format(time.RFC3339),
 		Error:       asset.Error,
+		Moderation:  string(asset.ModerationStatus),
 	}
 
 	if asset.ProcessedAt != nil {
@@ -336,14 +695,14 @@ func (h *UploadHandler) DeleteUpload(c *gin.Context) {
 	key := c.Query("key")
 
 	if key == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "key is required"})
+		utils.SendError(c, http.StatusBadRequest, "key is required", nil)
 		return
 	}
 
 	// Verify user owns this file (key starts with their user ID)
 	userIDVal, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
 	userID := userIDVal.(uuid.UUID)
@@ -351,16 +710,16 @@ func (h *UploadHandler) DeleteUpload(c *gin.Context) {
 	expectedPrefix := fmt.Sprintf("uploads/%s/", userID.String())
 	tmpPrefix := fmt.Sprintf("uploads/tmp/%s/", userID.String())
 	if !strings.HasPrefix(key, expectedPrefix) && !strings.HasPrefix(key, tmpPrefix) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "not authorized to delete this file"})
+		utils.SendError(c, http.StatusForbidden, "not authorized to delete this file", nil)
 		return
 	}
 
-	if err := h.r2.DeleteObject(ctx, key); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete file"})
+	if err := h.store.DeleteObject(ctx, key); err != nil {
+		utils.SendInternalError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "file deleted"})
+	utils.SendSuccess(c, "File deleted", gin.H{"key": key})
 }
 
 // ServeImage proxies image requests from R2
@@ -368,6 +727,11 @@ func (h *UploadHandler) ServeImage(c *gin.Context) {
 	hash := c.Param("hash")
 	rendition := c.Param("rendition")
 
+	if rendition == "original" {
+		h.serveOriginal(c, hash)
+		return
+	}
+
 	// Check Accept header
 	accept := c.GetHeader("Accept")
 	preferredFormat := ""
@@ -379,34 +743,55 @@ func (h *UploadHandler) ServeImage(c *gin.Context) {
 		}
 	}
 
-	key, _, err := h.imagingService.GetDerivativeKey(hash, rendition, preferredFormat)
+	key, _, err := h.imagingService.GetDerivativeKey(c.Request.Context(), hash, rendition, preferredFormat)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "image not found"})
+		if errors.Is(err, imaging.ErrAssetBlocked) {
+			c.Header("Cache-Control", "no-store")
+			utils.SendError(c, http.StatusNotFound, "image unavailable", nil)
+			return
+		}
+		utils.SendError(c, http.StatusNotFound, "image not found", nil)
 		return
 	}
 
-	// Always proxy for 'original' to avoid CORS issues when fetching for cropping/processing
-	// Or if explicitly requested via query param
-	if rendition == "original" || c.Query("proxy") == "true" {
+	// Proxy when explicitly requested via query param
+	if c.Query("proxy") == "true" {
+		// Derivatives are immutable, so a Range is honoured without If-Range checks.
+		// Unsupported ranges (multiple, malformed) get the whole image.
+		byteRange, _ := parseByteRange(c.GetHeader("Range"))
+
 		ctx := c.Request.Context()
-		stream, contentType, contentLength, err := h.r2.GetObjectStream(ctx, key)
-		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "image source not found"})
+		stream, err := h.store.GetObjectStream(ctx, key, byteRange)
+		if errors.Is(err, storage.ErrInvalidRange) {
+			c.Header("Accept-Ranges", "bytes")
+			utils.SendError(c, http.StatusRequestedRangeNotSatisfiable, "requested range not satisfiable", nil)
 			return
 		}
-		defer stream.Close()
+		if err != nil && !errors.Is(err, breaker.ErrOpen) {
+			utils.SendError(c, http.StatusNotFound, "image source not found", nil)
+			return
+		}
+		// While the R2 breaker is open, redirect instead: the public URL may still be served by the CDN
+		if err == nil {
+			defer stream.Body.Close()
 
-		// Add cache headers
-		c.Header("Cache-Control", "public, max-age=31536000, immutable")
-		if rendition != "original" {
+			// Add cache headers
+			c.Header("Cache-Control", "public, max-age=31536000, immutable")
 			c.Header("Vary", "Accept")
-		}
+			c.Header("Accept-Ranges", "bytes")
 
-		c.DataFromReader(http.StatusOK, contentLength, contentType, stream, nil)
-		return
+			status := http.StatusOK
+			var extraHeaders map[string]string
+			if stream.ContentRange != "" {
+				status = http.StatusPartialContent
+				extraHeaders = map[string]string{"Content-Range": stream.ContentRange}
+			}
+			c.DataFromReader(status, stream.ContentLength, stream.ContentType, stream.Body, extraHeaders)
+			return
+		}
 	}
 
-	publicURL := h.r2.GetPublicURL(key)
+	publicURL := h.store.GetPublicURL(key)
 
 	// Add cache headers
 	// Immutable cache for 1 year
@@ -417,6 +802,81 @@ func (h *UploadHandler) ServeImage(c *gin.Context) {
 	c.Redirect(http.StatusFound, publicURL)
 }
 
+// parseByteRange validates a Range header holding a single byte range
+// ("bytes=0-499", "bytes=500-" or "bytes=-500") and returns it normalized
+func parseByteRange(header string) (string, bool) {
+	spec, ok := strings.CutPrefix(strings.TrimSpace(header), "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return "", false
+	}
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok || (first == "" && last == "") {
+		return "", false
+	}
+	var start, end int64 = -1, -1
+	if first != "" {
+		n, err := strconv.ParseInt(first, 10, 64)
+		if err != nil || n < 0 {
+			return "", false
+		}
+		start = n
+	}
+	if last != "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return "", false
+		}
+		end = n
+	}
+	switch {
+	case start < 0:
+		if end == 0 {
+			return "", false
+		}
+		return fmt.Sprintf("bytes=-%d", end), true
+	case end < 0:
+		return fmt.Sprintf("bytes=%d-", start), true
+	case end < start:
+		return "", false
+	default:
+		return fmt.Sprintf("bytes=%d-%d", start, end), true
+	}
+}
+
+// serveOriginal redirects the asset owner or an imaging admin to a short-lived
+// signed URL of the original upload. Originals are never proxied or cached.
+func (h *UploadHandler) serveOriginal(c *gin.Context, hash string) {
+	c.Header("Cache-Control", "private, no-store")
+
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "sign in to access original images", nil)
+		return
+	}
+	asset, exists := h.imagingService.GetAsset(c.Request.Context(), hash)
+	if !exists {
+		utils.SendError(c, http.StatusNotFound, "image not found", nil)
+		return
+	}
+	if asset.CreatedByUserID != actor.UserID && !actor.Can(services.PermImagingAdmin) {
+		utils.SendError(c, http.StatusForbidden, "not authorized to access this original", nil)
+		return
+	}
+
+	key, _, err := h.imagingService.GetDerivativeKey(c.Request.Context(), hash, "original", "")
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "image not found", nil)
+		return
+	}
+	url, err := h.store.PresignGetURL(c.Request.Context(), key, h.originalURLTTL)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, url)
+}
+
 // ReprocessAsset triggers reprocessing of an existing asset with new crop data
 func (h *UploadHandler) ReprocessAsset(c *gin.Context) {
 	hash := c.Param("hash")
@@ -428,24 +888,22 @@ func (h *UploadHandler) ReprocessAsset(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDVal, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
-	userID := userIDVal.(uuid.UUID)
 
 	// 1. Get existing asset to verify ownership/existence
-	asset, exists := h.imagingService.GetAsset(hash)
+	asset, exists := h.imagingService.GetAsset(c.Request.Context(), hash)
 	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "asset not found"})
+		utils.SendError(c, http.StatusNotFound, "asset not found", nil)
 		return
 	}
 
-	// Verify ownership?
-	// The asset has CreatedByUserID.
-	if asset.CreatedByUserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "not authorized to reprocess this asset"})
+	// Owners may reprocess their own uploads; imaging:admin may reprocess any
+	if asset.CreatedByUserID != actor.UserID && !actor.Can(services.PermImagingAdmin) {
+		utils.SendError(c, http.StatusForbidden, "not authorized to reprocess this asset", nil)
 		return
 	}
 
@@ -462,20 +920,45 @@ func (h *UploadHandler) ReprocessAsset(c *gin.Context) {
 
 	// 3. Queue Reprocessing
 	// We use the same Category as the asset
-	jobID, err := h.imagingService.QueueReprocessing(originalKey, asset.Category, userID, req.CropData)
+	jobID, err := h.imagingService.QueueReprocessing(originalKey, asset.Category, actor.UserID, req.CropData)
 	if err != nil {
 		utils.SendInternalError(c, err)
 		return
 	}
 
 	// 4. Return success with status URL
-	c.JSON(http.StatusAccepted, gin.H{
-		"success": true,
-		"data": FinalizeResponse{
-			AssetID:                    jobID.String(),
-			Status:                     "processing",
-			EstimatedCompletionSeconds: 5,
-			StatusURL:                  fmt.Sprintf("/api/v1/assets/%s", jobID.String()),
-		},
+	utils.SendAccepted(c, "Processing queued", FinalizeResponse{
+		AssetID:                    jobID.String(),
+		Status:                     "processing",
+		EstimatedCompletionSeconds: 5,
+		StatusURL:                  fmt.Sprintf("/api/v1/assets/%s", jobID.String()),
 	})
 }
+
+// ScaleWorkersRequest sets the size of the imaging worker pool
+type ScaleWorkersRequest struct {
+	Workers int `json:"workers" binding:"required"`
+}
+
+// ScaleWorkers handles PUT /api/v1/admin/imaging/workers (requires imaging:admin)
+func (h *UploadHandler) ScaleWorkers(c *gin.Context) {
+	var req ScaleWorkersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+
+	if err := h.imagingService.SetWorkers(req.Workers); err != nil {
+		switch {
+		case errors.Is(err, imaging.ErrInvalidWorkerCount):
+			utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		case errors.Is(err, imaging.ErrServiceStopped):
+			utils.SendError(c, http.StatusServiceUnavailable, err.Error(), err)
+		default:
+			utils.SendInternalError(c, err)
+		}
+		return
+	}
+
+	utils.SendSuccess(c, "Imaging worker pool resized", h.imagingService.Status())
+}
@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"maukemana-backend/internal/imaging"
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/storage"
+	"maukemana-backend/internal/utils"
+)
+
+const (
+	// multipartPartSize is the size of every part but the last; 5 MiB is the
+	// smallest part S3-compatible stores accept
+	multipartPartSize = 5 << 20
+	// multipartUploadTTL is how long a multipart upload can be resumed
+	multipartUploadTTL = 24 * time.Hour
+	// multipartPartURLTTL is the lifetime of a presigned part URL
+	multipartPartURLTTL = 15 * time.Minute
+)
+
+// MultipartUploadStore persists multipart uploads so clients can resume them
+type MultipartUploadStore interface {
+	Create(ctx context.Context, u *models.MultipartUpload) (*models.MultipartUpload, error)
+	Get(ctx context.Context, uploadID, userID uuid.UUID) (*models.MultipartUpload, error)
+	Close(ctx context.Context, uploadID uuid.UUID, status string) error
+}
+
+// UseMultipartUploads enables multipart uploads
+func (h *UploadHandler) UseMultipartUploads(store MultipartUploadStore) {
+	h.multipart = store
+}
+
+// MultipartUploadResponse is a multipart upload with the parts stored so far
+type MultipartUploadResponse struct {
+	*models.MultipartUpload
+	UploadedParts []storage.UploadedPart `json:"uploaded_parts"`
+	MissingParts  []int                  `json:"missing_parts"` // Part numbers still to upload
+}
+
+// PartURLResponse is a presigned URL for one part of a multipart upload
+type PartURLResponse struct {
+	PartNumber      int    `json:"part_number"`
+	UploadURL       string `json:"upload_url"`
+	SizeBytes       int64  `json:"size_bytes"` // Exact length the PUT must send
+	UploadExpiresAt string `json:"upload_expires_at"`
+}
+
+// InitiateMultipartUpload handles POST /api/v1/uploads/multipart. The file is
+// split into part_count parts of part_size_bytes (the last one shorter), each
+// uploaded to its own presigned URL. After completing, the key is finalized
+// with POST /api/v1/uploads/finalize like a single PUT upload.
+func (h *UploadHandler) InitiateMultipartUpload(c *gin.Context) {
+	if h.multipart == nil {
+		utils.SendError(c, http.StatusServiceUnavailable, "multipart uploads are not configured", nil)
+		return
+	}
+	ctx := c.Request.Context()
+
+	var req PresignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	if !uploadContentTypes[req.ContentType] {
+		utils.SendError(c, http.StatusBadRequest, "invalid content type, allowed: "+strings.Join(allowedUploadTypes, ", "), nil)
+		return
+	}
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	category := req.Category
+	if category == "" {
+		category = "general"
+	}
+	limits := imaging.GetCategoryLimits(category)
+	if req.SizeBytes > limits.MaxBytes {
+		utils.SendError(c, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("file size %d exceeds maximum %d bytes for %s uploads", req.SizeBytes, limits.MaxBytes, category), nil)
+		return
+	}
+
+	key := uploadKey(actor.UserID, uuid.New(), category, req.Filename, req.ContentType)
+	storageUploadID, err := h.store.CreateMultipartUpload(ctx, key, req.ContentType)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	upload, err := h.multipart.Create(ctx, &models.MultipartUpload{
+		UserID:          actor.UserID,
+		StorageKey:      key,
+		StorageUploadID: storageUploadID,
+		Category:        category,
+		ContentType:     req.ContentType,
+		SizeBytes:       req.SizeBytes,
+		PartSizeBytes:   multipartPartSize,
+		PartCount:       int((req.SizeBytes + multipartPartSize - 1) / multipartPartSize),
+		ExpiresAt:       time.Now().Add(multipartUploadTTL),
+	})
+	if err != nil {
+		if abortErr := h.store.AbortMultipartUpload(ctx, key, storageUploadID); abortErr != nil {
+			c.Error(abortErr)
+		}
+		utils.SendInternalError(c, err)
+		return
+	}
+	if h.uploads != nil {
+		_, err := h.uploads.Create(ctx, &models.Upload{
+			UploadID:          upload.UploadID,
+			UserID:            actor.UserID,
+			StorageKey:        key,
+			Category:          category,
+			ContentType:       req.ContentType,
+			ExpectedSizeBytes: req.SizeBytes,
+			Multipart:         true,
+			ExpiresAt:         upload.ExpiresAt,
+		})
+		if err != nil {
+			if abortErr := h.store.AbortMultipartUpload(ctx, key, storageUploadID); abortErr != nil {
+				c.Error(abortErr)
+			}
+			if closeErr := h.multipart.Close(ctx, upload.UploadID, models.MultipartUploadAborted); closeErr != nil {
+				c.Error(closeErr)
+			}
+			utils.SendInternalError(c, err)
+			return
+		}
+	}
+
+	utils.SendCreated(c, "Multipart upload started", MultipartUploadResponse{
+		MultipartUpload: upload,
+		UploadedParts:   []storage.UploadedPart{},
+		MissingParts:    missingParts(upload, nil),
+	})
+}
+
+// GetMultipartUpload handles GET /api/v1/uploads/multipart/:upload_id. A
+// client resuming an upload gets the parts already stored and those missing.
+func (h *UploadHandler) GetMultipartUpload(c *gin.Context) {
+	upload, ok := h.loadMultipartUpload(c)
+	if !ok {
+		return
+	}
+
+	parts := []storage.UploadedPart{}
+	if upload.Status == models.MultipartUploadActive {
+		var err error
+		parts, err = h.store.ListUploadedParts(c.Request.Context(), upload.StorageKey, upload.StorageUploadID)
+		if errors.Is(err, storage.ErrNoSuchUpload) {
+			utils.SendError(c, http.StatusGone, "multipart upload expired", nil)
+			return
+		}
+		if err != nil {
+			utils.SendInternalError(c, err)
+			return
+		}
+	}
+
+	missing := []int{}
+	if upload.Status == models.MultipartUploadActive {
+		missing = missingParts(upload, parts)
+	}
+	utils.SendSuccess(c, "Multipart upload retrieved", MultipartUploadResponse{
+		MultipartUpload: upload,
+		UploadedParts:   parts,
+		MissingParts:    missing,
+	})
+}
+
+// PresignUploadPart handles POST /api/v1/uploads/multipart/:upload_id/parts/:part_number.
+// The URL only accepts a PUT of exactly the part's size. Parts can be
+// uploaded in any order and uploading one again replaces it.
+func (h *UploadHandler) PresignUploadPart(c *gin.Context) {
+	upload, ok := h.loadActiveMultipartUpload(c)
+	if !ok {
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("part_number"))
+	if err != nil || number < 1 || number > upload.PartCount {
+		utils.SendError(c, http.StatusBadRequest, fmt.Sprintf("part_number must be between 1 and %d", upload.PartCount), nil)
+		return
+	}
+
+	size := upload.PartSize(number)
+	url, err := h.store.PresignUploadPart(c.Request.Context(), upload.StorageKey, upload.StorageUploadID, int32(number), size, multipartPartURLTTL)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Presigned part URL generated", PartURLResponse{
+		PartNumber:      number,
+		UploadURL:       url,
+		SizeBytes:       size,
+		UploadExpiresAt: time.Now().Add(multipartPartURLTTL).Format(time.RFC3339),
+	})
+}
+
+// CompleteMultipartUpload handles POST /api/v1/uploads/multipart/:upload_id/complete.
+// Every part must be stored with its expected size; the parts are read from
+// storage, so clients do not need to collect ETags. The returned key is then
+// passed to POST /api/v1/uploads/finalize as upload_key.
+func (h *UploadHandler) CompleteMultipartUpload(c *gin.Context) {
+	upload, ok := h.loadActiveMultipartUpload(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+
+	parts, err := h.store.ListUploadedParts(ctx, upload.StorageKey, upload.StorageUploadID)
+	if errors.Is(err, storage.ErrNoSuchUpload) {
+		utils.SendError(c, http.StatusGone, "multipart upload expired", nil)
+		return
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	if missing := missingParts(upload, parts); len(missing) > 0 {
+		c.AbortWithStatusJSON(http.StatusConflict, utils.Response{
+			Success: false,
+			Message: "multipart upload is missing parts",
+			Error:   gin.H{"code": "parts_missing", "missing_parts": missing},
+		})
+		return
+	}
+
+	if err := h.store.CompleteMultipartUpload(ctx, upload.StorageKey, upload.StorageUploadID, parts); err != nil {
+		if errors.Is(err, storage.ErrNoSuchUpload) {
+			utils.SendError(c, http.StatusGone, "multipart upload expired", nil)
+			return
+		}
+		utils.SendInternalError(c, err)
+		return
+	}
+	if err := h.multipart.Close(ctx, upload.UploadID, models.MultipartUploadCompleted); err != nil && !errors.Is(err, repositories.ErrMultipartUploadNotFound) {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Multipart upload completed", gin.H{
+		"upload_id":  upload.UploadID,
+		"upload_key": upload.StorageKey,
+		"category":   upload.Category,
+	})
+}
+
+// AbortMultipartUpload handles DELETE /api/v1/uploads/multipart/:upload_id and
+// discards the parts stored so far
+func (h *UploadHandler) AbortMultipartUpload(c *gin.Context) {
+	upload, ok := h.loadMultipartUpload(c)
+	if !ok {
+		return
+	}
+	if upload.Status != models.MultipartUploadActive {
+		utils.SendError(c, http.StatusConflict, "multipart upload is already "+upload.Status, nil)
+		return
+	}
+	ctx := c.Request.Context()
+
+	if err := h.store.AbortMultipartUpload(ctx, upload.StorageKey, upload.StorageUploadID); err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+	if err := h.multipart.Close(ctx, upload.UploadID, models.MultipartUploadAborted); err != nil && !errors.Is(err, repositories.ErrMultipartUploadNotFound) {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Multipart upload aborted", gin.H{"upload_id": upload.UploadID})
+}
+
+// loadMultipartUpload loads the caller's multipart upload named by the
+// :upload_id parameter, writing the error response when it cannot
+func (h *UploadHandler) loadMultipartUpload(c *gin.Context) (*models.MultipartUpload, bool) {
+	if h.multipart == nil {
+		utils.SendError(c, http.StatusServiceUnavailable, "multipart uploads are not configured", nil)
+		return nil, false
+	}
+	uploadID, err := uuid.Parse(c.Param("upload_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid upload ID format", err)
+		return nil, false
+	}
+	actor, ok := actorFromContext(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return nil, false
+	}
+
+	upload, err := h.multipart.Get(c.Request.Context(), uploadID, actor.UserID)
+	if errors.Is(err, repositories.ErrMultipartUploadNotFound) {
+		utils.SendError(c, http.StatusNotFound, err.Error(), nil)
+		return nil, false
+	}
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return nil, false
+	}
+	return upload, true
+}
+
+// loadActiveMultipartUpload is loadMultipartUpload for uploads that can still
+// take parts
+func (h *UploadHandler) loadActiveMultipartUpload(c *gin.Context) (*models.MultipartUpload, bool) {
+	upload, ok := h.loadMultipartUpload(c)
+	if !ok {
+		return nil, false
+	}
+	if upload.Status != models.MultipartUploadActive {
+		utils.SendError(c, http.StatusConflict, "multipart upload is already "+upload.Status, nil)
+		return nil, false
+	}
+	if time.Now().After(upload.ExpiresAt) {
+		utils.SendError(c, http.StatusGone, "multipart upload expired", nil)
+		return nil, false
+	}
+	return upload, true
+}
+
+// missingParts returns the part numbers of upload not stored with their
+// expected size in parts
+func missingParts(upload *models.MultipartUpload, parts []storage.UploadedPart) []int {
+	stored := make(map[int]int64, len(parts))
+	for _, p := range parts {
+		stored[int(p.Number)] = p.Size
+	}
+	missing := []int{}
+	for n := 1; n <= upload.PartCount; n++ {
+		if size, ok := stored[n]; !ok || size != upload.PartSize(n) {
+			missing = append(missing, n)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// WebhookRepository defines the data access needed to manage webhook subscriptions
+type WebhookRepository interface {
+	List(ctx context.Context) ([]models.WebhookSubscription, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.WebhookSubscription, error)
+	Create(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error)
+	Update(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListDeliveries(ctx context.Context, id uuid.UUID, limit, offset int) ([]models.WebhookDelivery, error)
+}
+
+// WebhookHandler manages webhook subscriptions (routes require webhook:manage)
+type WebhookHandler struct {
+	repo WebhookRepository
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(repo WebhookRepository) *WebhookHandler {
+	return &WebhookHandler{repo: repo}
+}
+
+// WebhookRequest is the body for creating or replacing a webhook subscription
+type WebhookRequest struct {
+	URL          string   `json:"url" binding:"required,url"`
+	EventTypes   []string `json:"event_types"` // Empty subscribes to every event
+	Description  *string  `json:"description"`
+	IsActive     *bool    `json:"is_active"`
+	RotateSecret bool     `json:"rotate_secret"` // Update only
+}
+
+// validate checks the target URL and event filter
+func (req *WebhookRequest) validate() error {
+	u, err := url.Parse(req.URL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return errors.New("url must be an absolute https URL")
+	}
+	for _, t := range req.EventTypes {
+		if !slices.Contains(models.EventTypes, t) {
+			return fmt.Errorf("unknown event type %q", t)
+		}
+	}
+	return nil
+}
+
+// ListWebhooks handles GET /api/v1/admin/webhooks
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	subs, err := h.repo.List(c.Request.Context())
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Webhooks retrieved", gin.H{"webhooks": subs, "event_types": models.EventTypes})
+}
+
+// CreateWebhook handles POST /api/v1/admin/webhooks. The signing secret is only
+// returned in this response (and when rotated).
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var input WebhookRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	if err := input.validate(); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	actor, _ := actorFromContext(c)
+	created, err := h.repo.Create(c.Request.Context(), &models.WebhookSubscription{
+		URL:         input.URL,
+		Secret:      secret,
+		EventTypes:  pq.StringArray(input.EventTypes),
+		Description: input.Description,
+		IsActive:    input.IsActive == nil || *input.IsActive,
+		CreatedBy:   &actor.UserID,
+	})
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendCreated(c, "Webhook created", gin.H{"webhook": created, "secret": secret})
+}
+
+// UpdateWebhook handles PUT /api/v1/admin/webhooks/:id
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	var input WebhookRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.SendValidationError(c, err)
+		return
+	}
+	if err := input.validate(); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	var secret string
+	if input.RotateSecret {
+		var err error
+		if secret, err = newWebhookSecret(); err != nil {
+			utils.SendInternalError(c, err)
+			return
+		}
+	}
+
+	updated, err := h.repo.Update(c.Request.Context(), &models.WebhookSubscription{
+		SubscriptionID: id,
+		URL:            input.URL,
+		Secret:         secret,
+		EventTypes:     pq.StringArray(input.EventTypes),
+		Description:    input.Description,
+		IsActive:       input.IsActive == nil || *input.IsActive,
+	})
+	if err != nil {
+		sendWebhookError(c, err)
+		return
+	}
+
+	resp := gin.H{"webhook": updated}
+	if secret != "" {
+		resp["secret"] = secret
+	}
+	utils.SendSuccess(c, "Webhook updated", resp)
+}
+
+// DeleteWebhook handles DELETE /api/v1/admin/webhooks/:id
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Delete(c.Request.Context(), id); err != nil {
+		sendWebhookError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, "Webhook deleted", gin.H{"subscription_id": id})
+}
+
+// ListDeliveries handles GET /api/v1/admin/webhooks/:id/deliveries
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := h.repo.GetByID(ctx, id); err != nil {
+		sendWebhookError(c, err)
+		return
+	}
+
+	page, limit := utils.GetPagination(c)
+	offset := utils.GetOffset(page, limit)
+	deliveries, err := h.repo.ListDeliveries(ctx, id, limit, offset)
+	if err != nil {
+		utils.SendInternalError(c, err)
+		return
+	}
+
+	utils.SendPaginated(c, "Webhook deliveries retrieved", deliveries, page, limit, len(deliveries)+offset)
+}
+
+func parseWebhookID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "invalid webhook ID format", err)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// newWebhookSecret generates a random signing secret
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// sendWebhookError maps webhook repository errors to HTTP responses
+func sendWebhookError(c *gin.Context, err error) {
+	if errors.Is(err, repositories.ErrWebhookNotFound) {
+		utils.SendError(c, http.StatusNotFound, "webhook not found", err)
+		return
+	}
+	utils.SendInternalError(c, err)
+}
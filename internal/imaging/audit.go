@@ -0,0 +1,163 @@
+package imaging
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"maukemana-backend/internal/storage"
+)
+
+// auditPrefixes are the storage prefixes holding asset objects. Temporary
+// uploads and other objects are outside the audit.
+var auditPrefixes = []string{"originals/", "derivatives/", "quarantine/"}
+
+// AuditStore lists the assets a storage audit checks, with their derivatives
+type AuditStore interface {
+	ListAuditAssets(ctx context.Context) ([]ImageAsset, error)
+}
+
+// AuditObjectStore lists and deletes stored objects
+type AuditObjectStore interface {
+	ListObjects(ctx context.Context, prefix string, fn func(storage.ObjectInfo) error) error
+	DeleteObject(ctx context.Context, key string) error
+}
+
+// AuditOptions select the repairs a storage audit makes
+type AuditOptions struct {
+	// Requeue reprocesses ready assets with missing derivatives whose original
+	// is still stored; nil only reports them
+	Requeue *Service
+	// DeleteOrphans deletes objects no asset or derivative refers to
+	DeleteOrphans bool
+	// OrphanMinAge leaves out objects modified more recently, which may belong
+	// to an upload still being processed
+	OrphanMinAge time.Duration
+}
+
+// MissingObject is an object the database refers to that is not in storage
+type MissingObject struct {
+	AssetID     uuid.UUID `json:"asset_id"`
+	ContentHash string    `json:"content_hash"`
+	Key         string    `json:"key"`
+	Kind        string    `json:"kind"`                // "original" or "derivative"
+	Rendition   string    `json:"rendition,omitempty"` // Rendition name of a missing derivative
+}
+
+// AuditReport is the outcome of a storage audit
+type AuditReport struct {
+	StartedAt      time.Time            `json:"started_at"`
+	FinishedAt     time.Time            `json:"finished_at"`
+	ObjectsScanned int                  `json:"objects_scanned"`
+	AssetsChecked  int                  `json:"assets_checked"`
+	Missing        []MissingObject      `json:"missing"`
+	Orphans        []storage.ObjectInfo `json:"orphans"`
+	Requeued       []uuid.UUID          `json:"requeued"`
+	Deleted        []string             `json:"deleted"`
+	Errors         []string             `json:"errors"`
+}
+
+// Audit compares the image assets and derivatives in the database with the
+// objects in storage. Ready assets must have their original (or quarantined
+// original) and every derivative stored; stored objects under the asset
+// prefixes that no asset refers to are orphans. Repairs are made as opts
+// allow; repair failures are listed in the report rather than returned.
+func Audit(ctx context.Context, store AuditStore, objects AuditObjectStore, opts AuditOptions) (*AuditReport, error) {
+	report := &AuditReport{
+		StartedAt: time.Now().UTC(),
+		Missing:   []MissingObject{},
+		Orphans:   []storage.ObjectInfo{},
+		Requeued:  []uuid.UUID{},
+		Deleted:   []string{},
+		Errors:    []string{},
+	}
+
+	stored := make(map[string]storage.ObjectInfo)
+	for _, prefix := range auditPrefixes {
+		err := objects.ListObjects(ctx, prefix, func(o storage.ObjectInfo) error {
+			stored[o.Key] = o
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	report.ObjectsScanned = len(stored)
+
+	assets, err := store.ListAuditAssets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.AssetsChecked = len(assets)
+
+	known := make(map[string]bool, len(assets)*4)
+	var requeue []ImageAsset
+	for _, a := range assets {
+		original, quarantined := OriginalKey(a.ContentHash), QuarantineKey(a.ContentHash)
+		known[original], known[quarantined] = true, true
+		for _, d := range a.Derivatives {
+			known[d.StorageKey] = true
+		}
+		if a.Status != StatusReady {
+			continue
+		}
+
+		if a.ModerationStatus == ModerationQuarantined {
+			original = quarantined
+		}
+		_, hasOriginal := stored[original]
+		if !hasOriginal {
+			report.Missing = append(report.Missing, MissingObject{AssetID: a.ID, ContentHash: a.ContentHash, Key: original, Kind: "original"})
+		}
+		missingDerivative := false
+		for _, d := range a.Derivatives {
+			if _, ok := stored[d.StorageKey]; !ok {
+				missingDerivative = true
+				report.Missing = append(report.Missing, MissingObject{
+					AssetID: a.ID, ContentHash: a.ContentHash, Key: d.StorageKey, Kind: "derivative", Rendition: d.RenditionName,
+				})
+			}
+		}
+		// Derivatives are rebuilt from the original; without it there is nothing to requeue
+		if missingDerivative && hasOriginal && a.ModerationStatus != ModerationQuarantined {
+			requeue = append(requeue, a)
+		}
+	}
+
+	cutoff := report.StartedAt.Add(-opts.OrphanMinAge)
+	for key, o := range stored {
+		if !known[key] && o.LastModified.Before(cutoff) {
+			report.Orphans = append(report.Orphans, o)
+		}
+	}
+	sort.Slice(report.Orphans, func(i, j int) bool { return report.Orphans[i].Key < report.Orphans[j].Key })
+
+	if opts.Requeue != nil {
+		for _, a := range requeue {
+			if _, err := opts.Requeue.QueueReprocessing(OriginalKey(a.ContentHash), a.Category, a.CreatedByUserID, nil); err != nil {
+				report.Errors = append(report.Errors, "requeue asset "+a.ID.String()+": "+err.Error())
+				continue
+			}
+			report.Requeued = append(report.Requeued, a.ID)
+		}
+	}
+	if opts.DeleteOrphans {
+		for _, o := range report.Orphans {
+			if err := objects.DeleteObject(ctx, o.Key); err != nil {
+				report.Errors = append(report.Errors, "delete "+o.Key+": "+err.Error())
+				continue
+			}
+			report.Deleted = append(report.Deleted, o.Key)
+		}
+	}
+
+	report.FinishedAt = time.Now().UTC()
+	slog.Info("storage audit finished",
+		"objects", report.ObjectsScanned, "assets", report.AssetsChecked,
+		"missing", len(report.Missing), "orphans", len(report.Orphans),
+		"requeued", len(report.Requeued), "deleted", len(report.Deleted))
+	return report, nil
+}
@@ -0,0 +1,68 @@
+package imaging
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/davidbyttow/govips/v2/vips"
+)
+
+// ErrFormatUnsupported is returned for uploads in a format the linked
+// libvips cannot decode
+var ErrFormatUnsupported = errors.New("image format is not supported by this server")
+
+// optionalDecoders are the upload formats whose libvips loaders depend on
+// optional libraries (libheif with HEVC and AV1 decoders)
+var optionalDecoders = map[string]vips.ImageType{
+	"heic": vips.ImageTypeHEIF,
+	"avif": vips.ImageTypeAVIF,
+}
+
+var (
+	decodersOnce sync.Once
+	decoders     map[string]bool
+)
+
+// Decoders reports, for each format with an optional loader, whether libvips
+// has it. The probe runs once; a missing loader is logged at startup.
+func Decoders() map[string]bool {
+	decodersOnce.Do(func() {
+		decoders = make(map[string]bool, len(optionalDecoders))
+		for format, imageType := range optionalDecoders {
+			decoders[format] = vips.IsTypeSupported(imageType)
+			if !decoders[format] {
+				slog.Warn("libvips has no loader for an allowed upload format; such uploads are rejected", "format", format)
+			}
+		}
+	})
+	return decoders
+}
+
+// CanDecode reports whether uploads in format (as returned by DetectFormat)
+// can be processed
+func CanDecode(format string) bool {
+	if _, optional := optionalDecoders[format]; !optional {
+		return AllowedFormats[format]
+	}
+	return Decoders()[format]
+}
+
+// verifyDecode decodes the pixels of an image whose header already loaded.
+// HEIF loaders read headers without the codec, so a libheif built without
+// the HEVC or AV1 decoder only fails here.
+func verifyDecode(img *vips.ImageRef, format string) error {
+	probe, err := img.Copy()
+	if err != nil {
+		return err
+	}
+	defer probe.Close()
+	if err := probe.Thumbnail(32, 32, vips.InterestingNone); err != nil {
+		return fmt.Errorf("%w: cannot decode %s: %v", ErrFormatUnsupported, format, err)
+	}
+	if _, _, err := probe.ExportJpeg(vips.NewJpegExportParams()); err != nil {
+		return fmt.Errorf("%w: cannot decode %s: %v", ErrFormatUnsupported, format, err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package imaging
+
+import (
+	"context"
+	"sync"
+)
+
+// hashLocks serializes the processing of jobs with the same content hash, so
+// concurrent uploads of one image render it once and the others link to the
+// finished asset. Locks are per process; the unique content_hash of
+// image_assets still guards against a second instance creating the asset.
+type hashLocks struct {
+	mu    sync.Mutex
+	locks map[string]*hashLock
+}
+
+type hashLock struct {
+	ch   chan struct{} // Holds a token while locked
+	refs int           // Holders and waiters; the entry is dropped at zero
+}
+
+// lock waits until no other job holds hash, or ctx is done
+func (l *hashLocks) lock(ctx context.Context, hash string) (unlock func(), err error) {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*hashLock)
+	}
+	hl, ok := l.locks[hash]
+	if !ok {
+		hl = &hashLock{ch: make(chan struct{}, 1)}
+		l.locks[hash] = hl
+	}
+	hl.refs++
+	l.mu.Unlock()
+
+	select {
+	case hl.ch <- struct{}{}:
+		return func() {
+			<-hl.ch
+			l.release(hash, hl)
+		}, nil
+	case <-ctx.Done():
+		l.release(hash, hl)
+		return nil, ctx.Err()
+	}
+}
+
+func (l *hashLocks) release(hash string, hl *hashLock) {
+	l.mu.Lock()
+	hl.refs--
+	if hl.refs == 0 {
+		delete(l.locks, hash)
+	}
+	l.mu.Unlock()
+}
@@ -0,0 +1,173 @@
+package imaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LadderCategories are the categories with a rendition ladder of their own.
+// Assets of any other category are rendered with the general ladder.
+var LadderCategories = []string{"profile", "cover", "gallery", "general"}
+
+// DefaultLadderVersion is the version of the compiled-in ladders
+const DefaultLadderVersion = 1
+
+// Limits for ladder overrides
+const (
+	MaxLadderRenditions = 12
+	MaxRenditionSize    = 4096
+)
+
+// ErrInvalidLadder is returned for a rendition ladder failing validation
+var ErrInvalidLadder = errors.New("invalid rendition ladder")
+
+var renditionNamePattern = regexp.MustCompile(`^[a-z0-9]+_[a-z0-9_]+$`)
+
+// Ladder is the rendition ladder a category is rendered with. The version goes
+// up with every change and assets record the version their derivatives were
+// rendered with, so reprocess runs can pick out the outdated ones.
+type Ladder struct {
+	Category   string            `json:"category"`
+	Version    int               `json:"version"`
+	Renditions []RenditionConfig `json:"renditions"`
+	Override   bool              `json:"override"` // False when the compiled-in ladder is used
+	UpdatedBy  *uuid.UUID        `json:"updated_by,omitempty"`
+	UpdatedAt  *time.Time        `json:"updated_at,omitempty"`
+}
+
+// LadderCategory returns the ladder category assets of category are rendered with
+func LadderCategory(category string) string {
+	if slices.Contains(LadderCategories, category) {
+		return category
+	}
+	return "general"
+}
+
+// ValidateLadder checks a ladder override for category. Rendition names must
+// keep the category's prefix, which is how derivatives of one ladder are told
+// apart from those of another on the same asset.
+func ValidateLadder(category string, renditions []RenditionConfig) error {
+	if !slices.Contains(LadderCategories, category) {
+		return fmt.Errorf("%w: unknown category %q", ErrInvalidLadder, category)
+	}
+	if len(renditions) == 0 || len(renditions) > MaxLadderRenditions {
+		return fmt.Errorf("%w: between 1 and %d renditions required", ErrInvalidLadder, MaxLadderRenditions)
+	}
+
+	prefix := RenditionPrefix(category)
+	seen := make(map[string]bool, len(renditions))
+	for _, r := range renditions {
+		switch {
+		case !renditionNamePattern.MatchString(r.Name) || !strings.HasPrefix(r.Name, prefix):
+			return fmt.Errorf("%w: rendition %q must be lowercase and start with %q", ErrInvalidLadder, r.Name, prefix)
+		case seen[r.Name]:
+			return fmt.Errorf("%w: duplicate rendition %q", ErrInvalidLadder, r.Name)
+		case r.Width < 1 || r.Width > MaxRenditionSize:
+			return fmt.Errorf("%w: %s: width must be between 1 and %d", ErrInvalidLadder, r.Name, MaxRenditionSize)
+		case r.Height < 0 || r.Height > MaxRenditionSize:
+			return fmt.Errorf("%w: %s: height must be between 0 and %d", ErrInvalidLadder, r.Name, MaxRenditionSize)
+		}
+		seen[r.Name] = true
+
+		switch r.CropMode {
+		case CropCenterSquare, CropFitWidth:
+		case CropCenter16x9, CropNone:
+			if r.Height == 0 {
+				return fmt.Errorf("%w: %s: crop mode %q needs a height", ErrInvalidLadder, r.Name, r.CropMode)
+			}
+		default:
+			return fmt.Errorf("%w: %s: unknown crop mode %q", ErrInvalidLadder, r.Name, r.CropMode)
+		}
+
+		switch r.Quality {
+		case QualityHigh, QualityMedium, QualityLow:
+		default:
+			return fmt.Errorf("%w: %s: unknown quality %q", ErrInvalidLadder, r.Name, r.Quality)
+		}
+	}
+	return nil
+}
+
+// LadderStore loads the stored ladders. A stored ladder without renditions
+// was reverted to the compiled-in one and only carries its version on.
+type LadderStore interface {
+	ListRenditionLadders(ctx context.Context) ([]Ladder, error)
+}
+
+// Ladders holds the ladder of every category: the compiled-in one unless an
+// override is stored. Stored ladders are validated when loaded; an invalid one
+// is logged and its category keeps the ladder it had.
+type Ladders struct {
+	store LadderStore
+
+	mu         sync.RWMutex
+	byCategory map[string]Ladder
+}
+
+// NewLadders creates ladders loading overrides from store, which may be nil to
+// only use the compiled-in ones
+func NewLadders(store LadderStore) *Ladders {
+	l := &Ladders{store: store, byCategory: make(map[string]Ladder, len(LadderCategories))}
+	for _, c := range LadderCategories {
+		l.byCategory[c] = Ladder{Category: c, Version: DefaultLadderVersion, Renditions: GetRenditionsForCategory(c)}
+	}
+	return l
+}
+
+// Get returns the ladder assets of category are rendered with
+func (l *Ladders) Get(category string) Ladder {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.byCategory[LadderCategory(category)]
+}
+
+// All returns the ladder of every category
+func (l *Ladders) All() []Ladder {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	all := make([]Ladder, 0, len(LadderCategories))
+	for _, c := range LadderCategories {
+		all = append(all, l.byCategory[c])
+	}
+	return all
+}
+
+// Load reads the stored ladders, replacing those of their categories
+func (l *Ladders) Load(ctx context.Context) error {
+	if l.store == nil {
+		return nil
+	}
+	stored, err := l.store.ListRenditionLadders(ctx)
+	if err != nil {
+		return fmt.Errorf("load rendition ladders: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, s := range stored {
+		if !slices.Contains(LadderCategories, s.Category) {
+			slog.Warn("ignoring rendition ladder of unknown category", "category", s.Category)
+			continue
+		}
+		if s.Renditions == nil {
+			s.Renditions = GetRenditionsForCategory(s.Category)
+		} else if err := ValidateLadder(s.Category, s.Renditions); err != nil {
+			slog.Error("ignoring invalid rendition ladder", "category", s.Category, "version", s.Version, "error", err)
+			continue
+		}
+		if l.byCategory[s.Category].Version != s.Version {
+			slog.Info("rendition ladder loaded", "category", s.Category, "version", s.Version, "override", s.Override)
+		}
+		l.byCategory[s.Category] = s
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package imaging
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Default lifetimes of cached asset lookups
+const (
+	DefaultLookupTTL         = time.Minute
+	DefaultLookupNegativeTTL = 10 * time.Second
+	lookupCacheSize          = 10000
+)
+
+// assetLookups caches the asset lookups behind /img requests. Servable
+// (ready) assets are kept for ttl; missing and not-yet-ready ones for the
+// shorter negativeTTL so an asset becoming ready shows up quickly. Concurrent
+// misses for one hash share a single database query. Changes made by this
+// process invalidate the hash; other instances see them after the TTL.
+type assetLookups struct {
+	ttl         time.Duration
+	negativeTTL time.Duration
+
+	group   singleflight.Group
+	mu      sync.RWMutex
+	entries map[string]cachedLookup // keyed by content hash
+}
+
+type cachedLookup struct {
+	asset     *ImageAsset // nil when the asset does not exist
+	expiresAt time.Time
+}
+
+func newAssetLookups(ttl, negativeTTL time.Duration) *assetLookups {
+	return &assetLookups{ttl: ttl, negativeTTL: negativeTTL, entries: make(map[string]cachedLookup)}
+}
+
+// get returns the cached asset for hash or loads it. Load errors are not cached.
+func (c *assetLookups) get(ctx context.Context, hash string, load func(context.Context, string) (*ImageAsset, error)) (*ImageAsset, error) {
+	now := time.Now()
+	c.mu.RLock()
+	entry, ok := c.entries[hash]
+	c.mu.RUnlock()
+	if ok && now.Before(entry.expiresAt) {
+		if entry.asset == nil {
+			metrics.recordLookup(ctx, "negative_hit")
+		} else {
+			metrics.recordLookup(ctx, "hit")
+		}
+		return entry.asset, nil
+	}
+
+	v, err, shared := c.group.Do(hash, func() (interface{}, error) {
+		// The load is shared with other callers, so one of them going away must not cancel it
+		asset, err := load(context.WithoutCancel(ctx), hash)
+		if err != nil {
+			return nil, err
+		}
+		c.store(hash, asset)
+		return asset, nil
+	})
+	if shared {
+		metrics.recordLookup(ctx, "shared")
+	} else {
+		metrics.recordLookup(ctx, "miss")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v.(*ImageAsset), nil
+}
+
+func (c *assetLookups) store(hash string, asset *ImageAsset) {
+	ttl := c.ttl
+	if asset == nil || asset.Status != StatusReady {
+		ttl = c.negativeTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+	if asset != nil && asset.Status != StatusReady {
+		asset = nil // Served as not found until it is ready
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= lookupCacheSize {
+		for k, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < lookupCacheSize {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[hash] = cachedLookup{asset: asset, expiresAt: now.Add(ttl)}
+}
+
+// invalidate drops hash so the next lookup reads the database
+func (c *assetLookups) invalidate(hash string) {
+	c.mu.Lock()
+	delete(c.entries, hash)
+	c.mu.Unlock()
+	c.group.Forget(hash)
+}
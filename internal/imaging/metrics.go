@@ -0,0 +1,171 @@
+package imaging
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "maukemana-backend/internal/imaging"
+
+// tracer is used for spans around the processing pipeline stages
+var tracer trace.Tracer = otel.Tracer(instrumentationName)
+
+// pipelineMetrics holds the OTel instruments for the imaging pipeline.
+// Instruments are created from the global MeterProvider, so they are no-ops
+// until observability.InitOTel installs a real one.
+type pipelineMetrics struct {
+	jobs              metric.Int64Counter
+	failures          metric.Int64Counter
+	dedupHits         metric.Int64Counter
+	lookups           metric.Int64Counter
+	uploadBytes       metric.Int64Counter
+	rejections        metric.Int64Counter
+	jobDuration       metric.Float64Histogram
+	renditionDuration metric.Float64Histogram
+}
+
+var metrics = newPipelineMetrics()
+
+func newPipelineMetrics() *pipelineMetrics {
+	meter := otel.Meter(instrumentationName)
+	m := &pipelineMetrics{}
+	var err error
+
+	if m.jobs, err = meter.Int64Counter("imaging.jobs",
+		metric.WithDescription("Processing jobs handled by the worker pool"),
+		metric.WithUnit("{job}")); err != nil {
+		slog.Warn("failed to create imaging metric", "name", "imaging.jobs", "error", err)
+	}
+	if m.failures, err = meter.Int64Counter("imaging.failures",
+		metric.WithDescription("Processing failures by pipeline stage"),
+		metric.WithUnit("{failure}")); err != nil {
+		slog.Warn("failed to create imaging metric", "name", "imaging.failures", "error", err)
+	}
+	if m.dedupHits, err = meter.Int64Counter("imaging.dedup.hits",
+		metric.WithDescription("Uploads resolved to an existing asset by content hash"),
+		metric.WithUnit("{upload}")); err != nil {
+		slog.Warn("failed to create imaging metric", "name", "imaging.dedup.hits", "error", err)
+	}
+	if m.lookups, err = meter.Int64Counter("imaging.lookup_cache.lookups",
+		metric.WithDescription("Image asset lookups by cache result"),
+		metric.WithUnit("{lookup}")); err != nil {
+		slog.Warn("failed to create imaging metric", "name", "imaging.lookup_cache.lookups", "error", err)
+	}
+	if m.uploadBytes, err = meter.Int64Counter("imaging.upload.bytes",
+		metric.WithDescription("Bytes read from uploads and written as derivatives"),
+		metric.WithUnit("By")); err != nil {
+		slog.Warn("failed to create imaging metric", "name", "imaging.upload.bytes", "error", err)
+	}
+	if m.rejections, err = meter.Int64Counter("imaging.upload.rejections",
+		metric.WithDescription("Uploads rejected by content sniffing, by reason and stage"),
+		metric.WithUnit("{upload}")); err != nil {
+		slog.Warn("failed to create imaging metric", "name", "imaging.upload.rejections", "error", err)
+	}
+	if m.jobDuration, err = meter.Float64Histogram("imaging.job.duration",
+		metric.WithDescription("End-to-end processing time per job"),
+		metric.WithUnit("s")); err != nil {
+		slog.Warn("failed to create imaging metric", "name", "imaging.job.duration", "error", err)
+	}
+	if m.renditionDuration, err = meter.Float64Histogram("imaging.rendition.duration",
+		metric.WithDescription("Resize and encode time per rendition and format"),
+		metric.WithUnit("s")); err != nil {
+		slog.Warn("failed to create imaging metric", "name", "imaging.rendition.duration", "error", err)
+	}
+
+	return m
+}
+
+// registerQueueDepth reports the number of jobs waiting in the service queue
+func (s *Service) registerQueueDepth() {
+	meter := otel.Meter(instrumentationName)
+	_, err := meter.Int64ObservableGauge("imaging.queue.depth",
+		metric.WithDescription("Jobs waiting in the in-memory processing queue"),
+		metric.WithUnit("{job}"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(int64(len(s.jobQueue)))
+			return nil
+		}),
+	)
+	if err != nil {
+		slog.Warn("failed to register imaging queue depth gauge", "error", err)
+	}
+}
+
+// recordFailure increments the failure counter for a pipeline stage
+func (m *pipelineMetrics) recordFailure(ctx context.Context, stage string) {
+	if m.failures != nil {
+		m.failures.Add(ctx, 1, metric.WithAttributes(attribute.String("stage", stage)))
+	}
+}
+
+// recordBytes adds to the upload bytes counter for the given kind (original, derivative)
+func (m *pipelineMetrics) recordBytes(ctx context.Context, kind string, n int) {
+	if m.uploadBytes != nil {
+		m.uploadBytes.Add(ctx, int64(n), metric.WithAttributes(attribute.String("kind", kind)))
+	}
+}
+
+// RecordRejection counts an upload rejected by content sniffing at stage
+// (finalize, validate)
+func RecordRejection(ctx context.Context, reason, stage string) {
+	if metrics.rejections != nil {
+		metrics.rejections.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", reason), attribute.String("stage", stage)))
+	}
+}
+
+// startStage starts a child span for a processJob pipeline stage
+func startStage(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "imaging."+name, trace.WithAttributes(attribute.String("imaging.stage", name)))
+}
+
+// endStage ends a stage span, recording err on the span and in the failure counter
+func endStage(ctx context.Context, span trace.Span, name string, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		metrics.recordFailure(ctx, name)
+	}
+	span.End()
+}
+
+// recordJob records the outcome and duration of a processed job
+func (m *pipelineMetrics) recordJob(ctx context.Context, category, outcome string, seconds float64) {
+	attrs := metric.WithAttributes(attribute.String("category", category), attribute.String("outcome", outcome))
+	if m.jobs != nil {
+		m.jobs.Add(ctx, 1, attrs)
+	}
+	if m.jobDuration != nil {
+		m.jobDuration.Record(ctx, seconds, attrs)
+	}
+}
+
+// recordRendition records the encode time for a single rendition format
+func (m *pipelineMetrics) recordRendition(ctx context.Context, rendition, format string, seconds float64) {
+	if m.renditionDuration != nil {
+		m.renditionDuration.Record(ctx, seconds, metric.WithAttributes(
+			attribute.String("rendition", rendition),
+			attribute.String("format", format),
+		))
+	}
+}
+
+// recordDedupHit increments the dedup counter
+func (m *pipelineMetrics) recordDedupHit(ctx context.Context, category string) {
+	if m.dedupHits != nil {
+		m.dedupHits.Add(ctx, 1, metric.WithAttributes(attribute.String("category", category)))
+	}
+}
+
+// recordLookup increments the lookup cache counter for a result (hit,
+// negative_hit, miss, shared)
+func (m *pipelineMetrics) recordLookup(ctx context.Context, result string) {
+	if m.lookups != nil {
+		m.lookups.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+	}
+}
@@ -0,0 +1,339 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// ModerationStatus represents the content moderation state of an asset
+type ModerationStatus string
+
+const (
+	ModerationUnchecked   ModerationStatus = "unchecked"   // No moderator configured or not yet checked
+	ModerationApproved    ModerationStatus = "approved"    // Checked and considered safe
+	ModerationFlagged     ModerationStatus = "flagged"     // Processed, but must not be served
+	ModerationQuarantined ModerationStatus = "quarantined" // Not processed, original moved to quarantine
+)
+
+// Blocked reports whether assets with this status must not be served publicly
+func (m ModerationStatus) Blocked() bool {
+	return m == ModerationFlagged || m == ModerationQuarantined
+}
+
+// ModerationResult is the provider-agnostic outcome of a moderation check
+type ModerationResult struct {
+	Score  float64  // 0.0 to 1.0 confidence that the image is unsafe
+	Labels []string // Provider labels that contributed to the score
+}
+
+// Moderator checks image content for NSFW material
+type Moderator interface {
+	Name() string
+	Moderate(ctx context.Context, data []byte) (*ModerationResult, error)
+}
+
+// ModerationPolicy maps a moderation score to a status
+type ModerationPolicy struct {
+	FlagThreshold       float64
+	QuarantineThreshold float64
+}
+
+// DefaultModerationPolicy flags at 60% confidence and quarantines at 90%
+var DefaultModerationPolicy = ModerationPolicy{
+	FlagThreshold:       0.6,
+	QuarantineThreshold: 0.9,
+}
+
+// Evaluate returns the moderation status for a result
+func (p ModerationPolicy) Evaluate(result *ModerationResult) ModerationStatus {
+	if result == nil {
+		return ModerationUnchecked
+	}
+	switch {
+	case result.Score >= p.QuarantineThreshold:
+		return ModerationQuarantined
+	case result.Score >= p.FlagThreshold:
+		return ModerationFlagged
+	default:
+		return ModerationApproved
+	}
+}
+
+// NewModeratorFromEnv builds the moderator selected by IMAGE_MODERATION_PROVIDER.
+// Returns nil (moderation disabled) when the provider is empty or "none".
+func NewModeratorFromEnv() (Moderator, ModerationPolicy, error) {
+	policy := DefaultModerationPolicy
+	if v, err := strconv.ParseFloat(os.Getenv("IMAGE_MODERATION_FLAG_THRESHOLD"), 64); err == nil {
+		policy.FlagThreshold = v
+	}
+	if v, err := strconv.ParseFloat(os.Getenv("IMAGE_MODERATION_QUARANTINE_THRESHOLD"), 64); err == nil {
+		policy.QuarantineThreshold = v
+	}
+
+	provider := strings.ToLower(os.Getenv("IMAGE_MODERATION_PROVIDER"))
+	switch provider {
+	case "", "none":
+		return nil, policy, nil
+	case "rekognition":
+		m, err := NewRekognitionModerator()
+		return m, policy, err
+	case "cloudflare":
+		m, err := NewCloudflareModerator()
+		return m, policy, err
+	case "local":
+		m, err := NewLocalNSFWModerator(policy)
+		return m, policy, err
+	default:
+		return nil, policy, fmt.Errorf("unknown moderation provider %q", provider)
+	}
+}
+
+// RekognitionModerator uses AWS Rekognition DetectModerationLabels
+type RekognitionModerator struct {
+	region        string
+	creds         aws.CredentialsProvider
+	signer        *v4.Signer
+	httpClient    *http.Client
+	minConfidence float64
+}
+
+// NewRekognitionModerator creates a Rekognition moderator from AWS_* environment variables
+func NewRekognitionModerator() (*RekognitionModerator, error) {
+	region := os.Getenv("AWS_REGION")
+	accessKeyID := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretAccessKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if region == "" || accessKeyID == "" || secretAccessKey == "" {
+		return nil, fmt.Errorf("missing AWS configuration for rekognition moderator")
+	}
+
+	return &RekognitionModerator{
+		region:        region,
+		creds:         credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, os.Getenv("AWS_SESSION_TOKEN")),
+		signer:        v4.NewSigner(),
+		httpClient:    &http.Client{Timeout: 15 * time.Second},
+		minConfidence: 50,
+	}, nil
+}
+
+// Name returns the provider name
+func (m *RekognitionModerator) Name() string { return "rekognition" }
+
+// Moderate calls DetectModerationLabels and returns the highest label confidence as score
+func (m *RekognitionModerator) Moderate(ctx context.Context, data []byte) (*ModerationResult, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"Image":         map[string]string{"Bytes": base64.StdEncoding.EncodeToString(data)},
+		"MinConfidence": m.minConfidence,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal rekognition request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("https://rekognition.%s.amazonaws.com/", m.region)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build rekognition request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "RekognitionService.DetectModerationLabels")
+
+	creds, err := m.creds.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("retrieve aws credentials: %w", err)
+	}
+	payloadHash := sha256.Sum256(body)
+	if err := m.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(payloadHash[:]), "rekognition", m.region, time.Now()); err != nil {
+		return nil, fmt.Errorf("sign rekognition request: %w", err)
+	}
+
+	var out struct {
+		ModerationLabels []struct {
+			Name       string  `json:"Name"`
+			ParentName string  `json:"ParentName"`
+			Confidence float64 `json:"Confidence"`
+		} `json:"ModerationLabels"`
+	}
+	if err := doModerationRequest(m.httpClient, req, &out); err != nil {
+		return nil, fmt.Errorf("rekognition: %w", err)
+	}
+
+	result := &ModerationResult{}
+	for _, l := range out.ModerationLabels {
+		result.Labels = append(result.Labels, l.Name)
+		if score := l.Confidence / 100; score > result.Score {
+			result.Score = score
+		}
+	}
+	return result, nil
+}
+
+// CloudflareModerator runs an image classification model on Cloudflare Workers AI
+// and treats the configured labels as unsafe
+type CloudflareModerator struct {
+	accountID    string
+	apiToken     string
+	model        string
+	unsafeLabels map[string]bool
+	httpClient   *http.Client
+}
+
+// NewCloudflareModerator creates a Workers AI moderator from CLOUDFLARE_* environment variables
+func NewCloudflareModerator() (*CloudflareModerator, error) {
+	accountID := os.Getenv("CLOUDFLARE_ACCOUNT_ID")
+	if accountID == "" {
+		accountID = os.Getenv("R2_ACCOUNT_ID")
+	}
+	apiToken := os.Getenv("CLOUDFLARE_API_TOKEN")
+	model := os.Getenv("IMAGE_MODERATION_CF_MODEL")
+	if accountID == "" || apiToken == "" || model == "" {
+		return nil, fmt.Errorf("missing Cloudflare configuration for moderator")
+	}
+
+	labels := os.Getenv("IMAGE_MODERATION_CF_UNSAFE_LABELS")
+	if labels == "" {
+		labels = "nsfw,porn,hentai,sexy"
+	}
+	unsafe := make(map[string]bool)
+	for _, l := range strings.Split(labels, ",") {
+		if trimmed := strings.ToLower(strings.TrimSpace(l)); trimmed != "" {
+			unsafe[trimmed] = true
+		}
+	}
+
+	return &CloudflareModerator{
+		accountID:    accountID,
+		apiToken:     apiToken,
+		model:        model,
+		unsafeLabels: unsafe,
+		httpClient:   &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// Name returns the provider name
+func (m *CloudflareModerator) Name() string { return "cloudflare" }
+
+// Moderate classifies the image and returns the highest unsafe label score
+func (m *CloudflareModerator) Moderate(ctx context.Context, data []byte) (*ModerationResult, error) {
+	endpoint := fmt.Sprintf("https://api.cloudflare.com/client/v4/accounts/%s/ai/run/%s", m.accountID, m.model)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("build cloudflare request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+m.apiToken)
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	var out struct {
+		Success bool `json:"success"`
+		Result  []struct {
+			Label string  `json:"label"`
+			Score float64 `json:"score"`
+		} `json:"result"`
+	}
+	if err := doModerationRequest(m.httpClient, req, &out); err != nil {
+		return nil, fmt.Errorf("cloudflare: %w", err)
+	}
+	if !out.Success {
+		return nil, fmt.Errorf("cloudflare: request was not successful")
+	}
+
+	result := &ModerationResult{}
+	for _, r := range out.Result {
+		if !m.unsafeLabels[strings.ToLower(r.Label)] {
+			continue
+		}
+		result.Labels = append(result.Labels, r.Label)
+		if r.Score > result.Score {
+			result.Score = r.Score
+		}
+	}
+	return result, nil
+}
+
+// LocalNSFWModerator calls a self-hosted NSFW classifier (e.g. nsfw_model served over HTTP)
+// that returns per-class probabilities such as {"porn": 0.9, "neutral": 0.1}
+type LocalNSFWModerator struct {
+	endpoint      string
+	unsafeLabels  []string
+	flagThreshold float64 // Labels scoring at least this are reported
+	httpClient    *http.Client
+}
+
+// NewLocalNSFWModerator creates a local model moderator from IMAGE_MODERATION_LOCAL_URL.
+// Labels are reported at the flag threshold of the policy the service uses.
+func NewLocalNSFWModerator(policy ModerationPolicy) (*LocalNSFWModerator, error) {
+	endpoint := os.Getenv("IMAGE_MODERATION_LOCAL_URL")
+	if endpoint == "" {
+		return nil, fmt.Errorf("IMAGE_MODERATION_LOCAL_URL is required for local moderator")
+	}
+	return &LocalNSFWModerator{
+		endpoint:      endpoint,
+		unsafeLabels:  []string{"porn", "hentai", "sexy"},
+		flagThreshold: policy.FlagThreshold,
+		httpClient:    &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Name returns the provider name
+func (m *LocalNSFWModerator) Name() string { return "local" }
+
+// Moderate posts the image to the local classifier
+func (m *LocalNSFWModerator) Moderate(ctx context.Context, data []byte) (*ModerationResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("build local moderation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "image/jpeg")
+
+	var out map[string]float64
+	if err := doModerationRequest(m.httpClient, req, &out); err != nil {
+		return nil, fmt.Errorf("local moderator: %w", err)
+	}
+
+	result := &ModerationResult{}
+	for _, label := range m.unsafeLabels {
+		score, ok := out[label]
+		if !ok {
+			continue
+		}
+		if score >= m.flagThreshold {
+			result.Labels = append(result.Labels, label)
+		}
+		if score > result.Score {
+			result.Score = score
+		}
+	}
+	return result, nil
+}
+
+// doModerationRequest executes a request and decodes a JSON response
+func doModerationRequest(client *http.Client, req *http.Request, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package imaging
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestLocalNSFWModeratorUsesPolicyFlagThreshold(t *testing.T) {
+	classifier := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"porn": 0.4, "neutral": 0.6}`))
+	}))
+	defer classifier.Close()
+	t.Setenv("IMAGE_MODERATION_LOCAL_URL", classifier.URL)
+
+	m, err := NewLocalNSFWModerator(ModerationPolicy{FlagThreshold: 0.3, QuarantineThreshold: 0.9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := m.Moderate(context.Background(), []byte("image"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(result.Labels, "porn") {
+		t.Fatalf("labels = %v, want porn reported above the 0.3 flag threshold", result.Labels)
+	}
+}
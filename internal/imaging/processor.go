@@ -5,8 +5,12 @@ import (
 	"fmt"
 	"log/slog"
 	"runtime"
+	"time"
 
 	"github.com/davidbyttow/govips/v2/vips"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -23,6 +27,7 @@ func NewProcessor() *Processor {
 		CacheTrace:       false,
 		CollectStats:     true,
 	})
+	Decoders()
 
 	return &Processor{
 		maxConcurrency: runtime.NumCPU(),
@@ -44,8 +49,8 @@ type ProcessedImage struct {
 	SizeBytes int
 }
 
-// ProcessImage generates all renditions for an image in parallel
-func (p *Processor) ProcessImage(ctx context.Context, data []byte, category string, hasAlpha bool, cropConfig *CropConfig) ([]ProcessedImage, error) {
+// ProcessImage generates the renditions of a ladder for an image in parallel
+func (p *Processor) ProcessImage(ctx context.Context, data []byte, renditions []RenditionConfig, hasAlpha bool, cropConfig *CropConfig) ([]ProcessedImage, error) {
 	// Initialize source to check dimensions
 	srcParams := vips.NewImportParams()
 	srcParams.FailOnError.Set(true)
@@ -58,8 +63,6 @@ func (p *Processor) ProcessImage(ctx context.Context, data []byte, category stri
 	srcH := tmpImage.Height()
 	tmpImage.Close()
 
-	renditions := GetRenditionsForCategory(category)
-
 	// Use errgroup for parallel processing across available CPU cores
 	g, ctx := errgroup.WithContext(ctx)
 	// Limit concurrency if needed, but errgroup usually handles it via goroutines
@@ -104,6 +107,12 @@ func (p *Processor) ProcessImage(ctx context.Context, data []byte, category stri
 func (p *Processor) processRendition(ctx context.Context, srcData []byte, config RenditionConfig, hasAlpha bool, cropConfig *CropConfig) ([]ProcessedImage, error) {
 	formats := GetFormatsForRendition(hasAlpha, config.SkipAVIF)
 
+	ctx, span := tracer.Start(ctx, "imaging.rendition", trace.WithAttributes(
+		attribute.String("imaging.rendition", config.Name),
+		attribute.StringSlice("imaging.formats", formats),
+	))
+	defer span.End()
+
 	// Load the source image ONCE for this rendition
 	baseImg, err := vips.LoadImageFromBuffer(srcData, vips.NewImportParams())
 	if err != nil {
@@ -128,6 +137,7 @@ func (p *Processor) processRendition(ctx context.Context, srcData []byte, config
 		}
 
 		// Clone for this format
+		encodeStart := time.Now()
 		img, err := baseImg.Copy()
 		if err != nil {
 			return nil, fmt.Errorf("failed to copy image for %s: %w", format, err)
@@ -164,8 +174,11 @@ func (p *Processor) processRendition(ctx context.Context, srcData []byte, config
 		img.Close() // Explicitly close the copy immediately
 
 		if exportErr != nil {
+			span.RecordError(exportErr)
+			span.SetStatus(codes.Error, exportErr.Error())
 			return nil, exportErr
 		}
+		metrics.recordRendition(ctx, config.Name, format, time.Since(encodeStart).Seconds())
 
 		results = append(results, ProcessedImage{
 			Name:      config.Name,
@@ -234,6 +247,26 @@ func (p *Processor) resizeAndCrop(img *vips.ImageRef, config RenditionConfig, cr
 	}
 }
 
+// ModerationPreview renders a downscaled JPEG for content moderation providers,
+// which generally accept only JPEG/PNG and have request size limits
+func (p *Processor) ModerationPreview(data []byte) ([]byte, error) {
+	img, err := vips.LoadImageFromBuffer(data, vips.NewImportParams())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load image: %w", err)
+	}
+	defer img.Close()
+
+	if err := img.Thumbnail(1024, 1024, vips.InterestingNone); err != nil {
+		return nil, fmt.Errorf("failed to resize preview: %w", err)
+	}
+
+	ep := vips.NewJpegExportParams()
+	ep.Quality = 80
+	ep.StripMetadata = true
+	b, _, err := img.ExportJpeg(ep)
+	return b, err
+}
+
 // StripEXIF removes EXIF metadata from image data
 func (p *Processor) StripEXIF(data []byte) ([]byte, error) {
 	img, err := vips.LoadImageFromBuffer(data, vips.NewImportParams())
@@ -2,13 +2,13 @@ package imaging
 
 // RenditionConfig defines how to generate a specific image rendition
 type RenditionConfig struct {
-	Name          string
-	Width         int
-	Height        int // 0 means maintain aspect ratio
-	CropMode      CropMode
-	Quality       QualityLevel
-	SkipAVIF      bool // Skip AVIF for very small images
-	UseCustomCrop bool // If true, uses crop_data from job if available
+	Name          string       `json:"name"`
+	Width         int          `json:"width"`
+	Height        int          `json:"height"` // 0 means maintain aspect ratio
+	CropMode      CropMode     `json:"crop_mode"`
+	Quality       QualityLevel `json:"quality"`
+	SkipAVIF      bool         `json:"skip_avif,omitempty"`       // Skip AVIF for very small images
+	UseCustomCrop bool         `json:"use_custom_crop,omitempty"` // If true, uses crop_data from job if available
 }
 
 // CropMode defines how images should be cropped
@@ -51,23 +51,24 @@ type QualitySettings struct {
 	JPEG int // 0-100, higher = better quality
 }
 
-// GetRenditionsForCategory returns the image ladder for a category
+// GetRenditionsForCategory returns the compiled-in image ladder for a
+// category. Overrides stored in rendition_ladders take precedence; see Ladders.
 func GetRenditionsForCategory(category string) []RenditionConfig {
-	switch category {
+	switch LadderCategory(category) {
 	case "profile":
 		return []RenditionConfig{
-			{Name: "profile_48", Width: 48, Height: 48, CropMode: CropCenterSquare, Quality: QualityHigh, SkipAVIF: true},
-			{Name: "profile_96", Width: 96, Height: 96, CropMode: CropCenterSquare, Quality: QualityHigh, SkipAVIF: true},
-			{Name: "profile_200", Width: 200, Height: 200, CropMode: CropCenterSquare, Quality: QualityHigh},
-			{Name: "profile_400", Width: 400, Height: 400, CropMode: CropCenterSquare, Quality: QualityMedium},
+			{Name: "profile_48", Width: 48, Height: 48, CropMode: CropCenterSquare, Quality: QualityHigh, SkipAVIF: true, UseCustomCrop: true},
+			{Name: "profile_96", Width: 96, Height: 96, CropMode: CropCenterSquare, Quality: QualityHigh, SkipAVIF: true, UseCustomCrop: true},
+			{Name: "profile_200", Width: 200, Height: 200, CropMode: CropCenterSquare, Quality: QualityHigh, UseCustomCrop: true},
+			{Name: "profile_400", Width: 400, Height: 400, CropMode: CropCenterSquare, Quality: QualityMedium, UseCustomCrop: true},
 		}
 	case "cover":
 		return []RenditionConfig{
-			{Name: "cover_320", Width: 320, Height: 180, CropMode: CropCenter16x9, Quality: QualityHigh},
-			{Name: "cover_640", Width: 640, Height: 360, CropMode: CropCenter16x9, Quality: QualityHigh},
-			{Name: "cover_960", Width: 960, Height: 540, CropMode: CropCenter16x9, Quality: QualityHigh},
-			{Name: "cover_1200", Width: 1200, Height: 675, CropMode: CropCenter16x9, Quality: QualityHigh},
-			{Name: "cover_1920", Width: 1920, Height: 1080, CropMode: CropCenter16x9, Quality: QualityHigh},
+			{Name: "cover_320", Width: 320, Height: 180, CropMode: CropCenter16x9, Quality: QualityHigh, UseCustomCrop: true},
+			{Name: "cover_640", Width: 640, Height: 360, CropMode: CropCenter16x9, Quality: QualityHigh, UseCustomCrop: true},
+			{Name: "cover_960", Width: 960, Height: 540, CropMode: CropCenter16x9, Quality: QualityHigh, UseCustomCrop: true},
+			{Name: "cover_1200", Width: 1200, Height: 675, CropMode: CropCenter16x9, Quality: QualityHigh, UseCustomCrop: true},
+			{Name: "cover_1920", Width: 1920, Height: 1080, CropMode: CropCenter16x9, Quality: QualityHigh, UseCustomCrop: true},
 		}
 	case "gallery":
 		return []RenditionConfig{
@@ -103,3 +104,9 @@ func GetFormatsForRendition(hasAlpha bool, skipAVIF bool) []string {
 	}
 	return []string{"avif", "webp", "jpg"}
 }
+
+// RenditionPrefix is the name prefix shared by the renditions of a category's
+// ladder, e.g. "cover_". ValidateLadder keeps overrides to the same prefix.
+func RenditionPrefix(category string) string {
+	return LadderCategory(category) + "_"
+}
@@ -0,0 +1,192 @@
+package imaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reprocess run statuses
+const (
+	ReprocessRunning   = "running"
+	ReprocessCompleted = "completed"
+	ReprocessCancelled = "cancelled"
+	ReprocessFailed    = "failed"
+)
+
+// ErrReprocessRunning is returned when starting a run while another one is running
+var ErrReprocessRunning = errors.New("a reprocess run is already running")
+
+// ErrReprocessLeased is returned when another runner holds the lease of a run
+var ErrReprocessLeased = errors.New("reprocess run is being driven by another runner")
+
+// reprocessLease is how long a runner owns a run without renewing the lease.
+// It is renewed every batch, so it must outlast the batch interval.
+const reprocessLease = 2 * time.Minute
+
+// ReprocessRun is a pass queueing every ready asset for reprocessing with the
+// current rendition ladder
+type ReprocessRun struct {
+	RunID           uuid.UUID  `db:"run_id" json:"run_id"`
+	Status          string     `db:"status" json:"status"`
+	Category        *string    `db:"category" json:"category,omitempty"`
+	OutdatedOnly    bool       `db:"outdated_only" json:"outdated_only"` // Only assets rendered with an older ladder version
+	BatchSize       int        `db:"batch_size" json:"batch_size"`
+	BatchIntervalMS int        `db:"batch_interval_ms" json:"batch_interval_ms"`
+	Total           int        `db:"total" json:"total"`
+	Queued          int        `db:"queued" json:"queued"`
+	CursorCreatedAt *time.Time `db:"cursor_created_at" json:"-"`
+	CursorID        *uuid.UUID `db:"cursor_id" json:"-"`
+	LeaseOwner      *uuid.UUID `db:"lease_owner" json:"-"`
+	LeaseUntil      *time.Time `db:"lease_until" json:"-"`
+	StartedBy       *uuid.UUID `db:"started_by" json:"started_by,omitempty"`
+	LastError       *string    `db:"last_error" json:"last_error,omitempty"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at" json:"updated_at"`
+	CompletedAt     *time.Time `db:"completed_at" json:"completed_at,omitempty"`
+}
+
+// ReprocessStore persists reprocess runs and pages through ready assets
+type ReprocessStore interface {
+	StartReprocessRun(ctx context.Context, category *string, outdatedOnly bool, batchSize, batchIntervalMS int, startedBy *uuid.UUID) (*ReprocessRun, error)
+	GetActiveReprocessRun(ctx context.Context) (*ReprocessRun, error)
+	GetLatestReprocessRun(ctx context.Context) (*ReprocessRun, error)
+	ClaimReprocessRun(ctx context.Context, runID, owner uuid.UUID, lease time.Duration) (*ReprocessRun, error)
+	NextReprocessBatch(ctx context.Context, run *ReprocessRun) ([]ImageAsset, error)
+	AdvanceReprocessRun(ctx context.Context, runID uuid.UUID, last ImageAsset, queued int) error
+	FinishReprocessRun(ctx context.Context, runID uuid.UUID, status, lastError string) error
+}
+
+// Reprocessor drives reprocess runs, queueing assets on a Service in batches.
+// Batches wait for room in the service queue and for the run's batch interval,
+// so a run never floods the workers serving new uploads.
+type Reprocessor struct {
+	store ReprocessStore
+	svc   *Service
+	owner uuid.UUID // Identifies this runner in run leases
+
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewReprocessor creates a reprocessor queueing jobs on svc
+func NewReprocessor(store ReprocessStore, svc *Service) *Reprocessor {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Reprocessor{store: store, svc: svc, owner: uuid.New(), ctx: ctx, cancel: cancel}
+}
+
+// Start drives run in the background until it completes, is cancelled or the
+// reprocessor stops
+func (r *Reprocessor) Start(run *ReprocessRun) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		if err := r.Run(r.ctx, run); err != nil && !errors.Is(err, context.Canceled) {
+			slog.Error("reprocess run stopped", "run_id", run.RunID, "error", err)
+		}
+	}()
+}
+
+// Stop interrupts background runs; they stay running and resume when started again
+func (r *Reprocessor) Stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// Run queues the remaining assets of run, returning when every asset has been
+// queued, the run was cancelled, or ctx is done
+func (r *Reprocessor) Run(ctx context.Context, run *ReprocessRun) error {
+	interval := time.Duration(run.BatchIntervalMS) * time.Millisecond
+	l := slog.With("run_id", run.RunID)
+
+	for {
+		claimed, err := r.store.ClaimReprocessRun(ctx, run.RunID, r.owner, reprocessLease)
+		if err != nil {
+			return err
+		}
+		if claimed.Status != ReprocessRunning {
+			l.Info("reprocess run ended", "status", claimed.Status)
+			return nil
+		}
+		run = claimed
+
+		if err := r.waitForRoom(ctx, run.BatchSize); err != nil {
+			return err
+		}
+
+		assets, err := r.store.NextReprocessBatch(ctx, run)
+		if err != nil {
+			return r.fail(run, err)
+		}
+		if len(assets) == 0 {
+			l.Info("reprocess run completed", "queued", run.Queued)
+			return r.store.FinishReprocessRun(ctx, run.RunID, ReprocessCompleted, "")
+		}
+
+		for _, a := range assets {
+			if _, err := r.svc.QueueReprocessing(OriginalKey(a.ContentHash), a.Category, a.CreatedByUserID, nil); err != nil {
+				return r.fail(run, fmt.Errorf("queue asset %s: %w", a.ID, err))
+			}
+		}
+		if err := r.store.AdvanceReprocessRun(ctx, run.RunID, assets[len(assets)-1], len(assets)); err != nil {
+			return err
+		}
+		l.Info("reprocess batch queued", "assets", len(assets), "queued", run.Queued+len(assets), "total", run.Total)
+
+		if interval > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(interval):
+			}
+		}
+	}
+}
+
+// waitForRoom blocks until the service queue can take n more jobs
+func (r *Reprocessor) waitForRoom(ctx context.Context, n int) error {
+	for {
+		st := r.svc.Status()
+		if st.Stopping {
+			return ErrServiceStopped
+		}
+		if st.QueueCapacity-st.QueueDepth >= n {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// fail records err on the run unless the failure came from shutting down, in
+// which case the run stays running to be resumed
+func (r *Reprocessor) fail(run *ReprocessRun, err error) error {
+	if errors.Is(err, ErrServiceStopped) || errors.Is(err, context.Canceled) {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if ferr := r.store.FinishReprocessRun(ctx, run.RunID, ReprocessFailed, err.Error()); ferr != nil {
+		slog.Error("failed to record reprocess run failure", "run_id", run.RunID, "error", ferr)
+	}
+	return err
+}
+
+// OriginalKey is the storage key of an asset's original upload
+func OriginalKey(contentHash string) string {
+	return fmt.Sprintf("originals/%s/%s/original", contentHash[:2], contentHash)
+}
+
+// QuarantineKey is the storage key of a quarantined asset's original upload
+func QuarantineKey(contentHash string) string {
+	return fmt.Sprintf("quarantine/%s/%s/original", contentHash[:2], contentHash)
+}
@@ -4,13 +4,20 @@ import (
 	"context"
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"maukemana-backend/internal/breaker"
+
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -36,6 +43,21 @@ func (c *CropConfig) Scan(value interface{}) error {
 	return json.Unmarshal(b, &c)
 }
 
+// MaxWorkers bounds the size of the worker pool
+const MaxWorkers = 64
+
+// storageRetryDelay is how long a job waits after R2's circuit breaker rejected it
+const storageRetryDelay = 30 * time.Second
+
+// ErrInvalidWorkerCount is returned when scaling the pool outside 1..MaxWorkers
+var ErrInvalidWorkerCount = errors.New("invalid worker count")
+
+// ErrServiceStopped is returned when a job is queued after Stop was called
+var ErrServiceStopped = errors.New("imaging service is shutting down")
+
+// ErrAssetBlocked is returned when an asset exists but was blocked by content moderation
+var ErrAssetBlocked = errors.New("asset blocked by content moderation")
+
 // ProcessingStatus represents the status of an image processing job
 type ProcessingStatus string
 
@@ -50,21 +72,25 @@ const (
 
 // ImageAsset represents a processed image asset with all its derivatives
 type ImageAsset struct {
-	ID              uuid.UUID        `json:"id" db:"id"`
-	ContentHash     string           `json:"content_hash" db:"content_hash"`
-	OriginalWidth   int              `json:"original_width" db:"original_width"`
-	OriginalHeight  int              `json:"original_height" db:"original_height"`
-	OriginalFormat  string           `json:"original_format" db:"original_format"`
-	OriginalSize    int64            `json:"original_size" db:"original_size"`
-	HasAlpha        bool             `json:"has_alpha" db:"has_alpha"`
-	Category        string           `json:"category" db:"category"`
-	Status          ProcessingStatus `json:"status" db:"status"`
-	Error           string           `json:"error,omitempty" db:"error"`
-	Version         int              `json:"version" db:"version"`
-	Derivatives     []Derivative     `json:"derivatives,omitempty" db:"-"`
-	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
-	ProcessedAt     *time.Time       `json:"processed_at,omitempty" db:"processed_at"`
-	CreatedByUserID uuid.UUID        `json:"created_by_user_id" db:"created_by_user_id"`
+	ID               uuid.UUID        `json:"id" db:"id"`
+	ContentHash      string           `json:"content_hash" db:"content_hash"`
+	OriginalWidth    int              `json:"original_width" db:"original_width"`
+	OriginalHeight   int              `json:"original_height" db:"original_height"`
+	OriginalFormat   string           `json:"original_format" db:"original_format"`
+	OriginalSize     int64            `json:"original_size" db:"original_size"`
+	HasAlpha         bool             `json:"has_alpha" db:"has_alpha"`
+	Category         string           `json:"category" db:"category"`
+	Status           ProcessingStatus `json:"status" db:"status"`
+	Error            string           `json:"error,omitempty" db:"error"`
+	Version          int              `json:"version" db:"version"`
+	LadderVersion    int              `json:"ladder_version" db:"ladder_version"` // Version of the category's ladder the derivatives were rendered with
+	Derivatives      []Derivative     `json:"derivatives,omitempty" db:"-"`
+	CreatedAt        time.Time        `json:"created_at" db:"created_at"`
+	ProcessedAt      *time.Time       `json:"processed_at,omitempty" db:"processed_at"`
+	CreatedByUserID  uuid.UUID        `json:"created_by_user_id" db:"created_by_user_id"`
+	ModerationStatus ModerationStatus `json:"moderation_status" db:"moderation_status"`
+	ModerationScore  *float64         `json:"moderation_score,omitempty" db:"moderation_score"`
+	ModerationReason string           `json:"moderation_reason,omitempty" db:"moderation_reason"`
 }
 
 // Derivative represents a single image derivative
@@ -99,9 +125,12 @@ type ProcessingJob struct {
 type ImagingRepositoryInterface interface {
 	CreateAsset(ctx context.Context, asset *ImageAsset) error
 	UpdateAssetStatus(ctx context.Context, id uuid.UUID, status ProcessingStatus, errorMessage string) error
+	UpdateAssetModeration(ctx context.Context, id uuid.UUID, status ModerationStatus, score *float64, reason string) error
 	GetAssetByHash(ctx context.Context, hash string) (*ImageAsset, error)
 	GetAssetByID(ctx context.Context, id uuid.UUID) (*ImageAsset, error)
 	CreateDerivative(ctx context.Context, d Derivative) error
+	ReplaceDerivatives(ctx context.Context, assetID uuid.UUID, version int, renditionPrefix string, derivatives []Derivative) error
+	UpdateAssetLadderVersion(ctx context.Context, id uuid.UUID, ladderVersion int) error
 	GetDerivatives(ctx context.Context, assetID uuid.UUID) ([]Derivative, error)
 	CreateJob(ctx context.Context, job *ProcessingJob) error
 	UpdateJob(ctx context.Context, id uuid.UUID, status ProcessingStatus, assetID *uuid.UUID, attempts int, lastError string) error
@@ -115,14 +144,36 @@ type Service struct {
 	r2Client  R2ClientInterface
 	repo      ImagingRepositoryInterface
 
+	// Content moderation (nil disables the stage)
+	moderator        Moderator
+	moderationPolicy ModerationPolicy
+
 	// Job queue
 	jobQueue chan *ProcessingJob
-
-	// Worker pool
-	workerCount int
-	wg          sync.WaitGroup
-	ctx         context.Context
-	cancel      context.CancelFunc
+	hashes   hashLocks // Serializes jobs rendering the same image
+
+	lookups *assetLookups // Asset lookups behind GetDerivativeKey
+
+	// Rendition ladders, reloaded every ladderRefresh (0 disables)
+	ladders       *Ladders
+	ladderRefresh time.Duration
+
+	// Worker pool; workerCount is the target size, guarded by mu
+	workerCount  int
+	nextWorkerID int
+	wg           sync.WaitGroup
+	ctx          context.Context
+	cancel       context.CancelFunc
+
+	// Shutdown: stopping is closed by Stop
+	stopping chan struct{}
+	stopOnce sync.Once
+
+	// Pipeline state reported by Status, guarded by mu
+	mu            sync.Mutex
+	workers       map[int]*workerState
+	queued        map[uuid.UUID]time.Time // Creation time of the jobs in jobQueue
+	lastProcessed time.Time
 }
 
 // R2ClientInterface defines the interface for R2 operations
@@ -134,29 +185,92 @@ type R2ClientInterface interface {
 	MoveObject(ctx context.Context, srcKey, dstKey string) error
 }
 
+// ServiceOption configures optional Service behaviour
+type ServiceOption func(*Service)
+
+// WithModerator enables the content moderation stage using the given provider and policy
+func WithModerator(m Moderator, policy ModerationPolicy) ServiceOption {
+	return func(s *Service) {
+		s.moderator = m
+		s.moderationPolicy = policy
+	}
+}
+
+// WithLookupCache sets how long GetDerivativeKey caches servable assets (ttl)
+// and missing or unready ones (negativeTTL); zero disables either
+func WithLookupCache(ttl, negativeTTL time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.lookups = newAssetLookups(ttl, negativeTTL)
+	}
+}
+
+// WithLadders renders with ladders, reloading them every refresh so ladder
+// changes apply without a restart. Without it the compiled-in ladders are used.
+func WithLadders(ladders *Ladders, refresh time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.ladders = ladders
+		s.ladderRefresh = refresh
+	}
+}
+
 // NewService creates a new imaging service
-func NewService(r2Client R2ClientInterface, repo ImagingRepositoryInterface, workerCount int) *Service {
+func NewService(r2Client R2ClientInterface, repo ImagingRepositoryInterface, workerCount int, opts ...ServiceOption) *Service {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	s := &Service{
-		processor:   NewProcessor(),
-		r2Client:    r2Client,
-		repo:        repo,
-		jobQueue:    make(chan *ProcessingJob, 1000),
-		workerCount: workerCount,
-		ctx:         ctx,
-		cancel:      cancel,
+		processor:        NewProcessor(),
+		r2Client:         r2Client,
+		repo:             repo,
+		moderationPolicy: DefaultModerationPolicy,
+		jobQueue:         make(chan *ProcessingJob, 1000),
+		workerCount:      workerCount,
+		ctx:              ctx,
+		cancel:           cancel,
+		stopping:         make(chan struct{}),
+		workers:          make(map[int]*workerState),
+		queued:           make(map[uuid.UUID]time.Time),
+		lookups:          newAssetLookups(DefaultLookupTTL, DefaultLookupNegativeTTL),
+		ladders:          NewLadders(nil),
 	}
 
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.registerQueueDepth()
+
 	// Start worker pool
 	s.startWorkers()
 
 	// Resume pending jobs from database
 	go s.resumePendingJobs()
 
+	if s.ladderRefresh > 0 {
+		go s.refreshLadders()
+	}
+
 	return s
 }
 
+// refreshLadders reloads the rendition ladders until the service stops
+func (s *Service) refreshLadders() {
+	ticker := time.NewTicker(s.ladderRefresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopping:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+			if err := s.ladders.Load(ctx); err != nil {
+				slog.Warn("failed to reload rendition ladders", "error", err)
+			}
+			cancel()
+		}
+	}
+}
+
 func (s *Service) resumePendingJobs() {
 	time.Sleep(1 * time.Second)                                             // Small delay for startup stability
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute) // Increased timeout
@@ -174,49 +288,216 @@ func (s *Service) resumePendingJobs() {
 		j := job // copy
 		// Blocking send to ensure we don't drop jobs
 		// If queue is full, this will wait until workers consume some
+		s.markQueued(&j)
 		select {
 		case s.jobQueue <- &j:
 			slog.Info("resumed pending job", "job_id", j.ID)
-		case <-s.ctx.Done():
+		case <-s.stopping:
 			// Service shutting down
+			s.unmarkQueued(&j)
 			return
 		case <-ctx.Done():
+			s.unmarkQueued(&j)
 			slog.Warn("timeout resuming pending jobs")
 			return
 		}
 	}
 }
 
-// Stop gracefully stops the service
-func (s *Service) Stop() {
+// Stop gracefully stops the service. New jobs are refused and queued ones are
+// left pending for the next start, while in-flight jobs get until ctx is done
+// to finish. Jobs still running then are aborted and reset to pending.
+func (s *Service) Stop(ctx context.Context) error {
+	// Under mu so SetWorkers cannot add workers while the pool is drained
+	s.mu.Lock()
+	s.stopOnce.Do(func() { close(s.stopping) })
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+	}
+
 	s.cancel()
-	close(s.jobQueue)
-	s.wg.Wait()
+	interrupted := s.releaseActive()
+	slog.Warn("imaging drain timed out, in-flight jobs reset to pending", "jobs", interrupted)
+
+	// Aborted workers return once their current stage notices the cancellation
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		slog.Warn("imaging workers did not exit after cancellation")
+	}
+	return fmt.Errorf("imaging drain: %w", ctx.Err())
+}
+
+// releaseActive resets the jobs being processed to pending so the next start
+// resumes them, and returns how many there were
+func (s *Service) releaseActive() int {
+	s.mu.Lock()
+	var jobs []*ProcessingJob
+	for _, w := range s.workers {
+		if w.job != nil {
+			jobs = append(jobs, w.job)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, job := range jobs {
+		s.releaseJob(job)
+	}
+	return len(jobs)
+}
+
+// releaseJob returns an interrupted job to the queue without counting an attempt
+func (s *Service) releaseJob(job *ProcessingJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.repo.UpdateJob(ctx, job.ID, StatusPending, job.AssetID, job.Attempts, "interrupted by shutdown"); err != nil {
+		slog.Error("failed to reset interrupted job", "job_id", job.ID, "error", err)
+	}
+}
+
+// deferJob puts a job back to pending without using up an attempt and
+// queues it again once the storage breaker may have closed
+func (s *Service) deferJob(job *ProcessingJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.repo.UpdateJob(ctx, job.ID, StatusPending, job.AssetID, job.Attempts, "deferred: storage unavailable"); err != nil {
+		slog.Error("failed to defer job", "job_id", job.ID, "error", err)
+	}
+	go func() {
+		time.Sleep(storageRetryDelay)
+		if s.stopped() {
+			return // Left pending for the next start
+		}
+		if !s.enqueue(job) {
+			slog.Error("failed to requeue deferred job", "job_id", job.ID)
+		}
+	}()
+}
+
+func (s *Service) stopped() bool {
+	select {
+	case <-s.stopping:
+		return true
+	default:
+		return false
+	}
 }
 
 // startWorkers starts the image processing worker pool
 func (s *Service) startWorkers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	for i := 0; i < s.workerCount; i++ {
-		s.wg.Add(1)
-		go s.worker(i)
+		s.spawnWorkerLocked()
 	}
 }
 
-// worker processes jobs from the queue
-func (s *Service) worker(id int) {
+// SetWorkers scales the worker pool to n workers. Added workers start at once;
+// removed ones exit after finishing their current job.
+func (s *Service) SetWorkers(n int) error {
+	if n < 1 || n > MaxWorkers {
+		return fmt.Errorf("%w: must be between 1 and %d", ErrInvalidWorkerCount, MaxWorkers)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped() {
+		return ErrServiceStopped
+	}
+
+	var running []int
+	for id, w := range s.workers {
+		if !w.retiring {
+			running = append(running, id)
+		}
+	}
+	sort.Ints(running)
+
+	for i := len(running); i < n; i++ {
+		s.spawnWorkerLocked()
+	}
+	// Retire the newest workers first
+	for i := len(running) - 1; i >= n; i-- {
+		w := s.workers[running[i]]
+		w.retiring = true
+		close(w.quit)
+	}
+
+	if n != s.workerCount {
+		slog.Info("imaging worker pool scaled", "from", s.workerCount, "to", n)
+	}
+	s.workerCount = n
+	return nil
+}
+
+// spawnWorkerLocked starts one worker. s.mu must be held.
+func (s *Service) spawnWorkerLocked() {
+	id := s.nextWorkerID
+	s.nextWorkerID++
+	quit := make(chan struct{})
+	s.workers[id] = &workerState{quit: quit, since: time.Now()}
+	s.wg.Add(1)
+	go s.worker(id, quit)
+}
+
+// worker processes jobs from the queue until the service stops or quit is closed
+func (s *Service) worker(id int, quit <-chan struct{}) {
 	defer s.wg.Done()
 	l := slog.With("worker_id", id)
 
-	for job := range s.jobQueue {
-		// Priority check for shutdown
+	defer func() {
+		s.mu.Lock()
+		delete(s.workers, id)
+		s.mu.Unlock()
+	}()
+
+	for {
+		// Retired workers leave before taking another job
 		select {
-		case <-s.ctx.Done():
+		case <-quit:
 			return
 		default:
 		}
 
+		var job *ProcessingJob
+		select {
+		case <-s.stopping:
+			return
+		case <-quit:
+			return
+		case job = <-s.jobQueue:
+		}
+		s.unmarkQueued(job)
+		// Queued jobs stay pending in the database once shutdown has begun
+		if s.stopped() {
+			return
+		}
+
+		s.setBusy(id, job)
 		l.Info("worker processing job", "job_id", job.ID)
-		if err := s.processJob(job); err != nil {
+		err := s.processJob(job)
+		s.setIdle(id)
+
+		switch {
+		case err == nil:
+		case s.ctx.Err() != nil:
+			l.Warn("job interrupted by shutdown", "job_id", job.ID, "error", err)
+			s.releaseJob(job)
+		case errors.Is(err, breaker.ErrOpen):
+			l.Warn("job deferred while storage is unavailable", "job_id", job.ID, "error", err)
+			s.deferJob(job)
+		default:
 			l.Error("failed to process job", "job_id", job.ID, "error", err)
 			s.handleJobFailure(job, err)
 		}
@@ -225,6 +506,9 @@ func (s *Service) worker(id int) {
 
 // QueueProcessing queues an image for processing
 func (s *Service) QueueProcessing(uploadKey, category string, userID uuid.UUID, cropConfig *CropConfig) (uuid.UUID, error) {
+	if s.stopped() {
+		return uuid.Nil, ErrServiceStopped
+	}
 	job := &ProcessingJob{
 		ID:        uuid.New(),
 		UploadKey: uploadKey,
@@ -238,17 +522,16 @@ func (s *Service) QueueProcessing(uploadKey, category string, userID uuid.UUID,
 		return uuid.Nil, fmt.Errorf("failed to create job: %w", err)
 	}
 
-	select {
-	case s.jobQueue <- job:
-		return job.ID, nil
-	default:
-		// Even if queue is full, job is in DB so it can be resumed later
-		return job.ID, nil
-	}
+	// Even if queue is full, job is in DB so it can be resumed later
+	s.enqueue(job)
+	return job.ID, nil
 }
 
 // QueueReprocessing queues an existing asset for reprocessing
 func (s *Service) QueueReprocessing(uploadKey, category string, userID uuid.UUID, cropConfig *CropConfig) (uuid.UUID, error) {
+	if s.stopped() {
+		return uuid.Nil, ErrServiceStopped
+	}
 	job := &ProcessingJob{
 		ID:          uuid.New(),
 		UploadKey:   uploadKey,
@@ -263,37 +546,72 @@ func (s *Service) QueueReprocessing(uploadKey, category string, userID uuid.UUID
 		return uuid.Nil, fmt.Errorf("failed to create job: %w", err)
 	}
 
-	select {
-	case s.jobQueue <- job:
-		return job.ID, nil
-	default:
-		// Even if queue is full, job is in DB so it can be resumed later
-		return job.ID, nil
-	}
+	// Even if queue is full, job is in DB so it can be resumed later
+	s.enqueue(job)
+	return job.ID, nil
 }
 
 // processJob handles the full image processing pipeline
-func (s *Service) processJob(job *ProcessingJob) error {
+func (s *Service) processJob(job *ProcessingJob) (err error) {
 	ctx, cancel := context.WithTimeout(s.ctx, 5*time.Minute)
 	defer cancel()
 
+	ctx, span := tracer.Start(ctx, "imaging.processJob", trace.WithAttributes(
+		attribute.String("imaging.job_id", job.ID.String()),
+		attribute.String("imaging.category", job.Category),
+		attribute.Bool("imaging.reprocess", job.IsReprocess),
+	))
+	start := time.Now()
+	outcome := "ready"
+	defer func() {
+		if err != nil {
+			outcome = "failed"
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.SetAttributes(attribute.String("imaging.outcome", outcome))
+		span.End()
+		metrics.recordJob(ctx, job.Category, outcome, time.Since(start).Seconds())
+	}()
+
 	// 1. Download original from R2
 	s.repo.UpdateJob(ctx, job.ID, StatusDownloading, nil, job.Attempts, "")
-	data, err := s.r2Client.GetObject(ctx, job.UploadKey)
+	stageCtx, stageSpan := startStage(ctx, "download")
+	data, err := s.r2Client.GetObject(stageCtx, job.UploadKey)
+	endStage(ctx, stageSpan, "download", err)
 	if err != nil {
 		return fmt.Errorf("failed to download original: %w", err)
 	}
+	metrics.recordBytes(ctx, "original", len(data))
 
 	// 2. Validate
+	_, stageSpan = startStage(ctx, "validate")
 	validation, err := ValidateImage(data, job.Category)
+	endStage(ctx, stageSpan, "validate", err)
 	if err != nil {
+		var rejection *ContentRejection
+		if errors.As(err, &rejection) {
+			RecordRejection(ctx, rejection.Reason, "validate")
+		}
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
 	job.ContentHash = validation.ContentHash
+	span.SetAttributes(attribute.String("imaging.content_hash", validation.ContentHash))
+
+	// Concurrent uploads of the same image wait here for the first job, then
+	// find its asset ready below and link to it instead of rendering again
+	unlock, err := s.hashes.lock(ctx, validation.ContentHash)
+	if err != nil {
+		return fmt.Errorf("wait for duplicate upload: %w", err)
+	}
+	defer unlock()
+	defer s.lookups.invalidate(validation.ContentHash)
 
 	// 3. Check for existing asset (dedup)
-	existingAsset, err := s.repo.GetAssetByHash(ctx, validation.ContentHash)
+	stageCtx, stageSpan = startStage(ctx, "dedup")
+	existingAsset, err := s.repo.GetAssetByHash(stageCtx, validation.ContentHash)
+	endStage(ctx, stageSpan, "dedup", err)
 	if err != nil {
 		return fmt.Errorf("failed to check existing asset: %w", err)
 	}
@@ -308,6 +626,8 @@ func (s *Service) processJob(job *ProcessingJob) error {
 			s.repo.UpdateJob(ctx, job.ID, StatusReady, &existingAsset.ID, job.Attempts, "")
 			// Clean up the upload (original is same content)
 			s.r2Client.DeleteObject(ctx, job.UploadKey)
+			metrics.recordDedupHit(ctx, job.Category)
+			outcome = "deduplicated"
 			return nil
 		}
 		// If reprocessing or status not ready (maybe retry?), we reuse the ID but continue
@@ -318,38 +638,40 @@ func (s *Service) processJob(job *ProcessingJob) error {
 		assetVersion = 1
 	}
 
+	// The ladder is fixed for the whole job so the recorded version matches
+	// the renditions produced even if the ladder changes meanwhile
+	ladder := s.ladders.Get(job.Category)
+
 	// 4. Create or Update asset record
 	asset := &ImageAsset{
-		ID:              assetID,
-		ContentHash:     validation.ContentHash,
-		OriginalWidth:   validation.Width,
-		OriginalHeight:  validation.Height,
-		OriginalFormat:  validation.Format,
-		OriginalSize:    validation.OriginalSize,
-		HasAlpha:        validation.HasAlpha,
-		Category:        job.Category,
-		Status:          StatusProcessing,
-		Version:         assetVersion,
-		CreatedAt:       time.Now(),
-		CreatedByUserID: job.UserID,
+		ID:               assetID,
+		ContentHash:      validation.ContentHash,
+		OriginalWidth:    validation.Width,
+		OriginalHeight:   validation.Height,
+		OriginalFormat:   validation.Format,
+		OriginalSize:     validation.OriginalSize,
+		HasAlpha:         validation.HasAlpha,
+		Category:         job.Category,
+		Status:           StatusProcessing,
+		ModerationStatus: ModerationUnchecked,
+		Version:          assetVersion,
+		LadderVersion:    ladder.Version,
+		CreatedAt:        time.Now(),
+		CreatedByUserID:  job.UserID,
 	}
 
-	if existingAsset != nil {
-		// Update existing asset e.g. Version, Status
-		// For now we might rely on CreateAsset behaving like upsert or just use a new UpdateAsset method?
-		// Since we don't have explicit UpdateAsset full record, we might need to rely on CreateAsset doing nothing if ID exists?
-		// Wait, repo.CreateAsset might fail if ID exists.
-		// If Reprocess, we likely want to UPDATE the existing record or at least its version/status.
-		// Let's assume we need to handle this.
-		// For simplicity/robustness, if it exists, we update status and version.
-		// But s.repo.CreateAsset probably does INSERT.
-		// As a hack for now, I'll update status/version via UpdateAssetStatus if possible, or assume CreateAsset fails.
-		// Actually, I should probably add UpdateAssetMetadata to repo.
-		// For now, I will assume simple ID reuse.
-		if err := s.repo.UpdateAssetStatus(ctx, asset.ID, StatusProcessing, ""); err != nil {
-			// If this fails, maybe it doesn't exist? But we checked.
+	// A ready asset being reprocessed keeps serving its current derivatives
+	// until the new version replaces them, and keeps them if reprocessing fails
+	serving := existingAsset != nil && existingAsset.Status == StatusReady
+	setAssetStatus := func(status ProcessingStatus, msg string) {
+		if serving && status != StatusReady {
+			return
 		}
-		// Ideally we update Version too.
+		s.repo.UpdateAssetStatus(ctx, asset.ID, status, msg)
+	}
+
+	if existingAsset != nil {
+		setAssetStatus(StatusProcessing, "")
 	} else {
 		if err := s.repo.CreateAsset(ctx, asset); err != nil {
 			return fmt.Errorf("failed to create asset record: %w", err)
@@ -359,28 +681,43 @@ func (s *Service) processJob(job *ProcessingJob) error {
 	// Link job to asset
 	s.repo.UpdateJob(ctx, job.ID, StatusProcessing, &asset.ID, job.Attempts, "")
 
+	// 4b. Content moderation
+	stageCtx, stageSpan = startStage(ctx, "moderate")
+	moderation, err := s.moderate(stageCtx, asset.ID, data)
+	endStage(ctx, stageSpan, "moderate", err)
+	if err != nil {
+		return fmt.Errorf("moderation failed: %w", err)
+	}
+	if moderation == ModerationQuarantined {
+		outcome = "quarantined"
+		return s.quarantine(ctx, job, asset)
+	}
+
 	// 5. Generate renditions in parallel
 	slog.Debug("starting parallel processing", "asset_id", asset.ID)
 	// Update status again?
 	// s.repo.UpdateAssetStatus(ctx, asset.ID, StatusProcessing, "")
 
 	// Pro: Stripping EXIF is now handled efficiently during the export stage in ProcessImage
-	processed, err := s.processor.ProcessImage(ctx, data, job.Category, validation.HasAlpha, job.CropData)
+	stageCtx, stageSpan = startStage(ctx, "render")
+	processed, err := s.processor.ProcessImage(stageCtx, data, ladder.Renditions, validation.HasAlpha, job.CropData)
+	endStage(ctx, stageSpan, "render", err)
 	if err != nil {
-		s.repo.UpdateAssetStatus(ctx, asset.ID, StatusFailed, err.Error())
+		setAssetStatus(StatusFailed, err.Error())
 		return fmt.Errorf("processing failed: %w", err)
 	}
 
 	// 6. Upload derivatives to R2
 	// 6. Upload derivatives to R2 (Parallel)
-	s.repo.UpdateAssetStatus(ctx, asset.ID, StatusUploading, "")
+	setAssetStatus(StatusUploading, "")
 
 	// Pre-allocate slice for results to avoid mutex if possible,
 	// but we need to append valid results only. using a mutex for safety.
 	var derivatives []Derivative
 	var mu sync.Mutex
 
-	g, gCtx := errgroup.WithContext(ctx)
+	stageCtx, stageSpan = startStage(ctx, "upload")
+	g, gCtx := errgroup.WithContext(stageCtx)
 	// Limit upload concurrency to avoid flooding network/R2
 	sem := make(chan struct{}, 10)
 
@@ -405,6 +742,7 @@ func (s *Service) processJob(job *ProcessingJob) error {
 			if err := s.r2Client.PutObject(gCtx, storageKey, p.Data, contentType); err != nil {
 				return fmt.Errorf("failed to upload %s: %w", p.Name, err)
 			}
+			metrics.recordBytes(gCtx, "derivative", len(p.Data))
 
 			mu.Lock()
 			derivatives = append(derivatives, Derivative{
@@ -422,28 +760,43 @@ func (s *Service) processJob(job *ProcessingJob) error {
 		})
 	}
 
-	if err := g.Wait(); err != nil {
-		s.repo.UpdateAssetStatus(ctx, asset.ID, StatusFailed, err.Error())
+	err = g.Wait()
+	endStage(ctx, stageSpan, "upload", err)
+	if err != nil {
+		setAssetStatus(StatusFailed, err.Error())
 		return fmt.Errorf("upload failed: %w", err)
 	}
 	// Parallel uploads finished
 
 	// Create derivative records in DB
-	// We do this sequentially to avoid DB contention and because it's fast
-	// NOTE: For reprocessing, simple CreateDerivative is fine, it will add new rows.
-	// We might want to clear old derivatives for this version? Structure allows multiple?
-	// The DB likely has ID Primary Key.
-	// Old derivatives remain for old versions (if we supported versions fully).
-	// For now, adding new ones is fine.
-	for _, d := range derivatives {
-		if err := s.repo.CreateDerivative(ctx, d); err != nil {
-			slog.Warn("failed to save derivative record", "key", d.StorageKey, "error", err)
-			continue
+	// We do this sequentially to avoid DB contention and because it's fast.
+	// A reprocessed asset swaps the rows of the job's ladder for the new version
+	// at once, so renditions removed from the ladder disappear with it. Other
+	// ladders (a gallery photo promoted to cover keeps its gallery renditions)
+	// are left alone.
+	if existingAsset != nil {
+		if err := s.repo.ReplaceDerivatives(ctx, asset.ID, asset.Version, RenditionPrefix(job.Category), derivatives); err != nil {
+			setAssetStatus(StatusFailed, err.Error())
+			return fmt.Errorf("save derivatives: %w", err)
+		}
+		// The ladder version tracks the asset's own ladder, not the ones
+		// it was promoted to
+		if LadderCategory(existingAsset.Category) == ladder.Category {
+			if err := s.repo.UpdateAssetLadderVersion(ctx, asset.ID, ladder.Version); err != nil {
+				slog.Warn("failed to record ladder version", "asset_id", asset.ID, "error", err)
+			}
+		}
+	} else {
+		for _, d := range derivatives {
+			if err := s.repo.CreateDerivative(ctx, d); err != nil {
+				slog.Warn("failed to save derivative record", "key", d.StorageKey, "error", err)
+				continue
+			}
 		}
 	}
 
 	// 7. Move original to permanent location
-	originalKey := fmt.Sprintf("originals/%s/%s/original", hashPrefix, validation.ContentHash)
+	originalKey := OriginalKey(validation.ContentHash)
 
 	if job.UploadKey != originalKey {
 		if err := s.r2Client.MoveObject(ctx, job.UploadKey, originalKey); err != nil {
@@ -463,6 +816,52 @@ func (s *Service) processJob(job *ProcessingJob) error {
 	return nil
 }
 
+// moderate runs the configured moderator and records the outcome on the asset
+func (s *Service) moderate(ctx context.Context, assetID uuid.UUID, data []byte) (ModerationStatus, error) {
+	if s.moderator == nil {
+		return ModerationUnchecked, nil
+	}
+
+	preview, err := s.processor.ModerationPreview(data)
+	if err != nil {
+		return "", fmt.Errorf("render moderation preview: %w", err)
+	}
+
+	result, err := s.moderator.Moderate(ctx, preview)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", s.moderator.Name(), err)
+	}
+
+	status := s.moderationPolicy.Evaluate(result)
+	score := result.Score
+	reason := strings.Join(result.Labels, ", ")
+	if err := s.repo.UpdateAssetModeration(ctx, assetID, status, &score, reason); err != nil {
+		return "", fmt.Errorf("record moderation result: %w", err)
+	}
+
+	if status != ModerationApproved {
+		slog.Warn("asset failed content moderation",
+			"asset_id", assetID, "provider", s.moderator.Name(), "status", status, "score", score, "labels", reason)
+	}
+	return status, nil
+}
+
+// quarantine moves the upload out of the processing path and finishes the job
+// without generating renditions
+func (s *Service) quarantine(ctx context.Context, job *ProcessingJob, asset *ImageAsset) error {
+	quarantineKey := QuarantineKey(asset.ContentHash)
+	if job.UploadKey != quarantineKey {
+		if err := s.r2Client.MoveObject(ctx, job.UploadKey, quarantineKey); err != nil {
+			slog.Warn("failed to move quarantined original", "asset_id", asset.ID, "error", err)
+		}
+	}
+
+	msg := "image rejected by content moderation"
+	s.repo.UpdateAssetStatus(ctx, asset.ID, StatusFailed, msg)
+	s.repo.UpdateJob(ctx, job.ID, StatusFailed, &asset.ID, job.Attempts, msg)
+	return nil
+}
+
 // handleJobFailure handles failed jobs with retry logic
 func (s *Service) handleJobFailure(job *ProcessingJob, err error) {
 	job.Attempts++
@@ -475,9 +874,10 @@ func (s *Service) handleJobFailure(job *ProcessingJob, err error) {
 		// Retry with exponential backoff
 		go func() {
 			time.Sleep(time.Duration(job.Attempts*job.Attempts) * time.Second)
-			select {
-			case s.jobQueue <- job:
-			default:
+			if s.stopped() {
+				return // Left pending for the next start
+			}
+			if !s.enqueue(job) {
 				slog.Error("failed to requeue job", "job_id", job.ID)
 			}
 		}()
@@ -489,8 +889,8 @@ func (s *Service) handleJobFailure(job *ProcessingJob, err error) {
 }
 
 // GetAsset retrieves an asset by content hash
-func (s *Service) GetAsset(contentHash string) (*ImageAsset, bool) {
-	asset, err := s.repo.GetAssetByHash(context.Background(), contentHash)
+func (s *Service) GetAsset(ctx context.Context, contentHash string) (*ImageAsset, bool) {
+	asset, err := s.repo.GetAssetByHash(ctx, contentHash)
 	if err != nil || asset == nil {
 		return nil, false
 	}
@@ -498,8 +898,8 @@ func (s *Service) GetAsset(contentHash string) (*ImageAsset, bool) {
 }
 
 // GetAssetByID retrieves an asset by ID
-func (s *Service) GetAssetByID(id uuid.UUID) (*ImageAsset, bool) {
-	asset, err := s.repo.GetAssetByID(context.Background(), id)
+func (s *Service) GetAssetByID(ctx context.Context, id uuid.UUID) (*ImageAsset, bool) {
+	asset, err := s.repo.GetAssetByID(ctx, id)
 	if err != nil {
 		slog.Error("GetAssetByID failed", "id", id, "error", err)
 		return nil, false
@@ -512,8 +912,8 @@ func (s *Service) GetAssetByID(id uuid.UUID) (*ImageAsset, bool) {
 }
 
 // GetJobByID retrieves a specific processing job by its ID
-func (s *Service) GetJobByID(id uuid.UUID) (*ProcessingJob, bool) {
-	job, err := s.repo.GetJobByID(context.Background(), id)
+func (s *Service) GetJobByID(ctx context.Context, id uuid.UUID) (*ProcessingJob, bool) {
+	job, err := s.repo.GetJobByID(ctx, id)
 	if err != nil {
 		slog.Error("GetJobByID failed", "id", id, "error", err)
 		return nil, false
@@ -534,8 +934,8 @@ func (s *Service) GetDerivativeURL(contentHash, renditionName string) string {
 
 // GetDerivativeKey returns the storage key for a specific derivative
 // This attempts to find the best format match for the rendition
-func (s *Service) GetDerivativeKey(contentHash, renditionName, preferredFormat string) (string, string, error) {
-	asset, err := s.repo.GetAssetByHash(context.Background(), contentHash)
+func (s *Service) GetDerivativeKey(ctx context.Context, contentHash, renditionName, preferredFormat string) (string, string, error) {
+	asset, err := s.lookups.get(ctx, contentHash, s.repo.GetAssetByHash)
 	if err != nil {
 		return "", "", fmt.Errorf("lookup failed: %w", err)
 	}
@@ -547,6 +947,10 @@ func (s *Service) GetDerivativeKey(contentHash, renditionName, preferredFormat s
 		return "", "", fmt.Errorf("asset not ready")
 	}
 
+	if asset.ModerationStatus.Blocked() {
+		return "", "", ErrAssetBlocked
+	}
+
 	if renditionName == "original" {
 		// Return original key
 		hashPrefix := contentHash[:2]
@@ -0,0 +1,70 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SniffBytes is how much of an upload's start is inspected before it is
+// queued. PDF readers accept a header anywhere in the first 1024 bytes.
+const SniffBytes = 1024
+
+// Reasons an upload's content is rejected
+const (
+	RejectSVG     = "svg"     // SVG or other XML markup
+	RejectPDF     = "pdf"     // PDF document, alone or embedded in an image
+	RejectScript  = "script"  // HTML, script or server-side code
+	RejectUnknown = "unknown" // Not a recognised image format
+)
+
+// ContentRejection is returned for uploads that are not plain images,
+// whatever their extension or declared content type
+type ContentRejection struct {
+	Reason string // One of the Reject* constants
+	Detail string // What was found, e.g. the matched marker
+}
+
+func (e *ContentRejection) Error() string {
+	return fmt.Sprintf("upload rejected (%s): %s", e.Reason, e.Detail)
+}
+
+// embeddedMarkers find active content hidden in an otherwise valid image
+// (polyglots). Markers are long enough not to occur by chance in compressed
+// image data, so whole files can be scanned.
+var embeddedMarkers = regexp.MustCompile(`(?i)<script|<\?php\s|<iframe|<!doctype|<html[\s>]|javascript:|%pdf-[12]\.`)
+
+// SniffUpload checks the magic bytes of data, the start of an upload or all
+// of it, and returns the detected image format. SVG, PDF, markup and scripts
+// are rejected, as are images carrying such content.
+func SniffUpload(data []byte) (string, error) {
+	head := data[:min(len(data), SniffBytes)]
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")), " \t\r\n")
+	lower := bytes.ToLower(trimmed[:min(len(trimmed), 256)])
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("%PDF-")):
+		return "", &ContentRejection{Reason: RejectPDF, Detail: "PDF document"}
+	case bytes.HasPrefix(trimmed, []byte("#!")):
+		return "", &ContentRejection{Reason: RejectScript, Detail: "script with interpreter line"}
+	case bytes.HasPrefix(lower, []byte("<?xml")) || bytes.Contains(lower, []byte("<svg")):
+		return "", &ContentRejection{Reason: RejectSVG, Detail: "SVG or XML document"}
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return "", &ContentRejection{Reason: RejectScript, Detail: "markup document"}
+	}
+
+	format := DetectFormat(head)
+	if format == "" {
+		return "", &ContentRejection{Reason: RejectUnknown, Detail: "no known image signature"}
+	}
+
+	if loc := embeddedMarkers.FindIndex(data); loc != nil {
+		marker := strings.ToLower(string(data[loc[0]:loc[1]]))
+		reason := RejectScript
+		if strings.HasPrefix(marker, "%pdf") {
+			reason = RejectPDF
+		}
+		return "", &ContentRejection{Reason: reason, Detail: fmt.Sprintf("%q embedded in %s at byte %d", marker, format, loc[0])}
+	}
+	return format, nil
+}
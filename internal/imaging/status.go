@@ -0,0 +1,148 @@
+package imaging
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// workerState is what a worker is doing; job is nil while it is idle. Closing
+// quit retires the worker.
+type workerState struct {
+	job      *ProcessingJob
+	since    time.Time
+	quit     chan struct{}
+	retiring bool
+}
+
+// WorkerStatus reports one worker of the pool
+type WorkerStatus struct {
+	ID          int        `json:"id"`
+	State       string     `json:"state"` // "busy", "idle" or "retiring"
+	JobID       *uuid.UUID `json:"job_id,omitempty"`
+	BusySeconds float64    `json:"busy_seconds,omitempty"`
+}
+
+// Status is a snapshot of the processing pipeline taken from memory
+type Status struct {
+	QueueDepth           int             `json:"queue_depth"`
+	QueueCapacity        int             `json:"queue_capacity"`
+	OldestPendingSeconds float64         `json:"oldest_pending_seconds"`
+	LastProcessedAt      *time.Time      `json:"last_processed_at,omitempty"`
+	Stopping             bool            `json:"stopping"`
+	TargetWorkers        int             `json:"target_workers"`
+	Workers              []WorkerStatus  `json:"workers"`
+	Decoders             map[string]bool `json:"decoders"` // Optional upload formats libvips can decode
+}
+
+// Status reports queue depth, worker activity, the age of the oldest queued
+// job and when a job last finished
+func (s *Service) Status() Status {
+	now := time.Now()
+	st := Status{
+		QueueDepth:    len(s.jobQueue),
+		QueueCapacity: cap(s.jobQueue),
+		Stopping:      s.stopped(),
+		Workers:       []WorkerStatus{},
+		Decoders:      Decoders(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st.TargetWorkers = s.workerCount
+	for _, created := range s.queued {
+		if age := now.Sub(created).Seconds(); age > st.OldestPendingSeconds {
+			st.OldestPendingSeconds = age
+		}
+	}
+	if !s.lastProcessed.IsZero() {
+		last := s.lastProcessed
+		st.LastProcessedAt = &last
+	}
+	for id, w := range s.workers {
+		ws := WorkerStatus{ID: id, State: "idle"}
+		if w.job != nil {
+			jobID := w.job.ID
+			ws.State = "busy"
+			ws.JobID = &jobID
+			ws.BusySeconds = now.Sub(w.since).Seconds()
+		}
+		if w.retiring {
+			ws.State = "retiring"
+		}
+		st.Workers = append(st.Workers, ws)
+	}
+	sort.Slice(st.Workers, func(i, j int) bool { return st.Workers[i].ID < st.Workers[j].ID })
+	return st
+}
+
+// enqueue hands a job to the workers without blocking and reports whether the
+// queue had room. Jobs that do not fit stay pending in the database.
+func (s *Service) enqueue(job *ProcessingJob) bool {
+	s.markQueued(job)
+	select {
+	case s.jobQueue <- job:
+		return true
+	default:
+		s.unmarkQueued(job)
+		return false
+	}
+}
+
+func (s *Service) markQueued(job *ProcessingJob) {
+	created := job.CreatedAt
+	if created.IsZero() {
+		created = time.Now()
+	}
+	s.mu.Lock()
+	s.queued[job.ID] = created
+	s.mu.Unlock()
+}
+
+func (s *Service) unmarkQueued(job *ProcessingJob) {
+	s.mu.Lock()
+	delete(s.queued, job.ID)
+	s.mu.Unlock()
+}
+
+func (s *Service) setBusy(workerID int, job *ProcessingJob) {
+	s.mu.Lock()
+	if w, ok := s.workers[workerID]; ok {
+		w.job, w.since = job, time.Now()
+	}
+	s.mu.Unlock()
+}
+
+func (s *Service) setIdle(workerID int) {
+	now := time.Now()
+	s.mu.Lock()
+	if w, ok := s.workers[workerID]; ok {
+		w.job, w.since = nil, now
+	}
+	s.lastProcessed = now
+	s.mu.Unlock()
+}
+
+// WaitIdle blocks until the queue is empty and every worker is idle, or ctx is done
+func (s *Service) WaitIdle(ctx context.Context) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		st := s.Status()
+		busy := false
+		for _, w := range st.Workers {
+			busy = busy || w.JobID != nil
+		}
+		if st.QueueDepth == 0 && !busy {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -114,11 +114,12 @@ func ValidateImage(data []byte, category string) (*ValidationResult, error) {
 		return result, errors.New(result.Error)
 	}
 
-	// 2. Detect format from magic bytes (NOT Content-Type header)
-	format := DetectFormat(data)
-	if format == "" {
-		result.Error = "unable to detect image format"
-		return result, errors.New(result.Error)
+	// 2. Detect format from magic bytes (NOT Content-Type header); SVG, PDF
+	// and scripts, including ones embedded in an image, are rejected
+	format, err := SniffUpload(data)
+	if err != nil {
+		result.Error = err.Error()
+		return result, err
 	}
 
 	if !AllowedFormats[format] {
@@ -128,6 +129,11 @@ func ValidateImage(data []byte, category string) (*ValidationResult, error) {
 
 	result.Format = format
 
+	if !CanDecode(format) {
+		result.Error = fmt.Sprintf("%s images are not supported by this server", format)
+		return result, fmt.Errorf("%w: %s", ErrFormatUnsupported, format)
+	}
+
 	// 3. Decode image metadata using libvips (fast header read)
 	srcParams := vips.NewImportParams()
 	srcParams.FailOnError.Set(true)
@@ -164,6 +170,15 @@ func ValidateImage(data []byte, category string) (*ValidationResult, error) {
 		return result, errors.New(result.Error)
 	}
 
+	// HEIC and AVIF headers load without the codec; decode the pixels so an
+	// unsupported file fails validation rather than rendition processing
+	if _, optional := optionalDecoders[format]; optional {
+		if err := verifyDecode(img, format); err != nil {
+			result.Error = err.Error()
+			return result, err
+		}
+	}
+
 	// 5. Compute content hash for deduplication
 	hash := sha256.Sum256(data)
 	result.ContentHash = hex.EncodeToString(hash[:])
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"strings"
@@ -56,3 +57,17 @@ func ParseLevelFromEnv() slog.Level {
 func L() *slog.Logger {
 	return slog.Default()
 }
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying the request ID, so code without
+// access to the HTTP request (e.g. repositories) can correlate its logs
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID carried by ctx, or ""
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,61 @@
+// Package mail sends templated notification emails (POI decisions, business
+// verification decisions and the saved-POI digest) through a pluggable provider.
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"maukemana-backend/internal/config"
+)
+
+// Message is one rendered email
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	Headers map[string]string // Extra headers such as List-Unsubscribe
+}
+
+// Sender delivers messages through an email provider
+type Sender interface {
+	// Name identifies the provider in logs
+	Name() string
+	Send(ctx context.Context, from string, msg Message) error
+}
+
+// NewFromConfig builds the configured sender. It returns nil when no provider is set.
+func NewFromConfig(cfg config.MailSettings) (Sender, error) {
+	if cfg.Provider != "" && cfg.Provider != "log" && cfg.From == "" {
+		return nil, errors.New("MAIL_FROM is required to send email")
+	}
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case "log":
+		return LogSender{}, nil
+	case "smtp":
+		return NewSMTPSender(cfg)
+	case "resend":
+		return NewResendSender(cfg)
+	case "ses":
+		return NewSESSender(cfg)
+	default:
+		return nil, fmt.Errorf("unknown mail provider %q", cfg.Provider)
+	}
+}
+
+// LogSender logs messages instead of sending them, for local development
+type LogSender struct{}
+
+// Name returns the provider name
+func (LogSender) Name() string { return "log" }
+
+// Send logs the message
+func (LogSender) Send(ctx context.Context, from string, msg Message) error {
+	slog.InfoContext(ctx, "email (not sent)", "from", from, "to", msg.To, "subject", msg.Subject, "text", msg.Text)
+	return nil
+}
@@ -0,0 +1,286 @@
+package mail
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"sync"
+	"time"
+
+	"maukemana-backend/internal/config"
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/services"
+
+	"github.com/google/uuid"
+)
+
+const (
+	queueSize           = 256
+	sendTimeout         = 30 * time.Second
+	digestCheckInterval = time.Hour
+	digestBatchSize     = 100
+	digestItemLimit     = 10
+)
+
+// footers explain at the bottom of each email why it was sent
+var footers = map[string]string{
+	models.EmailPOIStatus:    "You receive this because you submitted this place.",
+	models.EmailVerification: "You receive this because you asked to verify this business.",
+	models.EmailSavedDigest:  "You receive this digest because you saved these places.",
+}
+
+// Store is the persistence the notifier needs
+type Store interface {
+	GetRecipient(ctx context.Context, userID uuid.UUID) (*models.EmailRecipient, error)
+	GetPOIOwnerRecipient(ctx context.Context, poiID uuid.UUID) (*models.EmailRecipient, string, error)
+	ClaimDigestRecipients(ctx context.Context, interval time.Duration, limit int) ([]models.DigestRecipient, error)
+	ListDigestItems(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]models.DigestItem, error)
+}
+
+// Notifier renders notification emails and sends them in the background.
+// Delivery is best effort: messages still queued at shutdown and messages
+// that fail to send are logged and dropped.
+type Notifier struct {
+	sender         Sender
+	store          Store
+	tokens         *Tokens // nil disables unsubscribe links
+	from           string
+	appURL         string
+	apiURL         string
+	digestInterval time.Duration
+
+	queue  chan Message
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewNotifier creates a notifier sending through sender
+func NewNotifier(sender Sender, store Store, tokens *Tokens, cfg config.MailSettings) *Notifier {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Notifier{
+		sender:         sender,
+		store:          store,
+		tokens:         tokens,
+		from:           cfg.From,
+		appURL:         cfg.AppURL,
+		apiURL:         cfg.APIURL,
+		digestInterval: cfg.DigestInterval,
+		queue:          make(chan Message, queueSize),
+		ctx:            ctx,
+		cancel:         cancel,
+	}
+}
+
+// Start begins sending queued messages and, unless disabled, saved-POI digests
+func (n *Notifier) Start() {
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		for {
+			select {
+			case <-n.ctx.Done():
+				if left := len(n.queue); left > 0 {
+					slog.Warn("dropping unsent emails on shutdown", "count", left)
+				}
+				return
+			case msg := <-n.queue:
+				n.deliver(msg)
+			}
+		}
+	}()
+
+	if n.digestInterval <= 0 {
+		return
+	}
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		ticker := time.NewTicker(digestCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-n.ctx.Done():
+				return
+			case <-ticker.C:
+				n.sendDigests()
+			}
+		}
+	}()
+}
+
+// Stop waits for the message being sent and stops the notifier
+func (n *Notifier) Stop() {
+	n.cancel()
+	n.wg.Wait()
+}
+
+// POIStatusChanged is a services.TransitionHook emailing the submitter when a
+// pending POI is approved or rejected by someone else, or any POI of theirs is
+// taken down. Takedown notices are sent even to users who opted out.
+func (n *Notifier) POIStatusChanged(ctx context.Context, event services.TransitionEvent) error {
+	var tmpl string
+	switch {
+	case event.To == services.POIStatusTakenDown:
+		tmpl = tmplPOITakenDown
+	case event.From != services.POIStatusPending:
+		return nil
+	case event.To == services.POIStatusApproved:
+		tmpl = tmplPOIApproved
+	case event.To == services.POIStatusRejected:
+		tmpl = tmplPOIRejected
+	default:
+		return nil
+	}
+
+	r, poiName, err := n.store.GetPOIOwnerRecipient(ctx, event.PoiID)
+	if err != nil {
+		return err
+	}
+	if r == nil || r.UserID == event.Actor.UserID {
+		return nil
+	}
+
+	data := map[string]interface{}{"POIName": poiName, "POIURL": n.poiURL(event.PoiID)}
+	if tmpl == tmplPOITakenDown {
+		data["TakedownReason"] = *event.Reason
+		return n.enqueue(r, models.EmailPOIStatus, tmpl, data)
+	}
+	if event.Reason != nil {
+		data["RejectionReason"] = *event.Reason
+	}
+	return n.notify(r, models.EmailPOIStatus, tmpl, data)
+}
+
+// VerificationDecided emails the requester the outcome of a business verification
+func (n *Notifier) VerificationDecided(ctx context.Context, v *models.BusinessVerification) error {
+	var tmpl string
+	switch v.Status {
+	case "approved":
+		tmpl = tmplVerificationApproved
+	case "rejected":
+		tmpl = tmplVerificationRejected
+	default:
+		return nil
+	}
+
+	r, err := n.store.GetRecipient(ctx, v.RequesterID)
+	if err != nil || r == nil {
+		return err
+	}
+
+	data := map[string]interface{}{"POIName": "your place", "POIURL": n.poiURL(v.PoiID)}
+	if v.PoiName != nil {
+		data["POIName"] = *v.PoiName
+	}
+	if v.ReviewNote != nil {
+		data["ReviewNote"] = *v.ReviewNote
+	}
+	return n.notify(r, models.EmailVerification, tmpl, data)
+}
+
+// notify queues a message for r unless they opted out of category
+func (n *Notifier) notify(r *models.EmailRecipient, category, tmpl string, data map[string]interface{}) error {
+	if !r.Allows(category) {
+		return nil
+	}
+	return n.enqueue(r, category, tmpl, data)
+}
+
+// enqueue queues a message for r regardless of their preferences
+func (n *Notifier) enqueue(r *models.EmailRecipient, category, tmpl string, data map[string]interface{}) error {
+	msg, err := n.compose(r.UserID, r.Email, r.Name, category, tmpl, data)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case n.queue <- msg:
+	default:
+		slog.Warn("email queue full, dropping message", "template", tmpl, "user_id", r.UserID)
+	}
+	return nil
+}
+
+// compose renders a message with the greeting, footer and unsubscribe links
+func (n *Notifier) compose(userID uuid.UUID, email string, name *string, category, tmpl string, data map[string]interface{}) (Message, error) {
+	data["Name"] = "there"
+	if name != nil && *name != "" {
+		data["Name"] = *name
+	}
+	data["Footer"] = footers[category]
+	data["PreferencesURL"] = n.appURL + "/settings/notifications"
+	data["UnsubscribeURL"] = data["PreferencesURL"]
+
+	headers := map[string]string{}
+	if n.tokens != nil && n.apiURL != "" {
+		link := n.apiURL + "/api/v1/email/unsubscribe?token=" + url.QueryEscape(n.tokens.Sign(userID, category))
+		data["UnsubscribeURL"] = link
+		// RFC 8058 one-click unsubscribe
+		headers["List-Unsubscribe"] = "<" + link + ">"
+		headers["List-Unsubscribe-Post"] = "List-Unsubscribe=One-Click"
+	}
+
+	msg, err := render(tmpl, email, data)
+	if err != nil {
+		return Message{}, err
+	}
+	msg.Headers = headers
+	return msg, nil
+}
+
+func (n *Notifier) deliver(msg Message) {
+	ctx, cancel := context.WithTimeout(n.ctx, sendTimeout)
+	defer cancel()
+	if err := n.sender.Send(ctx, n.from, msg); err != nil {
+		slog.Error("send email failed", "provider", n.sender.Name(), "subject", msg.Subject, "error", err)
+		return
+	}
+	slog.Debug("email sent", "provider", n.sender.Name(), "subject", msg.Subject)
+}
+
+// sendDigests emails every due user the saved POIs updated since their last digest
+func (n *Notifier) sendDigests() {
+	sent := 0
+	for n.ctx.Err() == nil {
+		recipients, err := n.store.ClaimDigestRecipients(n.ctx, n.digestInterval, digestBatchSize)
+		if err != nil {
+			slog.Error("claim digest recipients failed", "error", err)
+			return
+		}
+		if len(recipients) == 0 {
+			break
+		}
+
+		for _, r := range recipients {
+			items, err := n.store.ListDigestItems(n.ctx, r.UserID, r.Since, digestItemLimit)
+			if err != nil {
+				slog.Error("list digest items failed", "user_id", r.UserID, "error", err)
+				continue
+			}
+			if len(items) == 0 {
+				continue
+			}
+
+			entries := make([]map[string]string, len(items))
+			for i, item := range items {
+				entries[i] = map[string]string{"Name": item.Name, "URL": n.poiURL(item.PoiID)}
+			}
+			data := map[string]interface{}{"Items": entries, "Total": items[0].Total, "More": items[0].Total - len(items)}
+			msg, err := n.compose(r.UserID, r.Email, r.Name, models.EmailSavedDigest, tmplSavedDigest, data)
+			if err != nil {
+				slog.Error("render digest failed", "user_id", r.UserID, "error", err)
+				continue
+			}
+			n.deliver(msg)
+			sent++
+		}
+	}
+	if sent > 0 {
+		slog.Info("saved-POI digests sent", "count", sent)
+	}
+}
+
+func (n *Notifier) poiURL(poiID uuid.UUID) string {
+	return n.appURL + "/pois/" + poiID.String()
+}
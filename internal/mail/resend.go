@@ -0,0 +1,73 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"maukemana-backend/internal/config"
+)
+
+const resendURL = "https://api.resend.com/emails"
+
+// ResendSender sends through the Resend HTTP API
+type ResendSender struct {
+	httpClient *http.Client
+	apiKey     string
+}
+
+// NewResendSender creates a Resend sender from settings
+func NewResendSender(cfg config.MailSettings) (*ResendSender, error) {
+	if cfg.ResendAPIKey == "" {
+		return nil, errors.New("RESEND_API_KEY is required for the resend mail provider")
+	}
+	return &ResendSender{httpClient: &http.Client{Timeout: 15 * time.Second}, apiKey: cfg.ResendAPIKey}, nil
+}
+
+// Name returns the provider name
+func (s *ResendSender) Name() string { return "resend" }
+
+// Send posts msg to the Resend API
+func (s *ResendSender) Send(ctx context.Context, from string, msg Message) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"from":    from,
+		"to":      []string{msg.To},
+		"subject": msg.Subject,
+		"text":    msg.Text,
+		"html":    msg.HTML,
+		"headers": msg.Headers,
+	})
+	if err != nil {
+		return fmt.Errorf("encode resend request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, resendURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build resend request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+
+	return doMailRequest(s.httpClient, req, "resend")
+}
+
+// doMailRequest sends req and turns non-2xx responses into errors
+func doMailRequest(client *http.Client, req *http.Request, provider string) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s returned %d: %s", provider, resp.StatusCode, body)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,101 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"maukemana-backend/internal/config"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// SESSender sends through the Amazon SES v2 SendEmail API
+type SESSender struct {
+	region     string
+	creds      aws.CredentialsProvider
+	signer     *v4.Signer
+	httpClient *http.Client
+}
+
+// NewSESSender creates an SES sender from settings and AWS_* credentials
+func NewSESSender(cfg config.MailSettings) (*SESSender, error) {
+	accessKeyID := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretAccessKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if cfg.SESRegion == "" || accessKeyID == "" || secretAccessKey == "" {
+		return nil, errors.New("missing AWS configuration for the ses mail provider")
+	}
+	return &SESSender{
+		region:     cfg.SESRegion,
+		creds:      credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, os.Getenv("AWS_SESSION_TOKEN")),
+		signer:     v4.NewSigner(),
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// Name returns the provider name
+func (s *SESSender) Name() string { return "ses" }
+
+// Send calls SendEmail with a simple (text and HTML) message
+func (s *SESSender) Send(ctx context.Context, from string, msg Message) error {
+	type content struct {
+		Data    string `json:"Data"`
+		Charset string `json:"Charset"`
+	}
+	type header struct {
+		Name  string `json:"Name"`
+		Value string `json:"Value"`
+	}
+	body := map[string]interface{}{"Text": content{msg.Text, "UTF-8"}}
+	if msg.HTML != "" {
+		body["Html"] = content{msg.HTML, "UTF-8"}
+	}
+	headers := make([]header, 0, len(msg.Headers))
+	for k, v := range msg.Headers {
+		headers = append(headers, header{k, v})
+	}
+	sort.Slice(headers, func(i, j int) bool { return headers[i].Name < headers[j].Name })
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"FromEmailAddress": from,
+		"Destination":      map[string][]string{"ToAddresses": {msg.To}},
+		"Content": map[string]interface{}{
+			"Simple": map[string]interface{}{
+				"Subject": content{msg.Subject, "UTF-8"},
+				"Body":    body,
+				"Headers": headers,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("encode ses request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("https://email.%s.amazonaws.com/v2/email/outbound-emails", s.region)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build ses request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	creds, err := s.creds.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("retrieve aws credentials: %w", err)
+	}
+	payloadHash := sha256.Sum256(payload)
+	if err := s.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(payloadHash[:]), "ses", s.region, time.Now()); err != nil {
+		return fmt.Errorf("sign ses request: %w", err)
+	}
+
+	return doMailRequest(s.httpClient, req, "ses")
+}
@@ -0,0 +1,121 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"time"
+
+	"maukemana-backend/internal/config"
+)
+
+// SMTPSender sends through an SMTP relay, upgrading to TLS with STARTTLS when offered
+type SMTPSender struct {
+	addr string
+	host string
+	auth smtp.Auth
+}
+
+// NewSMTPSender creates an SMTP sender from settings
+func NewSMTPSender(cfg config.MailSettings) (*SMTPSender, error) {
+	if cfg.SMTPHost == "" {
+		return nil, errors.New("SMTP_HOST is required for the smtp mail provider")
+	}
+	s := &SMTPSender{addr: net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort)), host: cfg.SMTPHost}
+	if cfg.SMTPUsername != "" {
+		s.auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+	return s, nil
+}
+
+// Name returns the provider name
+func (s *SMTPSender) Name() string { return "smtp" }
+
+// Send delivers msg as a multipart/alternative email
+func (s *SMTPSender) Send(ctx context.Context, from string, msg Message) error {
+	sender, err := netmail.ParseAddress(from)
+	if err != nil {
+		return fmt.Errorf("parse sender address: %w", err)
+	}
+	if _, err := netmail.ParseAddress(msg.To); err != nil {
+		return fmt.Errorf("parse recipient address: %w", err)
+	}
+	body, err := buildMIME(from, msg)
+	if err != nil {
+		return err
+	}
+
+	// net/smtp has no context support; bound the whole exchange instead
+	done := make(chan error, 1)
+	go func() { done <- smtp.SendMail(s.addr, s.auth, sender.Address, []string{msg.To}, body) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("smtp send: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// buildMIME renders msg with a plain text and an HTML alternative
+func buildMIME(from string, msg Message) ([]byte, error) {
+	var boundary [12]byte
+	if _, err := rand.Read(boundary[:]); err != nil {
+		return nil, fmt.Errorf("generate mime boundary: %w", err)
+	}
+	b := "alt-" + hex.EncodeToString(boundary[:])
+
+	var buf bytes.Buffer
+	headers := map[string]string{
+		"From":         from,
+		"To":           msg.To,
+		"Subject":      mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date":         time.Now().Format(time.RFC1123Z),
+		"MIME-Version": "1.0",
+		"Content-Type": fmt.Sprintf("multipart/alternative; boundary=%q", b),
+	}
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "%s: %s\r\n", k, headers[k])
+	}
+	buf.WriteString("\r\n")
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		if part.content == "" {
+			continue
+		}
+		fmt.Fprintf(&buf, "--%s\r\nContent-Type: %s\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n", b, part.contentType)
+		w := quotedprintable.NewWriter(&buf)
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			return nil, fmt.Errorf("encode mime part: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("encode mime part: %w", err)
+		}
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", b)
+	return buf.Bytes(), nil
+}
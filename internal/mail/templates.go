@@ -0,0 +1,72 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Message templates. Each has templates/<name>.txt, defining "subject" and
+// the plain text body, and templates/<name>.html, defining "content" for
+// templates/layout.html.
+const (
+	tmplPOIApproved          = "poi_approved"
+	tmplPOIRejected          = "poi_rejected"
+	tmplPOITakenDown         = "poi_taken_down"
+	tmplVerificationApproved = "verification_approved"
+	tmplVerificationRejected = "verification_rejected"
+	tmplSavedDigest          = "saved_digest"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+type messageTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+var messageTemplates = mustLoadTemplates(
+	tmplPOIApproved, tmplPOIRejected, tmplPOITakenDown, tmplVerificationApproved, tmplVerificationRejected, tmplSavedDigest,
+)
+
+// mustLoadTemplates parses the embedded templates; a broken template is a
+// programming error, so it panics at startup
+func mustLoadTemplates(names ...string) map[string]messageTemplate {
+	out := make(map[string]messageTemplate, len(names))
+	for _, name := range names {
+		text := texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/"+name+".txt"))
+		html := htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/layout.html", "templates/"+name+".html"))
+		out[name] = messageTemplate{text: text, html: html}
+	}
+	return out
+}
+
+// render executes the named template into a message for to
+func render(name, to string, data map[string]interface{}) (Message, error) {
+	t, ok := messageTemplates[name]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := t.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("render %s subject: %w", name, err)
+	}
+	if err := t.text.Execute(&text, data); err != nil {
+		return Message{}, fmt.Errorf("render %s text: %w", name, err)
+	}
+	if err := t.html.ExecuteTemplate(&html, "layout", data); err != nil {
+		return Message{}, fmt.Errorf("render %s html: %w", name, err)
+	}
+
+	return Message{
+		To:      to,
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,65 @@
+package mail
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strings"
+
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidToken is returned for malformed or forged unsubscribe tokens
+var ErrInvalidToken = errors.New("invalid unsubscribe token")
+
+// Tokens signs the unsubscribe links put in emails. A token names one user and
+// one category and does not expire, so links in old emails keep working.
+type Tokens struct {
+	secret []byte
+}
+
+// NewTokens creates a token signer; it returns nil without a secret
+func NewTokens(secret string) *Tokens {
+	if secret == "" {
+		return nil
+	}
+	return &Tokens{secret: []byte(secret)}
+}
+
+// Sign returns the token unsubscribing userID from category
+func (t *Tokens) Sign(userID uuid.UUID, category string) string {
+	payload := userID.String() + "." + category
+	return payload + "." + t.mac(payload)
+}
+
+// Verify returns the user and category a token unsubscribes
+func (t *Tokens) Verify(token string) (uuid.UUID, string, error) {
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 {
+		return uuid.Nil, "", ErrInvalidToken
+	}
+	payload, sig := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(sig), []byte(t.mac(payload))) {
+		return uuid.Nil, "", ErrInvalidToken
+	}
+
+	id, category, ok := strings.Cut(payload, ".")
+	if !ok {
+		return uuid.Nil, "", ErrInvalidToken
+	}
+	userID, err := uuid.Parse(id)
+	if err != nil || !slices.Contains(models.EmailCategories, category) {
+		return uuid.Nil, "", ErrInvalidToken
+	}
+	return userID, category, nil
+}
+
+func (t *Tokens) mac(payload string) string {
+	h := hmac.New(sha256.New, t.secret)
+	h.Write([]byte("unsubscribe:" + payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
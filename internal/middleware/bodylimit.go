@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit caps request bodies at defaultBytes, or at the limit listed in
+// routes under "METHOD /route/template" (e.g. "PUT /api/v1/pois/:id/menu").
+// Requests that declare a larger Content-Length are rejected with 413 before
+// the handler runs; bodies sent without one fail to read past the limit.
+func BodyLimit(defaultBytes int64, routes map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := defaultBytes
+		if l, ok := routes[c.Request.Method+" "+c.FullPath()]; ok {
+			limit = l
+		}
+
+		if c.Request.ContentLength > limit {
+			utils.SendError(c, http.StatusRequestEntityTooLarge, "request body too large", nil)
+			return
+		}
+		if c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"maukemana-backend/internal/captcha"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CaptchaHeader carries the Turnstile or reCAPTCHA token solved by the client
+const CaptchaHeader = "X-Captcha-Token"
+
+// CaptchaVerifier checks a bot protection token server-side
+type CaptchaVerifier interface {
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+// Captcha requires a valid token in the X-Captcha-Token header on the routes
+// listed as "METHOD /route/template" (e.g. "POST /api/v1/pois/:id/comments").
+// Other routes pass through. When the provider cannot be reached the request
+// is refused with 503 rather than let through unchecked.
+func Captcha(verifier CaptchaVerifier, routes []string) gin.HandlerFunc {
+	protected := make(map[string]bool, len(routes))
+	for _, r := range routes {
+		protected[r] = true
+	}
+
+	return func(c *gin.Context) {
+		if !protected[c.Request.Method+" "+c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		token := c.GetHeader(CaptchaHeader)
+		if token == "" {
+			utils.SendError(c, http.StatusForbidden, "captcha token required", nil)
+			return
+		}
+		err := verifier.Verify(c.Request.Context(), token, c.ClientIP())
+		switch {
+		case errors.Is(err, captcha.ErrRejected):
+			utils.SendError(c, http.StatusForbidden, "captcha verification failed", err)
+			return
+		case err != nil:
+			slog.ErrorContext(c.Request.Context(), "captcha verification unavailable", "error", err)
+			utils.SendError(c, http.StatusServiceUnavailable, "captcha verification unavailable, please retry", nil)
+			return
+		}
+		c.Next()
+	}
+}
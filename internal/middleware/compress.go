@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// compressibleTypes are the response media types worth compressing; images,
+// video and archives are already compressed
+var compressibleTypes = map[string]bool{
+	"application/json":         true,
+	"application/geo+json":     true,
+	"application/graphql+json": true,
+	"application/javascript":   true,
+	"application/xml":          true,
+	"image/svg+xml":            true,
+	"text/plain":               true,
+	"text/html":                true,
+	"text/css":                 true,
+	"text/csv":                 true,
+	"text/xml":                 true,
+}
+
+// Compress gzips responses of a compressible content type once they reach
+// minSize bytes, for clients that accept gzip. Smaller responses are sent as
+// they are, since the gzip framing would outweigh the savings.
+func Compress(level, minSize int) gin.HandlerFunc {
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		level = gzip.DefaultCompression
+	}
+	pool := sync.Pool{New: func() interface{} {
+		gz, _ := gzip.NewWriterLevel(io.Discard, level)
+		return gz
+	}}
+
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		w := &gzipWriter{ResponseWriter: c.Writer, pool: &pool, minSize: minSize}
+		c.Writer = w
+		defer func() {
+			w.close()
+			c.Writer = w.ResponseWriter
+		}()
+		c.Next()
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") && strings.TrimSpace(coding) != "*" {
+			continue
+		}
+		return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+	}
+	return false
+}
+
+// gzipWriter holds back the start of the body until it knows whether the
+// response is large enough to compress
+type gzipWriter struct {
+	gin.ResponseWriter
+	pool    *sync.Pool
+	minSize int
+
+	buf     []byte
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.buf = append(w.buf, data...)
+		if len(w.buf) < w.minSize {
+			return len(data), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+	if w.gz != nil {
+		return w.gz.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteHeaderNow is deferred until the encoding is decided; gin sends the
+// headers of bodiless responses itself once the handlers return
+func (w *gzipWriter) WriteHeaderNow() {
+	if w.decided {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush sends what is buffered, compressed if the response qualifies by type
+func (w *gzipWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(true)
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide picks compression for the response, if large is set and the
+// headers allow it, then writes out the buffered start of the body
+func (w *gzipWriter) decide(large bool) error {
+	w.decided = true
+	h := w.Header()
+
+	mediaType, _, _ := mime.ParseMediaType(h.Get("Content-Type"))
+	eligible := compressibleTypes[mediaType] && h.Get("Content-Encoding") == "" && h.Get("Content-Range") == ""
+	switch w.Status() {
+	case http.StatusNoContent, http.StatusNotModified, http.StatusPartialContent:
+		eligible = false
+	}
+	if eligible {
+		h.Add("Vary", "Accept-Encoding")
+	}
+
+	if eligible && large {
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+		// The compressed bytes differ, so a strong validator becomes weak
+		if etag := h.Get("ETag"); strings.HasPrefix(etag, `"`) {
+			h.Set("ETag", "W/"+etag)
+		}
+		w.gz = w.pool.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+		_, err := w.gz.Write(w.buf)
+		w.buf = nil
+		return err
+	}
+
+	var err error
+	if len(w.buf) > 0 {
+		_, err = w.ResponseWriter.Write(w.buf)
+	}
+	w.buf = nil
+	return err
+}
+
+// close sends a response that stayed below the threshold, or finishes the gzip stream
+func (w *gzipWriter) close() {
+	if !w.decided {
+		if len(w.buf) == 0 {
+			return
+		}
+		_ = w.decide(false)
+	}
+	if w.gz != nil {
+		_ = w.gz.Close()
+		w.gz.Reset(io.Discard)
+		w.pool.Put(w.gz)
+		w.gz = nil
+	}
+}
+
+// DecompressBody accepts gzip-encoded request bodies on the routes listed as
+// "METHOD /route/template", capping the decompressed size at the listed limit.
+// Other routes reject encoded bodies with 415. It must run after BodyLimit,
+// which caps the compressed size.
+func DecompressBody(routes map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		encoding := strings.ToLower(strings.TrimSpace(c.GetHeader("Content-Encoding")))
+		if encoding == "" || encoding == "identity" || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+
+		limit, ok := routes[c.Request.Method+" "+c.FullPath()]
+		if !ok || encoding != "gzip" {
+			utils.SendError(c, http.StatusUnsupportedMediaType, "unsupported Content-Encoding: "+encoding, nil)
+			return
+		}
+		gz, err := gzip.NewReader(c.Request.Body)
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "invalid gzip body", err)
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, &gzipBody{Reader: gz, body: c.Request.Body}, limit)
+		c.Request.Header.Del("Content-Encoding")
+		c.Request.Header.Del("Content-Length")
+		c.Request.ContentLength = -1
+		c.Next()
+	}
+}
+
+// gzipBody closes both the gzip stream and the underlying request body
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Optional subsystems, reported by /api and /health
+const (
+	FeatureUploads       = "uploads"       // Object storage and the image pipeline
+	FeatureGeocoding     = "geocoding"     // Reverse geocoding of submitted POIs
+	FeatureNotifications = "notifications" // Notification emails
+)
+
+// Features records which optional subsystems are configured. It is filled in
+// while the router is built and only read afterwards.
+type Features map[string]bool
+
+// RequireFeature rejects requests with 503 Service Unavailable while feature
+// is disabled, so its routes exist on every server and answer consistently
+func RequireFeature(features Features, feature string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !features[feature] {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, utils.Response{
+				Success: false,
+				Message: feature + " is not available on this server",
+				Error:   gin.H{"code": "feature_unavailable", "feature": feature},
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+// Locale negotiates the content language for the request and stores it in the
+// context under "locale", next to "default_locale". An explicit ?lang= wins over
+// Accept-Language; anything unsupported falls back to the first entry of
+// supported (the default locale).
+func Locale(supported []string) gin.HandlerFunc {
+	tags := make([]language.Tag, 0, len(supported))
+	for _, l := range supported {
+		tags = append(tags, language.Make(l))
+	}
+	matcher := language.NewMatcher(tags)
+	defaultLocale := supported[0]
+
+	return func(c *gin.Context) {
+		locale := defaultLocale
+
+		var desired []language.Tag
+		if lang := c.Query("lang"); lang != "" {
+			if tag, err := language.Parse(lang); err == nil {
+				desired = []language.Tag{tag}
+			}
+		} else if header := c.GetHeader("Accept-Language"); header != "" {
+			desired, _, _ = language.ParseAcceptLanguage(header)
+		}
+
+		if len(desired) > 0 {
+			if _, idx, conf := matcher.Match(desired...); conf != language.No {
+				locale = supported[idx]
+			}
+		}
+
+		c.Set("locale", locale)
+		c.Set("default_locale", defaultLocale)
+		c.Header("Vary", "Accept-Language")
+		c.Next()
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var meter = otel.Meter("maukemana-backend/internal/middleware")
+
+// HTTPMetrics records request counts and latencies per route, method and status
+func HTTPMetrics() gin.HandlerFunc {
+	requests, err := meter.Int64Counter("http.server.requests",
+		metric.WithDescription("HTTP requests handled"),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		slog.Warn("failed to create http request counter", "error", err)
+	}
+	duration, err := meter.Float64Histogram("http.server.duration",
+		metric.WithDescription("HTTP request latency"),
+		metric.WithUnit("s"))
+	if err != nil {
+		slog.Warn("failed to create http duration histogram", "error", err)
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		// Use the route template to keep cardinality bounded
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		attrs := metric.WithAttributes(
+			attribute.String("http.route", route),
+			attribute.String("http.method", c.Request.Method),
+			attribute.String("http.status_code", strconv.Itoa(c.Writer.Status())),
+		)
+
+		ctx := c.Request.Context()
+		if requests != nil {
+			requests.Add(ctx, 1, attrs)
+		}
+		if duration != nil {
+			duration.Record(ctx, time.Since(start).Seconds(), attrs)
+		}
+	}
+}
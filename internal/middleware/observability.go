@@ -14,6 +14,7 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
 
+	"maukemana-backend/internal/logger"
 	"maukemana-backend/internal/utils"
 )
 
@@ -36,6 +37,7 @@ func Observability() gin.HandlerFunc {
 		}
 		c.Header("X-Request-ID", requestID)
 		c.Set("request_id", requestID)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestID))
 
 		// 2. Trace/Span extraction from context (set by otelgin in router)
 		span := trace.SpanFromContext(c.Request.Context())
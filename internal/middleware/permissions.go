@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+
+	"maukemana-backend/internal/services"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission rejects requests whose caller lacks any of perms. It reads
+// the permission set the auth middleware stores under "permissions", so it must
+// run after authentication.
+func RequirePermission(perms ...services.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("user_id"); !ok {
+			utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+			return
+		}
+		granted, _ := c.Get("permissions")
+		set, _ := granted.(services.PermissionSet)
+		for _, p := range perms {
+			if !set.Has(p) {
+				utils.SendError(c, http.StatusForbidden, "missing permission: "+string(p), nil)
+				return
+			}
+		}
+		c.Next()
+	}
+}
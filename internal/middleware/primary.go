@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"maukemana-backend/internal/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadFromPrimary sends the reads of state-changing requests to the primary
+// database, so the ownership checks before a write and the reloads after it
+// never see a replica that is lagging behind
+func ReadFromPrimary() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			c.Request = c.Request.WithContext(database.ReadFromPrimary(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
@@ -7,7 +7,10 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/metric"
 	"golang.org/x/time/rate"
+
+	"maukemana-backend/internal/utils"
 )
 
 // IPRateLimiter manages rate limiters for each IP address
@@ -81,13 +84,20 @@ func RateLimit() gin.HandlerFunc {
 	// 20 requests per second, burst of 50
 	limiter := NewIPRateLimiter(20, 50)
 
+	rejections, err := meter.Int64Counter("ratelimit.rejections",
+		metric.WithDescription("Requests rejected by the IP rate limiter"),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		log.Printf("Warning: failed to create rate limit counter: %v", err)
+	}
+
 	return func(c *gin.Context) {
 		ip := c.ClientIP()
 		if !limiter.GetLimiter(ip).Allow() {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"status":  "error",
-				"message": "Too many requests",
-			})
+			if rejections != nil {
+				rejections.Add(c.Request.Context(), 1)
+			}
+			utils.SendError(c, http.StatusTooManyRequests, "Too many requests", nil)
 			return
 		}
 		c.Next()
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TermsChecker tells which terms version is current and which one a user accepted
+type TermsChecker interface {
+	CurrentVersion(ctx context.Context) (*models.TermsVersion, error)
+	AcceptedVersion(ctx context.Context, userID uuid.UUID) (*int, error)
+}
+
+// RequireTerms rejects state-changing requests from users who have not
+// accepted the current terms version with 403 and the code
+// "terms_not_accepted". Reads pass, as does everything while no version is
+// published. It must run after authentication.
+func RequireTerms(terms TermsChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		userID, ok := c.Get("user_id")
+		if !ok {
+			utils.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+			return
+		}
+
+		current, err := terms.CurrentVersion(c.Request.Context())
+		if err != nil {
+			utils.SendInternalError(c, err)
+			return
+		}
+		if current == nil {
+			c.Next()
+			return
+		}
+		accepted, err := terms.AcceptedVersion(c.Request.Context(), userID.(uuid.UUID))
+		if err != nil {
+			utils.SendInternalError(c, err)
+			return
+		}
+		if accepted == nil || *accepted < current.Version {
+			c.AbortWithStatusJSON(http.StatusForbidden, utils.Response{
+				Success: false,
+				Message: "accept the current terms of service to continue",
+				Error:   gin.H{"code": "terms_not_accepted", "current_version": current.Version},
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"maukemana-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout bounds each request's context at defaultTimeout, or at the duration
+// listed in routes under "METHOD /route/template"; 0 means no bound (e.g. for
+// streamed downloads). Queries running on the request context are cancelled
+// when it expires, and a handler that gives up without responding gets a 504.
+// It must run before Compress so the response written by the handler is seen.
+func Timeout(defaultTimeout time.Duration, routes map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := defaultTimeout
+		if t, ok := routes[c.Request.Method+" "+c.FullPath()]; ok {
+			timeout = t
+		}
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			utils.SendError(c, http.StatusGatewayTimeout, "request timed out", ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Data export states
+const (
+	ExportPending    = "pending"
+	ExportProcessing = "processing"
+	ExportReady      = "ready"
+	ExportFailed     = "failed"
+)
+
+// Notification types
+const (
+	NotificationDataExportReady  = "data_export.ready"
+	NotificationSavedSearchMatch = "saved_search.match"
+	NotificationPOITakenDown     = "poi.taken_down"
+	NotificationQuestionAsked    = "question.asked"
+	NotificationQuestionAnswered = "question.answered"
+	NotificationAnswerAccepted   = "answer.accepted"
+)
+
+// DataExport is a user's request for a copy of their personal data
+type DataExport struct {
+	ExportID    uuid.UUID  `db:"export_id" json:"export_id"`
+	UserID      uuid.UUID  `db:"user_id" json:"user_id"`
+	Status      string     `db:"status" json:"status"`
+	SizeBytes   *int64     `db:"size_bytes" json:"size_bytes,omitempty"`
+	Error       *string    `db:"error" json:"error,omitempty"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	StartedAt   *time.Time `db:"started_at" json:"started_at,omitempty"`
+	CompletedAt *time.Time `db:"completed_at" json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `db:"expires_at" json:"expires_at,omitempty"`
+}
+
+// Notification is an in-app message for a user
+type Notification struct {
+	NotificationID uuid.UUID       `db:"notification_id" json:"notification_id"`
+	UserID         uuid.UUID       `db:"user_id" json:"user_id"`
+	Type           string          `db:"type" json:"type"`
+	Title          string          `db:"title" json:"title"`
+	Body           *string         `db:"body" json:"body,omitempty"`
+	Data           json.RawMessage `db:"data" json:"data"`
+	ReadAt         *time.Time      `db:"read_at" json:"read_at,omitempty"`
+	CreatedAt      time.Time       `db:"created_at" json:"created_at"`
+}
+
+// LinkCandidate is another account registered with one of the caller's
+// verified email addresses, likely created under a previous sign-in identity
+type LinkCandidate struct {
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	Email       string    `db:"email" json:"email"`
+	Name        *string   `db:"name" json:"name,omitempty"`
+	POICount    int       `db:"poi_count" json:"poi_count"`
+	ReviewCount int       `db:"review_count" json:"review_count"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+// AccountMerge records an account folded into another and what moved with it
+type AccountMerge struct {
+	MergeID        uuid.UUID `db:"merge_id" json:"merge_id"`
+	SourceUserID   uuid.UUID `db:"source_user_id" json:"source_user_id"`
+	TargetUserID   uuid.UUID `db:"target_user_id" json:"target_user_id"`
+	POIsMoved      int       `db:"pois_moved" json:"pois_moved"`
+	ReviewsMoved   int       `db:"reviews_moved" json:"reviews_moved"`
+	SavedPOIsMoved int       `db:"saved_pois_moved" json:"saved_pois_moved"`
+	PhotosMoved    int       `db:"photos_moved" json:"photos_moved"`
+	MergedAt       time.Time `db:"merged_at" json:"merged_at"`
+}
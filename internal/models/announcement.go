@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Announcement is an admin-managed banner shown in the apps during its window
+type Announcement struct {
+	AnnouncementID uuid.UUID      `db:"announcement_id" json:"announcement_id"`
+	Title          string         `db:"title" json:"title"`
+	Body           *string        `db:"body" json:"body,omitempty"`
+	LinkURL        *string        `db:"link_url" json:"link_url,omitempty"`
+	Severity       string         `db:"severity" json:"severity"` // info, warning, critical
+	Audience       string         `db:"audience" json:"audience"` // all, signed_in, signed_out
+	Roles          pq.StringArray `db:"roles" json:"roles"`
+	Dismissible    bool           `db:"dismissible" json:"dismissible"`
+	StartsAt       time.Time      `db:"starts_at" json:"starts_at"`
+	EndsAt         *time.Time     `db:"ends_at" json:"ends_at,omitempty"`
+	CreatedBy      *uuid.UUID     `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
+
+	// Admin listings only
+	Dismissals *int `db:"dismissals" json:"dismissals,omitempty"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AnonymousSession is the browsing state of a signed-out client
+type AnonymousSession struct {
+	SessionID      string          `db:"session_id" json:"session_id"`
+	RecentSearches json.RawMessage `db:"recent_searches" json:"recent_searches"`
+	CreatedAt      time.Time       `db:"created_at" json:"created_at"`
+	LastSeenAt     time.Time       `db:"last_seen_at" json:"last_seen_at"`
+
+	RecentPOIs []SessionPOI `db:"-" json:"recent_pois"`
+	SavedPOIs  []SessionPOI `db:"-" json:"saved_pois"`
+}
+
+// SessionPOI is a POI card in an anonymous session's recent or saved list
+type SessionPOI struct {
+	PoiID         uuid.UUID  `db:"poi_id" json:"poi_id"`
+	Name          string     `db:"name" json:"name"`
+	CoverImageURL *string    `db:"cover_image_url" json:"cover_image_url,omitempty"`
+	RatingAvg     float64    `db:"rating_avg" json:"rating_avg"`
+	SavedAt       *time.Time `db:"saved_at" json:"saved_at,omitempty"`
+}
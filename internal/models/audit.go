@@ -0,0 +1,28 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// Audited moderation actions
+const (
+	AuditCommentHide       = "comment.hide"
+	AuditCommentDelete     = "comment.delete"
+	AuditUserCommentsPurge = "user.comments_purge"
+)
+
+// Audit log target types
+const (
+	AuditTargetComment = "comment"
+	AuditTargetUser    = "user"
+)
+
+// AuditEntry is one moderation action recorded in the audit log
+type AuditEntry struct {
+	ActorID    uuid.UUID
+	Action     string
+	TargetType string
+	TargetID   uuid.UUID
+	Reason     string
+	Details    map[string]any
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// BusinessVerification is a POI owner's request for "verified business" status
+type BusinessVerification struct {
+	VerificationID uuid.UUID      `db:"verification_id" json:"verification_id"`
+	PoiID          uuid.UUID      `db:"poi_id" json:"poi_id"`
+	RequesterID    uuid.UUID      `db:"requester_id" json:"requester_id"`
+	BusinessName   *string        `db:"business_name" json:"business_name,omitempty"`
+	Note           *string        `db:"note" json:"note,omitempty"`
+	DocumentKeys   pq.StringArray `db:"document_keys" json:"-"`
+	Status         string         `db:"status" json:"status"` // pending, approved, rejected, withdrawn
+	ReviewerID     *uuid.UUID     `db:"reviewer_id" json:"reviewer_id,omitempty"`
+	ReviewNote     *string        `db:"review_note" json:"review_note,omitempty"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+	ReviewedAt     *time.Time     `db:"reviewed_at" json:"reviewed_at,omitempty"`
+
+	// Joined fields
+	PoiName       *string `db:"poi_name" json:"poi_name,omitempty"`
+	RequesterName *string `db:"requester_name" json:"requester_name,omitempty"`
+
+	// Short-lived signed links to the proof documents, only for reviewers
+	DocumentCount int      `db:"-" json:"document_count"`
+	DocumentURLs  []string `db:"-" json:"document_urls,omitempty"`
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type Comment struct {
@@ -15,7 +16,21 @@ type Comment struct {
 	CreatedAt time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
 
+	// Set when a moderator hid the comment; hidden comments are left out of listings
+	HiddenAt     *time.Time `db:"hidden_at" json:"hidden_at,omitempty"`
+	HiddenBy     *uuid.UUID `db:"hidden_by" json:"hidden_by,omitempty"`
+	HiddenReason *string    `db:"hidden_reason" json:"hidden_reason,omitempty"`
+
 	// Joined fields
 	User    *User     `db:"user" json:"user,omitempty"`
 	Replies []Comment `db:"replies" json:"replies,omitempty"`
 }
+
+// FlaggedComment is a comment the text moderation pipeline flagged for review
+type FlaggedComment struct {
+	Comment
+	ModerationID uuid.UUID      `db:"moderation_id" json:"moderation_id"`
+	Original     string         `db:"original" json:"original"`
+	Flags        pq.StringArray `db:"flags" json:"flags"`
+	FlaggedAt    time.Time      `db:"flagged_at" json:"flagged_at"`
+}
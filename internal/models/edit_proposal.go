@@ -0,0 +1,26 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EditProposal is a community-suggested, field-level change to a POI
+type EditProposal struct {
+	ProposalID uuid.UUID       `db:"proposal_id" json:"proposal_id"`
+	PoiID      uuid.UUID       `db:"poi_id" json:"poi_id"`
+	ProposerID uuid.UUID       `db:"proposer_id" json:"proposer_id"`
+	Changes    json.RawMessage `db:"changes" json:"changes"`
+	Original   json.RawMessage `db:"original" json:"original"`
+	Note       *string         `db:"note" json:"note,omitempty"`
+	Status     string          `db:"status" json:"status"` // pending, accepted, rejected, withdrawn
+	ReviewerID *uuid.UUID      `db:"reviewer_id" json:"reviewer_id,omitempty"`
+	ReviewNote *string         `db:"review_note" json:"review_note,omitempty"`
+	CreatedAt  time.Time       `db:"created_at" json:"created_at"`
+	ReviewedAt *time.Time      `db:"reviewed_at" json:"reviewed_at,omitempty"`
+
+	// Joined fields
+	ProposerName *string `db:"proposer_name" json:"proposer_name,omitempty"`
+}
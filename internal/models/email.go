@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Email categories a user can opt out of
+const (
+	EmailPOIStatus    = "poi_status"
+	EmailVerification = "verification"
+	EmailSavedDigest  = "saved_digest"
+)
+
+// EmailCategories lists every email category
+var EmailCategories = []string{EmailPOIStatus, EmailVerification, EmailSavedDigest}
+
+// EmailPreferences are the categories of email a user receives
+type EmailPreferences struct {
+	POIStatus    bool       `db:"poi_status" json:"poi_status"`
+	Verification bool       `db:"verification" json:"verification"`
+	SavedDigest  bool       `db:"saved_digest" json:"saved_digest"`
+	UpdatedAt    *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+// Allows reports whether the user receives emails of category
+func (p EmailPreferences) Allows(category string) bool {
+	switch category {
+	case EmailPOIStatus:
+		return p.POIStatus
+	case EmailVerification:
+		return p.Verification
+	case EmailSavedDigest:
+		return p.SavedDigest
+	default:
+		return false
+	}
+}
+
+// EmailRecipient is a user that can be emailed, with their preferences
+type EmailRecipient struct {
+	UserID uuid.UUID `db:"user_id"`
+	Email  string    `db:"email"`
+	Name   *string   `db:"name"`
+	EmailPreferences
+}
+
+// DigestRecipient is a user due a saved-POI digest covering updates since Since
+type DigestRecipient struct {
+	UserID uuid.UUID `db:"user_id"`
+	Email  string    `db:"email"`
+	Name   *string   `db:"name"`
+	Since  time.Time `db:"since"`
+}
+
+// DigestItem is a saved POI that changed since the previous digest
+type DigestItem struct {
+	PoiID     uuid.UUID `db:"poi_id"`
+	Name      string    `db:"name"`
+	UpdatedAt time.Time `db:"updated_at"`
+	Total     int       `db:"total"` // Updated saved POIs in the digest period, including unlisted ones
+}
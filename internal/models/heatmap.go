@@ -0,0 +1,27 @@
+package models
+
+// Heatmap metrics
+const (
+	HeatmapPOIDensity = "poi_density" // Approved POIs per cell
+	HeatmapAvgRating  = "avg_rating"  // Average rating of the reviewed POIs in a cell
+)
+
+// BBox is a WGS84 bounding box
+type BBox struct {
+	West, South, East, North float64
+}
+
+// HeatmapCell is one grid cell of a heatmap, located at its center
+type HeatmapCell struct {
+	Lat   float64 `db:"lat" json:"lat"`
+	Lng   float64 `db:"lng" json:"lng"`
+	Value float64 `db:"value" json:"value"`
+	POIs  int     `db:"pois" json:"pois"` // POIs the value is computed from
+}
+
+// Heatmap aggregates approved POIs of a bounding box on a square grid
+type Heatmap struct {
+	Metric   string        `json:"metric"`
+	CellSize float64       `json:"cell_size"` // Cell edge in degrees
+	Cells    []HeatmapCell `json:"cells"`
+}
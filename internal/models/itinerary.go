@@ -6,17 +6,35 @@ import (
 	"github.com/google/uuid"
 )
 
+// Itinerary access roles, strongest first
+const (
+	ItineraryRoleOwner = "owner"
+	ItineraryRoleEdit  = "edit"
+	ItineraryRoleView  = "view"
+)
+
+// Collaborator invitation states
+const (
+	CollaboratorPending  = "pending"
+	CollaboratorAccepted = "accepted"
+)
+
 // Itinerary represents a user's itinerary
 type Itinerary struct {
-	ItineraryID uuid.UUID  `db:"itinerary_id" json:"itinerary_id"`
-	UserID      uuid.UUID  `db:"user_id" json:"user_id"`
-	Title       string     `db:"title" json:"title"`
-	Description *string    `db:"description" json:"description,omitempty"`
-	StartDate   *time.Time `db:"start_date" json:"start_date,omitempty"`
-	EndDate     *time.Time `db:"end_date" json:"end_date,omitempty"`
-	IsPublic    bool       `db:"is_public" json:"is_public"`
-	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+	ItineraryID    uuid.UUID  `db:"itinerary_id" json:"itinerary_id"`
+	UserID         uuid.UUID  `db:"user_id" json:"user_id"`
+	Title          string     `db:"title" json:"title"`
+	Description    *string    `db:"description" json:"description,omitempty"`
+	StartDate      *time.Time `db:"start_date" json:"start_date,omitempty"`
+	EndDate        *time.Time `db:"end_date" json:"end_date,omitempty"`
+	IsPublic       bool       `db:"is_public" json:"is_public"`
+	ShareToken     *string    `db:"share_token" json:"share_token,omitempty"` // Only shown to the owner
+	ShareCreatedAt *time.Time `db:"share_created_at" json:"share_created_at,omitempty"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"updated_at"`
+
+	// Computed for the requesting user
+	Role string `db:"role" json:"role,omitempty"`
 }
 
 // ItineraryItem represents an item in an itinerary
@@ -29,5 +47,26 @@ type ItineraryItem struct {
 	PlannedTime *time.Time `db:"planned_time" json:"planned_time,omitempty"`
 	Duration    *int       `db:"duration" json:"duration,omitempty"`
 	Notes       *string    `db:"notes" json:"notes,omitempty"`
+	Version     int        `db:"version" json:"version"`
+	UpdatedBy   *uuid.UUID `db:"updated_by" json:"updated_by,omitempty"`
 	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+
+	// Joined fields
+	PoiName string `db:"poi_name" json:"poi_name"`
+}
+
+// ItineraryCollaborator is a user invited to view or edit an itinerary
+type ItineraryCollaborator struct {
+	ItineraryID uuid.UUID  `db:"itinerary_id" json:"itinerary_id"`
+	UserID      uuid.UUID  `db:"user_id" json:"user_id"`
+	Role        string     `db:"role" json:"role"`
+	Status      string     `db:"status" json:"status"`
+	InvitedBy   *uuid.UUID `db:"invited_by" json:"invited_by,omitempty"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	AcceptedAt  *time.Time `db:"accepted_at" json:"accepted_at,omitempty"`
+
+	// Joined fields
+	Email string  `db:"email" json:"email"`
+	Name  *string `db:"name" json:"name,omitempty"`
 }
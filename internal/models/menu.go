@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// MenuSection groups menu items (e.g. "Coffee", "Mains")
+type MenuSection struct {
+	SectionID   uuid.UUID  `db:"section_id" json:"section_id"`
+	PoiID       uuid.UUID  `db:"poi_id" json:"poi_id"`
+	Name        string     `db:"name" json:"name"`
+	Description *string    `db:"description" json:"description,omitempty"`
+	Position    int        `db:"position" json:"position"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+	Items       []MenuItem `db:"-" json:"items"`
+}
+
+// MenuItem is a single dish or drink on a POI menu
+type MenuItem struct {
+	ItemID       uuid.UUID      `db:"item_id" json:"item_id"`
+	SectionID    uuid.UUID      `db:"section_id" json:"section_id"`
+	PoiID        uuid.UUID      `db:"poi_id" json:"poi_id"`
+	Name         string         `db:"name" json:"name"`
+	Description  *string        `db:"description" json:"description,omitempty"`
+	Price        *float64       `db:"price" json:"price,omitempty"`
+	Currency     string         `db:"currency" json:"currency"`
+	DietaryTags  pq.StringArray `db:"dietary_tags" json:"dietary_tags"`
+	PhotoAssetID *uuid.UUID     `db:"photo_asset_id" json:"photo_asset_id,omitempty"`
+	IsAvailable  bool           `db:"is_available" json:"is_available"`
+	IsFeatured   bool           `db:"is_featured" json:"is_featured"`
+	Position     int            `db:"position" json:"position"`
+	CreatedAt    time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time      `db:"updated_at" json:"updated_at"`
+}
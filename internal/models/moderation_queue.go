@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// QueueMetrics reports moderation review times over a period and the current
+// age of the pending queue. Review times are nil when nothing was decided.
+type QueueMetrics struct {
+	Days              int        `json:"days"`
+	SLAHours          int        `json:"sla_hours"`
+	Decided           int        `db:"decided" json:"decided"`
+	DecidedWithinSLA  int        `db:"decided_within_sla" json:"decided_within_sla"`
+	MedianReviewHours *float64   `db:"median_review_hours" json:"median_review_hours"`
+	P90ReviewHours    *float64   `db:"p90_review_hours" json:"p90_review_hours"`
+	P95ReviewHours    *float64   `db:"p95_review_hours" json:"p95_review_hours"`
+	Pending           int        `db:"pending" json:"pending"`
+	Overdue           int        `db:"overdue" json:"overdue"`
+	OldestSubmittedAt *time.Time `db:"oldest_submitted_at" json:"oldest_submitted_at"`
+	QueueAge          []QueueAge `json:"queue_age"`
+}
+
+// QueueAge counts pending POIs that have waited between two ages
+type QueueAge struct {
+	Label string `db:"label" json:"label"`
+	Count int    `db:"count" json:"count"`
+}
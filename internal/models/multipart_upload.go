@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Multipart upload statuses
+const (
+	MultipartUploadActive    = "active"
+	MultipartUploadCompleted = "completed"
+	MultipartUploadAborted   = "aborted"
+)
+
+// MultipartUpload is an upload sent to storage in parts, kept so clients can
+// resume it after losing their connection
+type MultipartUpload struct {
+	UploadID        uuid.UUID `db:"upload_id" json:"upload_id"`
+	UserID          uuid.UUID `db:"user_id" json:"-"`
+	StorageKey      string    `db:"storage_key" json:"key"`
+	StorageUploadID string    `db:"storage_upload_id" json:"-"` // Upload ID issued by the bucket
+	Category        string    `db:"category" json:"category"`
+	ContentType     string    `db:"content_type" json:"content_type"`
+	SizeBytes       int64     `db:"size_bytes" json:"size_bytes"`
+	PartSizeBytes   int64     `db:"part_size_bytes" json:"part_size_bytes"` // Size of every part but the last
+	PartCount       int       `db:"part_count" json:"part_count"`
+	Status          string    `db:"status" json:"status"` // active, completed, aborted
+	ExpiresAt       time.Time `db:"expires_at" json:"expires_at"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// PartSize returns the size of part number (1-based)
+func (u *MultipartUpload) PartSize(number int) int64 {
+	if number == u.PartCount {
+		return u.SizeBytes - int64(u.PartCount-1)*u.PartSizeBytes
+	}
+	return u.PartSizeBytes
+}
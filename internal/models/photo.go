@@ -20,5 +20,6 @@ type Photo struct {
 	VibeCategory    *string    `db:"vibe_category" json:"vibe_category,omitempty"`
 	Score           int        `db:"score" json:"score"`
 	IsHero          bool       `db:"is_hero" json:"is_hero"`
+	SortIndex       *int       `db:"sort_index" json:"sort_index,omitempty"` // Owner-defined gallery position
 	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
 }
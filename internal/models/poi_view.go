@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// POIView is one deduplicated detail view of a POI
+type POIView struct {
+	PoiID      uuid.UUID `json:"poi_id"`
+	ViewedHour time.Time `json:"viewed_hour"`
+	ViewerHash string    `json:"viewer_hash"`
+}
+
+// TrendingPOI is a POI card in the "Trending now" carousel
+type TrendingPOI struct {
+	PoiID         uuid.UUID `db:"poi_id" json:"poi_id"`
+	Name          string    `db:"name" json:"name"`
+	CoverImageURL *string   `db:"cover_image_url" json:"cover_image_url,omitempty"`
+	PriceRange    *int      `db:"price_range" json:"price_range,omitempty"`
+	RatingAvg     float64   `db:"rating_avg" json:"rating_avg"`
+	ReviewsCount  int       `db:"reviews_count" json:"reviews_count"`
+	Views         int       `db:"views" json:"views"` // Over the trending window
+	Saves         int       `db:"saves" json:"saves"`
+	Score         float64   `db:"score" json:"score"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Question is a question a user asked about a POI
+type Question struct {
+	QuestionID       uuid.UUID  `db:"question_id" json:"question_id"`
+	PoiID            uuid.UUID  `db:"poi_id" json:"poi_id"`
+	UserID           uuid.UUID  `db:"user_id" json:"user_id"`
+	Body             string     `db:"body" json:"body"`
+	AcceptedAnswerID *uuid.UUID `db:"accepted_answer_id" json:"accepted_answer_id,omitempty"`
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time  `db:"updated_at" json:"updated_at"`
+
+	// Joined fields
+	UserName *string  `db:"user_name" json:"user_name,omitempty"`
+	Answers  []Answer `db:"-" json:"answers"`
+}
+
+// Answer is a reply to a question, by the POI owner or another user
+type Answer struct {
+	AnswerID   uuid.UUID `db:"answer_id" json:"answer_id"`
+	QuestionID uuid.UUID `db:"question_id" json:"question_id"`
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	Body       string    `db:"body" json:"body"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+
+	// Derived fields
+	FromOwner bool    `db:"from_owner" json:"from_owner"` // Written by the POI owner
+	Accepted  bool    `db:"accepted" json:"accepted"`
+	UserName  *string `db:"user_name" json:"user_name,omitempty"`
+}
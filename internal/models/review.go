@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // Review represents a user review
@@ -16,4 +17,21 @@ type Review struct {
 	Upvotes   int       `db:"upvotes" json:"upvotes"`
 	Downvotes int       `db:"downvotes" json:"downvotes"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
+
+	// Helpful minus unhelpful votes, and the caller's vote (1, -1 or 0) when listed for a signed-in user
+	HelpfulScore int  `db:"helpful_score" json:"helpful_score"`
+	UserVote     *int `db:"user_vote" json:"user_vote,omitempty"`
+
+	// Optional per-dimension ratings (1-5)
+	WifiRating          *int `db:"wifi_rating" json:"wifi_rating,omitempty"`
+	ComfortRating       *int `db:"comfort_rating" json:"comfort_rating,omitempty"`
+	FoodRating          *int `db:"food_rating" json:"food_rating,omitempty"`
+	ValueRating         *int `db:"value_rating" json:"value_rating,omitempty"`
+	NoiseAccuracyRating *int `db:"noise_accuracy_rating" json:"noise_accuracy_rating,omitempty"`
+
+	// Attached image assets, in display order
+	PhotoAssetIDs pq.StringArray `db:"photo_asset_ids" json:"photo_asset_ids"`
+
+	// Joined fields
+	UserName *string `db:"user_name" json:"user_name,omitempty"`
 }
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SavedSearch is a filter combination a user keeps to run again or be alerted about
+type SavedSearch struct {
+	SearchID      uuid.UUID       `db:"search_id" json:"search_id"`
+	UserID        uuid.UUID       `db:"user_id" json:"-"`
+	Name          string          `db:"name" json:"name"`
+	Query         json.RawMessage `db:"query" json:"query"` // GET /api/v1/pois parameters by name
+	AlertsEnabled bool            `db:"alerts_enabled" json:"alerts_enabled"`
+	CheckedUntil  time.Time       `db:"checked_until" json:"-"`
+	LastAlertedAt *time.Time      `db:"last_alerted_at" json:"last_alerted_at,omitempty"`
+	CreatedAt     time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time       `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import "encoding/json"
+
+// SearchEvent is one sampled, anonymized public search
+type SearchEvent struct {
+	Query       *string         `json:"query"`
+	Filters     json.RawMessage `json:"filters"`
+	SortBy      *string         `json:"sort_by"`
+	Area        *string         `json:"area"`
+	Lat         *float64        `json:"lat"`
+	Lng         *float64        `json:"lng"`
+	ResultCount int             `json:"result_count"`
+	SampleRate  float64         `json:"sample_rate"`
+}
+
+// SearchFilterCount is how often a filter value was used
+type SearchFilterCount struct {
+	Filter string `db:"filter" json:"filter"`
+	Value  string `db:"value" json:"value"`
+	Count  int    `db:"count" json:"count"`
+}
+
+// SearchQueryCount is how often a free-text query was searched
+type SearchQueryCount struct {
+	Query string `db:"query" json:"query"`
+	Count int    `db:"count" json:"count"`
+}
+
+// SearchAreaCount is how often searches targeted a service area
+type SearchAreaCount struct {
+	Area  string `db:"area" json:"area"`
+	Count int    `db:"count" json:"count"`
+}
+
+// SearchCellCount is how often searches were centered on a ~1 km cell
+type SearchCellCount struct {
+	Lat   float64 `db:"lat" json:"lat"`
+	Lng   float64 `db:"lng" json:"lng"`
+	Count int     `db:"count" json:"count"`
+}
+
+// SearchSummary aggregates search events over a period. Counts are estimated
+// searches: each recorded event stands for 1/sample_rate searches.
+type SearchSummary struct {
+	Days              int                 `json:"days"`
+	TotalSearches     int                 `db:"total_searches" json:"total_searches"`
+	ZeroResultRate    float64             `db:"zero_result_rate" json:"zero_result_rate"`
+	TopFilters        []SearchFilterCount `json:"top_filters"`
+	TopQueries        []SearchQueryCount  `json:"top_queries"`
+	ZeroResultQueries []SearchQueryCount  `json:"zero_result_queries"`
+	PopularAreas      []SearchAreaCount   `json:"popular_areas"`
+	PopularCells      []SearchCellCount   `json:"popular_cells"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServiceArea is a city or region the product operates in
+type ServiceArea struct {
+	AreaID    uuid.UUID       `db:"area_id" json:"area_id"`
+	Slug      string          `db:"slug" json:"slug"`
+	Name      string          `db:"name" json:"name"`
+	Boundary  json.RawMessage `db:"boundary" json:"boundary,omitempty"` // GeoJSON MultiPolygon
+	IsActive  bool            `db:"is_active" json:"is_active"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time       `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Recurrence values for POI specials
+const (
+	RecurrenceNone    = "none"
+	RecurrenceDaily   = "daily"
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+)
+
+// Special is a time-bounded offer or event at a POI
+type Special struct {
+	SpecialID      uuid.UUID     `db:"special_id" json:"special_id"`
+	PoiID          uuid.UUID     `db:"poi_id" json:"poi_id"`
+	Title          string        `db:"title" json:"title"`
+	Description    *string       `db:"description" json:"description,omitempty"`
+	StartsAt       time.Time     `db:"starts_at" json:"starts_at"`
+	EndsAt         *time.Time    `db:"ends_at" json:"ends_at,omitempty"`
+	Recurrence     string        `db:"recurrence" json:"recurrence"`
+	RecurrenceDays pq.Int64Array `db:"recurrence_days" json:"recurrence_days,omitempty"`
+	DailyStartTime *string       `db:"daily_start_time" json:"daily_start_time,omitempty"` // HH:MM:SS local time
+	DailyEndTime   *string       `db:"daily_end_time" json:"daily_end_time,omitempty"`
+	Timezone       string        `db:"timezone" json:"timezone"`
+	CreatedBy      *uuid.UUID    `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time     `db:"updated_at" json:"updated_at"`
+
+	// Computed
+	IsActive bool `db:"is_active" json:"is_active"`
+}
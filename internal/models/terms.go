@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// TermsVersion is a published version of the terms of service and privacy policy
+type TermsVersion struct {
+	Version     int       `db:"version" json:"version"`
+	TermsURL    string    `db:"terms_url" json:"terms_url"`
+	PrivacyURL  string    `db:"privacy_url" json:"privacy_url"`
+	Summary     *string   `db:"summary" json:"summary,omitempty"`
+	PublishedAt time.Time `db:"published_at" json:"published_at"`
+}
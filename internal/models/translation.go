@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// POITranslation holds locale-specific POI content
+type POITranslation struct {
+	PoiID       uuid.UUID  `db:"poi_id" json:"poi_id"`
+	Locale      string     `db:"locale" json:"locale"`
+	Name        *string    `db:"name" json:"name,omitempty"`
+	Description *string    `db:"description" json:"description,omitempty"`
+	UpdatedBy   *uuid.UUID `db:"updated_by" json:"updated_by,omitempty"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Upload statuses
+const (
+	UploadPending   = "pending"   // Presigned, no progress reported yet
+	UploadUploading = "uploading" // The client reported progress
+	UploadFinalized = "finalized" // Handed to the image pipeline
+	UploadExpired   = "expired"   // Never finalized; the object was deleted
+	UploadRejected  = "rejected"  // Content sniffing failed at finalize; the object was deleted
+)
+
+// Upload is a presigned direct-to-storage upload, tracked from presign to finalize
+type Upload struct {
+	UploadID          uuid.UUID  `db:"upload_id" json:"upload_id"`
+	UserID            uuid.UUID  `db:"user_id" json:"-"`
+	StorageKey        string     `db:"storage_key" json:"key"`
+	Category          string     `db:"category" json:"category"`
+	ContentType       string     `db:"content_type" json:"content_type"`
+	ExpectedSizeBytes int64      `db:"expected_size_bytes" json:"expected_size_bytes"`
+	BytesUploaded     int64      `db:"bytes_uploaded" json:"bytes_uploaded"` // Last progress reported by the client
+	Multipart         bool       `db:"multipart" json:"multipart"`
+	Status            string     `db:"status" json:"status"` // pending, uploading, finalized, expired, rejected
+	LastHeartbeatAt   *time.Time `db:"last_heartbeat_at" json:"last_heartbeat_at,omitempty"`
+	ExpiresAt         time.Time  `db:"expires_at" json:"expires_at"`
+	FinalizedAt       *time.Time `db:"finalized_at" json:"finalized_at,omitempty"`
+	CreatedAt         time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time  `db:"updated_at" json:"updated_at"`
+
+	// Expiry listings only: the storage upload ID of an unfinished multipart upload
+	StorageUploadID *string `db:"storage_upload_id" json:"-"`
+}
+
+// UploadRejection records an upload whose content was not a plain image
+type UploadRejection struct {
+	RejectionID         uuid.UUID  `db:"rejection_id" json:"rejection_id"`
+	UploadID            *uuid.UUID `db:"upload_id" json:"upload_id,omitempty"`
+	UserID              *uuid.UUID `db:"user_id" json:"user_id,omitempty"`
+	StorageKey          string     `db:"storage_key" json:"key"`
+	Reason              string     `db:"reason" json:"reason"` // svg, pdf, script, unknown
+	Detail              string     `db:"detail" json:"detail"`
+	DeclaredContentType *string    `db:"declared_content_type" json:"declared_content_type,omitempty"`
+	IPAddress           *string    `db:"ip_address" json:"ip_address,omitempty"`
+	UserAgent           *string    `db:"user_agent" json:"user_agent,omitempty"`
+	CreatedAt           time.Time  `db:"created_at" json:"created_at"`
+}
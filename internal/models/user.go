@@ -29,4 +29,68 @@ type UserProfile struct {
 	ImpactScore int       `db:"impact_score" json:"impact_score"`
 	CreatedAt   time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+
+	// Joined fields
+	Name       *string `db:"name" json:"name,omitempty"`
+	PictureURL *string `db:"picture_url" json:"picture_url,omitempty"`
+}
+
+// ProfilePrivacy controls what a user's public profile exposes to others
+type ProfilePrivacy struct {
+	ProfilePublic     bool `db:"profile_public" json:"profile_public"`
+	ShowStats         bool `db:"show_stats" json:"show_stats"`
+	ShowContributions bool `db:"show_contributions" json:"show_contributions"`
+}
+
+// Contribution types listed on a public profile
+const (
+	ContributionPOI    = "poi"
+	ContributionPhoto  = "photo"
+	ContributionReview = "review"
+)
+
+// ContributionStats counts a user's published contributions
+type ContributionStats struct {
+	ApprovedPOIs int `db:"approved_pois" json:"approved_pois"`
+	Photos       int `db:"photos" json:"photos"`
+	Reviews      int `db:"reviews" json:"reviews"`
+}
+
+// Contribution is a POI, photo or review a user added to an approved POI
+type Contribution struct {
+	Type      string    `db:"type" json:"type"`
+	ID        uuid.UUID `db:"id" json:"id"`
+	POIID     uuid.UUID `db:"poi_id" json:"poi_id"`
+	POIName   string    `db:"poi_name" json:"poi_name"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// PublicProfile is what other users see of a user. Stats and contributions
+// are omitted when the user hides them.
+type PublicProfile struct {
+	UserID              uuid.UUID          `db:"user_id" json:"user_id"`
+	Username            *string            `db:"username" json:"username,omitempty"`
+	Name                *string            `db:"name" json:"name,omitempty"`
+	AvatarURL           *string            `db:"avatar_url" json:"avatar_url,omitempty"`
+	ScoutLevel          int                `db:"scout_level" json:"scout_level"`
+	MemberSince         time.Time          `db:"member_since" json:"member_since"`
+	Stats               *ContributionStats `db:"-" json:"stats,omitempty"`
+	RecentContributions []Contribution     `db:"-" json:"recent_contributions,omitempty"`
+	Privacy             *ProfilePrivacy    `db:"-" json:"privacy,omitempty"` // Only shown to the owner
+}
+
+// Username is a user's handle and when it may next change
+type Username struct {
+	Username     string     `db:"username" json:"username"`
+	ChangedAt    *time.Time `db:"username_changed_at" json:"changed_at,omitempty"`
+	NextChangeAt *time.Time `db:"-" json:"next_change_at,omitempty"`
+}
+
+// BlockedUser is a user the caller blocked
+type BlockedUser struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	Username  *string   `db:"username" json:"username,omitempty"`
+	Name      *string   `db:"name" json:"name,omitempty"`
+	AvatarURL *string   `db:"avatar_url" json:"avatar_url,omitempty"`
+	BlockedAt time.Time `db:"blocked_at" json:"blocked_at"`
 }
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// POI lifecycle event types captured in the outbox
+const (
+	EventPOIApproved = "poi.approved"
+	EventPOIUpdated  = "poi.updated"
+	EventPOIDeleted  = "poi.deleted"
+)
+
+// EventTypes lists the event types webhooks can subscribe to
+var EventTypes = []string{EventPOIApproved, EventPOIUpdated, EventPOIDeleted}
+
+// OutboxEvent is a domain event recorded in the transactional outbox
+type OutboxEvent struct {
+	EventID     uuid.UUID       `db:"event_id" json:"event_id"`
+	EventType   string          `db:"event_type" json:"event_type"`
+	AggregateID uuid.UUID       `db:"aggregate_id" json:"aggregate_id"`
+	Payload     json.RawMessage `db:"payload" json:"payload"`
+	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
+	PublishedAt *time.Time      `db:"published_at" json:"published_at,omitempty"`
+}
+
+// WebhookSubscription is an external endpoint receiving signed event payloads
+type WebhookSubscription struct {
+	SubscriptionID uuid.UUID      `db:"subscription_id" json:"subscription_id"`
+	URL            string         `db:"url" json:"url"`
+	Secret         string         `db:"secret" json:"-"`
+	EventTypes     pq.StringArray `db:"event_types" json:"event_types"`
+	Description    *string        `db:"description" json:"description,omitempty"`
+	IsActive       bool           `db:"is_active" json:"is_active"`
+	CreatedBy      *uuid.UUID     `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+// Webhook delivery states
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookDelivery tracks sending one event to one subscription
+type WebhookDelivery struct {
+	DeliveryID     uuid.UUID  `db:"delivery_id" json:"delivery_id"`
+	SubscriptionID uuid.UUID  `db:"subscription_id" json:"subscription_id"`
+	EventID        uuid.UUID  `db:"event_id" json:"event_id"`
+	EventType      string     `db:"event_type" json:"event_type"`
+	Status         string     `db:"status" json:"status"`
+	Attempts       int        `db:"attempts" json:"attempts"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at" json:"next_attempt_at"`
+	LastStatusCode *int       `db:"last_status_code" json:"last_status_code,omitempty"`
+	LastError      *string    `db:"last_error" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `db:"delivered_at" json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+}
+
+// DueDelivery is a claimed delivery with everything needed to send it
+type DueDelivery struct {
+	DeliveryID uuid.UUID       `db:"delivery_id"`
+	Attempts   int             `db:"attempts"`
+	URL        string          `db:"url"`
+	Secret     string          `db:"secret"`
+	EventID    uuid.UUID       `db:"event_id"`
+	EventType  string          `db:"event_type"`
+	Payload    json.RawMessage `db:"payload"`
+	CreatedAt  time.Time       `db:"created_at"`
+}
@@ -0,0 +1,56 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// InitMetrics installs the global MeterProvider.
+// Metrics are always exposed for Prometheus scraping (see MetricsHandler) and are
+// additionally pushed over OTLP when OTEL_EXPORTER_OTLP_ENDPOINT is configured.
+func InitMetrics(ctx context.Context, serviceName string) (func(context.Context) error, error) {
+	res, err := newResource(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Registers with the default Prometheus registry served by promhttp.Handler
+	promExporter, err := otelprom.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
+	}
+
+	opts := []sdkmetric.Option{
+		sdkmetric.WithReader(promExporter),
+		sdkmetric.WithResource(res),
+	}
+
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" {
+		otlpExporter, err := otlpmetrichttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP metric exporter: %w", err)
+		}
+		opts = append(opts, sdkmetric.WithReader(
+			sdkmetric.NewPeriodicReader(otlpExporter, sdkmetric.WithInterval(30*time.Second)),
+		))
+	}
+
+	mp := sdkmetric.NewMeterProvider(opts...)
+	otel.SetMeterProvider(mp)
+
+	return mp.Shutdown, nil
+}
+
+// MetricsHandler serves metrics in the Prometheus text exposition format
+func MetricsHandler() http.Handler {
+	return promhttp.Handler()
+}
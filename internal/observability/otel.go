@@ -38,15 +38,9 @@ func InitOTel(ctx context.Context, serviceName string) (func(context.Context) er
 		}
 	}
 
-	res, err := resource.Merge(
-		resource.Default(),
-		resource.NewWithAttributes(
-			"", // Set empty schema URL to use default or let it be handled by resource.Merge
-			semconv.ServiceName(serviceName),
-		),
-	)
+	res, err := newResource(serviceName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create resource: %w", err)
+		return nil, err
 	}
 
 	tp := sdktrace.NewTracerProvider(
@@ -59,3 +53,18 @@ func InitOTel(ctx context.Context, serviceName string) (func(context.Context) er
 
 	return tp.Shutdown, nil
 }
+
+// newResource describes this service for traces and metrics
+func newResource(serviceName string) (*resource.Resource, error) {
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(
+			"", // Set empty schema URL to use default or let it be handled by resource.Merge
+			semconv.ServiceName(serviceName),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,104 @@
+package osm
+
+import (
+	"fmt"
+	"strings"
+
+	"maukemana-backend/internal/repositories"
+)
+
+// Source identifies OSM imports in points_of_interest.source
+const Source = "osm"
+
+// Attribution is required by the ODbL for data taken from OpenStreetMap
+const Attribution = "© OpenStreetMap contributors, ODbL"
+
+// ToImportedPOI maps an OSM element to an imported POI. Unnamed elements are
+// skipped since they cannot be reviewed or deduplicated.
+func ToImportedPOI(e Element) (repositories.ImportedPOI, bool) {
+	name := strings.TrimSpace(e.Tags["name"])
+	if name == "" {
+		return repositories.ImportedPOI{}, false
+	}
+	lat, lng := e.Coordinates()
+
+	poi := repositories.ImportedPOI{
+		Source:      Source,
+		SourceID:    fmt.Sprintf("%s/%d", e.Type, e.ID),
+		Attribution: Attribution,
+		CategoryKey: "category.cafe",
+		Name:        name,
+		Latitude:    lat,
+		Longitude:   lng,
+
+		Brand:                tag(e, "brand"),
+		City:                 tag(e, "addr:city"),
+		PostalCode:           tag(e, "addr:postcode"),
+		HasWifi:              wifiTag(e.Tags["internet_access"]),
+		OutdoorSeating:       yesNoTag(e.Tags["outdoor_seating"]),
+		WheelchairAccessible: yesNoTag(e.Tags["wheelchair"]),
+		Website:              tag(e, "website", "contact:website"),
+		Phone:                tag(e, "phone", "contact:phone"),
+		Email:                tag(e, "email", "contact:email"),
+	}
+	if e.Tags["amenity"] == "coworking_space" || e.Tags["office"] == "coworking" {
+		poi.CategoryKey = "category.coworking"
+	}
+
+	if street := tag(e, "addr:street"); street != nil {
+		s := *street
+		if number := tag(e, "addr:housenumber"); number != nil {
+			s += " " + *number
+		}
+		poi.Street = &s
+	}
+
+	// cuisine is a ;-separated list such as "coffee_shop;breakfast"
+	if cuisine := tag(e, "cuisine"); cuisine != nil {
+		first := strings.ReplaceAll(strings.TrimSpace(strings.Split(*cuisine, ";")[0]), "_", " ")
+		poi.Cuisine = &first
+	}
+
+	// Phone tags may list several numbers; keep the first
+	if poi.Phone != nil {
+		first := strings.TrimSpace(strings.Split(*poi.Phone, ";")[0])
+		poi.Phone = &first
+	}
+	return poi, true
+}
+
+// tag returns the first non-empty value of the given keys
+func tag(e Element, keys ...string) *string {
+	for _, k := range keys {
+		if v := strings.TrimSpace(e.Tags[k]); v != "" {
+			return &v
+		}
+	}
+	return nil
+}
+
+// yesNoTag maps yes/no tag values; anything else (limited, unknown) is nil
+func yesNoTag(v string) *bool {
+	switch v {
+	case "yes":
+		t := true
+		return &t
+	case "no":
+		f := false
+		return &f
+	}
+	return nil
+}
+
+// wifiTag maps internet_access values (wlan, yes, terminal, wired, no)
+func wifiTag(v string) *bool {
+	switch v {
+	case "wlan", "yes", "wifi":
+		t := true
+		return &t
+	case "no":
+		f := false
+		return &f
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+// Package osm fetches cafes and coworking spaces from OpenStreetMap through the
+// Overpass API and maps their tags to imported POIs.
+package osm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultOverpassURL is the public Overpass API endpoint
+const DefaultOverpassURL = "https://overpass-api.de/api/interpreter"
+
+// requestTimeout bounds an Overpass query; large boxes take a while to evaluate
+const requestTimeout = 3 * time.Minute
+
+// BBox is a WGS84 bounding box
+type BBox struct {
+	South, West, North, East float64
+}
+
+// ParseBBox parses "south,west,north,east", the Overpass bounding box order
+func ParseBBox(s string) (BBox, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return BBox{}, fmt.Errorf("bbox must be south,west,north,east")
+	}
+	var v [4]float64
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return BBox{}, fmt.Errorf("bbox: %w", err)
+		}
+		v[i] = f
+	}
+	b := BBox{South: v[0], West: v[1], North: v[2], East: v[3]}
+	if b.South >= b.North || b.West >= b.East || b.South < -90 || b.North > 90 || b.West < -180 || b.East > 180 {
+		return BBox{}, fmt.Errorf("bbox %q is not a valid south,west,north,east box", s)
+	}
+	return b, nil
+}
+
+func (b BBox) String() string {
+	return fmt.Sprintf("%f,%f,%f,%f", b.South, b.West, b.North, b.East)
+}
+
+// Element is an OSM node, way or relation. Ways and relations carry their center.
+type Element struct {
+	Type   string  `json:"type"`
+	ID     int64   `json:"id"`
+	Lat    float64 `json:"lat"`
+	Lon    float64 `json:"lon"`
+	Center *struct {
+		Lat float64 `json:"lat"`
+		Lon float64 `json:"lon"`
+	} `json:"center"`
+	Tags map[string]string `json:"tags"`
+}
+
+// Coordinates returns the element position (the center for ways and relations)
+func (e Element) Coordinates() (lat, lng float64) {
+	if e.Center != nil {
+		return e.Center.Lat, e.Center.Lon
+	}
+	return e.Lat, e.Lon
+}
+
+// Client queries an Overpass API endpoint
+type Client struct {
+	http *http.Client
+	url  string
+}
+
+// NewClient creates an Overpass client; an empty URL uses the public endpoint
+func NewClient(endpoint string) *Client {
+	if endpoint == "" {
+		endpoint = DefaultOverpassURL
+	}
+	return &Client{http: &http.Client{Timeout: requestTimeout}, url: endpoint}
+}
+
+// FetchPlaces returns the cafes and coworking spaces inside a bounding box
+func (c *Client) FetchPlaces(ctx context.Context, bbox BBox) ([]Element, error) {
+	query := fmt.Sprintf(`[out:json][timeout:%d];
+(
+  nwr["amenity"="cafe"](%[2]s);
+  nwr["amenity"="coworking_space"](%[2]s);
+  nwr["office"="coworking"](%[2]s);
+);
+out center tags;`, int(requestTimeout.Seconds()), bbox)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, strings.NewReader(url.Values{"data": {query}}.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", "maukemana-osm-import/1.0")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("overpass request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("overpass returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var out struct {
+		Elements []Element `json:"elements"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode overpass response: %w", err)
+	}
+	return out.Elements, nil
+}
@@ -0,0 +1,397 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrAccountNotFound is returned when a user does not exist or was already deleted
+	ErrAccountNotFound = errors.New("account not found")
+	// ErrExportNotFound is returned when a user has no data export
+	ErrExportNotFound = errors.New("data export not found")
+)
+
+// AccountRepository handles personal data exports, account deletion and linking
+type AccountRepository struct {
+	db *database.DB
+}
+
+// NewAccountRepository creates a new account repository
+func NewAccountRepository(db *database.DB) *AccountRepository {
+	return &AccountRepository{db: db}
+}
+
+const exportColumns = `export_id, user_id, status, size_bytes, error, created_at, started_at, completed_at, expires_at`
+
+// GetLatestExport returns the user's most recent export that has not expired
+func (r *AccountRepository) GetLatestExport(ctx context.Context, userID uuid.UUID) (*models.DataExport, error) {
+	var export models.DataExport
+	err := r.db.Conn(ctx).GetContext(ctx, &export, `
+		SELECT `+exportColumns+`
+		FROM data_exports
+		WHERE user_id = $1 AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrExportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get latest export: %w", err)
+	}
+	return &export, nil
+}
+
+// CreateExport queues a new export for the user
+func (r *AccountRepository) CreateExport(ctx context.Context, userID uuid.UUID) (*models.DataExport, error) {
+	var export models.DataExport
+	err := r.db.Conn(ctx).GetContext(ctx, &export, `
+		INSERT INTO data_exports (user_id) VALUES ($1)
+		RETURNING `+exportColumns, userID)
+	if err != nil {
+		return nil, fmt.Errorf("create export: %w", err)
+	}
+	return &export, nil
+}
+
+// GetExportArchive returns the JSON document of a ready export
+func (r *AccountRepository) GetExportArchive(ctx context.Context, exportID uuid.UUID) ([]byte, error) {
+	var archive []byte
+	err := r.db.Conn(ctx).QueryRowContext(ctx, `
+		SELECT archive FROM data_exports
+		WHERE export_id = $1 AND status = 'ready' AND expires_at > NOW()
+	`, exportID).Scan(&archive)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrExportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get export archive: %w", err)
+	}
+	return archive, nil
+}
+
+// ClaimPendingExport marks the oldest pending export as processing and returns
+// it. Exports stuck in processing for longer than lease are reclaimed.
+func (r *AccountRepository) ClaimPendingExport(ctx context.Context, lease time.Duration) (*models.DataExport, error) {
+	var export models.DataExport
+	err := r.db.Conn(ctx).GetContext(ctx, &export, `
+		UPDATE data_exports
+		SET status = 'processing', started_at = NOW()
+		WHERE export_id = (
+			SELECT export_id FROM data_exports
+			WHERE status = 'pending'
+			   OR (status = 'processing' AND started_at < NOW() - make_interval(secs => $1))
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+exportColumns, lease.Seconds())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("claim export: %w", err)
+	}
+	return &export, nil
+}
+
+// CompleteExport stores the archive and makes it downloadable until expiresAt
+func (r *AccountRepository) CompleteExport(ctx context.Context, exportID uuid.UUID, archive []byte, expiresAt time.Time) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE data_exports
+		SET status = 'ready', archive = $2, size_bytes = $3, error = NULL,
+		    completed_at = NOW(), expires_at = $4
+		WHERE export_id = $1
+	`, exportID, archive, len(archive), expiresAt)
+	if err != nil {
+		return fmt.Errorf("complete export: %w", err)
+	}
+	return nil
+}
+
+// FailExport records why an export could not be built
+func (r *AccountRepository) FailExport(ctx context.Context, exportID uuid.UUID, reason string) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE data_exports SET status = 'failed', error = $2, completed_at = NOW() WHERE export_id = $1
+	`, exportID, reason)
+	if err != nil {
+		return fmt.Errorf("fail export: %w", err)
+	}
+	return nil
+}
+
+// PurgeExpiredExports drops archives past their expiry
+func (r *AccountRepository) PurgeExpiredExports(ctx context.Context) (int64, error) {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, `DELETE FROM data_exports WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("purge exports: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// BuildExport assembles everything stored about a user into one JSON document
+func (r *AccountRepository) BuildExport(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	var doc []byte
+	err := r.db.Conn(ctx).QueryRowContext(ctx, `
+		SELECT jsonb_build_object(
+			'generated_at', NOW(),
+			'account', (
+				SELECT jsonb_build_object(
+					'user_id', u.user_id, 'email', u.email, 'name', u.name,
+					'picture_url', u.picture_url, 'role', u.role, 'created_at', u.created_at
+				)
+				FROM users u WHERE u.user_id = $1
+			),
+			'profile', (SELECT to_jsonb(up) FROM user_profiles up WHERE up.user_id = $1),
+			'territory_stats', COALESCE((SELECT jsonb_agg(to_jsonb(ts)) FROM user_territory_stats ts WHERE ts.user_id = $1), '[]'),
+			'pois', COALESCE((
+				SELECT jsonb_agg(
+					(to_jsonb(p) - 'location') || jsonb_build_object(
+						'latitude', ST_Y(p.location::geometry),
+						'longitude', ST_X(p.location::geometry)
+					) ORDER BY p.created_at)
+				FROM points_of_interest p WHERE p.created_by = $1
+			), '[]'),
+			'reviews', COALESCE((SELECT jsonb_agg(to_jsonb(rv) ORDER BY rv.created_at) FROM reviews rv WHERE rv.user_id = $1), '[]'),
+			'comments', COALESCE((SELECT jsonb_agg(to_jsonb(cm) ORDER BY cm.created_at) FROM comments cm WHERE cm.user_id = $1), '[]'),
+			'edit_proposals', COALESCE((SELECT jsonb_agg(to_jsonb(ep) ORDER BY ep.created_at) FROM poi_edit_proposals ep WHERE ep.proposer_id = $1), '[]'),
+			'saved_pois', COALESCE((
+				SELECT jsonb_agg(jsonb_build_object(
+					'poi_id', s.poi_id, 'poi_name', p.name, 'notes', s.notes, 'saved_at', s.created_at
+				) ORDER BY s.created_at)
+				FROM saved_pois s JOIN points_of_interest p ON p.poi_id = s.poi_id
+				WHERE s.user_id = $1
+			), '[]'),
+			'saved_searches', COALESCE((
+				SELECT jsonb_agg(jsonb_build_object(
+					'name', ss.name, 'query', ss.query, 'alerts_enabled', ss.alerts_enabled, 'created_at', ss.created_at
+				) ORDER BY ss.created_at)
+				FROM saved_searches ss WHERE ss.user_id = $1
+			), '[]'),
+			'itineraries', COALESCE((
+				SELECT jsonb_agg((to_jsonb(i) - 'share_token') || jsonb_build_object(
+					'items', COALESCE((SELECT jsonb_agg(to_jsonb(it) ORDER BY it.day, it.order_index)
+					                   FROM itinerary_items it WHERE it.itinerary_id = i.itinerary_id), '[]')
+				) ORDER BY i.created_at)
+				FROM itineraries i WHERE i.user_id = $1
+			), '[]'),
+			'photos', COALESCE((SELECT jsonb_agg(to_jsonb(ph) ORDER BY ph.created_at) FROM photos ph WHERE ph.user_id = $1), '[]'),
+			'photo_votes', COALESCE((SELECT jsonb_agg(to_jsonb(pv) ORDER BY pv.created_at) FROM photo_votes pv WHERE pv.user_id = $1), '[]'),
+			'uploaded_assets', COALESCE((
+				SELECT jsonb_agg(jsonb_build_object(
+					'asset_id', a.id, 'content_hash', a.content_hash, 'category', a.category,
+					'status', a.status, 'format', a.original_format, 'width', a.original_width,
+					'height', a.original_height, 'size_bytes', a.original_size, 'created_at', a.created_at
+				) ORDER BY a.created_at)
+				FROM image_assets a WHERE a.created_by_user_id = $1
+			), '[]')
+		)
+	`, userID).Scan(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("build export: %w", err)
+	}
+	return doc, nil
+}
+
+// Anonymize deletes a user's private data and strips their identity from the
+// account row. Published contributions (approved POIs, reviews, comments,
+// photos) stay but are no longer attributable. It returns the Clerk ID the
+// account was linked to, if any.
+func (r *AccountRepository) Anonymize(ctx context.Context, userID uuid.UUID) (*string, error) {
+	var clerkID *string
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		conn := r.db.Conn(ctx)
+		err := conn.QueryRowContext(ctx, `
+			SELECT clerk_id FROM users WHERE user_id = $1 AND deleted_at IS NULL FOR UPDATE
+		`, userID).Scan(&clerkID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAccountNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		statements := []string{
+			`DELETE FROM saved_pois WHERE user_id = $1`,
+			`DELETE FROM itineraries WHERE user_id = $1`,
+			`DELETE FROM itinerary_collaborators WHERE user_id = $1`,
+			`DELETE FROM sync_queue WHERE user_id = $1`,
+			`DELETE FROM user_territory_stats WHERE user_id = $1`,
+			`DELETE FROM user_profiles WHERE user_id = $1`,
+			`DELETE FROM data_exports WHERE user_id = $1`,
+			`DELETE FROM user_notifications WHERE user_id = $1`,
+			`DELETE FROM email_preferences WHERE user_id = $1`,
+			`DELETE FROM announcement_dismissals WHERE user_id = $1`,
+			`DELETE FROM saved_searches WHERE user_id = $1`,
+			`DELETE FROM poi_edit_proposals WHERE proposer_id = $1 AND status = 'pending'`,
+			// Unpublished submissions were never public contributions
+			`DELETE FROM points_of_interest WHERE created_by = $1 AND status IN ('draft', 'pending', 'rejected')`,
+			`UPDATE users
+			 SET email = 'deleted+' || user_id || '@users.invalid', name = NULL, picture_url = NULL, avatar_asset_id = NULL,
+			     google_id = NULL, clerk_id = NULL, deleted_at = NOW(), updated_at = NOW()
+			 WHERE user_id = $1`,
+		}
+		for _, stmt := range statements {
+			if _, err := conn.ExecContext(ctx, stmt, userID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, ErrAccountNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("anonymize account: %w", err)
+	}
+	return clerkID, nil
+}
+
+// SetAvatar makes an image asset the user's picture and returns the asset the
+// previous avatar used, if it came from the imaging pipeline
+func (r *AccountRepository) SetAvatar(ctx context.Context, userID, assetID uuid.UUID, pictureURL string) (*uuid.UUID, error) {
+	var previous *uuid.UUID
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		conn := r.db.Conn(ctx)
+		err := conn.GetContext(ctx, &previous, `
+			SELECT avatar_asset_id FROM users WHERE user_id = $1 AND deleted_at IS NULL FOR UPDATE
+		`, userID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAccountNotFound
+		}
+		if err != nil {
+			return err
+		}
+		_, err = conn.ExecContext(ctx, `
+			UPDATE users SET picture_url = $2, avatar_asset_id = $3, updated_at = NOW() WHERE user_id = $1
+		`, userID, pictureURL, assetID)
+		return err
+	})
+	if errors.Is(err, ErrAccountNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("set avatar: %w", err)
+	}
+	return previous, nil
+}
+
+// FindLinkCandidates returns the other live accounts registered with one of
+// the given email addresses
+func (r *AccountRepository) FindLinkCandidates(ctx context.Context, userID uuid.UUID, emails []string) ([]models.LinkCandidate, error) {
+	candidates := []models.LinkCandidate{}
+	if len(emails) == 0 {
+		return candidates, nil
+	}
+	lowered := make([]string, len(emails))
+	for i, e := range emails {
+		lowered[i] = strings.ToLower(e)
+	}
+	err := r.db.Conn(ctx).SelectContext(ctx, &candidates, `
+		SELECT u.user_id, u.email, u.name, u.created_at,
+		       (SELECT COUNT(*) FROM points_of_interest p WHERE p.created_by = u.user_id) AS poi_count,
+		       (SELECT COUNT(*) FROM reviews rv WHERE rv.user_id = u.user_id) AS review_count
+		FROM users u
+		WHERE LOWER(u.email) = ANY($2) AND u.user_id <> $1 AND u.deleted_at IS NULL
+		ORDER BY u.created_at
+	`, userID, pq.Array(lowered))
+	if err != nil {
+		return nil, fmt.Errorf("find link candidates: %w", err)
+	}
+	return candidates, nil
+}
+
+// MergeAccounts folds source into target in one transaction. POIs (with their
+// founder credit), reviews, saved POIs, photos and blocks move to target; where
+// both accounts reviewed or saved the same POI, target's copy wins, and blocks
+// between the two accounts are dropped. The source account is retired and its
+// sign-in identity is linked to target.
+func (r *AccountRepository) MergeAccounts(ctx context.Context, sourceID, targetID uuid.UUID) (*models.AccountMerge, error) {
+	merge := models.AccountMerge{SourceUserID: sourceID, TargetUserID: targetID}
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		conn := r.db.Conn(ctx)
+
+		// Lock both accounts in a fixed order so concurrent merges cannot deadlock
+		var locked int
+		err := conn.GetContext(ctx, &locked, `
+			SELECT COUNT(*) FROM (
+				SELECT user_id FROM users
+				WHERE user_id IN ($1, $2) AND deleted_at IS NULL
+				ORDER BY user_id
+				FOR UPDATE
+			) l
+		`, sourceID, targetID)
+		if err != nil {
+			return err
+		}
+		if locked != 2 {
+			return ErrAccountNotFound
+		}
+
+		moves := []struct {
+			stmt  string
+			count *int
+		}{
+			{`UPDATE points_of_interest SET created_by = $2 WHERE created_by = $1`, &merge.POIsMoved},
+			{`UPDATE points_of_interest SET founding_user_id = $2 WHERE founding_user_id = $1`, nil},
+			{`DELETE FROM reviews s WHERE s.user_id = $1
+			  AND EXISTS (SELECT 1 FROM reviews t WHERE t.user_id = $2 AND t.poi_id = s.poi_id)`, nil},
+			{`UPDATE reviews SET user_id = $2 WHERE user_id = $1`, &merge.ReviewsMoved},
+			{`INSERT INTO saved_pois (user_id, poi_id, notes, created_at)
+			  SELECT $2, poi_id, notes, created_at FROM saved_pois WHERE user_id = $1
+			  ON CONFLICT (user_id, poi_id) DO NOTHING`, &merge.SavedPOIsMoved},
+			{`DELETE FROM saved_pois WHERE user_id = $1`, nil},
+			{`UPDATE photos SET user_id = $2 WHERE user_id = $1`, &merge.PhotosMoved},
+			{`INSERT INTO user_blocks (blocker_id, blocked_id, created_at)
+			  SELECT CASE WHEN blocker_id = $1 THEN $2 ELSE blocker_id END,
+			         CASE WHEN blocked_id = $1 THEN $2 ELSE blocked_id END, created_at
+			  FROM user_blocks
+			  WHERE (blocker_id = $1 OR blocked_id = $1) AND blocker_id <> $2 AND blocked_id <> $2
+			  ON CONFLICT (blocker_id, blocked_id) DO NOTHING`, nil},
+			{`DELETE FROM user_blocks WHERE blocker_id = $1 OR blocked_id = $1`, nil},
+			{`UPDATE image_assets SET created_by_user_id = $2 WHERE created_by_user_id = $1`, nil},
+			{`UPDATE user_identities SET user_id = $2 WHERE user_id = $1`, nil},
+			{`INSERT INTO user_identities (clerk_id, user_id)
+			  SELECT clerk_id, $2 FROM users WHERE user_id = $1 AND clerk_id IS NOT NULL
+			  ON CONFLICT (clerk_id) DO UPDATE SET user_id = EXCLUDED.user_id, linked_at = NOW()`, nil},
+			{`UPDATE users
+			  SET email = 'merged+' || user_id || '@users.invalid', clerk_id = NULL, google_id = NULL,
+			      deleted_at = NOW(), updated_at = NOW()
+			  WHERE user_id = $1`, nil},
+		}
+		for _, m := range moves {
+			res, err := conn.ExecContext(ctx, m.stmt, sourceID, targetID)
+			if err != nil {
+				return err
+			}
+			if m.count != nil {
+				n, _ := res.RowsAffected()
+				*m.count = int(n)
+			}
+		}
+
+		return conn.GetContext(ctx, &merge, `
+			INSERT INTO user_account_merges (source_user_id, target_user_id, pois_moved, reviews_moved, saved_pois_moved, photos_moved)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING merge_id, source_user_id, target_user_id, pois_moved, reviews_moved, saved_pois_moved, photos_moved, merged_at
+		`, sourceID, targetID, merge.POIsMoved, merge.ReviewsMoved, merge.SavedPOIsMoved, merge.PhotosMoved)
+	})
+	if errors.Is(err, ErrAccountNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("merge accounts: %w", err)
+	}
+	return &merge, nil
+}
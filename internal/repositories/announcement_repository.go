@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrAnnouncementNotFound is returned when an announcement does not exist
+	ErrAnnouncementNotFound = errors.New("announcement not found")
+	// ErrAnnouncementNotDismissible is returned when dismissing an announcement that must stay visible
+	ErrAnnouncementNotDismissible = errors.New("announcement cannot be dismissed")
+)
+
+// AnnouncementViewer identifies who is asking for active announcements
+type AnnouncementViewer struct {
+	UserID *uuid.UUID // nil when signed out
+	Role   string
+}
+
+// AnnouncementRepository handles announcements and their dismissals
+type AnnouncementRepository struct {
+	db *database.DB
+}
+
+// NewAnnouncementRepository creates a new announcement repository
+func NewAnnouncementRepository(db *database.DB) *AnnouncementRepository {
+	return &AnnouncementRepository{db: db}
+}
+
+const announcementColumns = `announcement_id, title, body, link_url, severity, audience, roles, dismissible, starts_at, ends_at, created_by, created_at, updated_at`
+
+// ListActive returns the announcements shown to viewer now, most severe first.
+// Announcements the viewer dismissed are left out.
+func (r *AnnouncementRepository) ListActive(ctx context.Context, viewer AnnouncementViewer) ([]models.Announcement, error) {
+	announcements := []models.Announcement{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &announcements, `
+		SELECT `+announcementColumns+`
+		FROM announcements a
+		WHERE a.starts_at <= NOW() AND (a.ends_at IS NULL OR a.ends_at > NOW())
+		  AND (a.audience = 'all'
+		       OR (a.audience = 'signed_in' AND $1::uuid IS NOT NULL)
+		       OR (a.audience = 'signed_out' AND $1::uuid IS NULL))
+		  AND (cardinality(a.roles) = 0 OR ($1::uuid IS NOT NULL AND $2 = ANY(a.roles)))
+		  AND NOT EXISTS (
+		      SELECT 1 FROM announcement_dismissals d
+		      WHERE d.announcement_id = a.announcement_id AND d.user_id = $1
+		  )
+		ORDER BY CASE a.severity WHEN 'critical' THEN 0 WHEN 'warning' THEN 1 ELSE 2 END, a.starts_at DESC
+	`, viewer.UserID, viewer.Role)
+	if err != nil {
+		return nil, fmt.Errorf("list active announcements: %w", err)
+	}
+	return announcements, nil
+}
+
+// List returns every announcement with its dismissal count, newest first
+func (r *AnnouncementRepository) List(ctx context.Context, limit, offset int) ([]models.Announcement, error) {
+	announcements := []models.Announcement{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &announcements, `
+		SELECT `+announcementColumns+`,
+		       (SELECT COUNT(*) FROM announcement_dismissals d WHERE d.announcement_id = a.announcement_id) AS dismissals
+		FROM announcements a
+		ORDER BY a.starts_at DESC
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list announcements: %w", err)
+	}
+	return announcements, nil
+}
+
+// Create stores a new announcement
+func (r *AnnouncementRepository) Create(ctx context.Context, a *models.Announcement) (*models.Announcement, error) {
+	var created models.Announcement
+	err := r.db.Conn(ctx).GetContext(ctx, &created, `
+		INSERT INTO announcements (title, body, link_url, severity, audience, roles, dismissible, starts_at, ends_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING `+announcementColumns,
+		a.Title, a.Body, a.LinkURL, a.Severity, a.Audience, a.Roles, a.Dismissible, a.StartsAt, a.EndsAt, a.CreatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("create announcement: %w", err)
+	}
+	return &created, nil
+}
+
+// Update replaces an announcement. Dismissals are kept.
+func (r *AnnouncementRepository) Update(ctx context.Context, a *models.Announcement) (*models.Announcement, error) {
+	var updated models.Announcement
+	err := r.db.Conn(ctx).GetContext(ctx, &updated, `
+		UPDATE announcements
+		SET title = $2, body = $3, link_url = $4, severity = $5, audience = $6, roles = $7,
+		    dismissible = $8, starts_at = $9, ends_at = $10, updated_at = NOW()
+		WHERE announcement_id = $1
+		RETURNING `+announcementColumns,
+		a.AnnouncementID, a.Title, a.Body, a.LinkURL, a.Severity, a.Audience, a.Roles, a.Dismissible, a.StartsAt, a.EndsAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAnnouncementNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("update announcement: %w", err)
+	}
+	return &updated, nil
+}
+
+// Delete removes an announcement and its dismissals
+func (r *AnnouncementRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.Conn(ctx).ExecContext(ctx, `DELETE FROM announcements WHERE announcement_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete announcement: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrAnnouncementNotFound
+	}
+	return nil
+}
+
+// Dismiss hides an announcement from a user; dismissing twice is a no-op
+func (r *AnnouncementRepository) Dismiss(ctx context.Context, id, userID uuid.UUID) error {
+	var dismissible bool
+	err := r.db.Conn(ctx).GetContext(ctx, &dismissible, `SELECT dismissible FROM announcements WHERE announcement_id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrAnnouncementNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("get announcement: %w", err)
+	}
+	if !dismissible {
+		return ErrAnnouncementNotDismissible
+	}
+
+	_, err = r.db.Conn(ctx).ExecContext(ctx, `
+		INSERT INTO announcement_dismissals (announcement_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, id, userID)
+	if err != nil {
+		return fmt.Errorf("dismiss announcement: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	// sessionIdleDays is how long an unused anonymous session is kept
+	sessionIdleDays = 30
+	// sessionRecentPOIs and sessionRecentSearches bound the remembered history
+	sessionRecentPOIs     = 50
+	sessionRecentSearches = 10
+	// MaxSessionSavedPOIs is how many POIs a signed-out client can save
+	MaxSessionSavedPOIs = 100
+)
+
+var (
+	// ErrSessionNotFound is returned when an anonymous session does not exist or expired
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrSessionSavedLimit is returned when the session already saved MaxSessionSavedPOIs
+	ErrSessionSavedLimit = errors.New("session saved POI limit reached")
+	// ErrSessionPOINotFound is returned when saving a POI that does not exist or is not published
+	ErrSessionPOINotFound = errors.New("poi not found")
+)
+
+// sessionActive matches sessions that have not expired (anonymous_sessions aliased as s)
+var sessionActive = fmt.Sprintf("s.last_seen_at > NOW() - INTERVAL '%d days'", sessionIdleDays)
+
+// AnonymousSessionRepository stores signed-out browsing sessions
+type AnonymousSessionRepository struct {
+	db *database.DB
+}
+
+// NewAnonymousSessionRepository creates a new anonymous session repository
+func NewAnonymousSessionRepository(db *database.DB) *AnonymousSessionRepository {
+	return &AnonymousSessionRepository{db: db}
+}
+
+// Create starts a session, clearing out expired ones first
+func (r *AnonymousSessionRepository) Create(ctx context.Context, sessionID string) (*models.AnonymousSession, error) {
+	conn := r.db.Conn(ctx)
+	_, err := conn.ExecContext(ctx, `
+		DELETE FROM anonymous_sessions s WHERE NOT (`+sessionActive+`)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("purge anonymous sessions: %w", err)
+	}
+
+	session := &models.AnonymousSession{RecentPOIs: []models.SessionPOI{}, SavedPOIs: []models.SessionPOI{}}
+	err = conn.GetContext(ctx, session, `
+		INSERT INTO anonymous_sessions (session_id) VALUES ($1)
+		RETURNING session_id, recent_searches, created_at, last_seen_at
+	`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("create anonymous session: %w", err)
+	}
+	return session, nil
+}
+
+// Get returns a session with its recent and saved POIs (approved ones only)
+// and marks it as used
+func (r *AnonymousSessionRepository) Get(ctx context.Context, sessionID string) (*models.AnonymousSession, error) {
+	conn := r.db.Conn(ctx)
+	var session models.AnonymousSession
+	var recentSearches []byte
+	var recentIDs pq.StringArray
+	err := conn.QueryRowContext(ctx, `
+		UPDATE anonymous_sessions s SET last_seen_at = NOW()
+		WHERE s.session_id = $1 AND `+sessionActive+`
+		RETURNING s.session_id, s.recent_searches, s.created_at, s.last_seen_at, s.recent_poi_ids::text[]
+	`, sessionID).Scan(&session.SessionID, &recentSearches, &session.CreatedAt, &session.LastSeenAt, &recentIDs)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get anonymous session: %w", err)
+	}
+	session.RecentSearches = recentSearches
+
+	session.RecentPOIs = []models.SessionPOI{}
+	err = conn.SelectContext(ctx, &session.RecentPOIs, `
+		SELECT p.poi_id, p.name, p.cover_image_url, p.rating_avg
+		FROM unnest($1::uuid[]) WITH ORDINALITY AS r(poi_id, ord)
+		JOIN points_of_interest p ON p.poi_id = r.poi_id AND p.status = 'approved'
+		ORDER BY r.ord
+	`, recentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get session recent pois: %w", err)
+	}
+
+	session.SavedPOIs = []models.SessionPOI{}
+	err = conn.SelectContext(ctx, &session.SavedPOIs, `
+		SELECT p.poi_id, p.name, p.cover_image_url, p.rating_avg, a.created_at AS saved_at
+		FROM anonymous_saved_pois a
+		JOIN points_of_interest p ON p.poi_id = a.poi_id AND p.status = 'approved'
+		WHERE a.session_id = $1
+		ORDER BY a.created_at DESC
+	`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session saved pois: %w", err)
+	}
+	return &session, nil
+}
+
+// RecordView puts a POI first in the session's recently viewed list.
+// Unknown or expired sessions are ignored.
+func (r *AnonymousSessionRepository) RecordView(ctx context.Context, sessionID string, poiID uuid.UUID) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE anonymous_sessions s
+		SET recent_poi_ids = (array_prepend($2::uuid, array_remove(s.recent_poi_ids, $2::uuid)))[1:$3],
+		    last_seen_at = NOW()
+		WHERE s.session_id = $1 AND `+sessionActive,
+		sessionID, poiID, sessionRecentPOIs)
+	if err != nil {
+		return fmt.Errorf("record session view: %w", err)
+	}
+	return nil
+}
+
+// RecordSearch puts a set of search parameters first in the session's recent
+// searches, dropping an identical earlier one. Unknown or expired sessions are ignored.
+func (r *AnonymousSessionRepository) RecordSearch(ctx context.Context, sessionID string, params map[string]string) error {
+	payload, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("encode session search: %w", err)
+	}
+	_, err = r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE anonymous_sessions s
+		SET recent_searches = jsonb_build_array($2::jsonb) || COALESCE((
+		        SELECT jsonb_agg(e ORDER BY i)
+		        FROM jsonb_array_elements(s.recent_searches) WITH ORDINALITY AS x(e, i)
+		        WHERE e <> $2::jsonb AND i < $3
+		    ), '[]'),
+		    last_seen_at = NOW()
+		WHERE s.session_id = $1 AND `+sessionActive,
+		sessionID, payload, sessionRecentSearches)
+	if err != nil {
+		return fmt.Errorf("record session search: %w", err)
+	}
+	return nil
+}
+
+// SavePOI adds a POI to the session's temporary saved list; saving twice is a no-op
+func (r *AnonymousSessionRepository) SavePOI(ctx context.Context, sessionID string, poiID uuid.UUID) error {
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		conn := r.db.Conn(ctx)
+		var saved int
+		err := conn.QueryRowContext(ctx, `
+			SELECT (SELECT COUNT(*) FROM anonymous_saved_pois WHERE session_id = s.session_id)
+			FROM anonymous_sessions s
+			WHERE s.session_id = $1 AND `+sessionActive+`
+			FOR UPDATE
+		`, sessionID).Scan(&saved)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSessionNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("get anonymous session: %w", err)
+		}
+		if saved >= MaxSessionSavedPOIs {
+			return ErrSessionSavedLimit
+		}
+
+		var approved bool
+		err = conn.GetContext(ctx, &approved, `
+			SELECT EXISTS (SELECT 1 FROM points_of_interest WHERE poi_id = $1 AND status = 'approved')
+		`, poiID)
+		if err != nil {
+			return fmt.Errorf("get poi status: %w", err)
+		}
+		if !approved {
+			return ErrSessionPOINotFound
+		}
+
+		_, err = conn.ExecContext(ctx, `
+			INSERT INTO anonymous_saved_pois (session_id, poi_id) VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, sessionID, poiID)
+		if err != nil {
+			return fmt.Errorf("save session poi: %w", err)
+		}
+		_, err = conn.ExecContext(ctx, `UPDATE anonymous_sessions SET last_seen_at = NOW() WHERE session_id = $1`, sessionID)
+		return err
+	})
+}
+
+// UnsavePOI removes a POI from the session's temporary saved list
+func (r *AnonymousSessionRepository) UnsavePOI(ctx context.Context, sessionID string, poiID uuid.UUID) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		DELETE FROM anonymous_saved_pois WHERE session_id = $1 AND poi_id = $2
+	`, sessionID, poiID)
+	if err != nil {
+		return fmt.Errorf("unsave session poi: %w", err)
+	}
+	return nil
+}
+
+// Merge moves the session's saved POIs into the user's saved list and ends
+// the session. It returns how many POIs were newly saved.
+func (r *AnonymousSessionRepository) Merge(ctx context.Context, sessionID string, userID uuid.UUID) (int64, error) {
+	var merged int64
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		conn := r.db.Conn(ctx)
+		var exists bool
+		err := conn.GetContext(ctx, &exists, `
+			SELECT EXISTS (SELECT 1 FROM anonymous_sessions s WHERE s.session_id = $1 AND `+sessionActive+`)
+		`, sessionID)
+		if err != nil {
+			return fmt.Errorf("get anonymous session: %w", err)
+		}
+		if !exists {
+			return ErrSessionNotFound
+		}
+
+		res, err := conn.ExecContext(ctx, `
+			INSERT INTO saved_pois (user_id, poi_id, created_at)
+			SELECT $2, poi_id, created_at FROM anonymous_saved_pois WHERE session_id = $1
+			ON CONFLICT (user_id, poi_id) DO NOTHING
+		`, sessionID, userID)
+		if err != nil {
+			return fmt.Errorf("merge session saved pois: %w", err)
+		}
+		merged, _ = res.RowsAffected()
+
+		if _, err := conn.ExecContext(ctx, `DELETE FROM anonymous_sessions WHERE session_id = $1`, sessionID); err != nil {
+			return fmt.Errorf("delete anonymous session: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return merged, nil
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+)
+
+// recordAudit appends a moderation action to the audit log. Call it inside the
+// transaction of the action so the two are never out of step.
+func recordAudit(ctx context.Context, db *database.DB, entry models.AuditEntry) error {
+	details := []byte("{}")
+	if len(entry.Details) > 0 {
+		var err error
+		if details, err = json.Marshal(entry.Details); err != nil {
+			return fmt.Errorf("marshal audit details: %w", err)
+		}
+	}
+	_, err := db.Conn(ctx).ExecContext(ctx, `
+		INSERT INTO audit_log (actor_id, action, target_type, target_id, reason, details)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+	`, entry.ActorID, entry.Action, entry.TargetType, entry.TargetID, entry.Reason, details)
+	if err != nil {
+		return fmt.Errorf("record audit entry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// BlockRepository handles the users a user blocked
+type BlockRepository struct {
+	db *database.DB
+}
+
+// NewBlockRepository creates a new block repository
+func NewBlockRepository(db *database.DB) *BlockRepository {
+	return &BlockRepository{db: db}
+}
+
+// Block hides blockedID's comments and reviews from blockerID. Blocking again
+// is a no-op.
+func (r *BlockRepository) Block(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	var exists bool
+	err := r.db.Conn(ctx).GetContext(ctx, &exists, `
+		SELECT EXISTS (SELECT 1 FROM users WHERE user_id = $1 AND deleted_at IS NULL)
+	`, blockedID)
+	if err != nil {
+		return fmt.Errorf("check blocked user: %w", err)
+	}
+	if !exists {
+		return ErrUserNotFound
+	}
+
+	_, err = r.db.Conn(ctx).ExecContext(ctx, `
+		INSERT INTO user_blocks (blocker_id, blocked_id) VALUES ($1, $2)
+		ON CONFLICT (blocker_id, blocked_id) DO NOTHING
+	`, blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("block user: %w", err)
+	}
+	return nil
+}
+
+// Unblock removes a block; removing one that does not exist is a no-op
+func (r *BlockRepository) Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2
+	`, blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("unblock user: %w", err)
+	}
+	return nil
+}
+
+// ListBlocked returns the users blockerID blocked, most recent first
+func (r *BlockRepository) ListBlocked(ctx context.Context, blockerID uuid.UUID, limit, offset int) ([]models.BlockedUser, error) {
+	blocked := []models.BlockedUser{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &blocked, `
+		SELECT u.user_id, p.username, u.name,
+		       COALESCE(p.avatar_url, u.picture_url) AS avatar_url,
+		       b.created_at AS blocked_at
+		FROM user_blocks b
+		JOIN users u ON u.user_id = b.blocked_id
+		LEFT JOIN user_profiles p ON p.user_id = u.user_id
+		WHERE b.blocker_id = $1
+		ORDER BY b.created_at DESC
+		LIMIT $2 OFFSET $3
+	`, blockerID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list blocked users: %w", err)
+	}
+	return blocked, nil
+}
@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrVerificationNotFound   = errors.New("business verification not found")
+	ErrVerificationNotPending = errors.New("business verification already reviewed")
+	ErrVerificationOpen       = errors.New("a business verification request is already pending for this POI")
+	ErrAlreadyVerified        = errors.New("POI is already verified")
+)
+
+// BusinessVerificationRepository handles "verified business" requests
+type BusinessVerificationRepository struct {
+	db *database.DB
+}
+
+// NewBusinessVerificationRepository creates a new business verification repository
+func NewBusinessVerificationRepository(db *database.DB) *BusinessVerificationRepository {
+	return &BusinessVerificationRepository{db: db}
+}
+
+const businessVerificationColumns = `
+	bv.verification_id, bv.poi_id, bv.requester_id, bv.business_name, bv.note, bv.document_keys,
+	bv.status, bv.reviewer_id, bv.review_note, bv.created_at, bv.reviewed_at,
+	p.name as poi_name, u.name as requester_name`
+
+const businessVerificationFrom = `
+	FROM poi_business_verifications bv
+	JOIN points_of_interest p ON p.poi_id = bv.poi_id
+	LEFT JOIN users u ON u.user_id = bv.requester_id`
+
+// Create opens a request for a POI that is not verified yet
+func (r *BusinessVerificationRepository) Create(ctx context.Context, poiID, requesterID uuid.UUID, businessName, note *string, documentKeys []string) (*models.BusinessVerification, error) {
+	var id uuid.UUID
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		conn := r.db.Conn(ctx)
+		var verified bool
+		err := conn.GetContext(ctx, &verified, `SELECT COALESCE(is_verified, FALSE) FROM points_of_interest WHERE poi_id = $1 FOR UPDATE`, poiID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("create business verification: poi %s: %w", poiID, sql.ErrNoRows)
+		}
+		if err != nil {
+			return fmt.Errorf("lock poi: %w", err)
+		}
+		if verified {
+			return ErrAlreadyVerified
+		}
+
+		err = conn.GetContext(ctx, &id, `
+			INSERT INTO poi_business_verifications (poi_id, requester_id, business_name, note, document_keys)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING verification_id
+		`, poiID, requesterID, businessName, note, pq.Array(documentKeys))
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return ErrVerificationOpen
+		}
+		if err != nil {
+			return fmt.Errorf("create business verification: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+// GetByID retrieves a request by ID
+func (r *BusinessVerificationRepository) GetByID(ctx context.Context, verificationID uuid.UUID) (*models.BusinessVerification, error) {
+	var v models.BusinessVerification
+	err := r.db.Conn(ctx).GetContext(ctx, &v, `SELECT `+businessVerificationColumns+businessVerificationFrom+`
+		WHERE bv.verification_id = $1`, verificationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrVerificationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get business verification: %w", err)
+	}
+	v.DocumentCount = len(v.DocumentKeys)
+	return &v, nil
+}
+
+// GetLatestForPOI returns the most recent request for a POI
+func (r *BusinessVerificationRepository) GetLatestForPOI(ctx context.Context, poiID uuid.UUID) (*models.BusinessVerification, error) {
+	var v models.BusinessVerification
+	err := r.db.Conn(ctx).GetContext(ctx, &v, `SELECT `+businessVerificationColumns+businessVerificationFrom+`
+		WHERE bv.poi_id = $1
+		ORDER BY bv.created_at DESC
+		LIMIT 1`, poiID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrVerificationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get latest business verification: %w", err)
+	}
+	v.DocumentCount = len(v.DocumentKeys)
+	return &v, nil
+}
+
+// ListByStatus lists requests across all POIs (review queue), oldest first
+func (r *BusinessVerificationRepository) ListByStatus(ctx context.Context, status string, limit, offset int) ([]models.BusinessVerification, error) {
+	verifications := []models.BusinessVerification{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &verifications, `SELECT `+businessVerificationColumns+businessVerificationFrom+`
+		WHERE bv.status = $1
+		ORDER BY bv.created_at ASC
+		LIMIT $2 OFFSET $3`, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list business verifications: %w", err)
+	}
+	for i := range verifications {
+		verifications[i].DocumentCount = len(verifications[i].DocumentKeys)
+	}
+	return verifications, nil
+}
+
+// Approve marks a pending request approved and verifies its POI in one transaction
+func (r *BusinessVerificationRepository) Approve(ctx context.Context, verificationID, reviewerID uuid.UUID, reviewNote *string) (*models.BusinessVerification, error) {
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		poiID, err := r.markReviewed(ctx, verificationID, "approved", reviewerID, reviewNote)
+		if err != nil {
+			return err
+		}
+		_, err = r.db.Conn(ctx).ExecContext(ctx, `
+			UPDATE points_of_interest
+			SET is_verified = TRUE, verified_at = NOW(), updated_at = NOW()
+			WHERE poi_id = $1
+		`, poiID)
+		if err != nil {
+			return fmt.Errorf("verify poi: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, verificationID)
+}
+
+// Reject marks a pending request rejected
+func (r *BusinessVerificationRepository) Reject(ctx context.Context, verificationID, reviewerID uuid.UUID, reviewNote *string) (*models.BusinessVerification, error) {
+	if _, err := r.markReviewed(ctx, verificationID, "rejected", reviewerID, reviewNote); err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, verificationID)
+}
+
+// Withdraw lets the requester retract a pending request
+func (r *BusinessVerificationRepository) Withdraw(ctx context.Context, verificationID, requesterID uuid.UUID) error {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE poi_business_verifications SET status = 'withdrawn'
+		WHERE verification_id = $1 AND requester_id = $2 AND status = 'pending'
+	`, verificationID, requesterID)
+	if err != nil {
+		return fmt.Errorf("withdraw business verification: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("withdraw business verification rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrVerificationNotPending
+	}
+	return nil
+}
+
+// markReviewed moves a pending request to status and returns its POI
+func (r *BusinessVerificationRepository) markReviewed(ctx context.Context, verificationID uuid.UUID, status string, reviewerID uuid.UUID, reviewNote *string) (uuid.UUID, error) {
+	var poiID uuid.UUID
+	err := r.db.Conn(ctx).GetContext(ctx, &poiID, `
+		UPDATE poi_business_verifications
+		SET status = $2, reviewer_id = $3, review_note = $4, reviewed_at = NOW()
+		WHERE verification_id = $1 AND status = 'pending'
+		RETURNING poi_id
+	`, verificationID, status, reviewerID, reviewNote)
+	if errors.Is(err, sql.ErrNoRows) {
+		if _, getErr := r.GetByID(ctx, verificationID); errors.Is(getErr, ErrVerificationNotFound) {
+			return uuid.Nil, ErrVerificationNotFound
+		}
+		return uuid.Nil, ErrVerificationNotPending
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("mark business verification %s: %w", status, err)
+	}
+	return poiID, nil
+}
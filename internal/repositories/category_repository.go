@@ -8,6 +8,7 @@ import (
 	"maukemana-backend/internal/database"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // CategoryRepository handles category database operations
@@ -27,18 +28,21 @@ type Category struct {
 	NameKey          string     `db:"name_key" json:"name_key"`
 	Icon             *string    `db:"icon" json:"icon,omitempty"`
 	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
+	Label            string     `db:"label" json:"label"` // Localized display label
 }
 
-// GetAll retrieves all categories
-func (r *CategoryRepository) GetAll(ctx context.Context) ([]Category, error) {
+// GetAll retrieves all categories with labels in the first available of locales
+// (in preference order), falling back to name_key
+func (r *CategoryRepository) GetAll(ctx context.Context, locales []string) ([]Category, error) {
 	query := `
-		SELECT category_id, parent_category_id, name_key, icon, created_at
+		SELECT category_id, parent_category_id, name_key, icon, created_at,
+		       COALESCE(` + labelSubquery("category", "categories.category_id", "$1") + `, name_key) as label
 		FROM categories
 		ORDER BY name_key
 	`
 
 	var categories []Category
-	err := r.db.SelectContext(ctx, &categories, query)
+	err := r.db.Conn(ctx).SelectContext(ctx, &categories, query, pq.StringArray(locales))
 	if err != nil {
 		return nil, fmt.Errorf("get all categories: %w", err)
 	}
@@ -8,6 +8,8 @@ import (
 	"maukemana-backend/internal/models"
 
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type CommentRepository struct {
@@ -24,7 +26,7 @@ func (r *CommentRepository) Create(ctx context.Context, comment *models.Comment)
 		VALUES (:poi_id, :user_id, :content, :parent_id)
 		RETURNING comment_id, created_at, updated_at
 	`
-	rows, err := r.db.NamedQueryContext(ctx, query, comment)
+	rows, err := sqlx.NamedQueryContext(ctx, r.db.Conn(ctx), query, comment)
 	if err != nil {
 		return fmt.Errorf("create comment: %w", err)
 	}
@@ -39,7 +41,9 @@ func (r *CommentRepository) Create(ctx context.Context, comment *models.Comment)
 	return nil
 }
 
-func (r *CommentRepository) GetByPOI(ctx context.Context, poiID uuid.UUID, limit, offset int) ([]models.Comment, error) {
+// GetByPOI returns the newest top-level comments of a POI, leaving out hidden
+// comments. Comments of shadow-banned users are only returned to their author (viewerID).
+func (r *CommentRepository) GetByPOI(ctx context.Context, poiID uuid.UUID, viewerID *uuid.UUID, limit, offset int) ([]models.Comment, error) {
 	query := `
 		SELECT
 			c.*,
@@ -48,19 +52,20 @@ func (r *CommentRepository) GetByPOI(ctx context.Context, poiID uuid.UUID, limit
 			u.picture_url "user.picture_url"
 		FROM comments c
 		JOIN users u ON c.user_id = u.user_id
-		WHERE c.poi_id = $1 AND c.parent_id IS NULL
+		WHERE c.poi_id = $1 AND c.parent_id IS NULL AND c.hidden_at IS NULL AND ` + visibleAuthor("$4") + `
 		ORDER BY c.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	var comments []models.Comment
-	err := r.db.SelectContext(ctx, &comments, query, poiID, limit, offset)
+	comments := []models.Comment{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &comments, query, poiID, limit, offset, viewerID)
 	if err != nil {
 		return nil, fmt.Errorf("get comments by poi: %w", err)
 	}
 	return comments, nil
 }
 
-func (r *CommentRepository) GetReplies(ctx context.Context, parentID uuid.UUID) ([]models.Comment, error) {
+// GetReplies returns the replies to a comment, oldest first, hiding shadow-banned authors from others
+func (r *CommentRepository) GetReplies(ctx context.Context, parentID uuid.UUID, viewerID *uuid.UUID) ([]models.Comment, error) {
 	query := `
 		SELECT
 			c.*,
@@ -69,11 +74,11 @@ func (r *CommentRepository) GetReplies(ctx context.Context, parentID uuid.UUID)
 			u.picture_url "user.picture_url"
 		FROM comments c
 		JOIN users u ON c.user_id = u.user_id
-		WHERE c.parent_id = $1
+		WHERE c.parent_id = $1 AND c.hidden_at IS NULL AND ` + visibleAuthor("$2") + `
 		ORDER BY c.created_at ASC
 	`
-	var comments []models.Comment
-	err := r.db.SelectContext(ctx, &comments, query, parentID)
+	comments := []models.Comment{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &comments, query, parentID, viewerID)
 	if err != nil {
 		return nil, fmt.Errorf("get replies: %w", err)
 	}
@@ -82,7 +87,7 @@ func (r *CommentRepository) GetReplies(ctx context.Context, parentID uuid.UUID)
 
 func (r *CommentRepository) Delete(ctx context.Context, commentID uuid.UUID, userID uuid.UUID) error {
 	query := `DELETE FROM comments WHERE comment_id = $1 AND user_id = $2`
-	result, err := r.db.ExecContext(ctx, query, commentID, userID)
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query, commentID, userID)
 	if err != nil {
 		return fmt.Errorf("delete comment: %w", err)
 	}
@@ -95,3 +100,60 @@ func (r *CommentRepository) Delete(ctx context.Context, commentID uuid.UUID, use
 	}
 	return nil
 }
+
+// GetByPOIs returns up to perPOI of the most recent top-level comments of each POI, grouped by POI.
+// Shadow-banned authors are hidden from everyone but themselves (viewerID).
+func (r *CommentRepository) GetByPOIs(ctx context.Context, poiIDs []uuid.UUID, viewerID *uuid.UUID, perPOI int) (map[uuid.UUID][]models.Comment, error) {
+	query := `
+		SELECT
+			c.comment_id, c.poi_id, c.user_id, c.content, c.parent_id, c.created_at, c.updated_at,
+			u.user_id "user.user_id",
+			u.name "user.name",
+			u.picture_url "user.picture_url"
+		FROM (
+			SELECT cm.*, row_number() OVER (PARTITION BY cm.poi_id ORDER BY cm.created_at DESC) as rn
+			FROM comments cm
+			JOIN users u ON cm.user_id = u.user_id
+			WHERE cm.poi_id = ANY($1::uuid[]) AND cm.parent_id IS NULL AND cm.hidden_at IS NULL AND ` + visibleAuthor("$3") + `
+		) c
+		JOIN users u ON c.user_id = u.user_id
+		WHERE c.rn <= $2
+		ORDER BY c.poi_id, c.created_at DESC
+	`
+	var comments []models.Comment
+	if err := r.db.Conn(ctx).SelectContext(ctx, &comments, query, pq.Array(poiIDs), perPOI, viewerID); err != nil {
+		return nil, fmt.Errorf("get comments by pois: %w", err)
+	}
+
+	byPOI := make(map[uuid.UUID][]models.Comment, len(poiIDs))
+	for _, cm := range comments {
+		byPOI[cm.PoiID] = append(byPOI[cm.PoiID], cm)
+	}
+	return byPOI, nil
+}
+
+// GetRepliesByParents returns the replies of several comments, oldest first, grouped by parent.
+// Shadow-banned authors are hidden from everyone but themselves (viewerID).
+func (r *CommentRepository) GetRepliesByParents(ctx context.Context, parentIDs []uuid.UUID, viewerID *uuid.UUID) (map[uuid.UUID][]models.Comment, error) {
+	query := `
+		SELECT
+			c.*,
+			u.user_id "user.user_id",
+			u.name "user.name",
+			u.picture_url "user.picture_url"
+		FROM comments c
+		JOIN users u ON c.user_id = u.user_id
+		WHERE c.parent_id = ANY($1::uuid[]) AND c.hidden_at IS NULL AND ` + visibleAuthor("$2") + `
+		ORDER BY c.created_at ASC
+	`
+	var comments []models.Comment
+	if err := r.db.Conn(ctx).SelectContext(ctx, &comments, query, pq.Array(parentIDs), viewerID); err != nil {
+		return nil, fmt.Errorf("get replies by parents: %w", err)
+	}
+
+	byParent := make(map[uuid.UUID][]models.Comment, len(parentIDs))
+	for _, cm := range comments {
+		byParent[*cm.ParentID] = append(byParent[*cm.ParentID], cm)
+	}
+	return byParent, nil
+}
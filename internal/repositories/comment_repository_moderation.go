@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrCommentNotFound is returned when a comment does not exist
+	ErrCommentNotFound = errors.New("comment not found")
+	// ErrUserNotBanned is returned when purging the comments of a user who is not banned
+	ErrUserNotBanned = errors.New("user is not banned")
+)
+
+// HideComment hides a comment from every listing and records the action in the
+// audit log. A pending text moderation flag on the comment is resolved.
+func (r *CommentRepository) HideComment(ctx context.Context, commentID, actorID uuid.UUID, reason string) (*models.Comment, error) {
+	var comment models.Comment
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		err := r.db.Conn(ctx).GetContext(ctx, &comment, `
+			UPDATE comments
+			SET hidden_at = NOW(), hidden_by = $2, hidden_reason = $3
+			WHERE comment_id = $1
+			RETURNING *
+		`, commentID, actorID, reason)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCommentNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("hide comment: %w", err)
+		}
+		if err := r.resolveCommentFlags(ctx, []uuid.UUID{commentID}, actorID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, r.db, models.AuditEntry{
+			ActorID:    actorID,
+			Action:     models.AuditCommentHide,
+			TargetType: models.AuditTargetComment,
+			TargetID:   commentID,
+			Reason:     reason,
+			Details:    map[string]any{"author_id": comment.UserID, "poi_id": comment.PoiID},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// DeleteAnyComment deletes a comment regardless of its author, with its
+// replies, and records the action in the audit log
+func (r *CommentRepository) DeleteAnyComment(ctx context.Context, commentID, actorID uuid.UUID, reason string) error {
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		var comment models.Comment
+		err := r.db.Conn(ctx).GetContext(ctx, &comment, `
+			DELETE FROM comments WHERE comment_id = $1 RETURNING *
+		`, commentID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCommentNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("delete comment: %w", err)
+		}
+		if err := r.clearCommentFlags(ctx); err != nil {
+			return err
+		}
+		return recordAudit(ctx, r.db, models.AuditEntry{
+			ActorID:    actorID,
+			Action:     models.AuditCommentDelete,
+			TargetType: models.AuditTargetComment,
+			TargetID:   commentID,
+			Reason:     reason,
+			Details: map[string]any{
+				"author_id": comment.UserID,
+				"poi_id":    comment.PoiID,
+				"content":   comment.Content,
+			},
+		})
+	})
+}
+
+// DeleteCommentsByUser deletes every comment of a shadow-banned user and
+// records one audit log entry for the purge. It returns the number of
+// comments the user wrote that were deleted.
+func (r *CommentRepository) DeleteCommentsByUser(ctx context.Context, userID, actorID uuid.UUID, reason string) (int, error) {
+	var deleted int
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		var banned bool
+		err := r.db.Conn(ctx).GetContext(ctx, &banned, `
+			SELECT shadow_banned FROM users WHERE user_id = $1 FOR UPDATE
+		`, userID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("check user ban: %w", err)
+		}
+		if !banned {
+			return ErrUserNotBanned
+		}
+
+		var ids []uuid.UUID
+		err = r.db.Conn(ctx).SelectContext(ctx, &ids, `
+			DELETE FROM comments WHERE user_id = $1 RETURNING comment_id
+		`, userID)
+		if err != nil {
+			return fmt.Errorf("delete user comments: %w", err)
+		}
+		deleted = len(ids)
+		if err := r.clearCommentFlags(ctx); err != nil {
+			return err
+		}
+		return recordAudit(ctx, r.db, models.AuditEntry{
+			ActorID:    actorID,
+			Action:     models.AuditUserCommentsPurge,
+			TargetType: models.AuditTargetUser,
+			TargetID:   userID,
+			Reason:     reason,
+			Details:    map[string]any{"deleted": deleted},
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// ListFlagged returns the comments the text moderation pipeline flagged and no
+// moderator reviewed yet, most recently flagged first
+func (r *CommentRepository) ListFlagged(ctx context.Context, limit, offset int) ([]models.FlaggedComment, error) {
+	comments := []models.FlaggedComment{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &comments, `
+		SELECT
+			c.*,
+			u.user_id "user.user_id",
+			u.name "user.name",
+			u.picture_url "user.picture_url",
+			tm.moderation_id, tm.original, tm.flags, tm.created_at AS flagged_at
+		FROM text_moderation tm
+		JOIN comments c ON c.comment_id = tm.target_id
+		JOIN users u ON u.user_id = c.user_id
+		WHERE tm.field = 'comment' AND tm.needs_review AND tm.reviewed_at IS NULL
+		ORDER BY tm.created_at DESC
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list flagged comments: %w", err)
+	}
+	return comments, nil
+}
+
+// resolveCommentFlags marks the pending text moderation flags of comments as reviewed
+func (r *CommentRepository) resolveCommentFlags(ctx context.Context, commentIDs []uuid.UUID, reviewerID uuid.UUID) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE text_moderation SET reviewed_by = $2, reviewed_at = NOW()
+		WHERE field = 'comment' AND target_id = ANY($1::uuid[]) AND reviewed_at IS NULL
+	`, pq.Array(commentIDs), reviewerID)
+	if err != nil {
+		return fmt.Errorf("resolve comment flags: %w", err)
+	}
+	return nil
+}
+
+// clearCommentFlags drops the text moderation entries of deleted comments,
+// including replies removed by the cascade
+func (r *CommentRepository) clearCommentFlags(ctx context.Context) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		DELETE FROM text_moderation tm
+		WHERE tm.field = 'comment'
+		  AND NOT EXISTS (SELECT 1 FROM comments c WHERE c.comment_id = tm.target_id)
+	`)
+	if err != nil {
+		return fmt.Errorf("clear comment flags: %w", err)
+	}
+	return nil
+}
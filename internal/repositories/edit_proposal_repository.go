@@ -0,0 +1,403 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrProposalNotFound   = errors.New("edit proposal not found")
+	ErrProposalNotPending = errors.New("edit proposal already reviewed")
+	ErrInvalidProposal    = errors.New("invalid edit proposal")
+)
+
+// proposalField maps a proposable field to its POI column and JSON value kind
+type proposalField struct {
+	column string
+	kind   string // string, int, bool, json
+}
+
+// proposableFields is the whitelist of POI fields the community may propose changes to.
+// Keys are the JSON field names used by the POI API.
+var proposableFields = map[string]proposalField{
+	"name":                 {"name", "string"},
+	"brand":                {"brand", "string"},
+	"description":          {"description", "string"},
+	"website":              {"website", "string"},
+	"phone":                {"phone", "string"},
+	"email":                {"email", "string"},
+	"cuisine":              {"cuisine", "string"},
+	"floor_unit":           {"floor_unit", "string"},
+	"public_transport":     {"public_transport", "string"},
+	"wifi_quality":         {"wifi_quality", "string"},
+	"noise_level":          {"noise_level", "string"},
+	"power_outlets":        {"power_outlets", "string"},
+	"price_range":          {"price_range", "int"},
+	"price_currency":       {"price_currency", "currency"},
+	"avg_spend_min":        {"avg_spend_min", "number"},
+	"avg_spend_max":        {"avg_spend_max", "number"},
+	"wait_time_estimate":   {"wait_time_estimate", "int"},
+	"has_wifi":             {"has_wifi", "bool"},
+	"outdoor_seating":      {"outdoor_seating", "bool"},
+	"has_ac":               {"has_ac", "bool"},
+	"reservation_required": {"reservation_required", "bool"},
+	"open_hours":           {"open_hours", "json"},
+	"social_links":         {"social_media_links", "json"},
+}
+
+// ProposableFields returns the sorted list of fields accepted in edit proposals
+func ProposableFields() []string {
+	fields := make([]string, 0, len(proposableFields))
+	for name := range proposableFields {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// ValidateProposalChanges checks that every field is proposable and its value has the right type
+func ValidateProposalChanges(changes map[string]json.RawMessage) error {
+	if len(changes) == 0 {
+		return fmt.Errorf("%w: no changes", ErrInvalidProposal)
+	}
+	for name, raw := range changes {
+		field, ok := proposableFields[name]
+		if !ok {
+			return fmt.Errorf("%w: field %q cannot be edited", ErrInvalidProposal, name)
+		}
+		if err := checkProposalValue(field.kind, raw); err != nil {
+			return fmt.Errorf("%w: field %q: %v", ErrInvalidProposal, name, err)
+		}
+	}
+	return nil
+}
+
+func checkProposalValue(kind string, raw json.RawMessage) error {
+	isNull := strings.TrimSpace(string(raw)) == "null"
+	switch kind {
+	case "string":
+		if isNull {
+			return nil
+		}
+		var v string
+		return json.Unmarshal(raw, &v)
+	case "int":
+		if isNull {
+			return nil
+		}
+		var v int
+		return json.Unmarshal(raw, &v)
+	case "number":
+		if isNull {
+			return nil
+		}
+		var v float64
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		if v < 0 {
+			return errors.New("must not be negative")
+		}
+		return nil
+	case "currency":
+		var v string
+		if isNull {
+			return errors.New("must not be null")
+		}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		if !models.IsCurrencyCode(v) {
+			return errors.New("must be an ISO 4217 currency code")
+		}
+		return nil
+	case "bool":
+		var v bool
+		if isNull {
+			return errors.New("must not be null")
+		}
+		return json.Unmarshal(raw, &v)
+	case "json":
+		if !json.Valid(raw) {
+			return errors.New("must be valid JSON")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported kind %s", kind)
+}
+
+// EditProposalRepository handles community edit proposals
+type EditProposalRepository struct {
+	db *database.DB
+}
+
+// NewEditProposalRepository creates a new edit proposal repository
+func NewEditProposalRepository(db *database.DB) *EditProposalRepository {
+	return &EditProposalRepository{db: db}
+}
+
+const editProposalColumns = `
+	ep.proposal_id, ep.poi_id, ep.proposer_id, ep.changes, ep.original, ep.note,
+	ep.status, ep.reviewer_id, ep.review_note, ep.created_at, ep.reviewed_at,
+	u.name as proposer_name`
+
+// Create stores a proposal together with a snapshot of the current values of the changed fields
+func (r *EditProposalRepository) Create(ctx context.Context, poiID, proposerID uuid.UUID, changes map[string]json.RawMessage, note *string) (*models.EditProposal, error) {
+	if err := ValidateProposalChanges(changes); err != nil {
+		return nil, err
+	}
+
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return nil, fmt.Errorf("marshal changes: %w", err)
+	}
+
+	// Column names come from the whitelist, never from the request
+	pairs := make([]string, 0, len(changes))
+	for name := range changes {
+		pairs = append(pairs, fmt.Sprintf("'%s', %s", name, proposableFields[name].column))
+	}
+	sort.Strings(pairs)
+
+	query := fmt.Sprintf(`
+		INSERT INTO poi_edit_proposals (poi_id, proposer_id, changes, original, note)
+		SELECT poi_id, $2, $3, jsonb_build_object(%s), $4
+		FROM points_of_interest
+		WHERE poi_id = $1
+		RETURNING proposal_id
+	`, strings.Join(pairs, ", "))
+
+	var proposalID uuid.UUID
+	err = r.db.Conn(ctx).QueryRowContext(ctx, query, poiID, proposerID, string(changesJSON), note).Scan(&proposalID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("create edit proposal: poi %s: %w", poiID, sql.ErrNoRows)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create edit proposal: %w", err)
+	}
+
+	return r.GetByID(ctx, proposalID)
+}
+
+// GetByID retrieves a proposal by ID
+func (r *EditProposalRepository) GetByID(ctx context.Context, proposalID uuid.UUID) (*models.EditProposal, error) {
+	var p models.EditProposal
+	query := `SELECT ` + editProposalColumns + `
+		FROM poi_edit_proposals ep
+		LEFT JOIN users u ON ep.proposer_id = u.user_id
+		WHERE ep.proposal_id = $1`
+
+	err := r.db.Conn(ctx).GetContext(ctx, &p, query, proposalID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrProposalNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get edit proposal: %w", err)
+	}
+	return &p, nil
+}
+
+// ListByPOI lists proposals for a POI, optionally filtered by status
+func (r *EditProposalRepository) ListByPOI(ctx context.Context, poiID uuid.UUID, status string, limit, offset int) ([]models.EditProposal, error) {
+	proposals := []models.EditProposal{}
+	query := `SELECT ` + editProposalColumns + `
+		FROM poi_edit_proposals ep
+		LEFT JOIN users u ON ep.proposer_id = u.user_id
+		WHERE ep.poi_id = $1 AND ($2 = '' OR ep.status = $2)
+		ORDER BY ep.created_at DESC
+		LIMIT $3 OFFSET $4`
+
+	if err := r.db.Conn(ctx).SelectContext(ctx, &proposals, query, poiID, status, limit, offset); err != nil {
+		return nil, fmt.Errorf("list edit proposals by poi: %w", err)
+	}
+	return proposals, nil
+}
+
+// ListByStatus lists proposals across all POIs (admin queue), oldest first
+func (r *EditProposalRepository) ListByStatus(ctx context.Context, status string, limit, offset int) ([]models.EditProposal, error) {
+	proposals := []models.EditProposal{}
+	query := `SELECT ` + editProposalColumns + `
+		FROM poi_edit_proposals ep
+		LEFT JOIN users u ON ep.proposer_id = u.user_id
+		WHERE ep.status = $1
+		ORDER BY ep.created_at ASC
+		LIMIT $2 OFFSET $3`
+
+	if err := r.db.Conn(ctx).SelectContext(ctx, &proposals, query, status, limit, offset); err != nil {
+		return nil, fmt.Errorf("list edit proposals by status: %w", err)
+	}
+	return proposals, nil
+}
+
+// ListByProposer lists proposals submitted by a user
+func (r *EditProposalRepository) ListByProposer(ctx context.Context, proposerID uuid.UUID, limit, offset int) ([]models.EditProposal, error) {
+	proposals := []models.EditProposal{}
+	query := `SELECT ` + editProposalColumns + `
+		FROM poi_edit_proposals ep
+		LEFT JOIN users u ON ep.proposer_id = u.user_id
+		WHERE ep.proposer_id = $1
+		ORDER BY ep.created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	if err := r.db.Conn(ctx).SelectContext(ctx, &proposals, query, proposerID, limit, offset); err != nil {
+		return nil, fmt.Errorf("list edit proposals by proposer: %w", err)
+	}
+	return proposals, nil
+}
+
+// Accept applies a pending proposal to its POI, marks it accepted and awards the proposer XP,
+// all in one transaction
+func (r *EditProposalRepository) Accept(ctx context.Context, proposalID, reviewerID uuid.UUID, reviewNote *string, xp int) (*models.EditProposal, error) {
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		p, err := r.lockPending(ctx, proposalID)
+		if err != nil {
+			return err
+		}
+
+		var changes map[string]json.RawMessage
+		if err := json.Unmarshal(p.Changes, &changes); err != nil {
+			return fmt.Errorf("decode proposal changes: %w", err)
+		}
+		// Re-validate in case the whitelist changed since submission
+		if err := ValidateProposalChanges(changes); err != nil {
+			return err
+		}
+
+		names := make([]string, 0, len(changes))
+		for name := range changes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		sets := make([]string, 0, len(names)+1)
+		args := []interface{}{p.PoiID}
+		for i, name := range names {
+			field := proposableFields[name]
+			// Values are extracted from the stored JSONB so Postgres handles the casting
+			sets = append(sets, fmt.Sprintf("%s = %s", field.column, proposalValueExpr(field.kind, i+2)))
+			args = append(args, string(changes[name]))
+		}
+		sets = append(sets, "updated_at = NOW()")
+
+		query := fmt.Sprintf(`UPDATE points_of_interest SET %s WHERE poi_id = $1`, strings.Join(sets, ", "))
+		if _, err := r.db.Conn(ctx).ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("apply edit proposal: %w", err)
+		}
+
+		if err := r.markReviewed(ctx, proposalID, "accepted", reviewerID, reviewNote); err != nil {
+			return err
+		}
+
+		if xp > 0 {
+			_, err := r.db.Conn(ctx).ExecContext(ctx, `
+				INSERT INTO user_profiles (user_id, global_xp)
+				VALUES ($1, $2)
+				ON CONFLICT (user_id) DO UPDATE
+				SET global_xp = user_profiles.global_xp + EXCLUDED.global_xp, updated_at = NOW()
+			`, p.ProposerID, xp)
+			if err != nil {
+				return fmt.Errorf("award proposal xp: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, proposalID)
+}
+
+// Reject marks a pending proposal as rejected
+func (r *EditProposalRepository) Reject(ctx context.Context, proposalID, reviewerID uuid.UUID, reviewNote *string) (*models.EditProposal, error) {
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := r.lockPending(ctx, proposalID); err != nil {
+			return err
+		}
+		return r.markReviewed(ctx, proposalID, "rejected", reviewerID, reviewNote)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, proposalID)
+}
+
+// Withdraw lets the proposer retract a pending proposal
+func (r *EditProposalRepository) Withdraw(ctx context.Context, proposalID, proposerID uuid.UUID) error {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE poi_edit_proposals SET status = 'withdrawn'
+		WHERE proposal_id = $1 AND proposer_id = $2 AND status = 'pending'
+	`, proposalID, proposerID)
+	if err != nil {
+		return fmt.Errorf("withdraw edit proposal: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("withdraw edit proposal rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrProposalNotPending
+	}
+	return nil
+}
+
+// lockPending loads a proposal FOR UPDATE and ensures it is still pending
+func (r *EditProposalRepository) lockPending(ctx context.Context, proposalID uuid.UUID) (*models.EditProposal, error) {
+	var p models.EditProposal
+	err := r.db.Conn(ctx).GetContext(ctx, &p, `
+		SELECT proposal_id, poi_id, proposer_id, changes, original, note, status,
+		       reviewer_id, review_note, created_at, reviewed_at
+		FROM poi_edit_proposals
+		WHERE proposal_id = $1
+		FOR UPDATE
+	`, proposalID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrProposalNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lock edit proposal: %w", err)
+	}
+	if p.Status != "pending" {
+		return nil, ErrProposalNotPending
+	}
+	return &p, nil
+}
+
+func (r *EditProposalRepository) markReviewed(ctx context.Context, proposalID uuid.UUID, status string, reviewerID uuid.UUID, reviewNote *string) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE poi_edit_proposals
+		SET status = $2, reviewer_id = $3, review_note = $4, reviewed_at = NOW()
+		WHERE proposal_id = $1
+	`, proposalID, status, reviewerID, reviewNote)
+	if err != nil {
+		return fmt.Errorf("mark edit proposal %s: %w", status, err)
+	}
+	return nil
+}
+
+// proposalValueExpr converts a JSON-encoded parameter into the column type
+func proposalValueExpr(kind string, param int) string {
+	switch kind {
+	case "int":
+		return fmt.Sprintf("($%d::jsonb #>> '{}')::int", param)
+	case "number":
+		return fmt.Sprintf("($%d::jsonb #>> '{}')::numeric", param)
+	case "bool":
+		return fmt.Sprintf("($%d::jsonb #>> '{}')::boolean", param)
+	case "json":
+		return fmt.Sprintf("NULLIF($%d::jsonb, 'null'::jsonb)", param)
+	default:
+		return fmt.Sprintf("$%d::jsonb #>> '{}'", param)
+	}
+}
@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// UpdateEmailPreferencesInput changes the given email categories; nil fields are kept
+type UpdateEmailPreferencesInput struct {
+	POIStatus    *bool `json:"poi_status"`
+	Verification *bool `json:"verification"`
+	SavedDigest  *bool `json:"saved_digest"`
+}
+
+// EmailRepository handles email preferences and the lookups behind notification emails
+type EmailRepository struct {
+	db *database.DB
+}
+
+// NewEmailRepository creates a new email repository
+func NewEmailRepository(db *database.DB) *EmailRepository {
+	return &EmailRepository{db: db}
+}
+
+// Users without a preferences row receive every category
+const emailRecipientSelect = `
+	SELECT u.user_id, u.email, u.name,
+	       COALESCE(ep.poi_status, TRUE) AS poi_status,
+	       COALESCE(ep.verification, TRUE) AS verification,
+	       COALESCE(ep.saved_digest, TRUE) AS saved_digest,
+	       ep.updated_at
+	FROM users u
+	LEFT JOIN email_preferences ep ON ep.user_id = u.user_id`
+
+// GetPreferences returns a user's email preferences
+func (r *EmailRepository) GetPreferences(ctx context.Context, userID uuid.UUID) (*models.EmailPreferences, error) {
+	var prefs models.EmailPreferences
+	err := r.db.Conn(ctx).GetContext(ctx, &prefs, `
+		SELECT COALESCE(ep.poi_status, TRUE) AS poi_status,
+		       COALESCE(ep.verification, TRUE) AS verification,
+		       COALESCE(ep.saved_digest, TRUE) AS saved_digest,
+		       ep.updated_at
+		FROM (SELECT $1::uuid AS user_id) me
+		LEFT JOIN email_preferences ep ON ep.user_id = me.user_id
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get email preferences: %w", err)
+	}
+	return &prefs, nil
+}
+
+// UpdatePreferences changes a user's email preferences and returns the result
+func (r *EmailRepository) UpdatePreferences(ctx context.Context, userID uuid.UUID, input UpdateEmailPreferencesInput) (*models.EmailPreferences, error) {
+	var prefs models.EmailPreferences
+	err := r.db.Conn(ctx).GetContext(ctx, &prefs, `
+		INSERT INTO email_preferences (user_id, poi_status, verification, saved_digest)
+		VALUES ($1, COALESCE($2, TRUE), COALESCE($3, TRUE), COALESCE($4, TRUE))
+		ON CONFLICT (user_id) DO UPDATE SET
+			poi_status = COALESCE($2, email_preferences.poi_status),
+			verification = COALESCE($3, email_preferences.verification),
+			saved_digest = COALESCE($4, email_preferences.saved_digest),
+			updated_at = NOW()
+		RETURNING poi_status, verification, saved_digest, updated_at
+	`, userID, input.POIStatus, input.Verification, input.SavedDigest)
+	if err != nil {
+		return nil, fmt.Errorf("update email preferences: %w", err)
+	}
+	return &prefs, nil
+}
+
+// Unsubscribe turns off one email category for a user
+func (r *EmailRepository) Unsubscribe(ctx context.Context, userID uuid.UUID, category string) error {
+	off := false
+	var input UpdateEmailPreferencesInput
+	switch category {
+	case models.EmailPOIStatus:
+		input.POIStatus = &off
+	case models.EmailVerification:
+		input.Verification = &off
+	case models.EmailSavedDigest:
+		input.SavedDigest = &off
+	default:
+		return fmt.Errorf("unknown email category %q", category)
+	}
+	_, err := r.UpdatePreferences(ctx, userID, input)
+	return err
+}
+
+// GetRecipient returns a user that can be emailed, or nil for missing and deleted accounts
+func (r *EmailRepository) GetRecipient(ctx context.Context, userID uuid.UUID) (*models.EmailRecipient, error) {
+	var recipient models.EmailRecipient
+	err := r.db.Conn(ctx).GetContext(ctx, &recipient, emailRecipientSelect+`
+		WHERE u.user_id = $1 AND u.deleted_at IS NULL`, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get email recipient: %w", err)
+	}
+	return &recipient, nil
+}
+
+// GetPOIOwnerRecipient returns the submitter of a POI and the POI name. The
+// recipient is nil when the POI has no (undeleted) owner.
+func (r *EmailRepository) GetPOIOwnerRecipient(ctx context.Context, poiID uuid.UUID) (*models.EmailRecipient, string, error) {
+	var row struct {
+		models.EmailRecipient
+		POIName string `db:"poi_name"`
+	}
+	err := r.db.Conn(ctx).GetContext(ctx, &row, `
+		SELECT recipient.*, p.name AS poi_name
+		FROM points_of_interest p
+		JOIN (`+emailRecipientSelect+`
+			WHERE u.deleted_at IS NULL
+		) recipient ON recipient.user_id = p.created_by
+		WHERE p.poi_id = $1`, poiID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("get poi owner recipient: %w", err)
+	}
+	return &row.EmailRecipient, row.POIName, nil
+}
+
+// ClaimDigestRecipients marks up to limit users as sent a digest now and
+// returns them with the start of their digest period. Users are due when they
+// opted in, have saved POIs and got no digest within interval.
+func (r *EmailRepository) ClaimDigestRecipients(ctx context.Context, interval time.Duration, limit int) ([]models.DigestRecipient, error) {
+	recipients := []models.DigestRecipient{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &recipients, `
+		WITH due AS (
+			SELECT u.user_id, u.email, u.name,
+			       COALESCE(ep.last_digest_at, NOW() - $1 * INTERVAL '1 second') AS since
+			FROM users u
+			LEFT JOIN email_preferences ep ON ep.user_id = u.user_id
+			WHERE u.deleted_at IS NULL
+			  AND COALESCE(ep.saved_digest, TRUE)
+			  AND (ep.last_digest_at IS NULL OR ep.last_digest_at < NOW() - $1 * INTERVAL '1 second')
+			  AND EXISTS (SELECT 1 FROM saved_pois s WHERE s.user_id = u.user_id)
+			ORDER BY u.user_id
+			LIMIT $2
+			FOR UPDATE OF u SKIP LOCKED
+		), claimed AS (
+			INSERT INTO email_preferences (user_id, last_digest_at)
+			SELECT user_id, NOW() FROM due
+			ON CONFLICT (user_id) DO UPDATE SET last_digest_at = EXCLUDED.last_digest_at
+		)
+		SELECT user_id, email, name, since FROM due
+	`, interval.Seconds(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("claim digest recipients: %w", err)
+	}
+	return recipients, nil
+}
+
+// ListDigestItems returns the user's saved approved POIs updated after since,
+// most recently updated first
+func (r *EmailRepository) ListDigestItems(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]models.DigestItem, error) {
+	items := []models.DigestItem{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &items, `
+		SELECT p.poi_id, p.name, p.updated_at, COUNT(*) OVER () AS total
+		FROM saved_pois s
+		JOIN points_of_interest p ON p.poi_id = s.poi_id
+		WHERE s.user_id = $1 AND p.status = 'approved' AND p.updated_at > $2
+		ORDER BY p.updated_at DESC
+		LIMIT $3
+	`, userID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list digest items: %w", err)
+	}
+	return items, nil
+}
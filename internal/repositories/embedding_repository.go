@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"maukemana-backend/internal/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// EmbeddingRepository stores POI embeddings and runs vector similarity queries
+type EmbeddingRepository struct {
+	db *database.DB
+}
+
+// NewEmbeddingRepository creates a new embedding repository
+func NewEmbeddingRepository(db *database.DB) *EmbeddingRepository {
+	return &EmbeddingRepository{db: db}
+}
+
+// SemanticMatch is a POI ranked by embedding similarity
+type SemanticMatch struct {
+	POIID          uuid.UUID `db:"poi_id" json:"poi_id"`
+	Similarity     float64   `db:"similarity" json:"similarity"`
+	DistanceMeters *float64  `db:"distance_meters" json:"distance_meters,omitempty"`
+}
+
+// SemanticQuery narrows a similarity search. Geo filtering applies when Radius > 0.
+type SemanticQuery struct {
+	Model  string
+	Vector []float32
+	Lat    float64
+	Lng    float64
+	Radius float64 // meters
+	Limit  int
+}
+
+// vectorLiteral formats a vector in pgvector's text representation
+func vectorLiteral(v []float32) string {
+	var b strings.Builder
+	b.Grow(len(v) * 10)
+	b.WriteByte('[')
+	for i, f := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(f), 'f', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// GetContentHashes returns the stored content hash for each POI embedded with model
+func (r *EmbeddingRepository) GetContentHashes(ctx context.Context, model string, poiIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	var rows []struct {
+		POIID       uuid.UUID `db:"poi_id"`
+		ContentHash string    `db:"content_hash"`
+	}
+	err := r.db.Conn(ctx).SelectContext(ctx, &rows, `
+		SELECT poi_id, content_hash
+		FROM poi_embeddings
+		WHERE model = $1 AND poi_id = ANY($2::uuid[])
+	`, model, pq.Array(poiIDs))
+	if err != nil {
+		return nil, fmt.Errorf("get embedding hashes: %w", err)
+	}
+
+	hashes := make(map[uuid.UUID]string, len(rows))
+	for _, row := range rows {
+		hashes[row.POIID] = row.ContentHash
+	}
+	return hashes, nil
+}
+
+// Upsert stores the embedding of a POI, replacing any previous one
+func (r *EmbeddingRepository) Upsert(ctx context.Context, poiID uuid.UUID, model, contentHash string, vector []float32) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		INSERT INTO poi_embeddings (poi_id, model, content_hash, embedding)
+		VALUES ($1, $2, $3, $4::vector)
+		ON CONFLICT (poi_id) DO UPDATE
+		SET model = EXCLUDED.model,
+		    content_hash = EXCLUDED.content_hash,
+		    embedding = EXCLUDED.embedding,
+		    updated_at = NOW()
+	`, poiID, model, contentHash, vectorLiteral(vector))
+	if err != nil {
+		return fmt.Errorf("upsert embedding: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the embedding of a POI
+func (r *EmbeddingRepository) Delete(ctx context.Context, poiID uuid.UUID) error {
+	if _, err := r.db.Conn(ctx).ExecContext(ctx, `DELETE FROM poi_embeddings WHERE poi_id = $1`, poiID); err != nil {
+		return fmt.Errorf("delete embedding: %w", err)
+	}
+	return nil
+}
+
+// Search returns approved POIs closest to the query vector (cosine similarity),
+// optionally restricted to a radius around a point
+func (r *EmbeddingRepository) Search(ctx context.Context, q SemanticQuery) ([]SemanticMatch, error) {
+	args := []interface{}{vectorLiteral(q.Vector), q.Model, q.Limit}
+	distance := `NULL::float8`
+	geoFilter := ""
+	if q.Radius > 0 {
+		args = append(args, q.Lng, q.Lat, q.Radius)
+		distance = `ST_Distance(p.location, ST_SetSRID(ST_MakePoint($4, $5), 4326)::geography)`
+		geoFilter = `AND ST_DWithin(p.location, ST_SetSRID(ST_MakePoint($4, $5), 4326)::geography, $6)`
+	}
+
+	matches := []SemanticMatch{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &matches, `
+		SELECT e.poi_id,
+		       1 - (e.embedding <=> $1::vector) as similarity,
+		       `+distance+` as distance_meters
+		FROM poi_embeddings e
+		JOIN points_of_interest p ON p.poi_id = e.poi_id
+		WHERE e.model = $2 AND p.status = 'approved'
+		`+geoFilter+`
+		ORDER BY e.embedding <=> $1::vector
+		LIMIT $3
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("semantic search: %w", err)
+	}
+	return matches, nil
+}
@@ -0,0 +1,218 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"maukemana-backend/internal/database"
+
+	"github.com/lib/pq"
+)
+
+// filterOptionsTTL bounds how long the filter options of an area are served from memory
+const filterOptionsTTL = 10 * time.Minute
+
+// FilterOption is a value of a search filter with the number of approved
+// POIs in the requested area that match it
+type FilterOption struct {
+	Value string  `json:"value"`
+	Label string  `json:"label"`
+	Icon  *string `json:"icon,omitempty"`
+	Count int     `json:"count"`
+}
+
+// FilterOptions are the search filter values backed by vocabularies, and the
+// approved POI counts of every filter value, for one area
+type FilterOptions struct {
+	Vibes          []FilterOption `json:"vibes"`
+	CrowdTypes     []FilterOption `json:"crowd_types"`
+	DietaryOptions []FilterOption `json:"dietary_options"`
+	SeatingOptions []FilterOption `json:"seating_options"`
+	ParkingOptions []FilterOption `json:"parking_options"`
+	Cuisines       []FilterOption `json:"cuisines"`
+	Amenities      []FilterOption `json:"amenities"`
+
+	// Counts holds POI counts by search parameter and value, including the
+	// fixed-value filters (price_range, wifi_quality, noise_level, power_outlets)
+	Counts      map[string]map[string]int `json:"-"`
+	TotalPOIs   int                       `json:"total_pois"`
+	GeneratedAt time.Time                 `json:"generated_at"`
+}
+
+// Count returns how many POIs match value of the search parameter param
+func (o *FilterOptions) Count(param, value string) int {
+	return o.Counts[param][value]
+}
+
+type cachedFilterOptions struct {
+	options   *FilterOptions
+	expiresAt time.Time
+}
+
+// FilterOptionsRepository derives search filter options from the vocabularies
+// and the approved POIs that use them
+type FilterOptionsRepository struct {
+	db *database.DB
+
+	mu    sync.RWMutex
+	cache map[string]cachedFilterOptions
+}
+
+// NewFilterOptionsRepository creates a new filter options repository
+func NewFilterOptionsRepository(db *database.DB) *FilterOptionsRepository {
+	return &FilterOptionsRepository{db: db, cache: make(map[string]cachedFilterOptions)}
+}
+
+// filterVocabulary is an active vocabulary entry backing a filter value
+type filterVocabulary struct {
+	VocabType   string  `db:"vocab_type"`
+	Value       string  `db:"value"`
+	Icon        *string `db:"icon"`
+	Label       string  `db:"label"`
+	LegacyField *string `db:"legacy_field"`
+	LegacyValue *string `db:"legacy_value"`
+}
+
+// filterCount is the number of POIs matching one value of a search parameter
+type filterCount struct {
+	Param string `db:"param"`
+	Value string `db:"value"`
+	Count int    `db:"count"`
+}
+
+// GetFilterOptions returns the filter options with counts of approved POIs
+// inside the service area with slug area, or everywhere when area is empty.
+// Labels use the first available of locales. Results are computed at most
+// once per 10 minutes per area and locales.
+func (r *FilterOptionsRepository) GetFilterOptions(ctx context.Context, area string, locales []string) (*FilterOptions, error) {
+	cacheKey := area + "|" + strings.Join(locales, ",")
+	r.mu.RLock()
+	cached, ok := r.cache[cacheKey]
+	r.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.options, nil
+	}
+
+	conn := r.db.Conn(ctx)
+	if area != "" {
+		var exists bool
+		if err := conn.QueryRowxContext(ctx, `SELECT EXISTS (SELECT 1 FROM service_areas WHERE slug = $1)`, area).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("check filter options area: %w", err)
+		}
+		if !exists {
+			return nil, ErrServiceAreaNotFound
+		}
+	}
+
+	var vocabularies []filterVocabulary
+	err := conn.SelectContext(ctx, &vocabularies, `
+		SELECT v.vocab_type, substring(v.key FROM position('.' IN v.key) + 1) AS value, v.icon,
+		       COALESCE(`+labelSubquery("vocabulary", "v.vocab_id", "$1")+`, v.key) AS label,
+		       a.legacy_field, a.legacy_value
+		FROM vocabularies v
+		LEFT JOIN amenities a ON a.vocab_id = v.vocab_id
+		WHERE v.is_active AND v.vocab_type IN ('vibe', 'crowd_type', 'food', 'cuisine', 'amenity')
+		ORDER BY v.vocab_type, a.sort_order, v.key
+	`, pq.StringArray(locales))
+	if err != nil {
+		return nil, fmt.Errorf("get filter vocabularies: %w", err)
+	}
+
+	// One pass over the area's approved POIs counts every filter value; POIs
+	// count once per value even if an array repeats it
+	var counts []filterCount
+	err = conn.SelectContext(ctx, &counts, `
+		WITH scope AS (
+			SELECT p.poi_id, p.vibes, p.crowd_type, p.dietary_options, p.cuisine,
+			       p.price_range, p.wifi_quality, p.noise_level, p.power_outlets
+			FROM points_of_interest p
+			WHERE p.status = 'approved'
+			  AND ($1 = '' OR EXISTS (
+			      SELECT 1 FROM service_areas sa
+			      WHERE sa.slug = $1 AND ST_Within(p.location::geometry, sa.boundary)))
+		)
+		SELECT param, value, COUNT(DISTINCT poi_id) AS count
+		FROM (
+			SELECT '' AS param, '' AS value, poi_id FROM scope
+			UNION ALL SELECT 'vibes', unnest(vibes), poi_id FROM scope
+			UNION ALL SELECT 'crowd_type', unnest(crowd_type), poi_id FROM scope
+			UNION ALL SELECT 'dietary_options', unnest(dietary_options), poi_id FROM scope
+			UNION ALL SELECT 'cuisine', cuisine, poi_id FROM scope WHERE cuisine <> ''
+			UNION ALL SELECT 'price_range', price_range::text, poi_id FROM scope WHERE price_range IS NOT NULL
+			UNION ALL SELECT 'wifi_quality', wifi_quality, poi_id FROM scope WHERE wifi_quality <> ''
+			UNION ALL SELECT 'noise_level', noise_level, poi_id FROM scope WHERE noise_level <> ''
+			UNION ALL SELECT 'power_outlets', power_outlets, poi_id FROM scope WHERE power_outlets <> ''
+			UNION ALL
+			SELECT 'amenities', a.key, s.poi_id
+			FROM scope s
+			JOIN poi_amenities pa ON pa.poi_id = s.poi_id
+			JOIN amenities a ON a.amenity_id = pa.amenity_id
+		) f
+		GROUP BY param, value
+	`, area)
+	if err != nil {
+		return nil, fmt.Errorf("count filter options: %w", err)
+	}
+
+	options := &FilterOptions{
+		Vibes:          []FilterOption{},
+		CrowdTypes:     []FilterOption{},
+		DietaryOptions: []FilterOption{},
+		SeatingOptions: []FilterOption{},
+		ParkingOptions: []FilterOption{},
+		Cuisines:       []FilterOption{},
+		Amenities:      []FilterOption{},
+		Counts:         make(map[string]map[string]int),
+		GeneratedAt:    time.Now().UTC(),
+	}
+	for _, c := range counts {
+		if c.Param == "" {
+			options.TotalPOIs = c.Count
+			continue
+		}
+		if options.Counts[c.Param] == nil {
+			options.Counts[c.Param] = make(map[string]int)
+		}
+		options.Counts[c.Param][c.Value] = c.Count
+	}
+
+	for _, v := range vocabularies {
+		option := FilterOption{Value: v.Value, Label: v.Label, Icon: v.Icon}
+		switch v.VocabType {
+		case "vibe":
+			option.Count = options.Count("vibes", v.Value)
+			options.Vibes = append(options.Vibes, option)
+		case "crowd_type":
+			option.Count = options.Count("crowd_type", v.Value)
+			options.CrowdTypes = append(options.CrowdTypes, option)
+		case "food":
+			option.Count = options.Count("dietary_options", v.Value)
+			options.DietaryOptions = append(options.DietaryOptions, option)
+		case "cuisine":
+			option.Count = options.Count("cuisine", v.Value)
+			options.Cuisines = append(options.Cuisines, option)
+		case "amenity":
+			// Seating and parking options are the amenities mirroring those columns
+			option.Count = options.Count("amenities", v.Value)
+			options.Amenities = append(options.Amenities, option)
+			if v.LegacyField == nil || v.LegacyValue == nil {
+				continue
+			}
+			legacy := FilterOption{Value: *v.LegacyValue, Label: v.Label, Icon: v.Icon, Count: option.Count}
+			switch *v.LegacyField {
+			case "seating_options":
+				options.SeatingOptions = append(options.SeatingOptions, legacy)
+			case "parking_options":
+				options.ParkingOptions = append(options.ParkingOptions, legacy)
+			}
+		}
+	}
+
+	r.mu.Lock()
+	r.cache[cacheKey] = cachedFilterOptions{options: options, expiresAt: time.Now().Add(filterOptionsTTL)}
+	r.mu.Unlock()
+	return options, nil
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+)
+
+// HeatmapRepository aggregates approved POIs on a geographic grid
+type HeatmapRepository struct {
+	db *database.DB
+}
+
+// NewHeatmapRepository creates a new heatmap repository
+func NewHeatmapRepository(db *database.DB) *HeatmapRepository {
+	return &HeatmapRepository{db: db}
+}
+
+// heatmapValues are the cell value expressions of each metric
+var heatmapValues = map[string]struct{ value, where string }{
+	models.HeatmapPOIDensity: {value: "COUNT(*)"},
+	models.HeatmapAvgRating:  {value: "ROUND(AVG(p.rating_avg)::numeric, 2)", where: " AND p.reviews_count > 0"},
+}
+
+// Heatmap snaps the approved POIs inside bbox to a grid of cellSize degrees
+// and aggregates metric per non-empty cell
+func (r *HeatmapRepository) Heatmap(ctx context.Context, bbox models.BBox, metric string, cellSize float64) (*models.Heatmap, error) {
+	m, ok := heatmapValues[metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown heatmap metric %q", metric)
+	}
+
+	heatmap := &models.Heatmap{Metric: metric, CellSize: cellSize, Cells: []models.HeatmapCell{}}
+	err := r.db.ReadConn(ctx).SelectContext(ctx, &heatmap.Cells, fmt.Sprintf(`
+		SELECT ST_Y(cell) AS lat, ST_X(cell) AS lng, %s::float8 AS value, COUNT(*) AS pois
+		FROM (
+			SELECT p.rating_avg, ST_SnapToGrid(p.location::geometry, $5) AS cell
+			FROM points_of_interest p
+			WHERE p.status = 'approved'
+			  AND p.location::geometry && ST_MakeEnvelope($1, $2, $3, $4, 4326)%s
+		) p
+		GROUP BY cell
+		ORDER BY lat, lng
+	`, m.value, m.where), bbox.West, bbox.South, bbox.East, bbox.North, cellSize)
+	if err != nil {
+		return nil, fmt.Errorf("get %s heatmap: %w", metric, err)
+	}
+	return heatmap, nil
+}
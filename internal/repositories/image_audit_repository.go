@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"maukemana-backend/internal/imaging"
+
+	"github.com/google/uuid"
+)
+
+// ListAuditAssets returns every image asset with its derivatives, for
+// comparing the database against the objects in storage
+func (r *ImagingRepository) ListAuditAssets(ctx context.Context) ([]imaging.ImageAsset, error) {
+	conn := r.db.Conn(ctx)
+
+	assets := []imaging.ImageAsset{}
+	err := conn.SelectContext(ctx, &assets, `
+		SELECT id, content_hash, category, status, version, created_by_user_id, created_at, moderation_status
+		FROM image_assets
+		ORDER BY created_at, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list audit assets: %w", err)
+	}
+
+	var derivatives []imaging.Derivative
+	err = conn.SelectContext(ctx, &derivatives, `
+		SELECT id, asset_id, rendition_name, format, width, height, size_bytes, storage_key
+		FROM image_derivatives
+		ORDER BY asset_id, rendition_name, format
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list audit derivatives: %w", err)
+	}
+
+	byAsset := make(map[uuid.UUID][]imaging.Derivative, len(assets))
+	for _, d := range derivatives {
+		byAsset[d.AssetID] = append(byAsset[d.AssetID], d)
+	}
+	for i := range assets {
+		assets[i].Derivatives = byAsset[assets[i].ID]
+	}
+	return assets, nil
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"maukemana-backend/internal/imaging"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const reprocessRunColumns = `run_id, status, category, outdated_only, batch_size, batch_interval_ms, total, queued, cursor_created_at, cursor_id, lease_owner, lease_until, started_by, last_error, created_at, updated_at, completed_at`
+
+// StartReprocessRun creates a run over the currently ready assets, optionally
+// of one category and only those rendered with an outdated ladder. It fails
+// with imaging.ErrReprocessRunning while another run is running.
+func (r *ImagingRepository) StartReprocessRun(ctx context.Context, category *string, outdatedOnly bool, batchSize, batchIntervalMS int, startedBy *uuid.UUID) (*imaging.ReprocessRun, error) {
+	var run imaging.ReprocessRun
+	err := r.db.Conn(ctx).GetContext(ctx, &run, `
+		INSERT INTO image_reprocess_runs (category, outdated_only, batch_size, batch_interval_ms, total, started_by)
+		SELECT $1, $2, $3, $4, COUNT(*), $5
+		FROM image_assets a
+		WHERE a.status = 'ready' AND ($1::text IS NULL OR a.category = $1)
+		  AND (NOT $2 OR `+outdatedLadderSQL+`)
+		RETURNING `+reprocessRunColumns, category, outdatedOnly, batchSize, batchIntervalMS, startedBy)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return nil, imaging.ErrReprocessRunning
+	}
+	if err != nil {
+		return nil, fmt.Errorf("start reprocess run: %w", err)
+	}
+	return &run, nil
+}
+
+// GetActiveReprocessRun returns the running run, or nil when there is none
+func (r *ImagingRepository) GetActiveReprocessRun(ctx context.Context) (*imaging.ReprocessRun, error) {
+	return r.getReprocessRun(ctx, `SELECT `+reprocessRunColumns+` FROM image_reprocess_runs WHERE status = 'running'`)
+}
+
+// GetLatestReprocessRun returns the most recently started run, or nil when there is none
+func (r *ImagingRepository) GetLatestReprocessRun(ctx context.Context) (*imaging.ReprocessRun, error) {
+	return r.getReprocessRun(ctx, `SELECT `+reprocessRunColumns+` FROM image_reprocess_runs ORDER BY created_at DESC LIMIT 1`)
+}
+
+func (r *ImagingRepository) getReprocessRun(ctx context.Context, query string) (*imaging.ReprocessRun, error) {
+	var run imaging.ReprocessRun
+	err := r.db.Conn(ctx).GetContext(ctx, &run, query)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get reprocess run: %w", err)
+	}
+	return &run, nil
+}
+
+// ClaimReprocessRun takes or renews owner's lease on a run. Runs that are no
+// longer running are returned as they are; imaging.ErrReprocessLeased means
+// another runner holds an unexpired lease.
+func (r *ImagingRepository) ClaimReprocessRun(ctx context.Context, runID, owner uuid.UUID, lease time.Duration) (*imaging.ReprocessRun, error) {
+	var run imaging.ReprocessRun
+	err := r.db.Conn(ctx).GetContext(ctx, &run, `
+		UPDATE image_reprocess_runs
+		SET lease_owner = CASE WHEN status = 'running' THEN $2 ELSE lease_owner END,
+		    lease_until = CASE WHEN status = 'running' THEN NOW() + $3 * INTERVAL '1 millisecond' ELSE lease_until END,
+		    updated_at = NOW()
+		WHERE run_id = $1
+		  AND (status <> 'running' OR lease_until IS NULL OR lease_until < NOW() OR lease_owner = $2)
+		RETURNING `+reprocessRunColumns, runID, owner, lease.Milliseconds())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, imaging.ErrReprocessLeased
+	}
+	if err != nil {
+		return nil, fmt.Errorf("claim reprocess run: %w", err)
+	}
+	return &run, nil
+}
+
+// NextReprocessBatch returns the next ready assets after the run's cursor
+func (r *ImagingRepository) NextReprocessBatch(ctx context.Context, run *imaging.ReprocessRun) ([]imaging.ImageAsset, error) {
+	assets := []imaging.ImageAsset{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &assets, `
+		SELECT a.id, a.content_hash, a.category, a.created_by_user_id, a.created_at
+		FROM image_assets a
+		WHERE a.status = 'ready'
+		  AND ($1::text IS NULL OR a.category = $1)
+		  AND ($2::timestamptz IS NULL OR (a.created_at, a.id) > ($2, $3))
+		  AND (NOT $5 OR `+outdatedLadderSQL+`)
+		ORDER BY a.created_at, a.id
+		LIMIT $4
+	`, run.Category, run.CursorCreatedAt, run.CursorID, run.BatchSize, run.OutdatedOnly)
+	if err != nil {
+		return nil, fmt.Errorf("next reprocess batch: %w", err)
+	}
+	return assets, nil
+}
+
+// AdvanceReprocessRun moves the cursor past last after queueing a batch
+func (r *ImagingRepository) AdvanceReprocessRun(ctx context.Context, runID uuid.UUID, last imaging.ImageAsset, queued int) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE image_reprocess_runs
+		SET cursor_created_at = $2, cursor_id = $3, queued = queued + $4, updated_at = NOW()
+		WHERE run_id = $1
+	`, runID, last.CreatedAt, last.ID, queued)
+	if err != nil {
+		return fmt.Errorf("advance reprocess run: %w", err)
+	}
+	return nil
+}
+
+// FinishReprocessRun ends a running run with the given status
+func (r *ImagingRepository) FinishReprocessRun(ctx context.Context, runID uuid.UUID, status, lastError string) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE image_reprocess_runs
+		SET status = $2, last_error = NULLIF($3, ''), lease_owner = NULL, lease_until = NULL, completed_at = NOW(), updated_at = NOW()
+		WHERE run_id = $1 AND status = 'running'
+	`, runID, status, lastError)
+	if err != nil {
+		return fmt.Errorf("finish reprocess run: %w", err)
+	}
+	return nil
+}
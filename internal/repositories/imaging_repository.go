@@ -25,13 +25,15 @@ func (r *ImagingRepository) CreateAsset(ctx context.Context, asset *imaging.Imag
 	query := `
 		INSERT INTO image_assets (
 			id, content_hash, original_width, original_height, original_format,
-			original_size, has_alpha, category, status, version, created_by_user_id, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+			original_size, has_alpha, category, status, version, created_by_user_id, created_at,
+			moderation_status, ladder_version
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, COALESCE(NULLIF($13, ''), 'unchecked'), $14)`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
 		asset.ID, asset.ContentHash, asset.OriginalWidth, asset.OriginalHeight,
 		asset.OriginalFormat, asset.OriginalSize, asset.HasAlpha, asset.Category,
-		asset.Status, asset.Version, asset.CreatedByUserID, asset.CreatedAt)
+		asset.Status, asset.Version, asset.CreatedByUserID, asset.CreatedAt,
+		asset.ModerationStatus, asset.LadderVersion)
 
 	if err != nil {
 		return fmt.Errorf("create asset: %w", err)
@@ -48,19 +50,29 @@ func (r *ImagingRepository) UpdateAssetStatus(ctx context.Context, id uuid.UUID,
 		processedAt = &now
 	}
 
-	_, err := r.db.ExecContext(ctx, query, status, errorMessage, processedAt, id)
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query, status, errorMessage, processedAt, id)
 	if err != nil {
 		return fmt.Errorf("update asset status: %w", err)
 	}
 	return nil
 }
 
+// UpdateAssetModeration records the content moderation outcome for an asset
+func (r *ImagingRepository) UpdateAssetModeration(ctx context.Context, id uuid.UUID, status imaging.ModerationStatus, score *float64, reason string) error {
+	query := `UPDATE image_assets SET moderation_status = $1, moderation_score = $2, moderation_reason = $3, moderated_at = NOW() WHERE id = $4`
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query, status, score, reason, id)
+	if err != nil {
+		return fmt.Errorf("update asset moderation: %w", err)
+	}
+	return nil
+}
+
 // GetAssetByHash retrieves an asset by its content hash
 func (r *ImagingRepository) GetAssetByHash(ctx context.Context, hash string) (*imaging.ImageAsset, error) {
 	var asset imaging.ImageAsset
-	query := `SELECT id, content_hash, original_width, original_height, original_format, original_size, has_alpha, category, status, COALESCE(error_message, '') as error, version, created_by_user_id, created_at, processed_at FROM image_assets WHERE content_hash = $1`
+	query := `SELECT id, content_hash, original_width, original_height, original_format, original_size, has_alpha, category, status, COALESCE(error_message, '') as error, version, ladder_version, created_by_user_id, created_at, processed_at, moderation_status, moderation_score, COALESCE(moderation_reason, '') as moderation_reason FROM image_assets WHERE content_hash = $1`
 
-	err := r.db.GetContext(ctx, &asset, query, hash)
+	err := r.db.Conn(ctx).GetContext(ctx, &asset, query, hash)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -81,9 +93,9 @@ func (r *ImagingRepository) GetAssetByHash(ctx context.Context, hash string) (*i
 // GetAssetByID retrieves an asset by its ID
 func (r *ImagingRepository) GetAssetByID(ctx context.Context, id uuid.UUID) (*imaging.ImageAsset, error) {
 	var asset imaging.ImageAsset
-	query := `SELECT id, content_hash, original_width, original_height, original_format, original_size, has_alpha, category, status, COALESCE(error_message, '') as error, version, created_by_user_id, created_at, processed_at FROM image_assets WHERE id = $1`
+	query := `SELECT id, content_hash, original_width, original_height, original_format, original_size, has_alpha, category, status, COALESCE(error_message, '') as error, version, ladder_version, created_by_user_id, created_at, processed_at, moderation_status, moderation_score, COALESCE(moderation_reason, '') as moderation_reason FROM image_assets WHERE id = $1`
 
-	err := r.db.GetContext(ctx, &asset, query, id)
+	err := r.db.Conn(ctx).GetContext(ctx, &asset, query, id)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -108,7 +120,7 @@ func (r *ImagingRepository) CreateDerivative(ctx context.Context, d imaging.Deri
 			id, asset_id, rendition_name, format, width, height, size_bytes, storage_key
 		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
 		d.ID, d.AssetID, d.RenditionName, d.Format, d.Width, d.Height, d.SizeBytes, d.StorageKey)
 
 	if err != nil {
@@ -117,12 +129,33 @@ func (r *ImagingRepository) CreateDerivative(ctx context.Context, d imaging.Deri
 	return nil
 }
 
+// ReplaceDerivatives swaps the derivatives of a reprocessed asset whose
+// rendition names start with renditionPrefix for the new version's, dropping
+// renditions no longer in that ladder
+func (r *ImagingRepository) ReplaceDerivatives(ctx context.Context, assetID uuid.UUID, version int, renditionPrefix string, derivatives []imaging.Derivative) error {
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		conn := r.db.Conn(ctx)
+		if _, err := conn.ExecContext(ctx, `DELETE FROM image_derivatives WHERE asset_id = $1 AND starts_with(rendition_name, $2)`, assetID, renditionPrefix); err != nil {
+			return fmt.Errorf("delete old derivatives: %w", err)
+		}
+		for _, d := range derivatives {
+			if err := r.CreateDerivative(ctx, d); err != nil {
+				return err
+			}
+		}
+		if _, err := conn.ExecContext(ctx, `UPDATE image_assets SET version = $1 WHERE id = $2`, version, assetID); err != nil {
+			return fmt.Errorf("update asset version: %w", err)
+		}
+		return nil
+	})
+}
+
 // GetDerivatives retrieves all derivatives for an asset
 func (r *ImagingRepository) GetDerivatives(ctx context.Context, assetID uuid.UUID) ([]imaging.Derivative, error) {
 	var derivatives []imaging.Derivative
 	query := `SELECT id, asset_id, rendition_name, format, width, height, size_bytes, storage_key FROM image_derivatives WHERE asset_id = $1`
 
-	err := r.db.SelectContext(ctx, &derivatives, query, assetID)
+	err := r.db.Conn(ctx).SelectContext(ctx, &derivatives, query, assetID)
 	if err != nil {
 		return nil, fmt.Errorf("get derivatives: %w", err)
 	}
@@ -133,11 +166,12 @@ func (r *ImagingRepository) GetDerivatives(ctx context.Context, assetID uuid.UUI
 func (r *ImagingRepository) CreateJob(ctx context.Context, job *imaging.ProcessingJob) error {
 	query := `
 		INSERT INTO image_processing_jobs (
-			id, upload_key, category, user_id, status, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+			id, upload_key, category, user_id, status, created_at, updated_at, crop_data, is_reprocess
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
 
-	_, err := r.db.ExecContext(ctx, query,
-		job.ID, job.UploadKey, job.Category, job.UserID, imaging.StatusPending, job.CreatedAt, time.Now())
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		job.ID, job.UploadKey, job.Category, job.UserID, imaging.StatusPending, job.CreatedAt, time.Now(),
+		job.CropData, job.IsReprocess)
 
 	if err != nil {
 		return fmt.Errorf("create job: %w", err)
@@ -148,7 +182,7 @@ func (r *ImagingRepository) CreateJob(ctx context.Context, job *imaging.Processi
 // UpdateJob updates a job's status and metadata
 func (r *ImagingRepository) UpdateJob(ctx context.Context, id uuid.UUID, status imaging.ProcessingStatus, assetID *uuid.UUID, attempts int, lastError string) error {
 	query := `UPDATE image_processing_jobs SET status = $1, asset_id = $2, attempts = $3, last_error = $4, updated_at = $5 WHERE id = $6`
-	_, err := r.db.ExecContext(ctx, query, status, assetID, attempts, lastError, time.Now(), id)
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query, status, assetID, attempts, lastError, time.Now(), id)
 	if err != nil {
 		return fmt.Errorf("update job: %w", err)
 	}
@@ -158,9 +192,9 @@ func (r *ImagingRepository) UpdateJob(ctx context.Context, id uuid.UUID, status
 // GetPendingJobs retrieves all pending jobs
 func (r *ImagingRepository) GetPendingJobs(ctx context.Context) ([]imaging.ProcessingJob, error) {
 	var jobs []imaging.ProcessingJob
-	query := `SELECT id, upload_key, category, user_id, attempts, COALESCE(last_error, '') as last_error, created_at FROM image_processing_jobs WHERE status = 'pending' ORDER BY created_at ASC`
+	query := `SELECT id, upload_key, category, user_id, asset_id, attempts, COALESCE(last_error, '') as last_error, created_at, crop_data, COALESCE(is_reprocess, FALSE) AS is_reprocess FROM image_processing_jobs WHERE status = 'pending' ORDER BY created_at ASC`
 
-	err := r.db.SelectContext(ctx, &jobs, query)
+	err := r.db.Conn(ctx).SelectContext(ctx, &jobs, query)
 	if err != nil {
 		return nil, fmt.Errorf("get pending jobs: %w", err)
 	}
@@ -172,7 +206,7 @@ func (r *ImagingRepository) GetJobByID(ctx context.Context, id uuid.UUID) (*imag
 	var job imaging.ProcessingJob
 	query := `SELECT id, upload_key, category, user_id, asset_id, status, attempts, COALESCE(last_error, '') as last_error, created_at FROM image_processing_jobs WHERE id = $1`
 
-	err := r.db.GetContext(ctx, &job, query, id)
+	err := r.db.Conn(ctx).GetContext(ctx, &job, query, id)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -0,0 +1,368 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrItineraryNotFound is returned when an itinerary does not exist or is not visible to the user
+	ErrItineraryNotFound = errors.New("itinerary not found")
+	// ErrItineraryItemNotFound is returned when an item does not exist in the itinerary
+	ErrItineraryItemNotFound = errors.New("itinerary item not found")
+	// ErrItineraryItemConflict is returned when an item was changed since the caller read it
+	ErrItineraryItemConflict = errors.New("itinerary item was modified by someone else")
+	// ErrCollaboratorNotFound is returned when a user is not invited to the itinerary
+	ErrCollaboratorNotFound = errors.New("collaborator not found")
+)
+
+// ItineraryRepository handles itineraries, their items, share links and collaborators
+type ItineraryRepository struct {
+	db *database.DB
+}
+
+// NewItineraryRepository creates a new itinerary repository
+func NewItineraryRepository(db *database.DB) *ItineraryRepository {
+	return &ItineraryRepository{db: db}
+}
+
+// itinerarySelect resolves the role of user $1 on each itinerary: owner,
+// accepted collaborator role, view for public itineraries, or empty
+const itinerarySelect = `
+	SELECT i.itinerary_id, i.user_id, i.title, i.description, i.start_date, i.end_date,
+	       COALESCE(i.is_public, FALSE) as is_public, i.share_token, i.share_created_at,
+	       i.created_at, i.updated_at,
+	       CASE
+	           WHEN i.user_id = $1 THEN 'owner'
+	           ELSE COALESCE(c.role, CASE WHEN i.is_public THEN 'view' END, '')
+	       END as role
+	FROM itineraries i
+	LEFT JOIN itinerary_collaborators c
+	       ON c.itinerary_id = i.itinerary_id AND c.user_id = $1 AND c.status = 'accepted'`
+
+const itineraryItemSelect = `
+	SELECT it.item_id, it.itinerary_id, it.poi_id, it.day, it.order_index, it.planned_time,
+	       it.duration, it.notes, it.version, it.updated_by, it.created_at, it.updated_at,
+	       p.name as poi_name
+	FROM itinerary_items it
+	JOIN points_of_interest p ON p.poi_id = it.poi_id`
+
+// ListForUser returns the itineraries a user owns or collaborates on, most recently updated first
+func (r *ItineraryRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]models.Itinerary, error) {
+	itineraries := []models.Itinerary{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &itineraries, itinerarySelect+`
+		WHERE i.user_id = $1 OR c.user_id IS NOT NULL
+		ORDER BY i.updated_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list itineraries: %w", err)
+	}
+	return itineraries, nil
+}
+
+// GetForUser returns an itinerary with the user's role. Itineraries the user
+// cannot see are reported as not found.
+func (r *ItineraryRepository) GetForUser(ctx context.Context, id, userID uuid.UUID) (*models.Itinerary, error) {
+	var it models.Itinerary
+	err := r.db.Conn(ctx).GetContext(ctx, &it, itinerarySelect+` WHERE i.itinerary_id = $2`, userID, id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && it.Role == "") {
+		return nil, ErrItineraryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get itinerary: %w", err)
+	}
+	return &it, nil
+}
+
+// GetByShareToken returns the itinerary a share link points to, with the view role
+func (r *ItineraryRepository) GetByShareToken(ctx context.Context, token string) (*models.Itinerary, error) {
+	var it models.Itinerary
+	err := r.db.Conn(ctx).GetContext(ctx, &it, `
+		SELECT itinerary_id, user_id, title, description, start_date, end_date,
+		       COALESCE(is_public, FALSE) as is_public, created_at, updated_at, 'view' as role
+		FROM itineraries
+		WHERE share_token = $1
+	`, token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrItineraryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get itinerary by share token: %w", err)
+	}
+	return &it, nil
+}
+
+// Create inserts a new itinerary
+func (r *ItineraryRepository) Create(ctx context.Context, it *models.Itinerary) (*models.Itinerary, error) {
+	var id uuid.UUID
+	err := r.db.Conn(ctx).QueryRowContext(ctx, `
+		INSERT INTO itineraries (user_id, title, description, start_date, end_date, is_public)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING itinerary_id
+	`, it.UserID, it.Title, it.Description, it.StartDate, it.EndDate, it.IsPublic).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("create itinerary: %w", err)
+	}
+	return r.GetForUser(ctx, id, it.UserID)
+}
+
+// Update replaces the editable fields of an itinerary
+func (r *ItineraryRepository) Update(ctx context.Context, it *models.Itinerary) error {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE itineraries
+		SET title = $2, description = $3, start_date = $4, end_date = $5, is_public = $6, updated_at = NOW()
+		WHERE itinerary_id = $1
+	`, it.ItineraryID, it.Title, it.Description, it.StartDate, it.EndDate, it.IsPublic)
+	if err != nil {
+		return fmt.Errorf("update itinerary: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrItineraryNotFound
+	}
+	return nil
+}
+
+// Delete removes an itinerary with its items and collaborators
+func (r *ItineraryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, `DELETE FROM itineraries WHERE itinerary_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete itinerary: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrItineraryNotFound
+	}
+	return nil
+}
+
+// SetShareToken sets or (with nil) revokes the share link of an itinerary
+func (r *ItineraryRepository) SetShareToken(ctx context.Context, id uuid.UUID, token *string) error {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE itineraries
+		SET share_token = $2, share_created_at = CASE WHEN $2::text IS NULL THEN NULL ELSE NOW() END
+		WHERE itinerary_id = $1
+	`, id, token)
+	if err != nil {
+		return fmt.Errorf("set itinerary share token: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrItineraryNotFound
+	}
+	return nil
+}
+
+// ListItems returns the items of an itinerary in plan order
+func (r *ItineraryRepository) ListItems(ctx context.Context, itineraryID uuid.UUID) ([]models.ItineraryItem, error) {
+	items := []models.ItineraryItem{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &items, itineraryItemSelect+`
+		WHERE it.itinerary_id = $1
+		ORDER BY it.day, it.order_index, it.created_at
+	`, itineraryID)
+	if err != nil {
+		return nil, fmt.Errorf("list itinerary items: %w", err)
+	}
+	return items, nil
+}
+
+// GetItem returns one item of an itinerary
+func (r *ItineraryRepository) GetItem(ctx context.Context, itineraryID, itemID uuid.UUID) (*models.ItineraryItem, error) {
+	var item models.ItineraryItem
+	err := r.db.Conn(ctx).GetContext(ctx, &item, itineraryItemSelect+`
+		WHERE it.itinerary_id = $1 AND it.item_id = $2
+	`, itineraryID, itemID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrItineraryItemNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get itinerary item: %w", err)
+	}
+	return &item, nil
+}
+
+// CreateItem adds an item to an itinerary
+func (r *ItineraryRepository) CreateItem(ctx context.Context, item *models.ItineraryItem, userID uuid.UUID) (*models.ItineraryItem, error) {
+	var id uuid.UUID
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		if err := r.db.Conn(ctx).QueryRowContext(ctx, `
+			INSERT INTO itinerary_items (itinerary_id, poi_id, day, order_index, planned_time, duration, notes, updated_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING item_id
+		`, item.ItineraryID, item.PoiID, item.Day, item.OrderIndex, item.PlannedTime, item.Duration, item.Notes, userID).Scan(&id); err != nil {
+			return err
+		}
+		return r.touch(ctx, item.ItineraryID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create itinerary item: %w", err)
+	}
+	return r.GetItem(ctx, item.ItineraryID, id)
+}
+
+// UpdateItem saves an item if it is still at expectedVersion and bumps its version.
+// On ErrItineraryItemConflict the current item is returned so clients can merge.
+func (r *ItineraryRepository) UpdateItem(ctx context.Context, item *models.ItineraryItem, expectedVersion int, userID uuid.UUID) (*models.ItineraryItem, error) {
+	var updated bool
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		result, err := r.db.Conn(ctx).ExecContext(ctx, `
+			UPDATE itinerary_items
+			SET poi_id = $4, day = $5, order_index = $6, planned_time = $7, duration = $8, notes = $9,
+			    version = version + 1, updated_by = $10, updated_at = NOW()
+			WHERE itinerary_id = $1 AND item_id = $2 AND version = $3
+		`, item.ItineraryID, item.ItemID, expectedVersion,
+			item.PoiID, item.Day, item.OrderIndex, item.PlannedTime, item.Duration, item.Notes, userID)
+		if err != nil {
+			return err
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return nil
+		}
+		updated = true
+		return r.touch(ctx, item.ItineraryID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update itinerary item: %w", err)
+	}
+
+	current, err := r.GetItem(ctx, item.ItineraryID, item.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return current, ErrItineraryItemConflict
+	}
+	return current, nil
+}
+
+// DeleteItem removes an item if it is still at expectedVersion
+func (r *ItineraryRepository) DeleteItem(ctx context.Context, itineraryID, itemID uuid.UUID, expectedVersion int) (*models.ItineraryItem, error) {
+	var deleted bool
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		result, err := r.db.Conn(ctx).ExecContext(ctx, `
+			DELETE FROM itinerary_items WHERE itinerary_id = $1 AND item_id = $2 AND version = $3
+		`, itineraryID, itemID, expectedVersion)
+		if err != nil {
+			return err
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return nil
+		}
+		deleted = true
+		return r.touch(ctx, itineraryID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("delete itinerary item: %w", err)
+	}
+	if deleted {
+		return nil, nil
+	}
+
+	current, err := r.GetItem(ctx, itineraryID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	return current, ErrItineraryItemConflict
+}
+
+// touch bumps the itinerary's updated_at so lists reflect item activity
+func (r *ItineraryRepository) touch(ctx context.Context, itineraryID uuid.UUID) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `UPDATE itineraries SET updated_at = NOW() WHERE itinerary_id = $1`, itineraryID)
+	return err
+}
+
+// ListCollaborators returns everyone invited to an itinerary
+func (r *ItineraryRepository) ListCollaborators(ctx context.Context, itineraryID uuid.UUID) ([]models.ItineraryCollaborator, error) {
+	collaborators := []models.ItineraryCollaborator{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &collaborators, `
+		SELECT c.itinerary_id, c.user_id, c.role, c.status, c.invited_by, c.created_at, c.accepted_at,
+		       u.email, u.name
+		FROM itinerary_collaborators c
+		JOIN users u ON u.user_id = c.user_id
+		WHERE c.itinerary_id = $1
+		ORDER BY c.created_at
+	`, itineraryID)
+	if err != nil {
+		return nil, fmt.Errorf("list itinerary collaborators: %w", err)
+	}
+	return collaborators, nil
+}
+
+// Invite adds a pending collaborator, or changes the role of an existing one
+// without resetting their acceptance
+func (r *ItineraryRepository) Invite(ctx context.Context, itineraryID, userID, invitedBy uuid.UUID, role string) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		INSERT INTO itinerary_collaborators (itinerary_id, user_id, role, invited_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (itinerary_id, user_id) DO UPDATE SET role = EXCLUDED.role
+	`, itineraryID, userID, role, invitedBy)
+	if err != nil {
+		return fmt.Errorf("invite itinerary collaborator: %w", err)
+	}
+	return nil
+}
+
+// UpdateCollaboratorRole changes the role of an invited user
+func (r *ItineraryRepository) UpdateCollaboratorRole(ctx context.Context, itineraryID, userID uuid.UUID, role string) error {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE itinerary_collaborators SET role = $3 WHERE itinerary_id = $1 AND user_id = $2
+	`, itineraryID, userID, role)
+	if err != nil {
+		return fmt.Errorf("update itinerary collaborator: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrCollaboratorNotFound
+	}
+	return nil
+}
+
+// RemoveCollaborator revokes an invitation or a collaborator's access
+func (r *ItineraryRepository) RemoveCollaborator(ctx context.Context, itineraryID, userID uuid.UUID) error {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, `
+		DELETE FROM itinerary_collaborators WHERE itinerary_id = $1 AND user_id = $2
+	`, itineraryID, userID)
+	if err != nil {
+		return fmt.Errorf("remove itinerary collaborator: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrCollaboratorNotFound
+	}
+	return nil
+}
+
+// AcceptInvitation marks the user's pending invitation as accepted
+func (r *ItineraryRepository) AcceptInvitation(ctx context.Context, itineraryID, userID uuid.UUID) error {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE itinerary_collaborators
+		SET status = 'accepted', accepted_at = COALESCE(accepted_at, NOW())
+		WHERE itinerary_id = $1 AND user_id = $2
+	`, itineraryID, userID)
+	if err != nil {
+		return fmt.Errorf("accept itinerary invitation: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrCollaboratorNotFound
+	}
+	return nil
+}
+
+// ListInvitations returns the pending invitations of a user
+func (r *ItineraryRepository) ListInvitations(ctx context.Context, userID uuid.UUID) ([]models.Itinerary, error) {
+	itineraries := []models.Itinerary{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &itineraries, `
+		SELECT i.itinerary_id, i.user_id, i.title, i.description, i.start_date, i.end_date,
+		       COALESCE(i.is_public, FALSE) as is_public, i.created_at, i.updated_at, c.role
+		FROM itinerary_collaborators c
+		JOIN itineraries i ON i.itinerary_id = c.itinerary_id
+		WHERE c.user_id = $1 AND c.status = 'pending'
+		ORDER BY c.created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list itinerary invitations: %w", err)
+	}
+	return itineraries, nil
+}
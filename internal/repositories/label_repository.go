@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"maukemana-backend/internal/database"
+
+	"github.com/google/uuid"
+)
+
+var ErrLabelNotFound = errors.New("label not found")
+
+// Label entity types
+const (
+	LabelEntityCategory   = "category"
+	LabelEntityVocabulary = "vocabulary"
+)
+
+// DisplayLabel is a localized label for a category or vocabulary entry
+type DisplayLabel struct {
+	EntityType string     `db:"entity_type" json:"entity_type"`
+	EntityID   uuid.UUID  `db:"entity_id" json:"entity_id"`
+	Locale     string     `db:"locale" json:"locale"`
+	Label      string     `db:"label" json:"label"`
+	UpdatedBy  *uuid.UUID `db:"updated_by" json:"updated_by,omitempty"`
+	UpdatedAt  time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// labelSubquery picks the label of an entity in the first matching locale of the
+// text[] parameter localesParam, honouring its order
+func labelSubquery(entityType, entityIDRef, localesParam string) string {
+	return fmt.Sprintf(`(
+			SELECT dl.label FROM display_labels dl
+			WHERE dl.entity_type = '%s' AND dl.entity_id = %s AND dl.locale = ANY(%s)
+			ORDER BY array_position(%s, dl.locale)
+			LIMIT 1
+		)`, entityType, entityIDRef, localesParam, localesParam)
+}
+
+// LabelRepository manages display label translations
+type LabelRepository struct {
+	db *database.DB
+}
+
+// NewLabelRepository creates a new label repository
+func NewLabelRepository(db *database.DB) *LabelRepository {
+	return &LabelRepository{db: db}
+}
+
+// List returns every label of an entity
+func (r *LabelRepository) List(ctx context.Context, entityType string, entityID uuid.UUID) ([]DisplayLabel, error) {
+	labels := []DisplayLabel{}
+	query := `
+		SELECT entity_type, entity_id, locale, label, updated_by, updated_at
+		FROM display_labels
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY locale
+	`
+	if err := r.db.Conn(ctx).SelectContext(ctx, &labels, query, entityType, entityID); err != nil {
+		return nil, fmt.Errorf("list labels: %w", err)
+	}
+	return labels, nil
+}
+
+// Upsert sets the label of an entity for a locale. It returns ErrLabelNotFound
+// when the entity itself does not exist.
+func (r *LabelRepository) Upsert(ctx context.Context, entityType string, entityID uuid.UUID, locale, label string, updatedBy uuid.UUID) (*DisplayLabel, error) {
+	exists := `SELECT EXISTS (SELECT 1 FROM categories WHERE category_id = $1)`
+	if entityType == LabelEntityVocabulary {
+		exists = `SELECT EXISTS (SELECT 1 FROM vocabularies WHERE vocab_id = $1)`
+	}
+
+	var found bool
+	if err := r.db.Conn(ctx).QueryRowContext(ctx, exists, entityID).Scan(&found); err != nil {
+		return nil, fmt.Errorf("check label entity: %w", err)
+	}
+	if !found {
+		return nil, ErrLabelNotFound
+	}
+
+	var out DisplayLabel
+	query := `
+		INSERT INTO display_labels (entity_type, entity_id, locale, label, updated_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (entity_type, entity_id, locale) DO UPDATE
+		SET label = EXCLUDED.label, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING entity_type, entity_id, locale, label, updated_by, updated_at
+	`
+	err := r.db.Conn(ctx).GetContext(ctx, &out, query, entityType, entityID, locale, label, updatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("upsert label: %w", err)
+	}
+	return &out, nil
+}
+
+// Delete removes the label of an entity for a locale
+func (r *LabelRepository) Delete(ctx context.Context, entityType string, entityID uuid.UUID, locale string) error {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, `
+		DELETE FROM display_labels WHERE entity_type = $1 AND entity_id = $2 AND locale = $3
+	`, entityType, entityID, locale)
+	if err != nil {
+		return fmt.Errorf("delete label: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete label rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrLabelNotFound
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrUnknownMenuPhoto is returned when a menu item references a missing image asset
+var ErrUnknownMenuPhoto = errors.New("menu item references an unknown photo asset")
+
+// MenuRepository handles structured POI menus
+type MenuRepository struct {
+	db *database.DB
+}
+
+// NewMenuRepository creates a new menu repository
+func NewMenuRepository(db *database.DB) *MenuRepository {
+	return &MenuRepository{db: db}
+}
+
+// GetByPOI returns the menu of a POI as ordered sections with their items
+func (r *MenuRepository) GetByPOI(ctx context.Context, poiID uuid.UUID) ([]models.MenuSection, error) {
+	sections := []models.MenuSection{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &sections, `
+		SELECT section_id, poi_id, name, description, position, created_at, updated_at
+		FROM poi_menu_sections
+		WHERE poi_id = $1
+		ORDER BY position, created_at
+	`, poiID)
+	if err != nil {
+		return nil, fmt.Errorf("get menu sections: %w", err)
+	}
+	if len(sections) == 0 {
+		return sections, nil
+	}
+
+	var items []models.MenuItem
+	err = r.db.Conn(ctx).SelectContext(ctx, &items, `
+		SELECT item_id, section_id, poi_id, name, description, price, currency, dietary_tags,
+		       photo_asset_id, is_available, is_featured, position, created_at, updated_at
+		FROM poi_menu_items
+		WHERE poi_id = $1
+		ORDER BY position, created_at
+	`, poiID)
+	if err != nil {
+		return nil, fmt.Errorf("get menu items: %w", err)
+	}
+
+	index := make(map[uuid.UUID]int, len(sections))
+	for i := range sections {
+		sections[i].Items = []models.MenuItem{}
+		index[sections[i].SectionID] = i
+	}
+	for _, item := range items {
+		if i, ok := index[item.SectionID]; ok {
+			sections[i].Items = append(sections[i].Items, item)
+		}
+	}
+
+	return sections, nil
+}
+
+// ReplaceMenu atomically replaces the whole menu of a POI. Positions follow the
+// slice order. The legacy featured_menu_items column is kept in sync with the
+// items flagged as featured.
+func (r *MenuRepository) ReplaceMenu(ctx context.Context, poiID uuid.UUID, sections []models.MenuSection) error {
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		conn := r.db.Conn(ctx)
+
+		if _, err := conn.ExecContext(ctx, `DELETE FROM poi_menu_sections WHERE poi_id = $1`, poiID); err != nil {
+			return fmt.Errorf("clear menu: %w", err)
+		}
+
+		for si, section := range sections {
+			var sectionID uuid.UUID
+			err := conn.QueryRowContext(ctx, `
+				INSERT INTO poi_menu_sections (poi_id, name, description, position)
+				VALUES ($1, $2, $3, $4)
+				RETURNING section_id
+			`, poiID, section.Name, section.Description, si).Scan(&sectionID)
+			if err != nil {
+				return fmt.Errorf("insert menu section: %w", err)
+			}
+
+			for ii, item := range section.Items {
+				tags := item.DietaryTags
+				if tags == nil {
+					tags = pq.StringArray{}
+				}
+				_, err := conn.ExecContext(ctx, `
+					INSERT INTO poi_menu_items (
+						section_id, poi_id, name, description, price, currency, dietary_tags,
+						photo_asset_id, is_available, is_featured, position
+					) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+				`, sectionID, poiID, item.Name, item.Description, item.Price, item.Currency, tags,
+					item.PhotoAssetID, item.IsAvailable, item.IsFeatured, ii)
+				if err != nil {
+					var pqErr *pq.Error
+					if errors.As(err, &pqErr) && pqErr.Code == "23503" && pqErr.Constraint == "poi_menu_items_photo_asset_id_fkey" {
+						return ErrUnknownMenuPhoto
+					}
+					return fmt.Errorf("insert menu item: %w", err)
+				}
+			}
+		}
+
+		_, err := conn.ExecContext(ctx, `
+			UPDATE points_of_interest
+			SET featured_menu_items = ARRAY(
+				SELECT i.name
+				FROM poi_menu_items i
+				JOIN poi_menu_sections s ON s.section_id = i.section_id
+				WHERE i.poi_id = $1 AND i.is_featured
+				ORDER BY s.position, i.position
+			), updated_at = NOW()
+			WHERE poi_id = $1
+		`, poiID)
+		if err != nil {
+			return fmt.Errorf("sync featured menu items: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrMultipartUploadNotFound is returned when a multipart upload does not exist
+// or is not in the expected status
+var ErrMultipartUploadNotFound = errors.New("multipart upload not found")
+
+// MultipartUploadRepository stores the resumability metadata of multipart uploads
+type MultipartUploadRepository struct {
+	db *database.DB
+}
+
+// NewMultipartUploadRepository creates a new multipart upload repository
+func NewMultipartUploadRepository(db *database.DB) *MultipartUploadRepository {
+	return &MultipartUploadRepository{db: db}
+}
+
+const multipartUploadColumns = `upload_id, user_id, storage_key, storage_upload_id, category, content_type, size_bytes, part_size_bytes, part_count, status, expires_at, created_at, updated_at`
+
+// Create stores a new active multipart upload
+func (r *MultipartUploadRepository) Create(ctx context.Context, u *models.MultipartUpload) (*models.MultipartUpload, error) {
+	var created models.MultipartUpload
+	err := r.db.Conn(ctx).GetContext(ctx, &created, `
+		INSERT INTO multipart_uploads (user_id, storage_key, storage_upload_id, category, content_type, size_bytes, part_size_bytes, part_count, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING `+multipartUploadColumns,
+		u.UserID, u.StorageKey, u.StorageUploadID, u.Category, u.ContentType, u.SizeBytes, u.PartSizeBytes, u.PartCount, u.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("create multipart upload: %w", err)
+	}
+	return &created, nil
+}
+
+// Get returns a multipart upload of userID
+func (r *MultipartUploadRepository) Get(ctx context.Context, uploadID, userID uuid.UUID) (*models.MultipartUpload, error) {
+	var u models.MultipartUpload
+	err := r.db.Conn(ctx).GetContext(ctx, &u, `
+		SELECT `+multipartUploadColumns+`
+		FROM multipart_uploads
+		WHERE upload_id = $1 AND user_id = $2
+	`, uploadID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMultipartUploadNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get multipart upload: %w", err)
+	}
+	return &u, nil
+}
+
+// Close moves an active multipart upload to status (completed or aborted). It
+// returns ErrMultipartUploadNotFound when the upload is no longer active.
+func (r *MultipartUploadRepository) Close(ctx context.Context, uploadID uuid.UUID, status string) error {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE multipart_uploads SET status = $2, updated_at = NOW()
+		WHERE upload_id = $1 AND status = 'active'
+	`, uploadID, status)
+	if err != nil {
+		return fmt.Errorf("close multipart upload: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrMultipartUploadNotFound
+	}
+	return nil
+}
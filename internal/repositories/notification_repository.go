@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotificationNotFound is returned when a notification does not exist for the user
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// NotificationRepository handles in-app user notifications
+type NotificationRepository struct {
+	db *database.DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *database.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create stores a notification for a user
+func (r *NotificationRepository) Create(ctx context.Context, userID uuid.UUID, notificationType, title string, body *string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encode notification data: %w", err)
+	}
+	_, err = r.db.Conn(ctx).ExecContext(ctx, `
+		INSERT INTO user_notifications (user_id, type, title, body, data)
+		VALUES ($1, $2, $3, $4, $5)
+	`, userID, notificationType, title, body, payload)
+	if err != nil {
+		return fmt.Errorf("create notification: %w", err)
+	}
+	return nil
+}
+
+// ListForUser returns a user's notifications, newest first
+func (r *NotificationRepository) ListForUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]models.Notification, error) {
+	notifications := []models.Notification{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &notifications, `
+		SELECT notification_id, user_id, type, title, body, data, read_at, created_at
+		FROM user_notifications
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// MarkRead marks one of the user's notifications as read
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE user_notifications SET read_at = COALESCE(read_at, NOW())
+		WHERE notification_id = $1 AND user_id = $2
+	`, notificationID, userID)
+	if err != nil {
+		return fmt.Errorf("mark notification read: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// OutboxRepository reads the transactional outbox and manages webhook deliveries.
+// Events are written by the capture_poi_outbox_event trigger.
+type OutboxRepository struct {
+	db *database.DB
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db *database.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// PublishPending marks up to limit unpublished events as published and fans
+// them out to matching active webhook subscriptions, atomically. Concurrent
+// relays skip rows already locked by another instance.
+func (r *OutboxRepository) PublishPending(ctx context.Context, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	err := r.db.Conn(ctx).SelectContext(ctx, &events, `
+		WITH batch AS (
+			SELECT event_id, event_type
+			FROM outbox_events
+			WHERE published_at IS NULL
+			ORDER BY created_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		), fanout AS (
+			INSERT INTO webhook_deliveries (subscription_id, event_id)
+			SELECT s.subscription_id, b.event_id
+			FROM batch b
+			JOIN webhook_subscriptions s
+			  ON s.is_active AND (cardinality(s.event_types) = 0 OR b.event_type = ANY(s.event_types))
+			ON CONFLICT (subscription_id, event_id) DO NOTHING
+		)
+		UPDATE outbox_events o
+		SET published_at = NOW()
+		FROM batch b
+		WHERE o.event_id = b.event_id
+		RETURNING o.event_id, o.event_type, o.aggregate_id, o.payload, o.created_at, o.published_at
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("publish outbox events: %w", err)
+	}
+	return events, nil
+}
+
+// ClaimDueDeliveries leases up to limit pending deliveries that are due, so
+// that other dispatchers skip them until the lease expires.
+func (r *OutboxRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.DueDelivery, error) {
+	var due []models.DueDelivery
+	err := r.db.Conn(ctx).SelectContext(ctx, &due, `
+		WITH claimed AS (
+			UPDATE webhook_deliveries d
+			SET next_attempt_at = NOW() + make_interval(secs => $2), updated_at = NOW()
+			WHERE d.delivery_id IN (
+				SELECT delivery_id
+				FROM webhook_deliveries
+				WHERE status = 'pending' AND next_attempt_at <= NOW()
+				ORDER BY next_attempt_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING d.delivery_id, d.subscription_id, d.event_id, d.attempts
+		)
+		SELECT c.delivery_id, c.attempts, s.url, s.secret,
+		       e.event_id, e.event_type, e.payload, e.created_at
+		FROM claimed c
+		JOIN webhook_subscriptions s ON s.subscription_id = c.subscription_id
+		JOIN outbox_events e ON e.event_id = c.event_id
+	`, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+	return due, nil
+}
+
+// MarkDelivered records a successful delivery attempt
+func (r *OutboxRepository) MarkDelivered(ctx context.Context, deliveryID uuid.UUID, statusCode int) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = 'succeeded', attempts = attempts + 1, last_status_code = $2,
+		    last_error = NULL, delivered_at = NOW(), updated_at = NOW()
+		WHERE delivery_id = $1
+	`, deliveryID, statusCode)
+	if err != nil {
+		return fmt.Errorf("mark delivery succeeded: %w", err)
+	}
+	return nil
+}
+
+// MarkAttemptFailed records a failed attempt. With a nil retryAt the delivery
+// is given up and marked failed.
+func (r *OutboxRepository) MarkAttemptFailed(ctx context.Context, deliveryID uuid.UUID, statusCode *int, errMsg string, retryAt *time.Time) error {
+	status := models.DeliveryPending
+	nextAttempt := time.Now()
+	if retryAt == nil {
+		status = models.DeliveryFailed
+	} else {
+		nextAttempt = *retryAt
+	}
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = attempts + 1, last_status_code = $3,
+		    last_error = $4, next_attempt_at = $5, updated_at = NOW()
+		WHERE delivery_id = $1
+	`, deliveryID, status, statusCode, errMsg, nextAttempt)
+	if err != nil {
+		return fmt.Errorf("mark delivery attempt failed: %w", err)
+	}
+	return nil
+}
@@ -3,13 +3,20 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// ErrUnknownPhoto is returned when a gallery order lists a photo that does not
+// belong to the POI
+var ErrUnknownPhoto = errors.New("photo does not belong to this POI")
+
 type PhotoRepository struct {
 	db *database.DB
 }
@@ -112,7 +119,7 @@ func (r *PhotoRepository) VoteWithToggle(ctx context.Context, photoID, userID uu
 // Returns: voteType (1, -1, or 0 if no vote)
 func (r *PhotoRepository) GetUserVote(ctx context.Context, photoID, userID uuid.UUID) (int, error) {
 	var voteType sql.NullInt64
-	err := r.db.QueryRowContext(ctx, `
+	err := r.db.Conn(ctx).QueryRowContext(ctx, `
 		SELECT vote_type FROM photo_votes
 		WHERE photo_id = $1 AND user_id = $2
 	`, photoID, userID).Scan(&voteType)
@@ -126,3 +133,107 @@ func (r *PhotoRepository) GetUserVote(ctx context.Context, photoID, userID uuid.
 
 	return int(voteType.Int64), nil
 }
+
+// GetByPOIs returns the photos of several POIs in gallery order, grouped by POI.
+// Used to batch photo loads for list responses.
+func (r *PhotoRepository) GetByPOIs(ctx context.Context, poiIDs []uuid.UUID) (map[uuid.UUID][]models.Photo, error) {
+	var photos []models.Photo
+	err := r.db.Conn(ctx).SelectContext(ctx, &photos, `
+		SELECT photo_id, poi_id, user_id, url, original_url,
+		       COALESCE(is_admin_official, FALSE) as is_admin_official,
+		       COALESCE(is_pinned, FALSE) as is_pinned,
+		       COALESCE(upvotes, 0) as upvotes, COALESCE(downvotes, 0) as downvotes,
+		       vibe_category, COALESCE(score, 0) as score, COALESCE(is_hero, FALSE) as is_hero,
+		       sort_index, created_at
+		FROM photos
+		WHERE poi_id = ANY($1::uuid[])
+		ORDER BY poi_id, sort_index ASC NULLS LAST, is_pinned DESC, is_hero DESC, score DESC
+	`, pq.Array(poiIDs))
+	if err != nil {
+		return nil, fmt.Errorf("get photos by pois: %w", err)
+	}
+
+	byPOI := make(map[uuid.UUID][]models.Photo, len(poiIDs))
+	for _, p := range photos {
+		byPOI[p.PoiID] = append(byPOI[p.PoiID], p)
+	}
+	return byPOI, nil
+}
+
+// ReorderPOIPhotos stores photoIDs as the gallery order of a POI. Photos left
+// out of the list lose their position and follow the ordered ones.
+func (r *PhotoRepository) ReorderPOIPhotos(ctx context.Context, poiID uuid.UUID, photoIDs []uuid.UUID) error {
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		conn := r.db.Conn(ctx)
+
+		var owned int
+		err := conn.GetContext(ctx, &owned, `
+			SELECT COUNT(*) FROM photos WHERE poi_id = $1 AND photo_id = ANY($2::uuid[])
+		`, poiID, pq.Array(photoIDs))
+		if err != nil {
+			return fmt.Errorf("check gallery photos: %w", err)
+		}
+		if owned != len(photoIDs) {
+			return ErrUnknownPhoto
+		}
+
+		_, err = conn.ExecContext(ctx, `
+			UPDATE photos ph
+			SET sort_index = o.idx - 1
+			FROM (SELECT photo_id, idx FROM unnest($2::uuid[]) WITH ORDINALITY AS t(photo_id, idx)) o
+			WHERE ph.poi_id = $1 AND ph.photo_id = o.photo_id
+		`, poiID, pq.Array(photoIDs))
+		if err != nil {
+			return fmt.Errorf("order gallery photos: %w", err)
+		}
+		_, err = conn.ExecContext(ctx, `
+			UPDATE photos SET sort_index = NULL
+			WHERE poi_id = $1 AND sort_index IS NOT NULL AND NOT (photo_id = ANY($2::uuid[]))
+		`, poiID, pq.Array(photoIDs))
+		if err != nil {
+			return fmt.Errorf("clear gallery positions: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetPOIPhoto returns a photo of a POI, or ErrUnknownPhoto when the POI has no
+// such photo
+func (r *PhotoRepository) GetPOIPhoto(ctx context.Context, poiID, photoID uuid.UUID) (*models.Photo, error) {
+	var photo models.Photo
+	err := r.db.Conn(ctx).GetContext(ctx, &photo, `
+		SELECT photo_id, poi_id, user_id, url, original_url,
+		       COALESCE(is_admin_official, FALSE) as is_admin_official,
+		       COALESCE(is_pinned, FALSE) as is_pinned,
+		       COALESCE(upvotes, 0) as upvotes, COALESCE(downvotes, 0) as downvotes,
+		       vibe_category, COALESCE(score, 0) as score, COALESCE(is_hero, FALSE) as is_hero,
+		       sort_index, created_at
+		FROM photos
+		WHERE poi_id = $1 AND photo_id = $2
+	`, poiID, photoID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUnknownPhoto
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get poi photo: %w", err)
+	}
+	return &photo, nil
+}
+
+// SetCoverFromPhoto points the cover of a POI at coverURL in the same statement
+// that checks the photo still belongs to the POI
+func (r *PhotoRepository) SetCoverFromPhoto(ctx context.Context, poiID, photoID uuid.UUID, coverURL string) error {
+	res, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE points_of_interest
+		SET cover_image_url = $3, updated_at = NOW()
+		WHERE poi_id = $1
+		  AND EXISTS (SELECT 1 FROM photos WHERE poi_id = $1 AND photo_id = $2)
+	`, poiID, photoID, coverURL)
+	if err != nil {
+		return fmt.Errorf("set cover from photo: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrUnknownPhoto
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"maukemana-backend/internal/config"
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// POIRepository handles POI database operations
+type POIRepository struct {
+	db      *database.DB
+	ranking config.RankingWeights
+}
+
+// NewPOIRepository creates a new POI repository
+func NewPOIRepository(db *database.DB) *POIRepository {
+	return &POIRepository{db: db, ranking: config.GetRankingWeights()}
+}
+
+// PhotosJSON handles JSON scanning for photos
+type PhotosJSON []models.Photo
+
+// Scan implements the sql.Scanner interface
+func (p *PhotosJSON) Scan(value interface{}) error {
+	if value == nil {
+		*p = []models.Photo{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+// SpecialsJSON handles JSON scanning for aggregated specials
+type SpecialsJSON []models.Special
+
+// Scan implements the sql.Scanner interface
+func (s *SpecialsJSON) Scan(value interface{}) error {
+	if value == nil {
+		*s = []models.Special{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+// POI represents a Point of Interest from the database
+type POI struct {
+	PoiID                  uuid.UUID      `db:"poi_id" json:"poi_id"`
+	Name                   string         `db:"name" json:"name"`
+	CategoryID             *uuid.UUID     `db:"category_id" json:"category_id,omitempty"`
+	CategoryNames          pq.StringArray `db:"category_names" json:"category_names,omitempty"`
+	Website                *string        `db:"website" json:"website,omitempty"`
+	Brand                  *string        `db:"brand" json:"brand,omitempty"`
+	Description            *string        `db:"description" json:"description,omitempty"`
+	AddressID              *uuid.UUID     `db:"address_id" json:"address_id,omitempty"`
+	Latitude               float64        `db:"latitude" json:"latitude"`
+	Longitude              float64        `db:"longitude" json:"longitude"`
+	ParkingInfo            *string        `db:"parking_info" json:"parking_info,omitempty"`
+	Amenities              pq.StringArray `db:"amenities" json:"amenities,omitempty"`
+	HasWifi                bool           `db:"has_wifi" json:"has_wifi"`
+	OutdoorSeating         bool           `db:"outdoor_seating" json:"outdoor_seating"`
+	IsWheelchairAccessible bool           `db:"is_wheelchair_accessible" json:"is_wheelchair_accessible"`
+	HasDelivery            bool           `db:"has_delivery" json:"has_delivery"`
+	Cuisine                *string        `db:"cuisine" json:"cuisine,omitempty"`
+	PriceRange             *int           `db:"price_range" json:"price_range,omitempty"` // Legacy 1-4 band, derived from avg spend for IDR
+	PriceCurrency          *string        `db:"price_currency" json:"price_currency,omitempty"`
+	AvgSpendMin            *float64       `db:"avg_spend_min" json:"avg_spend_min,omitempty"`
+	AvgSpendMax            *float64       `db:"avg_spend_max" json:"avg_spend_max,omitempty"`
+	FoodOptions            pq.StringArray `db:"food_options" json:"food_options,omitempty"`
+	PaymentOptions         pq.StringArray `db:"payment_options" json:"payment_options,omitempty"`
+	KidsFriendly           bool           `db:"kids_friendly" json:"kids_friendly"`
+	SmokerFriendly         bool           `db:"smoker_friendly" json:"smoker_friendly"`
+	PetFriendly            pq.StringArray `db:"pet_friendly" json:"pet_friendly,omitempty"`
+	// New extended fields
+	FloorUnit           *string          `db:"floor_unit" json:"floor_unit,omitempty"`
+	PublicTransport     *string          `db:"public_transport" json:"public_transport,omitempty"`
+	CoverImageURL       *string          `db:"cover_image_url" json:"cover_image_url,omitempty"`
+	GalleryImageURLs    pq.StringArray   `db:"gallery_image_urls" json:"gallery_image_urls,omitempty"`
+	GalleryImages       PhotosJSON       `db:"gallery_images" json:"gallery_images,omitempty"`
+	WifiQuality         *string          `db:"wifi_quality" json:"wifi_quality,omitempty"`
+	PowerOutlets        *string          `db:"power_outlets" json:"power_outlets,omitempty"`
+	SeatingOptions      pq.StringArray   `db:"seating_options" json:"seating_options,omitempty"`
+	NoiseLevel          *string          `db:"noise_level" json:"noise_level,omitempty"`
+	HasAC               bool             `db:"has_ac" json:"has_ac"`
+	Vibes               pq.StringArray   `db:"vibes" json:"vibes,omitempty"`
+	CrowdType           pq.StringArray   `db:"crowd_type" json:"crowd_type,omitempty"`
+	Lighting            *string          `db:"lighting" json:"lighting,omitempty"`
+	MusicType           *string          `db:"music_type" json:"music_type,omitempty"`
+	Cleanliness         *string          `db:"cleanliness" json:"cleanliness,omitempty"`
+	CategoryIDs         pq.StringArray   `db:"category_ids" json:"category_ids,omitempty"`
+	ParkingOptions      pq.StringArray   `db:"parking_options" json:"parking_options,omitempty"`
+	PetPolicy           *string          `db:"pet_policy" json:"pet_policy,omitempty"`
+	DietaryOptions      pq.StringArray   `db:"dietary_options" json:"dietary_options,omitempty"`
+	FeaturedItems       pq.StringArray   `db:"featured_menu_items" json:"featured_items,omitempty"`
+	Specials            pq.StringArray   `db:"specials" json:"specials,omitempty"` // Deprecated: free-text, see ScheduledSpecials
+	ScheduledSpecials   SpecialsJSON     `db:"scheduled_specials" json:"scheduled_specials,omitempty"`
+	OpenHours           *json.RawMessage `db:"open_hours" json:"open_hours,omitempty"`
+	ReservationRequired bool             `db:"reservation_required" json:"reservation_required"`
+	ReservationPlatform *string          `db:"reservation_platform" json:"reservation_platform,omitempty"`
+	WaitTimeEstimate    *int             `db:"wait_time_estimate" json:"wait_time_estimate,omitempty"`
+	HappyHourInfo       *string          `db:"happy_hour_info" json:"happy_hour_info,omitempty"`
+	LoyaltyProgram      *string          `db:"loyalty_program" json:"loyalty_program,omitempty"`
+	Phone               *string          `db:"phone" json:"phone,omitempty"`
+	Email               *string          `db:"email" json:"email,omitempty"`
+	SocialLinks         *json.RawMessage `db:"social_media_links" json:"social_links,omitempty"`
+	// Accessibility details, nil when unknown
+	StepFreeEntrance   *bool          `db:"step_free_entrance" json:"step_free_entrance,omitempty"`
+	AccessibleRestroom *bool          `db:"accessible_restroom" json:"accessible_restroom,omitempty"`
+	TableHeights       pq.StringArray `db:"table_heights" json:"table_heights,omitempty"`
+	BrailleMenu        *bool          `db:"braille_menu" json:"braille_menu,omitempty"`
+	AccessibleParking  *bool          `db:"accessible_parking" json:"accessible_parking,omitempty"`
+	AccessibilityNotes *string        `db:"accessibility_notes" json:"accessibility_notes,omitempty"`
+	// Status workflow fields
+	Status         string     `db:"status" json:"status"`
+	SubmittedAt    *time.Time `db:"submitted_at" json:"submitted_at,omitempty"`
+	RejectedReason *string    `db:"rejected_reason" json:"rejected_reason,omitempty"`
+	CreatedBy      *uuid.UUID `db:"created_by" json:"created_by,omitempty"`
+	// Checklist percentage, only populated by the admin review queue
+	CompletenessScore *int `db:"completeness_score" json:"completeness_score,omitempty"`
+	// Review SLA and active reviewer assignment, likewise only set by the review queue
+	Overdue             *bool      `db:"overdue" json:"overdue,omitempty"`
+	AssignedTo          *uuid.UUID `db:"assigned_to" json:"assigned_to,omitempty"`
+	AssigneeName        *string    `db:"assignee_name" json:"assignee_name,omitempty"`
+	AssignmentExpiresAt *time.Time `db:"assignment_expires_at" json:"assignment_expires_at,omitempty"`
+	// Verification fields
+	IsVerified bool       `db:"is_verified" json:"is_verified"`
+	VerifiedAt *time.Time `db:"verified_at" json:"verified_at,omitempty"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at" json:"updated_at"`
+	// Open data license attribution of imported POIs
+	SourceAttribution *string `db:"source_attribution" json:"source_attribution,omitempty"`
+	// Fetched fields (e.g. from joins)
+	Address *string `db:"address" json:"address,omitempty"`
+	// Gamification & Granular Data
+	FoundingUserID       *uuid.UUID `db:"founding_user_id" json:"founding_user_id,omitempty"`
+	WifiSpeedMbps        *int       `db:"wifi_speed_mbps" json:"wifi_speed_mbps,omitempty"`
+	WifiVerifiedAt       *time.Time `db:"wifi_verified_at" json:"wifi_verified_at,omitempty"`
+	ErgonomicSeating     bool       `db:"ergonomic_seating" json:"ergonomic_seating"`
+	PowerSocketsReach    *string    `db:"power_sockets_reach" json:"power_sockets_reach,omitempty"`
+	FoundingUserUsername *string    `db:"founding_user_username" json:"founding_user_username"`
+	RatingAvg            float64    `db:"rating_avg" json:"rating_avg"`
+	ReviewsCount         int        `db:"reviews_count" json:"reviews_count"`
+	SavedAt              *time.Time `db:"saved_at" json:"saved_at,omitempty"`
+	ContentLocale        *string    `db:"content_locale" json:"-"` // Locale of the translation applied, if any
+
+	// Per-dimension review averages, nil until a review rates the dimension
+	WifiRatingAvg          *float64 `db:"wifi_rating_avg" json:"wifi_rating_avg,omitempty"`
+	ComfortRatingAvg       *float64 `db:"comfort_rating_avg" json:"comfort_rating_avg,omitempty"`
+	FoodRatingAvg          *float64 `db:"food_rating_avg" json:"food_rating_avg,omitempty"`
+	ValueRatingAvg         *float64 `db:"value_rating_avg" json:"value_rating_avg,omitempty"`
+	NoiseAccuracyRatingAvg *float64 `db:"noise_accuracy_rating_avg" json:"noise_accuracy_rating_avg,omitempty"`
+
+	// Optional expansions (?include=...)
+	Menu    []models.MenuSection `db:"-" json:"menu,omitempty"`
+	Reviews []models.Review      `db:"-" json:"reviews,omitempty"`
+
+	// Open rejection feedback, only loaded for the submitter and moderators
+	RequiresChanges []RejectionFeedback `db:"-" json:"requires_changes,omitempty"`
+}
+
+// POIWithDistance represents a POI with distance from a point
+type POIWithDistance struct {
+	POI
+	DistanceMeters float64 `db:"distance_meters" json:"distance_meters"`
+
+	// Set by travel-time enrichment (travel_mode on /pois/nearby)
+	TravelDurationSeconds *float64 `db:"-" json:"travel_duration_seconds,omitempty"`
+	TravelDistanceMeters  *float64 `db:"-" json:"travel_distance_meters,omitempty"`
+}
+
+// CreatePOIInput represents input for creating a POI
+type CreatePOIInput struct {
+	// Profile & Visuals
+	Name             string
+	BrandName        *string
+	Categories       []string
+	Description      *string
+	CoverImageURL    *string
+	GalleryImageURLs []string
+	CategoryIDs      []string
+	// Location
+	Address              *string
+	District             *string // Kecamatan
+	City                 *string // Kabupaten
+	Village              *string // Kelurahan
+	PostalCode           *string
+	FloorUnit            *string
+	Latitude             float64
+	Longitude            float64
+	PublicTransport      *string
+	ParkingOptions       []string
+	WheelchairAccessible bool
+	// Work & Prod
+	WifiQuality    *string
+	PowerOutlets   *string
+	SeatingOptions []string
+	NoiseLevel     *string
+	HasAC          bool
+	// Atmosphere
+	Vibes       []string
+	CrowdType   []string
+	Lighting    *string
+	MusicType   *string
+	Cleanliness *string
+	// Food & Drink
+	Cuisine        *string
+	PriceRange     *int
+	PriceCurrency  *string  // ISO 4217, defaults to IDR
+	AvgSpendMin    *float64 // Average spend per person
+	AvgSpendMax    *float64
+	DietaryOptions []string
+	FeaturedItems  []string
+	Specials       []string
+	// Operations
+	OpenHours           map[string]interface{}
+	ReservationRequired bool
+	ReservationPlatform *string
+	PaymentOptions      []string
+	WaitTimeEstimate    *int
+	// Social & Lifestyle
+	KidsFriendly   bool
+	PetFriendly    []string
+	PetPolicy      *string
+	SmokerFriendly bool
+	HappyHourInfo  *string
+	LoyaltyProgram *string
+	// Amenity keys; nil keeps the current amenities on update
+	Amenities []string
+	// Contact
+	Phone       *string
+	Email       *string
+	Website     *string
+	SocialLinks map[string]interface{}
+	// Accessibility
+	StepFreeEntrance   *bool
+	AccessibleRestroom *bool
+	TableHeights       []string
+	BrailleMenu        *bool
+	AccessibleParking  *bool
+	AccessibilityNotes *string
+	// Metadata
+	CreatedBy     *uuid.UUID
+	InitialStatus *string // Defaults to 'draft' if nil
+
+	// Gamification & Granular Data (New in v1.3.0)
+	WifiSpeedMbps     *int
+	ErgonomicSeating  bool
+	PowerSocketsReach *string
+	FoundingUserID    *uuid.UUID
+}
+
+// UpdatePOIInput represents input for updating a POI (partial update)
+type UpdatePOIInput struct {
+	Name           *string
+	Description    *string
+	HasWifi        *bool
+	OutdoorSeating *bool
+	PriceRange     *int
+	Amenities      []string
+}
+
+// UpdateFullInput is the input of a full POI update, the same content as a
+// create. UpdateFull ignores the address parts and metadata, and keeps the
+// current value of optional details (price, accessibility) left nil.
+type UpdateFullInput = CreatePOIInput
+
+// Helper function to check if slice contains a value
+func contains(slice []string, val string) bool {
+	for _, item := range slice {
+		if item == val {
+			return true
+		}
+	}
+	return false
+}
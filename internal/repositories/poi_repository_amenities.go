@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ErrInvalidAmenity is returned when a POI is tagged with an unknown amenity or
+// one derived from parking_options or seating_options
+var ErrInvalidAmenity = errors.New("invalid amenity")
+
+// Match modes of the amenities search filter
+const (
+	AmenitiesMatchAll = "all"
+	AmenitiesMatchAny = "any"
+)
+
+// poiAmenitiesExpr selects the amenity keys of the POI aliased as p
+const poiAmenitiesExpr = `COALESCE((
+		           SELECT array_agg(am.key ORDER BY am.sort_order, am.key)
+		           FROM poi_amenities pa
+		           JOIN amenities am ON am.amenity_id = pa.amenity_id
+		           WHERE pa.poi_id = p.poi_id
+		       ), '{}') as amenities`
+
+// setPOIAmenities replaces the amenities a POI is tagged with. Amenities
+// derived from parking_options and seating_options follow those columns and
+// cannot be set here.
+func setPOIAmenities(ctx context.Context, q sqlx.ExtContext, poiID uuid.UUID, keys []string) error {
+	var known []string
+	err := sqlx.SelectContext(ctx, q, &known, `
+		SELECT key FROM amenities WHERE key = ANY($1) AND legacy_field IS NULL
+	`, pq.StringArray(keys))
+	if err != nil {
+		return fmt.Errorf("check amenities: %w", err)
+	}
+	if invalid := missingKeys(keys, known); len(invalid) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidAmenity, strings.Join(invalid, ", "))
+	}
+
+	_, err = q.ExecContext(ctx, `
+		DELETE FROM poi_amenities pa
+		USING amenities a
+		WHERE pa.amenity_id = a.amenity_id AND pa.poi_id = $1 AND a.legacy_field IS NULL
+	`, poiID)
+	if err != nil {
+		return fmt.Errorf("clear poi amenities: %w", err)
+	}
+	_, err = q.ExecContext(ctx, `
+		INSERT INTO poi_amenities (poi_id, amenity_id)
+		SELECT $1, amenity_id FROM amenities WHERE key = ANY($2)
+		ON CONFLICT DO NOTHING
+	`, poiID, pq.StringArray(keys))
+	if err != nil {
+		return fmt.Errorf("set poi amenities: %w", err)
+	}
+	return nil
+}
+
+// amenitiesCondition matches POIs (aliased p) tagged with any or all of the
+// distinct amenity keys in keysParam
+func amenitiesCondition(keysParam string, matchAny bool) string {
+	tagged := `(SELECT COUNT(*) FROM poi_amenities pa
+		JOIN amenities am ON am.amenity_id = pa.amenity_id
+		WHERE pa.poi_id = p.poi_id AND am.key = ANY(` + keysParam + `))`
+	if matchAny {
+		return tagged + " > 0"
+	}
+	return tagged + " = cardinality(" + keysParam + "::text[])"
+}
+
+// uniqueStrings drops repeated values, keeping the first occurrence
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := values[:0]
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+// missingKeys returns the keys not in known, sorted and without duplicates
+func missingKeys(keys, known []string) []string {
+	found := make(map[string]bool, len(known))
+	for _, k := range known {
+		found[k] = true
+	}
+	var missing []string
+	for _, k := range keys {
+		if !found[k] {
+			found[k] = true
+			missing = append(missing, k)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
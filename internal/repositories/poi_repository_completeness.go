@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// completenessCheck is one checklist item of a POI submission. present is the
+// SQL condition that satisfies it (points_of_interest aliased as p). Required
+// items gate submission; fields names the wizard fields that fill the item.
+type completenessCheck struct {
+	section  string
+	item     string
+	required bool
+	fields   []string
+	present  string
+}
+
+// completenessChecks is the submission checklist, in wizard order
+var completenessChecks = []completenessCheck{
+	{"profile", "name", true, []string{"name"}, "COALESCE(btrim(p.name), '') <> ''"},
+	{"profile", "category", true, []string{"category_ids"}, "COALESCE(cardinality(p.category_ids), 0) > 0 OR p.category_id IS NOT NULL"},
+	{"profile", "description", false, []string{"description"}, "length(COALESCE(btrim(p.description), '')) >= 20"},
+	{"photos", "cover_photo", true, []string{"cover_image_url"}, "COALESCE(p.cover_image_url, '') <> ''"},
+	{"photos", "gallery", false, []string{"gallery_image_urls"}, "COALESCE(cardinality(p.gallery_image_urls), 0) >= 3"},
+	{"location", "address", true, []string{"address"}, "EXISTS (SELECT 1 FROM addresses a WHERE a.address_id = p.address_id AND COALESCE(btrim(a.street_address), '') <> '')"},
+	{"location", "coordinates", true, []string{"latitude", "longitude"}, "p.location IS NOT NULL AND NOT (ST_X(p.location::geometry) = 0 AND ST_Y(p.location::geometry) = 0)"},
+	{"operations", "open_hours", true, []string{"open_hours"}, "p.open_hours IS NOT NULL AND p.open_hours <> '{}'::jsonb"},
+	{"operations", "payment_options", false, []string{"payment_options"}, "COALESCE(cardinality(p.payment_options), 0) > 0"},
+	{"work-prod", "wifi_details", false, []string{"wifi_quality"}, "p.wifi_quality IS NOT NULL OR p.wifi_speed_mbps IS NOT NULL"},
+	{"work-prod", "power_outlets", false, []string{"power_outlets"}, "p.power_outlets IS NOT NULL"},
+	{"atmosphere", "vibes", false, []string{"vibes"}, "COALESCE(cardinality(p.vibes), 0) > 0"},
+	{"food-drink", "price_range", false, []string{"price_range"}, "p.price_range IS NOT NULL"},
+	{"accessibility", "entrance", false, []string{"step_free_entrance"}, "p.step_free_entrance IS NOT NULL"},
+	{"contact", "phone_or_email", false, []string{"phone", "email"}, "COALESCE(p.phone, '') <> '' OR COALESCE(p.email, '') <> ''"},
+	{"contact", "website_or_social", false, []string{"website", "social_links"}, "COALESCE(p.website, '') <> '' OR (p.social_media_links IS NOT NULL AND p.social_media_links <> '{}'::jsonb)"},
+}
+
+// CompletenessItem is one checklist entry
+type CompletenessItem struct {
+	Item     string   `json:"item"`
+	Required bool     `json:"required"`
+	Complete bool     `json:"complete"`
+	Fields   []string `json:"fields"`
+}
+
+// CompletenessSection groups checklist entries of one wizard section
+type CompletenessSection struct {
+	Section  string             `json:"section"`
+	Complete int                `json:"complete"`
+	Total    int                `json:"total"`
+	Items    []CompletenessItem `json:"items"`
+}
+
+// Completeness is a POI's submission checklist and score
+type Completeness struct {
+	PoiID         uuid.UUID             `json:"poi_id"`
+	Score         int                   `json:"score"` // Percentage of checklist items complete
+	ReadyToSubmit bool                  `json:"ready_to_submit"`
+	MissingFields []string              `json:"missing_fields"` // Fields of incomplete required items
+	Sections      []CompletenessSection `json:"sections"`
+}
+
+// completenessScoreExpr is the SQL percentage of checklist items a POI satisfies
+func completenessScoreExpr() string {
+	terms := make([]string, len(completenessChecks))
+	for i, check := range completenessChecks {
+		terms[i] = fmt.Sprintf("(CASE WHEN %s THEN 1 ELSE 0 END)", check.present)
+	}
+	return fmt.Sprintf("ROUND(100.0 * (%s) / %d)::int", strings.Join(terms, " + "), len(completenessChecks))
+}
+
+// GetCompleteness evaluates the submission checklist for a POI
+func (r *POIRepository) GetCompleteness(ctx context.Context, poiID uuid.UUID) (*Completeness, error) {
+	exprs := make([]string, len(completenessChecks))
+	for i, check := range completenessChecks {
+		exprs[i] = fmt.Sprintf("COALESCE((%s), false)", check.present)
+	}
+
+	complete := make([]bool, len(completenessChecks))
+	dest := make([]interface{}, len(complete))
+	for i := range complete {
+		dest[i] = &complete[i]
+	}
+	err := r.db.Conn(ctx).QueryRowContext(ctx, fmt.Sprintf(`
+		SELECT %s FROM points_of_interest p WHERE p.poi_id = $1
+	`, strings.Join(exprs, ", ")), poiID).Scan(dest...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get poi completeness: %w", err)
+	}
+
+	result := &Completeness{PoiID: poiID, MissingFields: []string{}, Sections: []CompletenessSection{}}
+	done := 0
+	for i, check := range completenessChecks {
+		if n := len(result.Sections); n == 0 || result.Sections[n-1].Section != check.section {
+			result.Sections = append(result.Sections, CompletenessSection{Section: check.section})
+		}
+		section := &result.Sections[len(result.Sections)-1]
+		section.Total++
+		section.Items = append(section.Items, CompletenessItem{
+			Item:     check.item,
+			Required: check.required,
+			Complete: complete[i],
+			Fields:   check.fields,
+		})
+
+		if complete[i] {
+			section.Complete++
+			done++
+		} else if check.required {
+			result.MissingFields = append(result.MissingFields, check.fields...)
+		}
+	}
+	result.Score = int(math.Round(100 * float64(done) / float64(len(completenessChecks))))
+	result.ReadyToSubmit = len(result.MissingFields) == 0
+	return result, nil
+}
+
+// MissingForSubmission lists the required fields the POI does not have yet
+func (r *POIRepository) MissingForSubmission(ctx context.Context, poiID uuid.UUID) ([]string, error) {
+	completeness, err := r.GetCompleteness(ctx, poiID)
+	if err != nil {
+		return nil, err
+	}
+	return completeness.MissingFields, nil
+}
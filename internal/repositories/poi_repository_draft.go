@@ -0,0 +1,282 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidDraft is returned when an autosave contains unknown fields or badly typed values
+	ErrInvalidDraft = errors.New("invalid draft")
+	// ErrPOINotEditableDraft is returned when autosaving a POI that is no longer a draft
+	ErrPOINotEditableDraft = errors.New("only draft or rejected POIs can be autosaved")
+)
+
+// draftField maps a submission wizard field to its POI column, JSON value kind
+// and the wizard section it belongs to
+type draftField struct {
+	column  string
+	kind    string // string, int, number, currency, bool, json, strings (text[]), float (coordinates), address
+	section string
+	notNull bool
+}
+
+// draftFields is the whitelist of fields accepted by draft autosave. Keys are
+// the JSON names used by CreatePOIRequest, sections match the section editing routes.
+var draftFields = map[string]draftField{
+	// Profile
+	"name":               {column: "name", kind: "string", section: "profile", notNull: true},
+	"brand_name":         {column: "brand", kind: "string", section: "profile"},
+	"description":        {column: "description", kind: "string", section: "profile"},
+	"category_ids":       {column: "category_ids", kind: "strings", section: "profile"},
+	"cover_image_url":    {column: "cover_image_url", kind: "string", section: "profile"},
+	"gallery_image_urls": {column: "gallery_image_urls", kind: "strings", section: "profile"},
+	// Location
+	"address":               {kind: "address", section: "location"},
+	"latitude":              {kind: "float", section: "location"},
+	"longitude":             {kind: "float", section: "location"},
+	"floor_unit":            {column: "floor_unit", kind: "string", section: "location"},
+	"public_transport":      {column: "public_transport", kind: "string", section: "location"},
+	"parking_options":       {column: "parking_options", kind: "strings", section: "location"},
+	"wheelchair_accessible": {column: "is_wheelchair_accessible", kind: "bool", section: "location"},
+	// Operations
+	"open_hours":           {column: "open_hours", kind: "json", section: "operations"},
+	"reservation_required": {column: "reservation_required", kind: "bool", section: "operations"},
+	"reservation_platform": {column: "reservation_platform", kind: "string", section: "operations"},
+	"payment_options":      {column: "payment_options", kind: "strings", section: "operations"},
+	"wait_time_estimate":   {column: "wait_time_estimate", kind: "int", section: "operations"},
+	// Work & Prod
+	"wifi_quality":    {column: "wifi_quality", kind: "string", section: "work-prod"},
+	"power_outlets":   {column: "power_outlets", kind: "string", section: "work-prod"},
+	"seating_options": {column: "seating_options", kind: "strings", section: "work-prod"},
+	"noise_level":     {column: "noise_level", kind: "string", section: "work-prod"},
+	"has_ac":          {column: "has_ac", kind: "bool", section: "work-prod"},
+	// Atmosphere
+	"vibes":       {column: "vibes", kind: "strings", section: "atmosphere"},
+	"crowd_type":  {column: "crowd_type", kind: "strings", section: "atmosphere"},
+	"lighting":    {column: "lighting", kind: "string", section: "atmosphere"},
+	"music_type":  {column: "music_type", kind: "string", section: "atmosphere"},
+	"cleanliness": {column: "cleanliness", kind: "string", section: "atmosphere"},
+	// Food & Drink
+	"cuisine":         {column: "cuisine", kind: "string", section: "food-drink"},
+	"price_range":     {column: "price_range", kind: "int", section: "food-drink"},
+	"price_currency":  {column: "price_currency", kind: "currency", section: "food-drink", notNull: true},
+	"avg_spend_min":   {column: "avg_spend_min", kind: "number", section: "food-drink"},
+	"avg_spend_max":   {column: "avg_spend_max", kind: "number", section: "food-drink"},
+	"dietary_options": {column: "dietary_options", kind: "strings", section: "food-drink"},
+	"featured_items":  {column: "featured_menu_items", kind: "strings", section: "food-drink"},
+	"specials":        {column: "specials", kind: "strings", section: "food-drink"},
+	// Social & Lifestyle
+	"kids_friendly":   {column: "kids_friendly", kind: "bool", section: "social"},
+	"pet_friendly":    {column: "pet_friendly", kind: "strings", section: "social"},
+	"smoker_friendly": {column: "smoker_friendly", kind: "bool", section: "social"},
+	"happy_hour_info": {column: "happy_hour_info", kind: "string", section: "social"},
+	"loyalty_program": {column: "loyalty_program", kind: "string", section: "social"},
+	// Accessibility
+	"step_free_entrance":  {column: "step_free_entrance", kind: "bool", section: "accessibility"},
+	"accessible_restroom": {column: "accessible_restroom", kind: "bool", section: "accessibility"},
+	"table_heights":       {column: "table_heights", kind: "strings", section: "accessibility"},
+	"braille_menu":        {column: "braille_menu", kind: "bool", section: "accessibility"},
+	"accessible_parking":  {column: "accessible_parking", kind: "bool", section: "accessibility"},
+	"accessibility_notes": {column: "accessibility_notes", kind: "string", section: "accessibility"},
+	// Contact
+	"phone":        {column: "phone", kind: "string", section: "contact"},
+	"email":        {column: "email", kind: "string", section: "contact"},
+	"website":      {column: "website", kind: "string", section: "contact"},
+	"social_links": {column: "social_media_links", kind: "json", section: "contact"},
+}
+
+// DraftSaveResult reports what an autosave applied
+type DraftSaveResult struct {
+	SavedFields     []string `json:"saved_fields"`
+	SkippedSections []string `json:"skipped_sections"` // Sections with a newer save already applied
+}
+
+// ValidateDraftFields checks that every field can be autosaved and its value has the right type
+func ValidateDraftFields(fields map[string]json.RawMessage) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("%w: no fields", ErrInvalidDraft)
+	}
+	for name, raw := range fields {
+		field, ok := draftFields[name]
+		if !ok {
+			return fmt.Errorf("%w: field %q cannot be autosaved", ErrInvalidDraft, name)
+		}
+		isNull := strings.TrimSpace(string(raw)) == "null"
+		if field.notNull && isNull {
+			return fmt.Errorf("%w: field %q must not be null", ErrInvalidDraft, name)
+		}
+
+		var err error
+		switch field.kind {
+		case "strings":
+			var v []string
+			err = json.Unmarshal(raw, &v)
+		case "float":
+			var v *float64
+			err = json.Unmarshal(raw, &v)
+		case "address":
+			err = checkProposalValue("string", raw)
+		default:
+			err = checkProposalValue(field.kind, raw)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: field %q: %v", ErrInvalidDraft, name, err)
+		}
+	}
+	return nil
+}
+
+// SaveDraft applies a partial autosave to a draft or rejected POI. Fields are
+// grouped by wizard section; a section whose last applied save is newer than
+// savedAt is skipped, so autosaves arriving out of order cannot roll back
+// newer input. Applied sections are recorded as edited by editedBy. Callers
+// validate fields with ValidateDraftFields first.
+func (r *POIRepository) SaveDraft(ctx context.Context, poiID uuid.UUID, fields map[string]json.RawMessage, savedAt time.Time, editedBy *uuid.UUID) (*DraftSaveResult, error) {
+	result := &DraftSaveResult{SavedFields: []string{}, SkippedSections: []string{}}
+
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		conn := r.db.Conn(ctx)
+
+		status, err := r.GetStatusForUpdate(ctx, poiID)
+		if err != nil {
+			return err
+		}
+		if status != "draft" && status != "rejected" {
+			return ErrPOINotEditableDraft
+		}
+
+		bySection := map[string][]string{}
+		for name := range fields {
+			section := draftFields[name].section
+			bySection[section] = append(bySection[section], name)
+		}
+
+		var names, applied []string
+		for section, sectionFields := range bySection {
+			var ok bool
+			err := conn.QueryRowContext(ctx, `
+				INSERT INTO poi_draft_sections (poi_id, section, client_saved_at)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (poi_id, section) DO UPDATE
+				SET client_saved_at = EXCLUDED.client_saved_at, updated_at = NOW()
+				WHERE poi_draft_sections.client_saved_at <= EXCLUDED.client_saved_at
+				RETURNING true
+			`, poiID, section, savedAt).Scan(&ok)
+			if errors.Is(err, sql.ErrNoRows) {
+				result.SkippedSections = append(result.SkippedSections, section)
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("record draft section: %w", err)
+			}
+			names = append(names, sectionFields...)
+			applied = append(applied, section)
+		}
+		sort.Strings(names)
+		sort.Strings(result.SkippedSections)
+		if len(names) == 0 {
+			return nil
+		}
+
+		sets := []string{"updated_at = NOW()"}
+		args := []interface{}{poiID}
+		var lat, lng json.RawMessage
+		for _, name := range names {
+			field := draftFields[name]
+			switch field.kind {
+			case "float":
+				if name == "latitude" {
+					lat = fields[name]
+				} else {
+					lng = fields[name]
+				}
+			case "address":
+				if err := r.saveDraftAddress(ctx, poiID, fields[name]); err != nil {
+					return err
+				}
+			case "strings":
+				args = append(args, string(fields[name]))
+				sets = append(sets, fmt.Sprintf("%s = ARRAY(SELECT jsonb_array_elements_text(COALESCE(NULLIF($%d::jsonb, 'null'::jsonb), '[]'::jsonb)))", field.column, len(args)))
+			default:
+				args = append(args, string(fields[name]))
+				sets = append(sets, fmt.Sprintf("%s = %s", field.column, proposalValueExpr(field.kind, len(args))))
+			}
+		}
+		if lat != nil || lng != nil {
+			// A coordinate saved on its own keeps the other half of the stored point
+			args = append(args, nullableJSON(lng), nullableJSON(lat))
+			sets = append(sets, fmt.Sprintf(`location = ST_SetSRID(ST_MakePoint(
+				COALESCE(($%d::jsonb #>> '{}')::float8, ST_X(location::geometry), 0),
+				COALESCE(($%d::jsonb #>> '{}')::float8, ST_Y(location::geometry), 0)), 4326)::geography`, len(args)-1, len(args)))
+		}
+
+		query := fmt.Sprintf(`UPDATE points_of_interest SET %s WHERE poi_id = $1`, strings.Join(sets, ", "))
+		if _, err := conn.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("save draft: %w", err)
+		}
+		if err := r.recordSectionEdits(ctx, conn, poiID, applied, editedBy); err != nil {
+			return err
+		}
+
+		if raw, ok := fields["gallery_image_urls"]; ok && contains(names, "gallery_image_urls") {
+			var urls []string
+			_ = json.Unmarshal(raw, &urls)
+			if err := r.syncPhotos(ctx, conn, poiID, urls); err != nil {
+				return fmt.Errorf("sync photos: %w", err)
+			}
+		}
+
+		result.SavedFields = names
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// saveDraftAddress updates the street address line, creating the address row on first save
+func (r *POIRepository) saveDraftAddress(ctx context.Context, poiID uuid.UUID, raw json.RawMessage) error {
+	var street *string
+	if err := json.Unmarshal(raw, &street); err != nil {
+		return fmt.Errorf("decode draft address: %w", err)
+	}
+
+	conn := r.db.Conn(ctx)
+	res, err := conn.ExecContext(ctx, `
+		UPDATE addresses SET street_address = $2
+		WHERE address_id = (SELECT address_id FROM points_of_interest WHERE poi_id = $1)
+	`, poiID, street)
+	if err != nil {
+		return fmt.Errorf("update draft address: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 || street == nil {
+		return nil
+	}
+
+	_, err = conn.ExecContext(ctx, `
+		WITH a AS (INSERT INTO addresses (street_address) VALUES ($2) RETURNING address_id)
+		UPDATE points_of_interest SET address_id = (SELECT address_id FROM a) WHERE poi_id = $1
+	`, poiID, street)
+	if err != nil {
+		return fmt.Errorf("create draft address: %w", err)
+	}
+	return nil
+}
+
+// nullableJSON passes an absent JSON value as SQL NULL
+func nullableJSON(raw json.RawMessage) interface{} {
+	if raw == nil {
+		return nil
+	}
+	return string(raw)
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// searchFacet describes how to count the values of one search filter
+type searchFacet struct {
+	join    string   // Extra FROM items producing the values
+	value   string   // Value expression, cast to text
+	filters []string // Search filter keys ignored while counting this facet
+}
+
+// searchFacets are the search parameters that can be counted with ?facets=.
+// Array columns count each POI once per value they contain.
+var searchFacets = map[string]searchFacet{
+	"category_id":     {value: "p.category_id::text", filters: []string{"category_id"}},
+	"price_range":     {value: "p.price_range::text", filters: []string{"price_range"}},
+	"wifi_quality":    {value: "p.wifi_quality", filters: []string{"wifi_quality"}},
+	"noise_level":     {value: "p.noise_level", filters: []string{"noise_level"}},
+	"power_outlets":   {value: "p.power_outlets", filters: []string{"power_outlets"}},
+	"cuisine":         {value: "p.cuisine", filters: []string{"cuisine"}},
+	"vibes":           {join: " CROSS JOIN LATERAL unnest(p.vibes) AS f(value)", value: "f.value", filters: []string{"vibes"}},
+	"crowd_type":      {join: " CROSS JOIN LATERAL unnest(p.crowd_type) AS f(value)", value: "f.value", filters: []string{"crowd_type"}},
+	"dietary_options": {join: " CROSS JOIN LATERAL unnest(p.dietary_options) AS f(value)", value: "f.value", filters: []string{"dietary_options"}},
+	"seating_options": {join: " CROSS JOIN LATERAL unnest(p.seating_options) AS f(value)", value: "f.value", filters: []string{"seating_options"}},
+	"parking_options": {join: " CROSS JOIN LATERAL unnest(p.parking_options) AS f(value)", value: "f.value", filters: []string{"parking_options"}},
+	"table_heights":   {join: " CROSS JOIN LATERAL unnest(p.table_heights) AS f(value)", value: "f.value", filters: []string{"table_heights"}},
+	"amenities": {
+		join:    " JOIN poi_amenities fpa ON fpa.poi_id = p.poi_id JOIN amenities fa ON fa.amenity_id = fpa.amenity_id",
+		value:   "fa.key",
+		filters: []string{"amenities", "amenities_match"},
+	},
+}
+
+// maxSearchFacets caps how many facets one search may count
+const maxSearchFacets = 6
+
+// ParseSearchFacets parses the comma-separated facets parameter of
+// GET /api/v1/pois into the facets to count, without duplicates
+func ParseSearchFacets(raw string) ([]string, error) {
+	facets := uniqueStrings(ParseCommaSeparated(strings.ToLower(raw)))
+	for _, f := range facets {
+		if _, ok := searchFacets[f]; !ok {
+			names := make([]string, 0, len(searchFacets))
+			for name := range searchFacets {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("facets must be among %s", strings.Join(names, ", "))
+		}
+	}
+	if len(facets) > maxSearchFacets {
+		return nil, fmt.Errorf("at most %d facets can be requested", maxSearchFacets)
+	}
+	return facets, nil
+}
+
+// SearchFacets counts, for each facet, the POIs matching the Search filters
+// per value of that facet. A facet's own filter is ignored while counting it
+// so every option shows how many results selecting it would give.
+func (r *POIRepository) SearchFacets(ctx context.Context, filters map[string]interface{}, facets []string) (map[string]map[string]int, error) {
+	counts := make(map[string]map[string]int, len(facets))
+	for _, name := range facets {
+		facet, ok := searchFacets[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown search facet %q", name)
+		}
+
+		scoped := make(map[string]interface{}, len(filters))
+		for k, v := range filters {
+			scoped[k] = v
+		}
+		for _, k := range facet.filters {
+			delete(scoped, k)
+		}
+		where := searchConditions(scoped, 1)
+
+		var rows []struct {
+			Value string `db:"value"`
+			Count int    `db:"count"`
+		}
+		query := fmt.Sprintf(`
+			SELECT %[1]s AS value, COUNT(DISTINCT p.poi_id) AS count
+			FROM points_of_interest p%[2]s
+			WHERE %[1]s IS NOT NULL AND %[1]s <> ''%[3]s
+			GROUP BY 1
+		`, facet.value, facet.join, where.sql)
+		if err := r.db.ReadConn(ctx).SelectContext(ctx, &rows, query, where.args...); err != nil {
+			return nil, fmt.Errorf("count search facet %s: %w", name, err)
+		}
+
+		counts[name] = make(map[string]int, len(rows))
+		for _, row := range rows {
+			counts[name][row.Value] = row.Count
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// RejectionFeedback is a moderator's comment on a section, or a single field
+// of it, that must change before a rejected POI can be approved
+type RejectionFeedback struct {
+	FeedbackID uuid.UUID  `db:"feedback_id" json:"feedback_id"`
+	Section    string     `db:"section" json:"section"`
+	Field      *string    `db:"field" json:"field,omitempty"`
+	Comment    string     `db:"comment" json:"comment"`
+	CreatedBy  *uuid.UUID `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+}
+
+// IsPOISection reports whether name is one of the POI editing sections
+func IsPOISection(name string) bool {
+	return slices.Contains(poiSections, name)
+}
+
+// ReplaceRejectionFeedback stores the comments of a new rejection in place of
+// any left from an earlier one
+func (r *POIRepository) ReplaceRejectionFeedback(ctx context.Context, poiID uuid.UUID, items []RejectionFeedback, createdBy *uuid.UUID) error {
+	conn := r.db.Conn(ctx)
+	if _, err := conn.ExecContext(ctx, `DELETE FROM poi_rejection_feedback WHERE poi_id = $1`, poiID); err != nil {
+		return fmt.Errorf("clear rejection feedback: %w", err)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	sections := make([]string, len(items))
+	fields := make([]*string, len(items))
+	comments := make([]string, len(items))
+	for i, item := range items {
+		sections[i], fields[i], comments[i] = item.Section, item.Field, item.Comment
+	}
+	_, err := conn.ExecContext(ctx, `
+		INSERT INTO poi_rejection_feedback (poi_id, section, field, comment, created_by)
+		SELECT $1, f.section, f.field, f.comment, $5::uuid
+		FROM unnest($2::text[], $3::text[], $4::text[]) AS f(section, field, comment)
+	`, poiID, pq.Array(sections), pq.Array(fields), pq.Array(comments), createdBy)
+	if err != nil {
+		return fmt.Errorf("insert rejection feedback: %w", err)
+	}
+	return nil
+}
+
+// GetRejectionFeedback lists the open comments on a POI in wizard section order
+func (r *POIRepository) GetRejectionFeedback(ctx context.Context, poiID uuid.UUID) ([]RejectionFeedback, error) {
+	var items []RejectionFeedback
+	err := r.db.Conn(ctx).SelectContext(ctx, &items, `
+		SELECT feedback_id, section, field, comment, created_by, created_at
+		FROM poi_rejection_feedback
+		WHERE poi_id = $1
+		ORDER BY array_position($2::text[], section::text), created_at
+	`, poiID, pq.Array(poiSections))
+	if err != nil {
+		return nil, fmt.Errorf("get rejection feedback: %w", err)
+	}
+	return items, nil
+}
+
+// clearResolvedFeedback removes the comments on sections edited after the
+// comment was made, run when the POI is submitted again
+func (r *POIRepository) clearResolvedFeedback(ctx context.Context, poiID uuid.UUID) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		DELETE FROM poi_rejection_feedback f
+		USING poi_section_edits e
+		WHERE f.poi_id = $1 AND e.poi_id = f.poi_id AND e.section = f.section
+		  AND e.updated_at > f.created_at
+	`, poiID)
+	if err != nil {
+		return fmt.Errorf("clear resolved feedback: %w", err)
+	}
+	return nil
+}
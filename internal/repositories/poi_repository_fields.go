@@ -0,0 +1,419 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// POIReadOptions shape a POI read
+type POIReadOptions struct {
+	Fields []string // Sparse fieldset; empty selects everything
+	Locale string   // Overlay translated content for this locale; empty for the default locale
+}
+
+// translatedColumns are overlaid from poi_translations when a locale is requested
+var translatedColumns = map[string]string{
+	"name":        "COALESCE(t.name, p.name) as name",
+	"description": "COALESCE(t.description, p.description) as description",
+}
+
+// poiColumn maps a POI response field to the SQL that produces it.
+// Queries alias points_of_interest as p.
+type poiColumn struct {
+	field string // JSON field name exposed by the API
+	expr  string // select expression, aliased to the POI db tag when needed
+	join  string // join required by expr ("", "gallery", "founder", "address", "translation")
+	list  bool   // available in list queries (Search); detail queries select every column
+}
+
+// poiColumns is the whitelist used to build sparse POI selects (?fields=).
+// Order matches the historical column order of the list and detail queries.
+var poiColumns = []poiColumn{
+	{"poi_id", "p.poi_id", "", true},
+	{"name", "p.name", "", true},
+	{"category_id", "p.category_id", "", true},
+	{"website", "p.website", "", true},
+	{"brand", "p.brand", "", true},
+	{"description", "p.description", "", true},
+	{"address_id", "p.address_id", "", true},
+	{"parking_info", "p.parking_info", "", true},
+	{"amenities", poiAmenitiesExpr, "", true},
+	{"has_wifi", "p.has_wifi", "", true},
+	{"outdoor_seating", "p.outdoor_seating", "", true},
+	{"is_wheelchair_accessible", "p.is_wheelchair_accessible", "", true},
+	{"has_delivery", "p.has_delivery", "", true},
+	{"cuisine", "p.cuisine", "", true},
+	{"price_range", "p.price_range", "", true},
+	{"price_currency", "p.price_currency", "", true},
+	{"avg_spend_min", "p.avg_spend_min", "", true},
+	{"avg_spend_max", "p.avg_spend_max", "", true},
+	{"food_options", "p.food_options", "", true},
+	{"payment_options", "p.payment_options", "", true},
+	{"kids_friendly", "p.kids_friendly", "", true},
+	{"smoker_friendly", "p.smoker_friendly", "", true},
+	{"pet_friendly", "p.pet_friendly", "", true},
+	{"status", "p.status", "", true},
+	{"is_verified", "p.is_verified", "", true},
+	{"verified_at", "p.verified_at", "", true},
+	{"created_at", "p.created_at", "", true},
+	{"updated_at", "p.updated_at", "", true},
+	{"created_by", "p.created_by", "", false},
+	{"submitted_at", "p.submitted_at", "", false},
+	{"floor_unit", "p.floor_unit", "", false},
+	{"public_transport", "p.public_transport", "", false},
+	{"cover_image_url", "p.cover_image_url", "", true},
+	{"gallery_image_urls", "p.gallery_image_urls", "", true},
+	{"gallery_images", "gallery.gallery_images", "gallery", true},
+	{"wifi_quality", "p.wifi_quality", "", true},
+	{"power_outlets", "p.power_outlets", "", true},
+	{"seating_options", "p.seating_options", "", true},
+	{"noise_level", "p.noise_level", "", true},
+	{"has_ac", "p.has_ac", "", true},
+	{"vibes", "p.vibes", "", true},
+	{"crowd_type", "p.crowd_type", "", true},
+	{"lighting", "p.lighting", "", false},
+	{"music_type", "p.music_type", "", false},
+	{"cleanliness", "p.cleanliness", "", false},
+	{"dietary_options", "p.dietary_options", "", true},
+	{"featured_items", "p.featured_menu_items", "", false},
+	{"specials", "p.specials", "", false},
+	{"open_hours", "p.open_hours", "", false},
+	{"reservation_required", "p.reservation_required", "", false},
+	{"reservation_platform", "p.reservation_platform", "", false},
+	{"wait_time_estimate", "p.wait_time_estimate", "", false},
+	{"happy_hour_info", "p.happy_hour_info", "", false},
+	{"loyalty_program", "p.loyalty_program", "", false},
+	{"phone", "p.phone", "", false},
+	{"email", "p.email", "", false},
+	{"social_links", "p.social_media_links", "", false},
+	{"category_ids", "p.category_ids", "", false},
+	{"parking_options", "p.parking_options", "", true},
+	{"pet_policy", "p.pet_policy", "", false},
+	{"founding_user_id", "p.founding_user_id", "", true},
+	{"wifi_speed_mbps", "p.wifi_speed_mbps", "", true},
+	{"wifi_verified_at", "p.wifi_verified_at", "", true},
+	{"ergonomic_seating", "p.ergonomic_seating", "", true},
+	{"power_sockets_reach", "p.power_sockets_reach", "", true},
+	{"latitude", "ST_Y(p.location::geometry) as latitude", "", true},
+	{"longitude", "ST_X(p.location::geometry) as longitude", "", true},
+	{"category_names", `(
+		           SELECT array_agg(name_key)
+		           FROM categories
+		           WHERE category_id = p.category_id
+		              OR category_id::text = ANY(p.category_ids)
+		       ) as category_names`, "", false},
+	{"address", "a.street_address as address", "address", false},
+	{"founding_user_username", "u.name as founding_user_username", "founder", true},
+	{"rating_avg", "p.rating_avg", "", true},
+	{"reviews_count", "p.reviews_count", "", true},
+	{"wifi_rating_avg", "p.wifi_rating_avg", "", true},
+	{"comfort_rating_avg", "p.comfort_rating_avg", "", false},
+	{"food_rating_avg", "p.food_rating_avg", "", false},
+	{"value_rating_avg", "p.value_rating_avg", "", false},
+	{"noise_accuracy_rating_avg", "p.noise_accuracy_rating_avg", "", false},
+	{"scheduled_specials", scheduledSpecialsSubquery("p.poi_id") + " as scheduled_specials", "", false},
+	{"source_attribution", "p.source_attribution", "", false},
+	{"step_free_entrance", "p.step_free_entrance", "", true},
+	{"accessible_restroom", "p.accessible_restroom", "", true},
+	{"table_heights", "p.table_heights", "", false},
+	{"braille_menu", "p.braille_menu", "", false},
+	{"accessible_parking", "p.accessible_parking", "", true},
+	{"accessibility_notes", "p.accessibility_notes", "", false},
+}
+
+// poiSummaryFields are the columns of compact POI lists: a user's POIs, saved
+// POIs and the review queue. Searches select every list column and the detail
+// view every column.
+var poiSummaryFields = []string{
+	"poi_id", "name", "category_id", "description", "status", "created_by",
+	"cover_image_url", "gallery_images", "has_wifi", "outdoor_seating", "price_range",
+	"rating_avg", "submitted_at", "created_at", "updated_at",
+}
+
+// poiSummarySelect builds the select list and joins of a compact POI list
+func poiSummarySelect() (string, string) {
+	return poiSelect(POIReadOptions{Fields: poiSummaryFields}, true)
+}
+
+// checkPOIColumns makes sure every column a POI query can select is scanned
+// into a POI field, so a renamed column or tag fails the tests instead of the
+// first query that selects it
+func checkPOIColumns() error {
+	tags := map[string]bool{}
+	t := reflect.TypeOf(POI{})
+	for i := 0; i < t.NumField(); i++ {
+		if tag := t.Field(i).Tag.Get("db"); tag != "" && tag != "-" {
+			tags[tag] = true
+		}
+	}
+
+	names := map[string]bool{}
+	for _, col := range poiColumns {
+		names[col.field] = true
+		if name := selectedName(col.expr); !tags[name] {
+			return fmt.Errorf("poi column %q selects %q, which no POI field scans", col.field, name)
+		}
+	}
+	for field, expr := range translatedColumns {
+		if name := selectedName(expr); !tags[name] {
+			return fmt.Errorf("translated poi column %q selects %q, which no POI field scans", field, name)
+		}
+	}
+	for _, field := range poiSummaryFields {
+		if !names[field] {
+			return fmt.Errorf("poi summary field %q is not a poi column", field)
+		}
+	}
+	return nil
+}
+
+// selectedName is the output column name of a select expression: its alias,
+// or the column name of a plain column reference
+func selectedName(expr string) string {
+	if i := strings.LastIndex(expr, " as "); i >= 0 {
+		return strings.TrimSpace(expr[i+len(" as "):])
+	}
+	return expr[strings.LastIndex(expr, ".")+1:]
+}
+
+// ValidatePOIFields checks requested sparse fields against the whitelist.
+// List queries expose a smaller set than the detail query.
+func ValidatePOIFields(fields []string, detail bool) error {
+	var unknown []string
+	for _, f := range fields {
+		if !poiFieldAvailable(f, detail) {
+			unknown = append(unknown, f)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown fields: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+func poiFieldAvailable(field string, detail bool) bool {
+	for _, col := range poiColumns {
+		if col.field == field {
+			return detail || col.list
+		}
+	}
+	return false
+}
+
+// poiSelect builds the select list and joins for the requested fields.
+// An empty field list selects every column available to the query type.
+// poi_id is always selected. With a locale, translatable columns fall back
+// to the default-locale content when no translation exists.
+func poiSelect(opts POIReadOptions, detail bool) (string, string) {
+	fields := opts.Fields
+	want := make(map[string]bool, len(fields)+1)
+	for _, f := range fields {
+		want[f] = true
+	}
+	want["poi_id"] = true
+
+	exprs := make([]string, 0, len(poiColumns))
+	joins := map[string]bool{}
+	for _, col := range poiColumns {
+		if !detail && !col.list {
+			continue
+		}
+		if len(fields) > 0 && !want[col.field] {
+			continue
+		}
+		if expr, ok := translatedColumns[col.field]; ok && opts.Locale != "" {
+			exprs = append(exprs, expr)
+			joins["translation"] = true
+			continue
+		}
+		exprs = append(exprs, col.expr)
+		if col.join != "" {
+			joins[col.join] = true
+		}
+	}
+	if joins["translation"] {
+		exprs = append(exprs, "t.locale as content_locale")
+	}
+
+	var joinSQL strings.Builder
+	if joins["translation"] {
+		// Locales are validated against the configured list before reaching the repository
+		joinSQL.WriteString("\n\t\tLEFT JOIN poi_translations t ON t.poi_id = p.poi_id AND t.locale = " + pq.QuoteLiteral(opts.Locale))
+	}
+	if joins["address"] {
+		joinSQL.WriteString("\n\t\tLEFT JOIN addresses a ON p.address_id = a.address_id")
+	}
+	if joins["founder"] {
+		joinSQL.WriteString("\n\t\tLEFT JOIN users u ON COALESCE(p.founding_user_id, p.created_by) = u.user_id")
+	}
+	if joins["gallery"] {
+		// The detail view returns the full gallery, lists only a preview
+		limit := galleryPreviewLimit
+		if detail {
+			limit = 0
+		}
+		joinSQL.WriteString("\n\t\t" + galleryJoin("p.poi_id", limit))
+	}
+
+	return strings.Join(exprs, ",\n\t\t       "), joinSQL.String()
+}
+
+// poiWrite is a points_of_interest column written from a CreatePOIInput
+type poiWrite struct {
+	column string
+	expr   string        // value expression with ? for each value; "" is a single ?
+	values []interface{} // bound in order to the ? markers
+	keep   bool          // UpdateFull keeps the current value when the value is NULL
+	insert string        // value expression used by Create instead, e.g. with a default
+}
+
+// poiContentWrites lists every content column Create and UpdateFull write, so
+// the two statements cannot drift apart. Status, ownership and the address
+// are handled by the statements themselves.
+func poiContentWrites(input CreatePOIInput) ([]poiWrite, error) {
+	openHours, err := jsonbValue(input.OpenHours)
+	if err != nil {
+		return nil, fmt.Errorf("marshal open_hours: %w", err)
+	}
+	socialLinks, err := jsonbValue(input.SocialLinks)
+	if err != nil {
+		return nil, fmt.Errorf("marshal social_links: %w", err)
+	}
+	v := func(column string, value interface{}) poiWrite {
+		return poiWrite{column: column, values: []interface{}{value}}
+	}
+	keep := func(column string, value interface{}) poiWrite {
+		return poiWrite{column: column, values: []interface{}{value}, keep: true}
+	}
+
+	currency := keep("price_currency", input.PriceCurrency)
+	currency.insert = "COALESCE(?, 'IDR')"
+
+	return []poiWrite{
+		// Profile & Visuals
+		v("name", input.Name),
+		v("brand", input.BrandName),
+		v("description", input.Description),
+		v("cover_image_url", input.CoverImageURL),
+		v("gallery_image_urls", pq.StringArray(input.GalleryImageURLs)),
+		keep("category_ids", nullableStrings(input.CategoryIDs)),
+		// Location
+		{column: "location", expr: "ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography", values: []interface{}{input.Longitude, input.Latitude}},
+		v("floor_unit", input.FloorUnit),
+		v("public_transport", input.PublicTransport),
+		v("parking_options", pq.StringArray(input.ParkingOptions)),
+		v("is_wheelchair_accessible", input.WheelchairAccessible),
+		// Work & Prod; has_wifi and outdoor_seating are derived for the legacy filters
+		v("has_wifi", input.WifiQuality != nil && *input.WifiQuality != "" && *input.WifiQuality != "none"),
+		v("outdoor_seating", contains(input.SeatingOptions, "outdoor")),
+		v("wifi_quality", input.WifiQuality),
+		v("power_outlets", input.PowerOutlets),
+		v("seating_options", pq.StringArray(input.SeatingOptions)),
+		v("noise_level", input.NoiseLevel),
+		v("has_ac", input.HasAC),
+		v("wifi_speed_mbps", input.WifiSpeedMbps),
+		v("ergonomic_seating", input.ErgonomicSeating),
+		v("power_sockets_reach", input.PowerSocketsReach),
+		// Atmosphere
+		v("vibes", pq.StringArray(input.Vibes)),
+		v("crowd_type", pq.StringArray(input.CrowdType)),
+		v("lighting", input.Lighting),
+		v("music_type", input.MusicType),
+		v("cleanliness", input.Cleanliness),
+		// Food & Drink; dietary options are kept in both columns
+		v("cuisine", input.Cuisine),
+		v("price_range", input.PriceRange),
+		currency,
+		keep("avg_spend_min", input.AvgSpendMin),
+		keep("avg_spend_max", input.AvgSpendMax),
+		v("food_options", pq.StringArray(input.DietaryOptions)),
+		v("dietary_options", pq.StringArray(input.DietaryOptions)),
+		v("featured_menu_items", pq.StringArray(input.FeaturedItems)),
+		v("specials", pq.StringArray(input.Specials)),
+		// Operations
+		v("open_hours", openHours),
+		v("reservation_required", input.ReservationRequired),
+		v("reservation_platform", input.ReservationPlatform),
+		v("payment_options", pq.StringArray(input.PaymentOptions)),
+		v("wait_time_estimate", input.WaitTimeEstimate),
+		// Social & Lifestyle
+		v("kids_friendly", input.KidsFriendly),
+		v("pet_friendly", pq.StringArray(input.PetFriendly)),
+		v("pet_policy", input.PetPolicy),
+		v("smoker_friendly", input.SmokerFriendly),
+		v("happy_hour_info", input.HappyHourInfo),
+		v("loyalty_program", input.LoyaltyProgram),
+		// Contact
+		v("website", input.Website),
+		v("phone", input.Phone),
+		v("email", input.Email),
+		v("social_media_links", socialLinks),
+		// Accessibility, nil when unknown
+		keep("step_free_entrance", input.StepFreeEntrance),
+		keep("accessible_restroom", input.AccessibleRestroom),
+		keep("table_heights", nullableStrings(input.TableHeights)),
+		keep("braille_menu", input.BrailleMenu),
+		keep("accessible_parking", input.AccessibleParking),
+		keep("accessibility_notes", input.AccessibilityNotes),
+	}, nil
+}
+
+// bindPOIWrites numbers the ? markers of each write from $start, returning
+// the columns, their value expressions and the arguments. For an update the
+// columns that keep their value on NULL are wrapped in COALESCE.
+func bindPOIWrites(writes []poiWrite, start int, update bool) (columns, exprs []string, args []interface{}) {
+	n := start
+	for _, w := range writes {
+		expr := w.expr
+		if !update && w.insert != "" {
+			expr = w.insert
+		}
+		if expr == "" {
+			expr = "?"
+		}
+		var b strings.Builder
+		for _, part := range strings.SplitAfter(expr, "?") {
+			if strings.HasSuffix(part, "?") {
+				b.WriteString(strings.TrimSuffix(part, "?") + "$" + strconv.Itoa(n))
+				n++
+				continue
+			}
+			b.WriteString(part)
+		}
+		expr = b.String()
+		if update && w.keep {
+			expr = "COALESCE(" + expr + ", " + w.column + ")"
+		}
+		columns = append(columns, w.column)
+		exprs = append(exprs, expr)
+		args = append(args, w.values...)
+	}
+	return columns, exprs, args
+}
+
+// jsonbValue encodes a map for a JSONB column, nil staying NULL
+func jsonbValue(m map[string]interface{}) (interface{}, error) {
+	if m == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// nullableStrings binds an empty list as NULL
+func nullableStrings(values []string) interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	return pq.StringArray(values)
+}
@@ -0,0 +1,9 @@
+package repositories
+
+import "testing"
+
+func TestPOIColumnsScanIntoPOI(t *testing.T) {
+	if err := checkPOIColumns(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Outcomes of ImportPOI
+const (
+	ImportCreated   = "created"   // Inserted as a new draft
+	ImportExists    = "exists"    // Already imported from the same source record
+	ImportDuplicate = "duplicate" // A similarly named POI already exists nearby
+)
+
+// importDuplicateSimilarity is the name trigram similarity above which a
+// nearby POI is considered the same place
+const importDuplicateSimilarity = 0.5
+
+// ImportedPOI is a POI mapped from an open data source
+type ImportedPOI struct {
+	Source      string // e.g. "osm"
+	SourceID    string // Upstream record ID, e.g. "node/123"
+	Attribution string // License attribution shown with the POI
+	CategoryKey string // categories.name_key, e.g. "category.cafe"
+
+	Name                 string
+	Brand                *string
+	Latitude             float64
+	Longitude            float64
+	Street               *string
+	City                 *string
+	PostalCode           *string
+	HasWifi              *bool
+	OutdoorSeating       *bool
+	WheelchairAccessible *bool
+	Cuisine              *string
+	Website              *string
+	Phone                *string
+	Email                *string
+}
+
+// ImportPOI inserts an imported POI as an unverified draft without an owner.
+// Records already imported from the same source, and POIs matching a similarly
+// named POI within dedupeRadius meters, are skipped.
+func (r *POIRepository) ImportPOI(ctx context.Context, in ImportedPOI, dedupeRadius float64) (string, *uuid.UUID, error) {
+	outcome := ImportCreated
+	var poiID uuid.UUID
+
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		conn := r.db.Conn(ctx)
+
+		err := conn.QueryRowContext(ctx, `
+			SELECT poi_id FROM points_of_interest WHERE source = $1 AND source_id = $2
+		`, in.Source, in.SourceID).Scan(&poiID)
+		if err == nil {
+			outcome = ImportExists
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("find imported poi: %w", err)
+		}
+
+		err = conn.QueryRowContext(ctx, `
+			SELECT poi_id FROM points_of_interest
+			WHERE status <> 'archived'
+			  AND ST_DWithin(location, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography, $3)
+			  AND similarity(lower(name), lower($4)) >= $5
+			ORDER BY similarity(lower(name), lower($4)) DESC
+			LIMIT 1
+		`, in.Longitude, in.Latitude, dedupeRadius, in.Name, importDuplicateSimilarity).Scan(&poiID)
+		if err == nil {
+			outcome = ImportDuplicate
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("find duplicate poi: %w", err)
+		}
+
+		var addressID *uuid.UUID
+		if in.Street != nil || in.City != nil || in.PostalCode != nil {
+			var id uuid.UUID
+			err := conn.QueryRowContext(ctx, `
+				INSERT INTO addresses (street_address, kabupaten, postal_code)
+				VALUES ($1, $2, $3)
+				RETURNING address_id
+			`, in.Street, in.City, in.PostalCode).Scan(&id)
+			if err != nil {
+				return fmt.Errorf("create imported address: %w", err)
+			}
+			addressID = &id
+		}
+
+		err = conn.QueryRowContext(ctx, `
+			INSERT INTO points_of_interest (
+				name, brand, location, address_id,
+				category_id, category_ids,
+				has_wifi, outdoor_seating, is_wheelchair_accessible,
+				cuisine, website, phone, email,
+				status, is_verified, source, source_id, source_attribution
+			)
+			SELECT $1, $2, ST_SetSRID(ST_MakePoint($3, $4), 4326)::geography, $5,
+			       c.category_id, CASE WHEN c.category_id IS NULL THEN '{}'::text[] ELSE ARRAY[c.category_id::text] END,
+			       COALESCE($6, false), COALESCE($7, false), $8,
+			       $9, $10, $11, $12,
+			       'draft', false, $13, $14, $15
+			FROM (SELECT NULL) AS one
+			LEFT JOIN LATERAL (SELECT category_id FROM categories WHERE name_key = $16 LIMIT 1) c ON TRUE
+			RETURNING poi_id
+		`, in.Name, in.Brand, in.Longitude, in.Latitude, addressID,
+			in.HasWifi, in.OutdoorSeating, in.WheelchairAccessible,
+			in.Cuisine, in.Website, in.Phone, in.Email,
+			in.Source, in.SourceID, in.Attribution, in.CategoryKey).Scan(&poiID)
+		if err != nil {
+			return fmt.Errorf("insert imported poi: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return outcome, &poiID, nil
+}
@@ -0,0 +1,192 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotPendingReview    = errors.New("poi is not pending review")
+	ErrReviewerAssigned    = errors.New("poi is already assigned to another reviewer")
+	ErrAssigneeNotReviewer = errors.New("assignee cannot review pois")
+)
+
+// ReviewSLAHours is how long a submission may wait for a decision before it
+// is overdue
+const ReviewSLAHours = 72
+
+// pendingSinceExpr is when a pending POI entered the queue; POIs submitted
+// before submitted_at was recorded count from their creation
+const pendingSinceExpr = "COALESCE(p.submitted_at, p.created_at)"
+
+// recordReviewDecision logs how long a pending POI waited before the decision.
+// It must run before the status update, while the POI is still pending.
+func (r *POIRepository) recordReviewDecision(ctx context.Context, poiID uuid.UUID, decision string) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO poi_review_decisions (poi_id, decision, submitted_at)
+		SELECT p.poi_id, $2, %s
+		FROM points_of_interest p
+		WHERE p.poi_id = $1 AND p.status = 'pending'
+	`, pendingSinceExpr), poiID, decision)
+	if err != nil {
+		return fmt.Errorf("record review decision: %w", err)
+	}
+	return nil
+}
+
+// GetQueueMetrics reports review times of the decisions made over the last
+// days and the age distribution of the pending queue
+func (r *POIRepository) GetQueueMetrics(ctx context.Context, days int) (*models.QueueMetrics, error) {
+	since := time.Now().AddDate(0, 0, -days)
+	metrics := &models.QueueMetrics{Days: days, SLAHours: ReviewSLAHours}
+	conn := r.db.ReadConn(ctx)
+
+	err := conn.GetContext(ctx, metrics, `
+		SELECT COUNT(*) AS decided,
+		       COUNT(*) FILTER (WHERE decided_at - submitted_at <= make_interval(hours => $2)) AS decided_within_sla,
+		       percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM decided_at - submitted_at)) / 3600 AS median_review_hours,
+		       percentile_cont(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM decided_at - submitted_at)) / 3600 AS p90_review_hours,
+		       percentile_cont(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM decided_at - submitted_at)) / 3600 AS p95_review_hours
+		FROM poi_review_decisions
+		WHERE decided_at >= $1
+	`, since, ReviewSLAHours)
+	if err != nil {
+		return nil, fmt.Errorf("summarize review times: %w", err)
+	}
+
+	err = conn.GetContext(ctx, metrics, fmt.Sprintf(`
+		SELECT COUNT(*) AS pending,
+		       COUNT(*) FILTER (WHERE %[1]s < NOW() - make_interval(hours => $1)) AS overdue,
+		       MIN(%[1]s) AS oldest_submitted_at
+		FROM points_of_interest p
+		WHERE p.status = 'pending'
+	`, pendingSinceExpr), ReviewSLAHours)
+	if err != nil {
+		return nil, fmt.Errorf("summarize pending queue: %w", err)
+	}
+
+	metrics.QueueAge = []models.QueueAge{}
+	err = conn.SelectContext(ctx, &metrics.QueueAge, fmt.Sprintf(`
+		SELECT b.label, COUNT(p.poi_id)::int AS count
+		FROM (VALUES (1, '0-24h', 0, 24), (2, '24-48h', 24, 48), (3, '48-72h', 48, 72),
+		             (4, '72h-7d', 72, 168), (5, '7d+', 168, NULL)) AS b(ord, label, min_hours, max_hours)
+		LEFT JOIN points_of_interest p ON p.status = 'pending'
+		     AND %[1]s <= NOW() - make_interval(hours => b.min_hours)
+		     AND (b.max_hours IS NULL OR %[1]s > NOW() - make_interval(hours => b.max_hours))
+		GROUP BY b.ord, b.label
+		ORDER BY b.ord
+	`, pendingSinceExpr))
+	if err != nil {
+		return nil, fmt.Errorf("summarize queue age: %w", err)
+	}
+	return metrics, nil
+}
+
+// ReviewAssignment is the reviewer working on a pending POI until ExpiresAt
+type ReviewAssignment struct {
+	PoiID      uuid.UUID `db:"poi_id" json:"poi_id"`
+	AssignedTo uuid.UUID `db:"assigned_to" json:"assigned_to"`
+	ExpiresAt  time.Time `db:"assignment_expires_at" json:"expires_at"`
+}
+
+// AssignReviewer assigns a pending POI to a reviewer for timeout, renewing the
+// assignment when they already hold it. An active assignment to someone else
+// is only replaced with force.
+func (r *POIRepository) AssignReviewer(ctx context.Context, poiID, assignee uuid.UUID, timeout time.Duration, force bool) (*ReviewAssignment, error) {
+	conn := r.db.Conn(ctx)
+
+	var canReview bool
+	err := conn.GetContext(ctx, &canReview, `
+		SELECT EXISTS (
+			SELECT 1 FROM users u
+			JOIN role_permissions rp ON rp.role = u.role
+			WHERE u.user_id = $1 AND rp.permission = 'poi:approve'
+		)
+	`, assignee)
+	if err != nil {
+		return nil, fmt.Errorf("check reviewer: %w", err)
+	}
+	if !canReview {
+		return nil, ErrAssigneeNotReviewer
+	}
+
+	var assignment ReviewAssignment
+	err = conn.GetContext(ctx, &assignment, `
+		UPDATE points_of_interest
+		SET assigned_to = $2, assignment_expires_at = NOW() + make_interval(secs => $3)
+		WHERE poi_id = $1 AND status = 'pending'
+		  AND ($4 OR assigned_to IS NULL OR assigned_to = $2 OR assignment_expires_at <= NOW())
+		RETURNING poi_id, assigned_to, assignment_expires_at
+	`, poiID, assignee, timeout.Seconds(), force)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, r.assignmentConflict(ctx, poiID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("assign reviewer: %w", err)
+	}
+	return &assignment, nil
+}
+
+// assignmentConflict explains why an assignment matched no row
+func (r *POIRepository) assignmentConflict(ctx context.Context, poiID uuid.UUID) error {
+	var status string
+	err := r.db.Conn(ctx).GetContext(ctx, &status, `SELECT status FROM points_of_interest WHERE poi_id = $1`, poiID)
+	if err != nil {
+		return fmt.Errorf("get poi status: %w", err)
+	}
+	if status != "pending" {
+		return ErrNotPendingReview
+	}
+	return ErrReviewerAssigned
+}
+
+// ReleaseReviewer ends the assignment of a POI held by userID, or by anyone
+// with force. Returns ErrReviewerAssigned when someone else holds it.
+func (r *POIRepository) ReleaseReviewer(ctx context.Context, poiID, userID uuid.UUID, force bool) error {
+	res, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE points_of_interest
+		SET assigned_to = NULL, assignment_expires_at = NULL
+		WHERE poi_id = $1 AND assigned_to IS NOT NULL
+		  AND ($3 OR assigned_to = $2 OR assignment_expires_at <= NOW())
+	`, poiID, userID, force)
+	if err != nil {
+		return fmt.Errorf("release reviewer: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		var assigned bool
+		err := r.db.Conn(ctx).GetContext(ctx, &assigned, `
+			SELECT assigned_to IS NOT NULL FROM points_of_interest WHERE poi_id = $1
+		`, poiID)
+		if err != nil {
+			return fmt.Errorf("get poi assignment: %w", err)
+		}
+		if assigned {
+			return ErrReviewerAssigned
+		}
+	}
+	return nil
+}
+
+// ActiveAssignee returns the reviewer a POI is assigned to, or nil when it is
+// unassigned or the assignment has lapsed
+func (r *POIRepository) ActiveAssignee(ctx context.Context, poiID uuid.UUID) (*uuid.UUID, error) {
+	var assignee *uuid.UUID
+	err := r.db.Conn(ctx).GetContext(ctx, &assignee, `
+		SELECT assigned_to FROM points_of_interest
+		WHERE poi_id = $1 AND assignment_expires_at > NOW()
+	`, poiID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get poi assignee: %w", err)
+	}
+	return assignee, nil
+}
@@ -2,12 +2,324 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"maukemana-backend/internal/models"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
+// galleryPreviewLimit caps the photos embedded in list results (Search, GetNearby).
+// The detail view (GetByID) still returns the full gallery.
+const galleryPreviewLimit = 12
+
+// galleryJoin aggregates the photos of each POI through a LATERAL join, keeping
+// at most limit photos (0 = all). The inner LIMIT lets Postgres walk
+// idx_photos_gallery_order instead of sorting every photo of the POI, and the
+// aggregate runs once per returned row.
+func galleryJoin(poiIDRef string, limit int) string {
+	limitClause := ""
+	if limit > 0 {
+		limitClause = fmt.Sprintf("\n\t\t\t\tLIMIT %d", limit)
+	}
+	return fmt.Sprintf(`LEFT JOIN LATERAL (
+			SELECT COALESCE(json_agg(
+				json_build_object(
+					'photo_id', ph.photo_id,
+					'poi_id', ph.poi_id,
+					'url', ph.url,
+					'is_hero', ph.is_hero,
+					'score', ph.score,
+					'upvotes', ph.upvotes,
+					'downvotes', ph.downvotes,
+					'is_pinned', ph.is_pinned,
+					'sort_index', ph.sort_index,
+					'is_admin_official', ph.is_admin_official,
+					'created_at', ph.created_at
+				) ORDER BY ph.sort_index ASC NULLS LAST, ph.is_pinned DESC, ph.is_hero DESC, ph.score DESC
+			), '[]'::json) as gallery_images
+			FROM (
+				SELECT * FROM photos
+				WHERE photos.poi_id = %s
+				ORDER BY sort_index ASC NULLS LAST, is_pinned DESC, is_hero DESC, score DESC%s
+			) ph
+		) gallery ON TRUE`, poiIDRef, limitClause)
+}
+
+// scheduledSpecialsSubquery aggregates the specials of a POI that have not expired,
+// flagging the ones active right now. Expired specials never reach read responses.
+func scheduledSpecialsSubquery(poiIDRef string) string {
+	return fmt.Sprintf(`(
+			SELECT COALESCE(json_agg(
+				json_build_object(
+					'special_id', s.special_id,
+					'poi_id', s.poi_id,
+					'title', s.title,
+					'description', s.description,
+					'starts_at', s.starts_at,
+					'ends_at', s.ends_at,
+					'recurrence', s.recurrence,
+					'recurrence_days', s.recurrence_days,
+					'daily_start_time', s.daily_start_time,
+					'daily_end_time', s.daily_end_time,
+					'timezone', s.timezone,
+					'created_at', s.created_at,
+					'updated_at', s.updated_at,
+					'is_active', poi_special_is_active(s, NOW())
+				) ORDER BY s.starts_at
+			), '[]'::json)
+			FROM poi_specials s
+			WHERE s.poi_id = %s AND (s.ends_at IS NULL OR s.ends_at > NOW())
+		)`, poiIDRef)
+}
+
+// recommendedScore builds the ranking expression for sort_by=recommended.
+// Components are normalised to 0..1 and combined with the configured weights:
+//   - rating: Bayesian average (v*R + m*C) / (v + m), scaled to 0..1
+//   - recency: 0.5 ^ (age_days / half_life)
+//   - distance: 1 / (1 + km / scale), only when $1/$2 hold the user's lng/lat
+//   - verified: 1 for verified POIs
+//
+// Weights come from config rather than user input, so they are inlined.
+func (r *POIRepository) recommendedScore(withDistance bool) string {
+	w := r.ranking
+	m := w.PriorWeight
+
+	expr := fmt.Sprintf(
+		"(%f * COALESCE((p.reviews_count * p.rating_avg + %f * %f) / NULLIF(p.reviews_count + %f, 0), 0) / 5.0"+
+			" + %f * (CASE WHEN p.is_verified THEN 1 ELSE 0 END)",
+		w.Rating, m, w.PriorMean, m, w.Verified)
+
+	if w.RecencyHalfLife > 0 {
+		expr += fmt.Sprintf(
+			" + %f * POWER(0.5, EXTRACT(EPOCH FROM (NOW() - p.created_at)) / 86400.0 / %f)",
+			w.Recency, w.RecencyHalfLife)
+	}
+
+	if withDistance && w.DistanceScaleKm > 0 {
+		expr += fmt.Sprintf(
+			" + %f * COALESCE(1.0 / (1.0 + ST_Distance(p.location, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography) / 1000.0 / %f), 0)",
+			w.Distance, w.DistanceScaleKm)
+	}
+
+	return expr + ")"
+}
+
+// accessibilityFilters maps boolean accessibility search filters to their columns
+var accessibilityFilters = []struct{ filter, column string }{
+	{"wheelchair_accessible", "is_wheelchair_accessible"},
+	{"step_free_entrance", "step_free_entrance"},
+	{"accessible_restroom", "accessible_restroom"},
+	{"braille_menu", "braille_menu"},
+	{"accessible_parking", "accessible_parking"},
+}
+
+// ParseSearchFilters builds Search filters from GET /api/v1/pois query
+// parameters. Free text (q), sparse fieldsets and locale are left to the
+// caller. status defaults to approved.
+func ParseSearchFilters(query url.Values) (map[string]interface{}, error) {
+	filters := make(map[string]interface{})
+
+	// Category filter
+	if category := query.Get("category_id"); category != "" {
+		if catID, err := uuid.Parse(category); err == nil {
+			filters["category_id"] = catID
+		}
+	}
+
+	// Legacy has_wifi boolean filter
+	if hasWifi := query.Get("has_wifi"); hasWifi == "true" {
+		filters["has_wifi"] = true
+	}
+
+	// Price range filter
+	if priceRange := query.Get("price_range"); priceRange != "" {
+		if pr, err := strconv.Atoi(priceRange); err == nil {
+			filters["price_range"] = pr
+		}
+	}
+
+	// Average spend per person filter, in currency (ISO 4217, default IDR)
+	if maxSpend := query.Get("max_avg_spend"); maxSpend != "" {
+		spend, err := strconv.ParseFloat(maxSpend, 64)
+		if err != nil || spend < 0 {
+			return nil, errors.New("max_avg_spend must be a non-negative number")
+		}
+		currency := strings.ToUpper(query.Get("currency"))
+		if currency == "" {
+			currency = models.DefaultCurrency
+		}
+		if !models.IsCurrencyCode(currency) {
+			return nil, errors.New("currency must be an ISO 4217 currency code")
+		}
+		filters["max_avg_spend"] = spend
+		filters["currency"] = currency
+	}
+
+	// Status filter - defaults to "approved" for public feed
+	status := query.Get("status")
+	if status == "" {
+		status = "approved"
+	}
+	filters["status"] = status
+
+	// WiFi quality filter (string: none|slow|moderate|fast|excellent)
+	if wifiQuality := query.Get("wifi_quality"); wifiQuality != "" {
+		filters["wifi_quality"] = wifiQuality
+	}
+
+	// Noise level filter (string: silent|quiet|moderate|lively|loud)
+	if noiseLevel := query.Get("noise_level"); noiseLevel != "" {
+		filters["noise_level"] = noiseLevel
+	}
+
+	// Power outlets filter (string: none|limited|moderate|plenty)
+	if powerOutlets := query.Get("power_outlets"); powerOutlets != "" {
+		filters["power_outlets"] = powerOutlets
+	}
+
+	// Cuisine filter (string)
+	if cuisine := query.Get("cuisine"); cuisine != "" {
+		filters["cuisine"] = cuisine
+	}
+
+	// Has AC filter (boolean)
+	if hasAC := query.Get("has_ac"); hasAC == "true" {
+		filters["has_ac"] = true
+	} else if hasAC == "false" {
+		filters["has_ac"] = false
+	}
+
+	// Vibes filter (comma-separated array)
+	if vibes := query.Get("vibes"); vibes != "" {
+		filters["vibes"] = ParseCommaSeparated(vibes)
+	}
+
+	// Crowd type filter (comma-separated array)
+	if crowdType := query.Get("crowd_type"); crowdType != "" {
+		filters["crowd_type"] = ParseCommaSeparated(crowdType)
+	}
+
+	// Dietary options filter (comma-separated array)
+	if dietaryOptions := query.Get("dietary_options"); dietaryOptions != "" {
+		filters["dietary_options"] = ParseCommaSeparated(dietaryOptions)
+	}
+
+	// Seating options filter (comma-separated array)
+	if seatingOptions := query.Get("seating_options"); seatingOptions != "" {
+		filters["seating_options"] = ParseCommaSeparated(seatingOptions)
+	}
+
+	// Parking options filter (comma-separated array)
+	if parkingOptions := query.Get("parking_options"); parkingOptions != "" {
+		filters["parking_options"] = ParseCommaSeparated(parkingOptions)
+	}
+
+	// Amenities filter (comma-separated keys); amenities_match=any relaxes the
+	// default of requiring every amenity
+	if amenities := query.Get("amenities"); amenities != "" {
+		filters["amenities"] = uniqueStrings(ParseCommaSeparated(amenities))
+		switch match := query.Get("amenities_match"); match {
+		case "", AmenitiesMatchAll, AmenitiesMatchAny:
+			if match == "" {
+				match = AmenitiesMatchAll
+			}
+			filters["amenities_match"] = match
+		default:
+			return nil, errors.New("amenities_match must be all or any")
+		}
+	}
+
+	// Sort by filter (string: recommended|nearest|top_rated)
+	if sortBy := query.Get("sort_by"); sortBy != "" {
+		filters["sort_by"] = sortBy
+	}
+
+	// Lat/Lng parsing (needed for sort_by=nearest OR radius filter)
+	if latStr := query.Get("lat"); latStr != "" {
+		if lat, err := strconv.ParseFloat(latStr, 64); err == nil {
+			filters["lat"] = lat
+		}
+	}
+	if lngStr := query.Get("lng"); lngStr != "" {
+		if lng, err := strconv.ParseFloat(lngStr, 64); err == nil {
+			filters["lng"] = lng
+		}
+	}
+
+	// Radius filter (meters)
+	if radiusStr := query.Get("radius"); radiusStr != "" {
+		if radius, err := strconv.ParseFloat(radiusStr, 64); err == nil {
+			filters["radius"] = radius
+		}
+	}
+
+	// WiFi Speed Min filter
+	if wifiSpeedMinStr := query.Get("wifi_speed_min"); wifiSpeedMinStr != "" {
+		if speed, err := strconv.Atoi(wifiSpeedMinStr); err == nil {
+			filters["wifi_speed_min"] = speed
+		}
+	}
+
+	// Minimum average wifi rating from reviews (1-5)
+	if minWifiRating := query.Get("min_wifi_rating"); minWifiRating != "" {
+		if rating, err := strconv.ParseFloat(minWifiRating, 64); err == nil {
+			filters["min_wifi_rating"] = rating
+		}
+	}
+
+	// Service area filter (slug, e.g. jakarta-selatan)
+	if area := query.Get("area"); area != "" {
+		filters["area"] = area
+	}
+
+	// Accessibility filters (boolean, only "true" narrows; unknown values never match)
+	for _, name := range []string{"wheelchair_accessible", "step_free_entrance", "accessible_restroom", "braille_menu", "accessible_parking"} {
+		if query.Get(name) == "true" {
+			filters[name] = true
+		}
+	}
+
+	// Table heights filter (comma-separated array: low|standard|high|adjustable)
+	if heights := query.Get("table_heights"); heights != "" {
+		filters["table_heights"] = ParseCommaSeparated(heights)
+	}
+
+	// Only verified businesses
+	if query.Get("verified") == "true" {
+		filters["is_verified"] = true
+	}
+
+	// Only POIs with a special running right now
+	if query.Get("has_active_special") == "true" {
+		filters["has_active_special"] = true
+	}
+
+	return filters, nil
+}
+
+// ParseCommaSeparated splits a comma-separated string into a slice of strings
+func ParseCommaSeparated(s string) []string {
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	result := make([]string, 0, len(parts))
+	for _, p := range parts {
+		trimmed := strings.TrimSpace(p)
+		if trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	return result
+}
+
 // Search searches POIs with filters
 func (r *POIRepository) Search(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]POI, error) {
 	var pois []POI
@@ -17,50 +329,25 @@ func (r *POIRepository) Search(ctx context.Context, filters map[string]interface
 	lat, hasLat := filters["lat"].(float64)
 	lng, hasLng := filters["lng"].(float64)
 	needsDistance := sortBy == "nearest" && hasLat && hasLng
+	// Index-ranked text search keeps the index order unless a sort is requested
+	_, hasMatches := filters["match_ids"].([]uuid.UUID)
+	byRelevance := hasMatches && sortBy == ""
+	// recommended ranking uses proximity when coordinates are supplied
+	rankByDistance := (sortBy == "recommended" || sortBy == "") && hasLat && hasLng && !byRelevance
 
-	selectClause := `
-		SELECT p.poi_id, p.name, p.category_id, p.website, p.brand, p.description,
-		       p.address_id, p.parking_info, p.amenities, p.has_wifi, p.outdoor_seating,
-		       p.is_wheelchair_accessible, p.has_delivery, p.cuisine, p.price_range,
-		       p.food_options, p.payment_options, p.kids_friendly, p.smoker_friendly,
-		       p.pet_friendly, p.status, p.cover_image_url, p.gallery_image_urls,
-		       (
-		           SELECT COALESCE(json_agg(
-		               json_build_object(
-		                   'photo_id', ph.photo_id,
-		                   'poi_id', ph.poi_id,
-		                   'url', ph.url,
-		                   'is_hero', ph.is_hero,
-		                   'score', ph.score,
-		                   'upvotes', ph.upvotes,
-		                   'downvotes', ph.downvotes,
-		                   'is_pinned', ph.is_pinned,
-		                   'is_admin_official', ph.is_admin_official,
-		                   'created_at', ph.created_at
-		               ) ORDER BY ph.is_pinned DESC, ph.is_hero DESC, ph.score DESC
-		           ), '[]'::json)
-		           FROM photos ph
-		           WHERE ph.poi_id = p.poi_id
-		       ) as gallery_images,
-		       p.is_verified, p.verified_at, p.created_at, p.updated_at,
-		       p.wifi_quality, p.power_outlets, p.noise_level, p.vibes, p.crowd_type,
-		       p.seating_options, p.parking_options, p.has_ac, p.dietary_options,
-		       p.founding_user_id, p.wifi_speed_mbps, p.wifi_verified_at, p.ergonomic_seating, p.power_sockets_reach,
-		       ST_Y(p.location::geometry) as latitude, ST_X(p.location::geometry) as longitude,
-		       u.name as founding_user_username,
-		       COALESCE(
-		           (SELECT AVG(rating)::float8 FROM reviews r WHERE r.poi_id = p.poi_id),
-		           0
-		       ) as rating_avg,
-		       (SELECT COUNT(*)::int FROM reviews r WHERE r.poi_id = p.poi_id) as reviews_count`
+	// Sparse fieldsets (?fields=) select only the requested columns
+	fields, _ := filters["fields"].([]string)
+	locale, _ := filters["locale"].(string)
+	columns, joins := poiSelect(POIReadOptions{Fields: fields, Locale: locale}, false)
+
+	selectClause := "\n\t\tSELECT " + columns
 
 	if needsDistance {
 		selectClause += ",\n		       ST_Distance(location, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography) as distance_meters"
 	}
 
 	query := selectClause + `
-		FROM points_of_interest p
-		LEFT JOIN users u ON COALESCE(p.founding_user_id, p.created_by) = u.user_id
+		FROM points_of_interest p` + joins + `
 		WHERE 1=1
 	`
 
@@ -68,11 +355,70 @@ func (r *POIRepository) Search(ctx context.Context, filters map[string]interface
 	paramIdx := 1
 
 	// If we need distance, add lat/lng as the first two parameters
-	if needsDistance {
+	if needsDistance || rankByDistance {
 		args = append(args, lng, lat)
 		paramIdx = 3
 	}
 
+	where := searchConditions(filters, paramIdx)
+	query += where.sql
+	args = append(args, where.args...)
+	paramIdx = where.next
+	relevanceParam, textParam := where.relevanceParam, where.textParam
+
+	// Dynamic ordering based on sort_by
+	switch sortBy {
+	case "nearest":
+		if needsDistance {
+			query += " ORDER BY distance_meters ASC"
+		} else {
+			query += " ORDER BY p.created_at DESC" // Fallback if no location provided
+		}
+	case "top_rated":
+		// rating_avg/reviews_count are maintained by trg_refresh_poi_rating_stats
+		query += " ORDER BY p.rating_avg DESC, p.reviews_count DESC, p.created_at DESC"
+	case "":
+		switch {
+		case byRelevance:
+			// Keep the search index ranking
+			query += fmt.Sprintf(" ORDER BY array_position($%d::uuid[], p.poi_id)", relevanceParam)
+		case textParam > 0:
+			// Name matches first, then the usual ranking
+			query += fmt.Sprintf(" ORDER BY (p.name ILIKE $%d) DESC, ", textParam) + r.recommendedScore(rankByDistance) + " DESC, p.created_at DESC, p.poi_id"
+		default:
+			query += " ORDER BY " + r.recommendedScore(rankByDistance) + " DESC, p.created_at DESC, p.poi_id"
+		}
+	default: // "recommended"
+		query += " ORDER BY " + r.recommendedScore(rankByDistance) + " DESC, p.created_at DESC, p.poi_id"
+	}
+
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", paramIdx, paramIdx+1)
+	args = append(args, limit, offset)
+
+	err := r.db.ReadConn(ctx).SelectContext(ctx, &pois, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search pois: %w", err)
+	}
+
+	return pois, nil
+}
+
+// searchWhere is the filter part of a POI search: conditions appended to
+// "WHERE 1=1" and their arguments, numbered from the first free parameter
+type searchWhere struct {
+	sql            string
+	args           []interface{}
+	next           int // Next free parameter number
+	relevanceParam int // Parameter holding the search index ranking, if any
+	textParam      int // Parameter holding the text search pattern, if any
+}
+
+// searchConditions builds the conditions of the Search filters, numbering
+// parameters from paramIdx
+func searchConditions(filters map[string]interface{}, paramIdx int) searchWhere {
+	query := ""
+	args := []interface{}{}
+
 	// Category filter
 	if categoryID, ok := filters["category_id"].(uuid.UUID); ok {
 		query += fmt.Sprintf(" AND category_id = $%d", paramIdx)
@@ -94,6 +440,14 @@ func (r *POIRepository) Search(ctx context.Context, filters map[string]interface
 		paramIdx++
 	}
 
+	// Average spend filter; POIs with only a minimum spend match on that
+	if maxSpend, ok := filters["max_avg_spend"].(float64); ok {
+		currency, _ := filters["currency"].(string)
+		query += fmt.Sprintf(" AND p.price_currency = $%d AND COALESCE(p.avg_spend_max, p.avg_spend_min) <= $%d", paramIdx, paramIdx+1)
+		args = append(args, currency, maxSpend)
+		paramIdx += 2
+	}
+
 	// Status filter
 	if status, ok := filters["status"].(string); ok && status != "" {
 		query += fmt.Sprintf(" AND status = $%d", paramIdx)
@@ -171,6 +525,13 @@ func (r *POIRepository) Search(ctx context.Context, filters map[string]interface
 		paramIdx++
 	}
 
+	// Amenities filter (all or any of the keys)
+	if amenities, ok := filters["amenities"].([]string); ok && len(amenities) > 0 {
+		query += " AND " + amenitiesCondition(fmt.Sprintf("$%d", paramIdx), filters["amenities_match"] == AmenitiesMatchAny)
+		args = append(args, pq.StringArray(amenities))
+		paramIdx++
+	}
+
 	// WiFi speed min filter
 	if wifiSpeedMin, ok := filters["wifi_speed_min"].(int); ok {
 		query += fmt.Sprintf(" AND wifi_speed_mbps >= $%d", paramIdx)
@@ -178,95 +539,94 @@ func (r *POIRepository) Search(ctx context.Context, filters map[string]interface
 		paramIdx++
 	}
 
-	// Radius filter (requires lat/lng)
-	radius, hasRadius := filters["radius"].(float64)
-	if hasRadius && hasLat && hasLng {
-		query += fmt.Sprintf(" AND ST_DWithin(location, ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography, $%d)", paramIdx, paramIdx+1, paramIdx+2)
-		args = append(args, lng, lat, radius)
-		paramIdx += 3
+	// Minimum average wifi rating from reviews (POIs without wifi ratings are excluded)
+	if minWifiRating, ok := filters["min_wifi_rating"].(float64); ok {
+		query += fmt.Sprintf(" AND p.wifi_rating_avg >= $%d", paramIdx)
+		args = append(args, minWifiRating)
+		paramIdx++
 	}
 
-	// Dynamic ordering based on sort_by
-	switch sortBy {
-	case "nearest":
-		if needsDistance {
-			query += " ORDER BY distance_meters ASC"
-		} else {
-			query += " ORDER BY created_at DESC" // Fallback if no location provided
+	// Accessibility filters (POIs with unknown values are excluded)
+	for _, f := range accessibilityFilters {
+		if v, ok := filters[f.filter].(bool); ok && v {
+			query += fmt.Sprintf(" AND p.%s IS TRUE", f.column)
 		}
-	case "top_rated":
-		// TODO: Add rating column when available, for now fallback to created_at
-		query += " ORDER BY created_at DESC"
-	default: // "recommended" or empty
-		query += " ORDER BY created_at DESC"
 	}
 
-	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", paramIdx, paramIdx+1)
-	args = append(args, limit, offset)
+	// Table heights filter (array - match any)
+	if heights, ok := filters["table_heights"].([]string); ok && len(heights) > 0 {
+		query += fmt.Sprintf(" AND p.table_heights && $%d", paramIdx)
+		args = append(args, pq.StringArray(heights))
+		paramIdx++
+	}
 
-	err := r.db.SelectContext(ctx, &pois, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("search pois: %w", err)
+	// Service area filter
+	if area, ok := filters["area"].(string); ok && area != "" {
+		query += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM service_areas sa WHERE sa.slug = $%d AND ST_Within(p.location::geometry, sa.boundary))", paramIdx)
+		args = append(args, area)
+		paramIdx++
 	}
 
-	return pois, nil
+	// Verified business filter
+	if verified, ok := filters["is_verified"].(bool); ok && verified {
+		query += " AND p.is_verified = TRUE"
+	}
+
+	// Active special filter
+	if hasActiveSpecial, ok := filters["has_active_special"].(bool); ok && hasActiveSpecial {
+		query += " AND EXISTS (SELECT 1 FROM poi_specials s WHERE s.poi_id = p.poi_id AND poi_special_is_active(s, NOW()))"
+	}
+
+	// Text search: candidates already ranked by the search index
+	relevanceParam := 0
+	if matchIDs, ok := filters["match_ids"].([]uuid.UUID); ok {
+		query += fmt.Sprintf(" AND p.poi_id = ANY($%d::uuid[])", paramIdx)
+		args = append(args, pq.Array(matchIDs))
+		relevanceParam = paramIdx
+		paramIdx++
+	}
+
+	// Text search fallback when no index is available
+	textQuery, hasTextQuery := filters["q"].(string)
+	textParam := 0
+	if hasTextQuery && textQuery != "" {
+		query += fmt.Sprintf(" AND (p.name ILIKE $%[1]d OR p.brand ILIKE $%[1]d OR p.cuisine ILIKE $%[1]d OR p.description ILIKE $%[1]d)", paramIdx)
+		args = append(args, "%"+escapeLike(textQuery)+"%")
+		textParam = paramIdx
+		paramIdx++
+	}
+
+	// Radius filter (requires lat/lng)
+	radius, hasRadius := filters["radius"].(float64)
+	lat, hasLat := filters["lat"].(float64)
+	lng, hasLng := filters["lng"].(float64)
+	if hasRadius && hasLat && hasLng {
+		query += fmt.Sprintf(" AND ST_DWithin(location, ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography, $%d)", paramIdx, paramIdx+1, paramIdx+2)
+		args = append(args, lng, lat, radius)
+		paramIdx += 3
+	}
+
+	return searchWhere{sql: query, args: args, next: paramIdx, relevanceParam: relevanceParam, textParam: textParam}
 }
 
 // GetByID retrieves a POI by its ID
 func (r *POIRepository) GetByID(ctx context.Context, poiID uuid.UUID) (*POI, error) {
+	return r.GetByIDWithOptions(ctx, poiID, POIReadOptions{})
+}
+
+// GetByIDWithOptions retrieves a POI selecting only the requested fields (all when
+// empty), localized when a locale is set. Fields must have been checked with
+// ValidatePOIFields.
+func (r *POIRepository) GetByIDWithOptions(ctx context.Context, poiID uuid.UUID, opts POIReadOptions) (*POI, error) {
 	var poi POI
+	columns, joins := poiSelect(opts, true)
 	query := `
-		SELECT poi_id, points_of_interest.name, category_id, points_of_interest.website, brand, description,
-		       points_of_interest.address_id, parking_info, amenities, has_wifi, outdoor_seating,
-		       is_wheelchair_accessible, has_delivery, cuisine, price_range,
-		       food_options, payment_options, kids_friendly, smoker_friendly,
-		       pet_friendly, points_of_interest.status, is_verified, verified_at, points_of_interest.created_at, points_of_interest.updated_at, points_of_interest.created_by,
-		       floor_unit, public_transport, cover_image_url, gallery_image_urls,
-		       (
-		           SELECT COALESCE(json_agg(
-		               json_build_object(
-		                   'photo_id', ph.photo_id,
-		                   'poi_id', ph.poi_id,
-		                   'url', ph.url,
-		                   'is_hero', ph.is_hero,
-		                   'score', ph.score,
-		                   'upvotes', ph.upvotes,
-		                   'downvotes', ph.downvotes,
-		                   'is_pinned', ph.is_pinned,
-		                   'is_admin_official', ph.is_admin_official,
-		                   'created_at', ph.created_at
-		               ) ORDER BY ph.is_pinned DESC, ph.is_hero DESC, ph.score DESC
-		           ), '[]'::json)
-		           FROM photos ph
-		           WHERE ph.poi_id = points_of_interest.poi_id
-		       ) as gallery_images,
-		       wifi_quality, power_outlets, seating_options, noise_level, has_ac,
-		       vibes, crowd_type, lighting, music_type, cleanliness, dietary_options,
-		       featured_menu_items, specials, open_hours, reservation_required,
-		       reservation_platform, wait_time_estimate, happy_hour_info, loyalty_program,
-		       points_of_interest.phone, points_of_interest.email, social_media_links, category_ids, parking_options, pet_policy,
-		       founding_user_id, wifi_speed_mbps, wifi_verified_at, ergonomic_seating, power_sockets_reach,
-		       ST_Y(location::geometry) as latitude, ST_X(location::geometry) as longitude,
-		       (
-		           SELECT array_agg(name_key)
-		           FROM categories
-		           WHERE category_id = points_of_interest.category_id
-		              OR category_id::text = ANY(points_of_interest.category_ids)
-		       ) as category_names,
-		       a.street_address as address,
-		       u.name as founding_user_username,
-		       COALESCE(
-		           (SELECT AVG(rating)::float8 FROM reviews r WHERE r.poi_id = points_of_interest.poi_id),
-		           0
-		       ) as rating_avg,
-		       (SELECT COUNT(*)::int FROM reviews r WHERE r.poi_id = points_of_interest.poi_id) as reviews_count
-		FROM points_of_interest
-		LEFT JOIN addresses a ON points_of_interest.address_id = a.address_id
-		LEFT JOIN users u ON COALESCE(points_of_interest.founding_user_id, points_of_interest.created_by) = u.user_id
-		WHERE poi_id = $1
+		SELECT ` + columns + `
+		FROM points_of_interest p` + joins + `
+		WHERE p.poi_id = $1
 	`
 
-	err := r.db.GetContext(ctx, &poi, query, poiID)
+	err := r.db.ReadConn(ctx).GetContext(ctx, &poi, query, poiID)
 	if err != nil {
 		return nil, fmt.Errorf("get poi by id: %w", err)
 	}
@@ -274,54 +634,80 @@ func (r *POIRepository) GetByID(ctx context.Context, poiID uuid.UUID) (*POI, err
 	return &poi, nil
 }
 
+// POIVersion summarises everything that changes the POI detail representation.
+// It is cheap to compute and used for ETag / Last-Modified handling.
+type POIVersion struct {
+	UpdatedAt       time.Time `db:"updated_at"`
+	Status          string    `db:"status"`
+	LastModified    time.Time `db:"last_modified"`
+	PhotosVersion   string    `db:"photos_version"`
+	ReviewsVersion  string    `db:"reviews_version"`
+	SpecialsVersion string    `db:"specials_version"`
+	I18nVersion     string    `db:"i18n_version"`
+}
+
+// GetVersion returns the version fingerprint of a POI without loading it.
+// Photo scores and review votes have no timestamps, so they are hashed.
+func (r *POIRepository) GetVersion(ctx context.Context, poiID uuid.UUID) (*POIVersion, error) {
+	var v POIVersion
+	query := `
+		SELECT p.updated_at, p.status,
+		       GREATEST(p.updated_at, ph.last_created, rv.last_created, sp.last_updated) as last_modified,
+		       ph.version as photos_version,
+		       rv.version as reviews_version,
+		       sp.version as specials_version,
+		       tr.version as i18n_version
+		FROM points_of_interest p
+		CROSS JOIN LATERAL (
+			SELECT md5(COALESCE(string_agg(
+				concat_ws(':', photo_id, score, upvotes, downvotes, is_pinned, is_hero, sort_index),
+				',' ORDER BY photo_id), '')) as version,
+			       max(created_at) as last_created
+			FROM photos WHERE poi_id = p.poi_id
+		) ph
+		CROSS JOIN LATERAL (
+			SELECT md5(COALESCE(string_agg(
+				concat_ws(':', review_id, rating, upvotes, downvotes),
+				',' ORDER BY review_id), '')) as version,
+			       max(created_at) as last_created
+			FROM reviews WHERE poi_id = p.poi_id
+		) rv
+		CROSS JOIN LATERAL (
+			SELECT md5(COALESCE(string_agg(
+				concat_ws(':', s.special_id, s.updated_at, poi_special_is_active(s, NOW())),
+				',' ORDER BY s.special_id), '')) as version,
+			       max(s.updated_at) as last_updated
+			FROM poi_specials s
+			WHERE s.poi_id = p.poi_id AND (s.ends_at IS NULL OR s.ends_at > NOW())
+		) sp
+		CROSS JOIN LATERAL (
+			SELECT md5(COALESCE(string_agg(concat_ws(':', locale, updated_at), ',' ORDER BY locale), '')) as version
+			FROM poi_translations WHERE poi_id = p.poi_id
+		) tr
+		WHERE p.poi_id = $1
+	`
+
+	if err := r.db.ReadConn(ctx).GetContext(ctx, &v, query, poiID); err != nil {
+		return nil, fmt.Errorf("get poi version: %w", err)
+	}
+	return &v, nil
+}
+
 // GetNearby retrieves POIs within a radius (in meters) from a point
 func (r *POIRepository) GetNearby(ctx context.Context, lat, lng float64, radiusMeters int, limit int) ([]POIWithDistance, error) {
 	var pois []POIWithDistance
 
+	columns, joins := poiSelect(POIReadOptions{}, false)
 	query := `
-		SELECT
-			poi_id, points_of_interest.name, category_id, website, brand, description,
-			address_id, parking_info, amenities, has_wifi, outdoor_seating,
-			is_wheelchair_accessible, has_delivery, cuisine, price_range,
-			food_options, payment_options, kids_friendly, smoker_friendly,
-			pet_friendly, is_verified, verified_at, points_of_interest.created_at, points_of_interest.updated_at,
-			cover_image_url, gallery_image_urls, status,
-			(
-			   SELECT COALESCE(json_agg(
-				   json_build_object(
-					   'photo_id', ph.photo_id,
-					   'poi_id', ph.poi_id,
-					   'url', ph.url,
-					   'is_hero', ph.is_hero,
-					   'score', ph.score,
-					   'upvotes', ph.upvotes,
-					   'downvotes', ph.downvotes,
-					   'is_pinned', ph.is_pinned,
-					   'is_admin_official', ph.is_admin_official,
-					   'created_at', ph.created_at
-				   ) ORDER BY ph.is_pinned DESC, ph.is_hero DESC, ph.score DESC
-			   ), '[]'::json)
-			   FROM photos ph
-			   WHERE ph.poi_id = points_of_interest.poi_id
-			) as gallery_images,
-			founding_user_id, wifi_speed_mbps, wifi_verified_at, ergonomic_seating, power_sockets_reach,
-			ST_Y(location::geometry) as latitude,
-			ST_X(location::geometry) as longitude,
+		SELECT ` + columns + `,
 			ST_Distance(
-				location,
+				p.location,
 				ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography
-			) as distance_meters,
-			u.name as founding_user_username,
-			COALESCE(
-				(SELECT AVG(rating)::float8 FROM reviews r WHERE r.poi_id = points_of_interest.poi_id),
-				0
-			) as rating_avg,
-			(SELECT COUNT(*)::int FROM reviews r WHERE r.poi_id = points_of_interest.poi_id) as reviews_count
-		FROM points_of_interest
-		LEFT JOIN users u ON COALESCE(points_of_interest.founding_user_id, points_of_interest.created_by) = u.user_id
-		WHERE location IS NOT NULL
+			) as distance_meters
+		FROM points_of_interest p` + joins + `
+		WHERE p.location IS NOT NULL
 		  AND ST_DWithin(
-			location,
+			p.location,
 			ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography,
 			$3
 		)
@@ -329,7 +715,7 @@ func (r *POIRepository) GetNearby(ctx context.Context, lat, lng float64, radiusM
 		LIMIT $4
 	`
 
-	err := r.db.SelectContext(ctx, &pois, query, lng, lat, radiusMeters, limit)
+	err := r.db.ReadConn(ctx).SelectContext(ctx, &pois, query, lng, lat, radiusMeters, limit)
 	if err != nil {
 		return nil, fmt.Errorf("get nearby pois: %w", err)
 	}
@@ -337,37 +723,169 @@ func (r *POIRepository) GetNearby(ctx context.Context, lat, lng float64, radiusM
 	return pois, nil
 }
 
+// TilePOI is an approved POI in a geohash tile, with the fields the map and
+// offline lists need
+type TilePOI struct {
+	PoiID         uuid.UUID  `db:"poi_id" json:"poi_id"`
+	Name          string     `db:"name" json:"name"`
+	CategoryID    *uuid.UUID `db:"category_id" json:"category_id,omitempty"`
+	CoverImageURL *string    `db:"cover_image_url" json:"cover_image_url,omitempty"`
+	PriceRange    *int       `db:"price_range" json:"price_range,omitempty"`
+	RatingAvg     float64    `db:"rating_avg" json:"rating_avg"`
+	ReviewsCount  int        `db:"reviews_count" json:"reviews_count"`
+	Latitude      float64    `db:"latitude" json:"latitude"`
+	Longitude     float64    `db:"longitude" json:"longitude"`
+	Geohash       string     `db:"geohash" json:"geohash"`
+	UpdatedAt     time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// GetTile returns approved POIs whose geohash starts with one of the given
+// (validated) cells, at most limit, ordered by geohash
+func (r *POIRepository) GetTile(ctx context.Context, cells []string, limit int) ([]TilePOI, error) {
+	conds := make([]string, len(cells))
+	args := make([]interface{}, 0, len(cells)+1)
+	for i, cell := range cells {
+		conds[i] = fmt.Sprintf("p.geohash LIKE $%d", i+1)
+		args = append(args, escapeLike(cell)+"%")
+	}
+	args = append(args, limit)
+
+	pois := []TilePOI{}
+	err := r.db.ReadConn(ctx).SelectContext(ctx, &pois, fmt.Sprintf(`
+		SELECT p.poi_id, p.name, p.category_id, p.cover_image_url, p.price_range,
+		       p.rating_avg, p.reviews_count,
+		       ST_Y(p.location::geometry) AS latitude,
+		       ST_X(p.location::geometry) AS longitude,
+		       p.geohash, p.updated_at
+		FROM points_of_interest p
+		WHERE p.status = 'approved' AND (%s)
+		ORDER BY p.geohash, p.poi_id
+		LIMIT $%d
+	`, strings.Join(conds, " OR "), len(args)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("get poi tile: %w", err)
+	}
+	return pois, nil
+}
+
+// POIDistance is how far a POI is from a point
+type POIDistance struct {
+	PoiID          uuid.UUID `db:"poi_id" json:"poi_id"`
+	DistanceMeters float64   `db:"distance_meters" json:"distance_meters"`
+}
+
+// GetDistances returns the distance from a point to each of the given POIs
+// in one query, in the order of ids. POIs without a location are left out.
+func (r *POIRepository) GetDistances(ctx context.Context, lat, lng float64, ids []uuid.UUID) ([]POIDistance, error) {
+	distances := []POIDistance{}
+	err := r.db.ReadConn(ctx).SelectContext(ctx, &distances, `
+		SELECT p.poi_id, ST_Distance(p.location, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography) AS distance_meters
+		FROM unnest($3::uuid[]) WITH ORDINALITY AS ids(poi_id, ord)
+		JOIN points_of_interest p ON p.poi_id = ids.poi_id
+		WHERE p.location IS NOT NULL
+		ORDER BY ids.ord
+	`, lng, lat, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("get poi distances: %w", err)
+	}
+	return distances, nil
+}
+
+// ApprovedPOI is a POI with the time it was first approved
+type ApprovedPOI struct {
+	PoiID      uuid.UUID `db:"poi_id"`
+	ApprovedAt time.Time `db:"approved_at"`
+}
+
+// ListApprovedBetween returns the POIs still approved that were first
+// approved after from and up to until, oldest first
+func (r *POIRepository) ListApprovedBetween(ctx context.Context, from, until time.Time) ([]ApprovedPOI, error) {
+	pois := []ApprovedPOI{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &pois, `
+		SELECT poi_id, approved_at
+		FROM points_of_interest
+		WHERE status = 'approved' AND approved_at > $1 AND approved_at <= $2
+		ORDER BY approved_at, poi_id
+	`, from, until)
+	if err != nil {
+		return nil, fmt.Errorf("list approved pois: %w", err)
+	}
+	return pois, nil
+}
+
+// NearbySimilarPOI is an approved POI near another one that shares its category or vibes
+type NearbySimilarPOI struct {
+	PoiID          uuid.UUID  `db:"poi_id" json:"poi_id"`
+	Name           string     `db:"name" json:"name"`
+	CategoryID     *uuid.UUID `db:"category_id" json:"category_id,omitempty"`
+	CoverImageURL  *string    `db:"cover_image_url" json:"cover_image_url,omitempty"`
+	PriceRange     *int       `db:"price_range" json:"price_range,omitempty"`
+	RatingAvg      float64    `db:"rating_avg" json:"rating_avg"`
+	ReviewsCount   int        `db:"reviews_count" json:"reviews_count"`
+	Latitude       float64    `db:"latitude" json:"latitude"`
+	Longitude      float64    `db:"longitude" json:"longitude"`
+	DistanceMeters float64    `db:"distance_meters" json:"distance_meters"`
+	SameCategory   bool       `db:"same_category" json:"same_category"`
+	SharedVibes    int        `db:"shared_vibes" json:"shared_vibes"`
+	Score          float64    `db:"score" json:"score"`
+}
+
+// GetNearbySimilar returns approved POIs within radiusMeters of a POI that
+// share one of its categories or vibes. Candidates are ranked by similarity
+// (category match and vibe overlap, 70%) and closeness (30%).
+func (r *POIRepository) GetNearbySimilar(ctx context.Context, poiID uuid.UUID, radiusMeters, limit int) ([]NearbySimilarPOI, error) {
+	pois := []NearbySimilarPOI{}
+	err := r.db.ReadConn(ctx).SelectContext(ctx, &pois, `
+		WITH src AS (
+			SELECT poi_id, location,
+			       array_remove(ARRAY[category_id::text] || COALESCE(category_ids, '{}'), NULL) AS categories,
+			       COALESCE(vibes, '{}') AS vibes
+			FROM points_of_interest
+			WHERE poi_id = $1 AND location IS NOT NULL
+		), candidates AS (
+			SELECT p.poi_id, p.name, p.category_id, p.cover_image_url, p.price_range,
+			       p.rating_avg, p.reviews_count,
+			       ST_Y(p.location::geometry) AS latitude,
+			       ST_X(p.location::geometry) AS longitude,
+			       ST_Distance(p.location, src.location) AS distance_meters,
+			       array_remove(ARRAY[p.category_id::text] || COALESCE(p.category_ids, '{}'), NULL) && src.categories AS same_category,
+			       (SELECT COUNT(DISTINCT v) FROM unnest(p.vibes) v WHERE v = ANY(src.vibes)) AS shared_vibes,
+			       (SELECT COUNT(DISTINCT v) FROM unnest(COALESCE(p.vibes, '{}') || src.vibes) v) AS all_vibes
+			FROM points_of_interest p, src
+			WHERE p.status = 'approved'
+			  AND p.poi_id <> src.poi_id
+			  AND p.location IS NOT NULL
+			  AND ST_DWithin(p.location, src.location, $2)
+		)
+		SELECT poi_id, name, category_id, cover_image_url, price_range, rating_avg, reviews_count,
+		       latitude, longitude, distance_meters, same_category, shared_vibes,
+		       0.7 * ((CASE WHEN same_category THEN 0.5 ELSE 0 END)
+		              + 0.5 * COALESCE(shared_vibes::float8 / NULLIF(all_vibes, 0), 0))
+		       + 0.3 * (1 - distance_meters / $2) AS score
+		FROM candidates
+		WHERE same_category OR shared_vibes > 0
+		ORDER BY score DESC, distance_meters
+		LIMIT $3
+	`, poiID, radiusMeters, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get nearby similar pois: %w", err)
+	}
+	return pois, nil
+}
+
 // GetByUser retrieves all POIs created by a specific user
 func (r *POIRepository) GetByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]POI, int, error) {
 	var pois []POI
+	columns, joins := poiSummarySelect()
 	query := `
-		SELECT poi_id, name, category_id, description, status, created_by,
-		       cover_image_url, has_wifi, outdoor_seating, price_range, created_at, updated_at,
-			   (
-			   SELECT COALESCE(json_agg(
-				   json_build_object(
-					   'photo_id', ph.photo_id,
-					   'poi_id', ph.poi_id,
-					   'url', ph.url,
-					   'is_hero', ph.is_hero,
-					   'score', ph.score,
-					   'upvotes', ph.upvotes,
-					   'downvotes', ph.downvotes,
-					   'is_pinned', ph.is_pinned,
-					   'is_admin_official', ph.is_admin_official,
-					   'created_at', ph.created_at
-				   ) ORDER BY ph.is_pinned DESC, ph.is_hero DESC, ph.score DESC
-			   ), '[]'::json)
-			   FROM photos ph
-			   WHERE ph.poi_id = points_of_interest.poi_id
-			) as gallery_images
-		FROM points_of_interest
-		WHERE created_by = $1
-		ORDER BY updated_at DESC
+		SELECT ` + columns + `
+		FROM points_of_interest p` + joins + `
+		WHERE p.created_by = $1
+		ORDER BY p.updated_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	err := r.db.SelectContext(ctx, &pois, query, userID, limit, offset)
+	err := r.db.ReadConn(ctx).SelectContext(ctx, &pois, query, userID, limit, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("get pois by user: %w", err)
 	}
@@ -375,7 +893,7 @@ func (r *POIRepository) GetByUser(ctx context.Context, userID uuid.UUID, limit,
 	// Get total count
 	var total int
 	countQuery := `SELECT COUNT(*) FROM points_of_interest WHERE created_by = $1`
-	err = r.db.GetContext(ctx, &total, countQuery, userID)
+	err = r.db.ReadConn(ctx).GetContext(ctx, &total, countQuery, userID)
 	if err != nil {
 		return pois, 0, fmt.Errorf("count pois by user: %w", err)
 	}
@@ -393,7 +911,7 @@ func (r *POIRepository) GetWithHeroImages(ctx context.Context, limit, offset int
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.db.QueryxContext(ctx, query, limit, offset)
+	rows, err := r.db.ReadConn(ctx).QueryxContext(ctx, query, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("query with hero images: %w", err)
 	}
@@ -409,3 +927,179 @@ func (r *POIRepository) GetWithHeroImages(ctx context.Context, limit, offset int
 
 	return pois, nil
 }
+
+// escapeLike escapes LIKE wildcards so user input matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// POISearchDocument is the denormalised view of an approved POI pushed to the
+// external search index
+type POISearchDocument struct {
+	ID              uuid.UUID      `db:"poi_id" json:"id"`
+	Name            string         `db:"name" json:"name"`
+	TranslatedNames pq.StringArray `db:"translated_names" json:"translated_names"`
+	Brand           *string        `db:"brand" json:"brand,omitempty"`
+	Description     *string        `db:"description" json:"description,omitempty"`
+	Cuisine         *string        `db:"cuisine" json:"cuisine,omitempty"`
+	CategoryNames   pq.StringArray `db:"category_names" json:"category_names"`
+	Vibes           pq.StringArray `db:"vibes" json:"vibes"`
+	Address         *string        `db:"address" json:"address,omitempty"`
+	Latitude        float64        `db:"latitude" json:"latitude"`
+	Longitude       float64        `db:"longitude" json:"longitude"`
+	PriceRange      *int           `db:"price_range" json:"price_range,omitempty"`
+	RatingAvg       float64        `db:"rating_avg" json:"rating_avg"`
+	ReviewsCount    int            `db:"reviews_count" json:"reviews_count"`
+	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+const searchDocumentSelect = `
+	SELECT p.poi_id, p.name,
+	       ARRAY(SELECT t.name FROM poi_translations t WHERE t.poi_id = p.poi_id AND t.name IS NOT NULL) as translated_names,
+	       p.brand, p.description, p.cuisine,
+	       ARRAY(
+	           SELECT name_key FROM categories
+	           WHERE category_id = p.category_id OR category_id::text = ANY(p.category_ids)
+	       ) as category_names,
+	       COALESCE(p.vibes, '{}') as vibes,
+	       a.street_address as address,
+	       ST_Y(p.location::geometry) as latitude, ST_X(p.location::geometry) as longitude,
+	       p.price_range, p.rating_avg, p.reviews_count, p.updated_at
+	FROM points_of_interest p
+	LEFT JOIN addresses a ON p.address_id = a.address_id
+	WHERE p.status = 'approved'`
+
+// GetSearchDocument returns the search document of an approved POI.
+// It returns sql.ErrNoRows (wrapped) when the POI is missing or not approved.
+func (r *POIRepository) GetSearchDocument(ctx context.Context, poiID uuid.UUID) (*POISearchDocument, error) {
+	var doc POISearchDocument
+	err := r.db.Conn(ctx).GetContext(ctx, &doc, searchDocumentSelect+` AND p.poi_id = $1`, poiID)
+	if err != nil {
+		return nil, fmt.Errorf("get search document: %w", err)
+	}
+	return &doc, nil
+}
+
+// ListSearchDocuments pages through approved POIs by ID, starting after the given ID
+func (r *POIRepository) ListSearchDocuments(ctx context.Context, after uuid.UUID, limit int) ([]POISearchDocument, error) {
+	var docs []POISearchDocument
+	err := r.db.Conn(ctx).SelectContext(ctx, &docs, searchDocumentSelect+`
+		AND p.poi_id > $1
+		ORDER BY p.poi_id
+		LIMIT $2`, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list search documents: %w", err)
+	}
+	return docs, nil
+}
+
+// RecentPOI is a POI card in the "New places" feed
+type RecentPOI struct {
+	PoiID         uuid.UUID  `db:"poi_id" json:"poi_id"`
+	Name          string     `db:"name" json:"name"`
+	CategoryID    *uuid.UUID `db:"category_id" json:"category_id,omitempty"`
+	CoverImageURL *string    `db:"cover_image_url" json:"cover_image_url,omitempty"`
+	PriceRange    *int       `db:"price_range" json:"price_range,omitempty"`
+	RatingAvg     float64    `db:"rating_avg" json:"rating_avg"`
+	ReviewsCount  int        `db:"reviews_count" json:"reviews_count"`
+	ApprovedAt    time.Time  `db:"approved_at" json:"approved_at"`
+}
+
+// RecentPOIFilter selects recently approved POIs. Results are ordered newest
+// first; After/AfterID resume after the last POI of the previous page.
+type RecentPOIFilter struct {
+	Since      time.Time
+	Area       string     // Service area slug, optional
+	CategoryID *uuid.UUID // Primary or secondary category, optional
+	After      *time.Time
+	AfterID    uuid.UUID
+	Limit      int
+}
+
+// ListRecentlyApproved returns approved POIs by approval time, newest first
+func (r *POIRepository) ListRecentlyApproved(ctx context.Context, f RecentPOIFilter) ([]RecentPOI, error) {
+	pois := []RecentPOI{}
+	err := r.db.ReadConn(ctx).SelectContext(ctx, &pois, `
+		SELECT p.poi_id, p.name, p.category_id, p.cover_image_url, p.price_range,
+		       p.rating_avg, p.reviews_count, p.approved_at
+		FROM points_of_interest p
+		WHERE p.status = 'approved'
+		  AND p.approved_at >= $1
+		  AND ($2::timestamptz IS NULL OR (p.approved_at, p.poi_id) < ($2, $3))
+		  AND ($4::uuid IS NULL OR p.category_id = $4 OR $4::text = ANY(p.category_ids))
+		  AND ($5 = '' OR EXISTS (
+		      SELECT 1 FROM service_areas sa
+		      WHERE sa.slug = $5 AND sa.is_active AND ST_Within(p.location::geometry, sa.boundary)))
+		ORDER BY p.approved_at DESC, p.poi_id DESC
+		LIMIT $6
+	`, f.Since, f.After, f.AfterID, f.CategoryID, f.Area, f.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("list recently approved pois: %w", err)
+	}
+	return pois, nil
+}
+
+// GetByUserAndStatus retrieves a user's POIs with the given status
+func (r *POIRepository) GetByUserAndStatus(ctx context.Context, userID uuid.UUID, status string, limit, offset int) ([]POI, error) {
+	var pois []POI
+	columns, joins := poiSummarySelect()
+	query := `
+		SELECT ` + columns + `
+		FROM points_of_interest p` + joins + `
+		WHERE p.created_by = $1 AND p.status = $2
+		ORDER BY p.updated_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	err := r.db.ReadConn(ctx).SelectContext(ctx, &pois, query, userID, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get user pois: %w", err)
+	}
+	return pois, nil
+}
+
+// GetByStatus retrieves POIs by status (for admin queue)
+func (r *POIRepository) GetByStatus(ctx context.Context, status string, limit, offset int) ([]POI, error) {
+	return r.GetByStatusOrdered(ctx, status, "", false, limit, offset)
+}
+
+// GetByStatusOrdered retrieves POIs by status with their completeness score,
+// whether they are past the review SLA and their active reviewer assignment. sort "completeness" puts the most
+// complete submissions first; otherwise the oldest submission comes first.
+// overdueOnly keeps only pending POIs past the SLA.
+func (r *POIRepository) GetByStatusOrdered(ctx context.Context, status, sort string, overdueOnly bool, limit, offset int) ([]POI, error) {
+	orderBy := "p.submitted_at ASC"
+	if sort == "completeness" {
+		orderBy = "completeness_score DESC, p.submitted_at ASC"
+	}
+
+	overdue := fmt.Sprintf("(p.status = 'pending' AND %s < NOW() - make_interval(hours => %d))", pendingSinceExpr, ReviewSLAHours)
+	where := "p.status = $1"
+	if overdueOnly {
+		where += " AND " + overdue
+	}
+
+	var pois []POI
+	columns, joins := poiSummarySelect()
+	query := fmt.Sprintf(`
+		SELECT %s,
+		       %s AS completeness_score,
+		       %s AS overdue,
+		       ra.assigned_to, ra.assignee_name, ra.assignment_expires_at
+		FROM points_of_interest p%s
+		LEFT JOIN LATERAL (
+			SELECT p.assigned_to, ru.name AS assignee_name, p.assignment_expires_at
+			FROM users ru
+			WHERE ru.user_id = p.assigned_to AND p.assignment_expires_at > NOW()
+		) ra ON true
+		WHERE %s
+		ORDER BY %s
+		LIMIT $2 OFFSET $3
+	`, columns, completenessScoreExpr(), overdue, joins, where, orderBy)
+
+	err := r.db.ReadConn(ctx).SelectContext(ctx, &pois, query, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get pois by status: %w", err)
+	}
+	return pois, nil
+}
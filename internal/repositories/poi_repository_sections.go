@@ -0,0 +1,285 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"maukemana-backend/internal/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// poiSections are the sections of the POI editing routes, in wizard order
+var poiSections = []string{"profile", "location", "operations", "work-prod", "atmosphere", "food-drink", "social", "contact", "accessibility"}
+
+// SectionEdit is the last recorded change to a section of a POI
+type SectionEdit struct {
+	Section    string     `db:"section"`
+	UpdatedAt  time.Time  `db:"updated_at"`
+	EditedBy   *uuid.UUID `db:"edited_by"`
+	EditorName *string    `db:"editor_name"`
+}
+
+// updateReturning runs a section UPDATE of points_of_interest, which must end
+// with its WHERE clause, records editedBy as the section's last editor and
+// returns the POI as the statement left it. The detail columns are selected
+// from the UPDATE's RETURNING row, so the result reflects the write without a
+// second read.
+func (r *POIRepository) updateReturning(ctx context.Context, q database.Querier, section string, editedBy *uuid.UUID, update string, args ...interface{}) (*POI, error) {
+	columns, joins := poiSelect(POIReadOptions{}, true)
+	args = append(args, section, editedBy)
+	query := fmt.Sprintf(`
+		WITH p AS (%s RETURNING *),
+		edit AS (
+			INSERT INTO poi_section_edits (poi_id, section, edited_by)
+			SELECT poi_id, $%d::text, $%d::uuid FROM p
+			ON CONFLICT (poi_id, section) DO UPDATE
+			SET edited_by = EXCLUDED.edited_by, updated_at = NOW()
+		)
+		SELECT %s
+		FROM p%s`, update, len(args)-1, len(args), columns, joins)
+
+	var poi POI
+	if err := q.GetContext(ctx, &poi, query, args...); err != nil {
+		return nil, err
+	}
+	return &poi, nil
+}
+
+// UpdateProfile updates profile and visual fields
+func (r *POIRepository) UpdateProfile(ctx context.Context, poiID uuid.UUID, input CreatePOIInput, editedBy *uuid.UUID) (*POI, error) {
+	query := `
+		UPDATE points_of_interest SET
+			name = $2, brand = $3, description = $4,
+			cover_image_url = $5, gallery_image_urls = $6,
+			category_id = (SELECT category_id FROM categories WHERE name_key = ANY($7) LIMIT 1),
+			category_ids = (SELECT array_agg(category_id) FROM categories WHERE name_key = ANY($7)),
+			updated_at = NOW()
+		WHERE poi_id = $1
+	`
+	poi, err := r.updateReturning(ctx, r.db.Conn(ctx), "profile", editedBy, query, poiID, input.Name, input.BrandName, input.Description, input.CoverImageURL, pq.StringArray(input.GalleryImageURLs), pq.StringArray(input.Categories))
+	if err != nil {
+		return nil, fmt.Errorf("update profile: %w", err)
+	}
+	return poi, nil
+}
+
+// UpdateLocation updates location specific fields
+func (r *POIRepository) UpdateLocation(ctx context.Context, poiID uuid.UUID, input CreatePOIInput, editedBy *uuid.UUID) (*POI, error) {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("update location begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. Handle Address
+	var addressID *uuid.UUID
+
+	// Check if POI already has an address
+	var existingAddressID *uuid.UUID
+	err = tx.QueryRowContext(ctx, "SELECT address_id FROM points_of_interest WHERE poi_id = $1", poiID).Scan(&existingAddressID)
+	if err != nil {
+		return nil, fmt.Errorf("update location check address: %w", err)
+	}
+
+	if input.Address != nil && *input.Address != "" {
+		if existingAddressID != nil {
+			// Update existing address
+			_, err = tx.ExecContext(ctx, "UPDATE addresses SET street_address = $1 WHERE address_id = $2", *input.Address, existingAddressID)
+			if err != nil {
+				return nil, fmt.Errorf("update address: %w", err)
+			}
+			addressID = existingAddressID
+		} else {
+			// Insert new address
+			var newAddrID uuid.UUID
+			err = tx.QueryRowContext(ctx, "INSERT INTO addresses (street_address) VALUES ($1) RETURNING address_id", *input.Address).Scan(&newAddrID)
+			if err != nil {
+				return nil, fmt.Errorf("insert address: %w", err)
+			}
+			addressID = &newAddrID
+		}
+	} else {
+		addressID = existingAddressID
+	}
+
+	// 2. Update POI Location
+	query := `
+		UPDATE points_of_interest SET
+			location = ST_SetSRID(ST_MakePoint($2, $3), 4326)::geography,
+			floor_unit = $4, public_transport = $5,
+			address_id = COALESCE($8, address_id),
+			parking_options = $6,
+			is_wheelchair_accessible = $7,
+			updated_at = NOW()
+		WHERE poi_id = $1
+	`
+	poi, err := r.updateReturning(ctx, tx, "location", editedBy, query, poiID, input.Longitude, input.Latitude, input.FloorUnit, input.PublicTransport, pq.StringArray(input.ParkingOptions), input.WheelchairAccessible, addressID)
+	if err != nil {
+		return nil, fmt.Errorf("update location update poi: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("update location commit: %w", err)
+	}
+
+	return poi, nil
+}
+
+// UpdateOperations updates operational fields
+func (r *POIRepository) UpdateOperations(ctx context.Context, poiID uuid.UUID, input CreatePOIInput, editedBy *uuid.UUID) (*POI, error) {
+	openHoursJSON, _ := json.Marshal(input.OpenHours)
+	query := `
+		UPDATE points_of_interest SET
+			open_hours = $1, reservation_required = $2, reservation_platform = $3,
+			payment_options = $4, wait_time_estimate = $5,
+			updated_at = NOW()
+		WHERE poi_id = $6
+	`
+	poi, err := r.updateReturning(ctx, r.db.Conn(ctx), "operations", editedBy, query, openHoursJSON, input.ReservationRequired, input.ReservationPlatform, pq.StringArray(input.PaymentOptions), input.WaitTimeEstimate, poiID)
+	if err != nil {
+		return nil, fmt.Errorf("update operations: %w", err)
+	}
+	return poi, nil
+}
+
+// UpdateWorkProd updates work and productivity fields
+func (r *POIRepository) UpdateWorkProd(ctx context.Context, poiID uuid.UUID, input CreatePOIInput, editedBy *uuid.UUID) (*POI, error) {
+	query := `
+		UPDATE points_of_interest SET
+			wifi_quality = $1, power_outlets = $2, seating_options = $3,
+			noise_level = $4, has_ac = $5,
+			updated_at = NOW()
+		WHERE poi_id = $6
+	`
+	poi, err := r.updateReturning(ctx, r.db.Conn(ctx), "work-prod", editedBy, query, input.WifiQuality, input.PowerOutlets, pq.StringArray(input.SeatingOptions), input.NoiseLevel, input.HasAC, poiID)
+	if err != nil {
+		return nil, fmt.Errorf("update work prod: %w", err)
+	}
+	return poi, nil
+}
+
+// UpdateAtmosphere updates atmosphere fields
+func (r *POIRepository) UpdateAtmosphere(ctx context.Context, poiID uuid.UUID, input CreatePOIInput, editedBy *uuid.UUID) (*POI, error) {
+	query := `
+		UPDATE points_of_interest SET
+			vibes = $1, crowd_type = $2, lighting = $3,
+			music_type = $4, cleanliness = $5,
+			updated_at = NOW()
+		WHERE poi_id = $6
+	`
+	poi, err := r.updateReturning(ctx, r.db.Conn(ctx), "atmosphere", editedBy, query, pq.StringArray(input.Vibes), pq.StringArray(input.CrowdType), input.Lighting, input.MusicType, input.Cleanliness, poiID)
+	if err != nil {
+		return nil, fmt.Errorf("update atmosphere: %w", err)
+	}
+	return poi, nil
+}
+
+// UpdateFoodDrink updates food and drink fields
+func (r *POIRepository) UpdateFoodDrink(ctx context.Context, poiID uuid.UUID, input CreatePOIInput, editedBy *uuid.UUID) (*POI, error) {
+	query := `
+		UPDATE points_of_interest SET
+			cuisine = $1, price_range = $2, food_options = $3,
+			featured_menu_items = $4, specials = $5,
+			price_currency = COALESCE($7, price_currency), avg_spend_min = $8, avg_spend_max = $9,
+			updated_at = NOW()
+		WHERE poi_id = $6
+	`
+	// Note: mapping DietaryOptions to food_options column
+	poi, err := r.updateReturning(ctx, r.db.Conn(ctx), "food-drink", editedBy, query, input.Cuisine, input.PriceRange, pq.StringArray(input.DietaryOptions), pq.StringArray(input.FeaturedItems), pq.StringArray(input.Specials), poiID,
+		input.PriceCurrency, input.AvgSpendMin, input.AvgSpendMax)
+	if err != nil {
+		return nil, fmt.Errorf("update food drink: %w", err)
+	}
+	return poi, nil
+}
+
+// UpdateSocial updates social and lifestyle fields
+func (r *POIRepository) UpdateSocial(ctx context.Context, poiID uuid.UUID, input CreatePOIInput, editedBy *uuid.UUID) (*POI, error) {
+	query := `
+		UPDATE points_of_interest SET
+			kids_friendly = $1, pet_friendly = $2, smoker_friendly = $3,
+			happy_hour_info = $4, loyalty_program = $5,
+			pet_policy = $6,
+			updated_at = NOW()
+		WHERE poi_id = $7
+	`
+	poi, err := r.updateReturning(ctx, r.db.Conn(ctx), "social", editedBy, query, input.KidsFriendly, pq.StringArray(input.PetFriendly), input.SmokerFriendly, input.HappyHourInfo, input.LoyaltyProgram, input.PetPolicy, poiID)
+	if err != nil {
+		return nil, fmt.Errorf("update social: %w", err)
+	}
+	return poi, nil
+}
+
+// UpdateContact updates contact fields
+func (r *POIRepository) UpdateContact(ctx context.Context, poiID uuid.UUID, input CreatePOIInput, editedBy *uuid.UUID) (*POI, error) {
+	socialLinksJSON, _ := json.Marshal(input.SocialLinks)
+	query := `
+		UPDATE points_of_interest SET
+			phone = $1, email = $2, website = $3,
+			social_media_links = $4,
+			updated_at = NOW()
+		WHERE poi_id = $5
+	`
+	poi, err := r.updateReturning(ctx, r.db.Conn(ctx), "contact", editedBy, query, input.Phone, input.Email, input.Website, socialLinksJSON, poiID)
+	if err != nil {
+		return nil, fmt.Errorf("update contact: %w", err)
+	}
+	return poi, nil
+}
+
+// UpdateAccessibility updates accessibility fields. The wheelchair flag is
+// shared with the location section.
+func (r *POIRepository) UpdateAccessibility(ctx context.Context, poiID uuid.UUID, input CreatePOIInput, editedBy *uuid.UUID) (*POI, error) {
+	query := `
+		UPDATE points_of_interest SET
+			is_wheelchair_accessible = $1, step_free_entrance = $2, accessible_restroom = $3,
+			table_heights = $4, braille_menu = $5, accessible_parking = $6,
+			accessibility_notes = $7,
+			updated_at = NOW()
+		WHERE poi_id = $8
+	`
+	poi, err := r.updateReturning(ctx, r.db.Conn(ctx), "accessibility", editedBy, query, input.WheelchairAccessible, input.StepFreeEntrance, input.AccessibleRestroom, pq.StringArray(input.TableHeights), input.BrailleMenu, input.AccessibleParking, input.AccessibilityNotes, poiID)
+	if err != nil {
+		return nil, fmt.Errorf("update accessibility: %w", err)
+	}
+	return poi, nil
+}
+
+// recordSectionEdits marks sections of a POI as just changed by editedBy
+func (r *POIRepository) recordSectionEdits(ctx context.Context, q database.Querier, poiID uuid.UUID, sections []string, editedBy *uuid.UUID) error {
+	_, err := q.ExecContext(ctx, `
+		INSERT INTO poi_section_edits (poi_id, section, edited_by)
+		SELECT $1, unnest($2::text[]), $3::uuid
+		ON CONFLICT (poi_id, section) DO UPDATE
+		SET edited_by = EXCLUDED.edited_by, updated_at = NOW()
+	`, poiID, pq.StringArray(sections), editedBy)
+	if err != nil {
+		return fmt.Errorf("record section edits: %w", err)
+	}
+	return nil
+}
+
+// GetSectionEdit returns the last recorded change to a section of a POI, or
+// nil when none was recorded
+func (r *POIRepository) GetSectionEdit(ctx context.Context, poiID uuid.UUID, section string) (*SectionEdit, error) {
+	var edit SectionEdit
+	err := r.db.Conn(ctx).GetContext(ctx, &edit, `
+		SELECT e.section, e.updated_at, e.edited_by, u.name AS editor_name
+		FROM poi_section_edits e
+		LEFT JOIN users u ON u.user_id = e.edited_by
+		WHERE e.poi_id = $1 AND e.section = $2
+	`, poiID, section)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get section edit: %w", err)
+	}
+	return &edit, nil
+}
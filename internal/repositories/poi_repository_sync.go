@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// syncCommitGrace holds back the most recent changes, so rows stamped by a
+// transaction that commits after a sync read are still picked up next time
+const syncCommitGrace = 30 * time.Second
+
+// SyncPOI is one change in the offline sync feed: a compact approved POI, or a
+// tombstone (Deleted set, other fields empty) for a POI that was deleted or
+// unpublished. Rating changes alone do not produce a change.
+type SyncPOI struct {
+	PoiID         uuid.UUID      `db:"poi_id" json:"poi_id"`
+	ChangedAt     time.Time      `db:"changed_at" json:"changed_at"`
+	Deleted       bool           `db:"deleted" json:"deleted,omitempty"`
+	Name          *string        `db:"name" json:"name,omitempty"`
+	CategoryID    *uuid.UUID     `db:"category_id" json:"category_id,omitempty"`
+	CoverImageURL *string        `db:"cover_image_url" json:"cover_image_url,omitempty"`
+	PriceRange    *int           `db:"price_range" json:"price_range,omitempty"`
+	RatingAvg     *float64       `db:"rating_avg" json:"rating_avg,omitempty"`
+	ReviewsCount  *int           `db:"reviews_count" json:"reviews_count,omitempty"`
+	Vibes         pq.StringArray `db:"vibes" json:"vibes,omitempty"`
+	IsVerified    *bool          `db:"is_verified" json:"is_verified,omitempty"`
+	Latitude      *float64       `db:"latitude" json:"latitude,omitempty"`
+	Longitude     *float64       `db:"longitude" json:"longitude,omitempty"`
+	Geohash       *string        `db:"geohash" json:"geohash,omitempty"`
+}
+
+// SyncCursor is the position of the last change a client has seen
+type SyncCursor struct {
+	ChangedAt time.Time
+	PoiID     uuid.UUID
+}
+
+// ListChangesSince returns POI changes after a cursor, oldest first. Without
+// a cursor it returns every approved POI and no tombstones (a full sync).
+// area optionally restricts the feed to a service area slug.
+func (r *POIRepository) ListChangesSince(ctx context.Context, after *SyncCursor, area string, limit int) ([]SyncPOI, error) {
+	var since *time.Time
+	sinceID := uuid.Nil
+	if after != nil {
+		since, sinceID = &after.ChangedAt, after.PoiID
+	}
+
+	changes := []SyncPOI{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &changes, `
+		WITH area AS (
+			SELECT boundary FROM service_areas WHERE slug = $3 AND is_active
+		)
+		SELECT * FROM (
+			SELECT p.poi_id, p.updated_at AS changed_at, FALSE AS deleted,
+			       p.name, p.category_id, p.cover_image_url, p.price_range,
+			       p.rating_avg, p.reviews_count, p.vibes, p.is_verified,
+			       ST_Y(p.location::geometry) AS latitude,
+			       ST_X(p.location::geometry) AS longitude,
+			       p.geohash
+			FROM points_of_interest p
+			WHERE p.status = 'approved'
+			  AND ($1::timestamptz IS NULL OR (p.updated_at, p.poi_id) > ($1, $2))
+			  AND ($3 = '' OR EXISTS (SELECT 1 FROM area WHERE ST_Within(p.location::geometry, area.boundary)))
+			UNION ALL
+			SELECT t.poi_id, t.removed_at, TRUE,
+			       NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL
+			FROM poi_tombstones t
+			WHERE $1::timestamptz IS NOT NULL
+			  AND (t.removed_at, t.poi_id) > ($1, $2)
+			  AND ($3 = '' OR EXISTS (SELECT 1 FROM area WHERE ST_Within(t.location::geometry, area.boundary)))
+		) c
+		WHERE c.changed_at <= $4
+		ORDER BY c.changed_at, c.poi_id
+		LIMIT $5
+	`, since, sinceID, area, time.Now().Add(-syncCommitGrace), limit)
+	if err != nil {
+		return nil, fmt.Errorf("list poi changes: %w", err)
+	}
+	return changes, nil
+}
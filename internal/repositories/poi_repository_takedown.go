@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// TakedownReasons are the legal reason codes of a takedown and how they are
+// described to the owner
+var TakedownReasons = map[string]string{
+	"copyright":   "Copyright infringement",
+	"trademark":   "Trademark infringement",
+	"defamation":  "Defamation",
+	"privacy":     "Privacy violation",
+	"court_order": "Court or government order",
+	"safety":      "Public safety risk",
+	"illegal":     "Illegal activity or content",
+	"other":       "Other legal reason",
+}
+
+// Takedown is the record of a POI unpublished for a legal or safety reason.
+// The note and snapshot are for internal records and never served publicly.
+type Takedown struct {
+	TakedownID     uuid.UUID  `db:"takedown_id" json:"takedown_id"`
+	PoiID          uuid.UUID  `db:"poi_id" json:"poi_id"`
+	ReasonCode     string     `db:"reason_code" json:"reason_code"`
+	Note           *string    `db:"note" json:"note,omitempty"`
+	PreviousStatus string     `db:"previous_status" json:"previous_status"`
+	TakenDownBy    *uuid.UUID `db:"taken_down_by" json:"taken_down_by,omitempty"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+}
+
+// RecordTakedown stores a takedown with a snapshot of the POI as it was
+// published, and tells the owner in-app. Run it in the transaction that moves
+// the POI to taken_down.
+func (r *POIRepository) RecordTakedown(ctx context.Context, poiID uuid.UUID, reasonCode string, note *string, previousStatus string, takenDownBy uuid.UUID) (*Takedown, error) {
+	conn := r.db.Conn(ctx)
+
+	var takedown Takedown
+	err := conn.GetContext(ctx, &takedown, `
+		INSERT INTO poi_takedowns (poi_id, reason_code, note, previous_status, snapshot, taken_down_by)
+		SELECT p.poi_id, $2, $3, $4,
+		       jsonb_set(to_jsonb(p), '{status}', to_jsonb($4::text))
+		         || jsonb_build_object('address', to_jsonb(a), 'photos', COALESCE(ph.photos, '[]'::jsonb)),
+		       $5
+		FROM points_of_interest p
+		LEFT JOIN addresses a ON a.address_id = p.address_id
+		LEFT JOIN LATERAL (
+			SELECT jsonb_agg(to_jsonb(x) ORDER BY x.created_at) AS photos FROM photos x WHERE x.poi_id = p.poi_id
+		) ph ON true
+		WHERE p.poi_id = $1
+		RETURNING takedown_id, poi_id, reason_code, note, previous_status, taken_down_by, created_at
+	`, poiID, reasonCode, note, previousStatus, takenDownBy)
+	if err != nil {
+		return nil, fmt.Errorf("record takedown: %w", err)
+	}
+
+	_, err = conn.ExecContext(ctx, `
+		INSERT INTO user_notifications (user_id, type, title, body, data)
+		SELECT p.created_by, $2, 'Your place was taken down',
+		       p.name || ' is no longer public: ' || $3::text,
+		       jsonb_build_object('poi_id', p.poi_id, 'reason_code', $4::text)
+		FROM points_of_interest p
+		WHERE p.poi_id = $1 AND p.created_by IS NOT NULL
+	`, poiID, models.NotificationPOITakenDown, TakedownReasons[reasonCode], reasonCode)
+	if err != nil {
+		return nil, fmt.Errorf("notify takedown: %w", err)
+	}
+	return &takedown, nil
+}
+
+// GetTakedown returns the latest takedown of a POI, or nil if it was never taken down
+func (r *POIRepository) GetTakedown(ctx context.Context, poiID uuid.UUID) (*Takedown, error) {
+	var takedown Takedown
+	err := r.db.Conn(ctx).GetContext(ctx, &takedown, `
+		SELECT takedown_id, poi_id, reason_code, note, previous_status, taken_down_by, created_at
+		FROM poi_takedowns
+		WHERE poi_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, poiID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get takedown: %w", err)
+	}
+	return &takedown, nil
+}
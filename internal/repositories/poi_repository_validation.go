@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ValidationFacts is what the pre-approval checks need to know about a POI
+type ValidationFacts struct {
+	PoiID   uuid.UUID `db:"poi_id"`
+	Name    string    `db:"name"`
+	Status  string    `db:"status"`
+	Lat     *float64  `db:"lat"`
+	Lng     *float64  `db:"lng"`
+	Phone   *string   `db:"phone"`
+	Website *string   `db:"website"`
+	// Slug of the active service area containing the POI, if any
+	ServiceArea     *string `db:"service_area"`
+	HasServiceAreas bool    `db:"has_service_areas"`
+	Images          []ValidationImage
+}
+
+// ValidationImage is one cover, gallery or community photo of a POI, with the
+// dimensions of its processed asset when the URL points at one
+type ValidationImage struct {
+	URL        string  `db:"url" json:"url"`
+	Width      *int    `db:"width" json:"width,omitempty"`
+	Height     *int    `db:"height" json:"height,omitempty"`
+	Moderation *string `db:"moderation_status" json:"moderation_status,omitempty"`
+}
+
+// SimilarPOI is a nearby POI whose name resembles the one being validated
+type SimilarPOI struct {
+	PoiID          uuid.UUID `db:"poi_id" json:"poi_id"`
+	Name           string    `db:"name" json:"name"`
+	Status         string    `db:"status" json:"status"`
+	DistanceMeters float64   `db:"distance_meters" json:"distance_meters"`
+	Similarity     float64   `db:"similarity" json:"similarity"`
+}
+
+// GetValidationFacts loads the fields and images checked before approval
+func (r *POIRepository) GetValidationFacts(ctx context.Context, poiID uuid.UUID) (*ValidationFacts, error) {
+	var facts ValidationFacts
+	err := r.db.Conn(ctx).GetContext(ctx, &facts, `
+		SELECT p.poi_id, p.name, p.status, p.phone, p.website,
+		       ST_Y(p.location::geometry) AS lat, ST_X(p.location::geometry) AS lng,
+		       (SELECT sa.slug FROM service_areas sa
+		        WHERE sa.is_active AND ST_Within(p.location::geometry, sa.boundary)
+		        ORDER BY ST_Area(sa.boundary) LIMIT 1) AS service_area,
+		       EXISTS (SELECT 1 FROM service_areas WHERE is_active) AS has_service_areas
+		FROM points_of_interest p
+		WHERE p.poi_id = $1
+	`, poiID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Processed uploads are served as /img/<content hash>/<rendition>
+	err = r.db.Conn(ctx).SelectContext(ctx, &facts.Images, `
+		WITH urls AS (
+			SELECT cover_image_url AS url, 0 AS pos FROM points_of_interest
+			WHERE poi_id = $1 AND COALESCE(cover_image_url, '') <> ''
+			UNION
+			SELECT u.url, 1 FROM points_of_interest p, unnest(p.gallery_image_urls) AS u(url)
+			WHERE p.poi_id = $1
+			UNION
+			SELECT url, 2 FROM photos WHERE poi_id = $1
+		)
+		SELECT DISTINCT ON (urls.url) urls.url, a.original_width AS width, a.original_height AS height, a.moderation_status
+		FROM urls
+		LEFT JOIN image_assets a ON a.content_hash = substring(urls.url from '/img/([0-9a-f]{64})/')
+		ORDER BY urls.url, urls.pos
+	`, poiID)
+	if err != nil {
+		return nil, fmt.Errorf("get validation images: %w", err)
+	}
+	return &facts, nil
+}
+
+// FindSimilarNearby returns other POIs within radiusMeters whose name trigram
+// similarity is at least minSimilarity, most similar first
+func (r *POIRepository) FindSimilarNearby(ctx context.Context, poiID uuid.UUID, radiusMeters, minSimilarity float64) ([]SimilarPOI, error) {
+	similar := []SimilarPOI{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &similar, `
+		SELECT o.poi_id, o.name, o.status,
+		       ST_Distance(o.location, p.location) AS distance_meters,
+		       similarity(lower(o.name), lower(p.name)) AS similarity
+		FROM points_of_interest p
+		JOIN points_of_interest o
+		  ON o.poi_id <> p.poi_id
+		 AND o.status <> 'archived'
+		 AND ST_DWithin(o.location, p.location, $2)
+		WHERE p.poi_id = $1 AND p.location IS NOT NULL
+		  AND similarity(lower(o.name), lower(p.name)) >= $3
+		ORDER BY similarity DESC, distance_meters ASC
+		LIMIT 10
+	`, poiID, radiusMeters, minSimilarity)
+	if err != nil {
+		return nil, fmt.Errorf("find similar nearby pois: %w", err)
+	}
+	return similar, nil
+}
@@ -2,17 +2,16 @@ package repositories
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
-	"github.com/lib/pq"
 )
 
 // Create creates a new POI from input
 func (r *POIRepository) Create(ctx context.Context, input CreatePOIInput) (*POI, error) {
-	tx, err := r.db.BeginTxx(ctx, nil)
+	tx, err := r.db.BeginTx(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("begin tx: %w", err)
 	}
@@ -45,129 +44,39 @@ func (r *POIRepository) Create(ctx context.Context, input CreatePOIInput) (*POI,
 		addressID = &newAddrID
 	}
 
-	// Convert open_hours map to JSONB
-	var openHoursJSON interface{} = nil
-	if input.OpenHours != nil {
-		b, err := json.Marshal(input.OpenHours)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal open_hours: %w", err)
-		}
-		openHoursJSON = string(b)
-	}
-
-	// Convert social_links map to JSONB
-	var socialLinksJSON interface{} = nil
-	if input.SocialLinks != nil {
-		b, err := json.Marshal(input.SocialLinks)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal social_links: %w", err)
-		}
-		socialLinksJSON = string(b)
+	writes, err := poiContentWrites(input)
+	if err != nil {
+		return nil, err
 	}
+	columns, exprs, args := bindPOIWrites(writes, 1, false)
+	n := len(args)
+	args = append(args, input.InitialStatus, input.CreatedBy, input.FoundingUserID, addressID)
 
-	query := `
+	query := fmt.Sprintf(`
 		INSERT INTO points_of_interest (
-			name, brand, description, website, location,
-			floor_unit, public_transport,
-			cover_image_url, gallery_image_urls, category_ids,
-			amenities, has_wifi, outdoor_seating, is_wheelchair_accessible,
-			wifi_quality, power_outlets, seating_options, noise_level, has_ac,
-			vibes, crowd_type, lighting, music_type, cleanliness,
-			cuisine, price_range, food_options, dietary_options,
-			featured_menu_items, specials,
-			open_hours, reservation_required, reservation_platform,
-			payment_options, wait_time_estimate,
-			kids_friendly, pet_friendly, pet_policy, smoker_friendly,
-			happy_hour_info, loyalty_program,
-			phone, email, social_media_links,
-			status, created_by, parking_options,
-			founding_user_id, wifi_speed_mbps, ergonomic_seating, power_sockets_reach,
-			address_id
+			%s,
+			status, created_by, founding_user_id, address_id
 		) VALUES (
-			$1, $2, $3, $4,
-			ST_SetSRID(ST_MakePoint($5, $6), 4326)::geography,
-			$7, $8,
-			$9, $10, $11,
-			$12, $13, $14, $15,
-			$16, $17, $18, $19, $20,
-			$21, $22, $23, $24, $25,
-			$26, $27, $28, $29,
-			$30, $31,
-			$32, $33, $34,
-			$35, $36,
-			$37, $38, $39, $40,
-			$41, $42,
-			$43, $44, $45,
-			COALESCE($53, 'draft'), $46, $47,
-			$48, $49, $50, $51,
-			$52
+			%s,
+			COALESCE($%d, 'draft'), $%d, $%d, $%d
 		)
 		RETURNING poi_id, name, brand, description, status, created_by,
 		          is_verified, created_at, updated_at, founding_user_id,
 		          wifi_speed_mbps, ergonomic_seating, power_sockets_reach
-	`
+	`, strings.Join(columns, ", "), strings.Join(exprs, ", "), n+1, n+2, n+3, n+4)
 
 	var poi POI
-	err = tx.QueryRowxContext(
-		ctx,
-		query,
-		input.Name,
-		input.BrandName,
-		input.Description,
-		input.Website,
-		input.Longitude,
-		input.Latitude,
-		input.FloorUnit,
-		input.PublicTransport,
-		input.CoverImageURL,
-		pq.StringArray(input.GalleryImageURLs), pq.StringArray(input.CategoryIDs),
-		pq.StringArray([]string{}), // amenities (empty for now, specific fields used)
-		input.WifiQuality != nil && *input.WifiQuality != "" && *input.WifiQuality != "none",
-		len(input.SeatingOptions) > 0 && contains(input.SeatingOptions, "outdoor"),
-		input.WheelchairAccessible,
-		input.WifiQuality,
-		input.PowerOutlets,
-		pq.StringArray(input.SeatingOptions),
-		input.NoiseLevel,
-		input.HasAC,
-		pq.StringArray(input.Vibes),
-		pq.StringArray(input.CrowdType),
-		input.Lighting,
-		input.MusicType,
-		input.Cleanliness,
-		input.Cuisine,
-		input.PriceRange,
-		pq.StringArray(input.DietaryOptions), // food_options
-		pq.StringArray(input.DietaryOptions),
-		pq.StringArray(input.FeaturedItems),
-		pq.StringArray(input.Specials),
-		openHoursJSON,
-		input.ReservationRequired,
-		input.ReservationPlatform,
-		pq.StringArray(input.PaymentOptions),
-		input.WaitTimeEstimate,
-		input.KidsFriendly,
-		pq.StringArray(input.PetFriendly), input.PetPolicy,
-		input.SmokerFriendly,
-		input.HappyHourInfo,
-		input.LoyaltyProgram,
-		input.Phone,
-		input.Email,
-		socialLinksJSON,
-		input.CreatedBy,
-		pq.StringArray(input.ParkingOptions),
-		input.FoundingUserID,
-		input.WifiSpeedMbps,
-		input.ErgonomicSeating,
-		input.PowerSocketsReach,
-		addressID,
-		input.InitialStatus,
-	).StructScan(&poi)
-
+	err = tx.QueryRowxContext(ctx, query, args...).StructScan(&poi)
 	if err != nil {
 		return nil, fmt.Errorf("create poi query: %w", err)
 	}
 
+	if input.Amenities != nil {
+		if err := setPOIAmenities(ctx, tx, poi.PoiID, input.Amenities); err != nil {
+			return nil, err
+		}
+	}
+
 	// Sync photos to dedicated table
 	if len(input.GalleryImageURLs) > 0 {
 		if err := r.syncPhotos(ctx, tx, poi.PoiID, input.GalleryImageURLs); err != nil {
@@ -182,7 +91,8 @@ func (r *POIRepository) Create(ctx context.Context, input CreatePOIInput) (*POI,
 	return &poi, nil
 }
 
-// Update updates specific fields of a POI
+// Update updates specific fields of a POI. Non-empty amenities replace the
+// POI's amenity tags.
 func (r *POIRepository) Update(ctx context.Context, poiID uuid.UUID, input UpdatePOIInput) error {
 	query := `
 		UPDATE points_of_interest SET
@@ -191,149 +101,77 @@ func (r *POIRepository) Update(ctx context.Context, poiID uuid.UUID, input Updat
 			has_wifi = COALESCE($4, has_wifi),
 			outdoor_seating = COALESCE($5, outdoor_seating),
 			price_range = COALESCE($6, price_range),
-			amenities = COALESCE($7, amenities),
 			updated_at = NOW()
 		WHERE poi_id = $1
 	`
 
-	var amenities interface{}
-	if len(input.Amenities) > 0 {
-		amenities = pq.StringArray(input.Amenities)
-	}
-
-	_, err := r.db.ExecContext(
-		ctx,
-		query,
-		poiID,
-		input.Name,
-		input.Description,
-		input.HasWifi,
-		input.OutdoorSeating,
-		input.PriceRange,
-		amenities,
-	)
-
-	if err != nil {
-		return fmt.Errorf("update poi: %w", err)
-	}
-	return nil
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		_, err := r.db.Conn(ctx).ExecContext(
+			ctx,
+			query,
+			poiID,
+			input.Name,
+			input.Description,
+			input.HasWifi,
+			input.OutdoorSeating,
+			input.PriceRange,
+		)
+		if err != nil {
+			return fmt.Errorf("update poi: %w", err)
+		}
+		if len(input.Amenities) > 0 {
+			return setPOIAmenities(ctx, r.db.Conn(ctx), poiID, input.Amenities)
+		}
+		return nil
+	})
 }
 
 // Delete deletes a POI by ID
 func (r *POIRepository) Delete(ctx context.Context, poiID uuid.UUID) error {
 	query := `DELETE FROM points_of_interest WHERE poi_id = $1`
-	_, err := r.db.ExecContext(ctx, query, poiID)
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query, poiID)
 	if err != nil {
 		return fmt.Errorf("delete poi: %w", err)
 	}
 	return nil
 }
 
-// UpdateFull updates all fields of a POI
-func (r *POIRepository) UpdateFull(ctx context.Context, poiID uuid.UUID, input UpdateFullInput) error {
-	tx, err := r.db.BeginTxx(ctx, nil)
+// UpdateFull updates all fields of a POI, recording every section as edited by editedBy
+func (r *POIRepository) UpdateFull(ctx context.Context, poiID uuid.UUID, input UpdateFullInput, editedBy *uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx)
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
 	}
 	defer tx.Rollback()
 
-	// Convert open_hours map to JSONB
-	var openHoursJSON interface{} = nil
-	if input.OpenHours != nil {
-		b, err := json.Marshal(input.OpenHours)
-		if err != nil {
-			return fmt.Errorf("failed to marshal open_hours: %w", err)
-		}
-		// Explicitly cast to string to ensure driver treats it as text/json
-		openHoursJSON = string(b)
+	writes, err := poiContentWrites(input)
+	if err != nil {
+		return err
 	}
-
-	// Convert social_links map to JSONB
-	var socialLinksJSON interface{} = nil
-	if input.SocialLinks != nil {
-		b, err := json.Marshal(input.SocialLinks)
-		if err != nil {
-			return fmt.Errorf("failed to marshal social_links: %w", err)
-		}
-		socialLinksJSON = string(b)
+	columns, exprs, args := bindPOIWrites(writes, 2, true)
+	sets := make([]string, len(columns))
+	for i, column := range columns {
+		sets[i] = column + " = " + exprs[i]
 	}
 
 	query := `
 		UPDATE points_of_interest SET
-			name = $2, brand = $3, description = $4, website = $5,
-			location = ST_SetSRID(ST_MakePoint($6, $7), 4326)::geography,
-			floor_unit = $8, public_transport = $9,
-			cover_image_url = $10, gallery_image_urls = $11,
-			amenities = $12, has_wifi = $13, outdoor_seating = $14, is_wheelchair_accessible = $15,
-			wifi_quality = $16, power_outlets = $17, seating_options = $18, noise_level = $19, has_ac = $20,
-			vibes = $21, crowd_type = $22, lighting = $23, music_type = $24, cleanliness = $25,
-			cuisine = $26, price_range = $27, food_options = $28, dietary_options = $29,
-			featured_menu_items = $30, specials = $31,
-			open_hours = $32, reservation_required = $33, reservation_platform = $34,
-			payment_options = $35, wait_time_estimate = $36,
-			kids_friendly = $37, pet_friendly = $38, smoker_friendly = $39,
-			happy_hour_info = $40, loyalty_program = $41,
-			phone = $42, email = $43, social_media_links = $44,
-			wifi_speed_mbps = $45, ergonomic_seating = $46, power_sockets_reach = $47,
+			` + strings.Join(sets, ",\n\t\t\t") + `,
 			updated_at = NOW()
 		WHERE poi_id = $1
 	`
-
-	_, err = tx.ExecContext(
-		ctx,
-		query,
-		poiID,
-		input.Name,
-		input.BrandName,
-		input.Description,
-		input.Website,
-		input.Longitude,
-		input.Latitude,
-		input.FloorUnit,
-		input.PublicTransport,
-		input.CoverImageURL,
-		pq.StringArray(input.GalleryImageURLs),
-		pq.StringArray(input.ParkingOptions), // amenities
-		input.WifiQuality != nil && *input.WifiQuality != "" && *input.WifiQuality != "none",
-		len(input.SeatingOptions) > 0 && contains(input.SeatingOptions, "outdoor"),
-		input.WheelchairAccessible,
-		input.WifiQuality,
-		input.PowerOutlets,
-		pq.StringArray(input.SeatingOptions),
-		input.NoiseLevel,
-		input.HasAC,
-		pq.StringArray(input.Vibes),
-		pq.StringArray(input.CrowdType),
-		input.Lighting,
-		input.MusicType,
-		input.Cleanliness,
-		input.Cuisine,
-		input.PriceRange,
-		pq.StringArray(input.DietaryOptions), // food_options
-		pq.StringArray(input.DietaryOptions),
-		pq.StringArray(input.FeaturedItems),
-		pq.StringArray(input.Specials),
-		openHoursJSON,
-		input.ReservationRequired,
-		input.ReservationPlatform,
-		pq.StringArray(input.PaymentOptions),
-		input.WaitTimeEstimate,
-		input.KidsFriendly,
-		pq.StringArray(input.PetFriendly),
-		input.SmokerFriendly,
-		input.HappyHourInfo,
-		input.LoyaltyProgram,
-		input.Phone,
-		input.Email,
-		socialLinksJSON,
-		input.WifiSpeedMbps,
-		input.ErgonomicSeating,
-		input.PowerSocketsReach,
-	)
-
+	_, err = tx.ExecContext(ctx, query, append([]interface{}{poiID}, args...)...)
 	if err != nil {
 		return fmt.Errorf("update full poi: %w", err)
 	}
+	if err := r.recordSectionEdits(ctx, tx, poiID, poiSections, editedBy); err != nil {
+		return err
+	}
+	if input.Amenities != nil {
+		if err := setPOIAmenities(ctx, tx, poiID, input.Amenities); err != nil {
+			return err
+		}
+	}
 
 	// Sync photos to dedicated table
 	if len(input.GalleryImageURLs) > 0 {
@@ -372,7 +210,7 @@ func (r *POIRepository) syncPhotos(ctx context.Context, q sqlx.ExtContext, poiID
 	return nil
 }
 
-// UpdateStatus updates the status of a POI
+// UpdateStatus updates the status of a POI. Leaving pending ends any reviewer assignment.
 func (r *POIRepository) UpdateStatus(ctx context.Context, poiID uuid.UUID, status string, rejectedReason *string) error {
 	var query string
 	var args []interface{}
@@ -381,16 +219,48 @@ func (r *POIRepository) UpdateStatus(ctx context.Context, poiID uuid.UUID, statu
 		query = `UPDATE points_of_interest SET status = $2, submitted_at = NOW(), updated_at = NOW() WHERE poi_id = $1`
 		args = []interface{}{poiID, status}
 	} else if status == "rejected" {
-		query = `UPDATE points_of_interest SET status = $2, rejected_reason = $3, updated_at = NOW() WHERE poi_id = $1`
+		query = `UPDATE points_of_interest SET status = $2, rejected_reason = $3, assigned_to = NULL, assignment_expires_at = NULL, updated_at = NOW() WHERE poi_id = $1`
 		args = []interface{}{poiID, status, rejectedReason}
+	} else if status == "approved" {
+		// Keep the first approval so re-approving an archived POI does not make it new again
+		query = `UPDATE points_of_interest SET status = $2, approved_at = COALESCE(approved_at, NOW()), assigned_to = NULL, assignment_expires_at = NULL, updated_at = NOW() WHERE poi_id = $1`
+		args = []interface{}{poiID, status}
 	} else {
-		query = `UPDATE points_of_interest SET status = $2, updated_at = NOW() WHERE poi_id = $1`
+		query = `UPDATE points_of_interest SET status = $2, assigned_to = NULL, assignment_expires_at = NULL, updated_at = NOW() WHERE poi_id = $1`
 		args = []interface{}{poiID, status}
 	}
 
-	_, err := r.db.ExecContext(ctx, query, args...)
+	if status == "approved" || status == "rejected" {
+		if err := r.recordReviewDecision(ctx, poiID, status); err != nil {
+			return err
+		}
+	}
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("update status: %w", err)
 	}
+
+	// Rejection feedback lasts until its section is changed and resubmitted,
+	// or the POI is approved as it is
+	switch status {
+	case "pending":
+		return r.clearResolvedFeedback(ctx, poiID)
+	case "approved":
+		return r.ReplaceRejectionFeedback(ctx, poiID, nil, nil)
+	}
 	return nil
 }
+
+// GetStatusForUpdate returns the current status of a POI and locks the row
+// for the rest of the surrounding transaction. Returns sql.ErrNoRows (wrapped) if missing.
+func (r *POIRepository) GetStatusForUpdate(ctx context.Context, poiID uuid.UUID) (string, error) {
+	var status string
+	err := r.db.Conn(ctx).QueryRowContext(ctx,
+		`SELECT status FROM points_of_interest WHERE poi_id = $1 FOR UPDATE`, poiID,
+	).Scan(&status)
+	if err != nil {
+		return "", fmt.Errorf("get poi status: %w", err)
+	}
+	return status, nil
+}
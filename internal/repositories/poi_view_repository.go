@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+)
+
+const (
+	// trendingWindow is how far back views and saves count towards trending
+	trendingWindow = 7 * 24 * time.Hour
+	// trendingHalfLife is the age at which a view or save weighs half as much
+	trendingHalfLife = 48 * time.Hour
+	// trendingSaveWeight is how many views a save is worth
+	trendingSaveWeight = 3
+	// trendingTTL bounds how long a trending list is served from memory
+	trendingTTL = 10 * time.Minute
+	// TrendingMaxLimit is the longest trending list that can be requested
+	TrendingMaxLimit = 50
+)
+
+type cachedTrending struct {
+	pois      []models.TrendingPOI
+	expiresAt time.Time
+}
+
+// POIViewRepository stores POI detail views and ranks trending POIs
+type POIViewRepository struct {
+	db *database.DB
+
+	mu       sync.RWMutex
+	trending map[string]cachedTrending
+}
+
+// NewPOIViewRepository creates a new POI view repository
+func NewPOIViewRepository(db *database.DB) *POIViewRepository {
+	return &POIViewRepository{db: db, trending: make(map[string]cachedTrending)}
+}
+
+// InsertPOIViews stores a batch of views, ignoring ones already recorded
+func (r *POIViewRepository) InsertPOIViews(ctx context.Context, views []models.POIView) error {
+	payload, err := json.Marshal(views)
+	if err != nil {
+		return fmt.Errorf("encode poi views: %w", err)
+	}
+	_, err = r.db.Conn(ctx).ExecContext(ctx, `
+		INSERT INTO poi_views (poi_id, viewed_hour, viewer_hash)
+		SELECT v.poi_id, v.viewed_hour, v.viewer_hash
+		FROM jsonb_to_recordset($1::jsonb) AS v(poi_id UUID, viewed_hour TIMESTAMPTZ, viewer_hash VARCHAR(32))
+		WHERE EXISTS (SELECT 1 FROM points_of_interest p WHERE p.poi_id = v.poi_id)
+		ON CONFLICT DO NOTHING
+	`, payload)
+	if err != nil {
+		return fmt.Errorf("insert poi views: %w", err)
+	}
+	return nil
+}
+
+// PurgePOIViews deletes views recorded before a time
+func (r *POIViewRepository) PurgePOIViews(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.Conn(ctx).ExecContext(ctx, `DELETE FROM poi_views WHERE viewed_hour < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("purge poi views: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// Trending ranks approved POIs by their views and saves over the past week,
+// each weighted down exponentially with age. area optionally restricts the
+// list to a service area slug. Lists are cached for a few minutes per area.
+func (r *POIViewRepository) Trending(ctx context.Context, area string, limit int) ([]models.TrendingPOI, error) {
+	limit = min(max(limit, 1), TrendingMaxLimit)
+
+	r.mu.RLock()
+	cached, ok := r.trending[area]
+	r.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.pois[:min(limit, len(cached.pois))], nil
+	}
+
+	if area != "" {
+		var exists bool
+		err := r.db.Conn(ctx).GetContext(ctx, &exists,
+			`SELECT EXISTS (SELECT 1 FROM service_areas WHERE slug = $1 AND is_active)`, area)
+		if err != nil {
+			return nil, fmt.Errorf("check service area: %w", err)
+		}
+		if !exists {
+			return nil, ErrServiceAreaNotFound
+		}
+	}
+
+	since := time.Now().Add(-trendingWindow)
+	halfLifeHours := trendingHalfLife.Hours()
+	pois := []models.TrendingPOI{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &pois, `
+		WITH views AS (
+			SELECT poi_id, COUNT(*) AS views,
+			       SUM(EXP(-LN(2) * EXTRACT(EPOCH FROM NOW() - viewed_hour) / 3600 / $2)) AS weight
+			FROM poi_views
+			WHERE viewed_hour >= $1
+			GROUP BY poi_id
+		), saves AS (
+			SELECT poi_id, COUNT(*) AS saves,
+			       SUM(EXP(-LN(2) * EXTRACT(EPOCH FROM NOW() - created_at) / 3600 / $2)) AS weight
+			FROM saved_pois
+			WHERE created_at >= $1
+			GROUP BY poi_id
+		)
+		SELECT p.poi_id, p.name, p.cover_image_url, p.price_range, p.rating_avg, p.reviews_count,
+		       COALESCE(v.views, 0) AS views, COALESCE(s.saves, 0) AS saves,
+		       (COALESCE(v.weight, 0) + $3 * COALESCE(s.weight, 0))::float8 AS score
+		FROM (SELECT poi_id FROM views UNION SELECT poi_id FROM saves) t
+		JOIN points_of_interest p ON p.poi_id = t.poi_id
+		LEFT JOIN views v ON v.poi_id = t.poi_id
+		LEFT JOIN saves s ON s.poi_id = t.poi_id
+		WHERE p.status = 'approved'
+		  AND ($4 = '' OR EXISTS (
+		      SELECT 1 FROM service_areas sa
+		      WHERE sa.slug = $4 AND ST_Within(p.location::geometry, sa.boundary)))
+		ORDER BY score DESC, p.poi_id
+		LIMIT $5
+	`, since, halfLifeHours, trendingSaveWeight, area, TrendingMaxLimit)
+	if err != nil {
+		return nil, fmt.Errorf("get trending pois: %w", err)
+	}
+
+	r.mu.Lock()
+	r.trending[area] = cachedTrending{pois: pois, expiresAt: time.Now().Add(trendingTTL)}
+	r.mu.Unlock()
+	return pois[:min(limit, len(pois))], nil
+}
@@ -0,0 +1,252 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrProfileNotFound is returned when a user has no visible public profile
+	ErrProfileNotFound = errors.New("profile not found")
+	// ErrUsernameTaken is returned when another user holds the username in any case
+	ErrUsernameTaken = errors.New("username is already taken")
+	// ErrUsernameCooldown is returned when the username changed too recently
+	ErrUsernameCooldown = errors.New("username was changed recently")
+)
+
+const (
+	// RecentContributionsLimit caps the contributions listed on a public profile
+	RecentContributionsLimit = 10
+	// UsernameChangeCooldown is the minimum time between two username changes
+	UsernameChangeCooldown = 30 * 24 * time.Hour
+)
+
+// UpdateProfilePrivacyInput changes the given privacy switches; nil fields are kept
+type UpdateProfilePrivacyInput struct {
+	ProfilePublic     *bool `json:"profile_public"`
+	ShowStats         *bool `json:"show_stats"`
+	ShowContributions *bool `json:"show_contributions"`
+}
+
+// ProfileRepository handles public user profiles and their privacy settings
+type ProfileRepository struct {
+	db *database.DB
+}
+
+// NewProfileRepository creates a new profile repository
+func NewProfileRepository(db *database.DB) *ProfileRepository {
+	return &ProfileRepository{db: db}
+}
+
+// Users without a profile row are public with everything shown
+const privacyColumns = `
+	COALESCE(p.profile_public, TRUE) AS profile_public,
+	COALESCE(p.show_stats, TRUE) AS show_stats,
+	COALESCE(p.show_contributions, TRUE) AS show_contributions`
+
+// GetPublicProfile returns the profile of a live user as seen by viewer (nil
+// when signed out). Private profiles, and shadow-banned users, are only
+// visible to the user themselves; hidden sections are left out for others.
+func (r *ProfileRepository) GetPublicProfile(ctx context.Context, userID uuid.UUID, viewer *uuid.UUID) (*models.PublicProfile, error) {
+	isOwner := viewer != nil && *viewer == userID
+
+	var row struct {
+		models.PublicProfile
+		models.ProfilePrivacy
+		ShadowBanned bool `db:"shadow_banned"`
+	}
+	err := r.db.Conn(ctx).GetContext(ctx, &row, `
+		SELECT u.user_id, p.username, u.name,
+		       COALESCE(p.avatar_url, u.picture_url) AS avatar_url,
+		       COALESCE(p.scout_level, 1) AS scout_level,
+		       u.created_at AS member_since,
+		       u.shadow_banned,`+privacyColumns+`
+		FROM users u
+		LEFT JOIN user_profiles p ON p.user_id = u.user_id
+		WHERE u.user_id = $1 AND u.deleted_at IS NULL
+	`, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrProfileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get public profile: %w", err)
+	}
+	if !isOwner && (!row.ProfilePublic || row.ShadowBanned) {
+		return nil, ErrProfileNotFound
+	}
+
+	profile := row.PublicProfile
+	if isOwner {
+		privacy := row.ProfilePrivacy
+		profile.Privacy = &privacy
+	}
+	if isOwner || row.ShowStats {
+		var stats models.ContributionStats
+		err := r.db.Conn(ctx).GetContext(ctx, &stats, `
+			SELECT
+				(SELECT COUNT(*) FROM points_of_interest WHERE created_by = $1 AND status = 'approved') AS approved_pois,
+				(SELECT COUNT(*) FROM photos ph JOIN points_of_interest p ON p.poi_id = ph.poi_id
+				 WHERE ph.user_id = $1 AND p.status = 'approved') AS photos,
+				(SELECT COUNT(*) FROM reviews rv JOIN points_of_interest p ON p.poi_id = rv.poi_id
+				 WHERE rv.user_id = $1 AND p.status = 'approved') AS reviews
+		`, userID)
+		if err != nil {
+			return nil, fmt.Errorf("get contribution stats: %w", err)
+		}
+		profile.Stats = &stats
+	}
+	if isOwner || row.ShowContributions {
+		profile.RecentContributions = []models.Contribution{}
+		err := r.db.Conn(ctx).SelectContext(ctx, &profile.RecentContributions, `
+			SELECT * FROM (
+				SELECT 'poi' AS type, p.poi_id AS id, p.poi_id, p.name AS poi_name, p.created_at
+				FROM points_of_interest p
+				WHERE p.created_by = $1 AND p.status = 'approved'
+				UNION ALL
+				SELECT 'photo', ph.photo_id, p.poi_id, p.name, ph.created_at
+				FROM photos ph JOIN points_of_interest p ON p.poi_id = ph.poi_id
+				WHERE ph.user_id = $1 AND p.status = 'approved'
+				UNION ALL
+				SELECT 'review', rv.review_id, p.poi_id, p.name, rv.created_at
+				FROM reviews rv JOIN points_of_interest p ON p.poi_id = rv.poi_id
+				WHERE rv.user_id = $1 AND p.status = 'approved'
+			) c
+			ORDER BY created_at DESC
+			LIMIT $2
+		`, userID, RecentContributionsLimit)
+		if err != nil {
+			return nil, fmt.Errorf("list recent contributions: %w", err)
+		}
+	}
+	return &profile, nil
+}
+
+// GetPrivacy returns a user's profile privacy settings
+func (r *ProfileRepository) GetPrivacy(ctx context.Context, userID uuid.UUID) (*models.ProfilePrivacy, error) {
+	var privacy models.ProfilePrivacy
+	err := r.db.Conn(ctx).GetContext(ctx, &privacy, `
+		SELECT`+privacyColumns+`
+		FROM (SELECT $1::uuid AS user_id) me
+		LEFT JOIN user_profiles p ON p.user_id = me.user_id
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get profile privacy: %w", err)
+	}
+	return &privacy, nil
+}
+
+// UpdatePrivacy changes a user's profile privacy settings and returns the result
+func (r *ProfileRepository) UpdatePrivacy(ctx context.Context, userID uuid.UUID, input UpdateProfilePrivacyInput) (*models.ProfilePrivacy, error) {
+	var privacy models.ProfilePrivacy
+	err := r.db.Conn(ctx).GetContext(ctx, &privacy, `
+		INSERT INTO user_profiles (user_id, profile_public, show_stats, show_contributions)
+		VALUES ($1, COALESCE($2, TRUE), COALESCE($3, TRUE), COALESCE($4, TRUE))
+		ON CONFLICT (user_id) DO UPDATE SET
+			profile_public = COALESCE($2, user_profiles.profile_public),
+			show_stats = COALESCE($3, user_profiles.show_stats),
+			show_contributions = COALESCE($4, user_profiles.show_contributions),
+			updated_at = NOW()
+		RETURNING profile_public, show_stats, show_contributions
+	`, userID, input.ProfilePublic, input.ShowStats, input.ShowContributions)
+	if err != nil {
+		return nil, fmt.Errorf("update profile privacy: %w", err)
+	}
+	return &privacy, nil
+}
+
+// ResolveUsername returns the user holding a username, matched case-insensitively
+func (r *ProfileRepository) ResolveUsername(ctx context.Context, username string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.db.Conn(ctx).GetContext(ctx, &userID, `
+		SELECT user_id FROM user_profiles WHERE LOWER(username) = LOWER($1)
+	`, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, ErrProfileNotFound
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("resolve username: %w", err)
+	}
+	return userID, nil
+}
+
+// SetUsername changes a user's username. Setting the current username again is
+// a no-op; a change within UsernameChangeCooldown of the last one fails with
+// ErrUsernameCooldown and returns the current username with its next change time.
+func (r *ProfileRepository) SetUsername(ctx context.Context, userID uuid.UUID, username string) (*models.Username, error) {
+	var result models.Username
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		conn := r.db.Conn(ctx)
+		var current struct {
+			Username  *string    `db:"username"`
+			ChangedAt *time.Time `db:"username_changed_at"`
+		}
+		err := conn.GetContext(ctx, &current, `
+			SELECT username, username_changed_at FROM user_profiles WHERE user_id = $1 FOR UPDATE
+		`, userID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		if current.Username != nil && *current.Username == username {
+			result = models.Username{Username: username, ChangedAt: current.ChangedAt}
+			return nil
+		}
+		if current.ChangedAt != nil {
+			next := current.ChangedAt.Add(UsernameChangeCooldown)
+			if time.Now().Before(next) {
+				result = models.Username{ChangedAt: current.ChangedAt, NextChangeAt: &next}
+				if current.Username != nil {
+					result.Username = *current.Username
+				}
+				return ErrUsernameCooldown
+			}
+		}
+
+		var taken bool
+		err = conn.GetContext(ctx, &taken, `
+			SELECT EXISTS (SELECT 1 FROM user_profiles WHERE LOWER(username) = LOWER($2) AND user_id <> $1)
+		`, userID, username)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrUsernameTaken
+		}
+
+		err = conn.GetContext(ctx, &result, `
+			INSERT INTO user_profiles (user_id, username, username_changed_at)
+			VALUES ($1, $2, NOW())
+			ON CONFLICT (user_id) DO UPDATE SET
+				username = EXCLUDED.username, username_changed_at = NOW(), updated_at = NOW()
+			RETURNING username, username_changed_at
+		`, userID, username)
+		// Lost a race with another user claiming the same name
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return ErrUsernameTaken
+		}
+		return err
+	})
+	switch {
+	case errors.Is(err, ErrUsernameCooldown):
+		return &result, err
+	case errors.Is(err, ErrUsernameTaken):
+		return nil, err
+	case err != nil:
+		return nil, fmt.Errorf("set username: %w", err)
+	}
+	if result.ChangedAt != nil {
+		next := result.ChangedAt.Add(UsernameChangeCooldown)
+		result.NextChangeAt = &next
+	}
+	return &result, nil
+}
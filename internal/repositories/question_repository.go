@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrQuestionNotFound is returned when a question does not exist on the POI
+	ErrQuestionNotFound = errors.New("question not found")
+	// ErrAnswerNotFound is returned when an answer does not belong to the question
+	ErrAnswerNotFound = errors.New("answer not found")
+)
+
+// QuestionRepository handles the questions and answers of POIs
+type QuestionRepository struct {
+	db *database.DB
+}
+
+// NewQuestionRepository creates a new question repository
+func NewQuestionRepository(db *database.DB) *QuestionRepository {
+	return &QuestionRepository{db: db}
+}
+
+// answerColumns selects an answer joined with its question (q), POI (p) and author (u)
+const answerColumns = `a.answer_id, a.question_id, a.user_id, a.body, a.created_at,
+	COALESCE(a.user_id IN (p.created_by, p.founding_user_id), false) AS from_owner,
+	COALESCE(q.accepted_answer_id = a.answer_id, false) AS accepted,
+	u.name AS user_name`
+
+// CreateQuestion stores a new question, filling in its ID and timestamps
+func (r *QuestionRepository) CreateQuestion(ctx context.Context, q *models.Question) error {
+	err := r.db.Conn(ctx).QueryRowContext(ctx, `
+		INSERT INTO poi_questions (poi_id, user_id, body)
+		VALUES ($1, $2, $3)
+		RETURNING question_id, created_at, updated_at
+	`, q.PoiID, q.UserID, q.Body).Scan(&q.QuestionID, &q.CreatedAt, &q.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("create question: %w", err)
+	}
+	q.Answers = []models.Answer{}
+	return nil
+}
+
+// GetQuestion returns a question of a POI without its answers
+func (r *QuestionRepository) GetQuestion(ctx context.Context, poiID, questionID uuid.UUID) (*models.Question, error) {
+	var q models.Question
+	err := r.db.Conn(ctx).GetContext(ctx, &q, `
+		SELECT q.*, u.name AS user_name
+		FROM poi_questions q
+		JOIN users u ON u.user_id = q.user_id
+		WHERE q.question_id = $1 AND q.poi_id = $2
+	`, questionID, poiID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrQuestionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get question: %w", err)
+	}
+	return &q, nil
+}
+
+// ListQuestions returns the newest questions of a POI with their answers. The
+// accepted answer comes first, then the owner's, then the rest oldest first.
+// Shadow-banned and blocked authors are hidden as for comments.
+func (r *QuestionRepository) ListQuestions(ctx context.Context, poiID uuid.UUID, viewerID *uuid.UUID, limit, offset int) ([]models.Question, error) {
+	questions := []models.Question{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &questions, `
+		SELECT q.*, u.name AS user_name
+		FROM poi_questions q
+		JOIN users u ON u.user_id = q.user_id
+		WHERE q.poi_id = $1 AND `+visibleAuthor("$4")+`
+		ORDER BY q.created_at DESC
+		LIMIT $2 OFFSET $3
+	`, poiID, limit, offset, viewerID)
+	if err != nil {
+		return nil, fmt.Errorf("list questions: %w", err)
+	}
+	if len(questions) == 0 {
+		return questions, nil
+	}
+
+	ids := make([]uuid.UUID, len(questions))
+	for i, q := range questions {
+		ids[i] = q.QuestionID
+	}
+	var answers []models.Answer
+	err = r.db.Conn(ctx).SelectContext(ctx, &answers, `
+		SELECT `+answerColumns+`
+		FROM poi_answers a
+		JOIN poi_questions q ON q.question_id = a.question_id
+		JOIN points_of_interest p ON p.poi_id = q.poi_id
+		JOIN users u ON u.user_id = a.user_id
+		WHERE a.question_id = ANY($1::uuid[]) AND `+visibleAuthor("$2")+`
+		ORDER BY accepted DESC, from_owner DESC, a.created_at
+	`, pq.Array(ids), viewerID)
+	if err != nil {
+		return nil, fmt.Errorf("list answers: %w", err)
+	}
+
+	byQuestion := make(map[uuid.UUID][]models.Answer, len(questions))
+	for _, a := range answers {
+		byQuestion[a.QuestionID] = append(byQuestion[a.QuestionID], a)
+	}
+	for i := range questions {
+		questions[i].Answers = byQuestion[questions[i].QuestionID]
+		if questions[i].Answers == nil {
+			questions[i].Answers = []models.Answer{}
+		}
+	}
+	return questions, nil
+}
+
+// CreateAnswer stores an answer to a question and returns it with its derived fields
+func (r *QuestionRepository) CreateAnswer(ctx context.Context, questionID, userID uuid.UUID, body string) (*models.Answer, error) {
+	var answer models.Answer
+	err := r.db.Conn(ctx).GetContext(ctx, &answer, `
+		WITH a AS (
+			INSERT INTO poi_answers (question_id, user_id, body)
+			VALUES ($1, $2, $3)
+			RETURNING *
+		)
+		SELECT `+answerColumns+`
+		FROM a
+		JOIN poi_questions q ON q.question_id = a.question_id
+		JOIN points_of_interest p ON p.poi_id = q.poi_id
+		JOIN users u ON u.user_id = a.user_id
+	`, questionID, userID, body)
+	if err != nil {
+		return nil, fmt.Errorf("create answer: %w", err)
+	}
+	return &answer, nil
+}
+
+// AcceptAnswer marks an answer as the accepted one of its question, replacing
+// any earlier choice, and returns it
+func (r *QuestionRepository) AcceptAnswer(ctx context.Context, questionID, answerID uuid.UUID) (*models.Answer, error) {
+	var answer models.Answer
+	err := r.db.Conn(ctx).GetContext(ctx, &answer, `
+		WITH q AS (
+			UPDATE poi_questions
+			SET accepted_answer_id = $2, updated_at = NOW()
+			WHERE question_id = $1
+			  AND EXISTS (SELECT 1 FROM poi_answers WHERE answer_id = $2 AND question_id = $1)
+			RETURNING *
+		)
+		SELECT `+answerColumns+`
+		FROM q
+		JOIN poi_answers a ON a.answer_id = q.accepted_answer_id
+		JOIN points_of_interest p ON p.poi_id = q.poi_id
+		JOIN users u ON u.user_id = a.user_id
+	`, questionID, answerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAnswerNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("accept answer: %w", err)
+	}
+	return &answer, nil
+}
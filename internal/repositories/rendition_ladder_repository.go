@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"maukemana-backend/internal/imaging"
+
+	"github.com/google/uuid"
+)
+
+// ladderCategorySQL maps an asset's category to the ladder it is rendered
+// with, as imaging.LadderCategory does
+const ladderCategorySQL = `CASE WHEN a.category IN ('profile', 'cover', 'gallery') THEN a.category ELSE 'general' END`
+
+// outdatedLadderSQL matches assets rendered with another version of their
+// ladder than the current one. Categories without a stored ladder are on the
+// compiled-in version 1.
+const outdatedLadderSQL = `a.ladder_version <> COALESCE((SELECT l.version FROM rendition_ladders l WHERE l.category = ` + ladderCategorySQL + `), 1)`
+
+const renditionLadderColumns = `category, version, renditions, updated_by, updated_at`
+
+type renditionLadderRow struct {
+	Category   string     `db:"category"`
+	Version    int        `db:"version"`
+	Renditions []byte     `db:"renditions"`
+	UpdatedBy  *uuid.UUID `db:"updated_by"`
+	UpdatedAt  time.Time  `db:"updated_at"`
+}
+
+func (row renditionLadderRow) ladder() (imaging.Ladder, error) {
+	l := imaging.Ladder{Category: row.Category, Version: row.Version, UpdatedBy: row.UpdatedBy, UpdatedAt: &row.UpdatedAt}
+	if row.Renditions == nil {
+		return l, nil
+	}
+	if err := json.Unmarshal(row.Renditions, &l.Renditions); err != nil {
+		return l, fmt.Errorf("decode %s ladder: %w", row.Category, err)
+	}
+	l.Override = true
+	return l, nil
+}
+
+// ListRenditionLadders returns the stored rendition ladders. Ladders that were
+// reset to the compiled-in ones have no renditions.
+func (r *ImagingRepository) ListRenditionLadders(ctx context.Context) ([]imaging.Ladder, error) {
+	var rows []renditionLadderRow
+	if err := r.db.Conn(ctx).SelectContext(ctx, &rows, `SELECT `+renditionLadderColumns+` FROM rendition_ladders ORDER BY category`); err != nil {
+		return nil, fmt.Errorf("list rendition ladders: %w", err)
+	}
+	ladders := make([]imaging.Ladder, 0, len(rows))
+	for _, row := range rows {
+		l, err := row.ladder()
+		if err != nil {
+			return nil, fmt.Errorf("list rendition ladders: %w", err)
+		}
+		ladders = append(ladders, l)
+	}
+	return ladders, nil
+}
+
+// SaveRenditionLadder stores the ladder of category under the next version.
+// Nil renditions reset it to the compiled-in ladder, which also takes a new
+// version so assets rendered with the override count as outdated.
+func (r *ImagingRepository) SaveRenditionLadder(ctx context.Context, category string, renditions []imaging.RenditionConfig, updatedBy *uuid.UUID) (*imaging.Ladder, error) {
+	var payload *string
+	if renditions != nil {
+		b, err := json.Marshal(renditions)
+		if err != nil {
+			return nil, fmt.Errorf("encode rendition ladder: %w", err)
+		}
+		s := string(b)
+		payload = &s
+	}
+
+	var row renditionLadderRow
+	err := r.db.Conn(ctx).GetContext(ctx, &row, `
+		INSERT INTO rendition_ladders (category, version, renditions, updated_by)
+		VALUES ($1, $2, $3::jsonb, $4)
+		ON CONFLICT (category) DO UPDATE
+		SET version = rendition_ladders.version + 1,
+		    renditions = EXCLUDED.renditions,
+		    updated_by = EXCLUDED.updated_by,
+		    updated_at = NOW()
+		RETURNING `+renditionLadderColumns, category, imaging.DefaultLadderVersion+1, payload, updatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("save rendition ladder: %w", err)
+	}
+	l, err := row.ladder()
+	if err != nil {
+		return nil, fmt.Errorf("save rendition ladder: %w", err)
+	}
+	return &l, nil
+}
+
+// UpdateAssetLadderVersion records the ladder version an asset's derivatives were rendered with
+func (r *ImagingRepository) UpdateAssetLadderVersion(ctx context.Context, id uuid.UUID, ladderVersion int) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `UPDATE image_assets SET ladder_version = $1 WHERE id = $2`, ladderVersion, id)
+	if err != nil {
+		return fmt.Errorf("update asset ladder version: %w", err)
+	}
+	return nil
+}
+
+// CountOutdatedAssets counts the ready assets rendered with an older version
+// of their ladder, by ladder category
+func (r *ImagingRepository) CountOutdatedAssets(ctx context.Context) (map[string]int, error) {
+	var rows []struct {
+		Category string `db:"category"`
+		Count    int    `db:"count"`
+	}
+	err := r.db.Conn(ctx).SelectContext(ctx, &rows, `
+		SELECT `+ladderCategorySQL+` AS category, COUNT(*) AS count
+		FROM image_assets a
+		WHERE a.status = 'ready' AND `+outdatedLadderSQL+`
+		GROUP BY 1`)
+	if err != nil {
+		return nil, fmt.Errorf("count outdated assets: %w", err)
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Category] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,245 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrUnknownReviewPhoto is returned when a review references an image asset
+// that does not exist or was uploaded by someone else
+var ErrUnknownReviewPhoto = errors.New("review references an unknown photo asset")
+
+var (
+	// ErrReviewNotFound is returned when a review does not exist
+	ErrReviewNotFound = errors.New("review not found")
+	// ErrOwnReviewVote is returned when a user votes on their own review
+	ErrOwnReviewVote = errors.New("cannot vote on your own review")
+)
+
+// Review list orders
+const (
+	ReviewSortHelpful = "helpful" // Highest helpful_score first, the default
+	ReviewSortNewest  = "newest"
+)
+
+// reviewOrderBy maps a review sort to its ORDER BY clause; unknown sorts use helpfulness
+func reviewOrderBy(sort string) string {
+	if sort == ReviewSortNewest {
+		return "r.created_at DESC"
+	}
+	return "r.helpful_score DESC, r.created_at DESC"
+}
+
+// reviewColumns selects a review with its author name and photos; queries alias reviews as r
+const reviewColumns = `r.review_id, r.poi_id, r.user_id, r.rating, r.content,
+	COALESCE(r.upvotes, 0) as upvotes, COALESCE(r.downvotes, 0) as downvotes, r.helpful_score,
+	r.created_at, r.wifi_rating, r.comfort_rating, r.food_rating, r.value_rating, r.noise_accuracy_rating,
+	ARRAY(SELECT rp.asset_id::text FROM review_photos rp WHERE rp.review_id = r.review_id ORDER BY rp.position) as photo_asset_ids,
+	u.name as user_name`
+
+// ReviewRepository handles POI reviews
+type ReviewRepository struct {
+	db *database.DB
+}
+
+// NewReviewRepository creates a new review repository
+func NewReviewRepository(db *database.DB) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+// GetByPOI returns the most helpful reviews of a POI as seen by viewerID, nil
+// for an anonymous viewer
+func (r *ReviewRepository) GetByPOI(ctx context.Context, poiID uuid.UUID, viewerID *uuid.UUID, limit, offset int) ([]models.Review, error) {
+	return r.ListByPOI(ctx, poiID, viewerID, ReviewSortHelpful, limit, offset)
+}
+
+// ListByPOI returns the reviews of a POI in the given sort order. With a viewer,
+// each review carries the viewer's vote (1, -1 or 0). Reviews of shadow-banned
+// users are only returned to their author.
+func (r *ReviewRepository) ListByPOI(ctx context.Context, poiID uuid.UUID, viewerID *uuid.UUID, sort string, limit, offset int) ([]models.Review, error) {
+	reviews := []models.Review{}
+	viewerVote := "NULL::int"
+	if viewerID != nil {
+		viewerVote = "COALESCE((SELECT v.vote_type::int FROM review_votes v WHERE v.review_id = r.review_id AND v.user_id = $4), 0)"
+	}
+	query := `
+		SELECT ` + reviewColumns + `, ` + viewerVote + ` as user_vote
+		FROM reviews r
+		LEFT JOIN users u ON r.user_id = u.user_id
+		WHERE r.poi_id = $1 AND ` + visibleAuthor("$4") + `
+		ORDER BY ` + reviewOrderBy(sort) + `
+		LIMIT $2 OFFSET $3
+	`
+	if err := r.db.Conn(ctx).SelectContext(ctx, &reviews, query, poiID, limit, offset, viewerID); err != nil {
+		return nil, fmt.Errorf("get reviews by poi: %w", err)
+	}
+	return reviews, nil
+}
+
+// GetByPOIs returns up to perPOI of the most helpful reviews of each POI, grouped by POI.
+// Shadow-banned authors are hidden from everyone but themselves (viewerID).
+func (r *ReviewRepository) GetByPOIs(ctx context.Context, poiIDs []uuid.UUID, viewerID *uuid.UUID, perPOI int) (map[uuid.UUID][]models.Review, error) {
+	var reviews []models.Review
+	query := `
+		SELECT review_id, poi_id, user_id, rating, content, upvotes, downvotes, helpful_score, created_at,
+		       wifi_rating, comfort_rating, food_rating, value_rating, noise_accuracy_rating,
+		       photo_asset_ids, user_name
+		FROM (
+			SELECT ` + reviewColumns + `,
+			       row_number() OVER (PARTITION BY r.poi_id ORDER BY r.helpful_score DESC, r.created_at DESC) as rn
+			FROM reviews r
+			LEFT JOIN users u ON r.user_id = u.user_id
+			WHERE r.poi_id = ANY($1::uuid[]) AND ` + visibleAuthor("$3") + `
+		) ranked
+		WHERE rn <= $2
+		ORDER BY poi_id, rn
+	`
+	if err := r.db.Conn(ctx).SelectContext(ctx, &reviews, query, pq.Array(poiIDs), perPOI, viewerID); err != nil {
+		return nil, fmt.Errorf("get reviews by pois: %w", err)
+	}
+
+	byPOI := make(map[uuid.UUID][]models.Review, len(poiIDs))
+	for _, rv := range reviews {
+		byPOI[rv.PoiID] = append(byPOI[rv.PoiID], rv)
+	}
+	return byPOI, nil
+}
+
+// Upsert creates or replaces the review a user wrote for a POI (one per user
+// and POI) and its photos. Photos must be image assets uploaded by the reviewer.
+func (r *ReviewRepository) Upsert(ctx context.Context, review *models.Review, photoAssetIDs []uuid.UUID) (*models.Review, error) {
+	var saved models.Review
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		conn := r.db.Conn(ctx)
+
+		var reviewID uuid.UUID
+		err := conn.QueryRowContext(ctx, `
+			INSERT INTO reviews (
+				poi_id, user_id, rating, content,
+				wifi_rating, comfort_rating, food_rating, value_rating, noise_accuracy_rating
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT (user_id, poi_id) DO UPDATE SET
+				rating = EXCLUDED.rating, content = EXCLUDED.content,
+				wifi_rating = EXCLUDED.wifi_rating, comfort_rating = EXCLUDED.comfort_rating,
+				food_rating = EXCLUDED.food_rating, value_rating = EXCLUDED.value_rating,
+				noise_accuracy_rating = EXCLUDED.noise_accuracy_rating,
+				updated_at = NOW()
+			RETURNING review_id
+		`, review.PoiID, review.UserID, review.Rating, review.Content,
+			review.WifiRating, review.ComfortRating, review.FoodRating, review.ValueRating, review.NoiseAccuracyRating,
+		).Scan(&reviewID)
+		if err != nil {
+			return fmt.Errorf("upsert review: %w", err)
+		}
+
+		if _, err := conn.ExecContext(ctx, `DELETE FROM review_photos WHERE review_id = $1`, reviewID); err != nil {
+			return fmt.Errorf("clear review photos: %w", err)
+		}
+		for i, assetID := range photoAssetIDs {
+			res, err := conn.ExecContext(ctx, `
+				INSERT INTO review_photos (review_id, asset_id, position)
+				SELECT $1, id, $3 FROM image_assets WHERE id = $2 AND created_by_user_id = $4
+				ON CONFLICT DO NOTHING
+			`, reviewID, assetID, i, review.UserID)
+			if err != nil {
+				return fmt.Errorf("insert review photo: %w", err)
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				return ErrUnknownReviewPhoto
+			}
+		}
+
+		return conn.GetContext(ctx, &saved, `
+			SELECT `+reviewColumns+`
+			FROM reviews r
+			LEFT JOIN users u ON r.user_id = u.user_id
+			WHERE r.review_id = $1
+		`, reviewID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// VoteWithToggle records a helpful (1) or unhelpful (-1) vote with the same
+// Reddit-style toggle as photo votes: a repeated vote is removed, an opposite
+// vote switches. Returns the new helpful score and the user's vote (1, -1 or 0).
+func (r *ReviewRepository) VoteWithToggle(ctx context.Context, reviewID, userID uuid.UUID, voteType int) (int, int, error) {
+	var newScore, userVote int
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		conn := r.db.Conn(ctx)
+
+		var authorID uuid.UUID
+		err := conn.QueryRowContext(ctx, `SELECT user_id FROM reviews WHERE review_id = $1 FOR UPDATE`, reviewID).Scan(&authorID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrReviewNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("get review: %w", err)
+		}
+		if authorID == userID {
+			return ErrOwnReviewVote
+		}
+
+		var existingVote sql.NullInt64
+		err = conn.QueryRowContext(ctx, `
+			SELECT vote_type FROM review_votes
+			WHERE review_id = $1 AND user_id = $2
+		`, reviewID, userID).Scan(&existingVote)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("check existing vote: %w", err)
+		}
+
+		switch {
+		case !existingVote.Valid:
+			_, err = conn.ExecContext(ctx, `
+				INSERT INTO review_votes (review_id, user_id, vote_type)
+				VALUES ($1, $2, $3)
+			`, reviewID, userID, voteType)
+			userVote = voteType
+		case int(existingVote.Int64) == voteType:
+			_, err = conn.ExecContext(ctx, `
+				DELETE FROM review_votes
+				WHERE review_id = $1 AND user_id = $2
+			`, reviewID, userID)
+			userVote = 0
+		default:
+			_, err = conn.ExecContext(ctx, `
+				UPDATE review_votes
+				SET vote_type = $3, created_at = NOW()
+				WHERE review_id = $1 AND user_id = $2
+			`, reviewID, userID, voteType)
+			userVote = voteType
+		}
+		if err != nil {
+			return fmt.Errorf("record vote: %w", err)
+		}
+
+		// Recalculate counts and score from the votes table
+		err = conn.QueryRowContext(ctx, `
+			UPDATE reviews
+			SET upvotes = (SELECT COUNT(*) FROM review_votes WHERE review_id = $1 AND vote_type = 1),
+			    downvotes = (SELECT COUNT(*) FROM review_votes WHERE review_id = $1 AND vote_type = -1),
+			    helpful_score = COALESCE((SELECT SUM(vote_type) FROM review_votes WHERE review_id = $1), 0)
+			WHERE review_id = $1
+			RETURNING helpful_score
+		`, reviewID).Scan(&newScore)
+		if err != nil {
+			return fmt.Errorf("update review score: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return newScore, userVote, nil
+}
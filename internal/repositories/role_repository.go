@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"maukemana-backend/internal/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrRoleNotFound is returned when a role is not defined
+	ErrRoleNotFound = errors.New("role not found")
+	// ErrUserNotFound is returned when a user does not exist
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// permissionCacheTTL bounds how long a role's permissions are served from
+// memory; every authenticated request looks them up
+const permissionCacheTTL = time.Minute
+
+// Role is a named set of permissions
+type Role struct {
+	Name        string         `db:"name" json:"name"`
+	Description *string        `db:"description" json:"description,omitempty"`
+	Permissions pq.StringArray `db:"permissions" json:"permissions"`
+}
+
+type cachedPermissions struct {
+	permissions []string
+	expiresAt   time.Time
+}
+
+// RoleRepository handles roles, permissions and role assignment
+type RoleRepository struct {
+	db *database.DB
+
+	mu    sync.RWMutex
+	cache map[string]cachedPermissions
+}
+
+// NewRoleRepository creates a new role repository
+func NewRoleRepository(db *database.DB) *RoleRepository {
+	return &RoleRepository{db: db, cache: make(map[string]cachedPermissions)}
+}
+
+// PermissionsForRole returns the permission names granted to a role. Unknown
+// roles have no permissions.
+func (r *RoleRepository) PermissionsForRole(ctx context.Context, role string) ([]string, error) {
+	r.mu.RLock()
+	cached, ok := r.cache[role]
+	r.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.permissions, nil
+	}
+
+	permissions := []string{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &permissions, `
+		SELECT permission FROM role_permissions WHERE role = $1 ORDER BY permission
+	`, role)
+	if err != nil {
+		return nil, fmt.Errorf("get role permissions: %w", err)
+	}
+
+	r.mu.Lock()
+	r.cache[role] = cachedPermissions{permissions: permissions, expiresAt: time.Now().Add(permissionCacheTTL)}
+	r.mu.Unlock()
+	return permissions, nil
+}
+
+// ListRoles returns every role with its permissions
+func (r *RoleRepository) ListRoles(ctx context.Context) ([]Role, error) {
+	roles := []Role{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &roles, `
+		SELECT r.name, r.description,
+		       COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}') AS permissions
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role = r.name
+		GROUP BY r.name, r.description
+		ORDER BY r.name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list roles: %w", err)
+	}
+	return roles, nil
+}
+
+// SetUserRole assigns a defined role to a user
+func (r *RoleRepository) SetUserRole(ctx context.Context, userID uuid.UUID, role string) error {
+	var exists bool
+	err := r.db.Conn(ctx).QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM roles WHERE name = $1)`, role).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("check role: %w", err)
+	}
+	if !exists {
+		return ErrRoleNotFound
+	}
+
+	var updated uuid.UUID
+	err = r.db.Conn(ctx).QueryRowContext(ctx, `
+		UPDATE users SET role = $2, updated_at = NOW()
+		WHERE user_id = $1 AND deleted_at IS NULL
+		RETURNING user_id
+	`, userID, role).Scan(&updated)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("set user role: %w", err)
+	}
+	return nil
+}
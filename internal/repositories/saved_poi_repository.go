@@ -34,7 +34,7 @@ func (r *SavedPOIRepository) SavePOI(ctx context.Context, userID, poiID uuid.UUI
 		VALUES ($1, $2, $3)
 		ON CONFLICT (user_id, poi_id) DO NOTHING
 	`
-	_, err := r.db.ExecContext(ctx, query, userID, poiID, time.Now())
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query, userID, poiID, time.Now())
 	if err != nil {
 		return fmt.Errorf("save poi: %w", err)
 	}
@@ -44,7 +44,7 @@ func (r *SavedPOIRepository) SavePOI(ctx context.Context, userID, poiID uuid.UUI
 // UnsavePOI removes a saved POI
 func (r *SavedPOIRepository) UnsavePOI(ctx context.Context, userID, poiID uuid.UUID) error {
 	query := `DELETE FROM saved_pois WHERE user_id = $1 AND poi_id = $2`
-	_, err := r.db.ExecContext(ctx, query, userID, poiID)
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query, userID, poiID)
 	if err != nil {
 		return fmt.Errorf("unsave poi: %w", err)
 	}
@@ -55,18 +55,17 @@ func (r *SavedPOIRepository) UnsavePOI(ctx context.Context, userID, poiID uuid.U
 // Assuming we want to return the actual POI data structure for the list
 func (r *SavedPOIRepository) GetSavedPOIs(ctx context.Context, userID uuid.UUID, limit, offset int) ([]POI, error) {
 	var pois []POI
+	columns, joins := poiSummarySelect()
 	query := `
-		SELECT p.poi_id, p.name, p.category_id, p.description, p.status, p.created_by,
-		       p.cover_image_url, p.has_wifi, p.outdoor_seating, p.price_range,
-		       COALESCE((SELECT AVG(rating) FROM reviews r WHERE r.poi_id = p.poi_id), 0) as rating_avg,
+		SELECT ` + columns + `,
 		       s.created_at as saved_at
-		FROM points_of_interest p
+		FROM points_of_interest p` + joins + `
 		JOIN saved_pois s ON p.poi_id = s.poi_id
 		WHERE s.user_id = $1
 		ORDER BY s.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	err := r.db.SelectContext(ctx, &pois, query, userID, limit, offset)
+	err := r.db.Conn(ctx).SelectContext(ctx, &pois, query, userID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("get saved pois: %w", err)
 	}
@@ -77,7 +76,7 @@ func (r *SavedPOIRepository) GetSavedPOIs(ctx context.Context, userID uuid.UUID,
 func (r *SavedPOIRepository) IsSaved(ctx context.Context, userID, poiID uuid.UUID) (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM saved_pois WHERE user_id = $1 AND poi_id = $2)`
-	err := r.db.QueryRowContext(ctx, query, userID, poiID).Scan(&exists)
+	err := r.db.Conn(ctx).QueryRowContext(ctx, query, userID, poiID).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("check is saved: %w", err)
 	}
@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// MaxSavedSearches is how many saved searches a user can keep
+const MaxSavedSearches = 20
+
+var (
+	// ErrSavedSearchNotFound is returned when a saved search does not exist for the user
+	ErrSavedSearchNotFound = errors.New("saved search not found")
+	// ErrSavedSearchLimit is returned when the user already keeps MaxSavedSearches
+	ErrSavedSearchLimit = errors.New("saved search limit reached")
+)
+
+const savedSearchColumns = `search_id, user_id, name, query, alerts_enabled, checked_until, last_alerted_at, created_at, updated_at`
+
+// SavedSearchRepository handles users' saved searches and their alert progress
+type SavedSearchRepository struct {
+	db *database.DB
+}
+
+// NewSavedSearchRepository creates a new saved search repository
+func NewSavedSearchRepository(db *database.DB) *SavedSearchRepository {
+	return &SavedSearchRepository{db: db}
+}
+
+// ListForUser returns a user's saved searches, newest first
+func (r *SavedSearchRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]models.SavedSearch, error) {
+	searches := []models.SavedSearch{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &searches, `
+		SELECT `+savedSearchColumns+`
+		FROM saved_searches
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list saved searches: %w", err)
+	}
+	return searches, nil
+}
+
+// Create stores a saved search. Alerts only cover POIs approved from now on.
+func (r *SavedSearchRepository) Create(ctx context.Context, s *models.SavedSearch) (*models.SavedSearch, error) {
+	var created models.SavedSearch
+	err := r.db.Conn(ctx).GetContext(ctx, &created, `
+		INSERT INTO saved_searches (user_id, name, query, alerts_enabled)
+		SELECT $1, $2, $3, $4
+		WHERE (SELECT COUNT(*) FROM saved_searches WHERE user_id = $1) < $5
+		RETURNING `+savedSearchColumns,
+		s.UserID, s.Name, []byte(s.Query), s.AlertsEnabled, MaxSavedSearches)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSavedSearchLimit
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create saved search: %w", err)
+	}
+	return &created, nil
+}
+
+// Update replaces the name, query and alert setting of a user's saved
+// search. Turning alerts on only covers POIs approved from then on.
+func (r *SavedSearchRepository) Update(ctx context.Context, s *models.SavedSearch) (*models.SavedSearch, error) {
+	var updated models.SavedSearch
+	err := r.db.Conn(ctx).GetContext(ctx, &updated, `
+		UPDATE saved_searches
+		SET name = $3, query = $4, alerts_enabled = $5,
+		    checked_until = CASE WHEN $5 AND NOT alerts_enabled THEN NOW() ELSE checked_until END,
+		    updated_at = NOW()
+		WHERE search_id = $1 AND user_id = $2
+		RETURNING `+savedSearchColumns,
+		s.SearchID, s.UserID, s.Name, []byte(s.Query), s.AlertsEnabled)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSavedSearchNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("update saved search: %w", err)
+	}
+	return &updated, nil
+}
+
+// Delete removes one of the user's saved searches
+func (r *SavedSearchRepository) Delete(ctx context.Context, userID, searchID uuid.UUID) error {
+	res, err := r.db.Conn(ctx).ExecContext(ctx, `DELETE FROM saved_searches WHERE search_id = $1 AND user_id = $2`, searchID, userID)
+	if err != nil {
+		return fmt.Errorf("delete saved search: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrSavedSearchNotFound
+	}
+	return nil
+}
+
+// ListAlertSearches returns the saved searches with alerts enabled that were
+// last evaluated before a time, least recently evaluated first
+func (r *SavedSearchRepository) ListAlertSearches(ctx context.Context, before time.Time, limit int) ([]models.SavedSearch, error) {
+	searches := []models.SavedSearch{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &searches, `
+		SELECT `+savedSearchColumns+`
+		FROM saved_searches
+		WHERE alerts_enabled AND checked_until < $1
+		ORDER BY checked_until
+		LIMIT $2
+	`, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list alert searches: %w", err)
+	}
+	return searches, nil
+}
+
+// MarkChecked records that approvals up to until were evaluated for a saved
+// search, and when it last alerted if alerted is set
+func (r *SavedSearchRepository) MarkChecked(ctx context.Context, searchID uuid.UUID, until time.Time, alerted bool) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE saved_searches
+		SET checked_until = GREATEST(checked_until, $2),
+		    last_alerted_at = CASE WHEN $3 THEN NOW() ELSE last_alerted_at END
+		WHERE search_id = $1
+	`, searchID, until, alerted)
+	if err != nil {
+		return fmt.Errorf("mark saved search checked: %w", err)
+	}
+	return nil
+}
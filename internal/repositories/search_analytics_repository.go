@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+)
+
+// SearchAnalyticsRepository stores sampled search events and summarizes them
+type SearchAnalyticsRepository struct {
+	db *database.DB
+}
+
+// NewSearchAnalyticsRepository creates a new search analytics repository
+func NewSearchAnalyticsRepository(db *database.DB) *SearchAnalyticsRepository {
+	return &SearchAnalyticsRepository{db: db}
+}
+
+// InsertSearchEvents stores a batch of events in one statement
+func (r *SearchAnalyticsRepository) InsertSearchEvents(ctx context.Context, events []models.SearchEvent) error {
+	payload, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("encode search events: %w", err)
+	}
+	_, err = r.db.Conn(ctx).ExecContext(ctx, `
+		INSERT INTO search_events (query, filters, sort_by, area, lat, lng, result_count, sample_rate)
+		SELECT e.query, COALESCE(e.filters, '{}'), e.sort_by, e.area, e.lat, e.lng, e.result_count, e.sample_rate
+		FROM jsonb_to_recordset($1::jsonb) AS e(
+			query TEXT, filters JSONB, sort_by VARCHAR(32), area VARCHAR(64),
+			lat DOUBLE PRECISION, lng DOUBLE PRECISION, result_count INT, sample_rate REAL
+		)
+	`, payload)
+	if err != nil {
+		return fmt.Errorf("insert search events: %w", err)
+	}
+	return nil
+}
+
+// PurgeSearchEvents deletes events recorded before a time
+func (r *SearchAnalyticsRepository) PurgeSearchEvents(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.Conn(ctx).ExecContext(ctx, `DELETE FROM search_events WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("purge search events: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// Summarize reports totals, the most used filter values, the top and
+// zero-result queries, and the most searched areas over the last days. Each
+// list holds at most limit entries; counts are estimated from the samples.
+func (r *SearchAnalyticsRepository) Summarize(ctx context.Context, days, limit int) (*models.SearchSummary, error) {
+	since := time.Now().AddDate(0, 0, -days)
+	summary := &models.SearchSummary{Days: days}
+	conn := r.db.Conn(ctx)
+
+	err := conn.GetContext(ctx, summary, `
+		SELECT COALESCE(ROUND(SUM(1 / sample_rate)), 0)::int AS total_searches,
+		       COALESCE(SUM(1 / sample_rate) FILTER (WHERE result_count = 0) / NULLIF(SUM(1 / sample_rate), 0), 0) AS zero_result_rate
+		FROM search_events
+		WHERE created_at >= $1
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("summarize search totals: %w", err)
+	}
+
+	// Array filters count once per selected value
+	summary.TopFilters = []models.SearchFilterCount{}
+	err = conn.SelectContext(ctx, &summary.TopFilters, `
+		SELECT f.key AS filter, v.value, ROUND(SUM(1 / e.sample_rate))::int AS count
+		FROM search_events e
+		CROSS JOIN LATERAL jsonb_each(e.filters) f
+		CROSS JOIN LATERAL (
+			SELECT jsonb_array_elements_text(f.value) AS value WHERE jsonb_typeof(f.value) = 'array'
+			UNION ALL
+			SELECT f.value #>> '{}' WHERE jsonb_typeof(f.value) <> 'array'
+		) v
+		WHERE e.created_at >= $1
+		GROUP BY f.key, v.value
+		ORDER BY count DESC, filter, value
+		LIMIT $2
+	`, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("summarize search filters: %w", err)
+	}
+
+	summary.TopQueries, err = r.queryCounts(ctx, since, limit, false)
+	if err != nil {
+		return nil, err
+	}
+	summary.ZeroResultQueries, err = r.queryCounts(ctx, since, limit, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Explicit ?area= searches, otherwise the active area containing the search center
+	summary.PopularAreas = []models.SearchAreaCount{}
+	err = conn.SelectContext(ctx, &summary.PopularAreas, `
+		SELECT COALESCE(e.area, sa.slug) AS area, ROUND(SUM(1 / e.sample_rate))::int AS count
+		FROM search_events e
+		LEFT JOIN service_areas sa
+			ON e.area IS NULL AND e.lat IS NOT NULL AND sa.is_active
+			AND ST_Within(ST_SetSRID(ST_MakePoint(e.lng, e.lat), 4326), sa.boundary)
+		WHERE e.created_at >= $1 AND COALESCE(e.area, sa.slug) IS NOT NULL
+		GROUP BY 1
+		ORDER BY count DESC, area
+		LIMIT $2
+	`, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("summarize search areas: %w", err)
+	}
+
+	summary.PopularCells = []models.SearchCellCount{}
+	err = conn.SelectContext(ctx, &summary.PopularCells, `
+		SELECT lat, lng, ROUND(SUM(1 / sample_rate))::int AS count
+		FROM search_events
+		WHERE created_at >= $1 AND lat IS NOT NULL AND lng IS NOT NULL
+		GROUP BY lat, lng
+		ORDER BY count DESC
+		LIMIT $2
+	`, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("summarize search cells: %w", err)
+	}
+
+	return summary, nil
+}
+
+func (r *SearchAnalyticsRepository) queryCounts(ctx context.Context, since time.Time, limit int, zeroResults bool) ([]models.SearchQueryCount, error) {
+	counts := []models.SearchQueryCount{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &counts, `
+		SELECT query, ROUND(SUM(1 / sample_rate))::int AS count
+		FROM search_events
+		WHERE created_at >= $1 AND query IS NOT NULL AND (NOT $3 OR result_count = 0)
+		GROUP BY query
+		ORDER BY count DESC, query
+		LIMIT $2
+	`, since, limit, zeroResults)
+	if err != nil {
+		return nil, fmt.Errorf("summarize search queries: %w", err)
+	}
+	return counts, nil
+}
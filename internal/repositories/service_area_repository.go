@@ -0,0 +1,290 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrServiceAreaNotFound is returned when a service area does not exist
+	ErrServiceAreaNotFound = errors.New("service area not found")
+	// ErrServiceAreaSlugTaken is returned when another area already uses the slug
+	ErrServiceAreaSlugTaken = errors.New("service area slug already exists")
+	// ErrInvalidBoundary is returned when PostGIS rejects a boundary geometry
+	ErrInvalidBoundary = errors.New("invalid service area boundary")
+)
+
+// areaSummaryTTL bounds how long an area landing page summary is served from memory
+const areaSummaryTTL = time.Hour
+
+// areaSummaryListSize is the number of top-rated and newest POIs in a summary
+const areaSummaryListSize = 6
+
+// AreaCategoryCount is the number of approved POIs of a category in an area
+type AreaCategoryCount struct {
+	CategoryID uuid.UUID `db:"category_id" json:"category_id"`
+	NameKey    string    `db:"name_key" json:"name_key"`
+	Icon       *string   `db:"icon" json:"icon,omitempty"`
+	Count      int       `db:"count" json:"count"`
+}
+
+// AreaPOI is a POI card on an area landing page
+type AreaPOI struct {
+	PoiID         uuid.UUID `db:"poi_id" json:"poi_id"`
+	Name          string    `db:"name" json:"name"`
+	CoverImageURL *string   `db:"cover_image_url" json:"cover_image_url,omitempty"`
+	PriceRange    *int      `db:"price_range" json:"price_range,omitempty"`
+	RatingAvg     float64   `db:"rating_avg" json:"rating_avg"`
+	ReviewsCount  int       `db:"reviews_count" json:"reviews_count"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+}
+
+// AreaSummary aggregates the approved POIs inside a service area
+type AreaSummary struct {
+	Slug            string              `json:"slug"`
+	Name            string              `json:"name"`
+	TotalPOIs       int                 `json:"total_pois"`
+	AvgPriceRange   *float64            `json:"avg_price_range,omitempty"`
+	Categories      []AreaCategoryCount `json:"categories"`
+	TopRated        []AreaPOI           `json:"top_rated"`
+	NewestAdditions []AreaPOI           `json:"newest_additions"`
+	GeneratedAt     time.Time           `json:"generated_at"`
+}
+
+type cachedAreaSummary struct {
+	summary   *AreaSummary
+	expiresAt time.Time
+}
+
+// ServiceAreaRepository handles the polygons of cities and regions the product operates in
+type ServiceAreaRepository struct {
+	db *database.DB
+
+	mu        sync.RWMutex
+	summaries map[string]cachedAreaSummary
+}
+
+// NewServiceAreaRepository creates a new service area repository
+func NewServiceAreaRepository(db *database.DB) *ServiceAreaRepository {
+	return &ServiceAreaRepository{db: db, summaries: make(map[string]cachedAreaSummary)}
+}
+
+const serviceAreaColumns = `area_id, slug, name, ST_AsGeoJSON(boundary)::jsonb AS boundary, is_active, created_at, updated_at`
+
+// activeAreaContains is true when no area is active (geofencing off) or an
+// active area contains the point geometry expression
+func activeAreaContains(point string) string {
+	return fmt.Sprintf(`(
+		NOT EXISTS (SELECT 1 FROM service_areas WHERE is_active)
+		OR EXISTS (SELECT 1 FROM service_areas sa WHERE sa.is_active AND ST_Within(%s, sa.boundary))
+	)`, point)
+}
+
+// List returns all service areas with their boundaries, by name
+func (r *ServiceAreaRepository) List(ctx context.Context) ([]models.ServiceArea, error) {
+	areas := []models.ServiceArea{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &areas, `
+		SELECT `+serviceAreaColumns+`
+		FROM service_areas
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list service areas: %w", err)
+	}
+	return areas, nil
+}
+
+// ListActive returns the active service areas without their boundaries, by name
+func (r *ServiceAreaRepository) ListActive(ctx context.Context) ([]models.ServiceArea, error) {
+	areas := []models.ServiceArea{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &areas, `
+		SELECT area_id, slug, name, is_active, created_at, updated_at
+		FROM service_areas
+		WHERE is_active
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list active service areas: %w", err)
+	}
+	return areas, nil
+}
+
+// Create stores a new service area. Polygon boundaries are stored as multipolygons.
+func (r *ServiceAreaRepository) Create(ctx context.Context, area *models.ServiceArea) (*models.ServiceArea, error) {
+	var created models.ServiceArea
+	err := r.db.Conn(ctx).GetContext(ctx, &created, `
+		INSERT INTO service_areas (slug, name, boundary, is_active)
+		VALUES ($1, $2, ST_Multi(ST_SetSRID(ST_GeomFromGeoJSON($3), 4326)), $4)
+		RETURNING `+serviceAreaColumns,
+		area.Slug, area.Name, string(area.Boundary), area.IsActive)
+	if err != nil {
+		return nil, serviceAreaWriteError("create service area", err)
+	}
+	return &created, nil
+}
+
+// Update replaces a service area
+func (r *ServiceAreaRepository) Update(ctx context.Context, area *models.ServiceArea) (*models.ServiceArea, error) {
+	var updated models.ServiceArea
+	err := r.db.Conn(ctx).GetContext(ctx, &updated, `
+		UPDATE service_areas
+		SET slug = $2, name = $3, boundary = ST_Multi(ST_SetSRID(ST_GeomFromGeoJSON($4), 4326)),
+		    is_active = $5, updated_at = NOW()
+		WHERE area_id = $1
+		RETURNING `+serviceAreaColumns,
+		area.AreaID, area.Slug, area.Name, string(area.Boundary), area.IsActive)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrServiceAreaNotFound
+	}
+	if err != nil {
+		return nil, serviceAreaWriteError("update service area", err)
+	}
+	r.clearSummaries()
+	return &updated, nil
+}
+
+// Delete removes a service area
+func (r *ServiceAreaRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.Conn(ctx).ExecContext(ctx, `DELETE FROM service_areas WHERE area_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete service area: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrServiceAreaNotFound
+	}
+	r.clearSummaries()
+	return nil
+}
+
+// InServiceArea reports whether a coordinate lies inside an active service area
+func (r *ServiceAreaRepository) InServiceArea(ctx context.Context, lat, lng float64) (bool, error) {
+	var inside bool
+	err := r.db.Conn(ctx).GetContext(ctx, &inside,
+		`SELECT `+activeAreaContains("ST_SetSRID(ST_MakePoint($1, $2), 4326)"), lng, lat)
+	if err != nil {
+		return false, fmt.Errorf("check service area: %w", err)
+	}
+	return inside, nil
+}
+
+// POIInServiceArea reports whether a POI's location lies inside an active service area
+func (r *ServiceAreaRepository) POIInServiceArea(ctx context.Context, poiID uuid.UUID) (bool, error) {
+	var inside bool
+	err := r.db.Conn(ctx).GetContext(ctx, &inside, `
+		SELECT COALESCE(`+activeAreaContains("p.location::geometry")+`, false)
+		FROM points_of_interest p
+		WHERE p.poi_id = $1
+	`, poiID)
+	if err != nil {
+		return false, fmt.Errorf("check poi service area: %w", err)
+	}
+	return inside, nil
+}
+
+// GetSummary aggregates the approved POIs inside an active area for its
+// landing page. Summaries are computed at most once per hour per area.
+func (r *ServiceAreaRepository) GetSummary(ctx context.Context, slug string) (*AreaSummary, error) {
+	r.mu.RLock()
+	cached, ok := r.summaries[slug]
+	r.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.summary, nil
+	}
+
+	summary := &AreaSummary{Slug: slug, GeneratedAt: time.Now().UTC()}
+	conn := r.db.Conn(ctx)
+
+	var area struct {
+		Name          string   `db:"name"`
+		TotalPOIs     int      `db:"total_pois"`
+		AvgPriceRange *float64 `db:"avg_price_range"`
+	}
+	err := conn.GetContext(ctx, &area, `
+		SELECT sa.name, COUNT(p.poi_id) AS total_pois, AVG(p.price_range)::float8 AS avg_price_range
+		FROM service_areas sa
+		LEFT JOIN points_of_interest p
+		  ON p.status = 'approved' AND ST_Within(p.location::geometry, sa.boundary)
+		WHERE sa.slug = $1 AND sa.is_active
+		GROUP BY sa.area_id, sa.name
+	`, slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrServiceAreaNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get area summary: %w", err)
+	}
+	summary.Name, summary.TotalPOIs, summary.AvgPriceRange = area.Name, area.TotalPOIs, area.AvgPriceRange
+
+	// POIs with several categories count towards each of them
+	summary.Categories = []AreaCategoryCount{}
+	err = conn.SelectContext(ctx, &summary.Categories, `
+		SELECT c.category_id, c.name_key, c.icon, COUNT(DISTINCT p.poi_id) AS count
+		FROM service_areas sa
+		JOIN points_of_interest p
+		  ON p.status = 'approved' AND ST_Within(p.location::geometry, sa.boundary)
+		JOIN categories c
+		  ON c.category_id = p.category_id OR c.category_id::text = ANY(p.category_ids)
+		WHERE sa.slug = $1
+		GROUP BY c.category_id, c.name_key, c.icon
+		ORDER BY count DESC, c.name_key
+	`, slug)
+	if err != nil {
+		return nil, fmt.Errorf("get area category counts: %w", err)
+	}
+
+	areaPOIs := func(dest *[]AreaPOI, where, orderBy string) error {
+		*dest = []AreaPOI{}
+		return conn.SelectContext(ctx, dest, fmt.Sprintf(`
+			SELECT p.poi_id, p.name, p.cover_image_url, p.price_range, p.rating_avg, p.reviews_count, p.created_at
+			FROM service_areas sa
+			JOIN points_of_interest p
+			  ON p.status = 'approved' AND ST_Within(p.location::geometry, sa.boundary)
+			WHERE sa.slug = $1 %s
+			ORDER BY %s
+			LIMIT $2
+		`, where, orderBy), slug, areaSummaryListSize)
+	}
+	if err := areaPOIs(&summary.TopRated, "AND p.reviews_count > 0", "p.rating_avg DESC, p.reviews_count DESC"); err != nil {
+		return nil, fmt.Errorf("get area top rated: %w", err)
+	}
+	if err := areaPOIs(&summary.NewestAdditions, "", "p.created_at DESC"); err != nil {
+		return nil, fmt.Errorf("get area newest: %w", err)
+	}
+
+	r.mu.Lock()
+	r.summaries[slug] = cachedAreaSummary{summary: summary, expiresAt: time.Now().Add(areaSummaryTTL)}
+	r.mu.Unlock()
+	return summary, nil
+}
+
+// clearSummaries drops cached summaries after an area's slug or boundary changed
+func (r *ServiceAreaRepository) clearSummaries() {
+	r.mu.Lock()
+	r.summaries = make(map[string]cachedAreaSummary)
+	r.mu.Unlock()
+}
+
+// serviceAreaWriteError maps constraint and geometry errors of an insert or update
+func serviceAreaWriteError(op string, err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code == "23505":
+			return ErrServiceAreaSlugTaken
+		case pqErr.Code.Class() == "22", pqErr.Code == "XX000":
+			// PostGIS reports unparsable GeoJSON as an internal error
+			return fmt.Errorf("%w: %s", ErrInvalidBoundary, pqErr.Message)
+		}
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
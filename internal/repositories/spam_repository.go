@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"maukemana-backend/internal/database"
+
+	"github.com/google/uuid"
+)
+
+// UGCSubmission is a comment or review as seen by the spam heuristics
+type UGCSubmission struct {
+	UserID      uuid.UUID
+	Kind        string    // "comment" or "review"
+	TargetID    uuid.UUID // POI the content is posted on
+	ContentHash string    // sha256 of the normalized text
+}
+
+// UserSpamStatus is the moderation state of a user
+type UserSpamStatus struct {
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	Email        string    `db:"email" json:"email"`
+	Name         *string   `db:"name" json:"name,omitempty"`
+	ShadowBanned bool      `db:"shadow_banned" json:"shadow_banned"`
+	SpamExempt   bool      `db:"spam_exempt" json:"spam_exempt"`
+}
+
+// SpamRepository stores the submission history and user flags used against spam
+type SpamRepository struct {
+	db *database.DB
+}
+
+// NewSpamRepository creates a new spam repository
+func NewSpamRepository(db *database.DB) *SpamRepository {
+	return &SpamRepository{db: db}
+}
+
+// visibleAuthor is the condition hiding shadow-banned authors from everyone but
+// themselves, and authors the viewer blocked. users is aliased as u; viewer is
+// a nullable uuid parameter.
+func visibleAuthor(viewer string) string {
+	return fmt.Sprintf(`((NOT COALESCE(u.shadow_banned, false) OR u.user_id = %[1]s)
+		AND NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE ub.blocker_id = %[1]s AND ub.blocked_id = u.user_id))`, viewer)
+}
+
+// IsSpamExempt reports whether a user skips the submission heuristics
+func (r *SpamRepository) IsSpamExempt(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var exempt bool
+	err := r.db.Conn(ctx).GetContext(ctx, &exempt, `SELECT spam_exempt FROM users WHERE user_id = $1`, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrUserNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("get spam exemption: %w", err)
+	}
+	return exempt, nil
+}
+
+// CountSubmissions counts the comments and reviews a user posted since a time
+func (r *SpamRepository) CountSubmissions(ctx context.Context, userID uuid.UUID, since time.Time) (int, error) {
+	var n int
+	err := r.db.Conn(ctx).GetContext(ctx, &n, `
+		SELECT COUNT(*) FROM ugc_submissions WHERE user_id = $1 AND created_at > $2
+	`, userID, since)
+	if err != nil {
+		return 0, fmt.Errorf("count submissions: %w", err)
+	}
+	return n, nil
+}
+
+// HasDuplicate reports whether the user posted the same content since a time.
+// Reviews are replaced in place, so repeating the text of one's own review of
+// the same POI is an edit rather than a duplicate.
+func (r *SpamRepository) HasDuplicate(ctx context.Context, sub UGCSubmission, since time.Time) (bool, error) {
+	var exists bool
+	err := r.db.Conn(ctx).GetContext(ctx, &exists, `
+		SELECT EXISTS (
+			SELECT 1 FROM ugc_submissions
+			WHERE user_id = $1 AND content_hash = $2 AND created_at > $3
+			  AND NOT (kind = 'review' AND $4 = 'review' AND target_id = $5)
+		)
+	`, sub.UserID, sub.ContentHash, since, sub.Kind, sub.TargetID)
+	if err != nil {
+		return false, fmt.Errorf("check duplicate submission: %w", err)
+	}
+	return exists, nil
+}
+
+// RecordSubmission stores an accepted submission
+func (r *SpamRepository) RecordSubmission(ctx context.Context, sub UGCSubmission) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		INSERT INTO ugc_submissions (user_id, kind, target_id, content_hash) VALUES ($1, $2, $3, $4)
+	`, sub.UserID, sub.Kind, sub.TargetID, sub.ContentHash)
+	if err != nil {
+		return fmt.Errorf("record submission: %w", err)
+	}
+	return nil
+}
+
+// ListFlaggedUsers returns users that are shadow-banned or spam-exempt
+func (r *SpamRepository) ListFlaggedUsers(ctx context.Context) ([]UserSpamStatus, error) {
+	users := []UserSpamStatus{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &users, `
+		SELECT user_id, email, name, shadow_banned, spam_exempt
+		FROM users
+		WHERE shadow_banned OR spam_exempt
+		ORDER BY email
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list flagged users: %w", err)
+	}
+	return users, nil
+}
+
+// SetUserSpamStatus updates a user's shadow-ban and exemption flags; nil keeps the current value
+func (r *SpamRepository) SetUserSpamStatus(ctx context.Context, userID uuid.UUID, shadowBanned, spamExempt *bool) (*UserSpamStatus, error) {
+	var status UserSpamStatus
+	err := r.db.Conn(ctx).GetContext(ctx, &status, `
+		UPDATE users
+		SET shadow_banned = COALESCE($2, shadow_banned),
+		    spam_exempt = COALESCE($3, spam_exempt),
+		    updated_at = NOW()
+		WHERE user_id = $1
+		RETURNING user_id, email, name, shadow_banned, spam_exempt
+	`, userID, shadowBanned, spamExempt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("set user spam status: %w", err)
+	}
+	return &status, nil
+}
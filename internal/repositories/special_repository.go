@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var ErrSpecialNotFound = errors.New("special not found")
+
+// SpecialRepository handles time-bounded POI specials and events
+type SpecialRepository struct {
+	db *database.DB
+}
+
+// NewSpecialRepository creates a new special repository
+func NewSpecialRepository(db *database.DB) *SpecialRepository {
+	return &SpecialRepository{db: db}
+}
+
+const specialColumns = `
+	s.special_id, s.poi_id, s.title, s.description, s.starts_at, s.ends_at,
+	s.recurrence, s.recurrence_days, s.daily_start_time::text, s.daily_end_time::text,
+	s.timezone, s.created_by, s.created_at, s.updated_at,
+	poi_special_is_active(s, NOW()) as is_active`
+
+// ListByPOI returns the specials of a POI. Expired specials (ends_at in the past)
+// are left out unless includeExpired is set.
+func (r *SpecialRepository) ListByPOI(ctx context.Context, poiID uuid.UUID, includeExpired bool) ([]models.Special, error) {
+	specials := []models.Special{}
+	query := `SELECT ` + specialColumns + `
+		FROM poi_specials s
+		WHERE s.poi_id = $1 AND ($2 OR s.ends_at IS NULL OR s.ends_at > NOW())
+		ORDER BY s.starts_at ASC, s.created_at ASC`
+
+	if err := r.db.Conn(ctx).SelectContext(ctx, &specials, query, poiID, includeExpired); err != nil {
+		return nil, fmt.Errorf("list specials: %w", err)
+	}
+	return specials, nil
+}
+
+// GetByID retrieves a special by ID
+func (r *SpecialRepository) GetByID(ctx context.Context, specialID uuid.UUID) (*models.Special, error) {
+	var s models.Special
+	query := `SELECT ` + specialColumns + `
+		FROM poi_specials s
+		WHERE s.special_id = $1`
+
+	err := r.db.Conn(ctx).GetContext(ctx, &s, query, specialID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSpecialNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get special: %w", err)
+	}
+	return &s, nil
+}
+
+// Create inserts a special and returns the stored row
+func (r *SpecialRepository) Create(ctx context.Context, s *models.Special) (*models.Special, error) {
+	var specialID uuid.UUID
+	err := r.db.Conn(ctx).QueryRowContext(ctx, `
+		INSERT INTO poi_specials (
+			poi_id, title, description, starts_at, ends_at, recurrence, recurrence_days,
+			daily_start_time, daily_end_time, timezone, created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8::time, $9::time, $10, $11)
+		RETURNING special_id
+	`, s.PoiID, s.Title, s.Description, s.StartsAt, s.EndsAt, s.Recurrence, s.RecurrenceDays,
+		s.DailyStartTime, s.DailyEndTime, s.Timezone, s.CreatedBy).Scan(&specialID)
+	if err != nil {
+		return nil, fmt.Errorf("create special: %w", err)
+	}
+	return r.GetByID(ctx, specialID)
+}
+
+// Update replaces the editable fields of a special
+func (r *SpecialRepository) Update(ctx context.Context, s *models.Special) (*models.Special, error) {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE poi_specials SET
+			title = $2, description = $3, starts_at = $4, ends_at = $5, recurrence = $6,
+			recurrence_days = $7, daily_start_time = $8::time, daily_end_time = $9::time,
+			timezone = $10, updated_at = NOW()
+		WHERE special_id = $1
+	`, s.SpecialID, s.Title, s.Description, s.StartsAt, s.EndsAt, s.Recurrence, s.RecurrenceDays,
+		s.DailyStartTime, s.DailyEndTime, s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("update special: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, ErrSpecialNotFound
+	}
+	return r.GetByID(ctx, s.SpecialID)
+}
+
+// Delete removes a special
+func (r *SpecialRepository) Delete(ctx context.Context, specialID uuid.UUID) error {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, `DELETE FROM poi_specials WHERE special_id = $1`, specialID)
+	if err != nil {
+		return fmt.Errorf("delete special: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrSpecialNotFound
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrTermsNotCurrent is returned when a user accepts a terms version other than the current one
+var ErrTermsNotCurrent = errors.New("only the current terms version can be accepted")
+
+// termsCacheTTL bounds how long the current terms version is served from
+// memory; every write request checks it
+const termsCacheTTL = time.Minute
+
+// TermsRepository handles terms of service versions and their acceptance
+type TermsRepository struct {
+	db *database.DB
+
+	mu        sync.RWMutex
+	current   *models.TermsVersion
+	expiresAt time.Time
+}
+
+// NewTermsRepository creates a new terms repository
+func NewTermsRepository(db *database.DB) *TermsRepository {
+	return &TermsRepository{db: db}
+}
+
+// CurrentVersion returns the newest published terms version, or nil when none
+// has been published
+func (r *TermsRepository) CurrentVersion(ctx context.Context) (*models.TermsVersion, error) {
+	r.mu.RLock()
+	current, fresh := r.current, time.Now().Before(r.expiresAt)
+	r.mu.RUnlock()
+	if fresh {
+		return current, nil
+	}
+
+	var v models.TermsVersion
+	err := r.db.Conn(ctx).GetContext(ctx, &v, `
+		SELECT version, terms_url, privacy_url, summary, published_at
+		FROM terms_versions
+		WHERE published_at <= NOW()
+		ORDER BY version DESC
+		LIMIT 1
+	`)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		current = nil
+	case err != nil:
+		return nil, fmt.Errorf("get current terms: %w", err)
+	default:
+		current = &v
+	}
+
+	r.mu.Lock()
+	r.current, r.expiresAt = current, time.Now().Add(termsCacheTTL)
+	r.mu.Unlock()
+	return current, nil
+}
+
+// AcceptedVersion returns the newest terms version the user accepted, or nil
+func (r *TermsRepository) AcceptedVersion(ctx context.Context, userID uuid.UUID) (*int, error) {
+	var version *int
+	err := r.db.Conn(ctx).GetContext(ctx, &version, `
+		SELECT MAX(version) FROM user_terms_acceptances WHERE user_id = $1
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get accepted terms: %w", err)
+	}
+	return version, nil
+}
+
+// Accept records that the user accepted the current terms version from the
+// given client. Accepting again keeps the first acceptance.
+func (r *TermsRepository) Accept(ctx context.Context, userID uuid.UUID, version int, ip, userAgent string) error {
+	current, err := r.CurrentVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if current == nil || current.Version != version {
+		return ErrTermsNotCurrent
+	}
+
+	_, err = r.db.Conn(ctx).ExecContext(ctx, `
+		INSERT INTO user_terms_acceptances (user_id, version, ip_address, user_agent)
+		VALUES ($1, $2, NULLIF($3, '')::inet, NULLIF($4, ''))
+		ON CONFLICT (user_id, version) DO NOTHING
+	`, userID, version, ip, userAgent)
+	if err != nil {
+		return fmt.Errorf("accept terms: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"maukemana-backend/internal/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrTextModerationNotFound is returned when a moderation entry does not exist
+var ErrTextModerationNotFound = errors.New("text moderation entry not found")
+
+// TextModeration is the original of a user generated text next to the sanitized
+// version stored by its target
+type TextModeration struct {
+	ModerationID uuid.UUID      `db:"moderation_id" json:"moderation_id"`
+	Field        string         `db:"field" json:"field"`
+	TargetID     uuid.UUID      `db:"target_id" json:"target_id"`
+	UserID       *uuid.UUID     `db:"user_id" json:"user_id,omitempty"`
+	Original     string         `db:"original" json:"original"`
+	Sanitized    string         `db:"sanitized" json:"sanitized"`
+	Flags        pq.StringArray `db:"flags" json:"flags"`
+	NeedsReview  bool           `db:"needs_review" json:"needs_review"`
+	ReviewedBy   *uuid.UUID     `db:"reviewed_by" json:"reviewed_by,omitempty"`
+	ReviewedAt   *time.Time     `db:"reviewed_at" json:"reviewed_at,omitempty"`
+	CreatedAt    time.Time      `db:"created_at" json:"created_at"`
+}
+
+// TextModerationRepository stores the outcomes of the text moderation pipeline
+type TextModerationRepository struct {
+	db *database.DB
+}
+
+// NewTextModerationRepository creates a new text moderation repository
+func NewTextModerationRepository(db *database.DB) *TextModerationRepository {
+	return &TextModerationRepository{db: db}
+}
+
+const textModerationColumns = `moderation_id, field, target_id, user_id, original, sanitized, flags, needs_review, reviewed_by, reviewed_at, created_at`
+
+// SaveTextModeration stores the moderation of a target's text, replacing the
+// entry of an earlier version and resetting its review
+func (r *TextModerationRepository) SaveTextModeration(ctx context.Context, m TextModeration) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		INSERT INTO text_moderation (field, target_id, user_id, original, sanitized, flags, needs_review)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (field, target_id) DO UPDATE
+		SET user_id = EXCLUDED.user_id, original = EXCLUDED.original, sanitized = EXCLUDED.sanitized,
+		    flags = EXCLUDED.flags, needs_review = EXCLUDED.needs_review,
+		    reviewed_by = NULL, reviewed_at = NULL, created_at = NOW()
+	`, m.Field, m.TargetID, m.UserID, m.Original, m.Sanitized, pq.Array(m.Flags), m.NeedsReview)
+	if err != nil {
+		return fmt.Errorf("save text moderation: %w", err)
+	}
+	return nil
+}
+
+// ClearTextModeration removes the entry of a target whose text is now clean
+func (r *TextModerationRepository) ClearTextModeration(ctx context.Context, field string, targetID uuid.UUID) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `DELETE FROM text_moderation WHERE field = $1 AND target_id = $2`, field, targetID)
+	if err != nil {
+		return fmt.Errorf("clear text moderation: %w", err)
+	}
+	return nil
+}
+
+// ListPending returns the flagged texts awaiting manual review, oldest first.
+// An empty field lists every field.
+func (r *TextModerationRepository) ListPending(ctx context.Context, field string, limit, offset int) ([]TextModeration, error) {
+	entries := []TextModeration{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &entries, `
+		SELECT `+textModerationColumns+`
+		FROM text_moderation
+		WHERE needs_review AND reviewed_at IS NULL AND ($1 = '' OR field = $1)
+		ORDER BY created_at
+		LIMIT $2 OFFSET $3
+	`, field, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list pending text moderation: %w", err)
+	}
+	return entries, nil
+}
+
+// Resolve marks a flagged text as reviewed
+func (r *TextModerationRepository) Resolve(ctx context.Context, id, reviewerID uuid.UUID) (*TextModeration, error) {
+	var entry TextModeration
+	err := r.db.Conn(ctx).GetContext(ctx, &entry, `
+		UPDATE text_moderation
+		SET reviewed_by = $2, reviewed_at = NOW()
+		WHERE moderation_id = $1
+		RETURNING `+textModerationColumns, id, reviewerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTextModerationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("resolve text moderation: %w", err)
+	}
+	return &entry, nil
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var ErrTranslationNotFound = errors.New("translation not found")
+
+// TranslationRepository handles localized POI content
+type TranslationRepository struct {
+	db *database.DB
+}
+
+// NewTranslationRepository creates a new translation repository
+func NewTranslationRepository(db *database.DB) *TranslationRepository {
+	return &TranslationRepository{db: db}
+}
+
+// ListByPOI returns every translation of a POI
+func (r *TranslationRepository) ListByPOI(ctx context.Context, poiID uuid.UUID) ([]models.POITranslation, error) {
+	translations := []models.POITranslation{}
+	query := `
+		SELECT poi_id, locale, name, description, updated_by, created_at, updated_at
+		FROM poi_translations
+		WHERE poi_id = $1
+		ORDER BY locale
+	`
+	if err := r.db.Conn(ctx).SelectContext(ctx, &translations, query, poiID); err != nil {
+		return nil, fmt.Errorf("list translations: %w", err)
+	}
+	return translations, nil
+}
+
+// Upsert creates or replaces the translation of a POI for a locale
+func (r *TranslationRepository) Upsert(ctx context.Context, t *models.POITranslation) (*models.POITranslation, error) {
+	var out models.POITranslation
+	query := `
+		INSERT INTO poi_translations (poi_id, locale, name, description, updated_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (poi_id, locale) DO UPDATE
+		SET name = EXCLUDED.name,
+		    description = EXCLUDED.description,
+		    updated_by = EXCLUDED.updated_by,
+		    updated_at = NOW()
+		RETURNING poi_id, locale, name, description, updated_by, created_at, updated_at
+	`
+	err := r.db.Conn(ctx).GetContext(ctx, &out, query, t.PoiID, t.Locale, t.Name, t.Description, t.UpdatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("upsert translation: %w", err)
+	}
+	return &out, nil
+}
+
+// Delete removes the translation of a POI for a locale
+func (r *TranslationRepository) Delete(ctx context.Context, poiID uuid.UUID, locale string) error {
+	result, err := r.db.Conn(ctx).ExecContext(ctx,
+		`DELETE FROM poi_translations WHERE poi_id = $1 AND locale = $2`, poiID, locale)
+	if err != nil {
+		return fmt.Errorf("delete translation: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete translation rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrTranslationNotFound
+	}
+	return nil
+}
+
+// Get returns the translation of a POI for a locale
+func (r *TranslationRepository) Get(ctx context.Context, poiID uuid.UUID, locale string) (*models.POITranslation, error) {
+	var t models.POITranslation
+	query := `
+		SELECT poi_id, locale, name, description, updated_by, created_at, updated_at
+		FROM poi_translations
+		WHERE poi_id = $1 AND locale = $2
+	`
+	err := r.db.Conn(ctx).GetContext(ctx, &t, query, poiID, locale)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTranslationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get translation: %w", err)
+	}
+	return &t, nil
+}
@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrUploadNotFound is returned when an upload record does not exist or is no
+// longer open
+var ErrUploadNotFound = errors.New("upload not found")
+
+// UploadRepository tracks presigned uploads from presign to finalize
+type UploadRepository struct {
+	db *database.DB
+}
+
+// NewUploadRepository creates a new upload repository
+func NewUploadRepository(db *database.DB) *UploadRepository {
+	return &UploadRepository{db: db}
+}
+
+const uploadColumns = `upload_id, user_id, storage_key, category, content_type, expected_size_bytes, bytes_uploaded, multipart, status, last_heartbeat_at, expires_at, finalized_at, created_at, updated_at`
+
+// Create stores a new pending upload
+func (r *UploadRepository) Create(ctx context.Context, u *models.Upload) (*models.Upload, error) {
+	var created models.Upload
+	err := r.db.Conn(ctx).GetContext(ctx, &created, `
+		INSERT INTO uploads (upload_id, user_id, storage_key, category, content_type, expected_size_bytes, multipart, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+uploadColumns,
+		u.UploadID, u.UserID, u.StorageKey, u.Category, u.ContentType, u.ExpectedSizeBytes, u.Multipart, u.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("create upload: %w", err)
+	}
+	return &created, nil
+}
+
+// Get returns an upload of userID
+func (r *UploadRepository) Get(ctx context.Context, uploadID, userID uuid.UUID) (*models.Upload, error) {
+	return r.get(ctx, `upload_id = $1 AND user_id = $2`, uploadID, userID)
+}
+
+// GetByKey returns the upload of userID stored under key
+func (r *UploadRepository) GetByKey(ctx context.Context, key string, userID uuid.UUID) (*models.Upload, error) {
+	return r.get(ctx, `storage_key = $1 AND user_id = $2`, key, userID)
+}
+
+func (r *UploadRepository) get(ctx context.Context, where string, args ...interface{}) (*models.Upload, error) {
+	var u models.Upload
+	err := r.db.Conn(ctx).GetContext(ctx, &u, `SELECT `+uploadColumns+` FROM uploads WHERE `+where, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUploadNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get upload: %w", err)
+	}
+	return &u, nil
+}
+
+// RecordProgress stores the bytes a client reports as uploaded. Progress
+// never moves backwards. It returns ErrUploadNotFound when the upload is
+// finalized or expired.
+func (r *UploadRepository) RecordProgress(ctx context.Context, uploadID uuid.UUID, bytesUploaded int64) (*models.Upload, error) {
+	var u models.Upload
+	err := r.db.Conn(ctx).GetContext(ctx, &u, `
+		UPDATE uploads
+		SET bytes_uploaded = GREATEST(bytes_uploaded, $2), status = 'uploading',
+		    last_heartbeat_at = NOW(), updated_at = NOW()
+		WHERE upload_id = $1 AND status IN ('pending', 'uploading') AND expires_at > NOW()
+		RETURNING `+uploadColumns,
+		uploadID, bytesUploaded)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUploadNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("record upload progress: %w", err)
+	}
+	return &u, nil
+}
+
+// MarkFinalized records that an open upload was handed to the image pipeline.
+// It returns ErrUploadNotFound when the upload is already finalized or expired.
+func (r *UploadRepository) MarkFinalized(ctx context.Context, uploadID uuid.UUID) error {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, `
+		UPDATE uploads
+		SET status = 'finalized', bytes_uploaded = expected_size_bytes, finalized_at = NOW(), updated_at = NOW()
+		WHERE upload_id = $1 AND status IN ('pending', 'uploading')
+	`, uploadID)
+	if err != nil {
+		return fmt.Errorf("finalize upload: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrUploadNotFound
+	}
+	return nil
+}
+
+// ListExpired returns up to limit open uploads past their expiry, oldest
+// first, with the storage upload ID of multipart uploads still active
+func (r *UploadRepository) ListExpired(ctx context.Context, limit int) ([]models.Upload, error) {
+	uploads := []models.Upload{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &uploads, `
+		SELECT u.upload_id, u.user_id, u.storage_key, u.category, u.content_type, u.expected_size_bytes,
+		       u.bytes_uploaded, u.multipart, u.status, u.last_heartbeat_at, u.expires_at, u.finalized_at,
+		       u.created_at, u.updated_at, m.storage_upload_id
+		FROM uploads u
+		LEFT JOIN multipart_uploads m ON m.upload_id = u.upload_id AND m.status = 'active'
+		WHERE u.status IN ('pending', 'uploading') AND u.expires_at <= NOW()
+		ORDER BY u.expires_at
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list expired uploads: %w", err)
+	}
+	return uploads, nil
+}
+
+// MarkExpired records that an upload was never finalized, aborting its
+// multipart upload record if any. It returns ErrUploadNotFound when the
+// upload was finalized in the meantime.
+func (r *UploadRepository) MarkExpired(ctx context.Context, uploadID uuid.UUID) error {
+	var expired bool
+	err := r.db.Conn(ctx).QueryRowxContext(ctx, `
+		WITH expired AS (
+			UPDATE uploads SET status = 'expired', updated_at = NOW()
+			WHERE upload_id = $1 AND status IN ('pending', 'uploading')
+			RETURNING upload_id
+		), aborted AS (
+			UPDATE multipart_uploads SET status = 'aborted', updated_at = NOW()
+			WHERE upload_id IN (SELECT upload_id FROM expired) AND status = 'active'
+		)
+		SELECT EXISTS (SELECT 1 FROM expired)
+	`, uploadID).Scan(&expired)
+	if err != nil {
+		return fmt.Errorf("expire upload: %w", err)
+	}
+	if !expired {
+		return ErrUploadNotFound
+	}
+	return nil
+}
+
+// Reject records an upload rejected by content sniffing and closes its upload
+// record, if it has one
+func (r *UploadRepository) Reject(ctx context.Context, rejection *models.UploadRejection) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, `
+		WITH closed AS (
+			UPDATE uploads SET status = 'rejected', updated_at = NOW()
+			WHERE upload_id = $1 AND status IN ('pending', 'uploading')
+		)
+		INSERT INTO upload_rejections (upload_id, user_id, storage_key, reason, detail, declared_content_type, ip_address, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7::inet, $8)
+	`, rejection.UploadID, rejection.UserID, rejection.StorageKey, rejection.Reason, rejection.Detail,
+		rejection.DeclaredContentType, rejection.IPAddress, rejection.UserAgent)
+	if err != nil {
+		return fmt.Errorf("record upload rejection: %w", err)
+	}
+	return nil
+}
+
+// ListRejections returns rejected uploads, newest first, optionally only those of userID
+func (r *UploadRepository) ListRejections(ctx context.Context, userID *uuid.UUID, limit, offset int) ([]models.UploadRejection, error) {
+	rejections := []models.UploadRejection{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &rejections, `
+		SELECT rejection_id, upload_id, user_id, storage_key, reason, detail, declared_content_type,
+		       host(ip_address) AS ip_address, user_agent, created_at
+		FROM upload_rejections
+		WHERE $1::uuid IS NULL OR user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list upload rejections: %w", err)
+	}
+	return rejections, nil
+}
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// CachedUserRepository serves GetByClerkID from memory for the auth
+// middleware, which looks the caller up on every request. Entries expire
+// after a TTL; Invalidate drops a user early after their role or account
+// changes. Other methods pass through to the wrapped repository.
+type CachedUserRepository struct {
+	*UserRepository
+
+	ttl     time.Duration
+	maxSize int
+	lookups metric.Int64Counter
+
+	mu      sync.RWMutex
+	entries map[string]cachedUser // keyed by clerk_id
+	byUser  map[uuid.UUID]string  // user_id -> clerk_id, for Invalidate
+}
+
+type cachedUser struct {
+	user      User
+	expiresAt time.Time
+}
+
+// NewCachedUserRepository wraps repo with a TTL cache of at most maxSize users
+func NewCachedUserRepository(repo *UserRepository, ttl time.Duration, maxSize int) *CachedUserRepository {
+	lookups, err := otel.Meter("maukemana-backend/internal/repositories").Int64Counter("auth.user_cache.lookups",
+		metric.WithDescription("Signed-in user lookups by cache result"),
+		metric.WithUnit("{lookup}"))
+	if err != nil {
+		slog.Warn("failed to create user cache counter", "error", err)
+	}
+	return &CachedUserRepository{
+		UserRepository: repo,
+		ttl:            ttl,
+		maxSize:        maxSize,
+		lookups:        lookups,
+		entries:        make(map[string]cachedUser),
+		byUser:         make(map[uuid.UUID]string),
+	}
+}
+
+// GetByClerkID returns the cached user or loads and caches it. Misses
+// (sql.ErrNoRows) are not cached so a freshly synced user is found next time.
+func (r *CachedUserRepository) GetByClerkID(ctx context.Context, clerkID string) (*User, error) {
+	now := time.Now()
+	r.mu.RLock()
+	entry, ok := r.entries[clerkID]
+	r.mu.RUnlock()
+	if ok && now.Before(entry.expiresAt) {
+		r.record(ctx, "hit")
+		user := entry.user
+		return &user, nil
+	}
+	r.record(ctx, "miss")
+
+	user, err := r.UserRepository.GetByClerkID(ctx, clerkID)
+	if err != nil || r.ttl <= 0 {
+		return user, err
+	}
+
+	r.mu.Lock()
+	r.makeRoom(now)
+	r.entries[clerkID] = cachedUser{user: *user, expiresAt: now.Add(r.ttl)}
+	r.byUser[user.UserID] = clerkID
+	r.mu.Unlock()
+	return user, nil
+}
+
+// UpdateClerkID links a Clerk ID and drops any stale entry for the user
+func (r *CachedUserRepository) UpdateClerkID(ctx context.Context, userID uuid.UUID, clerkID string) error {
+	r.Invalidate(userID)
+	return r.UserRepository.UpdateClerkID(ctx, userID, clerkID)
+}
+
+// Invalidate drops a user from the cache so their next request reloads them
+func (r *CachedUserRepository) Invalidate(userID uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if clerkID, ok := r.byUser[userID]; ok {
+		delete(r.entries, clerkID)
+		delete(r.byUser, userID)
+	}
+}
+
+// makeRoom evicts expired entries, then arbitrary ones, until one more fits.
+// Callers hold the write lock.
+func (r *CachedUserRepository) makeRoom(now time.Time) {
+	if len(r.entries) < r.maxSize {
+		return
+	}
+	for k, e := range r.entries {
+		if !now.Before(e.expiresAt) {
+			delete(r.entries, k)
+			delete(r.byUser, e.user.UserID)
+		}
+	}
+	for k, e := range r.entries {
+		if len(r.entries) < r.maxSize {
+			return
+		}
+		delete(r.entries, k)
+		delete(r.byUser, e.user.UserID)
+	}
+}
+
+func (r *CachedUserRepository) record(ctx context.Context, result string) {
+	if r.lookups != nil {
+		r.lookups.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+	}
+}
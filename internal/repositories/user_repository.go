@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // UserRepository handles user database operations
@@ -33,12 +35,16 @@ type User struct {
 	UpdatedAt  time.Time      `db:"updated_at" json:"updated_at"`
 }
 
-// GetByClerkID retrieves a user by Clerk ID
+// GetByClerkID retrieves a user by Clerk ID, including Clerk IDs linked to the
+// user from a merged account
 func (r *UserRepository) GetByClerkID(ctx context.Context, clerkID string) (*User, error) {
 	var user User
 	// Note: Fetching minimal fields as per auth middleware requirements, can expand if needed
-	query := "SELECT user_id, email, name, role, clerk_id, picture_url FROM users WHERE clerk_id = $1"
-	err := r.db.QueryRowContext(ctx, query, clerkID).Scan(
+	query := `SELECT user_id, email, name, role, clerk_id, picture_url FROM users
+		WHERE user_id = COALESCE(
+			(SELECT user_id FROM users WHERE clerk_id = $1),
+			(SELECT user_id FROM user_identities WHERE clerk_id = $1))`
+	err := r.db.Conn(ctx).QueryRowContext(ctx, query, clerkID).Scan(
 		&user.UserID, &user.Email, &user.Name, &user.Role, &user.ClerkID, &user.PictureURL,
 	)
 	if err != nil {
@@ -54,7 +60,7 @@ func (r *UserRepository) GetByClerkID(ctx context.Context, clerkID string) (*Use
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*User, error) {
 	var user User
 	query := "SELECT user_id, email, name, role, clerk_id, picture_url FROM users WHERE email = $1"
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
+	err := r.db.Conn(ctx).QueryRowContext(ctx, query, email).Scan(
 		&user.UserID, &user.Email, &user.Name, &user.Role, &user.ClerkID, &user.PictureURL,
 	)
 	if err != nil {
@@ -68,7 +74,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*User, e
 
 // UpdateClerkID updates the Clerk ID for an existing user
 func (r *UserRepository) UpdateClerkID(ctx context.Context, userID uuid.UUID, clerkID string) error {
-	_, err := r.db.ExecContext(ctx,
+	_, err := r.db.Conn(ctx).ExecContext(ctx,
 		"UPDATE users SET clerk_id = $1 WHERE user_id = $2",
 		clerkID, userID,
 	)
@@ -81,7 +87,7 @@ func (r *UserRepository) UpdateClerkID(ctx context.Context, userID uuid.UUID, cl
 // Create creates a new user
 func (r *UserRepository) Create(ctx context.Context, email, name, picture, clerkID, role string) (*User, error) {
 	var userID uuid.UUID
-	err := r.db.QueryRowContext(ctx,
+	err := r.db.Conn(ctx).QueryRowContext(ctx,
 		`INSERT INTO users (email, name, picture_url, clerk_id, role)
 		 VALUES ($1, $2, $3, $4, $5)
 		 RETURNING user_id`,
@@ -100,3 +106,30 @@ func (r *UserRepository) Create(ctx context.Context, email, name, picture, clerk
 		Role:       sql.NullString{String: role, Valid: role != ""},
 	}, nil
 }
+
+// GetProfilesByIDs returns the public profiles of several users keyed by user ID.
+// Users without a gamification profile get the default level and zero XP.
+func (r *UserRepository) GetProfilesByIDs(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]models.UserProfile, error) {
+	var profiles []models.UserProfile
+	err := r.db.Conn(ctx).SelectContext(ctx, &profiles, `
+		SELECT u.user_id, p.username, p.avatar_url,
+		       COALESCE(p.scout_level, 1) as scout_level,
+		       COALESCE(p.global_xp, 0) as global_xp,
+		       COALESCE(p.impact_score, 0) as impact_score,
+		       COALESCE(p.created_at, u.created_at) as created_at,
+		       COALESCE(p.updated_at, u.updated_at) as updated_at,
+		       u.name, u.picture_url
+		FROM users u
+		LEFT JOIN user_profiles p ON p.user_id = u.user_id
+		WHERE u.user_id = ANY($1::uuid[])
+	`, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("get profiles by ids: %w", err)
+	}
+
+	byID := make(map[uuid.UUID]models.UserProfile, len(profiles))
+	for _, p := range profiles {
+		byID[p.UserID] = p
+	}
+	return byID, nil
+}
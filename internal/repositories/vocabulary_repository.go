@@ -28,27 +28,57 @@ type Vocabulary struct {
 	Aliases   pq.StringArray `db:"aliases" json:"aliases"`
 	Icon      *string        `db:"icon" json:"icon,omitempty"`
 	IsActive  bool           `db:"is_active" json:"is_active"`
+	Label     string         `db:"label" json:"label"` // Localized display label
 }
 
-// GetActive retrieves active vocabularies, optionally filtered by type
-func (r *VocabularyRepository) GetActive(ctx context.Context, vocabType string) ([]Vocabulary, error) {
+// GetActive retrieves active vocabularies, optionally filtered by type, with labels
+// in the first available of locales (in preference order), falling back to key
+func (r *VocabularyRepository) GetActive(ctx context.Context, vocabType string, locales []string) ([]Vocabulary, error) {
 	query := `
-		SELECT vocab_id, vocab_type, key, aliases, icon, is_active
+		SELECT vocab_id, vocab_type, key, aliases, icon, is_active,
+		       COALESCE(` + labelSubquery("vocabulary", "vocabularies.vocab_id", "$1") + `, key) as label
 		FROM vocabularies
 		WHERE is_active = true
 	`
-	args := []interface{}{}
+	args := []interface{}{pq.StringArray(locales)}
 
 	if vocabType != "" {
-		query += " AND vocab_type = $1"
+		query += " AND vocab_type = $2"
 		args = append(args, vocabType)
 	}
 	query += " ORDER BY vocab_type, key"
 
 	var vocabularies []Vocabulary
-	err := r.db.SelectContext(ctx, &vocabularies, query, args...)
+	err := r.db.Conn(ctx).SelectContext(ctx, &vocabularies, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("get active vocabularies: %w", err)
 	}
 	return vocabularies, nil
 }
+
+// Amenity is an amenity a POI can be tagged with, backed by an 'amenity' vocabulary entry
+type Amenity struct {
+	AmenityID   uuid.UUID `db:"amenity_id" json:"amenity_id"`
+	Key         string    `db:"key" json:"key"`
+	Icon        *string   `db:"icon" json:"icon,omitempty"`
+	Label       string    `db:"label" json:"label"`                         // Localized display label
+	DerivedFrom *string   `db:"legacy_field" json:"derived_from,omitempty"` // Set from this POI field instead of amenities
+}
+
+// GetAmenities returns the active amenities with labels in the first available
+// of locales, falling back to the vocabulary key
+func (r *VocabularyRepository) GetAmenities(ctx context.Context, locales []string) ([]Amenity, error) {
+	amenities := []Amenity{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &amenities, `
+		SELECT a.amenity_id, a.key, v.icon, a.legacy_field,
+		       COALESCE(`+labelSubquery("vocabulary", "v.vocab_id", "$1")+`, v.key) as label
+		FROM amenities a
+		JOIN vocabularies v ON v.vocab_id = a.vocab_id
+		WHERE v.is_active = true
+		ORDER BY a.sort_order, a.key
+	`, pq.StringArray(locales))
+	if err != nil {
+		return nil, fmt.Errorf("get amenities: %w", err)
+	}
+	return amenities, nil
+}
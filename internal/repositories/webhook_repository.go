@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrWebhookNotFound is returned when a webhook subscription does not exist
+var ErrWebhookNotFound = errors.New("webhook subscription not found")
+
+// WebhookRepository handles webhook subscriptions and their delivery log
+type WebhookRepository struct {
+	db *database.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *database.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+const webhookColumns = `subscription_id, url, secret, event_types, description, is_active, created_by, created_at, updated_at`
+
+// List returns all webhook subscriptions, newest first
+func (r *WebhookRepository) List(ctx context.Context) ([]models.WebhookSubscription, error) {
+	subs := []models.WebhookSubscription{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &subs, `
+		SELECT `+webhookColumns+`
+		FROM webhook_subscriptions
+		ORDER BY created_at DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks: %w", err)
+	}
+	return subs, nil
+}
+
+// GetByID returns a webhook subscription
+func (r *WebhookRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.WebhookSubscription, error) {
+	var sub models.WebhookSubscription
+	err := r.db.Conn(ctx).GetContext(ctx, &sub, `
+		SELECT `+webhookColumns+`
+		FROM webhook_subscriptions
+		WHERE subscription_id = $1
+	`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get webhook: %w", err)
+	}
+	return &sub, nil
+}
+
+// Create stores a new webhook subscription
+func (r *WebhookRepository) Create(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	var created models.WebhookSubscription
+	err := r.db.Conn(ctx).GetContext(ctx, &created, `
+		INSERT INTO webhook_subscriptions (url, secret, event_types, description, is_active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+webhookColumns,
+		sub.URL, sub.Secret, eventTypesArray(sub.EventTypes), sub.Description, sub.IsActive, sub.CreatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("create webhook: %w", err)
+	}
+	return &created, nil
+}
+
+// Update replaces the editable fields of a subscription. An empty secret keeps the current one.
+func (r *WebhookRepository) Update(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	var updated models.WebhookSubscription
+	err := r.db.Conn(ctx).GetContext(ctx, &updated, `
+		UPDATE webhook_subscriptions
+		SET url = $2, secret = COALESCE(NULLIF($3, ''), secret), event_types = $4,
+		    description = $5, is_active = $6, updated_at = NOW()
+		WHERE subscription_id = $1
+		RETURNING `+webhookColumns,
+		sub.SubscriptionID, sub.URL, sub.Secret, eventTypesArray(sub.EventTypes), sub.Description, sub.IsActive)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("update webhook: %w", err)
+	}
+	return &updated, nil
+}
+
+// Delete removes a subscription and its delivery log
+func (r *WebhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.Conn(ctx).ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE subscription_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete webhook: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// ListDeliveries returns the most recent deliveries of a subscription
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, id uuid.UUID, limit, offset int) ([]models.WebhookDelivery, error) {
+	deliveries := []models.WebhookDelivery{}
+	err := r.db.Conn(ctx).SelectContext(ctx, &deliveries, `
+		SELECT d.delivery_id, d.subscription_id, d.event_id, e.event_type, d.status, d.attempts,
+		       d.next_attempt_at, d.last_status_code, d.last_error, d.delivered_at, d.created_at
+		FROM webhook_deliveries d
+		JOIN outbox_events e ON e.event_id = d.event_id
+		WHERE d.subscription_id = $1
+		ORDER BY d.created_at DESC
+		LIMIT $2 OFFSET $3
+	`, id, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// eventTypesArray stores a nil filter as an empty array (= all events)
+func eventTypesArray(types pq.StringArray) pq.StringArray {
+	if types == nil {
+		return pq.StringArray{}
+	}
+	return types
+}
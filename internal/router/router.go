@@ -1,71 +1,314 @@
 package router
 
 import (
+	"context"
+	"crypto/subtle"
 	"log"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
+	"maukemana-backend/internal/alerts"
+	"maukemana-backend/internal/analytics"
 	"maukemana-backend/internal/auth"
+	"maukemana-backend/internal/breaker"
+	"maukemana-backend/internal/captcha"
 	"maukemana-backend/internal/config"
 	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/dataexport"
+	"maukemana-backend/internal/embedding"
+	"maukemana-backend/internal/events"
+	"maukemana-backend/internal/geoip"
+	"maukemana-backend/internal/gql"
 	"maukemana-backend/internal/handlers"
 	"maukemana-backend/internal/imaging"
+	"maukemana-backend/internal/mail"
 	"maukemana-backend/internal/middleware"
+	"maukemana-backend/internal/observability"
 	"maukemana-backend/internal/repositories"
+	"maukemana-backend/internal/routing"
+	"maukemana-backend/internal/search"
 	"maukemana-backend/internal/services"
+	"maukemana-backend/internal/spam"
 	"maukemana-backend/internal/storage"
+	"maukemana-backend/internal/textmod"
+	"maukemana-backend/internal/uploadexpiry"
+	"maukemana-backend/internal/utils"
+	"maukemana-backend/internal/validation"
 )
 
-// Setup creates and configures the Gin router
-func Setup(db *database.DB) *gin.Engine {
+// Setup creates and configures the Gin router. The returned function stops the
+// background workers started for it, waiting until ctx is done at most.
+func Setup(db *database.DB) (*gin.Engine, func(ctx context.Context) error) {
 	// Initialize repositories
 	poiRepo := repositories.NewPOIRepository(db)
 
 	userRepo := repositories.NewUserRepository(db)
+	roleRepo := repositories.NewRoleRepository(db)
+	authCache := config.GetAuthCacheSettings()
+	authUsers := repositories.NewCachedUserRepository(userRepo, authCache.UserTTL, authCache.UserCacheSize)
 	categoryRepo := repositories.NewCategoryRepository(db)
 	vocabRepo := repositories.NewVocabularyRepository(db)
 	photoRepo := repositories.NewPhotoRepository(db)
 	// Services
-	geocodingService := services.NewMockGeocodingService()
+	features := middleware.Features{middleware.FeatureGeocoding: true}
+	breakerSettings := config.GetBreakerSettings()
+	geocodingService := services.NewBreakerGeocodingService(services.NewMockGeocodingService(), breaker.New("geocoding", breaker.Settings{
+		MaxFailures: breakerSettings.MaxFailures,
+		OpenFor:     breakerSettings.OpenFor,
+	}))
+	poiWorkflow := services.NewPOIWorkflowService(poiRepo, db, services.DefaultPOITransitions())
+	serviceAreaRepo := repositories.NewServiceAreaRepository(db)
+	poiWorkflow.Guard(services.POIStatusPending, services.ServiceAreaGuard(serviceAreaRepo))
+	// Only the assigned reviewer decides on a POI unless they force it
+	poiWorkflow.Guard(services.POIStatusApproved, services.AssignmentGuard(poiRepo))
+	poiWorkflow.Guard(services.POIStatusRejected, services.AssignmentGuard(poiRepo))
 
 	// Initialize handlers
-	poiHandler := handlers.NewPOIHandler(poiRepo, geocodingService)
+	menuRepo := repositories.NewMenuRepository(db)
+	reviewRepo := repositories.NewReviewRepository(db)
+	poiHandler := handlers.NewPOIHandler(poiRepo, geocodingService, poiWorkflow, handlers.POIRelations{
+		Menus:   menuRepo,
+		Reviews: reviewRepo,
+	})
+	poiHandler.UseServiceAreas(serviceAreaRepo)
+	poiHandler.UseFilterOptions(repositories.NewFilterOptionsRepository(db))
+	poiHandler.UseAssignmentTimeout(config.GetReviewSettings().AssignmentTimeout)
+	serviceAreaHandler := handlers.NewServiceAreaHandler(serviceAreaRepo)
+	menuHandler := handlers.NewMenuHandler(menuRepo, poiRepo)
+	reviewHandler := handlers.NewReviewHandler(reviewRepo, poiRepo)
 	savedPOIRepo := repositories.NewSavedPOIRepository(db)
 	savedPOIHandler := handlers.NewSavedPOIHandler(savedPOIRepo)
 
 	commentRepo := repositories.NewCommentRepository(db)
 	commentHandler := handlers.NewCommentHandler(commentRepo)
+	commentModerationHandler := handlers.NewCommentModerationHandler(commentRepo)
+	spamRepo := repositories.NewSpamRepository(db)
+	spamGuard := spam.NewGuard(spamRepo, config.GetSpamSettings())
+	commentHandler.UseSpamGuard(spamGuard)
+	reviewHandler.UseSpamGuard(spamGuard)
+	spamHandler := handlers.NewSpamHandler(spamRepo)
+
+	textModerationRepo := repositories.NewTextModerationRepository(db)
+	textModerator, err := textmod.NewModeratorFromEnv()
+	if err != nil {
+		log.Printf("Warning: text moderation not configured: %v", err)
+	}
+	textPipeline := textmod.NewPipeline(textModerator, textModerationRepo)
+	poiHandler.UseTextModerator(textPipeline)
+	commentHandler.UseTextModerator(textPipeline)
+	reviewHandler.UseTextModerator(textPipeline)
+	textModerationHandler := handlers.NewTextModerationHandler(textModerationRepo)
+	proposalRepo := repositories.NewEditProposalRepository(db)
+	proposalHandler := handlers.NewEditProposalHandler(proposalRepo, poiRepo)
+	verificationHandler := handlers.NewBusinessVerificationHandler(repositories.NewBusinessVerificationRepository(db), poiRepo)
+	specialRepo := repositories.NewSpecialRepository(db)
+	specialHandler := handlers.NewSpecialHandler(specialRepo, poiRepo)
+	translationRepo := repositories.NewTranslationRepository(db)
+	translationHandler := handlers.NewTranslationHandler(translationRepo, poiRepo, config.GetSupportedLocales())
 	categoryHandler := handlers.NewCategoryHandler(categoryRepo)
 	vocabHandler := handlers.NewVocabularyHandler(vocabRepo)
-	photoHandler := handlers.NewPhotoHandler(photoRepo)
-	authHandler := handlers.NewAuthHandler(userRepo)
+	labelHandler := handlers.NewLabelHandler(repositories.NewLabelRepository(db), config.GetSupportedLocales())
+	photoHandler := handlers.NewPhotoHandler(photoRepo, poiRepo)
+	itineraryHandler := handlers.NewItineraryHandler(repositories.NewItineraryRepository(db), userRepo)
+
+	// POI lifecycle events: the outbox relay fans events out to the in-process
+	// bus and to webhook subscriptions; the dispatcher delivers webhooks
+	eventBus := events.NewBus()
+	eventBus.Subscribe("", events.LogEvents)
+	outboxRepo := repositories.NewOutboxRepository(db)
+	events.NewRelay(outboxRepo, eventBus, 2*time.Second).Start()
+	events.NewDispatcher(outboxRepo, 5*time.Second).Start()
+	webhookHandler := handlers.NewWebhookHandler(repositories.NewWebhookRepository(db))
+
+	// Personal data exports are built in the background; users get a notification when ready
+	accountRepo := repositories.NewAccountRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	questionHandler := handlers.NewQuestionHandler(repositories.NewQuestionRepository(db), poiRepo)
+	questionHandler.UseNotifier(notificationRepo)
+	dataexport.NewWorker(accountRepo, notificationRepo, 10*time.Second).Start()
+	accountHandler := handlers.NewAccountHandler(accountRepo, notificationRepo, authUsers)
+
+	// Saved searches; alerts notify users about newly approved POIs matching them
+	savedSearchRepo := repositories.NewSavedSearchRepository(db)
+	alerts.NewWorker(savedSearchRepo, poiRepo, notificationRepo, 15*time.Minute).Start()
+	savedSearchHandler := handlers.NewSavedSearchHandler(savedSearchRepo)
+
+	// Optional notification emails: POI decisions, verification decisions and
+	// the saved-POI digest. Unsubscribe links work whenever a secret is set.
+	mailSettings := config.GetMailSettings()
+	emailRepo := repositories.NewEmailRepository(db)
+	unsubscribeTokens := mail.NewTokens(mailSettings.UnsubscribeSecret)
+	mailer, err := mail.NewFromConfig(mailSettings)
+	if err != nil {
+		log.Printf("Warning: email not configured: %v", err)
+	} else if mailer != nil {
+		notifier := mail.NewNotifier(mailer, emailRepo, unsubscribeTokens, mailSettings)
+		notifier.Start()
+		poiWorkflow.Subscribe(notifier.POIStatusChanged)
+		verificationHandler.UseNotifier(notifier)
+		features[middleware.FeatureNotifications] = true
+	}
+	emailHandler := handlers.NewEmailHandler(emailRepo, unsubscribeTokens)
+	profileHandler := handlers.NewProfileHandler(repositories.NewProfileRepository(db))
+	blockHandler := handlers.NewBlockHandler(repositories.NewBlockRepository(db))
+	announcementHandler := handlers.NewAnnouncementHandler(repositories.NewAnnouncementRepository(db))
+
+	// Sampled, anonymized search analytics
+	searchAnalyticsRepo := repositories.NewSearchAnalyticsRepository(db)
+	if recorder := analytics.NewSearchRecorder(searchAnalyticsRepo, config.GetSearchAnalyticsSettings()); recorder != nil {
+		recorder.Start()
+		poiHandler.UseSearchAnalytics(recorder)
+	}
+	analyticsHandler := handlers.NewAnalyticsHandler(searchAnalyticsRepo, repositories.NewHeatmapRepository(db))
+
+	// Deduplicated POI detail views feeding the trending carousel
+	poiViewRepo := repositories.NewPOIViewRepository(db)
+	if recorder := analytics.NewViewRecorder(poiViewRepo, config.GetViewAnalyticsSettings()); recorder != nil {
+		recorder.Start()
+		poiHandler.UseViewRecorder(recorder)
+	}
+	trendingHandler := handlers.NewTrendingHandler(poiViewRepo)
+	syncHandler := handlers.NewSyncHandler(poiRepo)
+
+	// Anonymous browsing sessions (X-Session-ID) for signed-out clients
+	sessionRepo := repositories.NewAnonymousSessionRepository(db)
+	poiHandler.UseSessions(sessionRepo)
+	sessionHandler := handlers.NewSessionHandler(sessionRepo)
+
+	// Optional external search index for ?q=, kept in sync from the event bus
+	searchIndex, err := search.NewFromConfig(config.GetSearchSettings())
+	if err != nil {
+		log.Printf("Warning: search index not configured: %v", err)
+	} else if searchIndex != nil {
+		search.NewSyncer(searchIndex, poiRepo).Register(eventBus)
+		poiHandler.UseTextSearch(searchIndex)
+	}
+
+	// Optional routing provider for travel_mode on /pois/nearby
+	travelTimes, err := routing.NewFromConfig(config.GetRoutingSettings())
+	if err != nil {
+		log.Printf("Warning: routing provider not configured: %v", err)
+	} else if travelTimes != nil {
+		poiHandler.UseTravelTimes(travelTimes)
+	}
 
-	// Initialize R2 storage (optional - continues without if not configured)
+	// Optional IP geolocation for nearest-first searches sent without coordinates
+	ipLocator, err := geoip.NewFromConfig(config.GetGeoIPSettings())
+	if err != nil {
+		log.Printf("Warning: IP geolocation not configured: %v", err)
+	} else if ipLocator != nil {
+		poiHandler.UseIPLocator(ipLocator)
+	}
+
+	// Optional embedding provider for semantic search, re-embedding POIs from the event bus
+	embeddingRepo := repositories.NewEmbeddingRepository(db)
+	embedder, err := embedding.NewFromConfig(config.GetEmbeddingSettings())
+	if err != nil {
+		log.Printf("Warning: semantic search not configured: %v", err)
+	} else if embedder != nil {
+		embedding.NewSyncer(embedder, poiRepo, embeddingRepo).Register(eventBus)
+	}
+	semanticSearchHandler := handlers.NewSemanticSearchHandler(embedder, embeddingRepo, poiRepo)
+	authHandler := handlers.NewAuthHandler(userRepo)
+	termsRepo := repositories.NewTermsRepository(db)
+	authHandler.UseTerms(termsRepo)
+	termsHandler := handlers.NewTermsHandler(termsRepo)
+	draftHandler := handlers.NewPOIDraftHandler(poiRepo)
+	validationHandler := handlers.NewPOIValidationHandler(validation.NewValidator(poiRepo, config.GetValidationSettings()))
+	roleHandler := handlers.NewRoleHandler(roleRepo, authUsers)
+
+	// Initialize object storage (optional - continues without if not configured)
 	var uploadHandler *handlers.UploadHandler
-	r2Client, err := storage.NewR2Client()
+	var imagingService *imaging.Service
+	var reprocessHandler *handlers.ReprocessHandler
+	var ladderHandler *handlers.RenditionLadderHandler
+	stop := func(context.Context) error { return nil }
+	stores, err := storage.NewRegistry(config.GetStorageSettings())
 	if err != nil {
-		log.Printf("Warning: R2 storage not configured: %v", err)
+		log.Printf("Warning: object storage not configured: %v", err)
 	} else {
+		features[middleware.FeatureUploads] = true
+		images := stores.For(storage.CategoryImages)
 		imagingRepo := repositories.NewImagingRepository(db)
-		imagingService := imaging.NewService(r2Client, imagingRepo, 4)
-		uploadHandler = handlers.NewUploadHandler(r2Client, imagingService)
+
+		var imagingOpts []imaging.ServiceOption
+		moderator, policy, err := imaging.NewModeratorFromEnv()
+		if err != nil {
+			log.Printf("Warning: image moderation not configured: %v", err)
+		} else if moderator != nil {
+			imagingOpts = append(imagingOpts, imaging.WithModerator(moderator, policy))
+		}
+
+		imagingSettings := config.GetImagingSettings()
+		imagingOpts = append(imagingOpts, imaging.WithLookupCache(imagingSettings.LookupTTL, imagingSettings.LookupNegativeTTL))
+
+		// Stored ladder overrides; the compiled-in ladders are used until they load
+		ladders := imaging.NewLadders(imagingRepo)
+		ladderCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := ladders.Load(ladderCtx); err != nil {
+			log.Printf("Warning: rendition ladders not loaded: %v", err)
+		}
+		cancel()
+		imagingOpts = append(imagingOpts, imaging.WithLadders(ladders, imagingSettings.LadderRefresh))
+		ladderHandler = handlers.NewRenditionLadderHandler(imagingRepo, ladders)
+
+		workers := min(max(imagingSettings.Workers, 1), imaging.MaxWorkers)
+		imagingService = imaging.NewService(images, imagingRepo, workers, imagingOpts...)
+		uploadHandler = handlers.NewUploadHandler(images, imagingService)
+		uploadHandler.UseOriginalURLTTL(imagingSettings.OriginalURLTTL)
+		uploadHandler.UseMultipartUploads(repositories.NewMultipartUploadRepository(db))
+		uploadRepo := repositories.NewUploadRepository(db)
+		uploadHandler.UseUploadRecords(uploadRepo)
+		// Presigned uploads never finalized are deleted and counted as abandoned
+		uploadSweeper := uploadexpiry.NewWorker(uploadRepo, images, 10*time.Minute)
+		uploadSweeper.Start()
+		photoHandler.UseImaging(imagingService)
+		accountHandler.UseImaging(imagingService)
+		verificationHandler.UseDocumentStorage(stores.For(storage.CategoryDocuments))
+		reprocessor := imaging.NewReprocessor(imagingRepo, imagingService)
+		reprocessHandler = handlers.NewReprocessHandler(imagingRepo, reprocessor)
+		stop = func(ctx context.Context) error {
+			reprocessor.Stop()
+			uploadSweeper.Stop()
+			return imagingService.Stop(ctx)
+		}
 	}
 
 	// Initialize Clerk
 	auth.InitClerk()
+	requireAuth := handlers.AuthMiddleware(authUsers, roleRepo)
+	optionalAuth := handlers.OptionalAuthMiddleware(authUsers, roleRepo)
+	requireUploads := middleware.RequireFeature(features, middleware.FeatureUploads)
+	requireNotifications := middleware.RequireFeature(features, middleware.FeatureNotifications)
 
 	// Setup router
 	router := setupBaseRouter()
+	router.Use(middleware.ReadFromPrimary())
+
+	// Optional bot protection on high-abuse endpoints (POI submission, comments)
+	captchaSettings := config.GetCaptchaSettings()
+	verifier, err := captcha.NewFromConfig(captchaSettings)
+	if err != nil {
+		log.Printf("Warning: bot protection not configured: %v", err)
+	} else if verifier != nil {
+		router.Use(middleware.Captcha(verifier, captchaSettings.Routes))
+	}
 
 	// Health check endpoint
-	router.GET("/health", healthCheck(db))
+	router.GET("/health", healthCheck(db, features))
+	router.GET("/health/imaging", imagingHealth(imagingService))
+
+	// Prometheus scrape endpoint
+	router.GET("/metrics", metricsAuth(), gin.WrapH(observability.MetricsHandler()))
 
 	// Auth routes
-	router.GET("/api/me", handlers.AuthMiddleware(userRepo), authHandler.GetMe)
+	router.GET("/api/me", requireAuth, authHandler.GetMe)
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
@@ -73,15 +316,26 @@ func Setup(db *database.DB) *gin.Engine {
 		// POI routes
 		pois := v1.Group("/pois")
 		{
-			pois.GET("", poiHandler.SearchPOIs)
+			pois.GET("", optionalAuth, poiHandler.SearchPOIs) // Moderators may search non-approved statuses
 			pois.GET("/nearby", poiHandler.GetNearbyPOIs)
+			pois.GET("/trending", trendingHandler.GetTrendingPOIs)
+			pois.GET("/recent", poiHandler.GetRecentPOIs)
+			pois.POST("/distances", poiHandler.GetDistances)
+			pois.GET("/tiles/:cell", poiHandler.GetTile)
 			pois.GET("/filter-options", poiHandler.GetFilterOptions)
-			pois.GET("/:id", poiHandler.GetPOI)
-			pois.GET("/:id/comments", commentHandler.GetCommentsByPOI) // Public read for comments
-
-			// Protected POI routes (require auth)
+			pois.GET("/semantic-search", semanticSearchHandler.Search)
+			pois.GET("/:id", optionalAuth, poiHandler.GetPOI) // Signed-in viewers are counted once per hour
+			pois.GET("/:id/nearby-similar", poiHandler.GetNearbySimilar)
+			pois.GET("/:id/comments", optionalAuth, commentHandler.GetCommentsByPOI) // Public read for comments
+			pois.GET("/:id/specials", specialHandler.ListSpecials)
+			pois.GET("/:id/menu", menuHandler.GetMenu)
+			pois.GET("/:id/reviews", optionalAuth, reviewHandler.ListReviews)
+			pois.GET("/:id/questions", optionalAuth, questionHandler.ListQuestions)
+			pois.GET("/:id/translations", translationHandler.ListTranslations)
+
+			// Protected POI routes (require auth); changes also need the current terms accepted
 			poisAuth := pois.Group("")
-			poisAuth.Use(handlers.AuthMiddleware(userRepo))
+			poisAuth.Use(requireAuth, middleware.RequireTerms(termsRepo))
 			{
 				poisAuth.POST("", poiHandler.CreatePOI)
 				poisAuth.GET("/my", poiHandler.GetMyPOIs)
@@ -91,19 +345,61 @@ func Setup(db *database.DB) *gin.Engine {
 
 				// Comments
 				poisAuth.POST("/:id/comments", commentHandler.CreateComment)
+				poisAuth.PUT("/:id/reviews/mine", reviewHandler.UpsertReview)
+
+				// Questions and answers
+				poisAuth.POST("/:id/questions", questionHandler.AskQuestion)
+				poisAuth.POST("/:id/questions/:question_id/answers", questionHandler.AnswerQuestion)
+				poisAuth.POST("/:id/questions/:question_id/answers/:answer_id/accept", questionHandler.AcceptAnswer)
+
 				poisAuth.DELETE("/:id", poiHandler.DeletePOI)
 				poisAuth.GET("/my-drafts", poiHandler.GetMyDrafts)
+				poisAuth.PATCH("/:id/draft", draftHandler.SaveDraft)
+				poisAuth.GET("/:id/completeness", draftHandler.Completeness)
 				poisAuth.POST("/:id/submit", poiHandler.SubmitPOI)
-				poisAuth.POST("/:id/approve", poiHandler.ApprovePOI)
-				poisAuth.POST("/:id/reject", poiHandler.RejectPOI)
-				poisAuth.GET("/pending", poiHandler.GetPendingPOIs)
-				poisAuth.GET("/admin-list", poiHandler.GetAdminPOIs)
+				canApprove := middleware.RequirePermission(services.PermPOIApprove)
+				poisAuth.POST("/:id/approve", canApprove, poiHandler.ApprovePOI)
+				poisAuth.POST("/:id/reject", canApprove, poiHandler.RejectPOI)
+				poisAuth.POST("/:id/archive", canApprove, poiHandler.ArchivePOI)
+				poisAuth.GET("/pending", canApprove, poiHandler.GetPendingPOIs)
+				poisAuth.GET("/admin-list", canApprove, poiHandler.GetAdminPOIs)
+
+				// Specials & events (owner or poi:merge)
+				poisAuth.POST("/:id/specials", specialHandler.CreateSpecial)
+				poisAuth.PUT("/:id/specials/:special_id", specialHandler.UpdateSpecial)
+				poisAuth.DELETE("/:id/specials/:special_id", specialHandler.DeleteSpecial)
+
+				// Translations (owner or poi:merge)
+				poisAuth.PUT("/:id/translations/:locale", translationHandler.UpsertTranslation)
+				poisAuth.DELETE("/:id/translations/:locale", translationHandler.DeleteTranslation)
+
+				// Menu (owner or poi:merge)
+				poisAuth.PUT("/:id/menu", menuHandler.UpdateMenu)
+
+				// Gallery order and cover (owner or poi:merge)
+				poisAuth.PUT("/:id/photos/order", photoHandler.ReorderPhotos)
+				poisAuth.POST("/:id/photos/:photo_id/set-cover", requireUploads, photoHandler.SetCover)
+
+				// Community edit proposals
+				poisAuth.GET("/my-proposals", proposalHandler.GetMyProposals)
+				poisAuth.POST("/:id/proposals", proposalHandler.CreateProposal)
+				poisAuth.GET("/:id/proposals", proposalHandler.GetProposals)
+				poisAuth.POST("/:id/proposals/:proposal_id/accept", proposalHandler.AcceptProposal)
+				poisAuth.POST("/:id/proposals/:proposal_id/reject", proposalHandler.RejectProposal)
+				poisAuth.DELETE("/:id/proposals/:proposal_id", proposalHandler.WithdrawProposal)
+
+				// Business verification (owner; reviewed under /admin/verifications)
+				poisAuth.POST("/:id/verification/documents", requireUploads, verificationHandler.PresignDocument)
+				poisAuth.POST("/:id/verification", verificationHandler.RequestVerification)
+				poisAuth.GET("/:id/verification", verificationHandler.GetVerification)
+				poisAuth.DELETE("/:id/verification/:verification_id", verificationHandler.WithdrawVerification)
 
 				// Debug/Admin routes (if needed)
 				// r.GET("/api/v1/pois/:id/saved-users", savedPOIHandler.GetUsersWhoSavedPOI)
 
 				// Section-based editing
 				sectionHandler := handlers.NewPOISectionHandler(poiRepo)
+				sectionHandler.UseTextModerator(textPipeline)
 				poisAuth.GET("/:id/section/profile", sectionHandler.GetPOIProfile)
 				poisAuth.PUT("/:id/section/profile", sectionHandler.UpdatePOIProfile)
 				poisAuth.GET("/:id/section/location", sectionHandler.GetPOILocation)
@@ -120,52 +416,241 @@ func Setup(db *database.DB) *gin.Engine {
 				poisAuth.PUT("/:id/section/food-drink", sectionHandler.UpdatePOIFoodDrink)
 				poisAuth.GET("/:id/section/contact", sectionHandler.GetPOIContact)
 				poisAuth.PUT("/:id/section/contact", sectionHandler.UpdatePOIContact)
+				poisAuth.GET("/:id/section/accessibility", sectionHandler.GetPOIAccessibility)
+				poisAuth.PUT("/:id/section/accessibility", sectionHandler.UpdatePOIAccessibility)
 			}
 		}
 
-		// Upload routes (require auth)
-		if uploadHandler != nil {
-			uploads := v1.Group("/uploads")
-			uploads.Use(handlers.AuthMiddleware(userRepo))
-			{
-				uploads.POST("/presign", uploadHandler.GetPresignedURL)
-				uploads.POST("/finalize", uploadHandler.FinalizeUpload)
-				uploads.DELETE("", uploadHandler.DeleteUpload)
-			}
+		// Admin routes
+		admin := v1.Group("/admin")
+		admin.Use(requireAuth)
+		{
+			admin.POST("/pois/batch-status", middleware.RequirePermission(services.PermPOIApprove), poiHandler.BatchUpdateStatus)
+			admin.POST("/pois/:id/assign", middleware.RequirePermission(services.PermPOIApprove), poiHandler.AssignReviewer)
+			admin.DELETE("/pois/:id/assign", middleware.RequirePermission(services.PermPOIApprove), poiHandler.ReleaseReviewer)
+			admin.POST("/pois/:id/takedown", middleware.RequirePermission(services.PermPOITakedown), poiHandler.TakedownPOI)
+			admin.GET("/pois/:id/validation", middleware.RequirePermission(services.PermPOIApprove), validationHandler.GetValidation)
+			admin.GET("/queue/metrics", middleware.RequirePermission(services.PermPOIApprove), poiHandler.GetQueueMetrics)
+			admin.GET("/proposals", middleware.RequirePermission(services.PermPOIMerge), proposalHandler.GetPendingProposals)
+			admin.GET("/text-moderation", middleware.RequirePermission(services.PermPOIApprove), textModerationHandler.ListPending)
+			admin.POST("/text-moderation/:id/resolve", middleware.RequirePermission(services.PermPOIApprove), textModerationHandler.Resolve)
+
+			// Comment moderation; every action is written to the audit log
+			canModerateComments := middleware.RequirePermission(services.PermCommentModerate)
+			admin.GET("/comments/flagged", canModerateComments, commentModerationHandler.ListFlagged)
+			admin.POST("/comments/:id/hide", canModerateComments, commentModerationHandler.HideComment)
+			admin.DELETE("/comments/:id", canModerateComments, commentModerationHandler.DeleteComment)
+			admin.DELETE("/users/:id/comments", canModerateComments, commentModerationHandler.DeleteUserComments)
+
+			// Business verification review queue
+			canVerify := middleware.RequirePermission(services.PermPOIVerify)
+			admin.GET("/verifications", canVerify, verificationHandler.ListVerifications)
+			admin.GET("/verifications/:id", canVerify, verificationHandler.ReviewVerification)
+			admin.POST("/verifications/:id/approve", canVerify, verificationHandler.ApproveVerification)
+			admin.POST("/verifications/:id/reject", canVerify, verificationHandler.RejectVerification)
+
+			// Roles and role assignment
+			canManageUsers := middleware.RequirePermission(services.PermUserManage)
+			admin.GET("/roles", canManageUsers, roleHandler.ListRoles)
+			admin.PUT("/users/:id/role", canManageUsers, roleHandler.SetUserRole)
+			admin.GET("/users/spam-status", canManageUsers, spamHandler.ListFlaggedUsers)
+			admin.PUT("/users/:id/spam-status", canManageUsers, spamHandler.UpdateSpamStatus)
+
+			// Localized category / vocabulary labels
+			canManageTaxonomy := middleware.RequirePermission(services.PermTaxonomyManage)
+			admin.GET("/categories/:id/labels", canManageTaxonomy, labelHandler.ListLabels(repositories.LabelEntityCategory))
+			admin.PUT("/categories/:id/labels/:locale", canManageTaxonomy, labelHandler.UpsertLabel(repositories.LabelEntityCategory))
+			admin.DELETE("/categories/:id/labels/:locale", canManageTaxonomy, labelHandler.DeleteLabel(repositories.LabelEntityCategory))
+			admin.GET("/vocabularies/:id/labels", canManageTaxonomy, labelHandler.ListLabels(repositories.LabelEntityVocabulary))
+			admin.PUT("/vocabularies/:id/labels/:locale", canManageTaxonomy, labelHandler.UpsertLabel(repositories.LabelEntityVocabulary))
+			admin.DELETE("/vocabularies/:id/labels/:locale", canManageTaxonomy, labelHandler.DeleteLabel(repositories.LabelEntityVocabulary))
+
+			// Webhook subscriptions for POI lifecycle events
+			canManageWebhooks := middleware.RequirePermission(services.PermWebhookManage)
+			admin.GET("/webhooks", canManageWebhooks, webhookHandler.ListWebhooks)
+			admin.POST("/webhooks", canManageWebhooks, webhookHandler.CreateWebhook)
+			admin.PUT("/webhooks/:id", canManageWebhooks, webhookHandler.UpdateWebhook)
+			admin.DELETE("/webhooks/:id", canManageWebhooks, webhookHandler.DeleteWebhook)
+			admin.GET("/webhooks/:id/deliveries", canManageWebhooks, webhookHandler.ListDeliveries)
+
+			// Service areas (geofencing of new POIs)
+			canManageAreas := middleware.RequirePermission(services.PermAreaManage)
+			admin.GET("/service-areas", canManageAreas, serviceAreaHandler.ListServiceAreas)
+			admin.POST("/service-areas", canManageAreas, serviceAreaHandler.CreateServiceArea)
+			admin.PUT("/service-areas/:id", canManageAreas, serviceAreaHandler.UpdateServiceArea)
+			admin.DELETE("/service-areas/:id", canManageAreas, serviceAreaHandler.DeleteServiceArea)
+
+			// Product analytics
+			admin.GET("/analytics/search", middleware.RequirePermission(services.PermAnalyticsView), analyticsHandler.SearchSummary)
+
+			// In-app announcements
+			canAnnounce := middleware.RequirePermission(services.PermAnnouncementManage)
+			admin.GET("/announcements", canAnnounce, announcementHandler.ListAnnouncements)
+			admin.POST("/announcements", canAnnounce, announcementHandler.CreateAnnouncement)
+			admin.PUT("/announcements/:id", canAnnounce, announcementHandler.UpdateAnnouncement)
+			admin.DELETE("/announcements/:id", canAnnounce, announcementHandler.DeleteAnnouncement)
+
+			// Runtime diagnostics
+			admin.GET("/debug/db-pool", middleware.RequirePermission(services.PermSystemDebug), dbPoolStats(db))
+		}
 
-			// Asset routes (public to allow polling without token expiration issues)
-			assets := v1.Group("/assets")
-			// assets.Use(handlers.AuthMiddleware(userRepo))
-			{
-				assets.GET("/:id", uploadHandler.GetAssetStatus)
-				assets.POST("/:hash/reprocess", handlers.AuthMiddleware(userRepo), uploadHandler.ReprocessAsset)
-			}
+		// Upload routes (require auth). Without storage the handlers are nil
+		// and requireUploads answers 503 before they are reached.
+		uploads := v1.Group("/uploads")
+		uploads.Use(requireUploads, requireAuth)
+		{
+			uploads.POST("/presign", uploadHandler.GetPresignedURL)
+			uploads.POST("/finalize", uploadHandler.FinalizeUpload)
+			uploads.DELETE("", uploadHandler.DeleteUpload)
+			uploads.POST("/:id/heartbeat", uploadHandler.UploadHeartbeat)
+
+			// Multipart uploads for large originals; complete, then finalize as usual
+			uploads.POST("/multipart", uploadHandler.InitiateMultipartUpload)
+			uploads.GET("/multipart/:upload_id", uploadHandler.GetMultipartUpload)
+			uploads.POST("/multipart/:upload_id/parts/:part_number", uploadHandler.PresignUploadPart)
+			uploads.POST("/multipart/:upload_id/complete", uploadHandler.CompleteMultipartUpload)
+			uploads.DELETE("/multipart/:upload_id", uploadHandler.AbortMultipartUpload)
+		}
+
+		// Asset routes (public to allow polling without token expiration issues)
+		assets := v1.Group("/assets")
+		assets.Use(requireUploads)
+		{
+			assets.GET("/:id", uploadHandler.GetAssetStatus)
+			assets.POST("/:hash/reprocess", requireAuth, uploadHandler.ReprocessAsset)
+		}
+
+		imagingAdmin := v1.Group("/admin/imaging")
+		imagingAdmin.Use(requireUploads, requireAuth, middleware.RequirePermission(services.PermImagingAdmin))
+		{
+			imagingAdmin.PUT("/workers", uploadHandler.ScaleWorkers)
+			imagingAdmin.GET("/reprocess", reprocessHandler.GetReprocess)
+			imagingAdmin.POST("/reprocess", reprocessHandler.StartReprocess)
+			imagingAdmin.POST("/reprocess/cancel", reprocessHandler.CancelReprocess)
+			imagingAdmin.GET("/ladders", ladderHandler.ListLadders)
+			imagingAdmin.PUT("/ladders/:category", ladderHandler.UpdateLadder)
+			imagingAdmin.DELETE("/ladders/:category", ladderHandler.ResetLadder)
+			imagingAdmin.GET("/rejections", uploadHandler.ListUploadRejections)
 		}
 
 		// Photo routes
 		photos := v1.Group("/photos")
-		photos.Use(handlers.AuthMiddleware(userRepo))
+		photos.Use(requireAuth)
 		{
 			photos.POST("/:photo_id/vote", photoHandler.VotePhoto)
 		}
 
+		// Review routes
+		v1.POST("/reviews/:review_id/vote", requireAuth, reviewHandler.VoteReview)
+
 		// Category routes
 		v1.GET("/categories", categoryHandler.GetCategories)
+		v1.GET("/service-areas", serviceAreaHandler.ListActiveServiceAreas)
+		v1.GET("/areas/:slug/summary", serviceAreaHandler.GetAreaSummary)
 
 		// Saved POI list route
-		v1.GET("/me/saved-pois", handlers.AuthMiddleware(userRepo), savedPOIHandler.GetMySavedPOIs)
+		v1.GET("/me/saved-pois", requireAuth, savedPOIHandler.GetMySavedPOIs)
+
+		// Own account: data export, deletion, notifications and avatar
+		me := v1.Group("/me")
+		me.Use(requireAuth)
+		{
+			me.GET("/export", accountHandler.ExportData)
+			me.DELETE("", accountHandler.DeleteAccount)
+			me.GET("/link-account", accountHandler.ListLinkCandidates)
+			me.POST("/link-account", accountHandler.LinkAccount)
+			me.POST("/accept-tos", termsHandler.AcceptTerms)
+			me.GET("/notifications", accountHandler.ListNotifications)
+			me.POST("/notifications/:id/read", accountHandler.MarkNotificationRead)
+			me.POST("/avatar", requireUploads, accountHandler.SetAvatar)
+			me.GET("/email-preferences", requireNotifications, emailHandler.GetPreferences)
+			me.PUT("/email-preferences", requireNotifications, emailHandler.UpdatePreferences)
+			me.GET("/profile-privacy", profileHandler.GetPrivacy)
+			me.PUT("/profile-privacy", profileHandler.UpdatePrivacy)
+			me.PUT("/username", profileHandler.SetUsername)
+			me.GET("/blocks", blockHandler.ListBlocks)
+			me.DELETE("/blocks/:id", blockHandler.UnblockUser)
+			me.GET("/saved-searches", savedSearchHandler.ListSavedSearches)
+			me.POST("/saved-searches", savedSearchHandler.CreateSavedSearch)
+			me.PUT("/saved-searches/:id", savedSearchHandler.UpdateSavedSearch)
+			me.DELETE("/saved-searches/:id", savedSearchHandler.DeleteSavedSearch)
+			me.POST("/session/merge", sessionHandler.MergeSession)
+		}
+
+		// Announcement banners; signed-in users can dismiss them
+		v1.GET("/announcements/active", optionalAuth, announcementHandler.ListActiveAnnouncements)
+		v1.POST("/announcements/:id/dismiss", requireAuth, announcementHandler.DismissAnnouncement)
+
+		// Public user profiles; the owner also sees hidden sections and privacy settings
+		v1.GET("/users/:id/public-profile", optionalAuth, profileHandler.GetPublicProfile)
+		v1.POST("/users/:id/block", requireAuth, blockHandler.BlockUser)
+
+		// Gridded POI coverage and quality for the internal dashboard
+		v1.GET("/analytics/heatmap", requireAuth, middleware.RequirePermission(services.PermAnalyticsView), analyticsHandler.Heatmap)
+
+		// Current terms of service; accepted under /me/accept-tos
+		v1.GET("/tos", termsHandler.GetCurrent)
+
+		// Anonymous sessions: recent history and a saved list merged on login
+		v1.POST("/sessions", sessionHandler.CreateSession)
+		v1.GET("/session", sessionHandler.GetSession)
+		v1.POST("/session/saved-pois/:id", sessionHandler.SaveSessionPOI)
+		v1.DELETE("/session/saved-pois/:id", sessionHandler.UnsaveSessionPOI)
+
+		// Delta feed for the mobile app's offline POI cache
+		v1.GET("/sync/pois", syncHandler.SyncPOIs)
+
+		// Unsubscribe links from emails (signed token, no sign-in)
+		v1.GET("/email/unsubscribe", emailHandler.Unsubscribe)
+		v1.POST("/email/unsubscribe", emailHandler.Unsubscribe)
 
 		// Vocabulary routes
 		v1.GET("/vocabularies", vocabHandler.GetVocabularies)
+		v1.GET("/amenities", vocabHandler.GetAmenities)
+
+		// Itineraries with share links and collaborators
+		v1.GET("/shared/itineraries/:token", itineraryHandler.GetSharedItinerary)
+		itineraries := v1.Group("/itineraries")
+		itineraries.Use(requireAuth)
+		{
+			itineraries.GET("", itineraryHandler.ListItineraries)
+			itineraries.POST("", itineraryHandler.CreateItinerary)
+			itineraries.GET("/invitations", itineraryHandler.ListInvitations)
+			itineraries.GET("/:id", itineraryHandler.GetItinerary)
+			itineraries.PUT("/:id", itineraryHandler.UpdateItinerary)
+			itineraries.DELETE("/:id", itineraryHandler.DeleteItinerary)
+			itineraries.POST("/:id/share", itineraryHandler.CreateShareLink)
+			itineraries.DELETE("/:id/share", itineraryHandler.RevokeShareLink)
+			itineraries.POST("/:id/items", itineraryHandler.AddItem)
+			itineraries.PUT("/:id/items/:item_id", itineraryHandler.UpdateItem)
+			itineraries.DELETE("/:id/items/:item_id", itineraryHandler.DeleteItem)
+			itineraries.GET("/:id/collaborators", itineraryHandler.ListCollaborators)
+			itineraries.POST("/:id/collaborators", itineraryHandler.InviteCollaborator)
+			itineraries.PUT("/:id/collaborators/:user_id", itineraryHandler.UpdateCollaborator)
+			itineraries.DELETE("/:id/collaborators/:user_id", itineraryHandler.RemoveCollaborator)
+			itineraries.POST("/:id/invitation/accept", itineraryHandler.AcceptInvitation)
+		}
 	}
 
-	// Public image serving route
-	router.GET("/img/:hash/:rendition", uploadHandler.ServeImage)
+	// Public image serving route; originals are limited to their owner and imaging admins
+	router.GET("/img/:hash/:rendition", requireUploads, optionalAuth, uploadHandler.ServeImage)
+
+	// Optional GraphQL endpoint for clients that want to shape their own responses
+	if gqlSettings := config.GetGraphQLSettings(); gqlSettings.Enabled {
+		resolver := gql.NewResolver(poiRepo, photoRepo, reviewRepo, commentRepo, userRepo)
+		gqlHandler, err := gql.NewHandler(resolver, gqlSettings.Introspection)
+		if err != nil {
+			log.Printf("Warning: GraphQL endpoint disabled: %v", err)
+		} else {
+			router.GET("/graphql", optionalAuth, gqlHandler.Serve)
+			router.POST("/graphql", optionalAuth, gqlHandler.Serve)
+		}
+	}
 
 	// API documentation endpoint
-	router.GET("/api", apiDocumentation())
+	router.GET("/api", apiDocumentation(features))
 
-	return router
+	return router, stop
 }
 
 func setupBaseRouter() *gin.Engine {
@@ -174,8 +659,34 @@ func setupBaseRouter() *gin.Engine {
 	// Middleware
 	router.Use(otelgin.Middleware("maukemana-api"))
 	router.Use(middleware.Observability())
+	router.Use(middleware.HTTPMetrics())
 	router.Use(middleware.SecurityHeaders()) // Add security headers
 	router.Use(middleware.RateLimit())
+	router.Use(middleware.BodyLimit(config.GetMaxBodyBytes(), map[string]int64{
+		"POST /api/v1/uploads/presign":   4 << 10,
+		"POST /api/v1/uploads/finalize":  16 << 10,
+		"POST /api/v1/uploads/multipart": 4 << 10,
+		"PUT /api/v1/pois/:id/menu":      4 << 20, // Full menus with many sections and items
+	}))
+	// Gzip request bodies are accepted where clients send large payloads
+	router.Use(middleware.DecompressBody(map[string]int64{
+		"POST /api/v1/admin/pois/batch-status": config.GetMaxBodyBytes(),
+		"PUT /api/v1/pois/:id/menu":            4 << 20,
+	}))
+	timeouts := config.GetTimeoutSettings()
+	router.Use(middleware.Timeout(timeouts.Default, map[string]time.Duration{
+		"GET /img/:hash/:rendition":            0, // Streams image bytes to slow clients
+		"GET /api/v1/me/export":                timeouts.Long,
+		"GET /api/v1/sync/pois":                timeouts.Long, // Full syncs read every approved POI
+		"GET /api/v1/admin/analytics/search":   timeouts.Long,
+		"GET /api/v1/analytics/heatmap":        timeouts.Long,
+		"POST /api/v1/admin/pois/batch-status": timeouts.Long,
+		"POST /api/v1/uploads/finalize":        timeouts.Long,
+	}))
+	if compression := config.GetCompressionSettings(); compression.Enabled {
+		router.Use(middleware.Compress(compression.Level, compression.MinSize))
+	}
+	router.Use(middleware.Locale(config.GetSupportedLocales()))
 
 	// Trusted Proxies Configuration
 	// In production, you should set this to the specific IP ranges of your load balancers or reverse proxies.
@@ -191,10 +702,12 @@ func setupBaseRouter() *gin.Engine {
 		"Content-Type",
 		"Authorization",
 		"Accept",
+		"Accept-Language",
 		"User-Agent",
 		"Cache-Control",
 		"Pragma",
 		"X-Session-ID",
+		middleware.CaptchaHeader,
 	}
 	corsConfig.AllowMethods = []string{
 		"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS",
@@ -205,13 +718,15 @@ func setupBaseRouter() *gin.Engine {
 	return router
 }
 
-func healthCheck(db *database.DB) gin.HandlerFunc {
+// healthCheck reports database health and which optional subsystems are enabled
+func healthCheck(db *database.DB, features middleware.Features) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if err := db.Health(c.Request.Context()); err != nil {
 			c.JSON(http.StatusServiceUnavailable, gin.H{
 				"status":    "unhealthy",
 				"error":     err.Error(),
 				"database":  "postgresql",
+				"features":  features,
 				"timestamp": time.Now().Unix(),
 			})
 			return
@@ -221,19 +736,77 @@ func healthCheck(db *database.DB) gin.HandlerFunc {
 			"status":    "healthy",
 			"version":   "2.0",
 			"database":  "postgresql",
+			"features":  features,
+			"timestamp": time.Now().Unix(),
+		})
+	}
+}
+
+// dbPoolStats reports connection pool usage (open, in use, idle, waits) and
+// its configured limits. The same counters are exported as metrics.
+func dbPoolStats(db *database.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		utils.SendSuccess(c, "Database pool stats retrieved", db.PoolStats())
+	}
+}
+
+// imagingHealth reports the image processing pipeline. It is degraded when
+// queued jobs wait longer than stuckImagingJobAge, which with idle workers
+// points at a stuck queue.
+func imagingHealth(svc *imaging.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if svc == nil {
+			c.JSON(http.StatusOK, gin.H{"status": "disabled", "timestamp": time.Now().Unix()})
+			return
+		}
+
+		st := svc.Status()
+		status, code := "healthy", http.StatusOK
+		switch {
+		case st.Stopping:
+			status, code = "stopping", http.StatusServiceUnavailable
+		case st.OldestPendingSeconds > stuckImagingJobAge.Seconds():
+			status = "degraded"
+		}
+		c.JSON(code, gin.H{
+			"status":    status,
+			"imaging":   st,
 			"timestamp": time.Now().Unix(),
 		})
 	}
 }
 
-func apiDocumentation() gin.HandlerFunc {
+// stuckImagingJobAge is how long a queued image job may wait before the
+// pipeline is reported degraded
+const stuckImagingJobAge = 10 * time.Minute
+
+// metricsAuth protects /metrics with a bearer token when METRICS_TOKEN is set
+func metricsAuth() gin.HandlerFunc {
+	token := os.Getenv("METRICS_TOKEN")
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+		provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Next()
+	}
+}
+
+func apiDocumentation(features middleware.Features) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"name":        "Maukemana API",
 			"version":     "2.0",
 			"description": "Travel discovery and planning API (PostgreSQL + PostGIS)",
+			"features":    features,
 			"endpoints": map[string]interface{}{
-				"health": "GET /health",
+				"health":         "GET /health",
+				"health_imaging": "GET /health/imaging",
 				"pois": map[string]string{
 					"list":           "GET /api/v1/pois",
 					"get":            "GET /api/v1/pois/:id",
@@ -241,10 +814,13 @@ func apiDocumentation() gin.HandlerFunc {
 					"update":         "PUT /api/v1/pois/:id",
 					"delete":         "DELETE /api/v1/pois/:id",
 					"nearby":         "GET /api/v1/pois/nearby?lat=...&lng=...&radius=...",
+					"trending":       "GET /api/v1/pois/trending?area=...",
+					"recent":         "GET /api/v1/pois/recent?days=7&cursor=...",
 					"filter_options": "GET /api/v1/pois/filter-options",
 				},
 				"categories":   "GET /api/v1/categories",
 				"vocabularies": "GET /api/v1/vocabularies?type=...",
+				"amenities":    "GET /api/v1/amenities",
 			},
 		})
 	}
@@ -0,0 +1,119 @@
+package routing
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Coordinates are rounded before caching so requests from nearly the same
+// spot share entries: 4 decimals (~11m) for origins, which move with the
+// user, and 5 (~1m) for destinations, which are fixed POI locations.
+const (
+	originPrecision      = 1e4
+	destinationPrecision = 1e5
+)
+
+type pairKey struct {
+	mode             Mode
+	fromLat, fromLng int64
+	toLat, toLng     int64
+}
+
+type cacheEntry struct {
+	leg       Leg
+	expiresAt time.Time
+}
+
+// CachedMatrix caches legs per origin/destination pair and sends only the
+// misses to the provider, in batches of its maximum size
+type CachedMatrix struct {
+	provider Matrix
+	ttl      time.Duration
+	maxSize  int
+
+	mu      sync.Mutex
+	entries map[pairKey]cacheEntry
+}
+
+// NewCachedMatrix wraps a provider with a pair cache holding up to maxSize entries
+func NewCachedMatrix(provider Matrix, ttl time.Duration, maxSize int) *CachedMatrix {
+	return &CachedMatrix{provider: provider, ttl: ttl, maxSize: maxSize, entries: make(map[pairKey]cacheEntry)}
+}
+
+// MaxDestinations reports the provider batch size
+func (c *CachedMatrix) MaxDestinations() int {
+	return c.provider.MaxDestinations()
+}
+
+// Legs returns one leg per destination, in order
+func (c *CachedMatrix) Legs(ctx context.Context, mode Mode, origin Point, dests []Point) ([]Leg, error) {
+	legs := make([]Leg, len(dests))
+	keys := make([]pairKey, len(dests))
+	var missing []int
+
+	now := time.Now()
+	c.mu.Lock()
+	for i, d := range dests {
+		keys[i] = newPairKey(mode, origin, d)
+		if e, ok := c.entries[keys[i]]; ok && now.Before(e.expiresAt) {
+			legs[i] = e.leg
+		} else {
+			missing = append(missing, i)
+		}
+	}
+	c.mu.Unlock()
+
+	batch := c.provider.MaxDestinations()
+	for start := 0; start < len(missing); start += batch {
+		idx := missing[start:min(start+batch, len(missing))]
+		points := make([]Point, len(idx))
+		for j, i := range idx {
+			points[j] = dests[i]
+		}
+
+		fetched, err := c.provider.Legs(ctx, mode, origin, points)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		c.makeRoom(len(idx), now)
+		for j, i := range idx {
+			legs[i] = fetched[j]
+			c.entries[keys[i]] = cacheEntry{leg: fetched[j], expiresAt: now.Add(c.ttl)}
+		}
+		c.mu.Unlock()
+	}
+	return legs, nil
+}
+
+// makeRoom evicts expired entries, then arbitrary ones, until n more fit.
+// Callers hold mu.
+func (c *CachedMatrix) makeRoom(n int, now time.Time) {
+	if len(c.entries)+n <= c.maxSize {
+		return
+	}
+	for k, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	for k := range c.entries {
+		if len(c.entries)+n <= c.maxSize {
+			return
+		}
+		delete(c.entries, k)
+	}
+}
+
+func newPairKey(mode Mode, from, to Point) pairKey {
+	return pairKey{
+		mode:    mode,
+		fromLat: int64(math.Round(from.Lat * originPrecision)),
+		fromLng: int64(math.Round(from.Lng * originPrecision)),
+		toLat:   int64(math.Round(to.Lat * destinationPrecision)),
+		toLng:   int64(math.Round(to.Lng * destinationPrecision)),
+	}
+}
@@ -0,0 +1,109 @@
+// Package routing estimates travel times from one origin to many destinations
+// through a pluggable distance-matrix provider (OSRM, Mapbox or Google).
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"maukemana-backend/internal/config"
+)
+
+// requestTimeout bounds every call to the routing provider
+const requestTimeout = 5 * time.Second
+
+// Mode is a travel mode
+type Mode string
+
+const (
+	ModeWalking Mode = "walking"
+	ModeCycling Mode = "cycling"
+	ModeDriving Mode = "driving"
+)
+
+// ParseMode validates a travel_mode query value
+func ParseMode(s string) (Mode, bool) {
+	switch m := Mode(s); m {
+	case ModeWalking, ModeCycling, ModeDriving:
+		return m, true
+	}
+	return "", false
+}
+
+// Point is a WGS84 coordinate
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// Leg is the estimated trip from the origin to one destination. OK is false
+// when the provider found no route.
+type Leg struct {
+	DurationSeconds float64
+	DistanceMeters  float64
+	OK              bool
+}
+
+// Matrix computes travel legs from one origin to many destinations
+type Matrix interface {
+	// MaxDestinations is the largest batch a single request accepts
+	MaxDestinations() int
+	// Legs returns one leg per destination, in order
+	Legs(ctx context.Context, mode Mode, origin Point, dests []Point) ([]Leg, error)
+}
+
+// NewFromConfig builds the configured provider wrapped in a pair cache. It
+// returns nil when no provider is set.
+func NewFromConfig(cfg config.RoutingSettings) (*CachedMatrix, error) {
+	httpClient := &http.Client{Timeout: requestTimeout}
+
+	var m Matrix
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case "osrm":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("ROUTING_URL is required for provider %q", cfg.Provider)
+		}
+		m = &osrmMatrix{http: httpClient, baseURL: cfg.URL}
+	case "mapbox":
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("ROUTING_API_KEY is required for provider %q", cfg.Provider)
+		}
+		m = &mapboxMatrix{http: httpClient, token: cfg.APIKey}
+	case "google":
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("ROUTING_API_KEY is required for provider %q", cfg.Provider)
+		}
+		m = &googleMatrix{http: httpClient, apiKey: cfg.APIKey}
+	default:
+		return nil, fmt.Errorf("unknown routing provider %q", cfg.Provider)
+	}
+	return NewCachedMatrix(m, cfg.CacheTTL, cfg.CacheSize), nil
+}
+
+// getJSON performs a GET and decodes a JSON response
+func getJSON(ctx context.Context, client *http.Client, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("build routing request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("routing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return fmt.Errorf("routing provider returned %d: %s", resp.StatusCode, msg)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode routing response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// osrmMatrix uses the OSRM table service of a self-hosted server. OSRM
+// servers are built for a single profile, so the mode only selects the URL path.
+type osrmMatrix struct {
+	http    *http.Client
+	baseURL string
+}
+
+var osrmProfiles = map[Mode]string{ModeWalking: "foot", ModeCycling: "bike", ModeDriving: "car"}
+
+func (o *osrmMatrix) MaxDestinations() int { return 100 }
+
+func (o *osrmMatrix) Legs(ctx context.Context, mode Mode, origin Point, dests []Point) ([]Leg, error) {
+	var resp struct {
+		Code      string       `json:"code"`
+		Durations [][]*float64 `json:"durations"`
+		Distances [][]*float64 `json:"distances"`
+	}
+	u := fmt.Sprintf("%s/table/v1/%s/%s?sources=0&annotations=duration,distance",
+		o.baseURL, osrmProfiles[mode], lngLatList(origin, dests))
+	if err := getJSON(ctx, o.http, u, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Code != "Ok" || len(resp.Durations) != 1 {
+		return nil, fmt.Errorf("osrm table returned code %q", resp.Code)
+	}
+	return tableLegs(resp.Durations[0], resp.Distances, len(dests)), nil
+}
+
+// mapboxMatrix uses the Mapbox Matrix API
+type mapboxMatrix struct {
+	http  *http.Client
+	token string
+}
+
+var mapboxProfiles = map[Mode]string{ModeWalking: "walking", ModeCycling: "cycling", ModeDriving: "driving"}
+
+// The Matrix API accepts 25 coordinates including the origin
+func (m *mapboxMatrix) MaxDestinations() int { return 24 }
+
+func (m *mapboxMatrix) Legs(ctx context.Context, mode Mode, origin Point, dests []Point) ([]Leg, error) {
+	var resp struct {
+		Code      string       `json:"code"`
+		Durations [][]*float64 `json:"durations"`
+		Distances [][]*float64 `json:"distances"`
+	}
+	u := fmt.Sprintf("https://api.mapbox.com/directions-matrix/v1/mapbox/%s/%s?sources=0&annotations=duration,distance&access_token=%s",
+		mapboxProfiles[mode], lngLatList(origin, dests), url.QueryEscape(m.token))
+	if err := getJSON(ctx, m.http, u, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Code != "Ok" || len(resp.Durations) != 1 {
+		return nil, fmt.Errorf("mapbox matrix returned code %q", resp.Code)
+	}
+	return tableLegs(resp.Durations[0], resp.Distances, len(dests)), nil
+}
+
+// lngLatList formats the origin followed by the destinations as "lng,lat;lng,lat"
+func lngLatList(origin Point, dests []Point) string {
+	coords := make([]string, 0, len(dests)+1)
+	for _, p := range append([]Point{origin}, dests...) {
+		coords = append(coords, strconv.FormatFloat(p.Lng, 'f', 6, 64)+","+strconv.FormatFloat(p.Lat, 'f', 6, 64))
+	}
+	return strings.Join(coords, ";")
+}
+
+// tableLegs converts the origin row of an OSRM-style table, whose first
+// column is the origin itself
+func tableLegs(durations []*float64, distances [][]*float64, n int) []Leg {
+	legs := make([]Leg, n)
+	for i := range legs {
+		col := i + 1
+		if col >= len(durations) || durations[col] == nil {
+			continue
+		}
+		legs[i] = Leg{DurationSeconds: *durations[col], OK: true}
+		if len(distances) == 1 && col < len(distances[0]) && distances[0][col] != nil {
+			legs[i].DistanceMeters = *distances[0][col]
+		}
+	}
+	return legs
+}
+
+// googleMatrix uses the Google Distance Matrix API
+type googleMatrix struct {
+	http   *http.Client
+	apiKey string
+}
+
+var googleModes = map[Mode]string{ModeWalking: "walking", ModeCycling: "bicycling", ModeDriving: "driving"}
+
+func (g *googleMatrix) MaxDestinations() int { return 25 }
+
+func (g *googleMatrix) Legs(ctx context.Context, mode Mode, origin Point, dests []Point) ([]Leg, error) {
+	destinations := make([]string, len(dests))
+	for i, d := range dests {
+		destinations[i] = latLng(d)
+	}
+	q := url.Values{}
+	q.Set("origins", latLng(origin))
+	q.Set("destinations", strings.Join(destinations, "|"))
+	q.Set("mode", googleModes[mode])
+	q.Set("key", g.apiKey)
+
+	var resp struct {
+		Status string `json:"status"`
+		Rows   []struct {
+			Elements []struct {
+				Status   string `json:"status"`
+				Duration struct {
+					Value float64 `json:"value"`
+				} `json:"duration"`
+				Distance struct {
+					Value float64 `json:"value"`
+				} `json:"distance"`
+			} `json:"elements"`
+		} `json:"rows"`
+	}
+	if err := getJSON(ctx, g.http, "https://maps.googleapis.com/maps/api/distancematrix/json?"+q.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status != "OK" || len(resp.Rows) != 1 {
+		return nil, fmt.Errorf("google distance matrix returned status %q", resp.Status)
+	}
+
+	legs := make([]Leg, len(dests))
+	for i, el := range resp.Rows[0].Elements {
+		if i < len(legs) && el.Status == "OK" {
+			legs[i] = Leg{DurationSeconds: el.Duration.Value, DistanceMeters: el.Distance.Value, OK: true}
+		}
+	}
+	return legs, nil
+}
+
+func latLng(p Point) string {
+	return strconv.FormatFloat(p.Lat, 'f', 6, 64) + "," + strconv.FormatFloat(p.Lng, 'f', 6, 64)
+}
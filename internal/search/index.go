@@ -0,0 +1,137 @@
+// Package search keeps an external full-text index (Meilisearch or OpenSearch)
+// of approved POIs in sync and queries it for typo-tolerant text search.
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"maukemana-backend/internal/config"
+	"maukemana-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// requestTimeout bounds every call to the search backend
+const requestTimeout = 5 * time.Second
+
+// searchableFields are matched by text queries, most important first
+var searchableFields = []string{
+	"name", "translated_names", "brand", "cuisine", "category_names", "vibes", "address", "description",
+}
+
+// Index is a full-text index of approved POIs
+type Index interface {
+	// Configure creates the index and applies its settings; it is idempotent
+	Configure(ctx context.Context) error
+	Upsert(ctx context.Context, docs []repositories.POISearchDocument) error
+	Delete(ctx context.Context, ids []uuid.UUID) error
+	// Clear removes every document
+	Clear(ctx context.Context) error
+	// Search returns the IDs of matching POIs, best match first
+	Search(ctx context.Context, query string, limit int) ([]uuid.UUID, error)
+}
+
+// NewFromConfig builds the configured index. It returns nil when no provider is set.
+func NewFromConfig(cfg config.SearchSettings) (Index, error) {
+	if cfg.Provider == "" {
+		return nil, nil
+	}
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("SEARCH_URL is required for provider %q", cfg.Provider)
+	}
+
+	c := &client{http: &http.Client{Timeout: requestTimeout}, baseURL: cfg.URL}
+	switch cfg.Provider {
+	case "meilisearch":
+		c.bearer = cfg.APIKey
+		return &meilisearchIndex{client: c, uid: cfg.Index}, nil
+	case "opensearch":
+		c.username, c.password = cfg.Username, cfg.Password
+		return &openSearchIndex{client: c, name: cfg.Index}, nil
+	default:
+		return nil, fmt.Errorf("unknown search provider %q", cfg.Provider)
+	}
+}
+
+// client is a minimal JSON-over-HTTP client shared by the backends
+type client struct {
+	http               *http.Client
+	baseURL            string
+	bearer             string
+	username, password string
+}
+
+// statusError is returned for non-2xx responses
+type statusError struct {
+	Status int
+	Body   string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("search backend returned %d: %s", e.Status, e.Body)
+}
+
+// do sends body (JSON-encoded unless it is already []byte) and decodes the response into out
+func (c *client) do(ctx context.Context, method, path, contentType string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		raw, ok := body.([]byte)
+		if !ok {
+			var err error
+			if raw, err = json.Marshal(body); err != nil {
+				return fmt.Errorf("encode search request: %w", err)
+			}
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("build search request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearer)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("search request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return &statusError{Status: resp.StatusCode, Body: string(msg)}
+	}
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode search response: %w", err)
+	}
+	return nil
+}
+
+// parseIDs converts backend document IDs, skipping anything that is not a UUID
+func parseIDs(raw []string) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(raw))
+	for _, s := range raw {
+		if id, err := uuid.Parse(s); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
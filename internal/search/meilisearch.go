@@ -0,0 +1,77 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"maukemana-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// meilisearchIndex talks to the Meilisearch REST API. Writes are asynchronous
+// tasks on the Meilisearch side; the index catches up within moments.
+type meilisearchIndex struct {
+	client *client
+	uid    string
+}
+
+func (m *meilisearchIndex) path(suffix string) string {
+	return "/indexes/" + url.PathEscape(m.uid) + suffix
+}
+
+func (m *meilisearchIndex) Configure(ctx context.Context) error {
+	err := m.client.do(ctx, http.MethodPost, "/indexes", "application/json",
+		map[string]string{"uid": m.uid, "primaryKey": "id"}, nil)
+	var se *statusError
+	if err != nil && !(errors.As(err, &se) && se.Status == http.StatusConflict) {
+		return err
+	}
+
+	return m.client.do(ctx, http.MethodPatch, m.path("/settings"), "application/json", map[string]interface{}{
+		"searchableAttributes": searchableFields,
+		"sortableAttributes":   []string{"rating_avg", "updated_at"},
+	}, nil)
+}
+
+func (m *meilisearchIndex) Upsert(ctx context.Context, docs []repositories.POISearchDocument) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	return m.client.do(ctx, http.MethodPost, m.path("/documents?primaryKey=id"), "application/json", docs, nil)
+}
+
+func (m *meilisearchIndex) Delete(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return m.client.do(ctx, http.MethodPost, m.path("/documents/delete-batch"), "application/json", ids, nil)
+}
+
+func (m *meilisearchIndex) Clear(ctx context.Context) error {
+	return m.client.do(ctx, http.MethodDelete, m.path("/documents"), "", nil, nil)
+}
+
+func (m *meilisearchIndex) Search(ctx context.Context, query string, limit int) ([]uuid.UUID, error) {
+	var resp struct {
+		Hits []struct {
+			ID string `json:"id"`
+		} `json:"hits"`
+	}
+	err := m.client.do(ctx, http.MethodPost, m.path("/search"), "application/json", map[string]interface{}{
+		"q":                    query,
+		"limit":                limit,
+		"attributesToRetrieve": []string{"id"},
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]string, len(resp.Hits))
+	for i, h := range resp.Hits {
+		raw[i] = h.ID
+	}
+	return parseIDs(raw), nil
+}
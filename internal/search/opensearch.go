@@ -0,0 +1,141 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"maukemana-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// openSearchIndex talks to the OpenSearch (or Elasticsearch-compatible) REST API
+type openSearchIndex struct {
+	client *client
+	name   string
+}
+
+// openSearchBoosts weight the searchable fields in multi_match queries
+var openSearchBoosts = map[string]string{
+	"name":             "^4",
+	"translated_names": "^3",
+	"brand":            "^2",
+	"cuisine":          "^2",
+}
+
+func (o *openSearchIndex) path(suffix string) string {
+	return "/" + url.PathEscape(o.name) + suffix
+}
+
+func (o *openSearchIndex) Configure(ctx context.Context) error {
+	textField := map[string]string{"type": "text"}
+	err := o.client.do(ctx, http.MethodPut, o.path(""), "application/json", map[string]interface{}{
+		"mappings": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"name":             textField,
+				"translated_names": textField,
+				"brand":            textField,
+				"cuisine":          textField,
+				"category_names":   textField,
+				"vibes":            textField,
+				"address":          textField,
+				"description":      textField,
+				"rating_avg":       map[string]string{"type": "float"},
+				"updated_at":       map[string]string{"type": "date"},
+			},
+		},
+	}, nil)
+
+	// An existing index answers 400 resource_already_exists_exception
+	var se *statusError
+	if err != nil && errors.As(err, &se) && se.Status == http.StatusBadRequest && strings.Contains(se.Body, "resource_already_exists_exception") {
+		return nil
+	}
+	return err
+}
+
+func (o *openSearchIndex) Upsert(ctx context.Context, docs []repositories.POISearchDocument) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, doc := range docs {
+		enc.Encode(map[string]interface{}{"index": map[string]string{"_index": o.name, "_id": doc.ID.String()}})
+		enc.Encode(doc)
+	}
+	return o.bulk(ctx, body.Bytes())
+}
+
+func (o *openSearchIndex) Delete(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, id := range ids {
+		enc.Encode(map[string]interface{}{"delete": map[string]string{"_index": o.name, "_id": id.String()}})
+	}
+	return o.bulk(ctx, body.Bytes())
+}
+
+// bulk sends an NDJSON _bulk request and surfaces per-item failures
+func (o *openSearchIndex) bulk(ctx context.Context, ndjson []byte) error {
+	var resp struct {
+		Errors bool `json:"errors"`
+	}
+	if err := o.client.do(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", ndjson, &resp); err != nil {
+		return err
+	}
+	if resp.Errors {
+		return fmt.Errorf("opensearch bulk request had item errors")
+	}
+	return nil
+}
+
+func (o *openSearchIndex) Clear(ctx context.Context) error {
+	return o.client.do(ctx, http.MethodPost, o.path("/_delete_by_query"), "application/json", map[string]interface{}{
+		"query": map[string]interface{}{"match_all": map[string]interface{}{}},
+	}, nil)
+}
+
+func (o *openSearchIndex) Search(ctx context.Context, query string, limit int) ([]uuid.UUID, error) {
+	fields := make([]string, len(searchableFields))
+	for i, f := range searchableFields {
+		fields[i] = f + openSearchBoosts[f]
+	}
+
+	var resp struct {
+		Hits struct {
+			Hits []struct {
+				ID string `json:"_id"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	err := o.client.do(ctx, http.MethodPost, o.path("/_search"), "application/json", map[string]interface{}{
+		"size":    limit,
+		"_source": false,
+		"query": map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":     query,
+				"fields":    fields,
+				"fuzziness": "AUTO",
+			},
+		},
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]string, len(resp.Hits.Hits))
+	for i, h := range resp.Hits.Hits {
+		raw[i] = h.ID
+	}
+	return parseIDs(raw), nil
+}
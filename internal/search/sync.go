@@ -0,0 +1,87 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"maukemana-backend/internal/events"
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// reindexBatchSize is the page size used by full reindexes
+const reindexBatchSize = 500
+
+// DocumentSource loads search documents for approved POIs
+type DocumentSource interface {
+	GetSearchDocument(ctx context.Context, poiID uuid.UUID) (*repositories.POISearchDocument, error)
+	ListSearchDocuments(ctx context.Context, after uuid.UUID, limit int) ([]repositories.POISearchDocument, error)
+}
+
+// Syncer applies POI lifecycle events to the index incrementally
+type Syncer struct {
+	index  Index
+	source DocumentSource
+}
+
+// NewSyncer creates a syncer
+func NewSyncer(index Index, source DocumentSource) *Syncer {
+	return &Syncer{index: index, source: source}
+}
+
+// Register subscribes the syncer to the POI lifecycle events on bus
+func (s *Syncer) Register(bus *events.Bus) {
+	bus.Subscribe(models.EventPOIApproved, s.handle)
+	bus.Subscribe(models.EventPOIUpdated, s.handle)
+	bus.Subscribe(models.EventPOIDeleted, s.handle)
+}
+
+// handle re-reads the POI so the index reflects the committed state, even if
+// events arrive out of order
+func (s *Syncer) handle(ctx context.Context, event models.OutboxEvent) error {
+	doc, err := s.source.GetSearchDocument(ctx, event.AggregateID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return s.index.Delete(ctx, []uuid.UUID{event.AggregateID})
+	}
+	if err != nil {
+		return err
+	}
+	return s.index.Upsert(ctx, []repositories.POISearchDocument{*doc})
+}
+
+// Reindex pushes every approved POI to the index. With clear, the index is
+// emptied first so documents of POIs deleted while the syncer was not running
+// disappear; text search misses POIs until the run completes.
+func Reindex(ctx context.Context, index Index, source DocumentSource, clear bool) (int, error) {
+	if err := index.Configure(ctx); err != nil {
+		return 0, fmt.Errorf("configure index: %w", err)
+	}
+	if clear {
+		if err := index.Clear(ctx); err != nil {
+			return 0, fmt.Errorf("clear index: %w", err)
+		}
+	}
+
+	total := 0
+	after := uuid.Nil
+	for {
+		docs, err := source.ListSearchDocuments(ctx, after, reindexBatchSize)
+		if err != nil {
+			return total, err
+		}
+		if len(docs) == 0 {
+			return total, nil
+		}
+		if err := index.Upsert(ctx, docs); err != nil {
+			return total, fmt.Errorf("upsert batch: %w", err)
+		}
+		total += len(docs)
+		after = docs[len(docs)-1].ID
+		slog.Info("reindexed batch", "count", len(docs), "total", total)
+	}
+}
@@ -0,0 +1,159 @@
+package seed
+
+// neighborhood is a Jakarta area POIs are scattered around
+type neighborhood struct {
+	Name      string // Kelurahan or area name used in street addresses
+	District  string // Kecamatan
+	City      string // Kabupaten / administrative city
+	Postal    string
+	Latitude  float64 // Area center
+	Longitude float64
+}
+
+var neighborhoods = []neighborhood{
+	{"Kemang", "Mampang Prapatan", "Jakarta Selatan", "12730", -6.2607, 106.8136},
+	{"Senopati", "Kebayoran Baru", "Jakarta Selatan", "12190", -6.2326, 106.8090},
+	{"SCBD", "Kebayoran Baru", "Jakarta Selatan", "12190", -6.2250, 106.8090},
+	{"Blok M", "Kebayoran Baru", "Jakarta Selatan", "12160", -6.2443, 106.8006},
+	{"Cipete", "Cilandak", "Jakarta Selatan", "12410", -6.2731, 106.8003},
+	{"Gandaria", "Kebayoran Lama", "Jakarta Selatan", "12240", -6.2443, 106.7835},
+	{"Tebet", "Tebet", "Jakarta Selatan", "12810", -6.2262, 106.8537},
+	{"Kuningan", "Setiabudi", "Jakarta Selatan", "12940", -6.2297, 106.8296},
+	{"Menteng", "Menteng", "Jakarta Pusat", "10310", -6.1963, 106.8328},
+	{"Cikini", "Menteng", "Jakarta Pusat", "10330", -6.1899, 106.8397},
+	{"Sudirman", "Tanah Abang", "Jakarta Pusat", "10220", -6.2088, 106.8197},
+	{"Kota Tua", "Taman Sari", "Jakarta Barat", "11110", -6.1352, 106.8133},
+	{"Tanjung Duren", "Grogol Petamburan", "Jakarta Barat", "11470", -6.1767, 106.7868},
+	{"Kelapa Gading", "Kelapa Gading", "Jakarta Utara", "14240", -6.1588, 106.9056},
+	{"Pantai Indah Kapuk", "Penjaringan", "Jakarta Utara", "14470", -6.1096, 106.7404},
+	{"Rawamangun", "Pulo Gadung", "Jakarta Timur", "13220", -6.1955, 106.8877},
+}
+
+var streets = []string{
+	"Jl. Kemang Raya", "Jl. Senopati", "Jl. Gunawarman", "Jl. Wijaya I", "Jl. Panglima Polim",
+	"Jl. Cikajang", "Jl. Tebet Raya", "Jl. Cikini Raya", "Jl. Sabang", "Jl. Kebon Sirih",
+	"Jl. Pintu Besar Utara", "Jl. Boulevard Raya", "Jl. Ampera Raya", "Jl. Fatmawati", "Jl. Bangka Raya",
+}
+
+// Name parts are combined into venue names such as "Kopi Senja" or "Ruang Temu Kerja"
+var (
+	cafePrefixes      = []string{"Kopi", "Kedai", "Warung Kopi", "Toko Kopi", "Rumah Kopi", "Sudut", "Teras"}
+	cafeNouns         = []string{"Senja", "Pagi", "Nusantara", "Tetangga", "Kala", "Rimba", "Sore", "Lokal", "Tuku", "Janji"}
+	restaurantPrefix  = []string{"Warung", "Dapur", "Rumah Makan", "Bistro", "Depot", "Kitchen"}
+	restaurantNouns   = []string{"Ibu", "Nenek", "Sederhana", "Selera", "Rasa", "Bumbu", "Kampung", "Sambal", "Pesisir", "Kenari"}
+	barPrefixes       = []string{"Bar", "Lounge", "Taproom", "Rooftop"}
+	barNouns          = []string{"Malam", "Bintang", "Cahaya", "Kota", "Angin", "Lampu"}
+	coworkingPrefixes = []string{"Ruang", "Hub", "Kolektif", "Studio", "Base"}
+	coworkingNouns    = []string{"Temu", "Kerja", "Karya", "Ide", "Kreatif", "Fokus"}
+)
+
+// kind is a category of seeded POI with the attributes typical for it
+type kind struct {
+	CategoryKey string
+	Weight      int // Relative share of generated POIs
+	Prefixes    []string
+	Nouns       []string
+	Cuisines    []string
+	SpendMin    float64 // IDR per person
+	SpendMax    float64
+	Opens       string
+	Closes      string
+	Description string // %s is the area name
+}
+
+var kinds = []kind{
+	{
+		CategoryKey: "category.cafe", Weight: 45,
+		Prefixes: cafePrefixes, Nouns: cafeNouns,
+		Cuisines: []string{"cafe", "coffee", "pastry", "fusion"},
+		SpendMin: 35000, SpendMax: 120000, Opens: "07:00", Closes: "22:00",
+		Description: "Neighbourhood coffee shop in %s with single-origin brews, pastries and plenty of seats for working.",
+	},
+	{
+		CategoryKey: "category.restaurant", Weight: 30,
+		Prefixes: restaurantPrefix, Nouns: restaurantNouns,
+		Cuisines: []string{"indonesian", "sundanese", "padang", "javanese", "japanese", "italian", "fusion"},
+		SpendMin: 40000, SpendMax: 250000, Opens: "10:00", Closes: "22:00",
+		Description: "Casual dining in %s serving home-style dishes, popular for family lunches and group dinners.",
+	},
+	{
+		CategoryKey: "category.bar", Weight: 10,
+		Prefixes: barPrefixes, Nouns: barNouns,
+		Cuisines: []string{"western", "fusion"},
+		SpendMin: 150000, SpendMax: 500000, Opens: "17:00", Closes: "02:00",
+		Description: "Evening spot in %s for cocktails and small plates, with live music on weekends.",
+	},
+	{
+		CategoryKey: "category.coworking", Weight: 15,
+		Prefixes: coworkingPrefixes, Nouns: coworkingNouns,
+		SpendMin: 50000, SpendMax: 150000, Opens: "08:00", Closes: "21:00",
+		Description: "Coworking space in %s with day passes, meeting rooms and fast, reliable internet.",
+	},
+}
+
+var (
+	wifiQualities  = []string{"slow", "moderate", "fast", "excellent"}
+	powerOutlets   = []string{"limited", "moderate", "plenty"}
+	noiseLevels    = []string{"quiet", "moderate", "lively"}
+	lightings      = []string{"dim", "moderate", "bright", "natural"}
+	cleanliness    = []string{"average", "clean", "spotless"}
+	vibes          = []string{"industrial", "cozy", "tropical", "minimalist", "luxury", "retro", "nature"}
+	crowdTypes     = []string{"quiet_study", "social_lively", "business"}
+	seatingOptions = []string{"ergonomic", "communal", "high-tops", "outdoor", "private-booths"}
+	parkingOptions = []string{"car", "motorcycle", "valet"}
+	dietaryOptions = []string{"vegan", "vegetarian", "halal", "gluten_free"}
+	paymentOptions = []string{"cash", "qris", "credit_card"}
+	weekdays       = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+)
+
+var reviewSnippets = []string{
+	"Great place to get work done, the wifi held up all afternoon.",
+	"Coffee was good but it gets crowded after lunch.",
+	"Friendly staff and comfortable seating. Will come back.",
+	"A bit pricey for the portion size, but the food is tasty.",
+	"Nice atmosphere in the evening, music was a little loud.",
+	"Parking is hard to find, better to come by ojek.",
+	"Hidden gem, quiet enough for calls.",
+}
+
+// Categories and vocabularies the API expects. Migrations insert them too; the
+// seeder only adds the ones missing from an older or hand-edited database.
+var categoryKeys = []struct{ Key, Icon string }{
+	{"category.cafe", "☕"},
+	{"category.restaurant", "🍽️"},
+	{"category.bar", "🍺"},
+	{"category.attraction", "🎭"},
+	{"category.hotel", "🏨"},
+	{"category.shopping", "🛍️"},
+	{"category.activity", "🏃"},
+	{"category.coworking", "💻"},
+}
+
+var vocabularyKeys = []struct{ Type, Key, Icon string }{
+	{"amenity", "amenity.wifi", "📶"},
+	{"amenity", "amenity.power_outlets", "🔌"},
+	{"amenity", "amenity.outdoor_seating", "🌳"},
+	{"amenity", "amenity.parking", "🅿️"},
+	{"amenity", "amenity.wheelchair_accessible", "♿"},
+	{"food", "food.vegan", "🌱"},
+	{"food", "food.vegetarian", "🥗"},
+	{"food", "food.halal", "🕌"},
+	{"food", "food.gluten_free", "🌾"},
+	{"payment", "payment.cash", "💵"},
+	{"payment", "payment.qris", "📱"},
+	{"payment", "payment.credit_card", "💳"},
+	{"pet", "pet.dogs", "🐕"},
+	{"pet", "pet.cats", "🐈"},
+	{"event", "event.live_music", "🎵"},
+}
+
+// seedUsers are the local accounts owning seeded content. They cannot sign in
+// through Clerk; sign up normally and promote yourself with the roles API.
+var seedUsers = []struct{ Email, Name, Role string }{
+	{"admin@maukemana.local", "Seed Admin", "admin"},
+	{"moderator@maukemana.local", "Seed Moderator", "moderator"},
+	{"rina@maukemana.local", "Rina Pratiwi", "user"},
+	{"budi@maukemana.local", "Budi Santoso", "user"},
+	{"sari@maukemana.local", "Sari Wulandari", "user"},
+	{"andi@maukemana.local", "Andi Wijaya", "user"},
+}
@@ -0,0 +1,179 @@
+package seed
+
+import (
+	"fmt"
+	"math/rand"
+
+	"maukemana-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// generated is a POI ready to insert, with the reviews left on it
+type generated struct {
+	CategoryKey string
+	Input       repositories.CreatePOIInput
+	Verified    bool
+	Reviews     []generatedReview
+}
+
+type generatedReview struct {
+	UserID     uuid.UUID
+	Rating     int
+	Content    string
+	WifiRating *int
+}
+
+// placeholderImage points at a stable placeholder photo for the n-th image of POI i
+func placeholderImage(i, n int) string {
+	return fmt.Sprintf("https://picsum.photos/seed/maukemana-%04d-%d/1200/800", i, n)
+}
+
+// generatePOI builds the i-th POI. users[0] (the seed admin) owns every POI;
+// the remaining users write its reviews.
+func generatePOI(rng *rand.Rand, i int, users []uuid.UUID) generated {
+	k := pickKind(rng)
+	area := neighborhoods[rng.Intn(len(neighborhoods))]
+
+	name := fmt.Sprintf("%s %s", pick(rng, k.Prefixes), pick(rng, k.Nouns))
+	description := fmt.Sprintf(k.Description, area.Name)
+	address := fmt.Sprintf("%s No. %d, %s", pick(rng, streets), 1+rng.Intn(120), area.Name)
+	district, city, village, postal := area.District, area.City, area.Name, area.Postal
+
+	// Roughly ±1 km around the area center
+	lat := area.Latitude + (rng.Float64()-0.5)*0.02
+	lng := area.Longitude + (rng.Float64()-0.5)*0.02
+
+	gallery := make([]string, photosPerPOI)
+	for n := range gallery {
+		gallery[n] = placeholderImage(i, n)
+	}
+	cover := gallery[0]
+
+	openHours := make(map[string]interface{}, len(weekdays))
+	for _, day := range weekdays {
+		openHours[day] = map[string]interface{}{"open": k.Opens, "close": k.Closes}
+	}
+
+	spendMin := k.SpendMin
+	spendMax := spendMin + float64(rng.Intn(int((k.SpendMax-k.SpendMin)/5000)+1))*5000
+	priceRange := priceRangeFor(spendMax)
+	currency := "IDR"
+
+	wifiSpeed := 5 + rng.Intn(96)
+	owner := users[0]
+
+	input := repositories.CreatePOIInput{
+		Name:                 name,
+		Categories:           []string{k.CategoryKey},
+		Description:          &description,
+		CoverImageURL:        &cover,
+		GalleryImageURLs:     gallery,
+		Address:              &address,
+		District:             &district,
+		City:                 &city,
+		Village:              &village,
+		PostalCode:           &postal,
+		Latitude:             lat,
+		Longitude:            lng,
+		ParkingOptions:       sample(rng, parkingOptions),
+		WheelchairAccessible: rng.Intn(3) == 0,
+		WifiQuality:          ptr(pick(rng, wifiQualities)),
+		PowerOutlets:         ptr(pick(rng, powerOutlets)),
+		SeatingOptions:       sample(rng, seatingOptions),
+		NoiseLevel:           ptr(pick(rng, noiseLevels)),
+		HasAC:                rng.Intn(4) != 0,
+		Vibes:                sample(rng, vibes),
+		CrowdType:            sample(rng, crowdTypes),
+		Lighting:             ptr(pick(rng, lightings)),
+		Cleanliness:          ptr(pick(rng, cleanliness)),
+		PriceRange:           &priceRange,
+		PriceCurrency:        &currency,
+		AvgSpendMin:          &spendMin,
+		AvgSpendMax:          &spendMax,
+		DietaryOptions:       sample(rng, dietaryOptions),
+		OpenHours:            openHours,
+		PaymentOptions:       append([]string{"cash"}, sample(rng, paymentOptions[1:])...),
+		KidsFriendly:         rng.Intn(2) == 0,
+		SmokerFriendly:       rng.Intn(3) == 0,
+		CreatedBy:            &owner,
+		WifiSpeedMbps:        &wifiSpeed,
+		ErgonomicSeating:     rng.Intn(3) == 0,
+	}
+	if len(k.Cuisines) > 0 {
+		input.Cuisine = ptr(pick(rng, k.Cuisines))
+	}
+
+	return generated{
+		CategoryKey: k.CategoryKey,
+		Input:       input,
+		Verified:    rng.Intn(3) == 0,
+		Reviews:     generateReviews(rng, users[1:]),
+	}
+}
+
+// generateReviews leaves up to three reviews from distinct users
+func generateReviews(rng *rand.Rand, users []uuid.UUID) []generatedReview {
+	n := rng.Intn(4)
+	reviews := make([]generatedReview, 0, n)
+	for _, idx := range rng.Perm(len(users))[:min(n, len(users))] {
+		r := generatedReview{
+			UserID:  users[idx],
+			Rating:  2 + rng.Intn(4),
+			Content: pick(rng, reviewSnippets),
+		}
+		if rng.Intn(2) == 0 {
+			wifi := 1 + rng.Intn(5)
+			r.WifiRating = &wifi
+		}
+		reviews = append(reviews, r)
+	}
+	return reviews
+}
+
+func pickKind(rng *rand.Rand) kind {
+	total := 0
+	for _, k := range kinds {
+		total += k.Weight
+	}
+	n := rng.Intn(total)
+	for _, k := range kinds {
+		if n < k.Weight {
+			return k
+		}
+		n -= k.Weight
+	}
+	return kinds[len(kinds)-1]
+}
+
+// priceRangeFor maps the upper average spend in IDR to the 1-4 price range
+func priceRangeFor(spendMax float64) int {
+	switch {
+	case spendMax <= 50000:
+		return 1
+	case spendMax <= 150000:
+		return 2
+	case spendMax <= 300000:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func pick(rng *rand.Rand, values []string) string {
+	return values[rng.Intn(len(values))]
+}
+
+// sample returns between one and three distinct values
+func sample(rng *rand.Rand, values []string) []string {
+	n := 1 + rng.Intn(min(3, len(values)))
+	out := make([]string, 0, n)
+	for _, idx := range rng.Perm(len(values))[:n] {
+		out = append(out, values[idx])
+	}
+	return out
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,221 @@
+// Package seed fills a local database with realistic data for development:
+// the category and vocabulary taxonomy, a handful of users and approved POIs
+// scattered around Jakarta with placeholder photos and reviews. Seeding is
+// idempotent; reruns only add what is missing.
+package seed
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"maukemana-backend/internal/database"
+	"maukemana-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Source marks seeded POIs in points_of_interest.source
+const Source = "seed"
+
+// photosPerPOI is the number of placeholder photos attached to each POI
+const photosPerPOI = 3
+
+// Options controls a seeding run
+type Options struct {
+	Count int   // Number of POIs
+	Seed  int64 // Random seed; the same seed always generates the same POIs
+}
+
+// Summary reports what a run added
+type Summary struct {
+	Users        int
+	POIsCreated  int
+	POIsExisting int
+	Photos       int
+	Reviews      int
+}
+
+// Seeder writes seed data
+type Seeder struct {
+	db   *database.DB
+	pois *repositories.POIRepository
+}
+
+// New creates a seeder
+func New(db *database.DB) *Seeder {
+	return &Seeder{db: db, pois: repositories.NewPOIRepository(db)}
+}
+
+// Run seeds the database
+func (s *Seeder) Run(ctx context.Context, opts Options) (*Summary, error) {
+	summary := &Summary{}
+
+	if err := s.ensureTaxonomy(ctx); err != nil {
+		return nil, err
+	}
+	categories, err := s.categoryIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.ensureUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summary.Users = len(users)
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	for i := 0; i < opts.Count; i++ {
+		// Generate before checking for an existing row so every POI keeps its
+		// attributes regardless of which ones a previous run created
+		p := generatePOI(rng, i, users)
+		sourceID := fmt.Sprintf("seed/%04d", i)
+
+		exists, err := s.poiExists(ctx, sourceID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			summary.POIsExisting++
+			continue
+		}
+
+		err = s.db.WithTx(ctx, func(ctx context.Context) error {
+			return s.createPOI(ctx, sourceID, p, categories[p.CategoryKey], summary)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("seed poi %s %q: %w", sourceID, p.Input.Name, err)
+		}
+		summary.POIsCreated++
+	}
+
+	if summary.POIsCreated > 0 {
+		if err := s.db.RefreshMaterializedView(ctx); err != nil {
+			return summary, fmt.Errorf("refresh poi view: %w", err)
+		}
+	}
+	return summary, nil
+}
+
+// ensureTaxonomy adds the categories and vocabularies missing from the database
+func (s *Seeder) ensureTaxonomy(ctx context.Context) error {
+	conn := s.db.Conn(ctx)
+	for _, c := range categoryKeys {
+		_, err := conn.ExecContext(ctx, `
+			INSERT INTO categories (name_key, icon)
+			SELECT $1, $2
+			WHERE NOT EXISTS (SELECT 1 FROM categories WHERE name_key = $1)
+		`, c.Key, c.Icon)
+		if err != nil {
+			return fmt.Errorf("seed category %s: %w", c.Key, err)
+		}
+	}
+	for _, v := range vocabularyKeys {
+		_, err := conn.ExecContext(ctx, `
+			INSERT INTO vocabularies (vocab_type, key, icon)
+			SELECT $1, $2, $3
+			WHERE NOT EXISTS (SELECT 1 FROM vocabularies WHERE vocab_type = $1 AND key = $2)
+		`, v.Type, v.Key, v.Icon)
+		if err != nil {
+			return fmt.Errorf("seed vocabulary %s: %w", v.Key, err)
+		}
+	}
+	return nil
+}
+
+func (s *Seeder) categoryIDs(ctx context.Context) (map[string]uuid.UUID, error) {
+	var rows []struct {
+		ID  uuid.UUID `db:"category_id"`
+		Key string    `db:"name_key"`
+	}
+	if err := s.db.Conn(ctx).SelectContext(ctx, &rows, `SELECT DISTINCT ON (name_key) category_id, name_key FROM categories ORDER BY name_key, created_at`); err != nil {
+		return nil, fmt.Errorf("list categories: %w", err)
+	}
+	ids := make(map[string]uuid.UUID, len(rows))
+	for _, r := range rows {
+		ids[r.Key] = r.ID
+	}
+	return ids, nil
+}
+
+// ensureUsers creates the seed users, returning their IDs in seedUsers order
+func (s *Seeder) ensureUsers(ctx context.Context) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(seedUsers))
+	for _, u := range seedUsers {
+		var id uuid.UUID
+		err := s.db.Conn(ctx).GetContext(ctx, &id, `
+			INSERT INTO users (email, name, role)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (email) DO UPDATE SET email = EXCLUDED.email
+			RETURNING user_id
+		`, u.Email, u.Name, u.Role)
+		if err != nil {
+			return nil, fmt.Errorf("seed user %s: %w", u.Email, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (s *Seeder) poiExists(ctx context.Context, sourceID string) (bool, error) {
+	var id uuid.UUID
+	err := s.db.Conn(ctx).GetContext(ctx, &id, `SELECT poi_id FROM points_of_interest WHERE source = $1 AND source_id = $2`, Source, sourceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("find seeded poi: %w", err)
+	}
+	return true, nil
+}
+
+// createPOI inserts one generated POI as approved, with its photos and reviews
+func (s *Seeder) createPOI(ctx context.Context, sourceID string, p generated, categoryID uuid.UUID, summary *Summary) error {
+	if categoryID != uuid.Nil {
+		p.Input.CategoryIDs = []string{categoryID.String()}
+	}
+	poi, err := s.pois.Create(ctx, p.Input)
+	if err != nil {
+		return err
+	}
+
+	conn := s.db.Conn(ctx)
+	var category *uuid.UUID
+	if categoryID != uuid.Nil {
+		category = &categoryID
+	}
+	_, err = conn.ExecContext(ctx, `
+		UPDATE points_of_interest
+		SET status = 'approved', approved_at = COALESCE(approved_at, NOW()), is_verified = $2, category_id = $3, source = $4, source_id = $5
+		WHERE poi_id = $1
+	`, poi.PoiID, p.Verified, category, Source, sourceID)
+	if err != nil {
+		return fmt.Errorf("approve seeded poi: %w", err)
+	}
+
+	for _, url := range p.Input.GalleryImageURLs {
+		_, err := conn.ExecContext(ctx, `
+			INSERT INTO photos (poi_id, user_id, url, original_url, is_admin_official)
+			VALUES ($1, $2, $3, $3, TRUE)
+		`, poi.PoiID, p.Input.CreatedBy, url)
+		if err != nil {
+			return fmt.Errorf("seed photo: %w", err)
+		}
+		summary.Photos++
+	}
+
+	for _, r := range p.Reviews {
+		_, err := conn.ExecContext(ctx, `
+			INSERT INTO reviews (poi_id, user_id, rating, content, wifi_rating)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (user_id, poi_id) DO NOTHING
+		`, poi.PoiID, r.UserID, r.Rating, r.Content, r.WifiRating)
+		if err != nil {
+			return fmt.Errorf("seed review: %w", err)
+		}
+		summary.Reviews++
+	}
+	return nil
+}
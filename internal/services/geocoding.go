@@ -1,7 +1,10 @@
 package services
 
 import (
+	"context"
 	"fmt"
+
+	"maukemana-backend/internal/breaker"
 )
 
 // AddressDetails contains address components
@@ -18,6 +21,25 @@ type GeocodingService interface {
 	ReverseGeocode(lat, lng float64) (*AddressDetails, error)
 }
 
+// breakerGeocodingService fails fast while the geocoder keeps failing, so
+// POI creation skips address enrichment instead of waiting on it
+type breakerGeocodingService struct {
+	inner   GeocodingService
+	breaker *breaker.Breaker
+}
+
+// NewBreakerGeocodingService wraps a geocoding service in a circuit breaker
+func NewBreakerGeocodingService(inner GeocodingService, b *breaker.Breaker) GeocodingService {
+	return &breakerGeocodingService{inner: inner, breaker: b}
+}
+
+// ReverseGeocode calls the wrapped service unless the breaker is open
+func (s *breakerGeocodingService) ReverseGeocode(lat, lng float64) (*AddressDetails, error) {
+	return breaker.Call(context.Background(), s.breaker, func() (*AddressDetails, error) {
+		return s.inner.ReverseGeocode(lat, lng)
+	})
+}
+
 // MockGeocodingService implements a mock geocoding service
 type MockGeocodingService struct{}
 
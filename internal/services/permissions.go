@@ -0,0 +1,40 @@
+package services
+
+// Permission is a fine-grained capability granted to roles
+type Permission string
+
+// Permissions checked by the API. Roles are mapped to permissions in the
+// role_permissions table.
+const (
+	PermPOIApprove         Permission = "poi:approve"         // Moderate submissions: approve, reject, archive
+	PermPOIMerge           Permission = "poi:merge"           // Edit POIs owned by others and merge their edit proposals
+	PermPOITakedown        Permission = "poi:takedown"        // Unpublish POIs for legal or safety reasons
+	PermUserManage         Permission = "user:manage"         // Assign roles to users
+	PermImagingAdmin       Permission = "imaging:admin"       // Reprocess any uploaded image
+	PermTaxonomyManage     Permission = "taxonomy:manage"     // Manage category and vocabulary labels
+	PermWebhookManage      Permission = "webhook:manage"      // Manage webhook subscriptions
+	PermAreaManage         Permission = "area:manage"         // Manage service areas
+	PermPOIVerify          Permission = "poi:verify"          // Review business verification requests
+	PermAnnouncementManage Permission = "announcement:manage" // Manage in-app announcements
+	PermAnalyticsView      Permission = "analytics:view"      // View product analytics
+	PermSystemDebug        Permission = "system:debug"        // Inspect runtime internals such as the database pool
+	PermCommentModerate    Permission = "comment:moderate"    // Hide and delete comments of any user
+)
+
+// PermissionSet is the set of permissions held by an actor
+type PermissionSet map[Permission]struct{}
+
+// NewPermissionSet builds a set from permission names
+func NewPermissionSet(names []string) PermissionSet {
+	set := make(PermissionSet, len(names))
+	for _, n := range names {
+		set[Permission(n)] = struct{}{}
+	}
+	return set
+}
+
+// Has reports whether the set contains p
+func (s PermissionSet) Has(p Permission) bool {
+	_, ok := s[p]
+	return ok
+}
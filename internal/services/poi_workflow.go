@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// POIStatus is a state in the POI moderation workflow
+type POIStatus string
+
+const (
+	POIStatusDraft    POIStatus = "draft"
+	POIStatusPending  POIStatus = "pending"
+	POIStatusApproved POIStatus = "approved"
+	POIStatusRejected POIStatus = "rejected"
+	POIStatusArchived POIStatus = "archived"
+	// Unpublished for a legal or safety reason; there is no way back
+	POIStatusTakenDown POIStatus = "taken_down"
+)
+
+// Built-in roles. What each role may do is defined by its permissions.
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+var (
+	ErrPOINotFound         = errors.New("poi not found")
+	ErrInvalidTransition   = errors.New("status transition not allowed")
+	ErrTransitionForbidden = errors.New("not permitted to perform this transition")
+	ErrReasonRequired      = errors.New("a reason is required for this transition")
+)
+
+// Transition is an allowed edge in the workflow
+type Transition struct {
+	From          POIStatus
+	To            POIStatus
+	Permission    Permission // Required permission; empty lets any authenticated actor (ownership is checked by callers)
+	RequireReason bool
+}
+
+// DefaultPOITransitions returns the standard moderation workflow:
+// draft→pending→approved/rejected, rejected→pending (resubmit), approved⇄archived,
+// and a takedown from any state.
+func DefaultPOITransitions() []Transition {
+	return []Transition{
+		{From: POIStatusDraft, To: POIStatusPending},
+		{From: POIStatusPending, To: POIStatusPending}, // Resubmit after edits
+		{From: POIStatusRejected, To: POIStatusPending},
+		{From: POIStatusRejected, To: POIStatusDraft},
+		{From: POIStatusPending, To: POIStatusApproved, Permission: PermPOIApprove},
+		{From: POIStatusPending, To: POIStatusRejected, Permission: PermPOIApprove, RequireReason: true},
+		{From: POIStatusApproved, To: POIStatusArchived, Permission: PermPOIApprove},
+		{From: POIStatusArchived, To: POIStatusApproved, Permission: PermPOIApprove},
+		{From: POIStatusDraft, To: POIStatusTakenDown, Permission: PermPOITakedown, RequireReason: true},
+		{From: POIStatusPending, To: POIStatusTakenDown, Permission: PermPOITakedown, RequireReason: true},
+		{From: POIStatusApproved, To: POIStatusTakenDown, Permission: PermPOITakedown, RequireReason: true},
+		{From: POIStatusRejected, To: POIStatusTakenDown, Permission: PermPOITakedown, RequireReason: true},
+		{From: POIStatusArchived, To: POIStatusTakenDown, Permission: PermPOITakedown, RequireReason: true},
+	}
+}
+
+// Actor identifies who is performing a transition
+type Actor struct {
+	UserID      uuid.UUID
+	Role        string
+	Permissions PermissionSet
+}
+
+// Can reports whether the actor holds a permission
+func (a Actor) Can(p Permission) bool {
+	return a.Permissions.Has(p)
+}
+
+// TransitionEvent is delivered to hooks after a transition is committed
+type TransitionEvent struct {
+	PoiID  uuid.UUID
+	From   POIStatus
+	To     POIStatus
+	Actor  Actor
+	Reason *string
+}
+
+// TransitionGuard vetoes a transition by actor before it is applied, inside its transaction
+type TransitionGuard func(ctx context.Context, poiID uuid.UUID, from, to POIStatus, actor Actor) error
+
+// TransitionHook reacts to committed transitions (notifications, XP, indexing...).
+// Hook errors are logged and never undo the transition.
+type TransitionHook func(ctx context.Context, event TransitionEvent) error
+
+// POIStatusRepository is the storage the workflow needs
+type POIStatusRepository interface {
+	GetStatusForUpdate(ctx context.Context, poiID uuid.UUID) (string, error)
+	UpdateStatus(ctx context.Context, poiID uuid.UUID, status string, reason *string) error
+}
+
+// TxRunner runs fn in a transaction carried on ctx (implemented by database.DB)
+type TxRunner interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// POIWorkflowService enforces the POI status state machine
+type POIWorkflowService struct {
+	repo        POIStatusRepository
+	tx          TxRunner
+	transitions map[POIStatus]map[POIStatus]Transition
+
+	mu     sync.RWMutex
+	hooks  []TransitionHook
+	guards map[POIStatus][]TransitionGuard
+}
+
+// NewPOIWorkflowService creates a workflow service with the given transitions
+func NewPOIWorkflowService(repo POIStatusRepository, tx TxRunner, transitions []Transition) *POIWorkflowService {
+	s := &POIWorkflowService{
+		repo:        repo,
+		tx:          tx,
+		transitions: make(map[POIStatus]map[POIStatus]Transition),
+		guards:      make(map[POIStatus][]TransitionGuard),
+	}
+	for _, t := range transitions {
+		if s.transitions[t.From] == nil {
+			s.transitions[t.From] = make(map[POIStatus]Transition)
+		}
+		s.transitions[t.From][t.To] = t
+	}
+	return s
+}
+
+// Subscribe registers a hook that runs after every committed transition
+func (s *POIWorkflowService) Subscribe(hook TransitionHook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, hook)
+}
+
+// Guard registers a check that must pass for every transition into status to
+func (s *POIWorkflowService) Guard(to POIStatus, guard TransitionGuard) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.guards[to] = append(s.guards[to], guard)
+}
+
+// CanTransition validates a transition without performing it
+func (s *POIWorkflowService) CanTransition(from, to POIStatus, actor Actor, reason *string) error {
+	t, ok := s.transitions[from][to]
+	if !ok {
+		return fmt.Errorf("%w: %s → %s", ErrInvalidTransition, from, to)
+	}
+	if t.Permission != "" && !actor.Can(t.Permission) {
+		return fmt.Errorf("%w: %s → %s", ErrTransitionForbidden, from, to)
+	}
+	if t.RequireReason && (reason == nil || strings.TrimSpace(*reason) == "") {
+		return ErrReasonRequired
+	}
+	return nil
+}
+
+// AllowedTransitions lists the target states reachable from a status for an actor
+func (s *POIWorkflowService) AllowedTransitions(from POIStatus, actor Actor) []POIStatus {
+	var out []POIStatus
+	for to, t := range s.transitions[from] {
+		if t.Permission == "" || actor.Can(t.Permission) {
+			out = append(out, to)
+		}
+	}
+	return out
+}
+
+// Transition moves a POI to a new status and notifies hooks once committed
+func (s *POIWorkflowService) Transition(ctx context.Context, poiID uuid.UUID, to POIStatus, actor Actor, reason *string) (*TransitionEvent, error) {
+	return s.TransitionWith(ctx, poiID, to, actor, reason, nil)
+}
+
+// TransitionWith is Transition with fn run in the same transaction once the
+// status is updated, for data that must be stored with it (such as rejection
+// feedback). An error from fn rolls the transition back.
+func (s *POIWorkflowService) TransitionWith(ctx context.Context, poiID uuid.UUID, to POIStatus, actor Actor, reason *string, fn func(ctx context.Context, event TransitionEvent) error) (*TransitionEvent, error) {
+	var event *TransitionEvent
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		event, err = s.apply(ctx, poiID, to, actor, reason)
+		if err != nil || fn == nil {
+			return err
+		}
+		return fn(ctx, *event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(ctx, *event)
+	return event, nil
+}
+
+// BatchResult is the per-item outcome of BatchTransition
+type BatchResult struct {
+	PoiID          uuid.UUID `json:"poi_id"`
+	Result         string    `json:"result"` // "updated", "not_found" or "invalid_transition"
+	PreviousStatus string    `json:"previous_status,omitempty"`
+	Error          string    `json:"error,omitempty"`
+}
+
+// BatchTransition applies the same transition to many POIs in one transaction.
+// Missing POIs and disallowed transitions are reported per item; storage errors
+// roll back the whole batch. Hooks run after commit for the updated items only.
+func (s *POIWorkflowService) BatchTransition(ctx context.Context, poiIDs []uuid.UUID, to POIStatus, actor Actor, reason *string) ([]BatchResult, error) {
+	var results []BatchResult
+	var events []TransitionEvent
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		results = make([]BatchResult, 0, len(poiIDs))
+		events = events[:0]
+
+		for _, poiID := range poiIDs {
+			event, err := s.apply(ctx, poiID, to, actor, reason)
+			switch {
+			case errors.Is(err, ErrPOINotFound):
+				results = append(results, BatchResult{PoiID: poiID, Result: "not_found"})
+			case errors.Is(err, ErrInvalidTransition), errors.Is(err, ErrTransitionForbidden), errors.Is(err, ErrReasonRequired),
+				errors.Is(err, ErrOutsideServiceArea), errors.Is(err, ErrAssignedToOther):
+				results = append(results, BatchResult{PoiID: poiID, Result: "invalid_transition", Error: err.Error()})
+			case err != nil:
+				return err
+			default:
+				results = append(results, BatchResult{PoiID: poiID, Result: "updated", PreviousStatus: string(event.From)})
+				events = append(events, *event)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		s.notify(ctx, event)
+	}
+	return results, nil
+}
+
+// apply validates and performs a single transition inside the caller's transaction
+func (s *POIWorkflowService) apply(ctx context.Context, poiID uuid.UUID, to POIStatus, actor Actor, reason *string) (*TransitionEvent, error) {
+	current, err := s.repo.GetStatusForUpdate(ctx, poiID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPOINotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	from := POIStatus(current)
+	if err := s.CanTransition(from, to, actor, reason); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	guards := append([]TransitionGuard(nil), s.guards[to]...)
+	s.mu.RUnlock()
+	for _, guard := range guards {
+		if err := guard(ctx, poiID, from, to, actor); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateStatus(ctx, poiID, string(to), reason); err != nil {
+		return nil, err
+	}
+
+	return &TransitionEvent{PoiID: poiID, From: from, To: to, Actor: actor, Reason: reason}, nil
+}
+
+// notify runs subscribed hooks for a committed transition
+func (s *POIWorkflowService) notify(ctx context.Context, event TransitionEvent) {
+	s.mu.RLock()
+	hooks := append([]TransitionHook(nil), s.hooks...)
+	s.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if err := hook(ctx, event); err != nil {
+			slog.Error("poi workflow hook failed",
+				"poi_id", event.PoiID, "from", event.From, "to", event.To, "error", err)
+		}
+	}
+}
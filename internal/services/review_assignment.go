@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrAssignedToOther is returned when a reviewer decides on a POI assigned to someone else
+var ErrAssignedToOther = errors.New("poi is assigned to another reviewer")
+
+// AssignmentChecker returns the reviewer a pending POI is currently assigned to
+type AssignmentChecker interface {
+	ActiveAssignee(ctx context.Context, poiID uuid.UUID) (*uuid.UUID, error)
+}
+
+type overrideAssignmentKey struct{}
+
+// OverrideAssignment marks ctx so that AssignmentGuard lets the actor decide
+// on a POI assigned to another reviewer
+func OverrideAssignment(ctx context.Context) context.Context {
+	return context.WithValue(ctx, overrideAssignmentKey{}, true)
+}
+
+// AssignmentGuard rejects decisions by anyone but the assigned reviewer while
+// an assignment is active, unless the context overrides it
+func AssignmentGuard(assignments AssignmentChecker) TransitionGuard {
+	return func(ctx context.Context, poiID uuid.UUID, _, _ POIStatus, actor Actor) error {
+		if ctx.Value(overrideAssignmentKey{}) != nil {
+			return nil
+		}
+		assignee, err := assignments.ActiveAssignee(ctx, poiID)
+		if err != nil {
+			return err
+		}
+		if assignee != nil && *assignee != actor.UserID {
+			return ErrAssignedToOther
+		}
+		return nil
+	}
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// maxSearchRadius bounds the radius filter of a POI search, in meters
+const maxSearchRadius = 50000
+
+// FilterError is a POI search filter, or combination of filters, that a client
+// must fix before the search can run
+type FilterError struct {
+	Param   string // Query parameter at fault
+	Message string
+}
+
+func (e *FilterError) Error() string { return e.Message }
+
+// filterErrorf builds a FilterError for param
+func filterErrorf(param, format string, args ...interface{}) *FilterError {
+	return &FilterError{Param: param, Message: fmt.Sprintf(format, args...)}
+}
+
+// enumFilters are single-valued filters and their accepted values
+var enumFilters = map[string][]string{
+	"wifi_quality":    {"none", "slow", "moderate", "fast", "excellent"},
+	"noise_level":     {"silent", "quiet", "moderate", "lively", "loud"},
+	"power_outlets":   {"none", "limited", "moderate", "plenty"},
+	"sort_by":         {"recommended", "nearest", "top_rated"},
+	"amenities_match": {"all", "any"},
+}
+
+// listFilters are comma-separated filters and their accepted values; nil
+// accepts any value, for lists that grow with the vocabularies
+var listFilters = map[string][]string{
+	"vibes":           nil,
+	"crowd_type":      nil,
+	"dietary_options": nil,
+	"seating_options": nil,
+	"parking_options": nil,
+	"amenities":       nil,
+	"table_heights":   {"low", "standard", "high", "adjustable"},
+}
+
+// boolFilters only accept true or false
+var boolFilters = []string{
+	"has_wifi", "has_ac", "verified", "has_active_special",
+	"wheelchair_accessible", "step_free_entrance", "accessible_restroom", "braille_menu", "accessible_parking",
+}
+
+// numberFilter is a numeric filter and its accepted range
+type numberFilter struct {
+	integer  bool
+	min, max float64
+	minOpen  bool // min itself is rejected
+}
+
+var numberFilters = map[string]numberFilter{
+	"price_range":     {integer: true, min: 1, max: 4},
+	"max_avg_spend":   {min: 0, max: 1e12},
+	"lat":             {min: -90, max: 90},
+	"lng":             {min: -180, max: 180},
+	"radius":          {min: 0, max: maxSearchRadius, minOpen: true},
+	"wifi_speed_min":  {integer: true, min: 0, max: 10000},
+	"min_wifi_rating": {min: 1, max: 5},
+}
+
+// FilterService normalizes and validates the POI search filters sent by
+// clients before they are parsed into repository filters
+type FilterService struct{}
+
+// NewFilterService creates a new filter service
+func NewFilterService() *FilterService {
+	return &FilterService{}
+}
+
+// Normalize returns a cleaned copy of the search query parameters: enum and
+// list values are lowercased and deduplicated and "any" drops a filter. It
+// returns a *FilterError for unknown values, out of range numbers, filters
+// that contradict each other and statuses the actor may not search.
+func (s *FilterService) Normalize(query url.Values, actor Actor) (url.Values, error) {
+	out := make(url.Values, len(query))
+	for k, v := range query {
+		out[k] = append([]string(nil), v...)
+	}
+
+	for param, allowed := range enumFilters {
+		if !out.Has(param) {
+			continue
+		}
+		value := strings.ToLower(strings.TrimSpace(out.Get(param)))
+		if value == "" || (value == "any" && !contains(allowed, value)) {
+			out.Del(param)
+			continue
+		}
+		if !contains(allowed, value) {
+			return nil, filterErrorf(param, "%s must be one of %s", param, strings.Join(allowed, ", "))
+		}
+		out.Set(param, value)
+	}
+
+	for param, allowed := range listFilters {
+		if !out.Has(param) {
+			continue
+		}
+		values, err := normalizeList(param, out.Get(param), allowed)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) == 0 {
+			out.Del(param)
+			continue
+		}
+		out.Set(param, strings.Join(values, ","))
+	}
+
+	for _, param := range boolFilters {
+		if !out.Has(param) {
+			continue
+		}
+		switch value := strings.ToLower(strings.TrimSpace(out.Get(param))); value {
+		case "true", "1":
+			out.Set(param, "true")
+		case "false", "0":
+			out.Set(param, "false")
+		case "", "any":
+			out.Del(param)
+		default:
+			return nil, filterErrorf(param, "%s must be true or false", param)
+		}
+	}
+
+	for param, f := range numberFilters {
+		if !out.Has(param) {
+			continue
+		}
+		raw := strings.ToLower(strings.TrimSpace(out.Get(param)))
+		if raw == "" || raw == "any" {
+			out.Del(param)
+			continue
+		}
+		value, err := checkNumber(param, raw, f)
+		if err != nil {
+			return nil, err
+		}
+		out.Set(param, value)
+	}
+
+	if category := strings.TrimSpace(out.Get("category_id")); category == "" || strings.EqualFold(category, "any") {
+		out.Del("category_id")
+	} else if _, err := uuid.Parse(category); err != nil {
+		return nil, filterErrorf("category_id", "category_id must be a UUID")
+	}
+
+	if err := checkStatus(out, actor); err != nil {
+		return nil, err
+	}
+	if err := checkCombinations(out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// normalizeList lowercases and deduplicates a comma-separated filter. A list
+// containing "any" matches everything and normalizes to no values.
+func normalizeList(param, raw string, allowed []string) ([]string, error) {
+	seen := map[string]bool{}
+	var values []string
+	for _, part := range strings.Split(raw, ",") {
+		value := strings.ToLower(strings.TrimSpace(part))
+		if value == "" || seen[value] {
+			continue
+		}
+		if value == "any" {
+			return nil, nil
+		}
+		if allowed != nil && !contains(allowed, value) {
+			return nil, filterErrorf(param, "%s values must be among %s", param, strings.Join(allowed, ", "))
+		}
+		seen[value] = true
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// checkNumber validates a numeric filter against its range and returns it in
+// canonical form
+func checkNumber(param, raw string, f numberFilter) (string, error) {
+	kind := "a number"
+	if f.integer {
+		kind = "an integer"
+	}
+	n, err := strconv.ParseFloat(raw, 64)
+	if err != nil || (f.integer && n != float64(int64(n))) {
+		return "", filterErrorf(param, "%s must be %s", param, kind)
+	}
+	if n < f.min || n > f.max || (f.minOpen && n == f.min) {
+		if f.minOpen {
+			return "", filterErrorf(param, "%s must be greater than %g and at most %g", param, f.min, f.max)
+		}
+		return "", filterErrorf(param, "%s must be between %g and %g", param, f.min, f.max)
+	}
+	return strconv.FormatFloat(n, 'f', -1, 64), nil
+}
+
+// checkStatus lets everyone search approved POIs and moderators any status
+func checkStatus(query url.Values, actor Actor) error {
+	if !query.Has("status") {
+		return nil
+	}
+	status := POIStatus(strings.ToLower(strings.TrimSpace(query.Get("status"))))
+	switch status {
+	case "":
+		query.Del("status")
+		return nil
+	case POIStatusApproved:
+	case POIStatusDraft, POIStatusPending, POIStatusRejected, POIStatusArchived, POIStatusTakenDown:
+		if !actor.Can(PermPOIApprove) {
+			return filterErrorf("status", "only moderators can search %s POIs", status)
+		}
+	default:
+		statuses := []string{
+			string(POIStatusDraft), string(POIStatusPending), string(POIStatusApproved),
+			string(POIStatusRejected), string(POIStatusArchived), string(POIStatusTakenDown),
+		}
+		sort.Strings(statuses)
+		return filterErrorf("status", "status must be one of %s", strings.Join(statuses, ", "))
+	}
+	query.Set("status", string(status))
+	return nil
+}
+
+// checkCombinations rejects filters that cannot match together or that only
+// apply alongside another filter
+func checkCombinations(query url.Values) error {
+	if query.Has("lat") != query.Has("lng") {
+		return filterErrorf("lat", "lat and lng must be given together")
+	}
+	if query.Has("radius") && !query.Has("lat") {
+		return filterErrorf("radius", "radius requires lat and lng")
+	}
+	if query.Has("amenities_match") && !query.Has("amenities") {
+		return filterErrorf("amenities_match", "amenities_match requires amenities")
+	}
+	if query.Has("currency") && !query.Has("max_avg_spend") {
+		return filterErrorf("currency", "currency only applies together with max_avg_spend")
+	}
+
+	if query.Get("wifi_quality") == "none" {
+		switch {
+		case query.Get("has_wifi") == "true":
+			return filterErrorf("wifi_quality", "wifi_quality=none contradicts has_wifi=true")
+		case query.Has("wifi_speed_min") && query.Get("wifi_speed_min") != "0":
+			return filterErrorf("wifi_quality", "wifi_quality=none contradicts wifi_speed_min")
+		case query.Has("min_wifi_rating"):
+			return filterErrorf("wifi_quality", "wifi_quality=none contradicts min_wifi_rating")
+		}
+	}
+	return nil
+}
+
+// contains reports whether values holds value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrOutsideServiceArea is returned when a POI's coordinates are not inside any active service area
+var ErrOutsideServiceArea = errors.New("coordinates are outside the service area")
+
+// ServiceAreaChecker tells whether a POI lies inside an active service area
+type ServiceAreaChecker interface {
+	POIInServiceArea(ctx context.Context, poiID uuid.UUID) (bool, error)
+}
+
+// ServiceAreaGuard rejects transitions of POIs located outside every active service area
+func ServiceAreaGuard(areas ServiceAreaChecker) TransitionGuard {
+	return func(ctx context.Context, poiID uuid.UUID, _, _ POIStatus, _ Actor) error {
+		inside, err := areas.POIInServiceArea(ctx, poiID)
+		if err != nil {
+			return err
+		}
+		if !inside {
+			return ErrOutsideServiceArea
+		}
+		return nil
+	}
+}
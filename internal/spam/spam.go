@@ -0,0 +1,104 @@
+// Package spam screens comments and reviews before they are stored: posting
+// velocity, repeated content and link-heavy text. Shadow bans are applied when
+// content is read, so a banned user's posts go through here like any other.
+package spam
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"maukemana-backend/internal/config"
+	"maukemana-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of user generated content screened by the guard
+const (
+	KindComment = "comment"
+	KindReview  = "review"
+)
+
+var (
+	// ErrTooFast is returned when a user exceeds the submission velocity limit
+	ErrTooFast = errors.New("you are posting too fast, please wait a few minutes")
+	// ErrDuplicate is returned when a user repeats content they recently posted
+	ErrDuplicate = errors.New("you already posted this content")
+	// ErrTooManyLinks is returned when content carries more links than allowed
+	ErrTooManyLinks = errors.New("content contains too many links")
+)
+
+// linkPattern matches URLs and bare www. hosts
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// Store is the submission history and user flags the guard relies on
+type Store interface {
+	IsSpamExempt(ctx context.Context, userID uuid.UUID) (bool, error)
+	CountSubmissions(ctx context.Context, userID uuid.UUID, since time.Time) (int, error)
+	HasDuplicate(ctx context.Context, sub repositories.UGCSubmission, since time.Time) (bool, error)
+	RecordSubmission(ctx context.Context, sub repositories.UGCSubmission) error
+}
+
+// Guard applies the anti-spam heuristics
+type Guard struct {
+	store    Store
+	settings config.SpamSettings
+}
+
+// NewGuard creates a spam guard
+func NewGuard(store Store, settings config.SpamSettings) *Guard {
+	return &Guard{store: store, settings: settings}
+}
+
+// Check screens a comment or review about to be posted on a POI and records it
+// when it passes. Spam-exempt users skip the heuristics.
+func (g *Guard) Check(ctx context.Context, userID uuid.UUID, kind string, targetID uuid.UUID, content string) error {
+	exempt, err := g.store.IsSpamExempt(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if exempt {
+		return nil
+	}
+
+	now := time.Now()
+	if g.settings.MaxPerWindow > 0 {
+		n, err := g.store.CountSubmissions(ctx, userID, now.Add(-g.settings.Window))
+		if err != nil {
+			return err
+		}
+		if n >= g.settings.MaxPerWindow {
+			return ErrTooFast
+		}
+	}
+
+	if len(linkPattern.FindAllStringIndex(content, -1)) > g.settings.MaxLinks {
+		return ErrTooManyLinks
+	}
+
+	sub := repositories.UGCSubmission{UserID: userID, Kind: kind, TargetID: targetID, ContentHash: contentHash(content)}
+	if strings.TrimSpace(content) != "" {
+		dup, err := g.store.HasDuplicate(ctx, sub, now.Add(-g.settings.DuplicateWindow))
+		if err != nil {
+			return err
+		}
+		if dup {
+			return ErrDuplicate
+		}
+	}
+
+	return g.store.RecordSubmission(ctx, sub)
+}
+
+// contentHash hashes text case-insensitively with whitespace collapsed, so
+// trivially reformatted copies still match
+func contentHash(content string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(content)), " ")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
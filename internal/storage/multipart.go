@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"maukemana-backend/internal/breaker"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// ErrNoSuchUpload is returned when a multipart upload was completed, aborted
+// or expired in the bucket
+var ErrNoSuchUpload = errors.New("multipart upload not found")
+
+// UploadedPart is a part already stored for a multipart upload
+type UploadedPart struct {
+	Number int32  `json:"part_number"`
+	ETag   string `json:"etag"`
+	Size   int64  `json:"size_bytes"`
+}
+
+// CreateMultipartUpload starts a multipart upload of key and returns the
+// bucket's upload ID
+func (r *R2Client) CreateMultipartUpload(ctx context.Context, key, contentType string) (string, error) {
+	out, err := breaker.Call(ctx, r.breaker, func() (*s3.CreateMultipartUploadOutput, error) {
+		return r.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+			Bucket:      aws.String(r.bucketName),
+			Key:         aws.String(key),
+			ContentType: aws.String(contentType),
+		})
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+	return aws.ToString(out.UploadId), nil
+}
+
+// PresignUploadPart creates a PUT URL for one part of a multipart upload.
+// Content-Length is part of the signature, so the PUT must send exactly size bytes.
+func (r *R2Client) PresignUploadPart(ctx context.Context, key, uploadID string, number int32, size int64, ttl time.Duration) (string, error) {
+	presignClient := s3.NewPresignClient(r.client)
+
+	request, err := presignClient.PresignUploadPart(ctx, &s3.UploadPartInput{
+		Bucket:        aws.String(r.bucketName),
+		Key:           aws.String(key),
+		UploadId:      aws.String(uploadID),
+		PartNumber:    aws.Int32(number),
+		ContentLength: aws.Int64(size),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to create presigned part URL: %w", err)
+	}
+
+	return request.URL, nil
+}
+
+// ListUploadedParts returns the parts stored so far for a multipart upload,
+// ordered by part number
+func (r *R2Client) ListUploadedParts(ctx context.Context, key, uploadID string) ([]UploadedPart, error) {
+	pages := s3.NewListPartsPaginator(r.client, &s3.ListPartsInput{
+		Bucket:   aws.String(r.bucketName),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	parts := []UploadedPart{}
+	for pages.HasMorePages() {
+		page, err := breaker.Call(ctx, r.breaker, func() (*s3.ListPartsOutput, error) {
+			return pages.NextPage(ctx)
+		})
+		if err != nil {
+			if isNoSuchUpload(err) {
+				return nil, ErrNoSuchUpload
+			}
+			return nil, fmt.Errorf("failed to list parts: %w", err)
+		}
+		for _, p := range page.Parts {
+			parts = append(parts, UploadedPart{
+				Number: aws.ToInt32(p.PartNumber),
+				ETag:   aws.ToString(p.ETag),
+				Size:   aws.ToInt64(p.Size),
+			})
+		}
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].Number < parts[j].Number })
+	return parts, nil
+}
+
+// CompleteMultipartUpload joins the given parts into the object
+func (r *R2Client) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []UploadedPart) error {
+	completed := make([]types.CompletedPart, len(parts))
+	for i, p := range parts {
+		completed[i] = types.CompletedPart{PartNumber: aws.Int32(p.Number), ETag: aws.String(p.ETag)}
+	}
+	err := r.breaker.Do(ctx, func() error {
+		_, err := r.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String(r.bucketName),
+			Key:             aws.String(key),
+			UploadId:        aws.String(uploadID),
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+		})
+		return err
+	})
+	if err != nil {
+		if isNoSuchUpload(err) {
+			return ErrNoSuchUpload
+		}
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	return nil
+}
+
+// AbortMultipartUpload discards a multipart upload and its stored parts.
+// Aborting an upload the bucket no longer knows is not an error.
+func (r *R2Client) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	err := r.breaker.Do(ctx, func() error {
+		_, err := r.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(r.bucketName),
+			Key:      aws.String(key),
+			UploadId: aws.String(uploadID),
+		})
+		return err
+	})
+	if err != nil && !isNoSuchUpload(err) {
+		return fmt.Errorf("failed to abort multipart upload: %w", err)
+	}
+	return nil
+}
+
+// isNoSuchUpload reports whether err is the bucket not knowing a multipart upload
+func isNoSuchUpload(err error) bool {
+	var notFound *types.NoSuchUpload
+	if errors.As(err, &notFound) {
+		return true
+	}
+	var apiErr interface{ ErrorCode() string }
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchUpload"
+}
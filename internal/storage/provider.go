@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"maukemana-backend/internal/config"
+)
+
+// Storage categories, each served by one configured profile
+const (
+	CategoryImages    = "images"    // Uploads, originals and derivatives of the image pipeline
+	CategoryDocuments = "documents" // Private documents such as business verification evidence
+)
+
+// ErrNotConfigured is returned when no storage profile is configured
+var ErrNotConfigured = errors.New("object storage is not configured")
+
+// Provider stores objects in one bucket
+type Provider interface {
+	GetObject(ctx context.Context, key string) ([]byte, error)
+	GetObjectStream(ctx context.Context, key, byteRange string) (*ObjectStream, error)
+	PutObject(ctx context.Context, key string, data []byte, contentType string) error
+	DeleteObject(ctx context.Context, key string) error
+	MoveObject(ctx context.Context, srcKey, dstKey string) error
+	ListObjects(ctx context.Context, prefix string, fn func(ObjectInfo) error) error
+	StatObject(ctx context.Context, key string) (ObjectInfo, error)
+	GetPublicURL(key string) string
+	GeneratePresignedURLWithMaxSize(ctx context.Context, key string, contentType string, maxSizeBytes int64) (string, error)
+	PresignGetURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+
+	CreateMultipartUpload(ctx context.Context, key, contentType string) (string, error)
+	PresignUploadPart(ctx context.Context, key, uploadID string, number int32, size int64, ttl time.Duration) (string, error)
+	ListUploadedParts(ctx context.Context, key, uploadID string) ([]UploadedPart, error)
+	CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []UploadedPart) error
+	AbortMultipartUpload(ctx context.Context, key, uploadID string) error
+}
+
+// Registry holds the configured storage profiles by name and picks the one
+// serving each category
+type Registry struct {
+	profiles   map[string]Provider
+	categories map[string]string
+	fallback   string // Profile of categories without an explicit one
+}
+
+// NewRegistry creates a client for every configured profile. It returns
+// ErrNotConfigured when there are none.
+func NewRegistry(cfg config.StorageSettings) (*Registry, error) {
+	if len(cfg.Profiles) == 0 {
+		return nil, ErrNotConfigured
+	}
+
+	r := &Registry{
+		profiles:   make(map[string]Provider, len(cfg.Profiles)),
+		categories: cfg.Categories,
+		fallback:   cfg.Profiles[0].Name,
+	}
+	for _, p := range cfg.Profiles {
+		client, err := NewClient(p)
+		if err != nil {
+			return nil, err
+		}
+		r.profiles[p.Name] = client
+	}
+	for category, name := range cfg.Categories {
+		if _, ok := r.profiles[name]; !ok {
+			return nil, fmt.Errorf("storage category %q uses unknown profile %q", category, name)
+		}
+	}
+	return r, nil
+}
+
+// Profile returns the provider of a named profile
+func (r *Registry) Profile(name string) (Provider, bool) {
+	p, ok := r.profiles[name]
+	return p, ok
+}
+
+// For returns the provider serving a storage category
+func (r *Registry) For(category string) Provider {
+	if name, ok := r.categories[category]; ok {
+		return r.profiles[name]
+	}
+	return r.profiles[r.fallback]
+}
@@ -3,67 +3,65 @@ package storage
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"os"
 	"time"
 
+	"maukemana-backend/internal/breaker"
+	"maukemana-backend/internal/config"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
 )
 
-// R2Client wraps the S3 client for Cloudflare R2
+// R2Client wraps the S3 client for one bucket of Cloudflare R2 or another
+// S3-compatible service
 type R2Client struct {
 	client     *s3.Client
 	bucketName string
+	endpoint   string
 	publicURL  string
+	breaker    *breaker.Breaker // Fails fast while the bucket is erroring or timing out
 }
 
-// NewR2Client creates a new R2 storage client
-func NewR2Client() (*R2Client, error) {
-	accountID := os.Getenv("R2_ACCOUNT_ID")
-	accessKeyID := os.Getenv("R2_ACCESS_KEY_ID")
-	secretAccessKey := os.Getenv("R2_SECRET_ACCESS_KEY")
-	bucketName := os.Getenv("R2_BUCKET_NAME")
-	publicURL := os.Getenv("R2_PUBLIC_URL")
-
-	if accountID == "" || accessKeyID == "" || secretAccessKey == "" || bucketName == "" {
-		return nil, fmt.Errorf("missing R2 configuration environment variables")
+// NewClient creates a storage client for a configured profile
+func NewClient(profile config.StorageProfile) (*R2Client, error) {
+	if profile.Endpoint == "" || profile.AccessKeyID == "" || profile.SecretAccessKey == "" || profile.Bucket == "" {
+		return nil, fmt.Errorf("storage profile %q: endpoint, access key, secret key and bucket are required", profile.Name)
 	}
 
-	// R2 endpoint format
-	endpoint := fmt.Sprintf("https://%s.r2.cloudflarestorage.com", accountID)
-
-	// Create S3 client configured for R2
 	client := s3.New(s3.Options{
-		Region:       "auto",
-		BaseEndpoint: aws.String(endpoint),
-		Credentials:  credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, ""),
+		Region:       profile.Region,
+		BaseEndpoint: aws.String(profile.Endpoint),
+		Credentials:  credentials.NewStaticCredentialsProvider(profile.AccessKeyID, profile.SecretAccessKey, ""),
+		UsePathStyle: profile.PathStyle,
 	})
 
+	cfg := config.GetBreakerSettings()
 	return &R2Client{
 		client:     client,
-		bucketName: bucketName,
-		publicURL:  publicURL,
+		bucketName: profile.Bucket,
+		endpoint:   profile.Endpoint,
+		publicURL:  profile.PublicURL,
+		breaker: breaker.New("storage_"+profile.Name, breaker.Settings{
+			MaxFailures: cfg.MaxFailures,
+			OpenFor:     cfg.OpenFor,
+			IsFailure:   isR2Failure,
+		}),
 	}, nil
 }
 
-// GeneratePresignedURL creates a presigned URL for uploading
-func (r *R2Client) GeneratePresignedURL(ctx context.Context, key string, contentType string) (string, error) {
-	presignClient := s3.NewPresignClient(r.client)
-
-	request, err := presignClient.PresignPutObject(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(r.bucketName),
-		Key:         aws.String(key),
-		ContentType: aws.String(contentType),
-	}, s3.WithPresignExpires(15*time.Minute))
-
-	if err != nil {
-		return "", fmt.Errorf("failed to create presigned URL: %w", err)
+// isR2Failure counts errors that point at R2 itself; client errors such as a
+// missing key or an invalid range say nothing about its health
+func isR2Failure(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorFault() == smithy.FaultClient {
+		return false
 	}
-
-	return request.URL, nil
+	return !errors.Is(err, context.Canceled)
 }
 
 // GetPublicURL returns the public URL for an uploaded file
@@ -71,67 +69,159 @@ func (r *R2Client) GetPublicURL(key string) string {
 	if r.publicURL != "" {
 		return fmt.Sprintf("%s/%s", r.publicURL, key)
 	}
-	return fmt.Sprintf("https://%s.r2.cloudflarestorage.com/%s/%s",
-		os.Getenv("R2_ACCOUNT_ID"), r.bucketName, key)
+	return fmt.Sprintf("%s/%s/%s", r.endpoint, r.bucketName, key)
 }
 
 // DeleteObject deletes a file from R2
 func (r *R2Client) DeleteObject(ctx context.Context, key string) error {
-	_, err := r.client.DeleteObject(ctx, &s3.DeleteObjectInput{
-		Bucket: aws.String(r.bucketName),
-		Key:    aws.String(key),
+	return r.breaker.Do(ctx, func() error {
+		_, err := r.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(r.bucketName),
+			Key:    aws.String(key),
+		})
+		return err
 	})
-	return err
 }
 
 // GetObject retrieves an object from R2
 func (r *R2Client) GetObject(ctx context.Context, key string) ([]byte, error) {
-	result, err := r.client.GetObject(ctx, &s3.GetObjectInput{
+	return breaker.Call(ctx, r.breaker, func() ([]byte, error) {
+		result, err := r.client.GetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(r.bucketName),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get object: %w", err)
+		}
+		defer result.Body.Close()
+
+		data, err := io.ReadAll(result.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read object body: %w", err)
+		}
+		return data, nil
+	})
+}
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// ListObjects calls fn for every object whose key starts with prefix,
+// fetching one ListObjectsV2 page at a time. An error from fn stops the listing.
+func (r *R2Client) ListObjects(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	pages := s3.NewListObjectsV2Paginator(r.client, &s3.ListObjectsV2Input{
 		Bucket: aws.String(r.bucketName),
-		Key:    aws.String(key),
+		Prefix: aws.String(prefix),
 	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to get object: %w", err)
+	for pages.HasMorePages() {
+		page, err := breaker.Call(ctx, r.breaker, func() (*s3.ListObjectsV2Output, error) {
+			return pages.NextPage(ctx)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list objects: %w", err)
+		}
+		for _, o := range page.Contents {
+			err := fn(ObjectInfo{
+				Key:          aws.ToString(o.Key),
+				Size:         aws.ToInt64(o.Size),
+				LastModified: aws.ToTime(o.LastModified),
+			})
+			if err != nil {
+				return err
+			}
+		}
 	}
-	defer result.Body.Close()
+	return nil
+}
+
+// ErrObjectNotFound is returned when a key is not stored
+var ErrObjectNotFound = errors.New("object not found")
 
-	data, err := io.ReadAll(result.Body)
+// StatObject returns the size and modification time of an object without
+// reading it
+func (r *R2Client) StatObject(ctx context.Context, key string) (ObjectInfo, error) {
+	result, err := breaker.Call(ctx, r.breaker, func() (*s3.HeadObjectOutput, error) {
+		return r.client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(r.bucketName),
+			Key:    aws.String(key),
+		})
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read object body: %w", err)
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "NotFound" || apiErr.ErrorCode() == "NoSuchKey") {
+			return ObjectInfo{}, ErrObjectNotFound
+		}
+		return ObjectInfo{}, fmt.Errorf("failed to stat object: %w", err)
 	}
+	return ObjectInfo{
+		Key:          key,
+		Size:         aws.ToInt64(result.ContentLength),
+		LastModified: aws.ToTime(result.LastModified),
+	}, nil
+}
 
-	return data, nil
+// ErrInvalidRange is returned when a requested byte range lies outside the object
+var ErrInvalidRange = errors.New("requested range not satisfiable")
+
+// ObjectStream is an object, or a byte range of one, being read from R2
+type ObjectStream struct {
+	Body          io.ReadCloser
+	ContentType   string
+	ContentLength int64  // Length of Body, which is the range length for partial reads
+	ContentRange  string // Content-Range of a partial read, empty for whole objects
 }
 
-// GetObjectStream retrieves an object from R2 as a stream
-func (r *R2Client) GetObjectStream(ctx context.Context, key string) (io.ReadCloser, string, int64, error) {
-	result, err := r.client.GetObject(ctx, &s3.GetObjectInput{
+// GetObjectStream retrieves an object from R2 as a stream. A non-empty
+// byteRange ("bytes=0-1023") reads only that range.
+func (r *R2Client) GetObjectStream(ctx context.Context, key, byteRange string) (*ObjectStream, error) {
+	input := &s3.GetObjectInput{
 		Bucket: aws.String(r.bucketName),
 		Key:    aws.String(key),
+	}
+	if byteRange != "" {
+		input.Range = aws.String(byteRange)
+	}
+	result, err := breaker.Call(ctx, r.breaker, func() (*s3.GetObjectOutput, error) {
+		return r.client.GetObject(ctx, input)
 	})
 	if err != nil {
-		return nil, "", 0, fmt.Errorf("failed to get object: %w", err)
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "InvalidRange" {
+			return nil, ErrInvalidRange
+		}
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchKey" {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get object: %w", err)
 	}
 
-	contentType := ""
+	stream := &ObjectStream{Body: result.Body}
 	if result.ContentType != nil {
-		contentType = *result.ContentType
+		stream.ContentType = *result.ContentType
 	}
-	contentLength := int64(0)
 	if result.ContentLength != nil {
-		contentLength = *result.ContentLength
+		stream.ContentLength = *result.ContentLength
 	}
-
-	return result.Body, contentType, contentLength, nil
+	if result.ContentRange != nil {
+		stream.ContentRange = *result.ContentRange
+	}
+	return stream, nil
 }
 
 // PutObject uploads an object to R2
 func (r *R2Client) PutObject(ctx context.Context, key string, data []byte, contentType string) error {
-	_, err := r.client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(r.bucketName),
-		Key:         aws.String(key),
-		Body:        bytes.NewReader(data),
-		ContentType: aws.String(contentType),
+	err := r.breaker.Do(ctx, func() error {
+		_, err := r.client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:      aws.String(r.bucketName),
+			Key:         aws.String(key),
+			Body:        bytes.NewReader(data),
+			ContentType: aws.String(contentType),
+		})
+		return err
 	})
 	if err != nil {
 		return fmt.Errorf("failed to put object: %w", err)
@@ -143,10 +233,13 @@ func (r *R2Client) PutObject(ctx context.Context, key string, data []byte, conte
 func (r *R2Client) MoveObject(ctx context.Context, srcKey, dstKey string) error {
 	// Copy to new location
 	copySource := fmt.Sprintf("%s/%s", r.bucketName, srcKey)
-	_, err := r.client.CopyObject(ctx, &s3.CopyObjectInput{
-		Bucket:     aws.String(r.bucketName),
-		Key:        aws.String(dstKey),
-		CopySource: aws.String(copySource),
+	err := r.breaker.Do(ctx, func() error {
+		_, err := r.client.CopyObject(ctx, &s3.CopyObjectInput{
+			Bucket:     aws.String(r.bucketName),
+			Key:        aws.String(dstKey),
+			CopySource: aws.String(copySource),
+		})
+		return err
 	})
 	if err != nil {
 		return fmt.Errorf("failed to copy object: %w", err)
@@ -160,7 +253,8 @@ func (r *R2Client) MoveObject(ctx context.Context, srcKey, dstKey string) error
 	return nil
 }
 
-// GeneratePresignedURLWithMaxSize creates a presigned URL with content-length constraints
+// GeneratePresignedURLWithMaxSize creates a presigned URL with content-length constraints.
+// Content-Length is part of the signature, so the PUT must send exactly maxSizeBytes.
 func (r *R2Client) GeneratePresignedURLWithMaxSize(ctx context.Context, key string, contentType string, maxSizeBytes int64) (string, error) {
 	presignClient := s3.NewPresignClient(r.client)
 
@@ -177,3 +271,18 @@ func (r *R2Client) GeneratePresignedURLWithMaxSize(ctx context.Context, key stri
 
 	return request.URL, nil
 }
+
+// PresignGetURL creates a GET URL for a private object that expires after ttl
+func (r *R2Client) PresignGetURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	presignClient := s3.NewPresignClient(r.client)
+
+	request, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(r.bucketName),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to create presigned GET URL: %w", err)
+	}
+
+	return request.URL, nil
+}
@@ -0,0 +1,122 @@
+// Package textmod moderates user generated text before it is stored: profanity
+// is masked, contact details are removed where they do not belong and risky
+// text is flagged for manual review. The original is kept next to the
+// sanitized text so moderators can see what was changed.
+package textmod
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"maukemana-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Fields of user generated text screened by the pipeline
+const (
+	FieldPOIDescription = "poi_description"
+	FieldComment        = "comment"
+	FieldReview         = "review"
+)
+
+// Flags reported by moderators
+const (
+	FlagProfanity = "profanity"
+	FlagEmail     = "email"
+	FlagPhone     = "phone"
+)
+
+// Result is the provider-agnostic outcome of moderating one text
+type Result struct {
+	Text        string   // Sanitized text to store
+	Flags       []string // Distinct reasons the text was changed or flagged
+	NeedsReview bool     // Whether a moderator should look at the text
+}
+
+// Moderator sanitizes the text of one field
+type Moderator interface {
+	Name() string
+	Moderate(ctx context.Context, field, text string) (*Result, error)
+}
+
+// NewModeratorFromEnv builds the moderator selected by TEXT_MODERATION_PROVIDER.
+// The built-in word list is used by default; "none" disables text moderation.
+func NewModeratorFromEnv() (Moderator, error) {
+	switch provider := strings.ToLower(os.Getenv("TEXT_MODERATION_PROVIDER")); provider {
+	case "", "wordlist":
+		var extra []string
+		for _, w := range strings.Split(os.Getenv("TEXT_MODERATION_EXTRA_WORDS"), ",") {
+			if w = strings.TrimSpace(w); w != "" {
+				extra = append(extra, w)
+			}
+		}
+		return NewWordListModerator(extra), nil
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown TEXT_MODERATION_PROVIDER %q", provider)
+	}
+}
+
+// Store keeps the originals of moderated text
+type Store interface {
+	SaveTextModeration(ctx context.Context, m repositories.TextModeration) error
+	ClearTextModeration(ctx context.Context, field string, targetID uuid.UUID) error
+}
+
+// Outcome is the moderation of one text, ready to be stored with its target
+type Outcome struct {
+	Field    string
+	Original string
+	Result
+}
+
+// Changed reports whether the sanitized text differs from the original or was flagged
+func (o *Outcome) Changed() bool {
+	return o.Text != o.Original || len(o.Flags) > 0
+}
+
+// Pipeline runs a moderator and records its outcomes
+type Pipeline struct {
+	moderator Moderator
+	store     Store
+}
+
+// NewPipeline creates a text moderation pipeline. A nil moderator leaves text untouched.
+func NewPipeline(moderator Moderator, store Store) *Pipeline {
+	return &Pipeline{moderator: moderator, store: store}
+}
+
+// Screen moderates the text of a field
+func (p *Pipeline) Screen(ctx context.Context, field, text string) (*Outcome, error) {
+	out := &Outcome{Field: field, Original: text, Result: Result{Text: text}}
+	if p.moderator == nil || strings.TrimSpace(text) == "" {
+		return out, nil
+	}
+	res, err := p.moderator.Moderate(ctx, field, text)
+	if err != nil {
+		return nil, fmt.Errorf("%s moderation: %w", p.moderator.Name(), err)
+	}
+	out.Result = *res
+	return out, nil
+}
+
+// Record stores the original of changed or flagged text stored under targetID.
+// Clean text clears what an earlier version of the target left behind.
+func (p *Pipeline) Record(ctx context.Context, targetID uuid.UUID, userID *uuid.UUID, out *Outcome) error {
+	if !out.Changed() {
+		return p.store.ClearTextModeration(ctx, out.Field, targetID)
+	}
+	return p.store.SaveTextModeration(ctx, repositories.TextModeration{
+		Field:       out.Field,
+		TargetID:    targetID,
+		UserID:      userID,
+		Original:    out.Original,
+		Sanitized:   out.Text,
+		Flags:       out.Flags,
+		NeedsReview: out.NeedsReview,
+	})
+}
@@ -0,0 +1,126 @@
+package textmod
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// riskyProfanityHits is the number of masked words that sends a text to manual review
+const riskyProfanityHits = 3
+
+// defaultProfanity lists common English and Indonesian profanity. Words with an
+// everyday meaning (anjing, babi) are left out so menus and pet policies survive.
+var defaultProfanity = []string{
+	"fuck", "fucking", "fucker", "motherfucker", "shit", "bullshit", "bitch", "asshole",
+	"bastard", "cunt", "dick", "dickhead", "pussy", "whore", "slut", "wanker",
+	"bangsat", "bajingan", "kontol", "memek", "ngentot", "jancok", "jancuk", "goblok",
+	"tolol", "kampret", "keparat", "brengsek", "pepek", "lonte",
+}
+
+var (
+	emailPattern = regexp.MustCompile(`(?i)[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}`)
+	// phoneCandidate matches numbers starting with a country code or a trunk 0
+	// (so prices like 25.000 - 45.000 do not match); candidates are kept when
+	// they hold as many digits as a phone number
+	phoneCandidate = regexp.MustCompile(`\(?(?:\+|\b0)\d[\d\s().-]{6,}\d`)
+)
+
+// Placeholders replacing removed contact details
+const (
+	emailPlaceholder = "[email removed]"
+	phonePlaceholder = "[phone removed]"
+)
+
+// contactFields are the fields where personal contact details must not be
+// published. POI descriptions keep them, but are flagged since POIs have
+// dedicated contact fields.
+var contactFields = map[string]bool{FieldComment: true, FieldReview: true}
+
+// WordListModerator masks words from a fixed list and detects contact details
+type WordListModerator struct {
+	words *regexp.Regexp
+}
+
+// NewWordListModerator creates a moderator for the default list plus extra words
+func NewWordListModerator(extra []string) *WordListModerator {
+	words := append(append([]string{}, defaultProfanity...), extra...)
+	quoted := make([]string, 0, len(words))
+	for _, w := range words {
+		quoted = append(quoted, regexp.QuoteMeta(strings.ToLower(w)))
+	}
+	// Longest first so "motherfucker" wins over "fucker"
+	sort.Slice(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+	return &WordListModerator{words: regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)}
+}
+
+// Name implements Moderator
+func (m *WordListModerator) Name() string { return "wordlist" }
+
+// Moderate implements Moderator
+func (m *WordListModerator) Moderate(_ context.Context, field, text string) (*Result, error) {
+	res := &Result{}
+	flags := make(map[string]bool)
+
+	hits := 0
+	text = m.words.ReplaceAllStringFunc(text, func(w string) string {
+		hits++
+		return mask(w)
+	})
+	if hits > 0 {
+		flags[FlagProfanity] = true
+	}
+	if hits >= riskyProfanityHits {
+		res.NeedsReview = true
+	}
+
+	if emailPattern.MatchString(text) {
+		flags[FlagEmail] = true
+		res.NeedsReview = true
+		if contactFields[field] {
+			text = emailPattern.ReplaceAllString(text, emailPlaceholder)
+		}
+	}
+
+	text = phoneCandidate.ReplaceAllStringFunc(text, func(s string) string {
+		if n := countDigits(s); n < 9 || n > 15 {
+			return s
+		}
+		flags[FlagPhone] = true
+		res.NeedsReview = true
+		if contactFields[field] {
+			return phonePlaceholder
+		}
+		return s
+	})
+
+	res.Text = text
+	for f := range flags {
+		res.Flags = append(res.Flags, f)
+	}
+	sort.Strings(res.Flags)
+	return res, nil
+}
+
+// mask keeps the first letter of a word and stars the rest
+func mask(w string) string {
+	runes := []rune(w)
+	for i := 1; i < len(runes); i++ {
+		if unicode.IsLetter(runes[i]) {
+			runes[i] = '*'
+		}
+	}
+	return string(runes)
+}
+
+func countDigits(s string) int {
+	n := 0
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			n++
+		}
+	}
+	return n
+}
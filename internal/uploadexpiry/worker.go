@@ -0,0 +1,146 @@
+// Package uploadexpiry cleans up presigned uploads that were never finalized
+// and counts them, so abandoned uploads show up in metrics.
+package uploadexpiry
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"maukemana-backend/internal/models"
+	"maukemana-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// batchSize is how many expired uploads are cleaned up per query
+const batchSize = 100
+
+// Store is the upload persistence the worker needs
+type Store interface {
+	ListExpired(ctx context.Context, limit int) ([]models.Upload, error)
+	MarkExpired(ctx context.Context, uploadID uuid.UUID) error
+}
+
+// ObjectStore deletes what abandoned uploads left in storage
+type ObjectStore interface {
+	DeleteObject(ctx context.Context, key string) error
+	AbortMultipartUpload(ctx context.Context, key, uploadID string) error
+}
+
+// Worker periodically expires uploads past their expiry that were never
+// finalized and deletes their objects and multipart parts
+type Worker struct {
+	store    Store
+	objects  ObjectStore
+	interval time.Duration
+	expired  metric.Int64Counter
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWorker creates a worker running every interval
+func NewWorker(store Store, objects ObjectStore, interval time.Duration) *Worker {
+	expired, err := otel.Meter("maukemana-backend/internal/uploadexpiry").Int64Counter("uploads.expired",
+		metric.WithDescription("Presigned uploads never finalized, by reported progress"),
+		metric.WithUnit("{upload}"))
+	if err != nil {
+		slog.Warn("failed to create expired uploads counter", "error", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Worker{store: store, objects: objects, interval: interval, expired: expired, ctx: ctx, cancel: cancel}
+}
+
+// Start begins cleaning up in the background
+func (w *Worker) Start() {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-ticker.C:
+				w.run()
+			}
+		}
+	}()
+}
+
+// Stop waits for the current cleanup to finish and stops the worker
+func (w *Worker) Stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+// run expires batches of uploads until none are left
+func (w *Worker) run() {
+	total := 0
+	for w.ctx.Err() == nil {
+		uploads, err := w.store.ListExpired(w.ctx, batchSize)
+		if err != nil {
+			slog.Error("list expired uploads failed", "error", err)
+			break
+		}
+		for _, u := range uploads {
+			if w.expire(u) {
+				total++
+			}
+		}
+		if len(uploads) < batchSize {
+			break
+		}
+	}
+	if total > 0 {
+		slog.Info("expired abandoned uploads", "count", total)
+	}
+}
+
+// expire marks one upload expired, then deletes what it stored. Marking first
+// means an upload finalized concurrently keeps its object.
+func (w *Worker) expire(u models.Upload) bool {
+	ctx := w.ctx
+	if err := w.store.MarkExpired(ctx, u.UploadID); err != nil {
+		if !errors.Is(err, repositories.ErrUploadNotFound) {
+			slog.Error("expire upload failed", "upload_id", u.UploadID, "error", err)
+		}
+		return false
+	}
+
+	if u.StorageUploadID != nil {
+		if err := w.objects.AbortMultipartUpload(ctx, u.StorageKey, *u.StorageUploadID); err != nil {
+			slog.Warn("abort expired multipart upload failed", "upload_id", u.UploadID, "error", err)
+		}
+	}
+	// A completed multipart upload or a finished PUT leaves an object behind
+	if err := w.objects.DeleteObject(ctx, u.StorageKey); err != nil {
+		slog.Warn("delete expired upload failed", "upload_id", u.UploadID, "key", u.StorageKey, "error", err)
+	}
+
+	if w.expired != nil {
+		w.expired.Add(ctx, 1, metric.WithAttributes(attribute.String("progress", progress(u))))
+	}
+	return true
+}
+
+// progress buckets how far the client got before abandoning an upload
+func progress(u models.Upload) string {
+	switch {
+	case u.BytesUploaded == 0:
+		return "none"
+	case u.BytesUploaded < u.ExpectedSizeBytes:
+		return "partial"
+	default:
+		return "complete"
+	}
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -21,6 +23,12 @@ type Pagination struct {
 	PerPage     int `json:"per_page"`
 	Total       int `json:"total"`
 	TotalPages  int `json:"total_pages"`
+
+	// Set when distances are measured from a location estimated from the client IP
+	ApproximateLocation bool `json:"approximate_location,omitempty"`
+
+	// POI counts by facet and value for the requested ?facets= of a search
+	Facets map[string]map[string]int `json:"facets,omitempty"`
 }
 
 // SendSuccess sends a success response with data (200 OK)
@@ -41,23 +49,41 @@ func SendCreated(c *gin.Context, message string, data interface{}) {
 	})
 }
 
+// SendAccepted sends a response for work that continues in the background (202 Accepted)
+func SendAccepted(c *gin.Context, message string, data interface{}) {
+	c.JSON(http.StatusAccepted, Response{
+		Success: true,
+		Message: message,
+		Data:    data,
+	})
+}
+
 // SendPaginated sends a success response with pagination metadata (200 OK)
 func SendPaginated(c *gin.Context, message string, data interface{}, page, limit, total int) {
+	SendPaginatedMeta(c, message, data, NewPagination(page, limit, total))
+}
+
+// NewPagination builds pagination metadata
+func NewPagination(page, limit, total int) *Pagination {
 	totalPages := 0
 	if limit > 0 {
 		totalPages = int((total + limit - 1) / limit)
 	}
+	return &Pagination{
+		CurrentPage: page,
+		PerPage:     limit,
+		Total:       total,
+		TotalPages:  totalPages,
+	}
+}
 
+// SendPaginatedMeta sends a paginated response with prepared metadata
+func SendPaginatedMeta(c *gin.Context, message string, data interface{}, meta *Pagination) {
 	c.JSON(http.StatusOK, Response{
 		Success: true,
 		Message: message,
 		Data:    data,
-		Meta: &Pagination{
-			CurrentPage: page,
-			PerPage:     limit,
-			Total:       total,
-			TotalPages:  totalPages,
-		},
+		Meta:    meta,
 	})
 }
 
@@ -81,7 +107,12 @@ func SendValidationError(c *gin.Context, err error) {
 	SendError(c, http.StatusBadRequest, "Validation failed", err)
 }
 
-// SendInternalError sends a 500 Internal Server Error
+// SendInternalError sends a 500 Internal Server Error, or a 504 Gateway
+// Timeout when the failure came from the request running out of time
 func SendInternalError(c *gin.Context, err error) {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(c.Request.Context().Err(), context.DeadlineExceeded) {
+		SendError(c, http.StatusGatewayTimeout, "request timed out", err)
+		return
+	}
 	SendError(c, http.StatusInternalServerError, "Internal server error", err)
 }
@@ -0,0 +1,304 @@
+// Package validation runs the automated checks admins review before approving
+// a POI: service area, nearby duplicates, website reachability, phone format
+// and photo coverage.
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"maukemana-backend/internal/config"
+	"maukemana-backend/internal/imaging"
+	"maukemana-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// websiteTimeout bounds the reachability check of a POI website
+	websiteTimeout = 5 * time.Second
+	// duplicateSimilarity is the name trigram similarity reported as a possible duplicate
+	duplicateSimilarity = 0.4
+	// likelyDuplicateSimilarity fails the check when the similar POI is already approved
+	likelyDuplicateSimilarity = 0.8
+	// minPhotos is the photo count below which the image check warns
+	minPhotos = 3
+	// minImageSide is the shortest side, in pixels, of an image considered good quality
+	minImageSide = 800
+)
+
+// Status is the outcome of one check
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// severity orders statuses so a report takes its worst check
+var severity = map[Status]int{StatusPass: 0, StatusWarn: 1, StatusFail: 2}
+
+// Check is the result of one rule
+type Check struct {
+	Rule    string      `json:"rule"`
+	Status  Status      `json:"status"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// Report is the full pre-approval validation of a POI
+type Report struct {
+	PoiID     uuid.UUID `json:"poi_id"`
+	Status    Status    `json:"status"` // Worst status of all checks
+	Checks    []Check   `json:"checks"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Store loads what the checks need
+type Store interface {
+	GetValidationFacts(ctx context.Context, poiID uuid.UUID) (*repositories.ValidationFacts, error)
+	FindSimilarNearby(ctx context.Context, poiID uuid.UUID, radiusMeters, minSimilarity float64) ([]repositories.SimilarPOI, error)
+}
+
+// Validator runs the pre-approval checks
+type Validator struct {
+	store Store
+	cfg   config.ValidationSettings
+	http  *http.Client
+}
+
+// NewValidator creates a validator. Website checks only connect to public
+// addresses, since the URL comes from the submitter.
+func NewValidator(store Store, cfg config.ValidationSettings) *Validator {
+	dialer := &net.Dialer{Timeout: websiteTimeout, Control: publicAddressOnly}
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   websiteTimeout,
+		ResponseHeaderTimeout: websiteTimeout,
+	}
+	return &Validator{
+		store: store,
+		cfg:   cfg,
+		http:  &http.Client{Timeout: websiteTimeout, Transport: transport},
+	}
+}
+
+// Validate runs every check for a POI. It returns sql.ErrNoRows when the POI does not exist.
+func (v *Validator) Validate(ctx context.Context, poiID uuid.UUID) (*Report, error) {
+	facts, err := v.store.GetValidationFacts(ctx, poiID)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates, err := v.checkDuplicates(ctx, facts)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		PoiID: poiID,
+		Checks: []Check{
+			v.checkServiceArea(facts),
+			duplicates,
+			v.checkWebsite(ctx, facts.Website),
+			checkPhone(facts.Phone),
+			checkImages(facts.Images),
+		},
+		CheckedAt: time.Now().UTC(),
+	}
+	report.Status = StatusPass
+	for _, check := range report.Checks {
+		if severity[check.Status] > severity[report.Status] {
+			report.Status = check.Status
+		}
+	}
+	return report, nil
+}
+
+func (v *Validator) checkServiceArea(facts *repositories.ValidationFacts) Check {
+	check := Check{Rule: "service_area"}
+	if facts.Lat == nil || facts.Lng == nil || (*facts.Lat == 0 && *facts.Lng == 0) {
+		check.Status, check.Message = StatusFail, "coordinates are not set"
+		return check
+	}
+
+	check.Details = map[string]interface{}{"latitude": *facts.Lat, "longitude": *facts.Lng, "area": facts.ServiceArea}
+	switch {
+	case facts.ServiceArea != nil:
+		check.Status, check.Message = StatusPass, fmt.Sprintf("coordinates are inside service area %q", *facts.ServiceArea)
+	case !facts.HasServiceAreas:
+		check.Status, check.Message = StatusWarn, "no active service areas are configured"
+	default:
+		check.Status, check.Message = StatusFail, "coordinates are outside every active service area"
+	}
+	return check
+}
+
+func (v *Validator) checkDuplicates(ctx context.Context, facts *repositories.ValidationFacts) (Check, error) {
+	check := Check{Rule: "duplicate_names"}
+	if facts.Lat == nil || facts.Lng == nil {
+		check.Status, check.Message = StatusWarn, "cannot look for nearby duplicates without coordinates"
+		return check, nil
+	}
+
+	similar, err := v.store.FindSimilarNearby(ctx, facts.PoiID, v.cfg.DuplicateRadius, duplicateSimilarity)
+	if err != nil {
+		return check, err
+	}
+	if len(similar) == 0 {
+		check.Status = StatusPass
+		check.Message = fmt.Sprintf("no similarly named POIs within %.0fm", v.cfg.DuplicateRadius)
+		return check, nil
+	}
+
+	check.Details = similar
+	check.Status = StatusWarn
+	check.Message = fmt.Sprintf("%d similarly named POIs within %.0fm", len(similar), v.cfg.DuplicateRadius)
+	for _, s := range similar {
+		if s.Status == "approved" && s.Similarity >= likelyDuplicateSimilarity {
+			check.Status = StatusFail
+			check.Message = fmt.Sprintf("likely duplicate of approved POI %q", s.Name)
+			break
+		}
+	}
+	return check, nil
+}
+
+func (v *Validator) checkWebsite(ctx context.Context, website *string) Check {
+	check := Check{Rule: "website"}
+	if website == nil || strings.TrimSpace(*website) == "" {
+		check.Status, check.Message = StatusPass, "no website provided"
+		return check
+	}
+
+	raw := strings.TrimSpace(*website)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		check.Status, check.Message = StatusFail, "website is not a valid http(s) URL"
+		return check
+	}
+	check.Details = map[string]string{"url": u.String()}
+
+	status, err := v.fetchStatus(ctx, http.MethodHead, u.String())
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented) {
+		status, err = v.fetchStatus(ctx, http.MethodGet, u.String())
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.As(err, &netErr) && netErr.Timeout():
+		check.Status, check.Message = StatusWarn, "website did not respond in time"
+	case err != nil:
+		check.Status, check.Message = StatusFail, "website is unreachable"
+	case status >= 400:
+		check.Status, check.Message = StatusFail, fmt.Sprintf("website returned HTTP %d", status)
+	default:
+		check.Status, check.Message = StatusPass, fmt.Sprintf("website responded with HTTP %d", status)
+	}
+	return check
+}
+
+func (v *Validator) fetchStatus(ctx context.Context, method, target string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "maukemana-validator/1.0")
+	resp, err := v.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// publicAddressOnly refuses connections to loopback, private and link-local addresses
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast() {
+		return fmt.Errorf("refusing to connect to non-public address %s", host)
+	}
+	return nil
+}
+
+var (
+	// phoneSeparators are stripped before matching phone numbers
+	phoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+	// indonesianPhone matches local (0…) and +62/62 prefixed mobile and landline numbers
+	indonesianPhone = regexp.MustCompile(`^(\+62|62|0)[1-9][0-9]{6,11}$`)
+	// internationalPhone matches E.164 numbers of other countries
+	internationalPhone = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+)
+
+func checkPhone(phone *string) Check {
+	check := Check{Rule: "phone_format"}
+	if phone == nil || strings.TrimSpace(*phone) == "" {
+		check.Status, check.Message = StatusPass, "no phone number provided"
+		return check
+	}
+
+	number := phoneSeparators.Replace(strings.TrimSpace(*phone))
+	check.Details = map[string]string{"phone": *phone}
+	switch {
+	case indonesianPhone.MatchString(number):
+		check.Status, check.Message = StatusPass, "phone number is a valid Indonesian number"
+	case internationalPhone.MatchString(number):
+		check.Status, check.Message = StatusWarn, "phone number is not an Indonesian number"
+	default:
+		check.Status, check.Message = StatusFail, "phone number format is invalid"
+	}
+	return check
+}
+
+func checkImages(images []repositories.ValidationImage) Check {
+	check := Check{Rule: "images"}
+	if len(images) == 0 {
+		check.Status, check.Message = StatusFail, "POI has no photos"
+		return check
+	}
+
+	var lowRes, blocked []string
+	for _, img := range images {
+		if img.Moderation != nil && imaging.ModerationStatus(*img.Moderation).Blocked() {
+			blocked = append(blocked, img.URL)
+		}
+		if img.Width != nil && img.Height != nil && min(*img.Width, *img.Height) < minImageSide {
+			lowRes = append(lowRes, img.URL)
+		}
+	}
+	check.Details = map[string]interface{}{
+		"count":          len(images),
+		"low_resolution": lowRes,
+		"blocked":        blocked,
+	}
+
+	switch {
+	case len(blocked) > 0:
+		check.Status, check.Message = StatusFail, fmt.Sprintf("%d photos were blocked by moderation", len(blocked))
+	case len(images) < minPhotos:
+		check.Status, check.Message = StatusWarn, fmt.Sprintf("only %d photos, at least %d recommended", len(images), minPhotos)
+	case len(lowRes) > 0:
+		check.Status, check.Message = StatusWarn, fmt.Sprintf("%d photos are smaller than %dpx", len(lowRes), minImageSide)
+	default:
+		check.Status, check.Message = StatusPass, fmt.Sprintf("%d photos of good quality", len(images))
+	}
+	return check
+}
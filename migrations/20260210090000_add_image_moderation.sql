@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE image_assets
+    ADD COLUMN moderation_status VARCHAR(20) NOT NULL DEFAULT 'unchecked'
+        CHECK (moderation_status IN ('unchecked', 'approved', 'flagged', 'quarantined')),
+    ADD COLUMN moderation_score DOUBLE PRECISION,
+    ADD COLUMN moderation_reason TEXT,
+    ADD COLUMN moderated_at TIMESTAMPTZ;
+
+CREATE INDEX idx_image_assets_moderation ON image_assets(moderation_status)
+    WHERE moderation_status IN ('flagged', 'quarantined');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_image_assets_moderation;
+ALTER TABLE image_assets
+    DROP COLUMN IF EXISTS moderated_at,
+    DROP COLUMN IF EXISTS moderation_reason,
+    DROP COLUMN IF EXISTS moderation_score,
+    DROP COLUMN IF EXISTS moderation_status;
+-- +goose StatementEnd
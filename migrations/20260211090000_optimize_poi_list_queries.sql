@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Gallery preview: matches the ORDER BY of the LATERAL photo join so the
+-- LIMIT can be satisfied by an index scan per POI
+CREATE INDEX IF NOT EXISTS idx_photos_gallery_order
+    ON photos(poi_id, is_pinned DESC, is_hero DESC, score DESC);
+
+-- Review stats: covering index so AVG/COUNT per POI is an index-only scan
+CREATE INDEX IF NOT EXISTS idx_reviews_poi_rating
+    ON reviews(poi_id) INCLUDE (rating);
+
+-- Default listing: status filter + newest first
+CREATE INDEX IF NOT EXISTS idx_poi_status_created
+    ON points_of_interest(status, created_at DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_poi_status_created;
+DROP INDEX IF EXISTS idx_reviews_poi_rating;
+DROP INDEX IF EXISTS idx_photos_gallery_order;
+-- +goose StatementEnd
@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE points_of_interest
+ADD COLUMN IF NOT EXISTS rating_avg DOUBLE PRECISION NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS reviews_count INTEGER NOT NULL DEFAULT 0;
+
+-- Backfill from existing reviews
+UPDATE points_of_interest p
+SET rating_avg = s.rating_avg,
+    reviews_count = s.reviews_count
+FROM (
+    SELECT poi_id, COALESCE(AVG(rating)::float8, 0) AS rating_avg, COUNT(*)::int AS reviews_count
+    FROM reviews
+    GROUP BY poi_id
+) s
+WHERE p.poi_id = s.poi_id;
+
+-- Recomputes the cached aggregates for the POI(s) touched by a review change.
+-- Recomputing (rather than incrementing) keeps the columns self-healing.
+CREATE OR REPLACE FUNCTION refresh_poi_rating_stats() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE points_of_interest
+        SET rating_avg = COALESCE((SELECT AVG(rating)::float8 FROM reviews WHERE poi_id = OLD.poi_id), 0),
+            reviews_count = (SELECT COUNT(*)::int FROM reviews WHERE poi_id = OLD.poi_id)
+        WHERE poi_id = OLD.poi_id;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') AND (TG_OP = 'INSERT' OR NEW.poi_id IS DISTINCT FROM OLD.poi_id OR NEW.rating IS DISTINCT FROM OLD.rating) THEN
+        UPDATE points_of_interest
+        SET rating_avg = COALESCE((SELECT AVG(rating)::float8 FROM reviews WHERE poi_id = NEW.poi_id), 0),
+            reviews_count = (SELECT COUNT(*)::int FROM reviews WHERE poi_id = NEW.poi_id)
+        WHERE poi_id = NEW.poi_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_refresh_poi_rating_stats
+AFTER INSERT OR UPDATE OF rating, poi_id OR DELETE
+ON reviews
+FOR EACH ROW
+EXECUTE FUNCTION refresh_poi_rating_stats();
+
+-- Supports sort_by=top_rated
+CREATE INDEX IF NOT EXISTS idx_poi_rating ON points_of_interest(rating_avg DESC, reviews_count DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_poi_rating;
+DROP TRIGGER IF EXISTS trg_refresh_poi_rating_stats ON reviews;
+DROP FUNCTION IF EXISTS refresh_poi_rating_stats;
+ALTER TABLE points_of_interest
+DROP COLUMN IF EXISTS reviews_count,
+DROP COLUMN IF EXISTS rating_avg;
+-- +goose StatementEnd
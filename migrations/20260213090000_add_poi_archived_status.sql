@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Allow the archived state used by the POI workflow state machine
+ALTER TABLE points_of_interest DROP CONSTRAINT IF EXISTS points_of_interest_status_check;
+ALTER TABLE points_of_interest
+ADD CONSTRAINT points_of_interest_status_check
+CHECK (status IN ('draft', 'pending', 'approved', 'rejected', 'archived'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE points_of_interest SET status = 'approved' WHERE status = 'archived';
+ALTER TABLE points_of_interest DROP CONSTRAINT IF EXISTS points_of_interest_status_check;
+ALTER TABLE points_of_interest
+ADD CONSTRAINT points_of_interest_status_check
+CHECK (status IN ('draft', 'pending', 'approved', 'rejected'));
+-- +goose StatementEnd
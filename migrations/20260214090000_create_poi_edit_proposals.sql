@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS poi_edit_proposals (
+    proposal_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    poi_id UUID NOT NULL REFERENCES points_of_interest(poi_id) ON DELETE CASCADE,
+    proposer_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    changes JSONB NOT NULL,           -- field -> proposed value
+    original JSONB NOT NULL,          -- field -> value at proposal time
+    note TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'accepted', 'rejected', 'withdrawn')),
+    reviewer_id UUID REFERENCES users(user_id),
+    review_note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_edit_proposals_poi_status ON poi_edit_proposals(poi_id, status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_edit_proposals_proposer ON poi_edit_proposals(proposer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_edit_proposals_pending ON poi_edit_proposals(created_at) WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS poi_edit_proposals;
+-- +goose StatementEnd
@@ -0,0 +1,72 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Structured, time-bounded specials/events per POI. Replaces the free-text
+-- points_of_interest.specials array, which is kept for older clients.
+CREATE TABLE IF NOT EXISTS poi_specials (
+    special_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    poi_id UUID NOT NULL REFERENCES points_of_interest(poi_id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    starts_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ends_at TIMESTAMPTZ,                       -- NULL = open-ended
+    recurrence VARCHAR(10) NOT NULL DEFAULT 'none'
+        CHECK (recurrence IN ('none', 'daily', 'weekly', 'monthly')),
+    recurrence_days SMALLINT[],                -- weekly: 0 (Sun) .. 6 (Sat); monthly: 1 .. 31
+    daily_start_time TIME,                     -- Local time window for recurring specials
+    daily_end_time TIME,                       -- May be earlier than start for overnight windows
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Jakarta',
+    created_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_poi_specials_poi ON poi_specials(poi_id, starts_at);
+CREATE INDEX IF NOT EXISTS idx_poi_specials_live ON poi_specials(poi_id) WHERE ends_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_poi_specials_ends_at ON poi_specials(ends_at);
+
+-- poi_special_is_active reports whether a special applies at the given instant.
+-- One-off specials are active for their whole [starts_at, ends_at) range; recurring
+-- specials additionally need the local day and time to match.
+CREATE OR REPLACE FUNCTION poi_special_is_active(s poi_specials, at_time TIMESTAMPTZ)
+RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+    SELECT s.starts_at <= at_time
+       AND (s.ends_at IS NULL OR s.ends_at > at_time)
+       AND (
+           s.recurrence = 'none'
+           OR (
+               CASE s.recurrence
+                   WHEN 'daily' THEN TRUE
+                   WHEN 'weekly' THEN EXTRACT(DOW FROM at_time AT TIME ZONE s.timezone)::int = ANY(s.recurrence_days)
+                   WHEN 'monthly' THEN EXTRACT(DAY FROM at_time AT TIME ZONE s.timezone)::int = ANY(s.recurrence_days)
+                   ELSE FALSE
+               END
+               AND (
+                   s.daily_start_time IS NULL OR s.daily_end_time IS NULL
+                   OR CASE
+                       WHEN s.daily_start_time <= s.daily_end_time THEN
+                           (at_time AT TIME ZONE s.timezone)::time >= s.daily_start_time
+                           AND (at_time AT TIME ZONE s.timezone)::time < s.daily_end_time
+                       ELSE
+                           (at_time AT TIME ZONE s.timezone)::time >= s.daily_start_time
+                           OR (at_time AT TIME ZONE s.timezone)::time < s.daily_end_time
+                   END
+               )
+           )
+       )
+$$;
+
+-- Carry over existing free-text specials as open-ended one-off entries
+INSERT INTO poi_specials (poi_id, title, starts_at, created_by)
+SELECT p.poi_id, LEFT(sp.title, 200), p.created_at, p.created_by
+FROM points_of_interest p
+CROSS JOIN LATERAL unnest(p.specials) AS sp(title)
+WHERE p.specials IS NOT NULL AND btrim(sp.title) <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS poi_special_is_active(poi_specials, TIMESTAMPTZ);
+DROP TABLE IF EXISTS poi_specials;
+-- +goose StatementEnd
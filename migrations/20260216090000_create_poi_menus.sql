@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Structured menus. Replaces the flat points_of_interest.featured_menu_items
+-- array, which is kept for older clients.
+CREATE TABLE IF NOT EXISTS poi_menu_sections (
+    section_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    poi_id UUID NOT NULL REFERENCES points_of_interest(poi_id) ON DELETE CASCADE,
+    name VARCHAR(120) NOT NULL,
+    description TEXT,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS poi_menu_items (
+    item_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    section_id UUID NOT NULL REFERENCES poi_menu_sections(section_id) ON DELETE CASCADE,
+    poi_id UUID NOT NULL REFERENCES points_of_interest(poi_id) ON DELETE CASCADE,
+    name VARCHAR(200) NOT NULL,
+    description TEXT,
+    price NUMERIC(12, 2) CHECK (price IS NULL OR price >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    dietary_tags TEXT[] NOT NULL DEFAULT '{}',
+    photo_asset_id UUID REFERENCES image_assets(id) ON DELETE SET NULL,
+    is_available BOOLEAN NOT NULL DEFAULT TRUE,
+    is_featured BOOLEAN NOT NULL DEFAULT FALSE,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_menu_sections_poi ON poi_menu_sections(poi_id, position);
+CREATE INDEX IF NOT EXISTS idx_menu_items_section ON poi_menu_items(section_id, position);
+CREATE INDEX IF NOT EXISTS idx_menu_items_poi ON poi_menu_items(poi_id);
+CREATE INDEX IF NOT EXISTS idx_menu_items_dietary ON poi_menu_items USING GIN(dietary_tags);
+
+-- Carry over featured items into a "Featured" section
+WITH sections AS (
+    INSERT INTO poi_menu_sections (poi_id, name)
+    SELECT poi_id, 'Featured'
+    FROM points_of_interest
+    WHERE cardinality(featured_menu_items) > 0
+    RETURNING section_id, poi_id
+)
+INSERT INTO poi_menu_items (section_id, poi_id, name, is_featured, position)
+SELECT s.section_id, s.poi_id, LEFT(fi.name, 200), TRUE, fi.ord - 1
+FROM sections s
+JOIN points_of_interest p ON p.poi_id = s.poi_id
+CROSS JOIN LATERAL unnest(p.featured_menu_items) WITH ORDINALITY AS fi(name, ord)
+WHERE btrim(fi.name) <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS poi_menu_items;
+DROP TABLE IF EXISTS poi_menu_sections;
+-- +goose StatementEnd
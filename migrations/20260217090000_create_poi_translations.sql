@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Translated POI content. The columns on points_of_interest hold the
+-- default-locale content; rows here override them per locale.
+CREATE TABLE IF NOT EXISTS poi_translations (
+    poi_id UUID NOT NULL REFERENCES points_of_interest(poi_id) ON DELETE CASCADE,
+    locale VARCHAR(10) NOT NULL,
+    name VARCHAR(255),
+    description TEXT,
+    updated_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (poi_id, locale)
+);
+
+CREATE INDEX IF NOT EXISTS idx_poi_translations_locale ON poi_translations(locale);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS poi_translations;
+-- +goose StatementEnd
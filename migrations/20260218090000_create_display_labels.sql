@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Localized display labels for categories and vocabularies. Entities without a
+-- label for the requested locale fall back to the default locale, then to their key.
+CREATE TABLE IF NOT EXISTS display_labels (
+    entity_type VARCHAR(20) NOT NULL CHECK (entity_type IN ('category', 'vocabulary')),
+    entity_id UUID NOT NULL,
+    locale VARCHAR(10) NOT NULL,
+    label VARCHAR(255) NOT NULL,
+    updated_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (entity_type, entity_id, locale)
+);
+
+-- Labels disappear with the entity they describe
+CREATE OR REPLACE FUNCTION delete_display_labels()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'categories' THEN
+        DELETE FROM display_labels WHERE entity_type = 'category' AND entity_id = OLD.category_id;
+    ELSE
+        DELETE FROM display_labels WHERE entity_type = 'vocabulary' AND entity_id = OLD.vocab_id;
+    END IF;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_categories_delete_labels
+AFTER DELETE ON categories
+FOR EACH ROW EXECUTE FUNCTION delete_display_labels();
+
+CREATE TRIGGER trg_vocabularies_delete_labels
+AFTER DELETE ON vocabularies
+FOR EACH ROW EXECUTE FUNCTION delete_display_labels();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS trg_vocabularies_delete_labels ON vocabularies;
+DROP TRIGGER IF EXISTS trg_categories_delete_labels ON categories;
+DROP FUNCTION IF EXISTS delete_display_labels();
+DROP TABLE IF EXISTS display_labels;
+-- +goose StatementEnd
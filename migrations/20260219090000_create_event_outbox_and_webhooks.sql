@@ -0,0 +1,107 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Transactional outbox: events are written in the same transaction as the POI change
+CREATE TABLE outbox_events (
+    event_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_type VARCHAR(64) NOT NULL,          -- poi.approved, poi.updated, poi.deleted
+    aggregate_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_outbox_events_unpublished ON outbox_events(created_at) WHERE published_at IS NULL;
+CREATE INDEX idx_outbox_events_aggregate ON outbox_events(aggregate_id, created_at DESC);
+
+CREATE TABLE webhook_subscriptions (
+    subscription_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,                     -- HMAC-SHA256 signing key
+    event_types TEXT[] NOT NULL DEFAULT '{}', -- Empty = every event
+    description TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE webhook_deliveries (
+    delivery_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(subscription_id) ON DELETE CASCADE,
+    event_id UUID NOT NULL REFERENCES outbox_events(event_id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_status_code INTEGER,
+    last_error TEXT,
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, created_at DESC);
+
+-- Capture POI lifecycle events. Running as a trigger keeps every write path
+-- (REST, section edits, accepted proposals, batch moderation) covered.
+CREATE OR REPLACE FUNCTION capture_poi_outbox_event() RETURNS TRIGGER AS $$
+DECLARE
+    evt VARCHAR(64);
+    row_data points_of_interest;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        -- Only POIs that were public matter to consumers
+        IF OLD.status <> 'approved' THEN
+            RETURN OLD;
+        END IF;
+        evt := 'poi.deleted';
+        row_data := OLD;
+    ELSIF NEW.status = 'approved' AND OLD.status IS DISTINCT FROM 'approved' THEN
+        evt := 'poi.approved';
+        row_data := NEW;
+    ELSIF NEW.status = 'approved' THEN
+        IF NEW IS NOT DISTINCT FROM OLD THEN
+            RETURN NEW;
+        END IF;
+        evt := 'poi.updated';
+        row_data := NEW;
+    ELSIF OLD.status = 'approved' THEN
+        -- Archived or otherwise withdrawn from the public listing
+        evt := 'poi.deleted';
+        row_data := NEW;
+    ELSE
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO outbox_events (event_type, aggregate_id, payload)
+    VALUES (evt, row_data.poi_id, jsonb_build_object(
+        'poi_id', row_data.poi_id,
+        'name', row_data.name,
+        'status', row_data.status,
+        'updated_at', row_data.updated_at
+    ));
+
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_poi_outbox
+AFTER INSERT OR UPDATE OR DELETE ON points_of_interest
+FOR EACH ROW
+EXECUTE FUNCTION capture_poi_outbox_event();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS trg_poi_outbox ON points_of_interest;
+DROP FUNCTION IF EXISTS capture_poi_outbox_event;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+DROP TABLE IF EXISTS outbox_events;
+-- +goose StatementEnd
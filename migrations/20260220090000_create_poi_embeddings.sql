@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE EXTENSION IF NOT EXISTS vector;
+
+-- One embedding per approved POI, built from its name, categories, cuisine,
+-- vibes and description. content_hash lets the sync skip unchanged POIs.
+CREATE TABLE poi_embeddings (
+    poi_id UUID PRIMARY KEY REFERENCES points_of_interest(poi_id) ON DELETE CASCADE,
+    model VARCHAR(100) NOT NULL,
+    content_hash CHAR(64) NOT NULL,           -- SHA-256 of the embedded text
+    embedding vector(1536) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_poi_embeddings_hnsw ON poi_embeddings USING hnsw (embedding vector_cosine_ops);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS poi_embeddings;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Read-only share links: anyone holding the token can view the itinerary
+ALTER TABLE itineraries
+    ADD COLUMN share_token VARCHAR(64) UNIQUE,
+    ADD COLUMN share_created_at TIMESTAMPTZ;
+
+-- Invited collaborators. Invitations stay pending until the invitee accepts.
+CREATE TABLE itinerary_collaborators (
+    itinerary_id UUID NOT NULL REFERENCES itineraries(itinerary_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    role VARCHAR(8) NOT NULL CHECK (role IN ('view', 'edit')),
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted')),
+    invited_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    accepted_at TIMESTAMPTZ,
+    PRIMARY KEY (itinerary_id, user_id)
+);
+
+CREATE INDEX idx_itinerary_collaborators_user ON itinerary_collaborators(user_id);
+
+-- Item-level optimistic concurrency: writers send the version they read and
+-- lose with 409 if someone else saved in between
+ALTER TABLE itinerary_items
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN updated_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE itinerary_items
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS updated_by,
+    DROP COLUMN IF EXISTS version;
+DROP TABLE IF EXISTS itinerary_collaborators;
+ALTER TABLE itineraries
+    DROP COLUMN IF EXISTS share_created_at,
+    DROP COLUMN IF EXISTS share_token;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Roles are named bundles of permissions. users.role references roles.name;
+-- a role missing here grants no permissions.
+CREATE TABLE roles (
+    name VARCHAR(50) PRIMARY KEY,
+    description TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE permissions (
+    name VARCHAR(64) PRIMARY KEY,
+    description TEXT
+);
+
+CREATE TABLE role_permissions (
+    role VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission VARCHAR(64) NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description) VALUES
+    ('user', 'Contributor; can submit and edit their own POIs'),
+    ('moderator', 'Reviews submissions and edit proposals'),
+    ('admin', 'Full access');
+
+INSERT INTO permissions (name, description) VALUES
+    ('poi:approve', 'Approve, reject and archive POIs'),
+    ('poi:merge', 'Edit POIs owned by others and merge edit proposals'),
+    ('user:manage', 'Assign roles to users'),
+    ('imaging:admin', 'Reprocess any uploaded image'),
+    ('taxonomy:manage', 'Manage category and vocabulary labels'),
+    ('webhook:manage', 'Manage webhook subscriptions');
+
+INSERT INTO role_permissions (role, permission)
+SELECT 'admin', name FROM permissions;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('moderator', 'poi:approve'),
+    ('moderator', 'poi:merge');
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Last applied autosave per wizard section. Autosaves carry the client's save
+-- time; an older save arriving late for a section is skipped instead of
+-- overwriting newer input.
+CREATE TABLE poi_draft_sections (
+    poi_id UUID NOT NULL REFERENCES points_of_interest(poi_id) ON DELETE CASCADE,
+    section VARCHAR(32) NOT NULL,
+    client_saved_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (poi_id, section)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS poi_draft_sections;
+-- +goose StatementEnd
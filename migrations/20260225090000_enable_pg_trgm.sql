@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Trigram similarity for spotting near-duplicate POI names before approval
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP EXTENSION IF EXISTS pg_trgm;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Cities and regions the product operates in. New POIs must fall inside an
+-- active area; while no area is active, geofencing is off.
+CREATE TABLE service_areas (
+    area_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    slug VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    boundary GEOMETRY(MULTIPOLYGON, 4326) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_service_areas_boundary ON service_areas USING GIST (boundary);
+
+INSERT INTO permissions (name, description) VALUES
+    ('area:manage', 'Manage service areas');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'area:manage');
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'area:manage';
+DROP TABLE IF EXISTS service_areas;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Where a POI came from. NULL for user submissions; imported rows keep the
+-- upstream ID so reruns of an import skip them, and the attribution the
+-- source's license requires.
+ALTER TABLE points_of_interest
+    ADD COLUMN IF NOT EXISTS source VARCHAR(32),
+    ADD COLUMN IF NOT EXISTS source_id VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS source_attribution TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_poi_source ON points_of_interest(source, source_id) WHERE source IS NOT NULL;
+
+INSERT INTO categories (name_key, icon)
+SELECT 'category.coworking', '💻'
+WHERE NOT EXISTS (SELECT 1 FROM categories WHERE name_key = 'category.coworking');
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_poi_source;
+ALTER TABLE points_of_interest
+    DROP COLUMN IF EXISTS source,
+    DROP COLUMN IF EXISTS source_id,
+    DROP COLUMN IF EXISTS source_attribution;
+-- +goose StatementEnd
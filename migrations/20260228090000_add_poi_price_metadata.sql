@@ -0,0 +1,87 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Currency-aware price metadata: average spend per person in price_currency.
+-- The legacy 1-4 price_range stays in responses and is kept in sync for IDR
+-- prices by trg_sync_poi_price_range.
+ALTER TABLE points_of_interest
+    ADD COLUMN IF NOT EXISTS price_currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    ADD COLUMN IF NOT EXISTS avg_spend_min NUMERIC(12, 2),
+    ADD COLUMN IF NOT EXISTS avg_spend_max NUMERIC(12, 2);
+
+ALTER TABLE points_of_interest
+    ADD CONSTRAINT chk_poi_price_currency CHECK (price_currency ~ '^[A-Z]{3}$'),
+    ADD CONSTRAINT chk_poi_avg_spend CHECK (
+        (avg_spend_min IS NULL OR avg_spend_min >= 0)
+        AND (avg_spend_max IS NULL OR avg_spend_max >= 0)
+        AND (avg_spend_min IS NULL OR avg_spend_max IS NULL OR avg_spend_min <= avg_spend_max)
+    );
+
+-- IDR spend bands behind the legacy price_range: <50k, <100k, <200k, 200k+
+CREATE OR REPLACE FUNCTION price_range_for_idr(amount NUMERIC) RETURNS INTEGER AS $$
+    SELECT CASE
+        WHEN amount < 50000 THEN 1
+        WHEN amount < 100000 THEN 2
+        WHEN amount < 200000 THEN 3
+        ELSE 4
+    END
+$$ LANGUAGE sql IMMUTABLE;
+
+UPDATE points_of_interest SET
+    avg_spend_min = (ARRAY[0, 50000, 100000, 200000]::numeric[])[price_range],
+    avg_spend_max = (ARRAY[50000, 100000, 200000, NULL]::numeric[])[price_range]
+WHERE price_range BETWEEN 1 AND 4;
+
+-- Writes of the spend derive price_range; writes of only price_range (legacy
+-- clients) derive the spend band
+CREATE OR REPLACE FUNCTION sync_poi_price_range() RETURNS TRIGGER AS $$
+DECLARE
+    spend_changed BOOLEAN;
+    range_changed BOOLEAN;
+BEGIN
+    IF NEW.price_currency <> 'IDR' THEN
+        RETURN NEW;
+    END IF;
+
+    IF TG_OP = 'INSERT' THEN
+        spend_changed := TRUE;
+        range_changed := TRUE;
+    ELSE
+        spend_changed := NEW.avg_spend_min IS DISTINCT FROM OLD.avg_spend_min
+            OR NEW.avg_spend_max IS DISTINCT FROM OLD.avg_spend_max
+            OR NEW.price_currency IS DISTINCT FROM OLD.price_currency;
+        range_changed := NEW.price_range IS DISTINCT FROM OLD.price_range;
+    END IF;
+
+    IF spend_changed AND COALESCE(NEW.avg_spend_min, NEW.avg_spend_max) IS NOT NULL THEN
+        NEW.price_range := price_range_for_idr(COALESCE(
+            (NEW.avg_spend_min + NEW.avg_spend_max) / 2, NEW.avg_spend_min, NEW.avg_spend_max));
+    ELSIF range_changed AND NEW.price_range BETWEEN 1 AND 4 THEN
+        NEW.avg_spend_min := (ARRAY[0, 50000, 100000, 200000]::numeric[])[NEW.price_range];
+        NEW.avg_spend_max := (ARRAY[50000, 100000, 200000, NULL]::numeric[])[NEW.price_range];
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_sync_poi_price_range
+    BEFORE INSERT OR UPDATE OF price_range, price_currency, avg_spend_min, avg_spend_max ON points_of_interest
+    FOR EACH ROW EXECUTE FUNCTION sync_poi_price_range();
+
+CREATE INDEX IF NOT EXISTS idx_poi_avg_spend ON points_of_interest (price_currency, (COALESCE(avg_spend_max, avg_spend_min)));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS trg_sync_poi_price_range ON points_of_interest;
+DROP FUNCTION IF EXISTS sync_poi_price_range();
+DROP FUNCTION IF EXISTS price_range_for_idr(NUMERIC);
+DROP INDEX IF EXISTS idx_poi_avg_spend;
+ALTER TABLE points_of_interest
+    DROP CONSTRAINT IF EXISTS chk_poi_avg_spend,
+    DROP CONSTRAINT IF EXISTS chk_poi_price_currency,
+    DROP COLUMN IF EXISTS avg_spend_max,
+    DROP COLUMN IF EXISTS avg_spend_min,
+    DROP COLUMN IF EXISTS price_currency;
+-- +goose StatementEnd
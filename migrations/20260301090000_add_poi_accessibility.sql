@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Structured accessibility details; NULL means unknown. is_wheelchair_accessible
+-- stays the overall flag.
+ALTER TABLE points_of_interest
+    ADD COLUMN IF NOT EXISTS step_free_entrance BOOLEAN,
+    ADD COLUMN IF NOT EXISTS accessible_restroom BOOLEAN,
+    ADD COLUMN IF NOT EXISTS table_heights TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS braille_menu BOOLEAN,
+    ADD COLUMN IF NOT EXISTS accessible_parking BOOLEAN,
+    ADD COLUMN IF NOT EXISTS accessibility_notes TEXT;
+
+ALTER TABLE points_of_interest
+    ADD CONSTRAINT chk_poi_table_heights
+    CHECK (table_heights <@ ARRAY['low', 'standard', 'high', 'adjustable']::text[]);
+
+CREATE INDEX IF NOT EXISTS idx_poi_table_heights ON points_of_interest USING GIN (table_heights);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_poi_table_heights;
+ALTER TABLE points_of_interest
+    DROP CONSTRAINT IF EXISTS chk_poi_table_heights,
+    DROP COLUMN IF EXISTS accessibility_notes,
+    DROP COLUMN IF EXISTS accessible_parking,
+    DROP COLUMN IF EXISTS braille_menu,
+    DROP COLUMN IF EXISTS table_heights,
+    DROP COLUMN IF EXISTS accessible_restroom,
+    DROP COLUMN IF EXISTS step_free_entrance;
+-- +goose StatementEnd
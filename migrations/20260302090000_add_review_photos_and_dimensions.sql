@@ -0,0 +1,120 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Optional per-dimension ratings next to the overall rating
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS wifi_rating SMALLINT CHECK (wifi_rating BETWEEN 1 AND 5),
+    ADD COLUMN IF NOT EXISTS comfort_rating SMALLINT CHECK (comfort_rating BETWEEN 1 AND 5),
+    ADD COLUMN IF NOT EXISTS food_rating SMALLINT CHECK (food_rating BETWEEN 1 AND 5),
+    ADD COLUMN IF NOT EXISTS value_rating SMALLINT CHECK (value_rating BETWEEN 1 AND 5),
+    ADD COLUMN IF NOT EXISTS noise_accuracy_rating SMALLINT CHECK (noise_accuracy_rating BETWEEN 1 AND 5),
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ DEFAULT NOW();
+
+-- Photos attached to a review, in display order
+CREATE TABLE IF NOT EXISTS review_photos (
+    review_id UUID NOT NULL REFERENCES reviews(review_id) ON DELETE CASCADE,
+    asset_id UUID NOT NULL REFERENCES image_assets(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (review_id, asset_id)
+);
+
+-- Cached per-dimension averages; NULL until a review rates the dimension
+ALTER TABLE points_of_interest
+    ADD COLUMN IF NOT EXISTS wifi_rating_avg DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS comfort_rating_avg DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS food_rating_avg DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS value_rating_avg DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS noise_accuracy_rating_avg DOUBLE PRECISION;
+
+CREATE OR REPLACE FUNCTION refresh_poi_rating_stats_for(target UUID) RETURNS VOID AS $$
+    UPDATE points_of_interest p
+    SET rating_avg = COALESCE(s.rating_avg, 0),
+        reviews_count = s.reviews_count,
+        wifi_rating_avg = s.wifi,
+        comfort_rating_avg = s.comfort,
+        food_rating_avg = s.food,
+        value_rating_avg = s.value,
+        noise_accuracy_rating_avg = s.noise_accuracy
+    FROM (
+        SELECT AVG(rating)::float8 AS rating_avg, COUNT(*)::int AS reviews_count,
+               AVG(wifi_rating)::float8 AS wifi, AVG(comfort_rating)::float8 AS comfort,
+               AVG(food_rating)::float8 AS food, AVG(value_rating)::float8 AS value,
+               AVG(noise_accuracy_rating)::float8 AS noise_accuracy
+        FROM reviews
+        WHERE poi_id = target
+    ) s
+    WHERE p.poi_id = target
+$$ LANGUAGE sql;
+
+CREATE OR REPLACE FUNCTION refresh_poi_rating_stats() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM refresh_poi_rating_stats_for(OLD.poi_id);
+    END IF;
+
+    IF TG_OP = 'INSERT' OR (TG_OP = 'UPDATE' AND NEW.poi_id IS DISTINCT FROM OLD.poi_id) THEN
+        PERFORM refresh_poi_rating_stats_for(NEW.poi_id);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_refresh_poi_rating_stats ON reviews;
+CREATE TRIGGER trg_refresh_poi_rating_stats
+AFTER INSERT OR UPDATE OF rating, poi_id, wifi_rating, comfort_rating, food_rating, value_rating, noise_accuracy_rating OR DELETE
+ON reviews
+FOR EACH ROW
+EXECUTE FUNCTION refresh_poi_rating_stats();
+
+-- Supports min_wifi_rating
+CREATE INDEX IF NOT EXISTS idx_poi_wifi_rating ON points_of_interest(wifi_rating_avg) WHERE wifi_rating_avg IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_poi_wifi_rating;
+
+CREATE OR REPLACE FUNCTION refresh_poi_rating_stats() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE points_of_interest
+        SET rating_avg = COALESCE((SELECT AVG(rating)::float8 FROM reviews WHERE poi_id = OLD.poi_id), 0),
+            reviews_count = (SELECT COUNT(*)::int FROM reviews WHERE poi_id = OLD.poi_id)
+        WHERE poi_id = OLD.poi_id;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') AND (TG_OP = 'INSERT' OR NEW.poi_id IS DISTINCT FROM OLD.poi_id OR NEW.rating IS DISTINCT FROM OLD.rating) THEN
+        UPDATE points_of_interest
+        SET rating_avg = COALESCE((SELECT AVG(rating)::float8 FROM reviews WHERE poi_id = NEW.poi_id), 0),
+            reviews_count = (SELECT COUNT(*)::int FROM reviews WHERE poi_id = NEW.poi_id)
+        WHERE poi_id = NEW.poi_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_refresh_poi_rating_stats ON reviews;
+CREATE TRIGGER trg_refresh_poi_rating_stats
+AFTER INSERT OR UPDATE OF rating, poi_id OR DELETE
+ON reviews
+FOR EACH ROW
+EXECUTE FUNCTION refresh_poi_rating_stats();
+
+DROP FUNCTION IF EXISTS refresh_poi_rating_stats_for(UUID);
+ALTER TABLE points_of_interest
+    DROP COLUMN IF EXISTS noise_accuracy_rating_avg,
+    DROP COLUMN IF EXISTS value_rating_avg,
+    DROP COLUMN IF EXISTS food_rating_avg,
+    DROP COLUMN IF EXISTS comfort_rating_avg,
+    DROP COLUMN IF EXISTS wifi_rating_avg;
+DROP TABLE IF EXISTS review_photos;
+ALTER TABLE reviews
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS noise_accuracy_rating,
+    DROP COLUMN IF EXISTS value_rating,
+    DROP COLUMN IF EXISTS food_rating,
+    DROP COLUMN IF EXISTS comfort_rating,
+    DROP COLUMN IF EXISTS wifi_rating;
+-- +goose StatementEnd
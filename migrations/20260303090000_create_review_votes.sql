@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Helpful (1) / unhelpful (-1) votes on reviews, one per user and review
+CREATE TABLE review_votes (
+    vote_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    review_id UUID NOT NULL REFERENCES reviews(review_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    vote_type SMALLINT NOT NULL CHECK (vote_type IN (-1, 1)),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE (review_id, user_id)
+);
+
+CREATE INDEX idx_review_votes_user ON review_votes(user_id);
+
+-- upvotes/downvotes count helpful/unhelpful votes; helpful_score is their difference
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS helpful_score INTEGER NOT NULL DEFAULT 0;
+UPDATE reviews SET helpful_score = COALESCE(upvotes, 0) - COALESCE(downvotes, 0);
+
+-- Supports the default helpfulness ordering of a POI's reviews
+CREATE INDEX idx_reviews_poi_helpful ON reviews(poi_id, helpful_score DESC, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_reviews_poi_helpful;
+ALTER TABLE reviews DROP COLUMN IF EXISTS helpful_score;
+DROP TABLE IF EXISTS review_votes;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Shadow-banned users keep posting normally but their content is hidden from
+-- everyone else; spam-exempt users skip the submission heuristics.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS shadow_banned BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS spam_exempt BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_users_shadow_banned ON users(user_id) WHERE shadow_banned;
+
+-- Recent comment and review submissions, for velocity limits and duplicate detection
+CREATE TABLE ugc_submissions (
+    submission_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    target_id UUID NOT NULL, -- POI the comment or review was posted on
+    content_hash CHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_ugc_submissions_user ON ugc_submissions(user_id, created_at DESC);
+CREATE INDEX idx_ugc_submissions_hash ON ugc_submissions(user_id, content_hash);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS ugc_submissions;
+DROP INDEX IF EXISTS idx_users_shadow_banned;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS spam_exempt,
+    DROP COLUMN IF EXISTS shadow_banned;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Originals of user generated text the moderation pipeline changed or flagged.
+-- The sanitized text is what the target row stores; flagged rows wait here for
+-- manual review.
+CREATE TABLE text_moderation (
+    moderation_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    field VARCHAR(32) NOT NULL, -- poi_description, comment or review
+    target_id UUID NOT NULL,    -- POI, comment or review holding the text
+    user_id UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    original TEXT NOT NULL,
+    sanitized TEXT NOT NULL,
+    flags TEXT[] NOT NULL DEFAULT '{}',
+    needs_review BOOLEAN NOT NULL DEFAULT FALSE,
+    reviewed_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (field, target_id)
+);
+
+CREATE INDEX idx_text_moderation_pending ON text_moderation(created_at) WHERE needs_review AND reviewed_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS text_moderation;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Reprocess-all passes over ready image assets, e.g. after the rendition ladder
+-- changed. The cursor (created_at, id) is the last asset queued so an
+-- interrupted run resumes where it stopped; the lease keeps two runners (the
+-- CLI and the API) from driving the same run.
+CREATE TABLE image_reprocess_runs (
+    run_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    status VARCHAR(20) NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'completed', 'cancelled', 'failed')),
+    category VARCHAR(50),                 -- NULL reprocesses every category
+    batch_size INTEGER NOT NULL CHECK (batch_size > 0),
+    batch_interval_ms INTEGER NOT NULL DEFAULT 0 CHECK (batch_interval_ms >= 0),
+    total INTEGER NOT NULL DEFAULT 0,     -- Ready assets when the run started
+    queued INTEGER NOT NULL DEFAULT 0,
+    cursor_created_at TIMESTAMPTZ,
+    cursor_id UUID,
+    lease_owner UUID,                     -- Runner currently driving the run
+    lease_until TIMESTAMPTZ,
+    started_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+-- At most one run at a time
+CREATE UNIQUE INDEX idx_image_reprocess_runs_running ON image_reprocess_runs ((TRUE)) WHERE status = 'running';
+
+-- Cursor order for the ready assets of a run
+CREATE INDEX idx_image_assets_ready_cursor ON image_assets (created_at, id) WHERE status = 'ready';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_image_assets_ready_cursor;
+DROP TABLE IF EXISTS image_reprocess_runs;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Owner-defined gallery position. Photos without one (never ordered, or added
+-- after the last reorder) follow the ordered ones by pin/hero/score.
+ALTER TABLE photos ADD COLUMN sort_index INTEGER CHECK (sort_index >= 0);
+
+DROP INDEX IF EXISTS idx_photos_gallery_order;
+CREATE INDEX idx_photos_gallery_order
+    ON photos(poi_id, sort_index ASC NULLS LAST, is_pinned DESC, is_hero DESC, score DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_photos_gallery_order;
+CREATE INDEX idx_photos_gallery_order
+    ON photos(poi_id, is_pinned DESC, is_hero DESC, score DESC);
+
+ALTER TABLE photos DROP COLUMN IF EXISTS sort_index;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Image asset behind users.picture_url when the avatar was uploaded through
+-- the imaging pipeline (NULL for identity provider pictures)
+ALTER TABLE users ADD COLUMN avatar_asset_id UUID REFERENCES image_assets(id) ON DELETE SET NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_asset_id;
+-- +goose StatementEnd
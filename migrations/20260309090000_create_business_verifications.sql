@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Requests by POI owners for "verified business" status. Proof documents are
+-- private R2 objects under documents/verification/<poi_id>/<user_id>/.
+CREATE TABLE poi_business_verifications (
+    verification_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    poi_id UUID NOT NULL REFERENCES points_of_interest(poi_id) ON DELETE CASCADE,
+    requester_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    business_name VARCHAR(255),
+    note TEXT,
+    document_keys TEXT[] NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'withdrawn')),
+    reviewer_id UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    review_note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMPTZ
+);
+
+-- One open request per POI
+CREATE UNIQUE INDEX idx_poi_business_verifications_pending
+    ON poi_business_verifications(poi_id) WHERE status = 'pending';
+CREATE INDEX idx_poi_business_verifications_status
+    ON poi_business_verifications(status, created_at);
+CREATE INDEX idx_poi_business_verifications_poi
+    ON poi_business_verifications(poi_id, created_at DESC);
+
+INSERT INTO permissions (name, description) VALUES
+    ('poi:verify', 'Review business verification requests');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'poi:verify');
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'poi:verify';
+DROP TABLE IF EXISTS poi_business_verifications;
+-- +goose StatementEnd
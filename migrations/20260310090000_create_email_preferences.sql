@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Per-user email opt-outs. Users without a row receive every category.
+CREATE TABLE email_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    poi_status BOOLEAN NOT NULL DEFAULT TRUE,   -- Approval/rejection of submitted POIs
+    verification BOOLEAN NOT NULL DEFAULT TRUE, -- Business verification decisions
+    saved_digest BOOLEAN NOT NULL DEFAULT TRUE, -- Weekly digest of updated saved POIs
+    last_digest_at TIMESTAMPTZ,                 -- When the last digest was claimed for this user
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS email_preferences;
+-- +goose StatementEnd
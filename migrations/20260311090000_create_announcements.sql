@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Admin-managed banners shown in the apps during their time window
+CREATE TABLE announcements (
+    announcement_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    title VARCHAR(255) NOT NULL,
+    body TEXT,
+    link_url TEXT,
+    severity VARCHAR(16) NOT NULL DEFAULT 'info' CHECK (severity IN ('info', 'warning', 'critical')),
+    audience VARCHAR(16) NOT NULL DEFAULT 'all' CHECK (audience IN ('all', 'signed_in', 'signed_out')),
+    roles TEXT[] NOT NULL DEFAULT '{}', -- When not empty, only signed-in users with one of these roles
+    dismissible BOOLEAN NOT NULL DEFAULT TRUE,
+    starts_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ends_at TIMESTAMPTZ,
+    created_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX idx_announcements_window ON announcements(starts_at, ends_at);
+
+-- Announcements a signed-in user closed; they are not returned to them again
+CREATE TABLE announcement_dismissals (
+    announcement_id UUID NOT NULL REFERENCES announcements(announcement_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    dismissed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (announcement_id, user_id)
+);
+
+CREATE INDEX idx_announcement_dismissals_user ON announcement_dismissals(user_id);
+
+INSERT INTO permissions (name, description) VALUES
+    ('announcement:manage', 'Manage in-app announcements');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'announcement:manage');
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'announcement:manage';
+DROP TABLE IF EXISTS announcement_dismissals;
+DROP TABLE IF EXISTS announcements;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Sampled public searches for product analytics. Rows carry no user, session
+-- or IP; coordinates are rounded to about 1 km.
+CREATE TABLE search_events (
+    event_id BIGSERIAL PRIMARY KEY,
+    query TEXT,                           -- Normalized free text, NULL when absent or dropped
+    filters JSONB NOT NULL DEFAULT '{}',  -- Applied filters by query parameter
+    sort_by VARCHAR(32),
+    area VARCHAR(64),                     -- ?area= service area slug
+    lat DOUBLE PRECISION,
+    lng DOUBLE PRECISION,
+    result_count INT NOT NULL,
+    sample_rate REAL NOT NULL,            -- Share of searches recorded when this one was
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_search_events_created ON search_events(created_at);
+
+INSERT INTO permissions (name, description) VALUES
+    ('analytics:view', 'View product analytics');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'analytics:view');
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'analytics:view';
+DROP TABLE IF EXISTS search_events;
+-- +goose StatementEnd
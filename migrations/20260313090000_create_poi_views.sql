@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- POI detail views, at most one per viewer and POI per hour. viewer_hash is an
+-- HMAC of the user or session that rotates every hour, so views cannot be
+-- linked to a person or across hours.
+CREATE TABLE poi_views (
+    poi_id UUID NOT NULL REFERENCES points_of_interest(poi_id) ON DELETE CASCADE,
+    viewed_hour TIMESTAMPTZ NOT NULL,
+    viewer_hash VARCHAR(32) NOT NULL,
+    PRIMARY KEY (poi_id, viewed_hour, viewer_hash)
+);
+
+CREATE INDEX idx_poi_views_hour ON poi_views(viewed_hour);
+CREATE INDEX idx_saved_pois_created ON saved_pois(created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_saved_pois_created;
+DROP TABLE IF EXISTS poi_views;
+-- +goose StatementEnd
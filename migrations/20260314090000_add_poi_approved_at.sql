@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- When a POI was first approved, for the "New places" feed
+ALTER TABLE points_of_interest ADD COLUMN approved_at TIMESTAMPTZ;
+
+-- Approvals before this migration were not recorded; submission time is the
+-- closest lower bound and keeps old POIs out of the feed
+UPDATE points_of_interest
+SET approved_at = COALESCE(submitted_at, created_at)
+WHERE status IN ('approved', 'archived');
+
+CREATE INDEX idx_pois_approved_at ON points_of_interest(approved_at DESC, poi_id DESC)
+WHERE status = 'approved';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_pois_approved_at;
+ALTER TABLE points_of_interest DROP COLUMN IF EXISTS approved_at;
+-- +goose StatementEnd
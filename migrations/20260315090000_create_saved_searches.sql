@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Filter combinations users keep, optionally alerting them about new matches
+CREATE TABLE saved_searches (
+    search_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    query JSONB NOT NULL DEFAULT '{}',                 -- GET /api/v1/pois parameters by name
+    alerts_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    checked_until TIMESTAMPTZ NOT NULL DEFAULT NOW(),  -- Approvals up to here were evaluated for alerts
+    last_alerted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_saved_searches_user ON saved_searches(user_id, created_at DESC);
+CREATE INDEX idx_saved_searches_alerts ON saved_searches(checked_until) WHERE alerts_enabled;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS saved_searches;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Geohash cell of each POI (precision 9, ~5 m) for tile-based fetching.
+-- Prefixes are the coarser cells containing it.
+ALTER TABLE points_of_interest ADD COLUMN geohash VARCHAR(9);
+
+CREATE OR REPLACE FUNCTION set_poi_geohash() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.location IS NULL THEN
+        NEW.geohash := NULL;
+    ELSE
+        NEW.geohash := ST_GeoHash(NEW.location::geometry, 9);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_set_poi_geohash
+BEFORE INSERT OR UPDATE OF location
+ON points_of_interest
+FOR EACH ROW
+EXECUTE FUNCTION set_poi_geohash();
+
+UPDATE points_of_interest SET geohash = ST_GeoHash(location::geometry, 9) WHERE location IS NOT NULL;
+
+-- text_pattern_ops serves the prefix (LIKE 'cell%') lookups
+CREATE INDEX idx_pois_geohash ON points_of_interest(geohash text_pattern_ops) WHERE status = 'approved';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_pois_geohash;
+DROP TRIGGER IF EXISTS trg_set_poi_geohash ON points_of_interest;
+DROP FUNCTION IF EXISTS set_poi_geohash();
+ALTER TABLE points_of_interest DROP COLUMN IF EXISTS geohash;
+-- +goose StatementEnd
@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Approved POIs that were deleted or unpublished, so offline clients syncing
+-- deltas (GET /api/v1/sync/pois) can drop them. A POI approved again loses its
+-- tombstone. location is kept for area-scoped syncs.
+CREATE TABLE poi_tombstones (
+    poi_id UUID PRIMARY KEY,
+    location GEOGRAPHY(Point, 4326),
+    removed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_poi_tombstones_removed ON poi_tombstones(removed_at, poi_id);
+
+CREATE OR REPLACE FUNCTION record_poi_tombstone() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        IF OLD.status = 'approved' THEN
+            INSERT INTO poi_tombstones (poi_id, location) VALUES (OLD.poi_id, OLD.location)
+            ON CONFLICT (poi_id) DO UPDATE SET location = EXCLUDED.location, removed_at = NOW();
+        END IF;
+        RETURN OLD;
+    END IF;
+
+    IF NEW.status = 'approved' THEN
+        DELETE FROM poi_tombstones WHERE poi_id = NEW.poi_id;
+    ELSIF OLD.status = 'approved' THEN
+        INSERT INTO poi_tombstones (poi_id, location) VALUES (NEW.poi_id, NEW.location)
+        ON CONFLICT (poi_id) DO UPDATE SET location = EXCLUDED.location, removed_at = NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_record_poi_tombstone
+AFTER DELETE OR UPDATE OF status
+ON points_of_interest
+FOR EACH ROW
+EXECUTE FUNCTION record_poi_tombstone();
+
+CREATE INDEX idx_pois_sync ON points_of_interest(updated_at, poi_id) WHERE status = 'approved';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_pois_sync;
+DROP TRIGGER IF EXISTS trg_record_poi_tombstone ON points_of_interest;
+DROP FUNCTION IF EXISTS record_poi_tombstone();
+DROP TABLE IF EXISTS poi_tombstones;
+-- +goose StatementEnd
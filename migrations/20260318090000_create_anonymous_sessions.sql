@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Signed-out browsing sessions identified by the X-Session-ID header. They
+-- remember recent views and searches and keep a temporary saved list that is
+-- merged into the account on sign-in. Idle sessions expire after 30 days.
+CREATE TABLE anonymous_sessions (
+    session_id VARCHAR(64) PRIMARY KEY,
+    recent_poi_ids UUID[] NOT NULL DEFAULT '{}',  -- Most recent first
+    recent_searches JSONB NOT NULL DEFAULT '[]',  -- Filter sets, most recent first
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_anonymous_sessions_last_seen ON anonymous_sessions(last_seen_at);
+
+CREATE TABLE anonymous_saved_pois (
+    session_id VARCHAR(64) NOT NULL REFERENCES anonymous_sessions(session_id) ON DELETE CASCADE,
+    poi_id UUID NOT NULL REFERENCES points_of_interest(poi_id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (session_id, poi_id)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS anonymous_saved_pois;
+DROP TABLE IF EXISTS anonymous_sessions;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO permissions (name, description) VALUES
+    ('system:debug', 'Inspect runtime internals such as the database pool');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'system:debug');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'system:debug';
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Last change per POI section, written by the section updates, draft autosave
+-- and full updates, so the edit UI can show who last touched a section and when.
+CREATE TABLE poi_section_edits (
+    poi_id UUID NOT NULL REFERENCES points_of_interest(poi_id) ON DELETE CASCADE,
+    section VARCHAR(32) NOT NULL,
+    edited_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (poi_id, section)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS poi_section_edits;
+-- +goose StatementEnd
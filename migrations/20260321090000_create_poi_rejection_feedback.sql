@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Moderator comments on the sections and fields of a rejected POI. A comment
+-- is removed when its section has been edited since and the POI is submitted
+-- again, and all remaining comments are removed on approval.
+CREATE TABLE poi_rejection_feedback (
+    feedback_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    poi_id UUID NOT NULL REFERENCES points_of_interest(poi_id) ON DELETE CASCADE,
+    section VARCHAR(32) NOT NULL,
+    field VARCHAR(64),
+    comment TEXT NOT NULL,
+    created_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_poi_rejection_feedback_poi ON poi_rejection_feedback(poi_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS poi_rejection_feedback;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- One row per moderation decision on a submission, recording how long the
+-- POI waited in the queue. Earlier decisions are not backfilled: approved_at
+-- was itself backfilled from submitted_at, so it carries no review time.
+CREATE TABLE poi_review_decisions (
+    decision_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    poi_id UUID NOT NULL REFERENCES points_of_interest(poi_id) ON DELETE CASCADE,
+    decision VARCHAR(16) NOT NULL,
+    submitted_at TIMESTAMPTZ NOT NULL,
+    decided_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_poi_review_decisions_decided_at ON poi_review_decisions(decided_at);
+
+-- Queue age and overdue lookups only touch pending POIs
+CREATE INDEX idx_pois_pending_submitted_at ON points_of_interest(submitted_at) WHERE status = 'pending';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_pois_pending_submitted_at;
+DROP TABLE IF EXISTS poi_review_decisions;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Reviewer working on a pending POI. The assignment lapses at
+-- assignment_expires_at, after which any reviewer may take the POI.
+ALTER TABLE points_of_interest
+    ADD COLUMN assigned_to UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    ADD COLUMN assignment_expires_at TIMESTAMPTZ;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE points_of_interest
+    DROP COLUMN IF EXISTS assignment_expires_at,
+    DROP COLUMN IF EXISTS assigned_to;
+-- +goose StatementEnd
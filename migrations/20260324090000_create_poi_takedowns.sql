@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Legal and safety takedowns: a terminal POI status, the permission to use it
+-- and an append-only record of each takedown with a snapshot of the POI
+ALTER TABLE points_of_interest DROP CONSTRAINT IF EXISTS points_of_interest_status_check;
+ALTER TABLE points_of_interest
+ADD CONSTRAINT points_of_interest_status_check
+CHECK (status IN ('draft', 'pending', 'approved', 'rejected', 'archived', 'taken_down'));
+
+INSERT INTO permissions (name, description) VALUES
+    ('poi:takedown', 'Unpublish POIs for legal or safety reasons');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'poi:takedown');
+
+-- No foreign key to points_of_interest: the record outlives the POI
+CREATE TABLE poi_takedowns (
+    takedown_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    poi_id UUID NOT NULL,
+    reason_code VARCHAR(32) NOT NULL,
+    note TEXT,
+    previous_status VARCHAR(20) NOT NULL,
+    snapshot JSONB NOT NULL,
+    taken_down_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_poi_takedowns_poi ON poi_takedowns(poi_id, created_at DESC);
+
+CREATE FUNCTION prevent_takedown_changes() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'poi_takedowns records are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+-- ON DELETE SET NULL of taken_down_by is an UPDATE too, so only the record
+-- columns are protected
+CREATE TRIGGER poi_takedowns_immutable
+BEFORE UPDATE OF takedown_id, poi_id, reason_code, note, previous_status, snapshot, created_at OR DELETE
+ON poi_takedowns
+FOR EACH ROW EXECUTE FUNCTION prevent_takedown_changes();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS poi_takedowns;
+DROP FUNCTION IF EXISTS prevent_takedown_changes();
+DELETE FROM permissions WHERE name = 'poi:takedown';
+UPDATE points_of_interest SET status = 'archived' WHERE status = 'taken_down';
+ALTER TABLE points_of_interest DROP CONSTRAINT IF EXISTS points_of_interest_status_check;
+ALTER TABLE points_of_interest
+ADD CONSTRAINT points_of_interest_status_check
+CHECK (status IN ('draft', 'pending', 'approved', 'rejected', 'archived'));
+-- +goose StatementEnd
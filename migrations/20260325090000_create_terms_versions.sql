@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Published versions of the terms of service and privacy policy. The current
+-- version is the newest one whose published_at has passed; users must accept
+-- it before changing POIs or posting comments.
+CREATE TABLE terms_versions (
+    version INT PRIMARY KEY,
+    terms_url TEXT NOT NULL,
+    privacy_url TEXT NOT NULL,
+    summary TEXT,
+    published_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE user_terms_acceptances (
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    version INT NOT NULL REFERENCES terms_versions(version),
+    accepted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ip_address INET,
+    user_agent TEXT,
+    PRIMARY KEY (user_id, version)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_terms_acceptances;
+DROP TABLE IF EXISTS terms_versions;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Extra sign-in identities of a user. When two accounts are linked the Clerk
+-- ID of the retired account is kept here so signing in with it reaches the
+-- surviving account.
+CREATE TABLE user_identities (
+    clerk_id VARCHAR(255) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    linked_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_identities_user ON user_identities(user_id);
+
+-- Audit trail of account merges and what moved to the surviving account
+CREATE TABLE user_account_merges (
+    merge_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_user_id UUID NOT NULL REFERENCES users(user_id),
+    target_user_id UUID NOT NULL REFERENCES users(user_id),
+    pois_moved INT NOT NULL DEFAULT 0,
+    reviews_moved INT NOT NULL DEFAULT 0,
+    saved_pois_moved INT NOT NULL DEFAULT 0,
+    photos_moved INT NOT NULL DEFAULT 0,
+    merged_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_account_merges_target ON user_account_merges(target_user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_account_merges;
+DROP TABLE IF EXISTS user_identities;
+-- +goose StatementEnd
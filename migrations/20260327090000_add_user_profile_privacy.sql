@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- What GET /users/:id/public-profile exposes. A private profile is only
+-- visible to its owner; the other switches hide parts of a public one.
+ALTER TABLE user_profiles
+    ADD COLUMN IF NOT EXISTS profile_public BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS show_stats BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS show_contributions BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_profiles
+    DROP COLUMN IF EXISTS show_contributions,
+    DROP COLUMN IF EXISTS show_stats,
+    DROP COLUMN IF EXISTS profile_public;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Usernames are unique regardless of case and can change once every 30 days
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS username_changed_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_profiles_username_lower ON user_profiles (LOWER(username));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_user_profiles_username_lower;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS username_changed_at;
+-- +goose StatementEnd
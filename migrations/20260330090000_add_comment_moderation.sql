@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Comments hidden by a moderator stay in the table but are left out of every
+-- listing
+ALTER TABLE comments
+    ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS hidden_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS hidden_reason TEXT;
+
+INSERT INTO permissions (name, description) VALUES
+    ('comment:moderate', 'Hide and delete comments of any user');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'comment:moderate'),
+    ('moderator', 'comment:moderate');
+
+-- Append-only record of moderation actions. No foreign key on the target: the
+-- entry outlives what it describes.
+CREATE TABLE audit_log (
+    audit_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    action VARCHAR(64) NOT NULL,
+    target_type VARCHAR(32) NOT NULL,
+    target_id UUID NOT NULL,
+    reason TEXT,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_target ON audit_log(target_type, target_id, created_at DESC);
+CREATE INDEX idx_audit_log_created ON audit_log(created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_log;
+DELETE FROM permissions WHERE name = 'comment:moderate';
+ALTER TABLE comments
+    DROP COLUMN IF EXISTS hidden_reason,
+    DROP COLUMN IF EXISTS hidden_by,
+    DROP COLUMN IF EXISTS hidden_at;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Questions about a POI answered by its owner or the community. Unlike
+-- comments, an answer can be accepted by the asker or the POI owner.
+CREATE TABLE poi_questions (
+    question_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    poi_id UUID NOT NULL REFERENCES points_of_interest(poi_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(user_id),
+    body TEXT NOT NULL,
+    accepted_answer_id UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_poi_questions_poi ON poi_questions(poi_id, created_at DESC);
+
+CREATE TABLE poi_answers (
+    answer_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    question_id UUID NOT NULL REFERENCES poi_questions(question_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(user_id),
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_poi_answers_question ON poi_answers(question_id, created_at);
+
+ALTER TABLE poi_questions
+    ADD CONSTRAINT poi_questions_accepted_answer_fkey
+    FOREIGN KEY (accepted_answer_id) REFERENCES poi_answers(answer_id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE IF EXISTS poi_questions DROP CONSTRAINT IF EXISTS poi_questions_accepted_answer_fkey;
+DROP TABLE IF EXISTS poi_answers;
+DROP TABLE IF EXISTS poi_questions;
+-- +goose StatementEnd
@@ -0,0 +1,135 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Amenities are the 'amenity' vocabulary entries a POI can be tagged with,
+-- replacing the free-form points_of_interest.amenities array. Amenities with a
+-- legacy value mirror an entry of parking_options or seating_options and are
+-- kept in sync with that column, so each concept exists once.
+INSERT INTO vocabularies (vocab_type, key, aliases, icon) VALUES
+    ('amenity', 'amenity.motorcycle_parking', ARRAY['Motorcycle', 'Motor Parking'], '🏍️'),
+    ('amenity', 'amenity.valet_parking', ARRAY['Valet', 'Valet Service'], '🚗'),
+    ('amenity', 'amenity.ergonomic_seating', ARRAY['Ergonomic'], '🪑'),
+    ('amenity', 'amenity.communal_tables', ARRAY['Communal'], '🍽️'),
+    ('amenity', 'amenity.high_tops', ARRAY['High-tops', 'Bar Seating'], '🪑'),
+    ('amenity', 'amenity.private_booths', ARRAY['Private Booths', 'Booth'], '🚪');
+
+CREATE TABLE amenities (
+    amenity_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    vocab_id UUID NOT NULL UNIQUE REFERENCES vocabularies(vocab_id) ON DELETE CASCADE,
+    key VARCHAR(100) NOT NULL UNIQUE, -- vocabulary key without the 'amenity.' prefix
+    legacy_field VARCHAR(32) CHECK (legacy_field IN ('parking_options', 'seating_options')),
+    legacy_value VARCHAR(50),
+    sort_order INT NOT NULL DEFAULT 0,
+    CHECK ((legacy_field IS NULL) = (legacy_value IS NULL)),
+    UNIQUE (legacy_field, legacy_value)
+);
+
+INSERT INTO amenities (vocab_id, key)
+SELECT vocab_id, substring(key FROM length('amenity.') + 1)
+FROM vocabularies
+WHERE vocab_type = 'amenity' AND key LIKE 'amenity.%';
+
+UPDATE amenities SET legacy_field = m.field, legacy_value = m.value
+FROM (VALUES
+    ('parking', 'parking_options', 'car'),
+    ('motorcycle_parking', 'parking_options', 'motorcycle'),
+    ('valet_parking', 'parking_options', 'valet'),
+    ('outdoor_seating', 'seating_options', 'outdoor'),
+    ('ergonomic_seating', 'seating_options', 'ergonomic'),
+    ('communal_tables', 'seating_options', 'communal'),
+    ('high_tops', 'seating_options', 'high-tops'),
+    ('private_booths', 'seating_options', 'private-booths')
+) AS m(key, field, value)
+WHERE amenities.key = m.key;
+
+-- Amenity vocabulary entries added later become amenities too
+CREATE FUNCTION add_vocabulary_amenity() RETURNS trigger AS $$
+BEGIN
+    IF NEW.vocab_type = 'amenity' AND NEW.key LIKE 'amenity.%' THEN
+        INSERT INTO amenities (vocab_id, key)
+        VALUES (NEW.vocab_id, substring(NEW.key FROM length('amenity.') + 1))
+        ON CONFLICT DO NOTHING;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER vocabulary_amenity_add
+AFTER INSERT ON vocabularies
+FOR EACH ROW EXECUTE FUNCTION add_vocabulary_amenity();
+
+CREATE TABLE poi_amenities (
+    poi_id UUID NOT NULL REFERENCES points_of_interest(poi_id) ON DELETE CASCADE,
+    amenity_id UUID NOT NULL REFERENCES amenities(amenity_id) ON DELETE CASCADE,
+    PRIMARY KEY (poi_id, amenity_id)
+);
+
+CREATE INDEX idx_poi_amenities_amenity ON poi_amenities(amenity_id);
+
+-- Existing free-form values match an amenity by key, vocabulary key or alias
+INSERT INTO poi_amenities (poi_id, amenity_id)
+SELECT DISTINCT p.poi_id, a.amenity_id
+FROM points_of_interest p
+CROSS JOIN LATERAL unnest(p.amenities) AS value
+JOIN amenities a ON a.legacy_field IS NULL
+JOIN vocabularies v ON v.vocab_id = a.vocab_id
+WHERE lower(value) = a.key
+   OR lower(value) = v.key
+   OR lower(value) IN (SELECT lower(alias) FROM unnest(v.aliases) AS alias)
+ON CONFLICT DO NOTHING;
+
+CREATE FUNCTION sync_poi_legacy_amenities() RETURNS trigger AS $$
+BEGIN
+    DELETE FROM poi_amenities pa
+    USING amenities a
+    WHERE pa.amenity_id = a.amenity_id AND pa.poi_id = NEW.poi_id AND a.legacy_field IS NOT NULL;
+
+    INSERT INTO poi_amenities (poi_id, amenity_id)
+    SELECT NEW.poi_id, a.amenity_id
+    FROM amenities a
+    WHERE (a.legacy_field = 'parking_options' AND a.legacy_value = ANY(COALESCE(NEW.parking_options, '{}')))
+       OR (a.legacy_field = 'seating_options' AND a.legacy_value = ANY(COALESCE(NEW.seating_options, '{}')))
+    ON CONFLICT DO NOTHING;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER poi_legacy_amenities_sync
+AFTER INSERT OR UPDATE OF parking_options, seating_options ON points_of_interest
+FOR EACH ROW EXECUTE FUNCTION sync_poi_legacy_amenities();
+
+-- Backfill the mirrored amenities of existing POIs
+INSERT INTO poi_amenities (poi_id, amenity_id)
+SELECT p.poi_id, a.amenity_id
+FROM points_of_interest p
+JOIN amenities a
+  ON (a.legacy_field = 'parking_options' AND a.legacy_value = ANY(COALESCE(p.parking_options, '{}')))
+  OR (a.legacy_field = 'seating_options' AND a.legacy_value = ANY(COALESCE(p.seating_options, '{}')))
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS poi_legacy_amenities_sync ON points_of_interest;
+DROP FUNCTION IF EXISTS sync_poi_legacy_amenities();
+DROP TRIGGER IF EXISTS vocabulary_amenity_add ON vocabularies;
+DROP FUNCTION IF EXISTS add_vocabulary_amenity();
+
+-- Restore the free-form column from the tagged amenities
+UPDATE points_of_interest p
+SET amenities = sub.keys
+FROM (
+    SELECT pa.poi_id, array_agg(a.key ORDER BY a.key) AS keys
+    FROM poi_amenities pa
+    JOIN amenities a ON a.amenity_id = pa.amenity_id
+    WHERE a.legacy_field IS NULL
+    GROUP BY pa.poi_id
+) sub
+WHERE sub.poi_id = p.poi_id;
+
+DROP TABLE IF EXISTS poi_amenities;
+DROP TABLE IF EXISTS amenities;
+DELETE FROM vocabularies WHERE vocab_type = 'amenity' AND key IN (
+    'amenity.motorcycle_parking', 'amenity.valet_parking', 'amenity.ergonomic_seating',
+    'amenity.communal_tables', 'amenity.high_tops', 'amenity.private_booths');
+-- +goose StatementEnd
@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Filter options for vibes, crowd types and cuisines come from the
+-- vocabularies table. The key suffix after the type prefix is the value stored
+-- on points_of_interest (vibes, crowd_type, lower(cuisine)); dietary options use
+-- the existing 'food' vocabulary.
+INSERT INTO vocabularies (vocab_type, key, aliases, icon) VALUES
+    ('vibe', 'vibe.industrial', ARRAY['Industrial'], '🏭'),
+    ('vibe', 'vibe.cozy', ARRAY['Cozy', 'Cosy'], '🛋️'),
+    ('vibe', 'vibe.tropical', ARRAY['Tropical'], '🌴'),
+    ('vibe', 'vibe.minimalist', ARRAY['Minimalist', 'Minimal'], '⬜'),
+    ('vibe', 'vibe.luxury', ARRAY['Luxury', 'Upscale'], '💎'),
+    ('vibe', 'vibe.retro', ARRAY['Retro', 'Vintage'], '📻'),
+    ('vibe', 'vibe.nature', ARRAY['Nature', 'Garden'], '🌳'),
+    ('crowd_type', 'crowd_type.quiet_study', ARRAY['Quiet', 'Study'], '📚'),
+    ('crowd_type', 'crowd_type.social_lively', ARRAY['Social', 'Lively'], '🎉'),
+    ('crowd_type', 'crowd_type.business', ARRAY['Business', 'Meetings'], '💼'),
+    ('cuisine', 'cuisine.italian', ARRAY['Italian'], '🍝'),
+    ('cuisine', 'cuisine.japanese', ARRAY['Japanese'], '🍣'),
+    ('cuisine', 'cuisine.mexican', ARRAY['Mexican'], '🌮'),
+    ('cuisine', 'cuisine.fusion', ARRAY['Fusion'], '🍱'),
+    ('cuisine', 'cuisine.cafe', ARRAY['Cafe', 'Coffee'], '☕'),
+    ('food', 'food.nut_free', ARRAY['Nut Free'], '🥜');
+
+-- English labels for the entries above; other locales fall back to the key
+INSERT INTO display_labels (entity_type, entity_id, locale, label)
+SELECT 'vocabulary', v.vocab_id, 'en', l.label
+FROM (VALUES
+    ('vibe.industrial', 'Industrial'),
+    ('vibe.cozy', 'Cozy'),
+    ('vibe.tropical', 'Tropical'),
+    ('vibe.minimalist', 'Minimalist'),
+    ('vibe.luxury', 'Luxury'),
+    ('vibe.retro', 'Retro'),
+    ('vibe.nature', 'Nature'),
+    ('crowd_type.quiet_study', 'Quiet / Study'),
+    ('crowd_type.social_lively', 'Social / Lively'),
+    ('crowd_type.business', 'Business'),
+    ('cuisine.italian', 'Italian'),
+    ('cuisine.japanese', 'Japanese'),
+    ('cuisine.mexican', 'Mexican'),
+    ('cuisine.fusion', 'Fusion'),
+    ('cuisine.cafe', 'Cafe'),
+    ('food.nut_free', 'Nut-Free')
+) AS l(key, label)
+JOIN vocabularies v ON v.key = l.key
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM vocabularies WHERE vocab_type IN ('vibe', 'crowd_type', 'cuisine') OR key = 'food.nut_free';
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Large originals are uploaded to storage in parts. The row keeps what a
+-- client needs to resume an interrupted upload; the parts themselves are
+-- listed from the bucket.
+CREATE TABLE multipart_uploads (
+    upload_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    storage_key TEXT NOT NULL,
+    storage_upload_id TEXT NOT NULL,
+    category VARCHAR(20) NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes > 0),
+    part_size_bytes BIGINT NOT NULL CHECK (part_size_bytes > 0),
+    part_count INT NOT NULL CHECK (part_count > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'completed', 'aborted')),
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_multipart_uploads_user ON multipart_uploads(user_id, created_at DESC);
+CREATE INDEX idx_multipart_uploads_active ON multipart_uploads(expires_at) WHERE status = 'active';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS multipart_uploads;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Every presigned upload (single PUT or multipart) gets a row so progress can
+-- be reported, finalize can check the stored object against the declared
+-- size, and uploads never finalized can be cleaned up and counted.
+CREATE TABLE uploads (
+    upload_id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    storage_key TEXT NOT NULL UNIQUE,
+    category VARCHAR(20) NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    expected_size_bytes BIGINT NOT NULL CHECK (expected_size_bytes > 0),
+    bytes_uploaded BIGINT NOT NULL DEFAULT 0 CHECK (bytes_uploaded >= 0),
+    multipart BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'uploading', 'finalized', 'expired')),
+    last_heartbeat_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL,
+    finalized_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_uploads_user ON uploads(user_id, created_at DESC);
+CREATE INDEX idx_uploads_open ON uploads(expires_at) WHERE status IN ('pending', 'uploading');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS uploads;
+-- +goose StatementEnd
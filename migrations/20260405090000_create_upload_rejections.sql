@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Uploads rejected at finalize because their content is not a plain image
+-- (SVG, PDF, markup or scripts, alone or embedded in an image). The object is
+-- deleted; the row is kept for abuse monitoring.
+CREATE TABLE upload_rejections (
+    rejection_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    upload_id UUID REFERENCES uploads(upload_id) ON DELETE SET NULL,
+    user_id UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    storage_key TEXT NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    detail TEXT NOT NULL,
+    declared_content_type VARCHAR(100),
+    ip_address INET,
+    user_agent TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_upload_rejections_created ON upload_rejections(created_at DESC);
+CREATE INDEX idx_upload_rejections_user ON upload_rejections(user_id, created_at DESC);
+
+ALTER TABLE uploads DROP CONSTRAINT IF EXISTS uploads_status_check;
+ALTER TABLE uploads ADD CONSTRAINT uploads_status_check
+    CHECK (status IN ('pending', 'uploading', 'finalized', 'expired', 'rejected'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE uploads SET status = 'expired' WHERE status = 'rejected';
+ALTER TABLE uploads DROP CONSTRAINT IF EXISTS uploads_status_check;
+ALTER TABLE uploads ADD CONSTRAINT uploads_status_check
+    CHECK (status IN ('pending', 'uploading', 'finalized', 'expired'));
+DROP TABLE IF EXISTS upload_rejections;
+-- +goose StatementEnd
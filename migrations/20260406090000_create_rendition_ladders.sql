@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Rendition ladder overrides per category. Categories without a row use the
+-- ladder compiled into the server at version 1; every change takes the next
+-- version. A NULL ladder is a reset to the compiled-in one.
+CREATE TABLE rendition_ladders (
+    category VARCHAR(50) PRIMARY KEY,
+    version INTEGER NOT NULL CHECK (version > 1),
+    renditions JSONB,
+    updated_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Ladder version each asset's derivatives were rendered with, so reprocess
+-- runs can be limited to outdated assets
+ALTER TABLE image_assets ADD COLUMN ladder_version INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE image_reprocess_runs ADD COLUMN outdated_only BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE image_reprocess_runs DROP COLUMN IF EXISTS outdated_only;
+ALTER TABLE image_assets DROP COLUMN IF EXISTS ladder_version;
+DROP TABLE IF EXISTS rendition_ladders;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+// Package migrations embeds the goose SQL migrations so binaries can apply
+// them without the migrations directory on disk.
+package migrations
+
+import "embed"
+
+// FS holds every migration file at its root
+//
+//go:embed *.sql
+var FS embed.FS